@@ -0,0 +1,286 @@
+package autopilot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// activationBucketKey is the top level bucket that stores the state
+	// of the activation requirements of the autopilot agent.
+	activationBucketKey = []byte("autopilot-activation")
+
+	// activationFirstStartKey is the key that stores the unix nano
+	// timestamp of the first start of the agent the activation delay is
+	// measured from.
+	activationFirstStartKey = []byte("first-start")
+
+	// activationFirstStartHeightKey is the key that stores the block
+	// height at the first start of the agent the activation block delay is
+	// measured from.
+	activationFirstStartHeightKey = []byte("first-start-height")
+
+	// activationManualChanKey is the key that is set once a channel that
+	// wasn't opened by the agent was seen.
+	activationManualChanKey = []byte("manual-channel-seen")
+)
+
+// blockDelayCheckInterval is the interval at which the agent checks whether
+// the activation block delay passed.
+const blockDelayCheckInterval = time.Minute
+
+// ActivationState is the persisted state of the activation requirements of
+// the autopilot agent.
+type ActivationState struct {
+	// FirstStart is the time of the first start of the agent. It's zero if
+	// the agent didn't run yet.
+	FirstStart time.Time
+
+	// FirstStartHeight is the block height at the first start of the
+	// agent. It's zero if it wasn't recorded yet, e.g. because the state
+	// was stored by a version without block delays.
+	FirstStartHeight uint32
+
+	// ManualChanSeen is true once a channel that wasn't opened by the
+	// agent was seen.
+	ManualChanSeen bool
+}
+
+// ActivationStore persists the state of the activation requirements of the
+// autopilot agent, so a restart doesn't reset them.
+type ActivationStore interface {
+	// FetchActivationState returns the stored activation state. An empty
+	// state is returned if nothing was stored yet.
+	FetchActivationState() (*ActivationState, error)
+
+	// PutActivationState stores the given activation state.
+	PutActivationState(state *ActivationState) error
+}
+
+// activationStore is an ActivationStore backed by a kvdb.Backend.
+type activationStore struct {
+	db kvdb.Backend
+}
+
+// NewActivationStore returns a new ActivationStore backed by the given
+// database.
+func NewActivationStore(db kvdb.Backend) (ActivationStore, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(activationBucketKey)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &activationStore{
+		db: db,
+	}, nil
+}
+
+// FetchActivationState returns the stored activation state.
+//
+// NOTE: This is part of the ActivationStore interface.
+func (s *activationStore) FetchActivationState() (*ActivationState, error) {
+	var state *ActivationState
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(activationBucketKey)
+		if bucket == nil {
+			return errors.New("activation bucket does not exist")
+		}
+
+		startBytes := bucket.Get(activationFirstStartKey)
+		if startBytes == nil {
+			return nil
+		}
+
+		state.FirstStart = time.Unix(
+			0, int64(byteOrder.Uint64(startBytes)),
+		)
+		manualChan := bucket.Get(activationManualChanKey)
+		state.ManualChanSeen = manualChan != nil
+
+		heightBytes := bucket.Get(activationFirstStartHeightKey)
+		if heightBytes != nil {
+			state.FirstStartHeight = byteOrder.Uint32(heightBytes)
+		}
+
+		return nil
+	}, func() {
+		state = &ActivationState{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// PutActivationState stores the given activation state.
+//
+// NOTE: This is part of the ActivationStore interface.
+func (s *activationStore) PutActivationState(state *ActivationState) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(activationBucketKey)
+		if bucket == nil {
+			return errors.New("activation bucket does not exist")
+		}
+
+		var startBytes [8]byte
+		startNano := uint64(state.FirstStart.UnixNano())
+		byteOrder.PutUint64(startBytes[:], startNano)
+
+		err := bucket.Put(activationFirstStartKey, startBytes[:])
+		if err != nil {
+			return err
+		}
+
+		var heightBytes [4]byte
+		byteOrder.PutUint32(heightBytes[:], state.FirstStartHeight)
+
+		err = bucket.Put(activationFirstStartHeightKey, heightBytes[:])
+		if err != nil {
+			return err
+		}
+
+		if !state.ManualChanSeen {
+			return bucket.Delete(activationManualChanKey)
+		}
+
+		return bucket.Put(activationManualChanKey, []byte{1})
+	}, func() {})
+}
+
+// ActivationConfig houses the parameters of an Activation.
+type ActivationConfig struct {
+	// Delay is the time that must pass after the first start of the agent
+	// before it opens any channels.
+	Delay time.Duration
+
+	// DelayBlocks is the number of blocks that must be mined after the
+	// first start of the agent before it opens any channels.
+	DelayBlocks uint32
+
+	// BestHeight returns the current block height. It must be set if
+	// DelayBlocks is.
+	BestHeight func() (uint32, error)
+
+	// RequireManualFirstChannel, if set, prevents the agent from opening
+	// any channels until a channel was opened without its help.
+	RequireManualFirstChannel bool
+
+	// Store persists the activation state across restarts.
+	Store ActivationStore
+
+	// Clock is the time source used to determine whether the delay
+	// passed.
+	Clock clock.Clock
+}
+
+// Activation tracks the requirements that must be met before the autopilot
+// agent opens its first channel.
+type Activation struct {
+	cfg ActivationConfig
+
+	// state is the persisted activation state.
+	state ActivationState
+
+	mtx sync.Mutex
+}
+
+// NewActivation creates a new Activation, restoring its state from the store.
+func NewActivation(cfg ActivationConfig) (*Activation, error) {
+	if cfg.Delay < 0 {
+		return nil, fmt.Errorf("invalid activation delay %v", cfg.Delay)
+	}
+
+	if cfg.DelayBlocks > 0 && cfg.BestHeight == nil {
+		return nil, errors.New("activation block delay requires the " +
+			"best height")
+	}
+
+	state, err := cfg.Store.FetchActivationState()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch activation state: %w",
+			err)
+	}
+
+	return &Activation{
+		cfg:   cfg,
+		state: *state,
+	}, nil
+}
+
+// Ready returns true if the agent may open channels given the number of
+// channels currently open. Until the agent is ready, it doesn't open any
+// channels itself, so any open channel counts as a manually opened one. If
+// the agent is waiting for the activation delay to pass, the time after which
+// to check again is returned as well. As blocks aren't mined at a fixed rate,
+// the block delay is checked again every blockDelayCheckInterval.
+func (a *Activation) Ready(numChans int) (bool, time.Duration, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	var height uint32
+	if a.cfg.DelayBlocks > 0 {
+		var err error
+		height, err = a.cfg.BestHeight()
+		if err != nil {
+			return false, 0, fmt.Errorf("unable to fetch best "+
+				"height: %w", err)
+		}
+	}
+
+	// If this is the first time the agent runs, the delays start now. A
+	// block delay that was configured after the first start is measured
+	// from the first check that knows about it.
+	state := a.state
+	if state.FirstStart.IsZero() {
+		state.FirstStart = a.cfg.Clock.Now()
+	}
+	if state.FirstStartHeight == 0 {
+		state.FirstStartHeight = height
+	}
+
+	if a.cfg.RequireManualFirstChannel && !state.ManualChanSeen &&
+		numChans != 0 {
+
+		// Once seen, the requirement stays satisfied, even if the
+		// channel is closed later on.
+		state.ManualChanSeen = true
+
+		log.Infof("Manually opened channel found, autopilot " +
+			"activation requirement satisfied")
+	}
+
+	if state != a.state {
+		if err := a.cfg.Store.PutActivationState(&state); err != nil {
+			return false, 0, err
+		}
+		a.state = state
+	}
+
+	if a.cfg.RequireManualFirstChannel && !state.ManualChanSeen {
+		return false, 0, nil
+	}
+
+	activateAt := state.FirstStart.Add(a.cfg.Delay)
+	if waitFor := activateAt.Sub(a.cfg.Clock.Now()); waitFor > 0 {
+		return false, waitFor, nil
+	}
+
+	if height < state.FirstStartHeight+a.cfg.DelayBlocks {
+		log.Debugf("Autopilot activation block delay not passed yet, "+
+			"%v blocks left", state.FirstStartHeight+
+			a.cfg.DelayBlocks-height)
+
+		return false, blockDelayCheckInterval, nil
+	}
+
+	return true, 0, nil
+}
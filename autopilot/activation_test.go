@@ -0,0 +1,136 @@
+package autopilot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestActivation tests that the agent is only activated once the activation
+// delay passed and a manually opened channel was seen, and that this state
+// survives a restart.
+func TestActivation(t *testing.T) {
+	t.Parallel()
+
+	const delay = time.Hour
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewActivationStore(cdb)
+	require.NoError(t, err)
+
+	startTime := time.Unix(1_700_000_000, 0)
+	testClock := clock.NewTestClock(startTime)
+
+	cfg := ActivationConfig{
+		Delay:                     delay,
+		RequireManualFirstChannel: true,
+		Store:                     store,
+		Clock:                     testClock,
+	}
+	activation, err := NewActivation(cfg)
+	require.NoError(t, err)
+
+	assertReady := func(a *Activation, numChans int, expected bool,
+		expectedWaitFor time.Duration) {
+
+		t.Helper()
+
+		ready, waitFor, err := a.Ready(numChans)
+		require.NoError(t, err)
+		require.Equal(t, expected, ready)
+		require.Equal(t, expectedWaitFor, waitFor)
+	}
+
+	// Without any channels, the agent waits for a manual one, regardless
+	// of the delay.
+	assertReady(activation, 0, false, 0)
+
+	// Once a channel was opened, the agent waits for the remaining delay.
+	testClock.SetTime(startTime.Add(10 * time.Minute))
+	assertReady(activation, 1, false, 50*time.Minute)
+
+	// A restart neither resets the delay nor the manually opened channel,
+	// even if it was closed in the meantime.
+	store, err = NewActivationStore(cdb)
+	require.NoError(t, err)
+
+	cfg.Store = store
+	activation, err = NewActivation(cfg)
+	require.NoError(t, err)
+	assertReady(activation, 0, false, 50*time.Minute)
+
+	// Once the delay passed, the agent is activated.
+	testClock.SetTime(startTime.Add(delay))
+	assertReady(activation, 0, true, 0)
+
+	state, err := store.FetchActivationState()
+	require.NoError(t, err)
+	require.True(t, startTime.Equal(state.FirstStart))
+	require.True(t, state.ManualChanSeen)
+}
+
+// TestActivationBlockDelay tests that the agent is only activated once the
+// activation block delay passed, and that the start height survives a
+// restart.
+func TestActivationBlockDelay(t *testing.T) {
+	t.Parallel()
+
+	const (
+		startHeight = 800_000
+		delayBlocks = 6
+	)
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewActivationStore(cdb)
+	require.NoError(t, err)
+
+	height := uint32(startHeight)
+	cfg := ActivationConfig{
+		DelayBlocks: delayBlocks,
+		BestHeight: func() (uint32, error) {
+			return height, nil
+		},
+		Store: store,
+		Clock: clock.NewTestClock(time.Unix(1_700_000_000, 0)),
+	}
+	activation, err := NewActivation(cfg)
+	require.NoError(t, err)
+
+	// The agent checks the block delay again after a while.
+	ready, waitFor, err := activation.Ready(0)
+	require.NoError(t, err)
+	require.False(t, ready)
+	require.Equal(t, blockDelayCheckInterval, waitFor)
+
+	// A restart doesn't reset the start height.
+	height = startHeight + delayBlocks - 1
+	activation, err = NewActivation(cfg)
+	require.NoError(t, err)
+
+	ready, _, err = activation.Ready(0)
+	require.NoError(t, err)
+	require.False(t, ready)
+
+	// Once enough blocks were mined, the agent is activated.
+	height = startHeight + delayBlocks
+	ready, waitFor, err = activation.Ready(0)
+	require.NoError(t, err)
+	require.True(t, ready)
+	require.Zero(t, waitFor)
+
+	state, err := store.FetchActivationState()
+	require.NoError(t, err)
+	require.EqualValues(t, startHeight, state.FirstStartHeight)
+
+	// A block delay requires a way to fetch the best height.
+	cfg.BestHeight = nil
+	_, err = NewActivation(cfg)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package autopilot
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
@@ -58,6 +59,19 @@ type Config struct {
 	// when opening channels.
 	Constraints AgentConstraints
 
+	// RecommendOnly, if set, makes the agent only record the channels it
+	// would open as recommendations instead of opening them.
+	RecommendOnly bool
+
+	// FeeBudget, if set, bounds the on-chain fees the agent may spend on
+	// opening channels within a period. The fees are expected to be
+	// reserved and settled by the ChanController.
+	FeeBudget *FeeBudget
+
+	// Activation, if set, holds back the agent from opening any channels
+	// until its activation requirements are met.
+	Activation *Activation
+
 	// TODO(roasbeef): add additional signals from fee rates and revenue of
 	// currently opened channels
 }
@@ -166,6 +180,13 @@ type Agent struct {
 	pendingOpens map[NodeID]LocalChannel
 	pendingMtx   sync.Mutex
 
+	// recommendations is the set of channels the agent would have opened
+	// during its last evaluation if it wasn't in recommend-only mode.
+	// recommendedAt is the time of that evaluation.
+	recommendations    []AttachmentDirective
+	recommendedAt      time.Time
+	recommendationsMtx sync.Mutex
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -420,6 +441,14 @@ func (a *Agent) controller() {
 		a.totalBalance = newBalance
 	}
 
+	// feeBudgetReset is set while the fee budget is exhausted and fires
+	// once the current budget period ends.
+	var feeBudgetReset <-chan time.Time
+
+	// activationDelay is set while the agent waits for its activation
+	// delay to pass and fires once it did.
+	var activationDelay <-chan time.Time
+
 	// TODO(roasbeef): add 10-minute wake up timer
 	for {
 		select {
@@ -498,6 +527,22 @@ func (a *Agent) controller() {
 			log.Debugf("Heuristic %v updated, assessing need for "+
 				"more channels", upd.heuristic.Name())
 
+		// The fee budget period ended, so we may be able to open
+		// channels again.
+		case <-feeBudgetReset:
+			log.Debugf("Fee budget period ended, assessing need " +
+				"for more channels")
+
+			feeBudgetReset = nil
+
+		// The activation delay passed, so we may be able to open our
+		// first channels.
+		case <-activationDelay:
+			log.Debugf("Activation delay passed, assessing need " +
+				"for more channels")
+
+			activationDelay = nil
+
 		// The agent has been signalled to exit, so we'll bail out
 		// immediately.
 		case <-a.quit:
@@ -514,9 +559,45 @@ func (a *Agent) controller() {
 		a.chanStateMtx.Lock()
 		a.pendingMtx.Lock()
 		totalChans := mergeChanState(a.pendingOpens, a.chanState)
+		numActiveChans := len(a.chanState)
 		a.pendingMtx.Unlock()
 		a.chanStateMtx.Unlock()
 
+		// Before the activation requirements are met, we won't open
+		// any channels.
+		if a.cfg.Activation != nil {
+			ready, waitFor, err := a.cfg.Activation.Ready(
+				numActiveChans,
+			)
+			if err != nil {
+				log.Errorf("Unable to check activation "+
+					"requirements: %v", err)
+
+				continue
+			}
+
+			switch {
+			case !ready && waitFor > 0:
+				log.Infof("Autopilot activation delay not "+
+					"passed yet, waiting for %v", waitFor)
+
+				a.setRecommendations(nil)
+				if activationDelay == nil {
+					activationDelay = time.After(waitFor)
+				}
+
+				continue
+
+			case !ready:
+				log.Infof("Waiting for a manually opened " +
+					"channel before activating autopilot")
+
+				a.setRecommendations(nil)
+
+				continue
+			}
+		}
+
 		// Now that we've updated our internal state, we'll consult our
 		// channel attachment heuristic to determine if we can open
 		// up any additional channels while staying within our
@@ -526,16 +607,41 @@ func (a *Agent) controller() {
 		)
 		switch {
 		case numChans == 0:
+			a.setRecommendations(nil)
 			continue
 
 		// If the amount is too small, we don't want to attempt opening
 		// another channel.
 		case availableFunds == 0:
+			a.setRecommendations(nil)
 			continue
 		case availableFunds < a.cfg.Constraints.MinChanSize():
+			a.setRecommendations(nil)
 			continue
 		}
 
+		// If we already spent our fee budget for the current period,
+		// we'll wait for the next one before opening any channels.
+		if a.cfg.FeeBudget != nil {
+			exhausted, resetIn, err := a.cfg.FeeBudget.Exhausted()
+			if err != nil {
+				log.Errorf("Unable to check fee budget: %v",
+					err)
+
+				continue
+			}
+
+			if exhausted {
+				log.Infof("Fee budget exhausted, not opening "+
+					"channels for %v", resetIn)
+
+				a.setRecommendations(nil)
+				feeBudgetReset = time.After(resetIn)
+
+				continue
+			}
+		}
+
 		log.Infof("Triggering attachment directive dispatch, "+
 			"total_funds=%v", a.totalBalance)
 
@@ -678,6 +784,23 @@ func (a *Agent) openChans(availableFunds btcutil.Amount, numChans uint32,
 		}
 	}
 
+	// In recommend-only mode, we stop here and only record the channels
+	// we would have opened.
+	if a.cfg.RecommendOnly {
+		directives := make(
+			[]AttachmentDirective, 0, len(chanCandidates),
+		)
+		for _, chanCandidate := range chanCandidates {
+			directives = append(directives, *chanCandidate)
+		}
+		a.setRecommendations(directives)
+
+		log.Infof("Recommending %d channel(s) to open",
+			len(directives))
+
+		return nil
+	}
+
 	if len(chanCandidates) == 0 {
 		log.Infof("No eligible candidates to connect to")
 		return nil
@@ -721,6 +844,35 @@ func (a *Agent) openChans(availableFunds btcutil.Amount, numChans uint32,
 	return nil
 }
 
+// setRecommendations replaces the agent's channel recommendations with the
+// given set of attachment directives. It is a no-op unless the agent is in
+// recommend-only mode.
+func (a *Agent) setRecommendations(directives []AttachmentDirective) {
+	if !a.cfg.RecommendOnly {
+		return
+	}
+
+	a.recommendationsMtx.Lock()
+	defer a.recommendationsMtx.Unlock()
+
+	a.recommendations = directives
+	a.recommendedAt = time.Now()
+}
+
+// Recommendations returns the channels the agent recommends to open, along
+// with the time they were last evaluated. The recommendations are refreshed
+// every time the agent re-evaluates its state, but only recorded if it is in
+// recommend-only mode.
+func (a *Agent) Recommendations() ([]AttachmentDirective, time.Time) {
+	a.recommendationsMtx.Lock()
+	defer a.recommendationsMtx.Unlock()
+
+	directives := make([]AttachmentDirective, len(a.recommendations))
+	copy(directives, a.recommendations)
+
+	return directives, a.recommendedAt
+}
+
 // executeDirective attempts to connect to the channel candidate specified by
 // the given attachment directive, and open a channel of the given size.
 //
@@ -842,10 +994,13 @@ func (a *Agent) executeDirective(directive AttachmentDirective) {
 
 		// As the attempt failed, we'll clear the peer from the set of
 		// pending opens and mark them as failed so we don't attempt to
-		// open a channel to them again.
+		// open a channel to them again. If we just ran out of fee
+		// budget, the peer isn't to blame though.
 		a.pendingMtx.Lock()
 		delete(a.pendingOpens, nodeID)
-		a.failedNodes[nodeID] = struct{}{}
+		if !errors.Is(err, ErrFeeBudgetExhausted) {
+			a.failedNodes[nodeID] = struct{}{}
+		}
 		a.pendingMtx.Unlock()
 
 		// Trigger the agent to re-evaluate everything and possibly
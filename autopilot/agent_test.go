@@ -11,6 +11,8 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -155,7 +157,9 @@ type testContext struct {
 	sync.Mutex
 }
 
-func setup(t *testing.T, initialChans []LocalChannel) *testContext {
+func setup(t *testing.T, initialChans []LocalChannel,
+	cfgModifiers ...func(*Config)) *testContext {
+
 	t.Helper()
 
 	// First, we'll create all the dependencies that we'll need in order to
@@ -213,6 +217,9 @@ func setup(t *testing.T, initialChans []LocalChannel) *testContext {
 		Graph:       memGraph,
 		Constraints: constraints,
 	}
+	for _, modifier := range cfgModifiers {
+		modifier(&testCfg)
+	}
 
 	agent, err := New(testCfg, initialChans)
 	require.NoError(t, err, "unable to create agent")
@@ -639,6 +646,117 @@ func TestAgentPrivateChannels(t *testing.T) {
 	}
 }
 
+// TestAgentRecommendOnly ensures that an agent in recommend-only mode records
+// the channels it would open as recommendations without opening them.
+func TestAgentRecommendOnly(t *testing.T) {
+	t.Parallel()
+
+	testCtx := setup(t, nil, func(cfg *Config) {
+		cfg.RecommendOnly = true
+	})
+
+	const numChans = 5
+
+	directives := make(map[NodeID]*NodeScore)
+	for i := 0; i < numChans; i++ {
+		pub, err := testCtx.graph.addRandNode()
+		require.NoError(t, err, "unable to generate key")
+
+		directives[NewNodeID(pub)] = &NodeScore{
+			NodeID: NewNodeID(pub),
+			Score:  0.5,
+		}
+	}
+
+	// Before the agent evaluated its state, there should be no
+	// recommendations.
+	recommendations, updatedAt := testCtx.agent.Recommendations()
+	require.Empty(t, recommendations)
+	require.True(t, updatedAt.IsZero())
+
+	// We'll give the agent a budget of 5 BTC to open 5 channels, and
+	// provide it with our fake directives.
+	respondMoreChans(t, testCtx, moreChansResp{
+		numMore: numChans,
+		amt:     5 * btcutil.SatoshiPerBitcoin,
+	})
+	respondNodeScores(t, testCtx, directives)
+
+	// The agent should now recommend a channel to each of the nodes.
+	require.Eventually(t, func() bool {
+		recommendations, _ = testCtx.agent.Recommendations()
+		return len(recommendations) == numChans
+	}, 10*time.Second, 10*time.Millisecond)
+
+	for _, recommendation := range recommendations {
+		require.Contains(t, directives, recommendation.NodeID)
+		require.EqualValues(
+			t, btcutil.SatoshiPerBitcoin, recommendation.ChanAmt,
+		)
+	}
+	_, updatedAt = testCtx.agent.Recommendations()
+	require.False(t, updatedAt.IsZero())
+
+	// However, none of the channels should actually be opened.
+	chanController := testCtx.chanController.(*mockChanController)
+	select {
+	case <-chanController.openChanSignals:
+		t.Fatalf("agent unexpectedly opened channel")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once the budget is used up, the recommendations should be cleared.
+	testCtx.agent.OnBalanceChange()
+	respondMoreChans(t, testCtx, moreChansResp{
+		numMore: 0,
+	})
+	require.Eventually(t, func() bool {
+		recommendations, _ = testCtx.agent.Recommendations()
+		return len(recommendations) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+// TestAgentFeeBudgetExhausted ensures that the agent doesn't attempt to open
+// any channels once its fee budget is exhausted.
+func TestAgentFeeBudgetExhausted(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewFeeBudgetStore(cdb)
+	require.NoError(t, err)
+
+	feeBudget, err := NewFeeBudget(FeeBudgetConfig{
+		Budget: 1_000,
+		Period: time.Hour,
+		Store:  store,
+		Clock:  clock.NewDefaultClock(),
+	})
+	require.NoError(t, err)
+	_, err = feeBudget.Reserve(1_000)
+	require.NoError(t, err)
+
+	testCtx := setup(t, nil, func(cfg *Config) {
+		cfg.FeeBudget = feeBudget
+	})
+
+	// Even though the agent has the funds to open more channels, it
+	// shouldn't consult the heuristic, as it can't pay for the fees.
+	respondMoreChans(t, testCtx, moreChansResp{
+		numMore: 1,
+		amt:     btcutil.SatoshiPerBitcoin,
+	})
+
+	select {
+	case <-testCtx.heuristic.nodeScoresArgs:
+		t.Fatalf("heuristic unexpectedly queried")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestAgentPendingChannelState ensures that the agent properly factors in its
 // pending channel state when making decisions w.r.t if it needs more channels
 // or not, and if so, who is eligible to open new channels to.
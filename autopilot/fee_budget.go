@@ -0,0 +1,352 @@
+package autopilot
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const (
+	// DefaultFeeBudgetPeriod is the default length of the period the fee
+	// budget of the autopilot agent applies to.
+	DefaultFeeBudgetPeriod = 24 * time.Hour
+)
+
+var (
+	// feeBudgetBucketKey is the top level bucket that stores the on-chain
+	// fees spent by the autopilot agent within the current budget period.
+	feeBudgetBucketKey = []byte("autopilot-fee-budget")
+
+	// feeBudgetPeriodStartKey is the key that stores the unix nano
+	// timestamp of the start of the current budget period.
+	feeBudgetPeriodStartKey = []byte("period-start")
+
+	// feeBudgetSpentKey is the key that stores the fees in satoshis spent
+	// within the current budget period.
+	feeBudgetSpentKey = []byte("fees-spent")
+
+	byteOrder = binary.BigEndian
+)
+
+// FeeBudgetStore persists the on-chain fees spent by the autopilot agent, so
+// the spent budget isn't reset by a restart.
+type FeeBudgetStore interface {
+	// FetchFeeSpend returns the start of the current budget period and
+	// the fees spent within it. A zero time is returned if nothing was
+	// stored yet.
+	FetchFeeSpend() (time.Time, btcutil.Amount, error)
+
+	// PutFeeSpend stores the start of the current budget period and the
+	// fees spent within it.
+	PutFeeSpend(periodStart time.Time, spent btcutil.Amount) error
+}
+
+// feeBudgetStore is a FeeBudgetStore backed by a kvdb.Backend.
+type feeBudgetStore struct {
+	db kvdb.Backend
+}
+
+// NewFeeBudgetStore returns a new FeeBudgetStore backed by the given
+// database.
+func NewFeeBudgetStore(db kvdb.Backend) (FeeBudgetStore, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(feeBudgetBucketKey)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &feeBudgetStore{
+		db: db,
+	}, nil
+}
+
+// FetchFeeSpend returns the start of the current budget period and the fees
+// spent within it.
+//
+// NOTE: This is part of the FeeBudgetStore interface.
+func (s *feeBudgetStore) FetchFeeSpend() (time.Time, btcutil.Amount, error) {
+	var (
+		periodStart time.Time
+		spent       btcutil.Amount
+	)
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(feeBudgetBucketKey)
+		if bucket == nil {
+			return errors.New("fee budget bucket does not exist")
+		}
+
+		startBytes := bucket.Get(feeBudgetPeriodStartKey)
+		spentBytes := bucket.Get(feeBudgetSpentKey)
+		if startBytes == nil || spentBytes == nil {
+			return nil
+		}
+
+		periodStart = time.Unix(
+			0, int64(byteOrder.Uint64(startBytes)),
+		)
+		spent = btcutil.Amount(byteOrder.Uint64(spentBytes))
+
+		return nil
+	}, func() {
+		periodStart = time.Time{}
+		spent = 0
+	})
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return periodStart, spent, nil
+}
+
+// PutFeeSpend stores the start of the current budget period and the fees
+// spent within it.
+//
+// NOTE: This is part of the FeeBudgetStore interface.
+func (s *feeBudgetStore) PutFeeSpend(periodStart time.Time,
+	spent btcutil.Amount) error {
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(feeBudgetBucketKey)
+		if bucket == nil {
+			return errors.New("fee budget bucket does not exist")
+		}
+
+		var startBytes, spentBytes [8]byte
+		startNano := uint64(periodStart.UnixNano())
+		byteOrder.PutUint64(startBytes[:], startNano)
+		byteOrder.PutUint64(spentBytes[:], uint64(spent))
+
+		err := bucket.Put(feeBudgetPeriodStartKey, startBytes[:])
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(feeBudgetSpentKey, spentBytes[:])
+	}, func() {})
+}
+
+// FeeBudgetConfig houses the parameters of a FeeBudget.
+type FeeBudgetConfig struct {
+	// Budget is the maximum amount of on-chain fees the agent may spend
+	// on opening channels within a single period.
+	Budget btcutil.Amount
+
+	// Period is the length of a budget period. Once a period ends, the
+	// spent fees are reset.
+	Period time.Duration
+
+	// Store persists the spent fees across restarts.
+	Store FeeBudgetStore
+
+	// Clock is the time source used to determine the current period.
+	Clock clock.Clock
+}
+
+// ErrFeeBudgetExhausted is returned if a fee can't be reserved because it
+// would exceed the fee budget of the current period.
+var ErrFeeBudgetExhausted = errors.New("fee budget exhausted")
+
+// FeeReservation is a fee that was reserved from the budget before publishing
+// a funding transaction. It must either be settled with the actual fee or
+// released if the transaction wasn't published.
+type FeeReservation struct {
+	// periodStart is the start of the period the fee was reserved in.
+	periodStart time.Time
+
+	// amount is the reserved fee.
+	amount btcutil.Amount
+}
+
+// FeeBudget tracks the on-chain fees the autopilot agent spent on opening
+// channels within the current period. Once the budget is exhausted, the agent
+// stops opening channels until the period ends.
+type FeeBudget struct {
+	cfg FeeBudgetConfig
+
+	// periodStart is the start of the current budget period.
+	periodStart time.Time
+
+	// spent is the amount of fees spent or reserved within the current
+	// period.
+	spent btcutil.Amount
+
+	// denied is set once a reservation was denied within the current
+	// period, as the remaining budget doesn't cover another channel open.
+	denied bool
+
+	mtx sync.Mutex
+}
+
+// NewFeeBudget creates a new FeeBudget, restoring the fees spent within the
+// current period from the store.
+func NewFeeBudget(cfg FeeBudgetConfig) (*FeeBudget, error) {
+	if cfg.Period <= 0 {
+		return nil, fmt.Errorf("invalid fee budget period %v",
+			cfg.Period)
+	}
+
+	periodStart, spent, err := cfg.Store.FetchFeeSpend()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch spent fees: %w", err)
+	}
+
+	b := &FeeBudget{
+		cfg:         cfg,
+		periodStart: periodStart,
+		spent:       spent,
+	}
+
+	// If this is the first time the budget is used, the first period
+	// starts now.
+	if periodStart.IsZero() {
+		b.periodStart = cfg.Clock.Now()
+		err := cfg.Store.PutFeeSpend(b.periodStart, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// rollPeriod starts a new budget period if the current one has ended.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (b *FeeBudget) rollPeriod() error {
+	now := b.cfg.Clock.Now()
+	if now.Before(b.periodStart.Add(b.cfg.Period)) {
+		return nil
+	}
+
+	// Periods follow each other back to back, so we'll skip ahead to the
+	// period that contains the current time.
+	elapsed := now.Sub(b.periodStart) / b.cfg.Period
+	periodStart := b.periodStart.Add(elapsed * b.cfg.Period)
+
+	if err := b.cfg.Store.PutFeeSpend(periodStart, 0); err != nil {
+		return err
+	}
+
+	log.Debugf("Starting new fee budget period at %v, spent %v in the "+
+		"previous period", periodStart, b.spent)
+
+	b.periodStart = periodStart
+	b.spent = 0
+	b.denied = false
+
+	return nil
+}
+
+// Exhausted returns true if the fees spent within the current period reached
+// the budget, along with the time left until the current period ends.
+func (b *FeeBudget) Exhausted() (bool, time.Duration, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.rollPeriod(); err != nil {
+		return false, 0, err
+	}
+
+	periodEnd := b.periodStart.Add(b.cfg.Period)
+	resetIn := periodEnd.Sub(b.cfg.Clock.Now())
+
+	return b.spent >= b.cfg.Budget || b.denied, resetIn, nil
+}
+
+// Reserve reserves the given estimated fee from the budget of the current
+// period. The reservation is persisted right away, so concurrent channel
+// opens can't overshoot the budget and a crash after publishing a funding
+// transaction doesn't lose its fee. ErrFeeBudgetExhausted is returned if the
+// fee exceeds the remaining budget.
+func (b *FeeBudget) Reserve(fee btcutil.Amount) (*FeeReservation, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.rollPeriod(); err != nil {
+		return nil, err
+	}
+
+	spent := b.spent + fee
+	if spent > b.cfg.Budget {
+		b.denied = true
+
+		return nil, fmt.Errorf("%w: %v of %v used, unable to reserve "+
+			"%v", ErrFeeBudgetExhausted, b.spent, b.cfg.Budget, fee)
+	}
+
+	if err := b.cfg.Store.PutFeeSpend(b.periodStart, spent); err != nil {
+		return nil, err
+	}
+	b.spent = spent
+
+	log.Debugf("Autopilot reserved %v for fees, %v of %v budget used in "+
+		"the current period", fee, spent, b.cfg.Budget)
+
+	return &FeeReservation{
+		periodStart: b.periodStart,
+		amount:      fee,
+	}, nil
+}
+
+// Settle replaces the given reservation with the actual fee that was paid. A
+// reservation of a past period is forgotten, the fee is charged to the current
+// period instead.
+func (b *FeeBudget) Settle(res *FeeReservation, fee btcutil.Amount) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.rollPeriod(); err != nil {
+		return err
+	}
+
+	spent := b.spent
+	if res.periodStart.Equal(b.periodStart) {
+		spent -= min(res.amount, spent)
+	}
+	spent += fee
+
+	if err := b.cfg.Store.PutFeeSpend(b.periodStart, spent); err != nil {
+		return err
+	}
+	b.spent = spent
+
+	log.Debugf("Autopilot spent %v on fees, %v of %v budget used in the "+
+		"current period", fee, spent, b.cfg.Budget)
+
+	return nil
+}
+
+// Release returns the given reservation to the budget, as the funding
+// transaction it was made for wasn't published.
+func (b *FeeBudget) Release(res *FeeReservation) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.rollPeriod(); err != nil {
+		return err
+	}
+
+	// A reservation of a past period was already reset.
+	if !res.periodStart.Equal(b.periodStart) {
+		return nil
+	}
+
+	spent := b.spent - min(res.amount, b.spent)
+	if err := b.cfg.Store.PutFeeSpend(b.periodStart, spent); err != nil {
+		return err
+	}
+	b.spent = spent
+
+	// The released fee might be enough for another channel open.
+	b.denied = false
+
+	return nil
+}
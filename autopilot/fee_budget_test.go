@@ -0,0 +1,102 @@
+package autopilot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeBudget tests that the fee budget is exhausted once the reserved fees
+// reach the budget, that it's reset once the period ends and that the spent
+// fees survive a restart.
+func TestFeeBudget(t *testing.T) {
+	t.Parallel()
+
+	const (
+		budget = btcutil.Amount(10_000)
+		period = time.Hour
+	)
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	store, err := NewFeeBudgetStore(cdb)
+	require.NoError(t, err)
+
+	startTime := time.Unix(1_700_000_000, 0)
+	testClock := clock.NewTestClock(startTime)
+
+	cfg := FeeBudgetConfig{
+		Budget: budget,
+		Period: period,
+		Store:  store,
+		Clock:  testClock,
+	}
+	feeBudget, err := NewFeeBudget(cfg)
+	require.NoError(t, err)
+
+	assertExhausted := func(b *FeeBudget, expected bool,
+		expectedResetIn time.Duration) {
+
+		t.Helper()
+
+		exhausted, resetIn, err := b.Exhausted()
+		require.NoError(t, err)
+		require.Equal(t, expected, exhausted)
+		require.Equal(t, expectedResetIn, resetIn)
+	}
+
+	// Reserving less than the budget leaves the budget available.
+	assertExhausted(feeBudget, false, period)
+	first, err := feeBudget.Reserve(4_000)
+	require.NoError(t, err)
+	second, err := feeBudget.Reserve(4_000)
+	require.NoError(t, err)
+	assertExhausted(feeBudget, false, period)
+
+	// A concurrent reservation that would exceed the budget is denied,
+	// which exhausts the budget.
+	_, err = feeBudget.Reserve(4_000)
+	require.ErrorIs(t, err, ErrFeeBudgetExhausted)
+	assertExhausted(feeBudget, true, period)
+
+	// Releasing a reservation makes the budget available again.
+	require.NoError(t, feeBudget.Release(second))
+	assertExhausted(feeBudget, false, period)
+
+	// Settling replaces the reservation with the actual fee. Once the
+	// budget is used up, it's exhausted.
+	testClock.SetTime(startTime.Add(10 * time.Minute))
+	require.NoError(t, feeBudget.Settle(first, 5_000))
+	_, err = feeBudget.Reserve(5_000)
+	require.NoError(t, err)
+	assertExhausted(feeBudget, true, 50*time.Minute)
+
+	_, spent, err := store.FetchFeeSpend()
+	require.NoError(t, err)
+	require.Equal(t, budget, spent)
+
+	// A restart within the same period must not reset the spent fees.
+	store, err = NewFeeBudgetStore(cdb)
+	require.NoError(t, err)
+
+	cfg.Store = store
+	feeBudget, err = NewFeeBudget(cfg)
+	require.NoError(t, err)
+	assertExhausted(feeBudget, true, 50*time.Minute)
+
+	// Once the period ends, the budget is available again. Periods follow
+	// each other back to back, even if a whole period was skipped.
+	testClock.SetTime(startTime.Add(2*period + 15*time.Minute))
+	assertExhausted(feeBudget, false, 45*time.Minute)
+
+	// The new period is persisted as well.
+	periodStart, spent, err := store.FetchFeeSpend()
+	require.NoError(t, err)
+	require.True(t, startTime.Add(2*period).Equal(periodStart))
+	require.Zero(t, spent)
+}
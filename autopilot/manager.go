@@ -3,6 +3,7 @@ package autopilot
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
@@ -390,3 +391,25 @@ func (m *Manager) SetNodeScores(name string, scores map[NodeID]float64) error {
 
 	return nil
 }
+
+// Recommendations returns the channels the autopilot agent recommends to open
+// while in recommend-only mode, along with the time they were last evaluated.
+// An error is returned if the agent isn't active or not in recommend-only
+// mode.
+func (m *Manager) Recommendations() ([]AttachmentDirective, time.Time, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.cfg.PilotCfg.RecommendOnly {
+		return nil, time.Time{}, fmt.Errorf("autopilot is not in " +
+			"recommend-only mode")
+	}
+
+	if m.pilot == nil {
+		return nil, time.Time{}, fmt.Errorf("autopilot is not active")
+	}
+
+	directives, updatedAt := m.pilot.Recommendations()
+
+	return directives, updatedAt, nil
+}
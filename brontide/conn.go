@@ -37,6 +37,27 @@ var _ net.Conn = (*Conn)(nil)
 func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	timeout time.Duration, dialer tor.DialFunc) (*Conn, error) {
 
+	return dial(local, netAddr, timeout, dialer, time.Time{})
+}
+
+// DialWithDeadline is like Dial, but the whole connection attempt including
+// the handshake must complete before the given deadline. The handshake isn't
+// bound by the usual handshake read timeout instead, so callers can wait
+// longer for slow peers, e.g. those reached over Tor.
+func DialWithDeadline(local keychain.SingleKeyECDH,
+	netAddr *lnwire.NetAddress, deadline time.Time,
+	dialer tor.DialFunc) (*Conn, error) {
+
+	return dial(local, netAddr, time.Until(deadline), dialer, deadline)
+}
+
+// dial establishes the connection to the remote peer and performs the
+// handshake. If the given handshake deadline is zero, the remote peer must
+// respond to our first act within handshakeReadTimeout.
+func dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
+	timeout time.Duration, dialer tor.DialFunc,
+	handshakeDeadline time.Time) (*Conn, error) {
+
 	ipAddr := netAddr.Address.String()
 	var conn net.Conn
 	var err error
@@ -62,9 +83,12 @@ func Dial(local keychain.SingleKeyECDH, netAddr *lnwire.NetAddress,
 	}
 
 	// We'll ensure that we get ActTwo from the remote peer in a timely
-	// manner. If they don't respond within handshakeReadTimeout, then
-	// we'll kill the connection.
-	err = conn.SetReadDeadline(time.Now().Add(handshakeReadTimeout))
+	// manner. If they don't respond within handshakeReadTimeout or before
+	// the deadline of the caller, then we'll kill the connection.
+	if handshakeDeadline.IsZero() {
+		handshakeDeadline = time.Now().Add(handshakeReadTimeout)
+	}
+	err = conn.SetReadDeadline(handshakeDeadline)
 	if err != nil {
 		b.conn.Close()
 		return nil, err
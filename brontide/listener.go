@@ -24,18 +24,34 @@ type Listener struct {
 
 	tcp *net.TCPListener
 
+	// connFilter decides whether a connection from the given remote
+	// address is accepted. If nil, all connections are accepted.
+	connFilter func(remoteAddr net.Addr) bool
+
 	handshakeSema chan struct{}
 	conns         chan maybeConn
 	quit          chan struct{}
 }
 
+// ListenerOption is a functional option that modifies a Listener.
+type ListenerOption func(*Listener)
+
+// WithConnFilter sets a filter that is applied to the remote address of every
+// accepted connection before the handshake is started. Connections the filter
+// doesn't allow are closed right away.
+func WithConnFilter(filter func(remoteAddr net.Addr) bool) ListenerOption {
+	return func(l *Listener) {
+		l.connFilter = filter
+	}
+}
+
 // A compile-time assertion to ensure that Conn meets the net.Listener interface.
 var _ net.Listener = (*Listener)(nil)
 
 // NewListener returns a new net.Listener which enforces the Brontide scheme
 // during both initial connection establishment and data transfer.
 func NewListener(localStatic keychain.SingleKeyECDH,
-	listenAddr string) (*Listener, error) {
+	listenAddr string, opts ...ListenerOption) (*Listener, error) {
 
 	addr, err := net.ResolveTCPAddr("tcp", listenAddr)
 	if err != nil {
@@ -54,6 +70,9 @@ func NewListener(localStatic keychain.SingleKeyECDH,
 		conns:         make(chan maybeConn),
 		quit:          make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(brontideListener)
+	}
 
 	for i := 0; i < defaultHandshakes; i++ {
 		brontideListener.handshakeSema <- struct{}{}
@@ -84,6 +103,14 @@ func (l *Listener) listen() {
 			continue
 		}
 
+		// Drop connections from disallowed remote addresses before
+		// spending any resources on the handshake.
+		if l.connFilter != nil && !l.connFilter(conn.RemoteAddr()) {
+			conn.Close()
+			l.handshakeSema <- struct{}{}
+			continue
+		}
+
 		go l.doHandshake(conn)
 	}
 }
@@ -22,7 +22,9 @@ type maybeNetConn struct {
 	err  error
 }
 
-func makeListener() (*Listener, *lnwire.NetAddress, error) {
+func makeListener(opts ...ListenerOption) (*Listener, *lnwire.NetAddress,
+	error) {
+
 	// First, generate the long-term private keys for the brontide listener.
 	localPriv, err := btcec.NewPrivateKey()
 	if err != nil {
@@ -35,7 +37,7 @@ func makeListener() (*Listener, *lnwire.NetAddress, error) {
 	addr := "localhost:0"
 
 	// Our listener will be local, and the connection remote.
-	listener, err := NewListener(localKeyECDH, addr)
+	listener, err := NewListener(localKeyECDH, addr, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -216,6 +218,52 @@ func TestConcurrentHandshakes(t *testing.T) {
 	result.conn.Close()
 }
 
+// TestListenerConnFilter tests that connections rejected by the connection
+// filter of the listener are closed before the handshake, while allowed ones
+// are accepted as usual.
+func TestListenerConnFilter(t *testing.T) {
+	allow := make(chan bool, 1)
+	filter := func(remoteAddr net.Addr) bool {
+		return <-allow
+	}
+
+	listener, netAddr, err := makeListener(WithConnFilter(filter))
+	require.NoError(t, err, "unable to create listener connection")
+	defer listener.Close()
+
+	remotePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err, "unable to generate private key")
+	remoteKeyECDH := &keychain.PrivKeyECDH{PrivKey: remotePriv}
+
+	dial := func() (net.Conn, error) {
+		return Dial(
+			remoteKeyECDH, netAddr, tor.DefaultConnTimeout,
+			net.DialTimeout,
+		)
+	}
+
+	// A rejected connection is closed before the handshake completes.
+	allow <- false
+	_, err = dial()
+	require.Error(t, err)
+
+	// An allowed connection completes the handshake and is accepted.
+	allow <- true
+	connChan := make(chan maybeNetConn, 1)
+	go func() {
+		conn, err := dial()
+		connChan <- maybeNetConn{conn, err}
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err, "unable to accept dial")
+	defer conn.Close()
+
+	result := <-connChan
+	require.NoError(t, result.err)
+	result.conn.Close()
+}
+
 func TestMaxPayloadLength(t *testing.T) {
 	t.Parallel()
 
@@ -120,38 +120,67 @@ type LeveledSubLogger interface {
 
 // ParseAndSetDebugLevels attempts to parse the specified debug level and set
 // the levels accordingly on the given logger. An appropriate error is returned
-// if anything is invalid.
+// if anything is invalid, in which case none of the levels are changed.
 func ParseAndSetDebugLevels(level string, logger LeveledSubLogger) error {
+	globalLevel, subLevels, err := parseDebugLevels(level, logger)
+	if err != nil {
+		return err
+	}
+
+	// Change the logging level for all subsystems first, so the levels of
+	// specific subsystems take precedence.
+	if globalLevel != "" {
+		logger.SetLogLevels(globalLevel)
+	}
+
+	for _, subLevel := range subLevels {
+		logger.SetLogLevel(subLevel.subsystem, subLevel.level)
+	}
+
+	return nil
+}
+
+// subsystemLevel is the log level requested for a single subsystem.
+type subsystemLevel struct {
+	subsystem string
+	level     string
+}
+
+// parseDebugLevels parses and validates the specified debug level without
+// changing any log levels. It returns the level for all subsystems, which is
+// empty if not specified, and the levels for specific subsystems.
+func parseDebugLevels(level string, logger LeveledSubLogger) (string,
+	[]subsystemLevel, error) {
+
 	// Split at the delimiter.
 	levels := strings.Split(level, ",")
 	if len(levels) == 0 {
-		return fmt.Errorf("invalid log level: %v", level)
+		return "", nil, fmt.Errorf("invalid log level: %v", level)
 	}
 
 	// If the first entry has no =, treat is as the log level for all
 	// subsystems.
-	globalLevel := levels[0]
-	if !strings.Contains(globalLevel, "=") {
+	var globalLevel string
+	if !strings.Contains(levels[0], "=") {
+		globalLevel = levels[0]
+
 		// Validate debug log level.
 		if !validLogLevel(globalLevel) {
 			str := "the specified debug level [%v] is invalid"
-			return fmt.Errorf(str, globalLevel)
+			return "", nil, fmt.Errorf(str, globalLevel)
 		}
 
-		// Change the logging level for all subsystems.
-		logger.SetLogLevels(globalLevel)
-
 		// The rest will target specific subsystems.
 		levels = levels[1:]
 	}
 
-	// Go through the subsystem/level pairs while detecting issues and
-	// update the log levels accordingly.
+	// Go through the subsystem/level pairs while detecting issues.
+	subLevels := make([]subsystemLevel, 0, len(levels))
 	for _, logLevelPair := range levels {
 		if !strings.Contains(logLevelPair, "=") {
 			str := "the specified debug level contains an " +
 				"invalid subsystem/level pair [%v]"
-			return fmt.Errorf(str, logLevelPair)
+			return "", nil, fmt.Errorf(str, logLevelPair)
 		}
 
 		// Extract the specified subsystem and log level.
@@ -160,7 +189,7 @@ func ParseAndSetDebugLevels(level string, logger LeveledSubLogger) error {
 			str := "the specified debug level has an invalid " +
 				"format [%v] -- use format subsystem1=level1," +
 				"subsystem2=level2"
-			return fmt.Errorf(str, logLevelPair)
+			return "", nil, fmt.Errorf(str, logLevelPair)
 		}
 		subsysID, logLevel := fields[0], fields[1]
 		subLoggers := logger.SubLoggers()
@@ -169,7 +198,7 @@ func ParseAndSetDebugLevels(level string, logger LeveledSubLogger) error {
 		if _, exists := subLoggers[subsysID]; !exists {
 			str := "the specified subsystem [%v] is invalid -- " +
 				"supported subsystems are %v"
-			return fmt.Errorf(
+			return "", nil, fmt.Errorf(
 				str, subsysID, logger.SupportedSubsystems(),
 			)
 		}
@@ -177,13 +206,16 @@ func ParseAndSetDebugLevels(level string, logger LeveledSubLogger) error {
 		// Validate log level.
 		if !validLogLevel(logLevel) {
 			str := "the specified debug level [%v] is invalid"
-			return fmt.Errorf(str, logLevel)
+			return "", nil, fmt.Errorf(str, logLevel)
 		}
 
-		logger.SetLogLevel(subsysID, logLevel)
+		subLevels = append(subLevels, subsystemLevel{
+			subsystem: subsysID,
+			level:     logLevel,
+		})
 	}
 
-	return nil
+	return globalLevel, subLevels, nil
 }
 
 // validLogLevel returns whether or not logLevel is a valid debug log level.
@@ -89,6 +89,11 @@ func TestParseAndSetDebugLevels(t *testing.T) {
 			debugLevel: "PEER=info,debug,SRVR=debug",
 			expErr:     "invalid",
 		},
+		{
+			name:       "valid global+invalid subsystem debug level",
+			debugLevel: "trace,PEER=info,SRVR=loud",
+			expErr:     "invalid",
+		},
 	}
 
 	for _, test := range testCases {
@@ -107,6 +112,12 @@ func TestParseAndSetDebugLevels(t *testing.T) {
 			err := build.ParseAndSetDebugLevels(test.debugLevel, m)
 			if test.expErr != "" {
 				require.Contains(t, err.Error(), test.expErr)
+
+				// An invalid level must not change any of the
+				// levels.
+				require.Empty(t, m.globalLogLevel)
+				require.Empty(t, m.subLogLevels)
+
 				return
 			}
 			require.NoError(t, err)
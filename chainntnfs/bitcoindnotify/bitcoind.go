@@ -73,6 +73,14 @@ type BitcoindNotifier struct {
 	// memNotifier notifies clients of events related to the mempool.
 	memNotifier *chainntnfs.MempoolNotifier
 
+	// rescanLimiter limits the number of historical rescans that are
+	// performed concurrently.
+	rescanLimiter *chainntnfs.RescanLimiter
+
+	// blockDownloadConcurrency is the number of blocks that are fetched
+	// in parallel while scanning the chain.
+	blockDownloadConcurrency uint32
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -91,7 +99,8 @@ var _ chainntnfs.MempoolWatcher = (*BitcoindNotifier)(nil)
 func New(chainConn *chain.BitcoindConn, chainParams *chaincfg.Params,
 	spendHintCache chainntnfs.SpendHintCache,
 	confirmHintCache chainntnfs.ConfirmHintCache,
-	blockCache *blockcache.BlockCache) *BitcoindNotifier {
+	blockCache *blockcache.BlockCache,
+	rescanConcurrency, blockDownloadConcurrency uint32) *BitcoindNotifier {
 
 	notifier := &BitcoindNotifier{
 		chainParams: chainParams,
@@ -104,8 +113,11 @@ func New(chainConn *chain.BitcoindConn, chainParams *chaincfg.Params,
 		spendHintCache:   spendHintCache,
 		confirmHintCache: confirmHintCache,
 
-		blockCache:  blockCache,
-		memNotifier: chainntnfs.NewMempoolNotifier(),
+		blockCache:    blockCache,
+		memNotifier:   chainntnfs.NewMempoolNotifier(),
+		rescanLimiter: chainntnfs.NewRescanLimiter(rescanConcurrency),
+
+		blockDownloadConcurrency: blockDownloadConcurrency,
 
 		quit: make(chan struct{}),
 	}
@@ -254,6 +266,13 @@ out:
 				go func(msg *chainntnfs.HistoricalConfDispatch) {
 					defer b.wg.Done()
 
+					// Wait until we're allowed to start
+					// another rescan.
+					if !b.rescanLimiter.Acquire(b.quit) {
+						return
+					}
+					defer b.rescanLimiter.Release()
+
 					confDetails, _, err := b.historicalConfDetails(
 						msg.ConfRequest,
 						msg.StartHeight, msg.EndHeight,
@@ -299,6 +318,13 @@ out:
 				go func(msg *chainntnfs.HistoricalSpendDispatch) {
 					defer b.wg.Done()
 
+					// Wait until we're allowed to start
+					// another rescan.
+					if !b.rescanLimiter.Acquire(b.quit) {
+						return
+					}
+					defer b.rescanLimiter.Release()
+
 					spendDetails, err := b.historicalSpendDetails(
 						msg.SpendRequest,
 						msg.StartHeight, msg.EndHeight,
@@ -581,46 +607,41 @@ func (b *BitcoindNotifier) confDetailsManually(confRequest chainntnfs.ConfReques
 
 	// Begin scanning blocks at every height to determine where the
 	// transaction was included in.
-	for height := currentHeight; height >= heightHint && height > 0; height-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-b.quit:
-			return nil, chainntnfs.TxNotFoundManually,
-				chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
-
-		blockHash, err := b.chainConn.GetBlockHash(int64(height))
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get hash from block "+
-					"with height %d", height)
-		}
+	var confDetails *chainntnfs.TxConfirmation
+	err := chainntnfs.ScanBlocksDescending(
+		currentHeight, heightHint, b.blockDownloadConcurrency,
+		b.fetchBlockByHeight, func(height uint32,
+			blockHash *chainhash.Hash, block *wire.MsgBlock) (bool,
+			error) {
+
+			// For every transaction in the block, check which one
+			// matches our request. If we find one that does, we
+			// can dispatch its confirmation details.
+			for txIndex, tx := range block.Transactions {
+				if !confRequest.MatchesTx(tx) {
+					continue
+				}
 
-		block, err := b.GetBlock(blockHash)
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get block with hash "+
-					"%v: %v", blockHash, err)
-		}
+				confDetails = &chainntnfs.TxConfirmation{
+					Tx:          tx.Copy(),
+					BlockHash:   blockHash,
+					BlockHeight: height,
+					TxIndex:     uint32(txIndex),
+					Block:       block,
+				}
 
-		// For every transaction in the block, check which one matches
-		// our request. If we find one that does, we can dispatch its
-		// confirmation details.
-		for txIndex, tx := range block.Transactions {
-			if !confRequest.MatchesTx(tx) {
-				continue
+				return true, nil
 			}
 
-			return &chainntnfs.TxConfirmation{
-				Tx:          tx.Copy(),
-				BlockHash:   blockHash,
-				BlockHeight: height,
-				TxIndex:     uint32(txIndex),
-				Block:       block,
-			}, chainntnfs.TxFoundManually, nil
-		}
+			return false, nil
+		}, b.quit,
+	)
+	if err != nil {
+		return nil, chainntnfs.TxNotFoundManually, err
+	}
+
+	if confDetails != nil {
+		return confDetails, chainntnfs.TxFoundManually, nil
 	}
 
 	// If we reach here, then we were not able to find the transaction
@@ -628,6 +649,25 @@ func (b *BitcoindNotifier) confDetailsManually(confRequest chainntnfs.ConfReques
 	return nil, chainntnfs.TxNotFoundManually, nil
 }
 
+// fetchBlockByHeight fetches the block at the given height of the main chain.
+func (b *BitcoindNotifier) fetchBlockByHeight(height uint32) (*chainhash.Hash,
+	*wire.MsgBlock, error) {
+
+	blockHash, err := b.chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get hash from block "+
+			"with height %d: %w", height, err)
+	}
+
+	block, err := b.GetBlock(blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block with hash "+
+			"%v: %w", blockHash, err)
+	}
+
+	return blockHash, block, nil
+}
+
 // handleBlockConnected applies a chain update for a new block. Any watched
 // transactions included this block will processed to either send notifications
 // now or after numConfirmations confs.
@@ -843,53 +883,51 @@ func (b *BitcoindNotifier) historicalSpendDetails(
 
 	// Begin scanning blocks at every height to determine if the outpoint
 	// was spent.
-	for height := endHeight; height >= startHeight && height > 0; height-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-b.quit:
-			return nil, chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
+	var spendDetails *chainntnfs.SpendDetail
+	err := chainntnfs.ScanBlocksDescending(
+		endHeight, startHeight, b.blockDownloadConcurrency,
+		b.fetchBlockByHeight, func(height uint32, _ *chainhash.Hash,
+			block *wire.MsgBlock) (bool, error) {
+
+			// We'll manually go over every input in every
+			// transaction in the block and determine whether it
+			// spends the request in question. If we find one,
+			// we'll dispatch the spend details.
+			for _, tx := range block.Transactions {
+				matches, inputIdx, err := spendRequest.MatchesTx(
+					tx,
+				)
+				if err != nil {
+					return false, err
+				}
+				if !matches {
+					continue
+				}
 
-		// First, we'll fetch the block for the current height.
-		blockHash, err := b.chainConn.GetBlockHash(int64(height))
-		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve hash for "+
-				"block with height %d: %v", height, err)
-		}
-		block, err := b.GetBlock(blockHash)
-		if err != nil {
-			return nil, fmt.Errorf("unable to retrieve block "+
-				"with hash %v: %v", blockHash, err)
-		}
+				txCopy := tx.Copy()
+				txHash := txCopy.TxHash()
+				spendOutPoint :=
+					&txCopy.TxIn[inputIdx].PreviousOutPoint
+
+				spendDetails = &chainntnfs.SpendDetail{
+					SpentOutPoint:     spendOutPoint,
+					SpenderTxHash:     &txHash,
+					SpendingTx:        txCopy,
+					SpenderInputIndex: inputIdx,
+					SpendingHeight:    int32(height),
+				}
 
-		// Then, we'll manually go over every input in every transaction
-		// in it and determine whether it spends the request in
-		// question. If we find one, we'll dispatch the spend details.
-		for _, tx := range block.Transactions {
-			matches, inputIdx, err := spendRequest.MatchesTx(tx)
-			if err != nil {
-				return nil, err
-			}
-			if !matches {
-				continue
+				return true, nil
 			}
 
-			txCopy := tx.Copy()
-			txHash := txCopy.TxHash()
-			spendOutPoint := &txCopy.TxIn[inputIdx].PreviousOutPoint
-			return &chainntnfs.SpendDetail{
-				SpentOutPoint:     spendOutPoint,
-				SpenderTxHash:     &txHash,
-				SpendingTx:        txCopy,
-				SpenderInputIndex: inputIdx,
-				SpendingHeight:    int32(height),
-			}, nil
-		}
+			return false, nil
+		}, b.quit,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	return spendDetails, nil
 }
 
 // RegisterConfirmationsNtfn registers an intent to be notified once the target
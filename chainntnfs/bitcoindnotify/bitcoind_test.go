@@ -64,6 +64,8 @@ func setUpNotifier(t *testing.T, bitcoindConn *chain.BitcoindConn,
 	notifier := New(
 		bitcoindConn, unittest.NetParams, spendHintCache,
 		confirmHintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+		chainntnfs.DefaultBlockDownloadConcurrency,
 	)
 	if err := notifier.Start(); err != nil {
 		t.Fatalf("unable to start notifier: %v", err)
@@ -49,7 +49,9 @@ func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
 	}
 
 	return New(chainConn, chainParams, spendHintCache,
-		confirmHintCache, blockCache), nil
+		confirmHintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+		chainntnfs.DefaultBlockDownloadConcurrency), nil
 }
 
 // init registers a driver for the BtcdNotifier concrete implementation of the
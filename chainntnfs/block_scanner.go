@@ -0,0 +1,106 @@
+package chainntnfs
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// DefaultBlockDownloadConcurrency is the default number of blocks a
+	// chain notifier fetches in parallel while scanning the chain.
+	DefaultBlockDownloadConcurrency = 1
+
+	// MaxBlockDownloadConcurrency is the maximum number of blocks a chain
+	// notifier may fetch in parallel while scanning the chain. Allowing
+	// more could overwhelm the chain backend and hold too many blocks in
+	// memory.
+	MaxBlockDownloadConcurrency = 16
+)
+
+// BlockFetcher fetches the block at the given height of the main chain.
+type BlockFetcher func(height uint32) (*chainhash.Hash, *wire.MsgBlock, error)
+
+// BlockProcessor processes a block fetched during a chain scan. Returning true
+// stops the scan.
+type BlockProcessor func(height uint32, hash *chainhash.Hash,
+	block *wire.MsgBlock) (bool, error)
+
+// scannedBlock is the result of fetching a block during a chain scan.
+type scannedBlock struct {
+	hash  *chainhash.Hash
+	block *wire.MsgBlock
+	err   error
+}
+
+// ScanBlocksDescending scans the chain from fromHeight down to toHeight, both
+// inclusive, skipping the genesis block. Up to concurrency blocks are fetched
+// in parallel, but the blocks are always processed one after another in
+// descending height order. The scan stops at the first error, once a block
+// processor returns true or once the quit channel is closed.
+func ScanBlocksDescending(fromHeight, toHeight, concurrency uint32,
+	fetch BlockFetcher, process BlockProcessor,
+	quit <-chan struct{}) error {
+
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	// The genesis block can't be spent from, so there's nothing to find
+	// in it.
+	lastHeight := int64(toHeight)
+	if lastHeight < 1 {
+		lastHeight = 1
+	}
+
+	// pending holds the results of the blocks that are being fetched, in
+	// the order in which they need to be processed.
+	var pending []chan scannedBlock
+	nextHeight := int64(fromHeight)
+
+	for height := int64(fromHeight); height >= lastHeight; height-- {
+		// Keep up to concurrency blocks in flight. As each fetch writes
+		// into its own buffered channel, fetches that are still in
+		// flight once we return don't block.
+		for nextHeight >= lastHeight &&
+			height-nextHeight < int64(concurrency) {
+
+			result := make(chan scannedBlock, 1)
+			go func(height uint32) {
+				hash, block, err := fetch(height)
+				result <- scannedBlock{
+					hash:  hash,
+					block: block,
+					err:   err,
+				}
+			}(uint32(nextHeight))
+
+			pending = append(pending, result)
+			nextHeight--
+		}
+
+		// Ensure we haven't been requested to shut down before
+		// processing the next height.
+		var scanned scannedBlock
+		select {
+		case scanned = <-pending[0]:
+			pending = pending[1:]
+
+		case <-quit:
+			return ErrChainNotifierShuttingDown
+		}
+
+		if scanned.err != nil {
+			return scanned.err
+		}
+
+		done, err := process(uint32(height), scanned.hash, scanned.block)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+
+	return nil
+}
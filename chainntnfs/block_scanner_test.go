@@ -0,0 +1,148 @@
+package chainntnfs
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScanBlocksDescending asserts that blocks fetched in parallel are still
+// processed in descending height order and that no more blocks than allowed
+// are fetched at once.
+func TestScanBlocksDescending(t *testing.T) {
+	t.Parallel()
+
+	const concurrency = 4
+
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+
+	// Blocks at lower heights are fetched faster, so they complete out of
+	// order.
+	fetch := func(height uint32) (*chainhash.Hash, *wire.MsgBlock,
+		error) {
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest ||
+				maxInFlight.CompareAndSwap(highest, current) {
+
+				break
+			}
+		}
+
+		time.Sleep(time.Duration(height%5) * time.Millisecond)
+
+		return &chainhash.Hash{byte(height)}, &wire.MsgBlock{}, nil
+	}
+
+	var processed []uint32
+	process := func(height uint32, hash *chainhash.Hash,
+		_ *wire.MsgBlock) (bool, error) {
+
+		require.Equal(t, &chainhash.Hash{byte(height)}, hash)
+		processed = append(processed, height)
+
+		return false, nil
+	}
+
+	quit := make(chan struct{})
+	err := ScanBlocksDescending(20, 0, concurrency, fetch, process, quit)
+	require.NoError(t, err)
+
+	// The genesis block is never scanned.
+	expected := make([]uint32, 0, 20)
+	for height := uint32(20); height >= 1; height-- {
+		expected = append(expected, height)
+	}
+	require.Equal(t, expected, processed)
+	require.LessOrEqual(t, maxInFlight.Load(), int32(concurrency))
+
+	// An empty range doesn't fetch anything.
+	err = ScanBlocksDescending(5, 10, concurrency, fetch,
+		func(uint32, *chainhash.Hash, *wire.MsgBlock) (bool, error) {
+			t.Fatal("unexpected block")
+			return false, nil
+		}, quit,
+	)
+	require.NoError(t, err)
+}
+
+// TestScanBlocksDescendingStop asserts that a scan stops once a block was
+// found, a fetch failed or the quit channel was closed.
+func TestScanBlocksDescendingStop(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(height uint32) (*chainhash.Hash, *wire.MsgBlock,
+		error) {
+
+		return &chainhash.Hash{}, &wire.MsgBlock{}, nil
+	}
+	quit := make(chan struct{})
+
+	// The scan stops at the block the processor was looking for.
+	var lastHeight uint32
+	err := ScanBlocksDescending(100, 1, 8, fetch,
+		func(height uint32, _ *chainhash.Hash, _ *wire.MsgBlock) (bool,
+			error) {
+
+			lastHeight = height
+			return height == 90, nil
+		}, quit,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 90, lastHeight)
+
+	// A failed fetch aborts the scan after the blocks above it were
+	// processed.
+	errFetch := errors.New("fetch failed")
+	failingFetch := func(height uint32) (*chainhash.Hash, *wire.MsgBlock,
+		error) {
+
+		if height == 50 {
+			return nil, nil, errFetch
+		}
+
+		return fetch(height)
+	}
+	lastHeight = 0
+	err = ScanBlocksDescending(100, 1, 8, failingFetch,
+		func(height uint32, _ *chainhash.Hash, _ *wire.MsgBlock) (bool,
+			error) {
+
+			lastHeight = height
+			return false, nil
+		}, quit,
+	)
+	require.ErrorIs(t, err, errFetch)
+	require.EqualValues(t, 51, lastHeight)
+
+	// A scan waiting for a block returns once we shut down.
+	var once sync.Once
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	blockingFetch := func(height uint32) (*chainhash.Hash, *wire.MsgBlock,
+		error) {
+
+		once.Do(func() { close(quit) })
+		<-release
+
+		return fetch(height)
+	}
+	err = ScanBlocksDescending(100, 1, 8, blockingFetch,
+		func(uint32, *chainhash.Hash, *wire.MsgBlock) (bool, error) {
+			return false, nil
+		}, quit,
+	)
+	require.ErrorIs(t, err, ErrChainNotifierShuttingDown)
+}
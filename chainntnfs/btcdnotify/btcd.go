@@ -91,6 +91,14 @@ type BtcdNotifier struct {
 	// memNotifier notifies clients of events related to the mempool.
 	memNotifier *chainntnfs.MempoolNotifier
 
+	// rescanLimiter limits the number of historical rescans that are
+	// performed concurrently.
+	rescanLimiter *chainntnfs.RescanLimiter
+
+	// blockDownloadConcurrency is the number of blocks that are fetched
+	// in parallel while scanning the chain.
+	blockDownloadConcurrency uint32
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -107,7 +115,9 @@ var _ chainntnfs.MempoolWatcher = (*BtcdNotifier)(nil)
 func New(config *rpcclient.ConnConfig, chainParams *chaincfg.Params,
 	spendHintCache chainntnfs.SpendHintCache,
 	confirmHintCache chainntnfs.ConfirmHintCache,
-	blockCache *blockcache.BlockCache) (*BtcdNotifier, error) {
+	blockCache *blockcache.BlockCache,
+	rescanConcurrency, blockDownloadConcurrency uint32) (*BtcdNotifier,
+	error) {
 
 	notifier := &BtcdNotifier{
 		chainParams: chainParams,
@@ -123,8 +133,11 @@ func New(config *rpcclient.ConnConfig, chainParams *chaincfg.Params,
 		spendHintCache:   spendHintCache,
 		confirmHintCache: confirmHintCache,
 
-		blockCache:  blockCache,
-		memNotifier: chainntnfs.NewMempoolNotifier(),
+		blockCache:    blockCache,
+		memNotifier:   chainntnfs.NewMempoolNotifier(),
+		rescanLimiter: chainntnfs.NewRescanLimiter(rescanConcurrency),
+
+		blockDownloadConcurrency: blockDownloadConcurrency,
 
 		quit: make(chan struct{}),
 	}
@@ -375,6 +388,13 @@ out:
 				go func(msg *chainntnfs.HistoricalConfDispatch) {
 					defer b.wg.Done()
 
+					// Wait until we're allowed to start
+					// another rescan.
+					if !b.rescanLimiter.Acquire(b.quit) {
+						return
+					}
+					defer b.rescanLimiter.Release()
+
 					confDetails, _, err := b.historicalConfDetails(
 						msg.ConfRequest,
 						msg.StartHeight, msg.EndHeight,
@@ -644,47 +664,41 @@ func (b *BtcdNotifier) confDetailsManually(confRequest chainntnfs.ConfRequest,
 
 	// Begin scanning blocks at every height to determine where the
 	// transaction was included in.
-	for height := endHeight; height >= startHeight && height > 0; height-- {
-		// Ensure we haven't been requested to shut down before
-		// processing the next height.
-		select {
-		case <-b.quit:
-			return nil, chainntnfs.TxNotFoundManually,
-				chainntnfs.ErrChainNotifierShuttingDown
-		default:
-		}
-
-		blockHash, err := b.chainConn.GetBlockHash(int64(height))
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get hash from block "+
-					"with height %d", height)
-		}
+	var confDetails *chainntnfs.TxConfirmation
+	err := chainntnfs.ScanBlocksDescending(
+		endHeight, startHeight, b.blockDownloadConcurrency,
+		b.fetchBlockByHeight, func(height uint32,
+			blockHash *chainhash.Hash, block *wire.MsgBlock) (bool,
+			error) {
+
+			// For every transaction in the block, check which one
+			// matches our request. If we find one that does, we
+			// can dispatch its confirmation details.
+			for txIndex, tx := range block.Transactions {
+				if !confRequest.MatchesTx(tx) {
+					continue
+				}
 
-		// TODO: fetch the neutrino filters instead.
-		block, err := b.GetBlock(blockHash)
-		if err != nil {
-			return nil, chainntnfs.TxNotFoundManually,
-				fmt.Errorf("unable to get block with hash "+
-					"%v: %v", blockHash, err)
-		}
+				confDetails = &chainntnfs.TxConfirmation{
+					Tx:          tx.Copy(),
+					BlockHash:   blockHash,
+					BlockHeight: height,
+					TxIndex:     uint32(txIndex),
+					Block:       block,
+				}
 
-		// For every transaction in the block, check which one matches
-		// our request. If we find one that does, we can dispatch its
-		// confirmation details.
-		for txIndex, tx := range block.Transactions {
-			if !confRequest.MatchesTx(tx) {
-				continue
+				return true, nil
 			}
 
-			return &chainntnfs.TxConfirmation{
-				Tx:          tx.Copy(),
-				BlockHash:   blockHash,
-				BlockHeight: height,
-				TxIndex:     uint32(txIndex),
-				Block:       block,
-			}, chainntnfs.TxFoundManually, nil
-		}
+			return false, nil
+		}, b.quit,
+	)
+	if err != nil {
+		return nil, chainntnfs.TxNotFoundManually, err
+	}
+
+	if confDetails != nil {
+		return confDetails, chainntnfs.TxFoundManually, nil
 	}
 
 	// If we reach here, then we were not able to find the transaction
@@ -692,6 +706,26 @@ func (b *BtcdNotifier) confDetailsManually(confRequest chainntnfs.ConfRequest,
 	return nil, chainntnfs.TxNotFoundManually, nil
 }
 
+// fetchBlockByHeight fetches the block at the given height of the main chain.
+func (b *BtcdNotifier) fetchBlockByHeight(height uint32) (*chainhash.Hash,
+	*wire.MsgBlock, error) {
+
+	blockHash, err := b.chainConn.GetBlockHash(int64(height))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get hash from block "+
+			"with height %d: %w", height, err)
+	}
+
+	// TODO: fetch the neutrino filters instead.
+	block, err := b.GetBlock(blockHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block with hash "+
+			"%v: %w", blockHash, err)
+	}
+
+	return blockHash, block, nil
+}
+
 // handleBlockConnected applies a chain update for a new block. Any watched
 // transactions included this block will processed to either send notifications
 // now or after numConfirmations confs.
@@ -839,8 +873,16 @@ func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 				err)
 		}
 
-		asyncResult := b.chainConn.RescanAsync(startHash, addrs, nil)
 		go func() {
+			// Wait until we're allowed to start another rescan.
+			if !b.rescanLimiter.Acquire(b.quit) {
+				return
+			}
+			defer b.rescanLimiter.Release()
+
+			asyncResult := b.chainConn.RescanAsync(
+				startHash, addrs, nil,
+			)
 			if rescanErr := asyncResult.Receive(); rescanErr != nil {
 				chainntnfs.Log.Errorf("Rescan to determine "+
 					"the spend details of %v failed: %v",
@@ -941,13 +983,19 @@ func (b *BtcdNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 	// long rescan, we'll launch a new goroutine to handle the async result
 	// of the rescan. We purposefully prevent from adding this goroutine to
 	// the WaitGroup as we cannot wait for a quit signal due to the
-	// asyncResult channel not being exposed.
+	// asyncResult channel not being exposed. The rescan is only started
+	// once the rescan limiter allows it.
 	//
 	// TODO(wilmer): add retry logic if rescan fails?
-	asyncResult := b.chainConn.RescanAsync(
-		startHash, nil, []*wire.OutPoint{outpoint},
-	)
 	go func() {
+		if !b.rescanLimiter.Acquire(b.quit) {
+			return
+		}
+		defer b.rescanLimiter.Release()
+
+		asyncResult := b.chainConn.RescanAsync(
+			startHash, nil, []*wire.OutPoint{outpoint},
+		)
 		if rescanErr := asyncResult.Receive(); rescanErr != nil {
 			chainntnfs.Log.Errorf("Rescan to determine the spend "+
 				"details of %v failed: %v", outpoint, rescanErr)
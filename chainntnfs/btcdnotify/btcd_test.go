@@ -57,6 +57,8 @@ func setUpNotifier(t *testing.T, h *rpctest.Harness) *BtcdNotifier {
 	rpcCfg := h.RPCConfig()
 	notifier, err := New(
 		&rpcCfg, unittest.NetParams, hintCache, hintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+		chainntnfs.DefaultBlockDownloadConcurrency,
 	)
 	require.NoError(t, err, "unable to create notifier")
 	if err := notifier.Start(); err != nil {
@@ -50,6 +50,8 @@ func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
 
 	return New(
 		config, chainParams, spendHintCache, confirmHintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+		chainntnfs.DefaultBlockDownloadConcurrency,
 	)
 }
 
@@ -41,7 +41,10 @@ func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
 			"is incorrect, expected a *blockcache.BlockCache")
 	}
 
-	return New(config, spendHintCache, confirmHintCache, blockCache), nil
+	return New(
+		config, spendHintCache, confirmHintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+	), nil
 }
 
 // init registers a driver for the NeutrinoNotify concrete implementation of
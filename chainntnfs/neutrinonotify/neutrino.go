@@ -79,6 +79,10 @@ type NeutrinoNotifier struct {
 	// blockCache is an LRU block cache.
 	blockCache *blockcache.BlockCache
 
+	// rescanLimiter limits the number of historical rescans that are
+	// performed concurrently.
+	rescanLimiter *chainntnfs.RescanLimiter
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -93,7 +97,8 @@ var _ chainntnfs.ChainNotifier = (*NeutrinoNotifier)(nil)
 // being passed into this function.
 func New(node *neutrino.ChainService, spendHintCache chainntnfs.SpendHintCache,
 	confirmHintCache chainntnfs.ConfirmHintCache,
-	blockCache *blockcache.BlockCache) *NeutrinoNotifier {
+	blockCache *blockcache.BlockCache,
+	rescanConcurrency uint32) *NeutrinoNotifier {
 
 	return &NeutrinoNotifier{
 		notificationCancels:  make(chan interface{}),
@@ -113,7 +118,8 @@ func New(node *neutrino.ChainService, spendHintCache chainntnfs.SpendHintCache,
 		spendHintCache:   spendHintCache,
 		confirmHintCache: confirmHintCache,
 
-		blockCache: blockCache,
+		blockCache:    blockCache,
+		rescanLimiter: chainntnfs.NewRescanLimiter(rescanConcurrency),
 
 		quit: make(chan struct{}),
 	}
@@ -438,6 +444,13 @@ func (n *NeutrinoNotifier) notificationDispatcher() {
 				go func(msg *chainntnfs.HistoricalConfDispatch) {
 					defer n.wg.Done()
 
+					// Wait until we're allowed to start
+					// another rescan.
+					if !n.rescanLimiter.Acquire(n.quit) {
+						return
+					}
+					defer n.rescanLimiter.Release()
+
 					confDetails, err := n.historicalConfDetails(
 						msg.ConfRequest,
 						msg.StartHeight, msg.EndHeight,
@@ -846,6 +859,12 @@ func (n *NeutrinoNotifier) RegisterSpendNtfn(outpoint *wire.OutPoint,
 			}
 		}
 
+		// Wait until we're allowed to start another rescan.
+		if !n.rescanLimiter.Acquire(n.quit) {
+			return
+		}
+		defer n.rescanLimiter.Release()
+
 		spendReport, err := n.p2pNode.GetUtxo(
 			neutrino.WatchInputs(inputToWatch),
 			neutrino.StartBlock(&headerfs.BlockStamp{
@@ -0,0 +1,58 @@
+package chainntnfs
+
+const (
+	// DefaultRescanConcurrency is the default number of historical rescans
+	// a chain notifier performs concurrently. Zero doesn't limit rescans.
+	DefaultRescanConcurrency = 0
+
+	// MaxRescanConcurrency is the maximum number of historical rescans a
+	// chain notifier may perform concurrently. Allowing more could
+	// overwhelm the chain backend.
+	MaxRescanConcurrency = 64
+)
+
+// RescanLimiter limits the number of historical rescans that a chain notifier
+// performs concurrently.
+type RescanLimiter struct {
+	// slots holds one element for each running rescan. It is nil if
+	// rescans aren't limited.
+	slots chan struct{}
+}
+
+// NewRescanLimiter returns a new RescanLimiter that allows up to maxRescans
+// concurrent rescans. If maxRescans is zero, rescans aren't limited.
+func NewRescanLimiter(maxRescans uint32) *RescanLimiter {
+	r := &RescanLimiter{}
+	if maxRescans > 0 {
+		r.slots = make(chan struct{}, maxRescans)
+	}
+
+	return r
+}
+
+// Acquire blocks until a rescan is allowed to start. False is returned if the
+// quit channel was closed before that, in which case the rescan must not be
+// started and Release must not be called.
+func (r *RescanLimiter) Acquire(quit <-chan struct{}) bool {
+	if r.slots == nil {
+		return true
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+		return true
+
+	case <-quit:
+		return false
+	}
+}
+
+// Release marks a rescan that was started after a successful call to Acquire
+// as finished.
+func (r *RescanLimiter) Release() {
+	if r.slots == nil {
+		return
+	}
+
+	<-r.slots
+}
@@ -0,0 +1,63 @@
+package chainntnfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRescanLimiter asserts that the RescanLimiter only allows the configured
+// number of concurrent rescans.
+func TestRescanLimiter(t *testing.T) {
+	t.Parallel()
+
+	quit := make(chan struct{})
+
+	// A limiter without a limit never blocks.
+	unlimited := NewRescanLimiter(0)
+	for i := 0; i < 100; i++ {
+		require.True(t, unlimited.Acquire(quit))
+	}
+	for i := 0; i < 100; i++ {
+		unlimited.Release()
+	}
+
+	limiter := NewRescanLimiter(2)
+	require.True(t, limiter.Acquire(quit))
+	require.True(t, limiter.Acquire(quit))
+
+	// The third rescan must wait until one of the others finished.
+	acquired := make(chan bool)
+	go func() {
+		acquired <- limiter.Acquire(quit)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("rescan started while limit reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	limiter.Release()
+
+	select {
+	case ok := <-acquired:
+		require.True(t, ok)
+	case <-time.After(testTimeout):
+		t.Fatalf("rescan not started after release")
+	}
+
+	// Waiting rescans are aborted once the quit channel is closed.
+	go func() {
+		acquired <- limiter.Acquire(quit)
+	}()
+	close(quit)
+
+	select {
+	case ok := <-acquired:
+		require.False(t, ok)
+	case <-time.After(testTimeout):
+		t.Fatalf("rescan not aborted on quit")
+	}
+}
@@ -1936,6 +1936,8 @@ func TestInterfaces(t *testing.T, targetBackEnd string) {
 				return bitcoindnotify.New(
 					bitcoindConn, unittest.NetParams,
 					hintCache, hintCache, blockCache,
+					chainntnfs.DefaultRescanConcurrency,
+					chainntnfs.DefaultBlockDownloadConcurrency,
 				), nil
 			}
 
@@ -1948,6 +1950,8 @@ func TestInterfaces(t *testing.T, targetBackEnd string) {
 				return bitcoindnotify.New(
 					bitcoindConn, unittest.NetParams,
 					hintCache, hintCache, blockCache,
+					chainntnfs.DefaultRescanConcurrency,
+					chainntnfs.DefaultBlockDownloadConcurrency,
 				), nil
 			}
 
@@ -1957,6 +1961,8 @@ func TestInterfaces(t *testing.T, targetBackEnd string) {
 				return btcdnotify.New(
 					&rpcConfig, unittest.NetParams,
 					hintCache, hintCache, blockCache,
+					chainntnfs.DefaultRescanConcurrency,
+					chainntnfs.DefaultBlockDownloadConcurrency,
 				)
 			}
 
@@ -1969,6 +1975,7 @@ func TestInterfaces(t *testing.T, targetBackEnd string) {
 				return neutrinonotify.New(
 					spvNode, hintCache, hintCache,
 					blockCache,
+					chainntnfs.DefaultRescanConcurrency,
 				), nil
 			}
 		}
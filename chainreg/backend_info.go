@@ -0,0 +1,167 @@
+package chainreg
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightninglabs/neutrino"
+)
+
+// BackendInfo describes the state of the chain backend as seen at the time it
+// was queried.
+type BackendInfo struct {
+	// Backend is the type of the chain backend, e.g. bitcoind.
+	Backend string
+
+	// Connected is true if the chain backend could be queried
+	// successfully.
+	Connected bool
+
+	// ConnErr is the error that was returned when querying the chain
+	// backend. It is only set if Connected is false.
+	ConnErr error
+
+	// BestBlockHash is the hash of the best block of the chain backend.
+	BestBlockHash chainhash.Hash
+
+	// BestBlockHeight is the height of the best block of the chain
+	// backend.
+	BestBlockHeight int32
+
+	// TipHeight is the height of the best block the chain backend knows
+	// to exist on the network, which it might not have fully processed
+	// yet.
+	TipHeight int32
+
+	// MempoolSize is the number of transactions in the mempool of the
+	// chain backend. It is only populated for bitcoind.
+	MempoolSize int64
+}
+
+// BlocksBehind returns the number of blocks the chain backend lags behind the
+// best block known on the network.
+func (b *BackendInfo) BlocksBehind() uint32 {
+	if b.TipHeight <= b.BestBlockHeight {
+		return 0
+	}
+
+	return uint32(b.TipHeight - b.BestBlockHeight)
+}
+
+// newBackendInfoFunc returns a function that queries the chain backend of the
+// given type with the passed query function and reports whether doing so
+// succeeded.
+func newBackendInfoFunc(backend string,
+	query func(*BackendInfo) error) func() *BackendInfo {
+
+	return func() *BackendInfo {
+		info := &BackendInfo{
+			Backend: backend,
+		}
+
+		if err := query(info); err != nil {
+			log.Debugf("Unable to query %v chain backend: %v",
+				backend, err)
+
+			return &BackendInfo{
+				Backend: backend,
+				ConnErr: err,
+			}
+		}
+		info.Connected = true
+
+		return info
+	}
+}
+
+// queryRPCBackendInfo queries a btcd or bitcoind chain backend over RPC. The
+// mempool size is only queried if queryMempool is true.
+func queryRPCBackendInfo(client *rpcclient.Client, queryMempool bool,
+	info *BackendInfo) error {
+
+	// We use a raw request as we're only interested in a few fields that
+	// btcd and all supported bitcoind versions have in common.
+	resp, err := client.RawRequest("getblockchaininfo", nil)
+	if err != nil {
+		return err
+	}
+
+	var chainInfo btcjson.GetBlockChainInfoResult
+	if err := json.Unmarshal(resp, &chainInfo); err != nil {
+		return err
+	}
+
+	bestHash, err := chainhash.NewHashFromStr(chainInfo.BestBlockHash)
+	if err != nil {
+		return err
+	}
+
+	info.BestBlockHash = *bestHash
+	info.BestBlockHeight = chainInfo.Blocks
+	info.TipHeight = chainInfo.Headers
+
+	if !queryMempool {
+		return nil
+	}
+
+	resp, err = client.RawRequest("getmempoolinfo", nil)
+	if err != nil {
+		return err
+	}
+
+	var mempoolInfo btcjson.GetMempoolInfoResult
+	if err := json.Unmarshal(resp, &mempoolInfo); err != nil {
+		return err
+	}
+	info.MempoolSize = mempoolInfo.Size
+
+	return nil
+}
+
+// queryNeutrinoBackendInfo queries the neutrino light client. As neutrino
+// doesn't have a separate notion of headers and blocks, the network tip is
+// derived from the best height announced by our peers.
+func queryNeutrinoBackendInfo(cs *neutrino.ChainService,
+	info *BackendInfo) error {
+
+	bestBlock, err := cs.BestBlock()
+	if err != nil {
+		return err
+	}
+
+	info.BestBlockHash = bestBlock.Hash
+	info.BestBlockHeight = bestBlock.Height
+	info.TipHeight = bestBlock.Height
+
+	peers := cs.Peers()
+	if len(peers) == 0 {
+		return errors.New("neutrino has no connected peers")
+	}
+
+	for _, peer := range peers {
+		if peer.LastBlock() > info.TipHeight {
+			info.TipHeight = peer.LastBlock()
+		}
+	}
+
+	return nil
+}
+
+// queryChainSourceInfo queries the best block of the given chain source. It is
+// used for backends that don't expose any further information.
+func queryChainSourceInfo(source chain.Interface, info *BackendInfo) error {
+	bestHash, bestHeight, err := source.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	info.BestBlockHash = *bestHash
+	info.BestBlockHeight = bestHeight
+	info.TipHeight = bestHeight
+
+	return nil
+}
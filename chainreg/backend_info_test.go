@@ -0,0 +1,52 @@
+package chainreg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackendInfoFunc checks that the backend info reports the connection
+// status of the chain backend and how far it lags behind the network.
+func TestBackendInfoFunc(t *testing.T) {
+	t.Parallel()
+
+	// A successful query reports the backend as connected.
+	backendInfo := newBackendInfoFunc(
+		"bitcoind", func(info *BackendInfo) error {
+			info.BestBlockHeight = 100
+			info.TipHeight = 105
+			info.MempoolSize = 42
+
+			return nil
+		},
+	)
+
+	info := backendInfo()
+	require.Equal(t, "bitcoind", info.Backend)
+	require.True(t, info.Connected)
+	require.NoError(t, info.ConnErr)
+	require.EqualValues(t, 5, info.BlocksBehind())
+	require.EqualValues(t, 42, info.MempoolSize)
+
+	// A backend that's ahead of the tip it knows about isn't behind.
+	info.TipHeight = 99
+	require.Zero(t, info.BlocksBehind())
+
+	// A failed query reports the error and discards any partial results.
+	errQuery := errors.New("connection refused")
+	backendInfo = newBackendInfoFunc(
+		"btcd", func(info *BackendInfo) error {
+			info.BestBlockHeight = 100
+
+			return errQuery
+		},
+	)
+
+	info = backendInfo()
+	require.Equal(t, "btcd", info.Backend)
+	require.False(t, info.Connected)
+	require.ErrorIs(t, info.ConnErr, errQuery)
+	require.Zero(t, info.BestBlockHeight)
+}
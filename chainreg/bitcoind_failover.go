@@ -0,0 +1,670 @@
+package chainreg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// failoverProbeTimeout is the time we give a bitcoind node to answer
+	// the probe that decides whether we can fail over to it.
+	failoverProbeTimeout = 10 * time.Second
+
+	// failoverDialTimeout is the timeout for establishing a connection to
+	// the RPC server of a bitcoind node.
+	failoverDialTimeout = 10 * time.Second
+
+	// failoverMaxTipLag is the number of blocks a bitcoind node may lag
+	// behind the best known tip while still being considered synced. This
+	// allows for a block that didn't propagate to all nodes yet.
+	failoverMaxTipLag = 1
+)
+
+// BitcoindEndpoint is the RPC endpoint of a bitcoind node.
+type BitcoindEndpoint struct {
+	// Host is the address of the RPC server of the node, including the
+	// port.
+	Host string
+
+	// User is the username used to authenticate to the RPC server.
+	User string
+
+	// Pass is the password used to authenticate to the RPC server.
+	Pass string
+
+	// CookiePath is the path of the cookie file of the node. If set, the
+	// credentials are re-read from it periodically, as bitcoind creates a
+	// new cookie on every restart.
+	CookiePath string
+}
+
+// BackendFailoverEvent is sent to subscribers whenever lnd fails over from one
+// bitcoind node to another.
+type BackendFailoverEvent struct {
+	// PreviousHost is the RPC host of the node that was used before.
+	PreviousHost string
+
+	// ActiveHost is the RPC host of the node that is used now.
+	ActiveHost string
+
+	// Reason is the error that caused the failover.
+	Reason error
+
+	// Timestamp is the time of the failover.
+	Timestamp time.Time
+}
+
+// BitcoindFailoverConfig houses the parameters of the BitcoindFailover.
+type BitcoindFailoverConfig struct {
+	// Endpoints are the bitcoind nodes in the order of preference. The
+	// first one is the primary node, the others are fallbacks.
+	Endpoints []BitcoindEndpoint
+
+	// ChainParams are the parameters of the chain all nodes must be on.
+	ChainParams *chaincfg.Params
+
+	// FailoverAfter is the number of consecutive failed health checks
+	// after which we fail over to the next node.
+	FailoverAfter int
+
+	// CookieRefreshInterval is the interval at which the credentials of
+	// the nodes with a cookie file are re-read. A value of zero disables
+	// the refresh.
+	CookieRefreshInterval time.Duration
+}
+
+// BitcoindFailover lets lnd switch between several bitcoind nodes, or swap the
+// credentials of a node, without a restart. The connections to bitcoind are
+// tied to a single host and credentials once they're created, so instead of
+// connecting to a node directly, all RPC clients of lnd connect to a local
+// JSON-RPC proxy. The proxy forwards every request to the active node, which
+// is replaced by the next healthy node once the chain health check fails
+// consistently.
+//
+// NOTE: ZMQ notifications can't be proxied as they are streamed from a single
+// node, so the failover requires bitcoind to be polled over RPC.
+type BitcoindFailover struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *BitcoindFailoverConfig
+
+	// user and pass authenticate the clients of lnd to the proxy, so that
+	// other local processes can't use it to access bitcoind.
+	user string
+	pass string
+
+	listener   net.Listener
+	httpServer *http.Server
+	httpClient *http.Client
+
+	ntfnServer *subscribe.Server
+
+	// refreshCookies is signaled when a node rejected our credentials, so
+	// that its cookie is re-read right away.
+	refreshCookies chan struct{}
+
+	// failoverMtx serializes failovers.
+	failoverMtx sync.Mutex
+
+	// mtx guards the fields below.
+	mtx       sync.RWMutex
+	endpoints []BitcoindEndpoint
+	active    int
+	failures  int
+
+	// tipHeight is the best header height any of the nodes reported so
+	// far. We only fail over to nodes that are synced up to it.
+	tipHeight int64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBitcoindFailover creates a new BitcoindFailover with the given config.
+func NewBitcoindFailover(cfg *BitcoindFailoverConfig) (*BitcoindFailover,
+	error) {
+
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("no bitcoind endpoints configured")
+	}
+
+	if cfg.FailoverAfter <= 0 {
+		return nil, fmt.Errorf("failover threshold must be positive, "+
+			"got %v", cfg.FailoverAfter)
+	}
+
+	user, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	pass, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout: failoverDialTimeout,
+	}).DialContext
+
+	return &BitcoindFailover{
+		cfg:            cfg,
+		user:           user,
+		pass:           pass,
+		httpClient:     &http.Client{Transport: transport},
+		ntfnServer:     subscribe.NewServer(),
+		refreshCookies: make(chan struct{}, 1),
+		endpoints: append(
+			[]BitcoindEndpoint(nil), cfg.Endpoints...,
+		),
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start starts the local proxy and selects the first healthy node as the
+// active one. If no node is healthy, the primary node stays active.
+func (f *BitcoindFailover) Start() error {
+	var startErr error
+	f.started.Do(func() {
+		if err := f.ntfnServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			startErr = fmt.Errorf("unable to start bitcoind failover "+
+				"proxy: %w", err)
+			return
+		}
+		f.listener = listener
+
+		proxy := &httputil.ReverseProxy{
+			Director:       f.direct,
+			ModifyResponse: f.checkResponse,
+			ErrorHandler:   f.handleProxyError,
+			Transport:      f.httpClient.Transport,
+		}
+		f.httpServer = &http.Server{
+			Handler:           f.authenticate(proxy),
+			ReadHeaderTimeout: failoverDialTimeout,
+		}
+
+		go func() {
+			err := f.httpServer.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Bitcoind failover proxy stopped: "+
+					"%v", err)
+			}
+		}()
+
+		if f.cfg.CookieRefreshInterval > 0 {
+			f.wg.Add(1)
+			go f.cookieRefresher()
+		}
+
+		endpoints := f.snapshotEndpoints()
+		candidates := make([]int, len(endpoints))
+		for i := range endpoints {
+			candidates[i] = i
+		}
+
+		active, err := f.selectEndpoint(endpoints, candidates, nil)
+		if err == nil {
+			f.mtx.Lock()
+			f.active = active
+			f.mtx.Unlock()
+		}
+
+		log.Infof("Bitcoind failover proxy listening on %v, active "+
+			"node %v", listener.Addr(), f.ActiveHost())
+	})
+
+	return startErr
+}
+
+// Stop stops the local proxy and cancels all subscriptions.
+func (f *BitcoindFailover) Stop() error {
+	var stopErr error
+	f.stopped.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+
+		if f.httpServer != nil {
+			stopErr = f.httpServer.Close()
+		}
+
+		if err := f.ntfnServer.Stop(); err != nil && stopErr == nil {
+			stopErr = err
+		}
+	})
+
+	return stopErr
+}
+
+// Host returns the address of the local proxy that RPC clients should connect
+// to instead of bitcoind.
+func (f *BitcoindFailover) Host() string {
+	return f.listener.Addr().String()
+}
+
+// User returns the username RPC clients must use to authenticate to the
+// proxy.
+func (f *BitcoindFailover) User() string {
+	return f.user
+}
+
+// Pass returns the password RPC clients must use to authenticate to the
+// proxy.
+func (f *BitcoindFailover) Pass() string {
+	return f.pass
+}
+
+// ActiveHost returns the RPC host of the bitcoind node requests are currently
+// forwarded to.
+func (f *BitcoindFailover) ActiveHost() string {
+	return f.activeEndpoint().Host
+}
+
+// HasFallback returns true if there is a node to fail over to.
+func (f *BitcoindFailover) HasFallback() bool {
+	return len(f.cfg.Endpoints) > 1
+}
+
+// SubscribeFailovers returns a client that receives a BackendFailoverEvent
+// whenever lnd fails over to another bitcoind node.
+func (f *BitcoindFailover) SubscribeFailovers() (*subscribe.Client, error) {
+	return f.ntfnServer.Subscribe()
+}
+
+// WrapHealthCheck wraps the given health check of the chain backend. Once the
+// check failed the configured number of times in a row, we fail over to the
+// next healthy node and check it instead.
+func (f *BitcoindFailover) WrapHealthCheck(check func() error) func() error {
+	return func() error {
+		err := check()
+
+		f.mtx.Lock()
+		if err == nil {
+			f.failures = 0
+			f.mtx.Unlock()
+
+			return nil
+		}
+		f.failures++
+		failures := f.failures
+		f.mtx.Unlock()
+
+		if failures < f.cfg.FailoverAfter {
+			return err
+		}
+
+		if failoverErr := f.failover(err); failoverErr != nil {
+			log.Errorf("Unable to fail over to another bitcoind "+
+				"node: %v", failoverErr)
+
+			return err
+		}
+
+		return check()
+	}
+}
+
+// failover switches to the next healthy node in the order of preference,
+// wrapping around to the primary node after the last fallback.
+func (f *BitcoindFailover) failover(reason error) error {
+	f.failoverMtx.Lock()
+	defer f.failoverMtx.Unlock()
+
+	f.mtx.RLock()
+	current := f.active
+	f.mtx.RUnlock()
+
+	endpoints := f.snapshotEndpoints()
+	numEndpoints := len(endpoints)
+	candidates := make([]int, 0, numEndpoints-1)
+	for i := 1; i < numEndpoints; i++ {
+		candidates = append(candidates, (current+i)%numEndpoints)
+	}
+
+	// The failing node might still know about a newer tip, so we ask it
+	// as well, but never select it again.
+	var tipInfo *bitcoindChainInfo
+	if info, err := f.probe(endpoints[current]); err == nil {
+		tipInfo = info
+	}
+
+	next, err := f.selectEndpoint(endpoints, candidates, tipInfo)
+	if err != nil {
+		return fmt.Errorf("no healthy fallback for bitcoind node "+
+			"%v: %w", endpoints[current].Host, err)
+	}
+
+	f.mtx.Lock()
+	f.active = next
+	f.failures = 0
+	f.mtx.Unlock()
+
+	event := &BackendFailoverEvent{
+		PreviousHost: endpoints[current].Host,
+		ActiveHost:   endpoints[next].Host,
+		Reason:       reason,
+		Timestamp:    time.Now(),
+	}
+
+	log.Warnf("Chain backend failover: bitcoind node %v failed %d "+
+		"consecutive health checks (%v), switched to %v",
+		event.PreviousHost, f.cfg.FailoverAfter, reason,
+		event.ActiveHost)
+
+	if err := f.ntfnServer.SendUpdate(event); err != nil {
+		log.Errorf("Unable to notify about chain backend failover: %v",
+			err)
+	}
+
+	return nil
+}
+
+// selectEndpoint probes the candidate nodes, given as indexes into the
+// endpoints in the order of preference, and returns the first one that is
+// healthy and synced up to the best known tip. The tip is the best header
+// height reported by any node so far, including the candidates and the
+// optional extra chain info.
+func (f *BitcoindFailover) selectEndpoint(endpoints []BitcoindEndpoint,
+	candidates []int, extra *bitcoindChainInfo) (int, error) {
+
+	f.mtx.RLock()
+	tipHeight := f.tipHeight
+	f.mtx.RUnlock()
+
+	if extra != nil && extra.Headers > tipHeight {
+		tipHeight = extra.Headers
+	}
+
+	infos := make(map[int]*bitcoindChainInfo, len(candidates))
+	for _, i := range candidates {
+		info, err := f.probe(endpoints[i])
+		if err != nil {
+			log.Warnf("Bitcoind node %v is unavailable: %v",
+				endpoints[i].Host, err)
+
+			continue
+		}
+
+		infos[i] = info
+		if info.Headers > tipHeight {
+			tipHeight = info.Headers
+		}
+	}
+
+	f.mtx.Lock()
+	if tipHeight > f.tipHeight {
+		f.tipHeight = tipHeight
+	}
+	f.mtx.Unlock()
+
+	for _, i := range candidates {
+		info, ok := infos[i]
+		if !ok {
+			continue
+		}
+
+		if info.Blocks+failoverMaxTipLag < tipHeight {
+			log.Warnf("Bitcoind node %v is behind the chain tip, "+
+				"at block %d of %d", endpoints[i].Host,
+				info.Blocks, tipHeight)
+
+			continue
+		}
+
+		return i, nil
+	}
+
+	return 0, errors.New("no node is healthy and synced")
+}
+
+// activeEndpoint returns the endpoint requests are currently forwarded to.
+func (f *BitcoindFailover) activeEndpoint() BitcoindEndpoint {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return f.endpoints[f.active]
+}
+
+// snapshotEndpoints returns a copy of all endpoints with their current
+// credentials.
+func (f *BitcoindFailover) snapshotEndpoints() []BitcoindEndpoint {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	return append([]BitcoindEndpoint(nil), f.endpoints...)
+}
+
+// cookieRefresher re-reads the cookies of the nodes periodically, or as soon
+// as a node rejected our credentials.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *BitcoindFailover) cookieRefresher() {
+	defer f.wg.Done()
+
+	refreshTicker := time.NewTicker(f.cfg.CookieRefreshInterval)
+	defer refreshTicker.Stop()
+
+	for {
+		select {
+		case <-refreshTicker.C:
+		case <-f.refreshCookies:
+		case <-f.quit:
+			return
+		}
+
+		f.reloadCookies()
+	}
+}
+
+// reloadCookies re-reads the cookies of all nodes that use one. If a cookie
+// can't be read, e.g. because bitcoind is restarting and didn't create its new
+// cookie yet, we keep the last known credentials and retry later.
+func (f *BitcoindFailover) reloadCookies() {
+	for i, endpoint := range f.snapshotEndpoints() {
+		if endpoint.CookiePath == "" {
+			continue
+		}
+
+		user, pass, err := lncfg.ReadBitcoindCookie(endpoint.CookiePath)
+		if err != nil {
+			log.Debugf("Keeping last known credentials of bitcoind "+
+				"node %v: %v", endpoint.Host, err)
+
+			continue
+		}
+
+		if user == endpoint.User && pass == endpoint.Pass {
+			continue
+		}
+
+		log.Infof("Bitcoind node %v rotated its auth cookie, using "+
+			"new credentials", endpoint.Host)
+
+		f.mtx.Lock()
+		f.endpoints[i].User = user
+		f.endpoints[i].Pass = pass
+		f.mtx.Unlock()
+	}
+}
+
+// checkResponse inspects the response of the active node. If the node
+// rejected our credentials, its cookie might have been rotated, so we re-read
+// it without waiting for the next refresh.
+func (f *BitcoindFailover) checkResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusUnauthorized ||
+		f.cfg.CookieRefreshInterval == 0 {
+
+		return nil
+	}
+
+	select {
+	case f.refreshCookies <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// authenticate only passes on requests that carry the credentials of the
+// proxy.
+func (f *BitcoindFailover) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		userOk := subtle.ConstantTimeCompare([]byte(user), []byte(f.user))
+		passOk := subtle.ConstantTimeCompare([]byte(pass), []byte(f.pass))
+		if !ok || userOk != 1 || passOk != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// direct rewrites a proxied request to target the active node.
+func (f *BitcoindFailover) direct(r *http.Request) {
+	endpoint := f.activeEndpoint()
+
+	r.URL.Scheme = "http"
+	r.URL.Host = endpoint.Host
+	r.Host = endpoint.Host
+	r.SetBasicAuth(endpoint.User, endpoint.Pass)
+}
+
+// handleProxyError answers a request that couldn't be forwarded to the active
+// node.
+func (f *BitcoindFailover) handleProxyError(w http.ResponseWriter,
+	_ *http.Request, err error) {
+
+	log.Debugf("Unable to forward request to bitcoind node %v: %v",
+		f.ActiveHost(), err)
+
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+// bitcoindChainInfo is the part of the getblockchaininfo response of a
+// bitcoind node we check before failing over to it.
+type bitcoindChainInfo struct {
+	// Chain is the name of the chain the node runs on.
+	Chain string `json:"chain"`
+
+	// Blocks is the height of the best validated block of the node.
+	Blocks int64 `json:"blocks"`
+
+	// Headers is the height of the best block header the node knows.
+	Headers int64 `json:"headers"`
+
+	// InitialBlockDownload is true while the node is still syncing the
+	// chain for the first time.
+	InitialBlockDownload bool `json:"initialblockdownload"`
+}
+
+// probe checks that the given node answers RPC requests, runs on the expected
+// chain and validated all blocks it knows the headers of. It returns the chain
+// info of the node, so that its height can be compared to other nodes.
+func (f *BitcoindFailover) probe(endpoint BitcoindEndpoint) (
+	*bitcoindChainInfo, error) {
+
+	reqBody := []byte(`{"jsonrpc":"1.0","id":0,` +
+		`"method":"getblockchaininfo","params":[]}`)
+
+	req, err := http.NewRequest(
+		http.MethodPost, "http://"+endpoint.Host,
+		bytes.NewReader(reqBody),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(endpoint.User, endpoint.Pass)
+
+	client := *f.httpClient
+	client.Timeout = failoverProbeTimeout
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	var chainInfo struct {
+		Result bitcoindChainInfo `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &chainInfo); err != nil {
+		return nil, err
+	}
+	info := &chainInfo.Result
+
+	expectedChain := bitcoindChainName(f.cfg.ChainParams)
+	if info.Chain != expectedChain {
+		return nil, fmt.Errorf("node is on chain %v, expected %v",
+			info.Chain, expectedChain)
+	}
+
+	if info.InitialBlockDownload {
+		return nil, fmt.Errorf("node is in initial block download, "+
+			"at block %d of %d", info.Blocks, info.Headers)
+	}
+
+	if info.Blocks+failoverMaxTipLag < info.Headers {
+		return nil, fmt.Errorf("node is still syncing, at block %d "+
+			"of %d", info.Blocks, info.Headers)
+	}
+
+	return info, nil
+}
+
+// bitcoindChainName returns the name bitcoind reports for the given chain.
+func bitcoindChainName(params *chaincfg.Params) string {
+	switch params.Net {
+	case chaincfg.MainNetParams.Net:
+		return "main"
+
+	case chaincfg.TestNet3Params.Net:
+		return "test"
+
+	default:
+		return params.Name
+	}
+}
+
+// randomHex returns a random hex string of the given number of bytes.
+func randomHex(numBytes int) (string, error) {
+	b := make([]byte, numBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,355 @@
+package chainreg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBitcoind is a minimal bitcoind RPC server that answers every request
+// with its name, so tests can tell which node served a request.
+type fakeBitcoind struct {
+	*httptest.Server
+
+	name    string
+	healthy atomic.Bool
+
+	// pass is the password the node accepts, the user name is always the
+	// name of the node.
+	pass atomic.Value
+
+	// blocks, headers and ibd are reported by getblockchaininfo.
+	blocks  atomic.Int64
+	headers atomic.Int64
+	ibd     atomic.Bool
+}
+
+// newFakeBitcoind starts a healthy fake bitcoind node with the given name,
+// which is also its password. The node is synced up to height 100.
+func newFakeBitcoind(t *testing.T, name string) *fakeBitcoind {
+	node := &fakeBitcoind{name: name}
+	node.healthy.Store(true)
+	node.pass.Store(name)
+	node.setHeight(100, 100)
+
+	node.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != name || pass != node.pass.Load() {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if !node.healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			var req struct {
+				ID     uint64 `json:"id"`
+				Method string `json:"method"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			var result string
+			switch req.Method {
+			case "getblockchaininfo":
+				result = fmt.Sprintf(`{"chain":"regtest",`+
+					`"blocks":%d,"headers":%d,`+
+					`"initialblockdownload":%v}`,
+					node.blocks.Load(),
+					node.headers.Load(), node.ibd.Load())
+
+			default:
+				result = fmt.Sprintf("%q", name)
+			}
+
+			fmt.Fprintf(w, `{"result":%s,"error":null,"id":%d}`,
+				result, req.ID)
+		},
+	))
+	t.Cleanup(node.Close)
+
+	return node
+}
+
+// setHeight sets the block and header height the fake node reports.
+func (n *fakeBitcoind) setHeight(blocks, headers int64) {
+	n.blocks.Store(blocks)
+	n.headers.Store(headers)
+}
+
+// endpoint returns the RPC endpoint of the fake node.
+func (n *fakeBitcoind) endpoint() BitcoindEndpoint {
+	return BitcoindEndpoint{
+		Host: strings.TrimPrefix(n.URL, "http://"),
+		User: n.name,
+		Pass: n.name,
+	}
+}
+
+// TestBitcoindFailover checks that requests are forwarded to the primary node
+// until the health check failed consistently, and to the fallback node after
+// the failover.
+func TestBitcoindFailover(t *testing.T) {
+	t.Parallel()
+
+	primary := newFakeBitcoind(t, "primary")
+	fallback := newFakeBitcoind(t, "fallback")
+
+	failover, err := NewBitcoindFailover(&BitcoindFailoverConfig{
+		Endpoints: []BitcoindEndpoint{
+			primary.endpoint(), fallback.endpoint(),
+		},
+		ChainParams:   &chaincfg.RegressionNetParams,
+		FailoverAfter: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, failover.Start())
+	t.Cleanup(func() {
+		require.NoError(t, failover.Stop())
+	})
+
+	client, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:                 failover.Host(),
+		User:                 failover.User(),
+		Pass:                 failover.Pass(),
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+		DisableTLS:           true,
+		HTTPPostMode:         true,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(client.Shutdown)
+
+	servedBy := func() string {
+		resp, err := client.RawRequest("uptime", nil)
+		require.NoError(t, err)
+
+		var name string
+		require.NoError(t, json.Unmarshal(resp, &name))
+
+		return name
+	}
+
+	// The proxy can only be used with its own credentials.
+	unauthorized, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         failover.Host(),
+		User:         "primary",
+		Pass:         "primary",
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(unauthorized.Shutdown)
+	_, err = unauthorized.RawRequest("uptime", nil)
+	require.Error(t, err)
+
+	require.Equal(t, primary.endpoint().Host, failover.ActiveHost())
+	require.Equal(t, "primary", servedBy())
+
+	sub, err := failover.SubscribeFailovers()
+	require.NoError(t, err)
+	t.Cleanup(sub.Cancel)
+
+	healthCheck := failover.WrapHealthCheck(func() error {
+		_, err := client.RawRequest("getbestblockhash", nil)
+		return err
+	})
+
+	// A single failed health check doesn't cause a failover yet.
+	primary.healthy.Store(false)
+	require.Error(t, healthCheck())
+	require.Equal(t, primary.endpoint().Host, failover.ActiveHost())
+
+	// Once the check failed consistently, we fail over and the check
+	// passes against the fallback node.
+	require.NoError(t, healthCheck())
+	require.Equal(t, fallback.endpoint().Host, failover.ActiveHost())
+	require.Equal(t, "fallback", servedBy())
+
+	select {
+	case update := <-sub.Updates():
+		event, ok := update.(*BackendFailoverEvent)
+		require.True(t, ok)
+		require.Equal(t, primary.endpoint().Host, event.PreviousHost)
+		require.Equal(t, fallback.endpoint().Host, event.ActiveHost)
+		require.Error(t, event.Reason)
+
+	case <-time.After(time.Second):
+		t.Fatal("no failover event received")
+	}
+
+	// If no other node is healthy, we stay on the current one.
+	fallback.healthy.Store(false)
+	require.Error(t, healthCheck())
+	require.Error(t, healthCheck())
+	require.Equal(t, fallback.endpoint().Host, failover.ActiveHost())
+
+	// As soon as the primary node is back, the next failed check fails
+	// back to it.
+	primary.healthy.Store(true)
+	require.NoError(t, healthCheck())
+	require.Equal(t, primary.endpoint().Host, failover.ActiveHost())
+}
+
+// TestBitcoindFailoverWrongChain checks that we don't fail over to a node that
+// runs on another chain.
+func TestBitcoindFailoverWrongChain(t *testing.T) {
+	t.Parallel()
+
+	primary := newFakeBitcoind(t, "primary")
+	fallback := newFakeBitcoind(t, "fallback")
+
+	failover, err := NewBitcoindFailover(&BitcoindFailoverConfig{
+		Endpoints: []BitcoindEndpoint{
+			primary.endpoint(), fallback.endpoint(),
+		},
+		ChainParams:   &chaincfg.MainNetParams,
+		FailoverAfter: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, failover.Start())
+	t.Cleanup(func() {
+		require.NoError(t, failover.Stop())
+	})
+
+	healthCheck := failover.WrapHealthCheck(func() error {
+		return errors.New("unhealthy")
+	})
+	require.Error(t, healthCheck())
+	require.Equal(t, primary.endpoint().Host, failover.ActiveHost())
+}
+
+// TestBitcoindFailoverUnsynced checks that we don't fail over to a node that
+// is in its initial block download, still validates blocks it has the headers
+// of, or is behind the best tip any node reported.
+func TestBitcoindFailoverUnsynced(t *testing.T) {
+	t.Parallel()
+
+	primary := newFakeBitcoind(t, "primary")
+	ibd := newFakeBitcoind(t, "ibd")
+	syncing := newFakeBitcoind(t, "syncing")
+	behind := newFakeBitcoind(t, "behind")
+	synced := newFakeBitcoind(t, "synced")
+
+	ibd.ibd.Store(true)
+	syncing.setHeight(50, 100)
+	behind.setHeight(90, 90)
+
+	failover, err := NewBitcoindFailover(&BitcoindFailoverConfig{
+		Endpoints: []BitcoindEndpoint{
+			primary.endpoint(), ibd.endpoint(), syncing.endpoint(),
+			behind.endpoint(), synced.endpoint(),
+		},
+		ChainParams:   &chaincfg.RegressionNetParams,
+		FailoverAfter: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, failover.Start())
+	t.Cleanup(func() {
+		require.NoError(t, failover.Stop())
+	})
+	require.Equal(t, primary.endpoint().Host, failover.ActiveHost())
+
+	healthCheck := failover.WrapHealthCheck(func() error {
+		return errors.New("unhealthy")
+	})
+
+	// The primary node is unreachable now, but we still remember its tip
+	// from the startup, so we skip the node that is behind it.
+	primary.healthy.Store(false)
+	require.Error(t, healthCheck())
+	require.Equal(t, synced.endpoint().Host, failover.ActiveHost())
+
+	// A newer tip reported by the failing node raises the bar as well, so
+	// there's no node left to fail over to.
+	synced.setHeight(110, 110)
+	require.Error(t, healthCheck())
+	require.Equal(t, synced.endpoint().Host, failover.ActiveHost())
+
+	// As soon as a node caught up, we fail over to it.
+	behind.setHeight(110, 110)
+	require.Error(t, healthCheck())
+	require.Equal(t, behind.endpoint().Host, failover.ActiveHost())
+}
+
+// TestBitcoindFailoverCookieRefresh checks that a rotated auth cookie is picked
+// up without a restart, and that the last known credentials are kept while
+// the cookie file is missing.
+func TestBitcoindFailoverCookieRefresh(t *testing.T) {
+	t.Parallel()
+
+	node := newFakeBitcoind(t, "node")
+
+	cookiePath := filepath.Join(t.TempDir(), ".cookie")
+	writeCookie := func(pass string) {
+		err := os.WriteFile(cookiePath, []byte("node:"+pass), 0600)
+		require.NoError(t, err)
+	}
+	writeCookie("node")
+
+	endpoint := node.endpoint()
+	endpoint.CookiePath = cookiePath
+
+	// We use a long interval, so that only a rejected request triggers a
+	// refresh of the cookie.
+	failover, err := NewBitcoindFailover(&BitcoindFailoverConfig{
+		Endpoints:             []BitcoindEndpoint{endpoint},
+		ChainParams:           &chaincfg.RegressionNetParams,
+		FailoverAfter:         1,
+		CookieRefreshInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	require.NoError(t, failover.Start())
+	t.Cleanup(func() {
+		require.NoError(t, failover.Stop())
+	})
+	require.False(t, failover.HasFallback())
+
+	client, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         failover.Host(),
+		User:         failover.User(),
+		Pass:         failover.Pass(),
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(client.Shutdown)
+
+	request := func() error {
+		_, err := client.RawRequest("uptime", nil)
+		return err
+	}
+	require.NoError(t, request())
+
+	// While bitcoind restarts, its cookie file is missing. We keep using
+	// the last known credentials.
+	require.NoError(t, os.Remove(cookiePath))
+	failover.reloadCookies()
+	require.Equal(t, "node", failover.activeEndpoint().Pass)
+
+	// Once bitcoind created its new cookie, the next rejected request
+	// makes us pick it up.
+	node.pass.Store("rotated")
+	writeCookie("rotated")
+	require.Error(t, request())
+
+	require.Eventually(t, func() bool {
+		return request() == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, "rotated", failover.activeEndpoint().Pass)
+}
@@ -0,0 +1,297 @@
+package chainreg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// netParamsFile is the JSON definition of a custom network as read from a
+// chain parameters file.
+type netParamsFile struct {
+	// Name is the name of the network. It is also used as the name of the
+	// network specific data directories.
+	Name string `json:"name"`
+
+	// Base is the name of the network the consensus rules and address
+	// encodings are taken from. It must be one of mainnet, testnet3,
+	// regtest, simnet or signet.
+	Base string `json:"base"`
+
+	// NetMagic is the magic value that identifies the network on the
+	// wire. It is derived from the challenge for signet based networks.
+	NetMagic uint32 `json:"net_magic"`
+
+	// DefaultPort is the default p2p port of the network.
+	DefaultPort string `json:"default_port"`
+
+	// RPCPort is the default RPC port of the chain backend.
+	RPCPort string `json:"rpc_port"`
+
+	// GenesisBlock is the hex encoded serialized genesis block. If unset,
+	// the genesis block of the base network is used.
+	GenesisBlock string `json:"genesis_block"`
+
+	// GenesisHash is the expected hash of the genesis block. If set, it
+	// must match the hash of the genesis block.
+	GenesisHash string `json:"genesis_hash"`
+
+	// HDCoinType is the BIP-0044 coin type used to derive keys. If unset,
+	// the coin type of the base network is used.
+	HDCoinType *uint32 `json:"hd_coin_type"`
+
+	// DNSSeeds is the list of DNS seeds used for peer discovery.
+	DNSSeeds []string `json:"dns_seeds"`
+
+	// SigNetChallenge is the hex encoded signet challenge script. It can
+	// only be set for signet based networks.
+	SigNetChallenge string `json:"signet_challenge"`
+}
+
+// baseNetParams maps the names of the networks that can be used as the base
+// of a custom network to their parameters.
+var baseNetParams = map[string]BitcoinNetParams{
+	chaincfg.MainNetParams.Name:       BitcoinMainNetParams,
+	chaincfg.TestNet3Params.Name:      BitcoinTestNetParams,
+	chaincfg.RegressionNetParams.Name: BitcoinRegTestNetParams,
+	chaincfg.SimNetParams.Name:        BitcoinSimNetParams,
+	chaincfg.SigNetParams.Name:        BitcoinSigNetParams,
+}
+
+// LoadNetParamsFile reads the JSON definition of a custom network from the
+// given file and assembles its parameters. The parameters are checked for
+// internal consistency and must not clash with any of the well-known
+// networks.
+func LoadNetParamsFile(path string) (BitcoinNetParams, error) {
+	var netParams BitcoinNetParams
+
+	rawParams, err := os.ReadFile(path)
+	if err != nil {
+		return netParams, err
+	}
+
+	var paramsFile netParamsFile
+	decoder := json.NewDecoder(bytes.NewReader(rawParams))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&paramsFile); err != nil {
+		return netParams, fmt.Errorf("unable to parse %v: %w", path,
+			err)
+	}
+
+	return paramsFile.netParams()
+}
+
+// netParams assembles and validates the parameters defined by the file.
+func (f *netParamsFile) netParams() (BitcoinNetParams, error) {
+	var netParams BitcoinNetParams
+
+	if f.Name == "" {
+		return netParams, errors.New("network name must be set")
+	}
+	for _, base := range baseNetParams {
+		if f.Name == base.Name {
+			return netParams, fmt.Errorf("network name %v is "+
+				"reserved for a well-known network", f.Name)
+		}
+	}
+	if !isValidNetName(f.Name) {
+		return netParams, fmt.Errorf("invalid network name %q, only "+
+			"letters, digits, '-' and '_' are allowed", f.Name)
+	}
+
+	base, ok := baseNetParams[f.Base]
+	if !ok {
+		return netParams, fmt.Errorf("unknown base network %q", f.Base)
+	}
+
+	// Copy the base parameters so we don't modify the well-known
+	// networks.
+	params := *base.Params
+	if f.Base == chaincfg.SigNetParams.Name {
+		challenge := chaincfg.DefaultSignetChallenge
+		if f.SigNetChallenge != "" {
+			var err error
+			challenge, err = hex.DecodeString(f.SigNetChallenge)
+			if err != nil {
+				return netParams, fmt.Errorf("invalid signet "+
+					"challenge: %w", err)
+			}
+		}
+		params = chaincfg.CustomSignetParams(challenge, nil)
+
+		// The magic of a signet is derived from its challenge, so a
+		// configured magic must match it.
+		magic := wire.BitcoinNet(f.NetMagic)
+		if f.NetMagic != 0 && magic != params.Net {
+			return netParams, fmt.Errorf("net magic %#x doesn't "+
+				"match the magic %#x derived from the signet "+
+				"challenge", f.NetMagic, uint32(params.Net))
+		}
+	} else {
+		if f.SigNetChallenge != "" {
+			return netParams, errors.New("signet challenge can " +
+				"only be set for signet based networks")
+		}
+
+		params.Net = wire.BitcoinNet(f.NetMagic)
+	}
+	params.Name = f.Name
+
+	if params.Net == 0 {
+		return netParams, errors.New("net magic must be set")
+	}
+	for _, known := range baseNetParams {
+		if params.Net == known.Net {
+			return netParams, fmt.Errorf("net magic %#x is "+
+				"used by %v", uint32(params.Net), known.Name)
+		}
+	}
+
+	if err := validatePort(f.DefaultPort); err != nil {
+		return netParams, fmt.Errorf("invalid default port: %w", err)
+	}
+	if err := validatePort(f.RPCPort); err != nil {
+		return netParams, fmt.Errorf("invalid RPC port: %w", err)
+	}
+	if f.DefaultPort == f.RPCPort {
+		return netParams, errors.New("default port and RPC port " +
+			"must differ")
+	}
+	params.DefaultPort = f.DefaultPort
+
+	if f.GenesisBlock != "" {
+		genesis, err := parseGenesisBlock(f.GenesisBlock)
+		if err != nil {
+			return netParams, err
+		}
+
+		genesisHash := genesis.BlockHash()
+		params.GenesisBlock = genesis
+		params.GenesisHash = &genesisHash
+
+		// Checkpoints of the base network are meaningless on a chain
+		// with a different genesis block.
+		if genesisHash != *base.GenesisHash {
+			params.Checkpoints = nil
+		}
+	}
+
+	if f.GenesisHash != "" {
+		expectedHash, err := chainhash.NewHashFromStr(f.GenesisHash)
+		if err != nil {
+			return netParams, fmt.Errorf("invalid genesis hash: %w",
+				err)
+		}
+
+		if *expectedHash != *params.GenesisHash {
+			return netParams, fmt.Errorf("genesis hash %v doesn't "+
+				"match genesis block hash %v", expectedHash,
+				params.GenesisHash)
+		}
+	}
+
+	// Using the same coin type as mainnet would derive the same keys as
+	// a mainnet node using the same seed.
+	coinType := base.CoinType
+	if f.HDCoinType != nil {
+		coinType = *f.HDCoinType
+	}
+	if coinType >= hdkeychain.HardenedKeyStart {
+		return netParams, fmt.Errorf("invalid HD coin type %v",
+			coinType)
+	}
+	if coinType == keychain.CoinTypeBitcoin {
+		return netParams, errors.New("the mainnet HD coin type must " +
+			"not be used by custom networks")
+	}
+	params.HDCoinType = coinType
+
+	params.DNSSeeds = make([]chaincfg.DNSSeed, 0, len(f.DNSSeeds))
+	for _, seed := range f.DNSSeeds {
+		params.DNSSeeds = append(params.DNSSeeds, chaincfg.DNSSeed{
+			Host: seed,
+		})
+	}
+
+	return BitcoinNetParams{
+		Params:   &params,
+		RPCPort:  f.RPCPort,
+		CoinType: coinType,
+	}, nil
+}
+
+// parseGenesisBlock decodes the given hex encoded genesis block and checks
+// that it is well-formed.
+func parseGenesisBlock(genesisHex string) (*wire.MsgBlock, error) {
+	rawBlock, err := hex.DecodeString(genesisHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis block: %w", err)
+	}
+
+	var genesis wire.MsgBlock
+	if err := genesis.Deserialize(bytes.NewReader(rawBlock)); err != nil {
+		return nil, fmt.Errorf("invalid genesis block: %w", err)
+	}
+
+	if genesis.Header.PrevBlock != (chainhash.Hash{}) {
+		return nil, errors.New("genesis block must not have a " +
+			"previous block")
+	}
+	if len(genesis.Transactions) != 1 {
+		return nil, fmt.Errorf("genesis block must have exactly one "+
+			"transaction, got %v", len(genesis.Transactions))
+	}
+	if !blockchain.IsCoinBaseTx(genesis.Transactions[0]) {
+		return nil, errors.New("genesis block transaction must be a " +
+			"coinbase")
+	}
+
+	merkleRoot := genesis.Transactions[0].TxHash()
+	if genesis.Header.MerkleRoot != merkleRoot {
+		return nil, fmt.Errorf("genesis block merkle root %v doesn't "+
+			"match its transaction %v", genesis.Header.MerkleRoot,
+			merkleRoot)
+	}
+
+	return &genesis, nil
+}
+
+// validatePort checks that the given string is a valid, non-zero port.
+func validatePort(port string) error {
+	portNum, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return err
+	}
+	if portNum == 0 {
+		return errors.New("port must not be zero")
+	}
+
+	return nil
+}
+
+// isValidNetName returns true if the network name only contains characters
+// that are safe to use in a directory name.
+func isValidNetName(name string) bool {
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9', c == '-', c == '_':
+
+		default:
+			return false
+		}
+	}
+
+	return true
+}
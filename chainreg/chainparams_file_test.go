@@ -0,0 +1,154 @@
+package chainreg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadNetParamsFile checks that custom network parameters are assembled
+// from a params file and that inconsistent parameters are rejected.
+func TestLoadNetParamsFile(t *testing.T) {
+	t.Parallel()
+
+	// Create a genesis block that differs from the regtest one.
+	genesis := *chaincfg.RegressionNetParams.GenesisBlock
+	genesis.Header.Timestamp = genesis.Header.Timestamp.Add(time.Second)
+	genesisHash := genesis.BlockHash()
+
+	var genesisBuf bytes.Buffer
+	require.NoError(t, genesis.Serialize(&genesisBuf))
+	genesisHex := hex.EncodeToString(genesisBuf.Bytes())
+
+	// A genesis block whose merkle root doesn't commit to its coinbase.
+	badGenesis := genesis
+	badGenesis.Header.MerkleRoot[0] ^= 1
+
+	var badGenesisBuf bytes.Buffer
+	require.NoError(t, badGenesis.Serialize(&badGenesisBuf))
+	badGenesisHex := hex.EncodeToString(badGenesisBuf.Bytes())
+
+	testCases := []struct {
+		name   string
+		params string
+		expErr string
+	}{{
+		name: "valid custom genesis",
+		params: `{
+			"name": "devnet",
+			"base": "regtest",
+			"net_magic": 3735928559,
+			"default_port": "19444",
+			"rpc_port": "19443",
+			"genesis_block": "` + genesisHex + `",
+			"genesis_hash": "` + genesisHash.String() + `",
+			"hd_coin_type": 1337,
+			"dns_seeds": ["seed.devnet.example"]
+		}`,
+	}, {
+		name: "reserved name",
+		params: `{"name": "regtest", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2"}`,
+		expErr: "reserved",
+	}, {
+		name: "unknown base",
+		params: `{"name": "devnet", "base": "litecoin",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2"}`,
+		expErr: "unknown base network",
+	}, {
+		name: "well-known magic",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 3652501241, "default_port": "1",
+			"rpc_port": "2"}`,
+		expErr: "used by mainnet",
+	}, {
+		name: "signet with default challenge",
+		params: `{"name": "devnet", "base": "signet",
+			"default_port": "1", "rpc_port": "2"}`,
+		expErr: "used by signet",
+	}, {
+		name: "signet challenge on regtest",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2", "signet_challenge": "51"}`,
+		expErr: "only be set for signet",
+	}, {
+		name: "same ports",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "1"}`,
+		expErr: "must differ",
+	}, {
+		name: "genesis hash mismatch",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2", "genesis_hash": "` +
+			chaincfg.RegressionNetParams.GenesisHash.String() +
+			`", "genesis_block": "` + genesisHex + `"}`,
+		expErr: "doesn't match genesis block hash",
+	}, {
+		name: "bad merkle root",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2", "genesis_block": "` +
+			badGenesisHex + `"}`,
+		expErr: "merkle root",
+	}, {
+		name: "mainnet coin type",
+		params: `{"name": "devnet", "base": "mainnet",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2"}`,
+		expErr: "mainnet HD coin type",
+	}, {
+		name: "unknown field",
+		params: `{"name": "devnet", "base": "regtest",
+			"net_magic": 1, "default_port": "1",
+			"rpc_port": "2", "bech32_hrp": "dev"}`,
+		expErr: "unknown field",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "params.json")
+			err := os.WriteFile(path, []byte(tc.params), 0600)
+			require.NoError(t, err)
+
+			netParams, err := LoadNetParamsFile(path)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, "devnet", netParams.Name)
+			require.Equal(
+				t, wire.BitcoinNet(3735928559), netParams.Net,
+			)
+			require.Equal(t, "19444", netParams.DefaultPort)
+			require.Equal(t, "19443", netParams.RPCPort)
+			require.Equal(t, genesisHash, *netParams.GenesisHash)
+			require.Nil(t, netParams.Checkpoints)
+			require.EqualValues(t, 1337, netParams.CoinType)
+			require.EqualValues(t, 1337, netParams.HDCoinType)
+			require.Len(t, netParams.DNSSeeds, 1)
+
+			// The well-known network must not have been modified.
+			require.Equal(
+				t, "regtest", chaincfg.RegressionNetParams.Name,
+			)
+		})
+	}
+}
@@ -45,6 +45,15 @@ type Config struct {
 	// queries if true.
 	HeightHintCacheQueryDisable bool
 
+	// RescanConcurrency is the maximum number of historical rescans the
+	// chain notifier performs concurrently.
+	RescanConcurrency uint32
+
+	// BlockDownloadConcurrency is the number of blocks the chain notifier
+	// fetches in parallel while scanning the chain. It only applies to the
+	// btcd and bitcoind backends.
+	BlockDownloadConcurrency uint32
+
 	// NeutrinoMode defines settings for connecting to a neutrino
 	// light-client.
 	NeutrinoMode *lncfg.Neutrino
@@ -85,10 +94,24 @@ type Config struct {
 	// optional.
 	Fee *lncfg.Fee
 
+	// MinRelayFeeRate is a hard floor for all fee rates returned by the
+	// fee estimator, independent of the backend's relay fee. A zero value
+	// disables the floor.
+	MinRelayFeeRate chainfee.SatPerKWeight
+
+	// MaxFeeRate is a hard ceiling for all fee rates estimated by the fee
+	// estimator. A zero value disables the ceiling.
+	MaxFeeRate chainfee.SatPerKWeight
+
 	// Dialer is a function closure that will be used to establish outbound
 	// TCP connections to Bitcoin peers in the event of a pruned block being
 	// requested.
 	Dialer chain.Dialer
+
+	// BackendFailoverAfter is the number of consecutive failed health
+	// checks after which lnd fails over to a fallback bitcoind node. It is
+	// only used if a fallback node is configured.
+	BackendFailoverAfter int
 }
 
 const (
@@ -146,6 +169,19 @@ type PartialChainControl struct {
 	// node.
 	HealthCheck func() error
 
+	// BackendInfo queries the chain backend for its current state, such
+	// as its best block and how far it lags behind the network.
+	BackendInfo func() *BackendInfo
+
+	// BackendFailover switches between the primary and the fallback
+	// bitcoind node and refreshes their credentials. It is nil if lnd
+	// connects to bitcoind directly.
+	BackendFailover *BitcoindFailover
+
+	// HeightHintCache is the cache of spend and confirm height hints used
+	// by the chain notifier.
+	HeightHintCache *channeldb.HeightHintCache
+
 	// FeeEstimator is used to estimate an optimal fee for transactions
 	// important to us.
 	FeeEstimator chainfee.Estimator
@@ -246,6 +282,7 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		return nil, nil, fmt.Errorf("unable to initialize height hint "+
 			"cache: %v", err)
 	}
+	cc.HeightHintCache = hintCache
 
 	// Map the deprecated feeurl flag to fee.url.
 	if cfg.FeeURL != "" {
@@ -267,6 +304,7 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		// the neutrino light client.
 		cc.ChainNotifier = neutrinonotify.New(
 			cfg.NeutrinoCS, hintCache, hintCache, cfg.BlockCache,
+			cfg.RescanConcurrency,
 		)
 		cc.ChainView, err = chainview.NewCfFilteredChainView(
 			cfg.NeutrinoCS, cfg.BlockCache,
@@ -275,9 +313,11 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return nil, nil, err
 		}
 
-		cc.ChainSource = chain.NewNeutrinoClient(
+		neutrinoClient := chain.NewNeutrinoClient(
 			cfg.ActiveNetParams.Params, cfg.NeutrinoCS,
 		)
+		trackWalletFilterCacheHits(neutrinoClient, cfg.NeutrinoCS)
+		cc.ChainSource = neutrinoClient
 
 		// Get our best block as a health check.
 		cc.HealthCheck = func() error {
@@ -285,52 +325,78 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return err
 		}
 
+		cc.BackendInfo = newBackendInfoFunc(
+			cfg.Bitcoin.Node, func(info *BackendInfo) error {
+				return queryNeutrinoBackendInfo(
+					cfg.NeutrinoCS, info,
+				)
+			},
+		)
+
 	case "bitcoind":
 		bitcoindMode := cfg.BitcoindMode
 
 		// Otherwise, we'll be speaking directly via RPC and ZMQ to a
-		// bitcoind node. If the specified host for the btcd RPC
-		// server already has a port specified, then we use that
-		// directly. Otherwise, we assume the default port according to
-		// the selected chain parameters.
-		var bitcoindHost string
-		if strings.Contains(bitcoindMode.RPCHost, ":") {
-			bitcoindHost = bitcoindMode.RPCHost
-		} else {
-			// The RPC ports specified in chainparams.go assume
-			// btcd, which picks a different port so that btcwallet
-			// can use the same RPC port as bitcoind. We convert
-			// this back to the btcwallet/bitcoind port.
-			rpcPort, err := strconv.Atoi(cfg.ActiveNetParams.RPCPort)
+		// bitcoind node.
+		bitcoindHost, err := bitcoindRPCHost(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		rpcUser, rpcPass := bitcoindMode.RPCUser, bitcoindMode.RPCPass
+
+		// If a fallback node is configured or the cookie is
+		// refreshed, all our RPC clients connect to a local proxy that
+		// forwards their requests to whichever node is healthy, with
+		// its current credentials.
+		fallback := bitcoindMode.Fallback
+		cookieRefresh := bitcoindMode.RPCCookieRefresh
+		if fallback.Enabled() || cookieRefresh > 0 {
+			endpoints := []BitcoindEndpoint{{
+				Host: bitcoindHost,
+				User: rpcUser,
+				Pass: rpcPass,
+			}}
+			if fallback.Enabled() {
+				endpoints = append(endpoints, BitcoindEndpoint{
+					Host: fallback.RPCHost,
+					User: fallback.RPCUser,
+					Pass: fallback.RPCPass,
+				})
+			}
+
+			// Only cookies that are refreshed are tracked, as the
+			// credentials are fixed otherwise.
+			if cookieRefresh > 0 {
+				endpoints[0].CookiePath = bitcoindMode.RPCCookie
+				if fallback.Enabled() {
+					endpoints[1].CookiePath = fallback.RPCCookie
+				}
+			}
+
+			failoverCfg := &BitcoindFailoverConfig{
+				Endpoints:             endpoints,
+				ChainParams:           cfg.ActiveNetParams.Params,
+				FailoverAfter:         cfg.BackendFailoverAfter,
+				CookieRefreshInterval: cookieRefresh,
+			}
+			failover, err := NewBitcoindFailover(failoverCfg)
 			if err != nil {
 				return nil, nil, err
 			}
-			rpcPort -= 2
-			bitcoindHost = fmt.Sprintf("%v:%d",
-				bitcoindMode.RPCHost, rpcPort)
-			if cfg.Bitcoin.RegTest || cfg.Bitcoin.SigNet {
-				conn, err := net.Dial("tcp", bitcoindHost)
-				if err != nil || conn == nil {
-					switch {
-					case cfg.Bitcoin.RegTest:
-						rpcPort = 18443
-					case cfg.Bitcoin.SigNet:
-						rpcPort = 38332
-					}
-					bitcoindHost = fmt.Sprintf("%v:%d",
-						bitcoindMode.RPCHost,
-						rpcPort)
-				} else {
-					conn.Close()
-				}
+			if err := failover.Start(); err != nil {
+				return nil, nil, err
 			}
+			cc.BackendFailover = failover
+
+			bitcoindHost = failover.Host()
+			rpcUser, rpcPass = failover.User(), failover.Pass()
 		}
 
 		bitcoindCfg := &chain.BitcoindConfig{
 			ChainParams:        cfg.ActiveNetParams.Params,
 			Host:               bitcoindHost,
-			User:               bitcoindMode.RPCUser,
-			Pass:               bitcoindMode.RPCPass,
+			User:               rpcUser,
+			Pass:               rpcPass,
 			Dialer:             cfg.Dialer,
 			PrunedModeMaxPeers: bitcoindMode.PrunedNodeMaxPeers,
 		}
@@ -365,7 +431,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 		chainNotifier := bitcoindnotify.New(
 			bitcoindConn, cfg.ActiveNetParams.Params, hintCache,
-			hintCache, cfg.BlockCache,
+			hintCache, cfg.BlockCache, cfg.RescanConcurrency,
+			cfg.BlockDownloadConcurrency,
 		)
 
 		cc.ChainNotifier = chainNotifier
@@ -380,8 +447,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		// proper fee estimator for testnet.
 		rpcConfig := &rpcclient.ConnConfig{
 			Host:                 bitcoindHost,
-			User:                 bitcoindMode.RPCUser,
-			Pass:                 bitcoindMode.RPCPass,
+			User:                 rpcUser,
+			Pass:                 rpcPass,
 			DisableConnectOnNew:  true,
 			DisableAutoReconnect: false,
 			DisableTLS:           true,
@@ -528,6 +595,20 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return checkOutboundPeers(chainConn)
 		}
 
+		if cc.BackendFailover != nil && cc.BackendFailover.HasFallback() {
+			cc.HealthCheck = cc.BackendFailover.WrapHealthCheck(
+				cc.HealthCheck,
+			)
+		}
+
+		cc.BackendInfo = newBackendInfoFunc(
+			cfg.Bitcoin.Node, func(info *BackendInfo) error {
+				return queryRPCBackendInfo(
+					chainConn, true, info,
+				)
+			},
+		)
+
 	case "btcd":
 		// Otherwise, we'll be speaking directly via RPC to a node.
 		//
@@ -585,7 +666,8 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 
 		chainNotifier, err := btcdnotify.New(
 			rpcConfig, cfg.ActiveNetParams.Params, hintCache,
-			hintCache, cfg.BlockCache,
+			hintCache, cfg.BlockCache, cfg.RescanConcurrency,
+			cfg.BlockDownloadConcurrency,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -651,6 +733,14 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			return checkOutboundPeers(chainRPC.Client)
 		}
 
+		cc.BackendInfo = newBackendInfoFunc(
+			cfg.Bitcoin.Node, func(info *BackendInfo) error {
+				return queryRPCBackendInfo(
+					chainConn, false, info,
+				)
+			},
+		)
+
 		// If we're not in simnet or regtest mode, then we'll attempt
 		// to use a proper fee estimator for testnet.
 		if !cfg.Bitcoin.SimNet && !cfg.Bitcoin.RegTest {
@@ -683,6 +773,11 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		cc.HealthCheck = func() error {
 			return nil
 		}
+		cc.BackendInfo = newBackendInfoFunc(
+			cfg.Bitcoin.Node, func(info *BackendInfo) error {
+				return queryChainSourceInfo(source, info)
+			},
+		)
 
 	default:
 		return nil, nil, fmt.Errorf("unknown node type: %s",
@@ -709,17 +804,29 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 		cacheFees := !cfg.Bitcoin.RegTest
 
 		log.Infof("Using external fee estimator %v: cached=%v: "+
-			"min update timeout=%v, max update timeout=%v",
-			cfg.Fee.URL, cacheFees, cfg.Fee.MinUpdateTimeout,
-			cfg.Fee.MaxUpdateTimeout)
+			"min update timeout=%v, max update timeout=%v, "+
+			"cache ttl=%v", cfg.Fee.URL, cacheFees,
+			cfg.Fee.MinUpdateTimeout, cfg.Fee.MaxUpdateTimeout,
+			cfg.Fee.CacheTTL)
+
+		feeSource := chainfee.SparseConfFeeSource{
+			URL: cfg.Fee.URL,
+		}
+		if cfg.Fee.URLSchema != "" {
+			feeSource.Schema, err = chainfee.ParseFeeSchema(
+				cfg.Fee.URLSchema,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 
 		cc.FeeEstimator, err = chainfee.NewWebAPIEstimator(
-			chainfee.SparseConfFeeSource{
-				URL: cfg.Fee.URL,
-			},
+			feeSource,
 			!cacheFees,
 			cfg.Fee.MinUpdateTimeout,
 			cfg.Fee.MaxUpdateTimeout,
+			cfg.Fee.CacheTTL,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -733,6 +840,34 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 					err)
 			}
 		}
+
+		if cc.BackendFailover != nil {
+			if err := cc.BackendFailover.Stop(); err != nil {
+				log.Errorf("Failed to stop bitcoind failover: "+
+					"%v", err)
+			}
+		}
+	}
+
+	// If a minimum relay fee rate override is set, make sure no fee rate
+	// below it is ever handed out, regardless of the backend's estimates.
+	if cfg.MinRelayFeeRate != 0 {
+		log.Infof("Enforcing minimum relay fee rate of %v",
+			cfg.MinRelayFeeRate)
+
+		cc.FeeEstimator = chainfee.NewFloorEstimator(
+			cc.FeeEstimator, cfg.MinRelayFeeRate,
+		)
+	}
+
+	// Likewise, if a max fee rate is set, make sure no fee rate estimate
+	// above it is ever handed out.
+	if cfg.MaxFeeRate != 0 {
+		log.Infof("Enforcing max fee rate of %v", cfg.MaxFeeRate)
+
+		cc.FeeEstimator = chainfee.NewCeilingEstimator(
+			cc.FeeEstimator, cfg.MaxFeeRate,
+		)
 	}
 
 	// Start fee estimator.
@@ -910,3 +1045,40 @@ func checkOutboundPeers(client *rpcclient.Client) error {
 
 	return nil
 }
+
+// bitcoindRPCHost returns the address of the RPC server of the configured
+// bitcoind node. If the specified host already has a port specified, then we
+// use that directly. Otherwise, we assume the default port according to the
+// selected chain parameters.
+func bitcoindRPCHost(cfg *Config) (string, error) {
+	rpcHost := cfg.BitcoindMode.RPCHost
+	if strings.Contains(rpcHost, ":") {
+		return rpcHost, nil
+	}
+
+	// The RPC ports specified in chainparams.go assume btcd, which picks a
+	// different port so that btcwallet can use the same RPC port as
+	// bitcoind. We convert this back to the btcwallet/bitcoind port.
+	rpcPort, err := strconv.Atoi(cfg.ActiveNetParams.RPCPort)
+	if err != nil {
+		return "", err
+	}
+	rpcPort -= 2
+	bitcoindHost := fmt.Sprintf("%v:%d", rpcHost, rpcPort)
+	if cfg.Bitcoin.RegTest || cfg.Bitcoin.SigNet {
+		conn, err := net.Dial("tcp", bitcoindHost)
+		if err != nil || conn == nil {
+			switch {
+			case cfg.Bitcoin.RegTest:
+				rpcPort = 18443
+			case cfg.Bitcoin.SigNet:
+				rpcPort = 38332
+			}
+			bitcoindHost = fmt.Sprintf("%v:%d", rpcHost, rpcPort)
+		} else {
+			conn.Close()
+		}
+	}
+
+	return bitcoindHost, nil
+}
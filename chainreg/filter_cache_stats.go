@@ -0,0 +1,179 @@
+package chainreg
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcutil/gcs"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightninglabs/neutrino/filterdb"
+)
+
+// FilterCacheStats is a snapshot of the statistics of the compact filter cache
+// of neutrino.
+type FilterCacheStats struct {
+	// Capacity is the capacity of the cache in bytes.
+	Capacity uint64
+
+	// NumEntries is the number of filters currently in the cache.
+	NumEntries int
+
+	// Misses is the number of filter lookups that weren't served from the
+	// cache.
+	Misses uint64
+
+	// DiskHits is the number of cache misses that were served from the
+	// filters persisted on disk. All other misses are fetched from the
+	// network.
+	DiskHits uint64
+
+	// WalletHits is the number of filter lookups of the block filter scans
+	// of the wallet that were served from the cache. Lookups made within
+	// neutrino, like the ones of its rescans, can't be observed and aren't
+	// counted.
+	WalletHits uint64
+
+	// WalletMisses is the number of filter lookups of the block filter
+	// scans of the wallet that weren't served from the cache. They're
+	// counted at the same lookup site as WalletHits, so both add up to all
+	// lookups of the wallet.
+	WalletMisses uint64
+}
+
+// filterCacheMissCounter wraps the filter database of neutrino to count the
+// filter lookups that miss the filter cache. Neutrino only consults the
+// database if a filter isn't cached, so every lookup is a miss.
+type filterCacheMissCounter struct {
+	filterdb.FilterDatabase
+
+	// capacity is the configured capacity of the filter cache.
+	capacity uint64
+
+	misses       atomic.Uint64
+	diskHits     atomic.Uint64
+	walletHits   atomic.Uint64
+	walletMisses atomic.Uint64
+}
+
+// FetchFilter fetches the filter of the given block from the database and
+// counts the lookup as a cache miss.
+//
+// NOTE: This is part of the filterdb.FilterDatabase interface.
+func (f *filterCacheMissCounter) FetchFilter(blockHash *chainhash.Hash,
+	filterType filterdb.FilterType) (*gcs.Filter, error) {
+
+	f.misses.Add(1)
+
+	filter, err := f.FilterDatabase.FetchFilter(blockHash, filterType)
+	if err == nil && filter != nil {
+		f.diskHits.Add(1)
+	}
+
+	return filter, err
+}
+
+// filterCache is the filter cache of neutrino.
+type filterCache = lru.Cache[neutrino.FilterCacheKey, *neutrino.CacheableFilter]
+
+// filterCacheHitCounter wraps the chain service used by the wallet to count
+// the filter lookups of its block filter scans that are served from the
+// filter cache and the ones that miss it.
+type filterCacheHitCounter struct {
+	chain.NeutrinoChainService
+
+	cache *filterCache
+
+	hits   *atomic.Uint64
+	misses *atomic.Uint64
+}
+
+// GetCFilter returns the filter of the given type for the block with the
+// given hash, and counts the lookup as a cache hit if the filter is cached or
+// as a cache miss otherwise.
+//
+// NOTE: This is part of the chain.NeutrinoChainService interface.
+func (f *filterCacheHitCounter) GetCFilter(blockHash chainhash.Hash,
+	filterType wire.FilterType,
+	options ...neutrino.QueryOption) (*gcs.Filter, error) {
+
+	// Neutrino only caches regular filters, which it looks up in its cache
+	// first, so we do the same to find out if the lookup is a hit.
+	if filterType == wire.GCSFilterRegular {
+		_, err := f.cache.Get(neutrino.FilterCacheKey{
+			BlockHash:  blockHash,
+			FilterType: filterdb.RegularFilter,
+		})
+		if err == nil {
+			f.hits.Add(1)
+		} else {
+			f.misses.Add(1)
+		}
+	}
+
+	return f.NeutrinoChainService.GetCFilter(
+		blockHash, filterType, options...,
+	)
+}
+
+// TrackFilterCache starts tracking the statistics of the filter cache of the
+// given chain service, which has the given capacity. It must be called before
+// the chain service is started.
+func TrackFilterCache(cs *neutrino.ChainService, capacity uint64) {
+	cs.FilterDB = &filterCacheMissCounter{
+		FilterDatabase: cs.FilterDB,
+		capacity:       capacity,
+	}
+}
+
+// trackWalletFilterCacheHits counts the filter lookups of the given wallet
+// chain client that are served from the filter cache of the given chain
+// service and the ones that miss it. Nothing is counted if the filter cache
+// isn't tracked.
+func trackWalletFilterCacheHits(client *chain.NeutrinoClient,
+	cs *neutrino.ChainService) {
+
+	counter, ok := cs.FilterDB.(*filterCacheMissCounter)
+	if !ok {
+		return
+	}
+
+	client.CS = &filterCacheHitCounter{
+		NeutrinoChainService: client.CS,
+		cache:                cs.FilterCache,
+		hits:                 &counter.walletHits,
+		misses:               &counter.walletMisses,
+	}
+}
+
+// NeutrinoFilterCacheStats returns the statistics of the filter cache of the
+// given chain service. The cache must be tracked with TrackFilterCache.
+func NeutrinoFilterCacheStats(cs *neutrino.ChainService) (*FilterCacheStats,
+	error) {
+
+	counter, ok := cs.FilterDB.(*filterCacheMissCounter)
+	if !ok {
+		return nil, errors.New("filter cache isn't tracked")
+	}
+
+	return &FilterCacheStats{
+		Capacity:     counter.capacity,
+		NumEntries:   cs.FilterCache.Len(),
+		Misses:       counter.misses.Load(),
+		DiskHits:     counter.diskHits.Load(),
+		WalletHits:   counter.walletHits.Load(),
+		WalletMisses: counter.walletMisses.Load(),
+	}, nil
+}
+
+// String returns a human readable summary of the filter cache statistics.
+func (s *FilterCacheStats) String() string {
+	return fmt.Sprintf("capacity=%v bytes, entries=%v, misses=%v, "+
+		"disk_hits=%v, wallet_hits=%v, wallet_misses=%v", s.Capacity,
+		s.NumEntries, s.Misses, s.DiskHits, s.WalletHits,
+		s.WalletMisses)
+}
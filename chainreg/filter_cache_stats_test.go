@@ -0,0 +1,142 @@
+package chainreg
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/gcs"
+	"github.com/btcsuite/btcd/btcutil/gcs/builder"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/lightninglabs/neutrino"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightninglabs/neutrino/filterdb"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFilterDB is a filter database that holds the filters of a fixed set of
+// blocks.
+type mockFilterDB struct {
+	filterdb.FilterDatabase
+
+	filters map[chainhash.Hash]*gcs.Filter
+}
+
+// FetchFilter returns the filter of the given block if it's known.
+func (m *mockFilterDB) FetchFilter(blockHash *chainhash.Hash,
+	_ filterdb.FilterType) (*gcs.Filter, error) {
+
+	filter, ok := m.filters[*blockHash]
+	if !ok {
+		return nil, filterdb.ErrFilterNotFound
+	}
+
+	return filter, nil
+}
+
+// mockNeutrinoChainService looks up filters like neutrino does, first in its
+// cache and then in its filter database.
+type mockNeutrinoChainService struct {
+	chain.NeutrinoChainService
+
+	cs *neutrino.ChainService
+}
+
+// GetCFilter returns the filter of the given block from the cache or the
+// filter database.
+func (m *mockNeutrinoChainService) GetCFilter(blockHash chainhash.Hash,
+	_ wire.FilterType, _ ...neutrino.QueryOption) (*gcs.Filter, error) {
+
+	cached, err := m.cs.FilterCache.Get(neutrino.FilterCacheKey{
+		BlockHash:  blockHash,
+		FilterType: filterdb.RegularFilter,
+	})
+	if err == nil {
+		return cached.Filter, nil
+	}
+
+	return m.cs.FilterDB.FetchFilter(&blockHash, filterdb.RegularFilter)
+}
+
+// newTestFilter returns a filter that matches the given data.
+func newTestFilter(t *testing.T, data []byte) *gcs.Filter {
+	t.Helper()
+
+	filter, err := gcs.BuildGCSFilter(
+		builder.DefaultP, builder.DefaultM, [gcs.KeySize]byte{},
+		[][]byte{data},
+	)
+	require.NoError(t, err)
+
+	return filter
+}
+
+// TestFilterCacheStats tests that filter lookups that miss the filter cache
+// are counted, split into disk hits and network fetches, and that the lookups
+// of the wallet are counted as cache hits and misses.
+func TestFilterCacheStats(t *testing.T) {
+	t.Parallel()
+
+	const capacity = 1_000_000
+
+	var (
+		cachedHash = chainhash.Hash{1}
+		diskHash   = chainhash.Hash{2}
+		remoteHash = chainhash.Hash{3}
+	)
+
+	cs := &neutrino.ChainService{
+		FilterDB: &mockFilterDB{
+			filters: map[chainhash.Hash]*gcs.Filter{
+				diskHash: newTestFilter(t, []byte{2}),
+			},
+		},
+		FilterCache: lru.NewCache[
+			neutrino.FilterCacheKey, *neutrino.CacheableFilter,
+		](capacity),
+	}
+	_, err := cs.FilterCache.Put(neutrino.FilterCacheKey{
+		BlockHash:  cachedHash,
+		FilterType: filterdb.RegularFilter,
+	}, &neutrino.CacheableFilter{Filter: newTestFilter(t, []byte{1})})
+	require.NoError(t, err)
+
+	// Without tracking, no stats are available.
+	_, err = NeutrinoFilterCacheStats(cs)
+	require.Error(t, err)
+
+	TrackFilterCache(cs, capacity)
+
+	client := &chain.NeutrinoClient{
+		CS: &mockNeutrinoChainService{cs: cs},
+	}
+	trackWalletFilterCacheHits(client, cs)
+
+	// A cached filter is a wallet hit and doesn't reach the database.
+	filter, err := client.CS.GetCFilter(cachedHash, wire.GCSFilterRegular)
+	require.NoError(t, err)
+	require.NotNil(t, filter)
+
+	// A filter on disk is a wallet miss served from disk.
+	filter, err = client.CS.GetCFilter(diskHash, wire.GCSFilterRegular)
+	require.NoError(t, err)
+	require.NotNil(t, filter)
+
+	// A filter that's neither cached nor on disk is a wallet miss that
+	// neutrino fetches from the network.
+	_, err = client.CS.GetCFilter(remoteHash, wire.GCSFilterRegular)
+	require.ErrorIs(t, err, filterdb.ErrFilterNotFound)
+
+	stats, err := NeutrinoFilterCacheStats(cs)
+	require.NoError(t, err)
+	require.Equal(t, &FilterCacheStats{
+		Capacity:     capacity,
+		NumEntries:   1,
+		Misses:       2,
+		DiskHits:     1,
+		WalletHits:   1,
+		WalletMisses: 2,
+	}, stats)
+	require.Equal(t, "capacity=1000000 bytes, entries=1, misses=2, "+
+		"disk_hits=1, wallet_hits=1, wallet_misses=2", stats.String())
+}
@@ -0,0 +1,57 @@
+package chainreg
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// neutrinoPeerPollInterval is the interval at which we check how many peers
+// neutrino is connected to while waiting for the minimum number of peers.
+const neutrinoPeerPollInterval = 500 * time.Millisecond
+
+// ErrMinPeersTimeout is returned when neutrino didn't connect to the minimum
+// number of peers before the timeout.
+var ErrMinPeersTimeout = errors.New("timed out waiting for minimum number " +
+	"of neutrino peers")
+
+// WaitForNeutrinoPeers blocks until neutrino is connected to at least
+// minPeers peers, as reported by connectedCount. If that doesn't happen
+// within the timeout, ErrMinPeersTimeout is returned, so a poorly connected
+// node isn't stuck forever.
+func WaitForNeutrinoPeers(ctx context.Context, connectedCount func() int32,
+	minPeers int32, timeout time.Duration) error {
+
+	if connectedCount() >= minPeers {
+		return nil
+	}
+
+	log.Infof("Waiting up to %v for neutrino to connect to at least %d "+
+		"peers", timeout, minPeers)
+
+	pollTicker := time.NewTicker(neutrinoPeerPollInterval)
+	defer pollTicker.Stop()
+
+	timeoutTimer := time.NewTimer(timeout)
+	defer timeoutTimer.Stop()
+
+	for {
+		select {
+		case <-pollTicker.C:
+			numPeers := connectedCount()
+			if numPeers < minPeers {
+				continue
+			}
+
+			log.Infof("Neutrino connected to %d peers", numPeers)
+
+			return nil
+
+		case <-timeoutTimer.C:
+			return ErrMinPeersTimeout
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
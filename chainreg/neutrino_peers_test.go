@@ -0,0 +1,46 @@
+package chainreg
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWaitForNeutrinoPeers checks that we wait until the minimum number of
+// neutrino peers is connected, but not longer than the timeout.
+func TestWaitForNeutrinoPeers(t *testing.T) {
+	var numPeers atomic.Int32
+	connectedCount := numPeers.Load
+
+	ctx := context.Background()
+
+	// Enough peers are connected already.
+	numPeers.Store(3)
+	err := WaitForNeutrinoPeers(ctx, connectedCount, 3, time.Millisecond)
+	require.NoError(t, err)
+
+	// Peers that connect while we wait are picked up.
+	numPeers.Store(1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		numPeers.Store(4)
+	}()
+	err = WaitForNeutrinoPeers(ctx, connectedCount, 3, 10*time.Second)
+	require.NoError(t, err)
+
+	// If not enough peers connect, we give up after the timeout.
+	numPeers.Store(1)
+	err = WaitForNeutrinoPeers(
+		ctx, connectedCount, 3, 100*time.Millisecond,
+	)
+	require.ErrorIs(t, err, ErrMinPeersTimeout)
+
+	// Shutting down aborts the wait.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = WaitForNeutrinoPeers(cancelCtx, connectedCount, 3, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,98 @@
+package chainreg
+
+import (
+	"github.com/lightninglabs/neutrino"
+)
+
+// SyncPhaseProgress is the progress of a single phase of the neutrino sync.
+type SyncPhaseProgress struct {
+	// Height is the height up to which the phase is synced.
+	Height uint32
+
+	// TargetHeight is the height the phase is syncing towards.
+	TargetHeight uint32
+}
+
+// Synced returns true if the phase reached its target height.
+func (p SyncPhaseProgress) Synced() bool {
+	return p.Height >= p.TargetHeight
+}
+
+// Progress returns the progress of the phase as a fraction between 0 and 1.
+func (p SyncPhaseProgress) Progress() float64 {
+	if p.Synced() {
+		return 1
+	}
+
+	return float64(p.Height) / float64(p.TargetHeight)
+}
+
+// NeutrinoSyncProgress breaks down the sync progress of neutrino into its
+// phases. Neutrino first syncs the block headers and then the filter headers
+// committing to the compact filter of each block. The filters themselves are
+// only fetched on demand for rescans, so they don't have a sync phase.
+type NeutrinoSyncProgress struct {
+	// BlockHeaders is the progress of the block header sync. Its target
+	// is the best height announced by our peers.
+	BlockHeaders SyncPhaseProgress
+
+	// FilterHeaders is the progress of the filter header sync. Its target
+	// is the height of the synced block headers, as filter headers can
+	// only be validated against the block headers.
+	FilterHeaders SyncPhaseProgress
+
+	// NumPeers is the number of peers we're connected to.
+	NumPeers int
+}
+
+// QueryNeutrinoSyncProgress returns the sync progress of the given neutrino
+// chain service.
+func QueryNeutrinoSyncProgress(
+	cs *neutrino.ChainService) (*NeutrinoSyncProgress, error) {
+
+	_, blockHeight, err := cs.BlockHeaders.ChainTip()
+	if err != nil {
+		return nil, err
+	}
+
+	_, filterHeight, err := cs.RegFilterHeaders.ChainTip()
+	if err != nil {
+		return nil, err
+	}
+
+	peers := cs.Peers()
+	peerHeights := make([]int32, 0, len(peers))
+	for _, peer := range peers {
+		peerHeights = append(peerHeights, peer.LastBlock())
+	}
+
+	return newNeutrinoSyncProgress(blockHeight, filterHeight, peerHeights),
+		nil
+}
+
+// newNeutrinoSyncProgress derives the sync progress from the heights of our
+// header chains and the best heights announced by our peers.
+func newNeutrinoSyncProgress(blockHeight, filterHeight uint32,
+	peerHeights []int32) *NeutrinoSyncProgress {
+
+	// Without peers, or with peers that are behind us, the best we know
+	// of is our own chain.
+	targetHeight := blockHeight
+	for _, peerHeight := range peerHeights {
+		if peerHeight > 0 && uint32(peerHeight) > targetHeight {
+			targetHeight = uint32(peerHeight)
+		}
+	}
+
+	return &NeutrinoSyncProgress{
+		BlockHeaders: SyncPhaseProgress{
+			Height:       blockHeight,
+			TargetHeight: targetHeight,
+		},
+		FilterHeaders: SyncPhaseProgress{
+			Height:       filterHeight,
+			TargetHeight: blockHeight,
+		},
+		NumPeers: len(peerHeights),
+	}
+}
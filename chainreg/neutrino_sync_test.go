@@ -0,0 +1,41 @@
+package chainreg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNeutrinoSyncProgress checks that the target heights of the neutrino sync
+// phases are derived from our peers and the block header chain.
+func TestNeutrinoSyncProgress(t *testing.T) {
+	t.Parallel()
+
+	// While syncing block headers, the target is the best height announced
+	// by our peers, and the filter headers follow our block headers.
+	progress := newNeutrinoSyncProgress(600, 300, []int32{0, 800, 1000})
+	require.Equal(t, 3, progress.NumPeers)
+	require.Equal(t, SyncPhaseProgress{
+		Height:       600,
+		TargetHeight: 1000,
+	}, progress.BlockHeaders)
+	require.InDelta(t, 0.6, progress.BlockHeaders.Progress(), 1e-9)
+	require.False(t, progress.BlockHeaders.Synced())
+
+	require.Equal(t, SyncPhaseProgress{
+		Height:       300,
+		TargetHeight: 600,
+	}, progress.FilterHeaders)
+	require.InDelta(t, 0.5, progress.FilterHeaders.Progress(), 1e-9)
+
+	// Peers that are behind us don't lower the target.
+	progress = newNeutrinoSyncProgress(1000, 1000, []int32{900})
+	require.True(t, progress.BlockHeaders.Synced())
+	require.True(t, progress.FilterHeaders.Synced())
+	require.EqualValues(t, 1, progress.BlockHeaders.Progress())
+
+	// Without any peers, we can only report our own chain.
+	progress = newNeutrinoSyncProgress(0, 0, nil)
+	require.Zero(t, progress.NumPeers)
+	require.True(t, progress.BlockHeaders.Synced())
+}
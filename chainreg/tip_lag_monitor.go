@@ -0,0 +1,195 @@
+package chainreg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+)
+
+// TipLagMonitorConfig houses the parameters of the TipLagMonitor.
+type TipLagMonitorConfig struct {
+	// BackendInfo queries the current state of the chain backend.
+	BackendInfo func() *BackendInfo
+
+	// MaxBlockLag is the maximum number of blocks the chain backend may
+	// lag behind the best block known on the network. A value of zero
+	// disables this check.
+	MaxBlockLag uint32
+
+	// MaxTimeLag is the maximum time that may pass without the chain
+	// backend advancing its best block. A value of zero disables this
+	// check.
+	MaxTimeLag time.Duration
+
+	// Interval is the interval at which the chain backend is checked.
+	Interval time.Duration
+
+	// Clock is the time source used by the monitor.
+	Clock clock.Clock
+
+	// OnLagging is called when the chain backend starts lagging behind.
+	OnLagging func()
+
+	// OnRecovered is called when the chain backend caught up again after
+	// it was lagging behind.
+	OnRecovered func()
+}
+
+// TipLagMonitor periodically checks whether the chain backend lags behind the
+// network. Once it exceeds the configured lag, OnLagging is called so lnd can
+// protect itself from acting on stale chain data. As soon as the backend
+// caught up again, OnRecovered is called.
+type TipLagMonitor struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *TipLagMonitorConfig
+
+	// lastHeight is the best block height of the chain backend seen by
+	// the last check.
+	lastHeight int32
+
+	// lastAdvance is the time at which the best block height of the chain
+	// backend was last seen advancing.
+	lastAdvance time.Time
+
+	// lagging is true while the chain backend is considered to be lagging
+	// behind.
+	lagging bool
+
+	// reason describes why the chain backend is lagging behind. It is
+	// empty while the backend keeps up with the network.
+	reason    string
+	reasonMtx sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewTipLagMonitor creates a new TipLagMonitor with the given config.
+func NewTipLagMonitor(cfg *TipLagMonitorConfig) *TipLagMonitor {
+	return &TipLagMonitor{
+		cfg:         cfg,
+		lastAdvance: cfg.Clock.Now(),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start launches the background task that monitors the chain backend.
+func (m *TipLagMonitor) Start() error {
+	m.started.Do(func() {
+		log.Infof("Starting chain tip lag monitor, max_block_lag=%v, "+
+			"max_time_lag=%v", m.cfg.MaxBlockLag, m.cfg.MaxTimeLag)
+
+		m.wg.Add(1)
+		go m.monitorLoop()
+	})
+
+	return nil
+}
+
+// Stop signals the background task to exit and waits for it to do so.
+func (m *TipLagMonitor) Stop() error {
+	m.stopped.Do(func() {
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// monitorLoop periodically checks the chain backend.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *TipLagMonitor) monitorLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.check()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// check queries the chain backend and calls the OnLagging or OnRecovered
+// callback if the backend started or stopped lagging behind.
+func (m *TipLagMonitor) check() {
+	info := m.cfg.BackendInfo()
+	now := m.cfg.Clock.Now()
+
+	if info.Connected && info.BestBlockHeight > m.lastHeight {
+		m.lastHeight = info.BestBlockHeight
+		m.lastAdvance = now
+	}
+
+	reason := m.lagReason(info, now)
+
+	m.reasonMtx.Lock()
+	m.reason = reason
+	m.reasonMtx.Unlock()
+
+	switch {
+	case reason != "" && !m.lagging:
+		log.Errorf("Chain backend is lagging behind: %v. Pausing "+
+			"HTLC forwards until it caught up", reason)
+
+		m.lagging = true
+		m.cfg.OnLagging()
+
+	case reason != "" && m.lagging:
+		log.Debugf("Chain backend is still lagging behind: %v",
+			reason)
+
+	case reason == "" && m.lagging:
+		log.Infof("Chain backend caught up at height %v, resuming "+
+			"normal operation", info.BestBlockHeight)
+
+		m.lagging = false
+		m.cfg.OnRecovered()
+	}
+}
+
+// CheckLag returns an error if the last check found the chain backend lagging
+// behind the network. It is meant to be used as a health check.
+func (m *TipLagMonitor) CheckLag() error {
+	m.reasonMtx.Lock()
+	defer m.reasonMtx.Unlock()
+
+	if m.reason == "" {
+		return nil
+	}
+
+	return errors.New("chain backend is lagging behind: " + m.reason)
+}
+
+// lagReason returns a description of why the chain backend is considered to
+// be lagging behind, or an empty string if it isn't.
+func (m *TipLagMonitor) lagReason(info *BackendInfo, now time.Time) string {
+	// We can only tell how far the backend is behind the network if we
+	// were able to query it.
+	if m.cfg.MaxBlockLag != 0 && info.Connected &&
+		info.BlocksBehind() > m.cfg.MaxBlockLag {
+
+		return fmt.Sprintf("best block %v is %v blocks behind the "+
+			"network tip %v", info.BestBlockHeight,
+			info.BlocksBehind(), info.TipHeight)
+	}
+
+	sinceAdvance := now.Sub(m.lastAdvance)
+	if m.cfg.MaxTimeLag != 0 && sinceAdvance > m.cfg.MaxTimeLag {
+		return fmt.Sprintf("no new block since %v",
+			sinceAdvance.Round(time.Second))
+	}
+
+	return ""
+}
@@ -0,0 +1,79 @@
+package chainreg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTipLagMonitor checks that the monitor detects a chain backend that lags
+// behind in blocks or time and notices once it caught up again.
+func TestTipLagMonitor(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1_700_000_000, 0))
+	info := &BackendInfo{
+		Backend:         "bitcoind",
+		Connected:       true,
+		BestBlockHeight: 100,
+		TipHeight:       100,
+	}
+
+	var lagging, recovered int
+	m := NewTipLagMonitor(&TipLagMonitorConfig{
+		BackendInfo: func() *BackendInfo {
+			infoCopy := *info
+			return &infoCopy
+		},
+		MaxBlockLag: 3,
+		MaxTimeLag:  time.Hour,
+		Interval:    time.Minute,
+		Clock:       testClock,
+		OnLagging: func() {
+			lagging++
+		},
+		OnRecovered: func() {
+			recovered++
+		},
+	})
+
+	// A backend that's in sync isn't lagging.
+	m.check()
+	require.Zero(t, lagging)
+	require.NoError(t, m.CheckLag())
+
+	// Falling a few blocks behind is tolerated.
+	info.TipHeight = 103
+	m.check()
+	require.Zero(t, lagging)
+
+	// Exceeding the block lag puts us into protective mode, but only
+	// notifies once while the backend keeps lagging.
+	info.TipHeight = 104
+	m.check()
+	m.check()
+	require.Equal(t, 1, lagging)
+	require.ErrorContains(t, m.CheckLag(), "4 blocks behind")
+
+	// Catching up resumes normal operation.
+	info.BestBlockHeight = 104
+	m.check()
+	require.Equal(t, 1, recovered)
+	require.NoError(t, m.CheckLag())
+
+	// A backend that stalls without knowing about new blocks is caught by
+	// the time lag, even if it can't be reached anymore.
+	info.Connected = false
+	testClock.SetTime(testClock.Now().Add(time.Hour + time.Second))
+	m.check()
+	require.Equal(t, 2, lagging)
+
+	// A new block resets the time lag.
+	info.Connected = true
+	info.BestBlockHeight = 105
+	info.TipHeight = 105
+	m.check()
+	require.Equal(t, 2, recovered)
+}
@@ -3,6 +3,7 @@ package chanbackup
 import (
 	"fmt"
 	"net"
+	"sort"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
@@ -105,3 +106,60 @@ func FetchStaticChanBackups(chanSource LiveChannelSource,
 
 	return staticChanBackups, nil
 }
+
+// BackupDiff describes how the set of channels in a stored multi-channel backup
+// differs from the set of live channels.
+type BackupDiff struct {
+	// MissingChans are the live channels that aren't part of the stored
+	// backup.
+	MissingChans []wire.OutPoint
+
+	// StaleChans are the channels in the stored backup that aren't live
+	// anymore.
+	StaleChans []wire.OutPoint
+}
+
+// InSync returns true if the stored backup contains exactly the live channels.
+func (d *BackupDiff) InSync() bool {
+	return len(d.MissingChans) == 0 && len(d.StaleChans) == 0
+}
+
+// CompareBackups compares the channels of the live static channel backups to
+// the channels of the stored backups. Only the set of channel points is
+// compared, so the order of the backups doesn't matter.
+func CompareBackups(live, stored []Single) *BackupDiff {
+	liveChans := make(map[wire.OutPoint]struct{}, len(live))
+	for _, single := range live {
+		liveChans[single.FundingOutpoint] = struct{}{}
+	}
+
+	storedChans := make(map[wire.OutPoint]struct{}, len(stored))
+	for _, single := range stored {
+		storedChans[single.FundingOutpoint] = struct{}{}
+	}
+
+	diff := &BackupDiff{}
+	for chanPoint := range liveChans {
+		if _, ok := storedChans[chanPoint]; !ok {
+			diff.MissingChans = append(diff.MissingChans, chanPoint)
+		}
+	}
+	for chanPoint := range storedChans {
+		if _, ok := liveChans[chanPoint]; !ok {
+			diff.StaleChans = append(diff.StaleChans, chanPoint)
+		}
+	}
+
+	// Sort the channel points so the result is deterministic.
+	sortOutPoints(diff.MissingChans)
+	sortOutPoints(diff.StaleChans)
+
+	return diff
+}
+
+// sortOutPoints sorts the given outpoints by their string representation.
+func sortOutPoints(outPoints []wire.OutPoint) {
+	sort.Slice(outPoints, func(i, j int) bool {
+		return outPoints[i].String() < outPoints[j].String()
+	})
+}
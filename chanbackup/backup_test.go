@@ -191,3 +191,42 @@ func TestFetchStaticChanBackups(t *testing.T) {
 		t.Fatalf("query should fail")
 	}
 }
+
+// TestCompareBackups tests that the channels that differ between the live and
+// stored backups are reported regardless of their order.
+func TestCompareBackups(t *testing.T) {
+	t.Parallel()
+
+	chanA := wire.OutPoint{Index: 1}
+	chanB := wire.OutPoint{Index: 2}
+	chanC := wire.OutPoint{Index: 3}
+	chanD := wire.OutPoint{Index: 4}
+
+	singles := func(chanPoints ...wire.OutPoint) []Single {
+		var backups []Single
+		for _, chanPoint := range chanPoints {
+			backups = append(backups, Single{
+				FundingOutpoint: chanPoint,
+			})
+		}
+
+		return backups
+	}
+
+	// The same channels in a different order are in sync.
+	diff := CompareBackups(
+		singles(chanA, chanB, chanC), singles(chanC, chanA, chanB),
+	)
+	require.True(t, diff.InSync())
+	require.Empty(t, diff.MissingChans)
+	require.Empty(t, diff.StaleChans)
+
+	// Channels that are only live are missing from the stored backup,
+	// channels that are only stored are stale.
+	diff = CompareBackups(
+		singles(chanD, chanA, chanB), singles(chanC, chanA),
+	)
+	require.False(t, diff.InSync())
+	require.Equal(t, []wire.OutPoint{chanB, chanD}, diff.MissingChans)
+	require.Equal(t, []wire.OutPoint{chanC}, diff.StaleChans)
+}
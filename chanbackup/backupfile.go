@@ -147,3 +147,54 @@ func (b *MultiFile) ExtractMulti(keyChain keychain.KeyRing) (*Multi, error) {
 	packedMulti := PackedMulti(multiBytes)
 	return packedMulti.Unpack(keyChain)
 }
+
+// WritePackedMulti atomically writes the packed multi backup to a new file with
+// the given name. The backup is first written and synced to a uniquely named
+// temporary file in the same directory, which is then renamed to the target
+// file. The target file therefore only appears once it contains the complete
+// backup, and concurrent writes of the auto updated backup file don't
+// interfere with the staging file. An existing file is never overwritten, so
+// the caller can't replace the auto updated backup file or any other file lnd
+// has access to.
+func WritePackedMulti(fileName string, backup PackedMulti) error {
+	if fileName == "" {
+		return ErrNoBackupFileExists
+	}
+
+	if _, err := os.Lstat(fileName); err == nil {
+		return fmt.Errorf("backup file %v already exists", fileName)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(
+		filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp",
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create temp file: %w", err)
+	}
+
+	// If anything fails before the rename, we don't leave the temp file
+	// behind. Once renamed, the temporary name no longer exists.
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(backup); err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("unable to write backup to temp file: %w",
+			err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("unable to sync temp file: %w", err)
+	}
+
+	// Some OSes don't support renaming a file that's still open
+	// (Windows), so we close it first.
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("unable to close temp file: %w", err)
+	}
+
+	log.Infof("Writing multi backup file to %v", fileName)
+
+	return os.Rename(tempFile.Name(), fileName)
+}
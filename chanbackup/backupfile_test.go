@@ -274,3 +274,39 @@ func TestExtractMulti(t *testing.T) {
 		assertMultiEqual(t, &unpackedMulti, freshUnpackedMulti)
 	}
 }
+
+// TestWritePackedMulti tests that a packed multi backup is written atomically
+// to a new file, that an existing file is never replaced and that no temporary
+// files are left behind.
+func TestWritePackedMulti(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	fileName := filepath.Join(tempDir, "snapshot.backup")
+
+	require.ErrorIs(t, WritePackedMulti("", nil), ErrNoBackupFileExists)
+
+	backup, err := makeFakePackedMulti()
+	require.NoError(t, err)
+
+	require.NoError(t, WritePackedMulti(fileName, backup))
+	assertBackupMatches(t, fileName, backup)
+
+	// A second backup to the same file is refused and the first backup
+	// stays untouched.
+	newBackup, err := makeFakePackedMulti()
+	require.NoError(t, err)
+
+	require.Error(t, WritePackedMulti(fileName, newBackup))
+	assertBackupMatches(t, fileName, backup)
+
+	files, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Writing into a directory that doesn't exist fails.
+	err = WritePackedMulti(
+		filepath.Join(tempDir, "missing", "snapshot.backup"), nil,
+	)
+	require.Error(t, err)
+}
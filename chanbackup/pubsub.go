@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -93,6 +94,16 @@ type SubSwapper struct {
 	// multi backup.
 	keyRing keychain.KeyRing
 
+	// debounce is the quiet period after a channel change before the
+	// backup file is updated. Changes within this period are coalesced
+	// into a single update. If zero, the file is updated on every change.
+	debounce time.Duration
+
+	// maxWait is the maximum time a debounced update of the backup file
+	// is delayed, so that continuous channel changes can't hold back the
+	// update indefinitely.
+	maxWait time.Duration
+
 	Swapper
 
 	quit chan struct{}
@@ -102,9 +113,12 @@ type SubSwapper struct {
 // NewSubSwapper creates a new instance of the SubSwapper given the starting
 // set of channels, and the required interfaces to be notified of new channel
 // updates, pack a multi backup, and swap the current best backup from its
-// storage location.
+// storage location. Updates of the backup are delayed until no channel changed
+// for the debounce duration, but at most for maxWait after the first pending
+// change. A zero debounce duration updates it on every change.
 func NewSubSwapper(startingChans []Single, chanNotifier ChannelNotifier,
-	keyRing keychain.KeyRing, backupSwapper Swapper) (*SubSwapper, error) {
+	keyRing keychain.KeyRing, backupSwapper Swapper, debounce,
+	maxWait time.Duration) (*SubSwapper, error) {
 
 	// First, we'll subscribe to the latest set of channel updates given
 	// the set of channels we already know of.
@@ -128,6 +142,8 @@ func NewSubSwapper(startingChans []Single, chanNotifier ChannelNotifier,
 		backupState: backupState,
 		chanEvents:  chanEvents,
 		keyRing:     keyRing,
+		debounce:    debounce,
+		maxWait:     maxWait,
 		Swapper:     backupSwapper,
 		quit:        make(chan struct{}),
 	}, nil
@@ -252,6 +268,42 @@ func (s *SubSwapper) backupUpdater() {
 
 	log.Debugf("SubSwapper's backupUpdater is active!")
 
+	var (
+		// pendingUpdate is true if the backup state changed since the
+		// last update of the backup file.
+		pendingUpdate bool
+
+		// pendingClosed are the channels that were closed since the
+		// last update of the backup file.
+		pendingClosed []wire.OutPoint
+
+		// debounceTimer fires once the debounce period after the last
+		// channel change has passed. It is nil if no update is
+		// pending.
+		debounceTimer <-chan time.Time
+
+		// maxWaitTimer fires once the maximum delay after the first
+		// pending channel change has passed. It is nil if no update is
+		// pending.
+		maxWaitTimer <-chan time.Time
+	)
+
+	// flush updates the on-disk backup state with any pending changes.
+	flush := func() {
+		if !pendingUpdate {
+			return
+		}
+
+		if err := s.updateBackupFile(pendingClosed...); err != nil {
+			log.Errorf("unable to update backup file: %v", err)
+		}
+
+		pendingUpdate = false
+		pendingClosed = nil
+		debounceTimer = nil
+		maxWaitTimer = nil
+	}
+
 	for {
 		select {
 		// The channel state has been modified! We'll evaluate all
@@ -273,9 +325,6 @@ func (s *SubSwapper) backupUpdater() {
 
 			// For all closed channels, we'll remove the prior
 			// backup state.
-			closedChans := make(
-				[]wire.OutPoint, 0, len(chanUpdate.ClosedChans),
-			)
 			for i, closedChan := range chanUpdate.ClosedChans {
 				log.Debugf("Removing channel %v from backup "+
 					"state", newLogClosure(func() string {
@@ -284,8 +333,11 @@ func (s *SubSwapper) backupUpdater() {
 
 				delete(s.backupState, closedChan)
 
-				closedChans = append(closedChans, closedChan)
+				pendingClosed = append(
+					pendingClosed, closedChan,
+				)
 			}
+			pendingUpdate = true
 
 			newStateSize := len(s.backupState)
 
@@ -294,17 +346,43 @@ func (s *SubSwapper) backupUpdater() {
 				oldStateSize, newStateSize)
 
 			// With out new state constructed, we'll, atomically
-			// update the on-disk backup state.
-			if err := s.updateBackupFile(closedChans...); err != nil {
-				log.Errorf("unable to update backup file: %v",
-					err)
+			// update the on-disk backup state. If we debounce
+			// updates, we'll wait until no further changes happen
+			// for the debounce period instead.
+			if s.debounce == 0 {
+				flush()
+				continue
+			}
+
+			log.Debugf("Delaying update of backup file by %v",
+				s.debounce)
+
+			debounceTimer = time.After(s.debounce)
+			if maxWaitTimer == nil {
+				maxWaitTimer = time.After(s.maxWait)
 			}
 
+		// No channel changed during the debounce period, so we'll
+		// write all pending changes at once.
+		case <-debounceTimer:
+			flush()
+
+		// Channels kept changing for the maximum delay, so we'll
+		// write the pending changes without waiting for a quiet
+		// period.
+		case <-maxWaitTimer:
+			log.Debugf("Updating backup file after max delay of %v",
+				s.maxWait)
+
+			flush()
+
 		// TODO(roasbeef): refresh periodically on a time basis due to
 		// possible addr changes from node
 
-		// Exit at once if a quit signal is detected.
+		// Exit at once if a quit signal is detected, after writing
+		// any debounced changes so they aren't lost.
 		case <-s.quit:
+			flush()
 			return
 		}
 	}
@@ -88,7 +88,7 @@ func TestNewSubSwapperSubscribeFail(t *testing.T) {
 		fail: true,
 	}
 
-	_, err := NewSubSwapper(nil, &chanNotifier, keyRing, &swapper)
+	_, err := NewSubSwapper(nil, &chanNotifier, keyRing, &swapper, 0, 0)
 	if err == nil {
 		t.Fatalf("expected fail due to lack of subscription")
 	}
@@ -158,7 +158,9 @@ func TestSubSwapperIdempotentStartStop(t *testing.T) {
 	var chanNotifier mockChannelNotifier
 
 	swapper := newMockSwapper(keyRing)
-	subSwapper, err := NewSubSwapper(nil, &chanNotifier, keyRing, swapper)
+	subSwapper, err := NewSubSwapper(
+		nil, &chanNotifier, keyRing, swapper, 0, 0,
+	)
 	require.NoError(t, err, "unable to init subSwapper")
 
 	if err := subSwapper.Start(); err != nil {
@@ -224,7 +226,7 @@ func TestSubSwapperUpdater(t *testing.T) {
 	// With our channel set created, we'll make a fresh sub swapper
 	// instance to begin our test.
 	subSwapper, err := NewSubSwapper(
-		initialChanSet, chanNotifier, keyRing, swapper,
+		initialChanSet, chanNotifier, keyRing, swapper, 0, 0,
 	)
 	require.NoError(t, err, "unable to make swapper")
 	if err := subSwapper.Start(); err != nil {
@@ -278,3 +280,100 @@ func TestSubSwapperUpdater(t *testing.T) {
 	// sub-swapper switches the new set with the old.
 	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
 }
+
+// TestSubSwapperDebounce tests that channel changes within the debounce period
+// are coalesced into a single update of the backup file, and that a pending
+// update is written when the SubSwapper is stopped.
+func TestSubSwapperDebounce(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &lnencrypt.MockKeyRing{}
+	chanNotifier := newMockChannelNotifier()
+	swapper := newMockSwapper(keyRing)
+
+	// We use a debounce period that won't pass during the test, so the
+	// changes are only written on shutdown.
+	subSwapper, err := NewSubSwapper(
+		nil, chanNotifier, keyRing, swapper, time.Hour, time.Hour,
+	)
+	require.NoError(t, err, "unable to make swapper")
+	require.NoError(t, subSwapper.Start())
+
+	// The initial channel state is written right away.
+	backupSet := make(map[wire.OutPoint]Single)
+	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
+
+	// Notify the sub-swapper about two new channels in quick succession.
+	for i := 0; i < 2; i++ {
+		newChannel, err := genRandomOpenChannelShell()
+		require.NoError(t, err, "unable to create new chan")
+
+		select {
+		case chanNotifier.chanEvents <- ChannelEvent{
+			NewChans: []ChannelWithAddrs{{
+				OpenChannel: newChannel,
+			}},
+		}:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("update swapper didn't read new channel")
+		}
+
+		backupSet[newChannel.FundingOutpoint] = NewSingle(
+			newChannel, nil,
+		)
+	}
+
+	// The backup file isn't updated within the debounce period.
+	select {
+	case <-swapper.swaps:
+		t.Fatalf("backup file updated within debounce period")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Stopping the sub-swapper writes both channels in a single update.
+	require.NoError(t, subSwapper.Stop())
+	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
+}
+
+// TestSubSwapperDebounceMaxWait tests that a debounced update of the backup
+// file is written once the maximum delay passed, even if the debounce period
+// hasn't.
+func TestSubSwapperDebounceMaxWait(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &lnencrypt.MockKeyRing{}
+	chanNotifier := newMockChannelNotifier()
+	swapper := newMockSwapper(keyRing)
+
+	subSwapper, err := NewSubSwapper(
+		nil, chanNotifier, keyRing, swapper, time.Hour,
+		100*time.Millisecond,
+	)
+	require.NoError(t, err, "unable to make swapper")
+	require.NoError(t, subSwapper.Start())
+	t.Cleanup(func() {
+		require.NoError(t, subSwapper.Stop())
+	})
+
+	backupSet := make(map[wire.OutPoint]Single)
+	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
+
+	newChannel, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to create new chan")
+
+	select {
+	case chanNotifier.chanEvents <- ChannelEvent{
+		NewChans: []ChannelWithAddrs{{
+			OpenChannel: newChannel,
+		}},
+	}:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("update swapper didn't read new channel")
+	}
+
+	backupSet[newChannel.FundingOutpoint] = NewSingle(newChannel, nil)
+
+	// The update is written after the maximum delay, long before the
+	// debounce period passed.
+	assertExpectedBackupSwap(t, swapper, subSwapper, keyRing, backupSet)
+}
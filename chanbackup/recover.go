@@ -1,9 +1,13 @@
 package chanbackup
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"sync"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -32,6 +36,194 @@ type PeerConnector interface {
 	ConnectPeer(node *btcec.PublicKey, addrs []net.Addr) error
 }
 
+// ChannelReestablisher is an optional interface of a PeerConnector. The
+// ConnectPeer method usually only initiates the connection to a peer, so the
+// recovery would move on to the next peer before the data loss recovery
+// protocol even started. If a PeerConnector also implements this interface,
+// the Recover method uses it to wait for the protocol to start once all peers
+// were connected to.
+type ChannelReestablisher interface {
+	// WaitForReestablish waits until the target node reestablished the
+	// restored channels with the given channel points, which lets it send
+	// us the data needed to recover our funds. Channels that were
+	// reestablished before the call are counted as well. The number of
+	// channels that were reestablished is returned, which may be fewer
+	// than requested if the node didn't reestablish all of them in time.
+	// The node is still connected to in the background in that case.
+	WaitForReestablish(node *btcec.PublicKey,
+		chanPoints []wire.OutPoint) (int, error)
+}
+
+// FundingOutputVerifier is an interface that allows the funding output of a
+// restored channel to be checked against the chain.
+type FundingOutputVerifier interface {
+	// VerifyFundingOutput looks up the funding output of the channel of
+	// the given backup on chain and reports whether it is still unspent
+	// and matches the backup. The lookup is aborted once the quit channel
+	// is closed.
+	VerifyFundingOutput(backup *Single,
+		quit <-chan struct{}) (FundingOutputStatus, error)
+}
+
+// FundingOutputStatus describes the state of the funding output of a channel
+// on chain at the time it was restored.
+type FundingOutputStatus uint8
+
+const (
+	// FundingOutputUnverified means that the funding output wasn't looked
+	// up on chain, either because no verification was requested or because
+	// the lookup failed.
+	FundingOutputUnverified FundingOutputStatus = iota
+
+	// FundingOutputUnspent means that the funding output exists and is
+	// still unspent, so the channel appears to be open.
+	FundingOutputUnspent
+
+	// FundingOutputSpent means that the funding output was already spent,
+	// so the channel appears to be closed.
+	FundingOutputSpent
+
+	// FundingOutputNotFound means that the funding output couldn't be
+	// found on chain.
+	FundingOutputNotFound
+
+	// FundingOutputMismatch means that an unspent output exists at the
+	// channel point, but its script or value don't match the backup.
+	FundingOutputMismatch
+
+	// FundingOutputUnconfirmed means that the channel wasn't confirmed yet
+	// when it was backed up, so its funding output wasn't looked up. The
+	// chain backends can't tell an unconfirmed output from a spent one.
+	FundingOutputUnconfirmed
+)
+
+// String returns a human readable representation of the funding output
+// status.
+func (s FundingOutputStatus) String() string {
+	switch s {
+	case FundingOutputUnverified:
+		return "unverified"
+
+	case FundingOutputUnspent:
+		return "unspent"
+
+	case FundingOutputSpent:
+		return "spent"
+
+	case FundingOutputNotFound:
+		return "not_found"
+
+	case FundingOutputMismatch:
+		return "mismatch"
+
+	case FundingOutputUnconfirmed:
+		return "unconfirmed"
+
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// DefaultRecoveryConcurrency is the default number of channel peers
+	// that are waited for in parallel during a recovery.
+	DefaultRecoveryConcurrency = 8
+
+	// MaxRecoveryConcurrency is the maximum number of channel peers that
+	// may be waited for in parallel during a recovery. Each of them holds
+	// event subscriptions of the node while it starts up.
+	MaxRecoveryConcurrency = 32
+)
+
+// RestoreFilter selects the channels of a backup that should be restored. A
+// channel is selected if either its channel point or its peer is part of the
+// filter. A nil or empty filter selects all channels.
+type RestoreFilter struct {
+	// ChanPoints are the channel points of the channels to restore.
+	ChanPoints map[wire.OutPoint]struct{}
+
+	// Peers are the compressed public keys of the peers whose channels
+	// should be restored.
+	Peers map[[33]byte]struct{}
+}
+
+// Matches returns true if the channel of the given backup should be restored.
+func (f *RestoreFilter) Matches(backup *Single) bool {
+	if f == nil || (len(f.ChanPoints) == 0 && len(f.Peers) == 0) {
+		return true
+	}
+
+	if _, ok := f.ChanPoints[backup.FundingOutpoint]; ok {
+		return true
+	}
+
+	if backup.RemoteNodePub == nil {
+		return false
+	}
+
+	var peer [33]byte
+	copy(peer[:], backup.RemoteNodePub.SerializeCompressed())
+	_, ok := f.Peers[peer]
+
+	return ok
+}
+
+// RestoreStatus describes the outcome of restoring a single channel.
+type RestoreStatus uint8
+
+const (
+	// RestoreStatusRestored means that the channel shell was stored and
+	// the connection to the peer initiated.
+	RestoreStatusRestored RestoreStatus = iota
+
+	// RestoreStatusAlreadyExists means that the channel was already
+	// present in the channel DB.
+	RestoreStatusAlreadyExists
+
+	// RestoreStatusSkipped means that the channel wasn't selected by the
+	// restore filter and was left untouched.
+	RestoreStatusSkipped
+
+	// RestoreStatusFailed means that the channel shell couldn't be stored.
+	RestoreStatusFailed
+)
+
+// String returns a human readable representation of the restore status.
+func (s RestoreStatus) String() string {
+	switch s {
+	case RestoreStatusRestored:
+		return "restored"
+
+	case RestoreStatusAlreadyExists:
+		return "already_exists"
+
+	case RestoreStatusSkipped:
+		return "skipped"
+
+	case RestoreStatusFailed:
+		return "failed"
+
+	default:
+		return "unknown"
+	}
+}
+
+// RestoreResult is the outcome of restoring the channel of a single backup.
+type RestoreResult struct {
+	// ChanPoint is the channel point of the channel.
+	ChanPoint wire.OutPoint
+
+	// RemoteNodePub is the public key of the channel peer.
+	RemoteNodePub *btcec.PublicKey
+
+	// Status is the outcome of the restore.
+	Status RestoreStatus
+
+	// Err is the reason the channel couldn't be restored if the status is
+	// RestoreStatusFailed.
+	Err error
+}
+
 // Recover attempts to recover the static channel state from a set of static
 // channel backups. If successfully, the database will be populated with a
 // series of "shell" channels. These "shell" channels cannot be used to operate
@@ -43,7 +235,66 @@ type PeerConnector interface {
 func Recover(backups []Single, restorer ChannelRestorer,
 	peerConnector PeerConnector) error {
 
+	_, err := RecoverFiltered(
+		backups, nil, DefaultRecoveryConcurrency, restorer,
+		peerConnector,
+	)
+
+	return err
+}
+
+// RecoverFiltered is like Recover, but only restores the channels selected by
+// the given filter. The channels that aren't selected are left untouched. The
+// outcome for each backup is returned in the order of the backups, along with
+// the errors of all channels that couldn't be restored or whose peers couldn't
+// be connected to.
+//
+// The channel shells are stored first, then the peers of the restored channels
+// are connected to as described in ConnectChannelPeers.
+func RecoverFiltered(backups []Single, filter *RestoreFilter,
+	concurrency int, restorer ChannelRestorer,
+	peerConnector PeerConnector) ([]RestoreResult, error) {
+
+	results, restoreErr := RestoreFiltered(backups, filter, restorer)
+
+	// A channel that couldn't be restored doesn't keep us from recovering
+	// the ones that were.
+	connectErr := ConnectChannelPeers(
+		RestoredBackups(backups, results), concurrency, peerConnector,
+	)
+
+	return results, errors.Join(restoreErr, connectErr)
+}
+
+// RestoreFiltered stores the channel shells of the channels selected by the
+// given filter without connecting to their peers. The outcome for each backup
+// is returned in the order of the backups. A channel that can't be restored is
+// reported as failed in its result and doesn't keep the remaining channels
+// from being restored. The errors of all failed channels are returned along
+// with the results.
+func RestoreFiltered(backups []Single, filter *RestoreFilter,
+	restorer ChannelRestorer) ([]RestoreResult, error) {
+
+	var (
+		results = make([]RestoreResult, 0, len(backups))
+		errs    []error
+	)
 	for i, backup := range backups {
+		result := RestoreResult{
+			ChanPoint:     backup.FundingOutpoint,
+			RemoteNodePub: backup.RemoteNodePub,
+		}
+
+		if !filter.Matches(&backups[i]) {
+			log.Infof("Skipping ChannelPoint(%v), not selected "+
+				"by restore filter", backup.FundingOutpoint)
+
+			result.Status = RestoreStatusSkipped
+			results = append(results, result)
+
+			continue
+		}
+
 		log.Infof("Restoring ChannelPoint(%v) to disk: ",
 			backup.FundingOutpoint)
 
@@ -54,34 +305,264 @@ func Recover(backups []Single, restorer ChannelRestorer,
 		// for example. This allows resume of a restore in case another
 		// error happens.
 		if err == channeldb.ErrChanAlreadyExists {
+			result.Status = RestoreStatusAlreadyExists
+			results = append(results, result)
+
 			continue
 		}
 		if err != nil {
-			return err
+			log.Errorf("Unable to restore ChannelPoint(%v): %v",
+				backup.FundingOutpoint, err)
+
+			result.Status = RestoreStatusFailed
+			result.Err = err
+			results = append(results, result)
+
+			errs = append(errs, fmt.Errorf("unable to restore "+
+				"ChannelPoint(%v): %w", backup.FundingOutpoint,
+				err))
+
+			continue
+		}
+
+		result.Status = RestoreStatusRestored
+		results = append(results, result)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// RestoredBackups returns the backups whose channels were restored according
+// to the given results of RestoreFiltered.
+func RestoredBackups(backups []Single, results []RestoreResult) []Single {
+	var restored []Single
+	for i, result := range results {
+		if result.Status == RestoreStatusRestored {
+			restored = append(restored, backups[i])
 		}
+	}
+
+	return restored
+}
+
+// channelPeer is the peer of one or more restored channels.
+type channelPeer struct {
+	// node is the public key of the peer.
+	node *btcec.PublicKey
+
+	// addrs are the addresses of the peer found in the backups of its
+	// channels.
+	addrs []net.Addr
+
+	// chanPoints are the channel points of the restored channels with the
+	// peer.
+	chanPoints []wire.OutPoint
+}
+
+// groupByPeer groups the channels of the given backups by their peer, in the
+// order in which the peers first appear in the backups.
+func groupByPeer(backups []Single) []*channelPeer {
+	var (
+		peers  []*channelPeer
+		byNode = make(map[[33]byte]*channelPeer)
+	)
+	for _, backup := range backups {
+		var node [33]byte
+		copy(node[:], backup.RemoteNodePub.SerializeCompressed())
+
+		peer, ok := byNode[node]
+		if !ok {
+			peer = &channelPeer{node: backup.RemoteNodePub}
+			byNode[node] = peer
+			peers = append(peers, peer)
+		}
+
+		for _, addr := range backup.Addresses {
+			known := false
+			for _, peerAddr := range peer.addrs {
+				if peerAddr.String() == addr.String() {
+					known = true
+					break
+				}
+			}
+			if !known {
+				peer.addrs = append(peer.addrs, addr)
+			}
+		}
+
+		peer.chanPoints = append(
+			peer.chanPoints, backup.FundingOutpoint,
+		)
+	}
+
+	return peers
+}
+
+// ConnectChannelPeers connects to the peers of the given restored channels to
+// start the data loss recovery protocol. Each peer is connected to once for
+// all of its channels. All connection requests are issued up front, and a
+// peer that can't be connected to doesn't keep us from connecting to the
+// others. The errors of all failed connection attempts are returned.
+//
+// If the connector is a ChannelReestablisher, we then wait for the connected
+// peers to reestablish their channels, up to concurrency peers in parallel.
+func ConnectChannelPeers(backups []Single, concurrency int,
+	peerConnector PeerConnector) error {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		peers     = groupByPeer(backups)
+		connected []*channelPeer
+		errs      []error
+	)
+	for _, peer := range peers {
+		node := peer.node.SerializeCompressed()
 
 		log.Infof("Attempting to connect to node=%x (addrs=%v) to "+
-			"restore ChannelPoint(%v)",
-			backup.RemoteNodePub.SerializeCompressed(),
+			"restore %d channel(s)", node,
 			newLogClosure(func() string {
-				return spew.Sdump(backups[i].Addresses)
-			}), backup.FundingOutpoint)
+				return spew.Sdump(peer.addrs)
+			}), len(peer.chanPoints))
 
-		err = peerConnector.ConnectPeer(
-			backup.RemoteNodePub, backup.Addresses,
-		)
+		err := peerConnector.ConnectPeer(peer.node, peer.addrs)
 		if err != nil {
-			return err
+			log.Errorf("Unable to connect to node=%x to restore "+
+				"%d channel(s): %v", node, len(peer.chanPoints),
+				err)
+
+			errs = append(errs, fmt.Errorf("unable to connect to "+
+				"node=%x: %w", node, err))
+
+			continue
 		}
 
-		// TODO(roasbeef): to handle case where node has changed addrs,
-		// need to subscribe to new updates for target node pub to
-		// attempt to connect to other addrs
-		//
-		//  * just to to fresh w/ call to node addrs and de-dup?
+		connected = append(connected, peer)
+	}
+
+	log.Infof("Initiated connection to %d of %d channel peers",
+		len(connected), len(peers))
+
+	reestablisher, ok := peerConnector.(ChannelReestablisher)
+	if !ok {
+		return errors.Join(errs...)
 	}
 
-	return nil
+	var (
+		wg               sync.WaitGroup
+		mtx              sync.Mutex
+		numPeersDone     int
+		numReestablished int
+
+		// slots limits the number of peers waited for at once.
+		slots = make(chan struct{}, concurrency)
+	)
+	for _, peer := range connected {
+		slots <- struct{}{}
+
+		wg.Add(1)
+		go func(peer *channelPeer) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			node := peer.node.SerializeCompressed()
+			reestablished, err := reestablisher.WaitForReestablish(
+				peer.node, peer.chanPoints,
+			)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			numPeersDone++
+			numReestablished += reestablished
+
+			if err != nil {
+				log.Errorf("Unable to wait for node=%x to "+
+					"reestablish %d channel(s): %v", node,
+					len(peer.chanPoints), err)
+
+				errs = append(errs, fmt.Errorf("unable to "+
+					"wait for node=%x: %w", node, err))
+
+				return
+			}
+
+			log.Infof("Node=%x reestablished %d of %d restored "+
+				"channel(s), %d of %d channels reestablished "+
+				"with %d of %d channel peers", node,
+				reestablished, len(peer.chanPoints),
+				numReestablished, len(backups), numPeersDone,
+				len(connected))
+		}(peer)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// VerifyFundingOutputs checks the funding outputs of the channels of the given
+// backups on chain one after another and logs the outcome. The status of each
+// funding output is returned in the order of the backups. Once the quit channel
+// is closed, the remaining funding outputs are left unverified.
+func VerifyFundingOutputs(backups []Single, verifier FundingOutputVerifier,
+	quit <-chan struct{}) []FundingOutputStatus {
+
+	statuses := make([]FundingOutputStatus, len(backups))
+	for i := range backups {
+		select {
+		case <-quit:
+			return statuses
+		default:
+		}
+
+		statuses[i] = VerifyFundingOutput(&backups[i], verifier, quit)
+	}
+
+	return statuses
+}
+
+// VerifyFundingOutput checks the funding output of the channel of the given
+// backup on chain and logs the outcome. A failed lookup is only logged and
+// reported as unverified, as it must not prevent the channel from being
+// recovered. Channels that appear to be closed are only reported too, as the
+// recovery may still be needed to sweep our funds. The lookup is aborted once
+// the quit channel is closed.
+func VerifyFundingOutput(backup *Single, verifier FundingOutputVerifier,
+	quit <-chan struct{}) FundingOutputStatus {
+
+	status, err := verifier.VerifyFundingOutput(backup, quit)
+	if err != nil {
+		log.Warnf("Unable to verify funding output of "+
+			"ChannelPoint(%v) on chain: %v", backup.FundingOutpoint,
+			err)
+
+		return FundingOutputUnverified
+	}
+
+	switch status {
+	case FundingOutputUnspent:
+		log.Infof("Funding output of ChannelPoint(%v) is unspent",
+			backup.FundingOutpoint)
+
+	case FundingOutputSpent:
+		log.Warnf("Funding output of ChannelPoint(%v) is already "+
+			"spent, the channel appears to be closed",
+			backup.FundingOutpoint)
+
+	case FundingOutputUnconfirmed:
+		log.Infof("ChannelPoint(%v) was unconfirmed when it was "+
+			"backed up, not looking up its funding output",
+			backup.FundingOutpoint)
+
+	default:
+		log.Warnf("Funding output of ChannelPoint(%v) doesn't match "+
+			"the backup: %v", backup.FundingOutpoint, status)
+	}
+
+	return status
 }
 
 // TODO(roasbeef): more specific keychain interface?
@@ -91,9 +572,10 @@ func Recover(backups []Single, restorer ChannelRestorer,
 // and also reach out to connect to any of the known node addresses for that
 // channel. It is assumes that after this method exists, if a connection we
 // able to be established, then then PeerConnector will continue to attempt to
-// re-establish a persistent connection in the background.
+// re-establish a persistent connection in the background. Up to concurrency
+// channel peers are connected to in parallel.
 func UnpackAndRecoverSingles(singles PackedSingles,
-	keyChain keychain.KeyRing, restorer ChannelRestorer,
+	keyChain keychain.KeyRing, concurrency int, restorer ChannelRestorer,
 	peerConnector PeerConnector) error {
 
 	chanBackups, err := singles.Unpack(keyChain)
@@ -101,7 +583,11 @@ func UnpackAndRecoverSingles(singles PackedSingles,
 		return err
 	}
 
-	return Recover(chanBackups, restorer, peerConnector)
+	_, err = RecoverFiltered(
+		chanBackups, nil, concurrency, restorer, peerConnector,
+	)
+
+	return err
 }
 
 // UnpackAndRecoverMulti is a one-shot method, that given a set of packed
@@ -109,9 +595,10 @@ func UnpackAndRecoverSingles(singles PackedSingles,
 // and also reach out to connect to any of the known node addresses for that
 // channel. It is assumes that after this method exists, if a connection we
 // able to be established, then then PeerConnector will continue to attempt to
-// re-establish a persistent connection in the background.
+// re-establish a persistent connection in the background. Up to concurrency
+// channel peers are connected to in parallel.
 func UnpackAndRecoverMulti(packedMulti PackedMulti,
-	keyChain keychain.KeyRing, restorer ChannelRestorer,
+	keyChain keychain.KeyRing, concurrency int, restorer ChannelRestorer,
 	peerConnector PeerConnector) error {
 
 	chanBackups, err := packedMulti.Unpack(keyChain)
@@ -119,5 +606,10 @@ func UnpackAndRecoverMulti(packedMulti PackedMulti,
 		return err
 	}
 
-	return Recover(chanBackups.StaticBackups, restorer, peerConnector)
+	_, err = RecoverFiltered(
+		chanBackups.StaticBackups, nil, concurrency, restorer,
+		peerConnector,
+	)
+
+	return err
 }
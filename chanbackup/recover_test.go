@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/stretchr/testify/require"
 )
@@ -14,14 +17,24 @@ import (
 type mockChannelRestorer struct {
 	fail bool
 
+	// failChans are the channel points of the channels that can't be
+	// restored.
+	failChans map[wire.OutPoint]struct{}
+
 	callCount int
 }
 
-func (m *mockChannelRestorer) RestoreChansFromSingles(...Single) error {
+func (m *mockChannelRestorer) RestoreChansFromSingles(backups ...Single) error {
 	if m.fail {
 		return fmt.Errorf("fail")
 	}
 
+	for _, backup := range backups {
+		if _, ok := m.failChans[backup.FundingOutpoint]; ok {
+			return fmt.Errorf("fail")
+		}
+	}
+
 	m.callCount++
 
 	return nil
@@ -45,6 +58,26 @@ func (m *mockPeerConnector) ConnectPeer(node *btcec.PublicKey,
 	return nil
 }
 
+type mockFundingOutputVerifier struct {
+	statuses map[wire.OutPoint]FundingOutputStatus
+
+	fail bool
+
+	callCount int
+}
+
+func (m *mockFundingOutputVerifier) VerifyFundingOutput(backup *Single,
+	_ <-chan struct{}) (FundingOutputStatus, error) {
+
+	m.callCount++
+
+	if m.fail {
+		return 0, fmt.Errorf("fail")
+	}
+
+	return m.statuses[backup.FundingOutpoint], nil
+}
+
 // TestUnpackAndRecoverSingles tests that we're able to properly unpack and
 // recover a set of packed singles.
 func TestUnpackAndRecoverSingles(t *testing.T) {
@@ -84,7 +117,8 @@ func TestUnpackAndRecoverSingles(t *testing.T) {
 	// as well
 	chanRestorer.fail = true
 	err := UnpackAndRecoverSingles(
-		packedBackups, keyRing, &chanRestorer, &peerConnector,
+		packedBackups, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("restoration should have failed")
@@ -96,19 +130,23 @@ func TestUnpackAndRecoverSingles(t *testing.T) {
 	// well
 	peerConnector.fail = true
 	err = UnpackAndRecoverSingles(
-		packedBackups, keyRing, &chanRestorer, &peerConnector,
+		packedBackups, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("restoration should have failed")
 	}
 
-	chanRestorer.callCount--
+	// All channel shells are stored before the peers are connected to.
+	require.Equal(t, numSingles, chanRestorer.callCount)
+	chanRestorer.callCount = 0
 	peerConnector.fail = false
 
 	// Next, we'll ensure that if all the interfaces function as expected,
 	// then the channels will properly be unpacked and restored.
 	err = UnpackAndRecoverSingles(
-		packedBackups, keyRing, &chanRestorer, &peerConnector,
+		packedBackups, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	require.NoError(t, err, "unable to recover chans")
 
@@ -126,7 +164,8 @@ func TestUnpackAndRecoverSingles(t *testing.T) {
 	// If we modify the keyRing, then unpacking should fail.
 	keyRing.Fail = true
 	err = UnpackAndRecoverSingles(
-		packedBackups, keyRing, &chanRestorer, &peerConnector,
+		packedBackups, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("unpacking should have failed")
@@ -178,7 +217,8 @@ func TestUnpackAndRecoverMulti(t *testing.T) {
 	// as well
 	chanRestorer.fail = true
 	err := UnpackAndRecoverMulti(
-		packedMulti, keyRing, &chanRestorer, &peerConnector,
+		packedMulti, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("restoration should have failed")
@@ -190,19 +230,23 @@ func TestUnpackAndRecoverMulti(t *testing.T) {
 	// well
 	peerConnector.fail = true
 	err = UnpackAndRecoverMulti(
-		packedMulti, keyRing, &chanRestorer, &peerConnector,
+		packedMulti, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("restoration should have failed")
 	}
 
-	chanRestorer.callCount--
+	// All channel shells are stored before the peers are connected to.
+	require.Equal(t, numSingles, chanRestorer.callCount)
+	chanRestorer.callCount = 0
 	peerConnector.fail = false
 
 	// Next, we'll ensure that if all the interfaces function as expected,
 	// then the channels will properly be unpacked and restored.
 	err = UnpackAndRecoverMulti(
-		packedMulti, keyRing, &chanRestorer, &peerConnector,
+		packedMulti, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	require.NoError(t, err, "unable to recover chans")
 
@@ -220,7 +264,8 @@ func TestUnpackAndRecoverMulti(t *testing.T) {
 	// If we modify the keyRing, then unpacking should fail.
 	keyRing.Fail = true
 	err = UnpackAndRecoverMulti(
-		packedMulti, keyRing, &chanRestorer, &peerConnector,
+		packedMulti, keyRing, DefaultRecoveryConcurrency,
+		&chanRestorer, &peerConnector,
 	)
 	if err == nil {
 		t.Fatalf("unpacking should have failed")
@@ -228,3 +273,310 @@ func TestUnpackAndRecoverMulti(t *testing.T) {
 
 	// TODO(roasbeef): verify proper call args
 }
+
+// TestRecoverFiltered tests that only the channels selected by the restore
+// filter are restored, and that the outcome is reported for each channel.
+func TestRecoverFiltered(t *testing.T) {
+	t.Parallel()
+
+	const numSingles = 4
+	backups := make([]Single, 0, numSingles)
+	for i := 0; i < numSingles; i++ {
+		channel, err := genRandomOpenChannelShell()
+		require.NoError(t, err)
+
+		backups = append(backups, NewSingle(channel, nil))
+	}
+
+	// Select the first channel by its channel point and the second one by
+	// its peer.
+	var peer [33]byte
+	copy(peer[:], backups[1].RemoteNodePub.SerializeCompressed())
+	filter := &RestoreFilter{
+		ChanPoints: map[wire.OutPoint]struct{}{
+			backups[0].FundingOutpoint: {},
+		},
+		Peers: map[[33]byte]struct{}{
+			peer: {},
+		},
+	}
+
+	chanRestorer := mockChannelRestorer{}
+	peerConnector := mockPeerConnector{}
+	results, err := RecoverFiltered(
+		backups, filter, DefaultRecoveryConcurrency, &chanRestorer,
+		&peerConnector,
+	)
+	require.NoError(t, err)
+
+	// Only the selected channels are restored, the others are skipped.
+	require.Equal(t, 2, chanRestorer.callCount)
+	require.Equal(t, 2, peerConnector.callCount)
+
+	expectedStatus := []RestoreStatus{
+		RestoreStatusRestored, RestoreStatusRestored,
+		RestoreStatusSkipped, RestoreStatusSkipped,
+	}
+	require.Len(t, results, numSingles)
+	for i, result := range results {
+		require.Equal(t, backups[i].FundingOutpoint, result.ChanPoint)
+		require.Equal(t, expectedStatus[i], result.Status)
+	}
+
+	// Without a filter, all channels are restored.
+	results, err = RecoverFiltered(
+		backups, nil, DefaultRecoveryConcurrency, &chanRestorer,
+		&peerConnector,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, numSingles)
+	require.Equal(t, 2+numSingles, chanRestorer.callCount)
+	for _, result := range results {
+		require.Equal(t, RestoreStatusRestored, result.Status)
+	}
+
+	// A channel that can't be restored is reported as failed along with
+	// the error, while the remaining channels are still restored and their
+	// peers connected to.
+	chanRestorer = mockChannelRestorer{
+		failChans: map[wire.OutPoint]struct{}{
+			backups[1].FundingOutpoint: {},
+		},
+	}
+	peerConnector = mockPeerConnector{}
+	results, err = RecoverFiltered(
+		backups, nil, DefaultRecoveryConcurrency, &chanRestorer,
+		&peerConnector,
+	)
+	require.ErrorContains(t, err, backups[1].FundingOutpoint.String())
+	require.Len(t, results, numSingles)
+	require.Equal(t, numSingles-1, chanRestorer.callCount)
+	require.Equal(t, numSingles-1, peerConnector.callCount)
+	for i, result := range results {
+		if i == 1 {
+			require.Equal(t, RestoreStatusFailed, result.Status)
+			require.Error(t, result.Err)
+
+			continue
+		}
+
+		require.Equal(t, RestoreStatusRestored, result.Status)
+		require.NoError(t, result.Err)
+	}
+}
+
+// TestVerifyFundingOutputs tests that the funding outputs of a set of backups
+// are verified in order, that a failed verification leaves them unverified and
+// that the verification stops once the quit channel is closed.
+func TestVerifyFundingOutputs(t *testing.T) {
+	t.Parallel()
+
+	const numSingles = 3
+	backups := make([]Single, 0, numSingles)
+	for i := 0; i < numSingles; i++ {
+		channel, err := genRandomOpenChannelShell()
+		require.NoError(t, err)
+
+		backups = append(backups, NewSingle(channel, nil))
+	}
+
+	verifier := &mockFundingOutputVerifier{
+		statuses: map[wire.OutPoint]FundingOutputStatus{
+			backups[0].FundingOutpoint: FundingOutputUnconfirmed,
+			backups[1].FundingOutpoint: FundingOutputSpent,
+			backups[2].FundingOutpoint: FundingOutputUnspent,
+		},
+	}
+
+	statuses := VerifyFundingOutputs(backups, verifier, nil)
+	require.Equal(t, []FundingOutputStatus{
+		FundingOutputUnconfirmed, FundingOutputSpent,
+		FundingOutputUnspent,
+	}, statuses)
+
+	verifier.fail = true
+	statuses = VerifyFundingOutputs(backups, verifier, nil)
+	require.Equal(t, make([]FundingOutputStatus, numSingles), statuses)
+
+	quit := make(chan struct{})
+	close(quit)
+
+	verifier.fail = false
+	verifier.callCount = 0
+	statuses = VerifyFundingOutputs(backups, verifier, quit)
+	require.Zero(t, verifier.callCount)
+	require.Equal(t, make([]FundingOutputStatus, numSingles), statuses)
+}
+
+// recordingPeerConnector is a PeerConnector that records the peers connected
+// to and fails to connect to the given peers.
+type recordingPeerConnector struct {
+	mtx sync.Mutex
+
+	connected map[[33]byte]int
+	failPeers map[[33]byte]struct{}
+	callCount int
+}
+
+func (c *recordingPeerConnector) ConnectPeer(node *btcec.PublicKey,
+	addrs []net.Addr) error {
+
+	var peer [33]byte
+	copy(peer[:], node.SerializeCompressed())
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.callCount++
+	if _, ok := c.failPeers[peer]; ok {
+		return fmt.Errorf("fail")
+	}
+	c.connected[peer]++
+
+	return nil
+}
+
+// waitingPeerConnector is a recordingPeerConnector that also waits for the
+// channels to be reestablished, recording how many peers are waited for at
+// once and the channels of each peer.
+type waitingPeerConnector struct {
+	*recordingPeerConnector
+
+	inFlight    int
+	maxInFlight int
+
+	// connectsBeforeWait is the number of connection attempts made
+	// before the first wait.
+	connectsBeforeWait int
+
+	chanPoints map[[33]byte][]wire.OutPoint
+}
+
+func (w *waitingPeerConnector) WaitForReestablish(node *btcec.PublicKey,
+	chanPoints []wire.OutPoint) (int, error) {
+
+	var peer [33]byte
+	copy(peer[:], node.SerializeCompressed())
+
+	w.mtx.Lock()
+	if len(w.chanPoints) == 0 && w.inFlight == 0 {
+		w.connectsBeforeWait = w.callCount
+	}
+	w.inFlight++
+	if w.inFlight > w.maxInFlight {
+		w.maxInFlight = w.inFlight
+	}
+	w.mtx.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.inFlight--
+	w.chanPoints[peer] = append(w.chanPoints[peer], chanPoints...)
+
+	return len(chanPoints), nil
+}
+
+// TestRecoverConcurrency tests that all channel peers are connected to up
+// front, only once for all of their channels, and that they are waited for in
+// parallel up to the given concurrency.
+func TestRecoverConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numSingles  = 12
+		numPeers    = numSingles - 2
+		concurrency = 4
+	)
+	backups := make([]Single, 0, numSingles)
+	for i := 0; i < numSingles; i++ {
+		channel, err := genRandomOpenChannelShell()
+		require.NoError(t, err)
+
+		backups = append(backups, NewSingle(channel, nil))
+	}
+
+	// The first three channels are with the same peer.
+	backups[1].RemoteNodePub = backups[0].RemoteNodePub
+	backups[2].RemoteNodePub = backups[0].RemoteNodePub
+
+	peerKey := func(i int) [33]byte {
+		var peer [33]byte
+		copy(peer[:], backups[i].RemoteNodePub.SerializeCompressed())
+
+		return peer
+	}
+
+	chanRestorer := mockChannelRestorer{}
+	peerConnector := &recordingPeerConnector{
+		connected: make(map[[33]byte]int),
+	}
+	results, err := RecoverFiltered(
+		backups, nil, concurrency, &chanRestorer, peerConnector,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, numSingles)
+	for _, result := range results {
+		require.Equal(t, RestoreStatusRestored, result.Status)
+	}
+
+	require.Equal(t, numSingles, chanRestorer.callCount)
+	require.Equal(t, numPeers, peerConnector.callCount)
+	require.Len(t, peerConnector.connected, numPeers)
+	for _, count := range peerConnector.connected {
+		require.Equal(t, 1, count)
+	}
+
+	// If the connector waits for the channels to be reestablished, all
+	// peers are connected to before the first one is waited for, and the
+	// waiter is given all channels of a peer at once.
+	chanRestorer = mockChannelRestorer{}
+	reestablisher := &waitingPeerConnector{
+		recordingPeerConnector: &recordingPeerConnector{
+			connected: make(map[[33]byte]int),
+		},
+		chanPoints: make(map[[33]byte][]wire.OutPoint),
+	}
+	_, err = RecoverFiltered(
+		backups, nil, concurrency, &chanRestorer, reestablisher,
+	)
+	require.NoError(t, err)
+	require.Equal(t, numPeers, reestablisher.callCount)
+	require.Equal(t, numPeers, reestablisher.connectsBeforeWait)
+	require.Greater(t, reestablisher.maxInFlight, 1)
+	require.LessOrEqual(t, reestablisher.maxInFlight, concurrency)
+	require.Len(t, reestablisher.chanPoints, numPeers)
+	require.Equal(t, []wire.OutPoint{
+		backups[0].FundingOutpoint, backups[1].FundingOutpoint,
+		backups[2].FundingOutpoint,
+	}, reestablisher.chanPoints[peerKey(0)])
+
+	// If connecting to some peers fails, the other peers are still
+	// connected to and waited for, and the errors of all failed peers are
+	// returned.
+	chanRestorer = mockChannelRestorer{}
+	reestablisher = &waitingPeerConnector{
+		recordingPeerConnector: &recordingPeerConnector{
+			connected: make(map[[33]byte]int),
+			failPeers: map[[33]byte]struct{}{
+				peerKey(0): {},
+				peerKey(5): {},
+			},
+		},
+		chanPoints: make(map[[33]byte][]wire.OutPoint),
+	}
+	_, err = RecoverFiltered(
+		backups, nil, concurrency, &chanRestorer, reestablisher,
+	)
+	require.Error(t, err)
+	require.ErrorContains(t, err, fmt.Sprintf("%x", peerKey(0)))
+	require.ErrorContains(t, err, fmt.Sprintf("%x", peerKey(5)))
+	require.Equal(t, numSingles, chanRestorer.callCount)
+	require.Equal(t, numPeers, reestablisher.callCount)
+	require.Len(t, reestablisher.connected, numPeers-2)
+	require.Len(t, reestablisher.chanPoints, numPeers-2)
+	require.NotContains(t, reestablisher.chanPoints, peerKey(0))
+	require.NotContains(t, reestablisher.chanPoints, peerKey(5))
+}
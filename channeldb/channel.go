@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
@@ -245,6 +246,13 @@ const (
 	// A tlv type definition used to serialize and deserialize the
 	// Memo for the channel channel.
 	channelMemoType tlv.Type = 5
+
+	// A tlv type definition used to serialize and deserialize the time a
+	// channel was restored from a static channel backup. It's odd, so
+	// versions that don't know it can still load restored channels, and
+	// far above the types upstream allocates one after another, so a
+	// database written by a later upstream version is never misread.
+	restoredAtType tlv.Type = 65537
 )
 
 // indexStatus is an enum-like type that describes what state the
@@ -856,6 +864,11 @@ type OpenChannel struct {
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// RestoredAt is the time the channel was restored from a static
+	// channel backup. It is zero for channels that weren't restored and
+	// for channels restored by versions that didn't record it.
+	RestoredAt time.Time
+
 	// TODO(roasbeef): eww
 	Db *ChannelStateDB
 
@@ -4011,8 +4024,7 @@ func putChanInfo(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 	localBalance := uint64(channel.InitialLocalBalance)
 	remoteBalance := uint64(channel.InitialRemoteBalance)
 
-	// Create the tlv stream.
-	tlvStream, err := tlv.NewStream(
+	records := []tlv.Record{
 		// Write the RevocationKeyLocator as the first entry in a tlv
 		// stream.
 		MakeKeyLocRecord(
@@ -4026,7 +4038,18 @@ func putChanInfo(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		),
 		MakeScidRecord(realScidType, &channel.confirmedScid),
 		tlv.MakePrimitiveRecord(channelMemoType, &channel.Memo),
-	)
+	}
+
+	// The restore time is only written for restored channels.
+	if !channel.RestoredAt.IsZero() {
+		restoredAt := uint64(channel.RestoredAt.Unix())
+		records = append(records, tlv.MakePrimitiveRecord(
+			restoredAtType, &restoredAt,
+		))
+	}
+
+	// Create the tlv stream.
+	tlvStream, err := tlv.NewStream(records...)
 	if err != nil {
 		return err
 	}
@@ -4226,6 +4249,7 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		localBalance  uint64
 		remoteBalance uint64
 		memo          []byte
+		restoredAt    uint64
 	)
 
 	// Create the tlv stream.
@@ -4243,6 +4267,7 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		),
 		MakeScidRecord(realScidType, &channel.confirmedScid),
 		tlv.MakePrimitiveRecord(channelMemoType, &memo),
+		tlv.MakePrimitiveRecord(restoredAtType, &restoredAt),
 	)
 	if err != nil {
 		return err
@@ -4252,6 +4277,10 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 		return err
 	}
 
+	if restoredAt != 0 {
+		channel.RestoredAt = time.Unix(int64(restoredAt), 0)
+	}
+
 	// Attach the balance fields.
 	channel.InitialLocalBalance = lnwire.MilliSatoshi(localBalance)
 	channel.InitialRemoteBalance = lnwire.MilliSatoshi(remoteBalance)
@@ -1535,6 +1535,49 @@ func TestKeyLocatorEncoding(t *testing.T) {
 	require.Equal(t, keyLoc, decodedKeyLoc)
 }
 
+// TestRestoredAtUpstreamRecord tests that a record upstream stores next to
+// the channel info isn't read as the time the channel was restored.
+func TestRestoredAtUpstreamRecord(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+	state := createTestChannel(t, cdb)
+
+	// Upstream stores a custom blob as the record following the memo.
+	var record bytes.Buffer
+	blob := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(channelMemoType+2, &blob),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tlvStream.Encode(&record))
+
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, state.IdentityPub, &state.FundingOutpoint,
+			state.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		info := bytes.Join(
+			[][]byte{chanBucket.Get(chanInfoKey), record.Bytes()},
+			nil,
+		)
+
+		return chanBucket.Put(chanInfoKey, info)
+	}, func() {})
+	require.NoError(t, err)
+
+	// The channel still loads, and isn't considered restored.
+	require.NoError(t, state.Refresh())
+	require.True(t, state.RestoredAt.IsZero())
+}
+
 // TestFinalHtlcs tests final htlc storage and retrieval.
 func TestFinalHtlcs(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,30 @@
+package channeldb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// ErrCompactionUnsupported is returned if the database backend can't be
+// compacted while it is open.
+var ErrCompactionUnsupported = errors.New("database compaction is only " +
+	"supported by the bolt database backend")
+
+// Compact compacts the database while it is open and returns the size of the
+// database file before and after the compaction. Writes are paused for the
+// whole compaction. If the context is canceled before the compacted copy of the
+// database is swapped in, the compaction is aborted and the database is left
+// untouched.
+//
+// NOTE: Only bolt backends opened with on demand compaction enabled can be
+// compacted, ErrCompactionUnsupported is returned for all other backends.
+func (d *DB) Compact(ctx context.Context) (int64, int64, error) {
+	compacter, ok := d.Backend.(kvdb.Compacter)
+	if !ok {
+		return 0, 0, ErrCompactionUnsupported
+	}
+
+	return compacter.Compact(ctx)
+}
@@ -0,0 +1,55 @@
+package channeldb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompact asserts that a database opened with on demand compaction can be
+// compacted without losing data, and that other backends are rejected.
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	if kvdb.PostgresBackend || kvdb.SqliteBackend || kvdb.EtcdBackend {
+		t.Skip("compaction is only tested with the bolt backend")
+	}
+
+	// The default test database doesn't support compaction while it's
+	// open.
+	testDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	_, _, err = testDB.Compact(context.Background())
+	require.ErrorIs(t, err, ErrCompactionUnsupported)
+
+	backend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:          t.TempDir(),
+		DBFileName:      "channel.db",
+		NoFreelistSync:  true,
+		DBTimeout:       kvdb.DefaultDBTimeout,
+		CompactOnDemand: true,
+	})
+	require.NoError(t, err)
+
+	cdb, err := CreateWithBackend(backend)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cdb.Close())
+	})
+
+	preimage := lntypes.Preimage(rev)
+	require.NoError(t, cdb.NewWitnessCache().AddSha256Witnesses(preimage))
+
+	sizeBefore, sizeAfter, err := cdb.Compact(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, sizeBefore)
+	require.NotZero(t, sizeAfter)
+
+	// The data is still there after the compacted file was swapped in.
+	_, err = cdb.NewWitnessCache().LookupSha256Witness(preimage.Hash())
+	require.NoError(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
@@ -334,6 +335,10 @@ type DB struct {
 	// noRevLogAmtData if true, means that commitment transaction amount
 	// data should not be stored in the revocation log.
 	noRevLogAmtData bool
+
+	// preMigrationCheck, if set, is called before any migration is
+	// applied.
+	preMigrationCheck func() error
 }
 
 // Open opens or creates channeldb. Any necessary schemas migrations due
@@ -393,6 +398,7 @@ func CreateWithBackend(backend kvdb.Backend,
 		keepFailedPaymentAttempts: opts.keepFailedPaymentAttempts,
 		storeFinalHtlcResolutions: opts.storeFinalHtlcResolutions,
 		noRevLogAmtData:           opts.NoRevLogAmtData,
+		preMigrationCheck:         opts.preMigrationCheck,
 	}
 
 	// Set the parent pointer (only used in tests).
@@ -402,7 +408,7 @@ func CreateWithBackend(backend kvdb.Backend,
 	chanDB.graph, err = NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		opts.UseGraphCache, opts.NoMigration,
+		opts.MaxCacheChannels, opts.UseGraphCache, opts.NoMigration,
 	)
 	if err != nil {
 		return nil, err
@@ -1312,6 +1318,13 @@ func (c *ChannelStateDB) RestoreChannelShells(channelShells ...*ChannelShell) er
 			// regular one.
 			channel.chanStatus |= ChanStatusRestored
 
+			// We also record when the channel was restored, so we
+			// can tell how long we've been waiting for the peer.
+			if channel.RestoredAt.IsZero() {
+				now := time.Now().Unix()
+				channel.RestoredAt = time.Unix(now, 0)
+			}
+
 			// First, we'll attempt to create a new open channel
 			// and link node for this channel. If the channel
 			// already exists, then in order to ensure this method
@@ -1519,6 +1532,10 @@ func (d *DB) syncVersions(versions []mandatoryVersion) error {
 		return nil
 	}
 
+	if err := d.runPreMigrationCheck(); err != nil {
+		return err
+	}
+
 	log.Infof("Performing database schema migration")
 
 	// Otherwise, we fetch the migrations which need to applied, and
@@ -1559,6 +1576,19 @@ func (d *DB) syncVersions(versions []mandatoryVersion) error {
 	}, func() {})
 }
 
+// runPreMigrationCheck runs the configured pre-migration check, if any.
+func (d *DB) runPreMigrationCheck() error {
+	if d.preMigrationCheck == nil {
+		return nil
+	}
+
+	if err := d.preMigrationCheck(); err != nil {
+		return fmt.Errorf("refusing to migrate database: %w", err)
+	}
+
+	return nil
+}
+
 // applyOptionalVersions takes a config to determine whether the optional
 // migrations will be applied.
 //
@@ -1596,6 +1626,10 @@ func (d *DB) applyOptionalVersions(cfg OptionalMiragtionConfig) error {
 		return nil
 	}
 
+	if err := d.runPreMigrationCheck(); err != nil {
+		return err
+	}
+
 	// Get the optional version.
 	version := optionalVersions[0]
 	log.Infof("Performing database optional migration: %s", version.name)
@@ -392,6 +392,10 @@ func TestRestoreChannelShells(t *testing.T) {
 			nodeChans[0].chanStatus)
 	}
 
+	// The time the channel was restored is recorded as well.
+	require.False(t, nodeChans[0].RestoredAt.IsZero())
+	require.Equal(t, channelShell.Chan.RestoredAt, nodeChans[0].RestoredAt)
+
 	// We should also be able to find the channel if we query for it
 	// directly.
 	_, err = cdb.FetchChannel(nil, channelShell.Chan.FundingOutpoint)
@@ -77,6 +77,36 @@ func fetchDuplicatePaymentStatus(bucket kvdb.RBucket) (PaymentStatus, error) {
 	return 0, ErrPaymentNotInitiated
 }
 
+// countDuplicateAttempts returns the number of HTLC attempts stored for the
+// legacy duplicate payments of the given payment. Each duplicate payment only
+// stores its latest attempt.
+func countDuplicateAttempts(paymentBucket kvdb.RBucket) (int, error) {
+	duplicates := paymentBucket.NestedReadBucket(duplicatePaymentsBucket)
+	if duplicates == nil {
+		return 0, nil
+	}
+
+	var numAttempts int
+	err := duplicates.ForEach(func(k, _ []byte) error {
+		duplicate := duplicates.NestedReadBucket(k)
+		if duplicate == nil {
+			return fmt.Errorf("non bucket element in duplicate " +
+				"payments bucket")
+		}
+
+		if duplicate.Get(duplicatePaymentAttemptInfoKey) != nil {
+			numAttempts++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numAttempts, nil
+}
+
 func deserializeDuplicateHTLCAttemptInfo(r io.Reader) (
 	*duplicateHTLCAttemptInfo, error) {
 
@@ -189,10 +189,13 @@ type ChannelGraph struct {
 }
 
 // NewChannelGraph allocates a new ChannelGraph backed by a DB instance. The
-// returned instance has its own unique reject cache and channel cache.
+// returned instance has its own unique reject cache and channel cache. If
+// maxCacheChannels is non-zero, the in-memory graph cache holds at most that
+// many channels.
 func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
-	batchCommitInterval time.Duration, preAllocCacheNumNodes int,
-	useGraphCache, noMigrations bool) (*ChannelGraph, error) {
+	batchCommitInterval time.Duration, preAllocCacheNumNodes,
+	maxCacheChannels int, useGraphCache, noMigrations bool) (*ChannelGraph,
+	error) {
 
 	if !noMigrations {
 		if err := initChannelGraph(db); err != nil {
@@ -216,11 +219,26 @@ func NewChannelGraph(db kvdb.Backend, rejectCacheSize, chanCacheSize int,
 	// speed/memory usage tradeoff.
 	if useGraphCache {
 		g.graphCache = NewGraphCache(preAllocCacheNumNodes)
+		g.graphCache.SetMaxChannels(maxCacheChannels)
+
+		// Our own channels must never be evicted from the cache, so we
+		// pin the source node if it is already known.
+		sourceNode, err := g.SourceNode()
+		switch {
+		case err == nil:
+			g.graphCache.PinNode(sourceNode.PubKeyBytes)
+
+		case !errors.Is(err, ErrSourceNodeNotSet) &&
+			!errors.Is(err, ErrGraphNotFound):
+
+			return nil, err
+		}
+
 		startTime := time.Now()
 		log.Debugf("Populating in-memory channel graph, this might " +
 			"take a while...")
 
-		err := g.ForEachNodeCacheable(
+		err = g.ForEachNodeCacheable(
 			func(tx kvdb.RTx, node GraphCacheNode) error {
 				g.graphCache.AddNodeFeatures(node)
 
@@ -794,12 +812,26 @@ func (c *ChannelGraph) sourceNode(nodes kvdb.RBucket) (*LightningNode, error) {
 	return &node, nil
 }
 
+// MarkChannelsUsed marks the given channels as recently used in one of our own
+// payment routes. This makes them less likely to be evicted from the graph
+// cache if its size is limited.
+func (c *ChannelGraph) MarkChannelsUsed(chanIDs ...uint64) {
+	if c.graphCache != nil {
+		c.graphCache.MarkChannelsUsed(chanIDs...)
+	}
+}
+
 // SetSourceNode sets the source node within the graph database. The source
 // node is to be used as the center of a star-graph within path finding
 // algorithms.
 func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 	nodePubBytes := node.PubKeyBytes[:]
 
+	// Make sure our own channels are never evicted from the graph cache.
+	if c.graphCache != nil {
+		c.graphCache.PinNode(node.PubKeyBytes)
+	}
+
 	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
 		// First grab the nodes bucket which stores the mapping from
 		// pubKey to node information.
@@ -2784,11 +2816,41 @@ func updateEdgePolicy(tx kvdb.RwTx, edge *models.ChannelEdgePolicy,
 		graphCache.UpdatePolicy(
 			edge, fromNodePubKey, toNodePubKey, isUpdate1,
 		)
+
+		// If the channel was evicted from a size limited cache, the
+		// fresh update makes it relevant again, so we add it back.
+		if graphCache.isEvicted(fromNodePubKey, edge.ChannelID) {
+			err := addEvictedChannel(edges, edgeIndex, chanID[:],
+				graphCache)
+			if err != nil {
+				return false, err
+			}
+		}
 	}
 
 	return isUpdate1, nil
 }
 
+// addEvictedChannel loads the channel with the given ID from disk and adds it
+// back to the graph cache.
+func addEvictedChannel(edges, edgeIndex kvdb.RBucket, chanID []byte,
+	graphCache *GraphCache) error {
+
+	edgeInfo, err := fetchChanEdgeInfo(edgeIndex, chanID)
+	if err != nil {
+		return err
+	}
+
+	policy1, policy2, err := fetchChanEdgePolicies(edgeIndex, edges, chanID)
+	if err != nil {
+		return err
+	}
+
+	graphCache.AddChannel(&edgeInfo, policy1, policy2)
+
+	return nil
+}
+
 // LightningNode represents an individual vertex/node within the channel graph.
 // A node is connected to other nodes by one or more channel edges emanating
 // from it. As the graph is directed, a node will also have an incoming edge
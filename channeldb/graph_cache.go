@@ -2,7 +2,9 @@ package channeldb
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb/models"
@@ -83,12 +85,43 @@ func (c *DirectedChannel) DeepCopy() *DirectedChannel {
 	return &channelCopy
 }
 
+// evictionBatchPercent is the percentage of the maximum number of channels
+// that is evicted from a size limited graph cache at once. Evicting in batches
+// makes sure we don't need to sort all channels on every single addition once
+// the limit is reached.
+const evictionBatchPercent = 10
+
+// cachedChannel holds the information needed to decide whether a channel can
+// be evicted from a size limited graph cache.
+type cachedChannel struct {
+	node1 route.Vertex
+	node2 route.Vertex
+
+	// lastRelevant is the last time the channel was either updated by one
+	// of its policies or used in one of our own payment routes.
+	lastRelevant time.Time
+}
+
 // GraphCache is a type that holds a minimal set of information of the public
 // channel graph that can be used for pathfinding.
 type GraphCache struct {
 	nodeChannels map[route.Vertex]map[uint64]*DirectedChannel
 	nodeFeatures map[route.Vertex]*lnwire.FeatureVector
 
+	// maxChannels is the maximum number of channels that are held in the
+	// cache. If the limit is exceeded, the least recently relevant
+	// channels are evicted from the cache (but not from disk). A value of
+	// zero means the cache isn't limited.
+	maxChannels int
+
+	// channels tracks the relevance of each cached channel. It is only
+	// populated if the size of the cache is limited.
+	channels map[uint64]*cachedChannel
+
+	// pinnedNodes is the set of nodes whose channels are never evicted
+	// from the cache, which is usually just our own node.
+	pinnedNodes map[route.Vertex]struct{}
+
 	mtx sync.RWMutex
 }
 
@@ -106,7 +139,146 @@ func NewGraphCache(preAllocNumNodes int) *GraphCache {
 			map[route.Vertex]*lnwire.FeatureVector,
 			preAllocNumNodes,
 		),
+		channels:    make(map[uint64]*cachedChannel),
+		pinnedNodes: make(map[route.Vertex]struct{}),
+	}
+}
+
+// SetMaxChannels limits the number of channels held by the cache. If the cache
+// already holds more channels than the new limit, the least recently relevant
+// ones are evicted right away. A value of zero removes the limit.
+func (c *GraphCache) SetMaxChannels(maxChannels int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.maxChannels = maxChannels
+	if maxChannels == 0 {
+		c.channels = make(map[uint64]*cachedChannel)
+		return
+	}
+
+	// Start tracking all channels that are already in the cache. We don't
+	// know when they were last relevant, so they all start out as equally
+	// stale.
+	for node, channels := range c.nodeChannels {
+		for chanID, channel := range channels {
+			if _, ok := c.channels[chanID]; ok {
+				continue
+			}
+
+			node1, node2 := node, channel.OtherNode
+			if !channel.IsNode1 {
+				node1, node2 = node2, node1
+			}
+			c.channels[chanID] = &cachedChannel{
+				node1: node1,
+				node2: node2,
+			}
+		}
+	}
+
+	c.evictChannels()
+}
+
+// PinNode makes sure that the channels of the given node are never evicted
+// from the cache, even if its size is limited.
+func (c *GraphCache) PinNode(node route.Vertex) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.pinnedNodes[node] = struct{}{}
+}
+
+// isEvicted returns true if the size of the cache is limited and the given
+// channel of the node isn't currently held by the cache.
+func (c *GraphCache) isEvicted(node route.Vertex, chanID uint64) bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if c.maxChannels == 0 {
+		return false
+	}
+
+	_, ok := c.nodeChannels[node][chanID]
+	return !ok
+}
+
+// MarkChannelsUsed marks the given channels as recently relevant, which makes
+// them less likely to be evicted from a size limited cache. This is used for
+// channels that were part of one of our own payment routes.
+func (c *GraphCache) MarkChannelsUsed(chanIDs ...uint64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	for _, chanID := range chanIDs {
+		c.touchChannel(chanID, now)
+	}
+}
+
+// touchChannel bumps the last relevant time of a tracked channel if the given
+// timestamp is more recent.
+//
+// NOTE: This method must be called with the mutex held.
+func (c *GraphCache) touchChannel(chanID uint64, ts time.Time) {
+	if c.maxChannels == 0 {
+		return
+	}
+
+	channel, ok := c.channels[chanID]
+	if ok && ts.After(channel.lastRelevant) {
+		channel.lastRelevant = ts
+	}
+}
+
+// isPinned returns true if the channel connects to one of the pinned nodes.
+//
+// NOTE: This method must be called with the mutex held.
+func (c *GraphCache) isPinned(channel *cachedChannel) bool {
+	_, pinned1 := c.pinnedNodes[channel.node1]
+	_, pinned2 := c.pinnedNodes[channel.node2]
+
+	return pinned1 || pinned2
+}
+
+// evictChannels removes the least recently relevant channels from the cache if
+// it holds more channels than allowed. To avoid evicting on every single
+// addition, we evict a batch of channels below the limit at once. Channels of
+// pinned nodes are never evicted.
+//
+// NOTE: This method must be called with the mutex held.
+func (c *GraphCache) evictChannels() {
+	if c.maxChannels == 0 || len(c.channels) <= c.maxChannels {
+		return
 	}
+
+	candidates := make([]uint64, 0, len(c.channels))
+	for chanID, channel := range c.channels {
+		if c.isPinned(channel) {
+			continue
+		}
+
+		candidates = append(candidates, chanID)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := c.channels[candidates[i]], c.channels[candidates[j]]
+		return a.lastRelevant.Before(b.lastRelevant)
+	})
+
+	target := c.maxChannels - c.maxChannels*evictionBatchPercent/100
+	numEvict := len(c.channels) - target
+	if numEvict > len(candidates) {
+		numEvict = len(candidates)
+	}
+
+	for _, chanID := range candidates[:numEvict] {
+		channel := c.channels[chanID]
+		c.removeChannelIfFound(channel.node1, chanID)
+		c.removeChannelIfFound(channel.node2, chanID)
+	}
+
+	log.Debugf("Evicted %d channels from size limited graph cache "+
+		"(max_channels=%d)", numEvict, c.maxChannels)
 }
 
 // Stats returns statistics about the current cache size.
@@ -184,6 +356,25 @@ func (c *GraphCache) AddChannel(info *models.ChannelEdgeInfo,
 		OtherNode: info.NodeKey1Bytes,
 		Capacity:  info.Capacity,
 	})
+	if c.maxChannels != 0 {
+		if _, ok := c.channels[info.ChannelID]; !ok {
+			c.channels[info.ChannelID] = &cachedChannel{
+				node1: info.NodeKey1Bytes,
+				node2: info.NodeKey2Bytes,
+			}
+		}
+
+		// The channel is as relevant as its most recent policy. We
+		// need to know that before evicting, otherwise we'd evict the
+		// channel we just added.
+		if policy1 != nil {
+			c.touchChannel(info.ChannelID, policy1.LastUpdate)
+		}
+		if policy2 != nil {
+			c.touchChannel(info.ChannelID, policy2.LastUpdate)
+		}
+		c.evictChannels()
+	}
 	c.mtx.Unlock()
 
 	// The policy's node is always the to_node. So if policy 1 has to_node
@@ -268,6 +459,8 @@ func (c *GraphCache) UpdatePolicy(policy *models.ChannelEdgePolicy, fromNode,
 
 	updatePolicy(fromNode)
 	updatePolicy(toNode)
+
+	c.touchChannel(policy.ChannelID, policy.LastUpdate)
 }
 
 // RemoveNode completely removes a node and all its channels (including the
@@ -299,6 +492,8 @@ func (c *GraphCache) RemoveChannel(node1, node2 route.Vertex, chanID uint64) {
 
 // removeChannelIfFound removes a single channel from one side.
 func (c *GraphCache) removeChannelIfFound(node route.Vertex, chanID uint64) {
+	delete(c.channels, chanID)
+
 	if len(c.nodeChannels[node]) == 0 {
 		return
 	}
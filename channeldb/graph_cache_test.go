@@ -3,6 +3,7 @@ package channeldb
 import (
 	"encoding/hex"
 	"testing"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb/models"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -171,3 +172,81 @@ func assertCachedPolicyEqual(t *testing.T, original *models.ChannelEdgePolicy,
 		t, route.Vertex(original.ToNode), cached.ToNodePubKey(),
 	)
 }
+
+// TestGraphCacheMaxChannels tests that a size limited graph cache evicts the
+// least recently relevant channels while never evicting the channels of a
+// pinned node.
+func TestGraphCacheMaxChannels(t *testing.T) {
+	t.Parallel()
+
+	const maxChannels = 10
+
+	cache := NewGraphCache(10)
+	cache.SetMaxChannels(maxChannels)
+	cache.PinNode(pubKey1)
+
+	addChannel := func(chanID uint64, node1, node2 route.Vertex,
+		lastUpdate time.Time) {
+
+		info := &models.ChannelEdgeInfo{
+			ChannelID:     chanID,
+			NodeKey1Bytes: node1,
+			NodeKey2Bytes: node2,
+			Capacity:      500,
+		}
+		policy := &models.ChannelEdgePolicy{
+			ChannelID:  chanID,
+			ToNode:     node2,
+			LastUpdate: lastUpdate,
+		}
+		cache.AddChannel(info, policy, nil)
+	}
+
+	hasChannel := func(node route.Vertex, chanID uint64) bool {
+		_, ok := cache.nodeChannels[node][chanID]
+		return ok
+	}
+
+	// Add a few channels of our pinned node with very old updates.
+	for i := uint64(0); i < 5; i++ {
+		addChannel(i, pubKey1, route.Vertex{byte(i)}, time.Unix(1, 0))
+	}
+
+	// We now add more channels between other nodes than fit into the
+	// cache, each one more recent than the previous one. The very first
+	// one is marked as used by one of our payments.
+	const numChannels = 20
+	for i := uint64(0); i < numChannels; i++ {
+		chanID := 100 + i
+		addChannel(
+			chanID, pubKey2, route.Vertex{byte(i)},
+			time.Unix(int64(1000+i), 0),
+		)
+
+		if i == 0 {
+			cache.MarkChannelsUsed(chanID)
+		}
+	}
+
+	// The cache must not hold more channels than allowed.
+	require.LessOrEqual(t, len(cache.channels), maxChannels)
+
+	// None of our own channels must have been evicted.
+	for i := uint64(0); i < 5; i++ {
+		require.True(t, hasChannel(pubKey1, i))
+	}
+
+	// The channel we used and the most recent channel are still cached
+	// while the oldest unused one was evicted from both sides.
+	require.True(t, hasChannel(pubKey2, 100))
+	require.True(t, hasChannel(pubKey2, 100+numChannels-1))
+	require.False(t, hasChannel(pubKey2, 101))
+	require.False(t, hasChannel(route.Vertex{1}, 101))
+
+	// A fresh update for an evicted channel doesn't bring it back by
+	// itself, the caller is responsible for adding it again.
+	require.True(t, cache.isEvicted(pubKey2, 101))
+	addChannel(101, pubKey2, route.Vertex{1}, time.Now())
+	require.True(t, hasChannel(pubKey2, 101))
+	require.LessOrEqual(t, len(cache.channels), maxChannels)
+}
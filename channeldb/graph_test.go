@@ -70,7 +70,7 @@ func MakeTestGraph(t testing.TB, modifiers ...OptionModifier) (*ChannelGraph, er
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.MaxCacheChannels, true, false,
 	)
 	if err != nil {
 		backendCleanup()
@@ -4003,7 +4003,7 @@ func TestGraphLoading(t *testing.T) {
 	graph, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.MaxCacheChannels, true, false,
 	)
 	require.NoError(t, err)
 
@@ -4017,7 +4017,7 @@ func TestGraphLoading(t *testing.T) {
 	graphReloaded, err := NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		true, false,
+		opts.MaxCacheChannels, true, false,
 	)
 	require.NoError(t, err)
 
@@ -2,6 +2,8 @@ package channeldb
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -20,6 +22,51 @@ var (
 	confirmHintBucket = []byte("confirm-hints")
 )
 
+// HeightHintType denotes whether a height hint belongs to a spend or a confirm
+// request.
+type HeightHintType uint8
+
+const (
+	// SpendHint is a height hint of a spend request, keyed by the
+	// serialized outpoint or the output script.
+	SpendHint HeightHintType = iota
+
+	// ConfirmHint is a height hint of a confirm request, keyed by the
+	// transaction hash or the output script.
+	ConfirmHint
+)
+
+// String returns a human readable representation of the height hint type.
+func (t HeightHintType) String() string {
+	switch t {
+	case SpendHint:
+		return "spend"
+
+	case ConfirmHint:
+		return "confirm"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// HeightHint is a single raw entry of the height hint cache.
+type HeightHint struct {
+	// Type denotes whether this is a spend or a confirm hint.
+	Type HeightHintType
+
+	// Key is the key the hint is stored under within the cache.
+	Key []byte
+
+	// Height is the earliest height at which the request could have been
+	// fulfilled.
+	Height uint32
+}
+
+// ErrEmptyHeightHintKey is returned when a height hint without a key is
+// imported.
+var ErrEmptyHeightHintKey = errors.New("height hint key must not be empty")
+
 // CacheConfig contains the HeightHintCache configuration.
 type CacheConfig struct {
 	// QueryDisable prevents reliance on the Height Hint Cache.  This is
@@ -283,6 +330,134 @@ func (c *HeightHintCache) PurgeConfirmHint(
 	})
 }
 
+// FetchHeightHints returns all spend and confirm hints currently stored in
+// the cache.
+func (c *HeightHintCache) FetchHeightHints() ([]HeightHint, error) {
+	var hints []HeightHint
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		fetchHints := func(bucketName []byte,
+			hintType HeightHintType) error {
+
+			bucket := tx.ReadBucket(bucketName)
+			if bucket == nil {
+				return chainntnfs.ErrCorruptedHeightHintCache
+			}
+
+			return bucket.ForEach(func(k, v []byte) error {
+				var height uint32
+				err := ReadElement(bytes.NewReader(v), &height)
+				if err != nil {
+					return err
+				}
+
+				hints = append(hints, HeightHint{
+					Type:   hintType,
+					Key:    append([]byte(nil), k...),
+					Height: height,
+				})
+
+				return nil
+			})
+		}
+
+		if err := fetchHints(spendHintBucket, SpendHint); err != nil {
+			return err
+		}
+
+		return fetchHints(confirmHintBucket, ConfirmHint)
+	}, func() {
+		hints = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hints, nil
+}
+
+// ImportHeightHints stores the given hints within the cache. An existing hint
+// with the same key is only replaced if the imported hint is lower, as a higher
+// hint would make the notifier start its rescan after the spend or
+// confirmation it's looking for. All hints are written atomically. The number
+// of hints that were stored is returned.
+func (c *HeightHintCache) ImportHeightHints(hints []HeightHint) (int, error) {
+	if len(hints) == 0 {
+		return 0, nil
+	}
+
+	log.Debugf("Importing %d height hints", len(hints))
+
+	var numImported int
+	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		for _, hint := range hints {
+			var bucketName []byte
+			switch hint.Type {
+			case SpendHint:
+				bucketName = spendHintBucket
+
+			case ConfirmHint:
+				bucketName = confirmHintBucket
+
+			default:
+				return fmt.Errorf("unknown height hint type: "+
+					"%v", hint.Type)
+			}
+
+			if len(hint.Key) == 0 {
+				return ErrEmptyHeightHintKey
+			}
+
+			bucket := tx.ReadWriteBucket(bucketName)
+			if bucket == nil {
+				return chainntnfs.ErrCorruptedHeightHintCache
+			}
+
+			existing := bucket.Get(hint.Key)
+			if existing != nil {
+				var existingHeight uint32
+				err := ReadElement(
+					bytes.NewReader(existing),
+					&existingHeight,
+				)
+				if err != nil {
+					return err
+				}
+
+				if hint.Height >= existingHeight {
+					log.Debugf("Not raising %v height hint "+
+						"%x from %d to %d", hint.Type,
+						hint.Key, existingHeight,
+						hint.Height)
+
+					continue
+				}
+			}
+
+			var height bytes.Buffer
+			err := WriteElement(&height, hint.Height)
+			if err != nil {
+				return err
+			}
+
+			err = bucket.Put(hint.Key, height.Bytes())
+			if err != nil {
+				return err
+			}
+
+			numImported++
+		}
+
+		return nil
+	}, func() {
+		numImported = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numImported, nil
+}
+
 // confHintKey returns the key that will be used to index the confirmation
 // request's hint within the height hint cache.
 func confHintKey(r *chainntnfs.ConfRequest) ([]byte, error) {
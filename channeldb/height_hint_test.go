@@ -174,3 +174,90 @@ func TestQueryDisable(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, uint32(0), cachedSpendHeight)
 }
+
+// TestHeightHintCacheExportImport asserts that the height hints can be
+// exported and that imported hints only replace existing ones if they are
+// lower.
+func TestHeightHintCacheExportImport(t *testing.T) {
+	t.Parallel()
+
+	hintCache := initHintCache(t)
+
+	spendRequest := chainntnfs.SpendRequest{
+		OutPoint: wire.OutPoint{Index: 1},
+	}
+	confRequest := chainntnfs.ConfRequest{
+		TxID: chainhash.Hash{1},
+	}
+
+	err := hintCache.CommitSpendHint(100, spendRequest)
+	require.NoError(t, err)
+	err = hintCache.CommitConfirmHint(200, confRequest)
+	require.NoError(t, err)
+
+	// Both hints should be exported along with their type.
+	hints, err := hintCache.FetchHeightHints()
+	require.NoError(t, err)
+	require.Len(t, hints, 2)
+
+	require.Equal(t, SpendHint, hints[0].Type)
+	require.EqualValues(t, 100, hints[0].Height)
+	require.Equal(t, ConfirmHint, hints[1].Type)
+	require.EqualValues(t, 200, hints[1].Height)
+
+	// Importing lower hints using the exported keys should replace the
+	// existing hints.
+	hints[0].Height = 50
+	hints[1].Height = 150
+	numImported, err := hintCache.ImportHeightHints(hints)
+	require.NoError(t, err)
+	require.Equal(t, 2, numImported)
+
+	spendHint, err := hintCache.QuerySpendHint(spendRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, spendHint)
+
+	confHint, err := hintCache.QueryConfirmHint(confRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 150, confHint)
+
+	// Importing higher or equal hints must not raise the existing hints,
+	// as the notifier would then miss spends and confirmations before
+	// them. A hint for a new key is stored as is.
+	newConfRequest := chainntnfs.ConfRequest{
+		TxID: chainhash.Hash{2},
+	}
+	newConfKey, err := confHintKey(&newConfRequest)
+	require.NoError(t, err)
+
+	numImported, err = hintCache.ImportHeightHints([]HeightHint{
+		{Type: SpendHint, Key: hints[0].Key, Height: 300},
+		{Type: ConfirmHint, Key: hints[1].Key, Height: 150},
+		{Type: ConfirmHint, Key: newConfKey, Height: 400},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, numImported)
+
+	spendHint, err = hintCache.QuerySpendHint(spendRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, spendHint)
+
+	confHint, err = hintCache.QueryConfirmHint(confRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 150, confHint)
+
+	confHint, err = hintCache.QueryConfirmHint(newConfRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 400, confHint)
+
+	// Invalid hints must be rejected without importing any of the hints.
+	_, err = hintCache.ImportHeightHints([]HeightHint{
+		{Type: SpendHint, Key: hints[0].Key, Height: 10},
+		{Type: ConfirmHint, Height: 10},
+	})
+	require.ErrorIs(t, err, ErrEmptyHeightHintKey)
+
+	spendHint, err = hintCache.QuerySpendHint(spendRequest)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, spendHint)
+}
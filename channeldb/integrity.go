@@ -0,0 +1,446 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// integrityProgressInterval is the interval in which the progress of
+	// a running integrity check is logged.
+	integrityProgressInterval = 10 * time.Second
+)
+
+// errIntegrityCheckTimeout is returned internally to abort an integrity check
+// that exceeded its maximum runtime.
+var errIntegrityCheckTimeout = errors.New("integrity check timed out")
+
+// IntegrityIssue describes a single inconsistency found by an integrity check
+// of the channel state database.
+type IntegrityIssue struct {
+	// ChanPoint is the outpoint of the channel the issue was found for.
+	ChanPoint wire.OutPoint
+
+	// Critical is true if the issue may put funds at risk or prevents the
+	// channel from being handled correctly, for example because revoked
+	// states can no longer be punished.
+	Critical bool
+
+	// Description is a human readable description of the issue.
+	Description string
+}
+
+// String returns a human readable representation of the issue.
+func (i IntegrityIssue) String() string {
+	severity := "warning"
+	if i.Critical {
+		severity = "critical"
+	}
+
+	return fmt.Sprintf("%v: chan_point=%v: %v", severity, i.ChanPoint,
+		i.Description)
+}
+
+// IntegrityReport is the result of an integrity check of the channel state
+// database.
+type IntegrityReport struct {
+	// NumChannels is the number of open channels that were checked.
+	NumChannels int
+
+	// NumRevocationLogs is the number of revocation log entries that were
+	// checked.
+	NumRevocationLogs uint64
+
+	// Complete is false if the check was aborted because it exceeded its
+	// maximum runtime, in which case only a part of the database was
+	// checked.
+	Complete bool
+
+	// Issues is the list of all inconsistencies that were found.
+	Issues []IntegrityIssue
+}
+
+// NumCritical returns the number of critical issues in the report.
+func (r *IntegrityReport) NumCritical() int {
+	var numCritical int
+	for _, issue := range r.Issues {
+		if issue.Critical {
+			numCritical++
+		}
+	}
+
+	return numCritical
+}
+
+// addIssue adds an issue for the given channel to the report.
+func (r *IntegrityReport) addIssue(chanPoint wire.OutPoint, critical bool,
+	format string, args ...interface{}) {
+
+	r.Issues = append(r.Issues, IntegrityIssue{
+		ChanPoint:   chanPoint,
+		Critical:    critical,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// CheckIntegrity runs a consistency check across the open channels of the
+// channel state database and returns a report of all inconsistencies found.
+// The following is checked:
+//   - every open channel can be deserialized,
+//   - every open channel is marked as open in the outpoint index, and every
+//     open entry of the outpoint index references an existing open channel,
+//   - the revocation log of every open channel contains an entry for each
+//     revoked state of the remote party,
+//   - revocation log entries contain the output amounts, unless the database
+//     was opened with the NoRevLogAmtData option.
+//
+// The check runs within a single read transaction and is aborted once it
+// exceeds the given timeout, in which case the report is marked incomplete. A
+// timeout of zero means the runtime isn't limited. The progress is logged
+// periodically, as checking the revocation logs of a large database may take
+// a while.
+func (c *ChannelStateDB) CheckIntegrity(
+	timeout time.Duration) (*IntegrityReport, error) {
+
+	var (
+		report      *IntegrityReport
+		openChanIdx map[wire.OutPoint]struct{}
+		start       = time.Now()
+		lastLog     time.Time
+	)
+
+	// checkProgress aborts the check if it exceeded its maximum runtime
+	// and logs the progress once in a while.
+	checkProgress := func() error {
+		now := time.Now()
+		if timeout > 0 && now.Sub(start) > timeout {
+			return errIntegrityCheckTimeout
+		}
+
+		if now.Sub(lastLog) > integrityProgressInterval {
+			log.Infof("Integrity check in progress: checked %d "+
+				"channels and %d revocation log entries, "+
+				"found %d issues", report.NumChannels,
+				report.NumRevocationLogs, len(report.Issues))
+
+			lastLog = now
+		}
+
+		return nil
+	}
+
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		openChanBucket := tx.ReadBucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrNoActiveChannels
+		}
+
+		opBucket := tx.ReadBucket(outpointBucket)
+		if opBucket == nil {
+			return ErrNoChanDBExists
+		}
+
+		checkChannel := func(chainBucket kvdb.RBucket,
+			chanKey []byte) error {
+
+			return c.checkChannelIntegrity(
+				chainBucket, opBucket, chanKey, report,
+				openChanIdx, checkProgress,
+			)
+		}
+
+		err := forEachChannelBucket(openChanBucket, checkChannel)
+		if err != nil {
+			return err
+		}
+
+		return checkOutpointIndex(
+			opBucket, report, openChanIdx, checkProgress,
+		)
+	}, func() {
+		report = &IntegrityReport{}
+		openChanIdx = make(map[wire.OutPoint]struct{})
+		lastLog = start
+	})
+	switch {
+	case errors.Is(err, errIntegrityCheckTimeout):
+		log.Warnf("Integrity check aborted after %v, only %d channels "+
+			"were checked", timeout, report.NumChannels)
+
+		return report, nil
+
+	case errors.Is(err, ErrNoActiveChannels):
+		report.Complete = true
+		return report, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	report.Complete = true
+
+	return report, nil
+}
+
+// forEachChannelBucket calls the given function for every channel bucket
+// within the open channel bucket, which is structured as node public key ->
+// chain hash -> channel outpoint.
+func forEachChannelBucket(openChanBucket kvdb.RBucket,
+	cb func(chainBucket kvdb.RBucket, chanKey []byte) error) error {
+
+	return openChanBucket.ForEach(func(nodeKey, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		nodeBucket := openChanBucket.NestedReadBucket(nodeKey)
+
+		return nodeBucket.ForEach(func(chainHash, v []byte) error {
+			if v != nil {
+				return nil
+			}
+			chainBucket := nodeBucket.NestedReadBucket(chainHash)
+
+			return chainBucket.ForEach(func(k, v []byte) error {
+				if v != nil {
+					return nil
+				}
+
+				return cb(chainBucket, k)
+			})
+		})
+	})
+}
+
+// checkChannelIntegrity checks the channel stored under the given key within
+// the chain bucket and adds all issues found to the report.
+func (c *ChannelStateDB) checkChannelIntegrity(chainBucket,
+	opBucket kvdb.RBucket, chanKey []byte, report *IntegrityReport,
+	openChanIdx map[wire.OutPoint]struct{},
+	checkProgress func() error) error {
+
+	if err := checkProgress(); err != nil {
+		return err
+	}
+
+	var chanPoint wire.OutPoint
+	err := readOutpoint(bytes.NewReader(chanKey), &chanPoint)
+	if err != nil {
+		return err
+	}
+
+	report.NumChannels++
+	openChanIdx[chanPoint] = struct{}{}
+
+	// An open channel must have an open entry in the outpoint index,
+	// otherwise it can't be closed.
+	status, err := fetchOutpointStatus(opBucket, chanKey)
+	switch {
+	case errors.Is(err, ErrMissingIndexEntry):
+		report.addIssue(chanPoint, true, "channel is missing from the "+
+			"outpoint index")
+
+	case err != nil:
+		report.addIssue(chanPoint, true, "unable to read outpoint "+
+			"index entry: %v", err)
+
+	case status != outpointOpen:
+		report.addIssue(chanPoint, true, "channel is marked as "+
+			"closed in the outpoint index")
+	}
+
+	chanBucket := chainBucket.NestedReadBucket(chanKey)
+	channel, err := fetchOpenChannel(chanBucket, &chanPoint)
+	if err != nil {
+		report.addIssue(chanPoint, true, "unable to read channel "+
+			"data: %v", err)
+
+		return nil
+	}
+
+	// Channels restored from a static channel backup don't have any
+	// revocation log, as we only know the channel's basic parameters.
+	if channel.HasChanStatus(ChanStatusRestored) {
+		return nil
+	}
+
+	return c.checkRevocationLog(
+		chanBucket, channel, report, checkProgress,
+	)
+}
+
+// checkRevocationLog checks that the revocation log of the given channel
+// contains an entry for every revoked state of the remote party, and that the
+// entries contain amount data unless it's disabled.
+func (c *ChannelStateDB) checkRevocationLog(chanBucket kvdb.RBucket,
+	channel *OpenChannel, report *IntegrityReport,
+	checkProgress func() error) error {
+
+	// All remote commitments below the current one were revoked and must
+	// have a revocation log entry, either in the new or the deprecated
+	// log bucket.
+	tailHeight := channel.RemoteCommitment.CommitHeight
+	if tailHeight == 0 {
+		return nil
+	}
+
+	var (
+		numEntries      uint64
+		numNoAmountData uint64
+		checkAmountData = !c.parent.noRevLogAmtData
+	)
+
+	logBucket := chanBucket.NestedReadBucket(revocationLogBucket)
+	if logBucket != nil {
+		err := logBucket.ForEach(func(k, v []byte) error {
+			if len(k) != 8 || byteOrder.Uint64(k) >= tailHeight {
+				return nil
+			}
+
+			numEntries++
+			report.NumRevocationLogs++
+			if report.NumRevocationLogs%1000 == 0 {
+				if err := checkProgress(); err != nil {
+					return err
+				}
+			}
+
+			if !checkAmountData {
+				return nil
+			}
+
+			rl, err := deserializeRevocationLog(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				report.addIssue(channel.FundingOutpoint, true,
+					"unable to read revocation log entry "+
+						"for height %d: %v",
+					byteOrder.Uint64(k), err)
+
+				return nil
+			}
+
+			if rl.OurBalance == nil || rl.TheirBalance == nil {
+				numNoAmountData++
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	oldBucket := chanBucket.NestedReadBucket(revocationLogBucketDeprecated)
+	if oldBucket != nil {
+		err := oldBucket.ForEach(func(k, _ []byte) error {
+			if len(k) != 8 || byteOrder.Uint64(k) >= tailHeight {
+				return nil
+			}
+
+			// An entry might already have been migrated to the
+			// new bucket.
+			if logBucket != nil && logBucket.Get(k) != nil {
+				return nil
+			}
+
+			numEntries++
+			report.NumRevocationLogs++
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if numEntries < tailHeight {
+		report.addIssue(channel.FundingOutpoint, true, "revocation "+
+			"log is missing %d of %d entries, breaches of the "+
+			"missing states can't be punished",
+			tailHeight-numEntries, tailHeight)
+	}
+
+	if numNoAmountData > 0 {
+		report.addIssue(channel.FundingOutpoint, false, "%d "+
+			"revocation log entries are missing amount data even "+
+			"though no-rev-log-amt-data is not set, these states "+
+			"can't be backed up to a watchtower", numNoAmountData)
+	}
+
+	return nil
+}
+
+// checkOutpointIndex checks that every open entry of the outpoint index
+// references an existing open channel.
+func checkOutpointIndex(opBucket kvdb.RBucket, report *IntegrityReport,
+	openChanIdx map[wire.OutPoint]struct{},
+	checkProgress func() error) error {
+
+	return opBucket.ForEach(func(k, v []byte) error {
+		if err := checkProgress(); err != nil {
+			return err
+		}
+
+		var chanPoint wire.OutPoint
+		err := readOutpoint(bytes.NewReader(k), &chanPoint)
+		if err != nil {
+			return err
+		}
+
+		status, err := decodeOutpointStatus(v)
+		if err != nil {
+			report.addIssue(chanPoint, false, "unable to read "+
+				"outpoint index entry: %v", err)
+
+			return nil
+		}
+
+		if status != outpointOpen {
+			return nil
+		}
+
+		if _, ok := openChanIdx[chanPoint]; !ok {
+			report.addIssue(chanPoint, false, "outpoint index "+
+				"references an open channel that doesn't "+
+				"exist")
+		}
+
+		return nil
+	})
+}
+
+// fetchOutpointStatus returns the status of the outpoint stored under the
+// given key in the outpoint index.
+func fetchOutpointStatus(opBucket kvdb.RBucket,
+	chanKey []byte) (indexStatus, error) {
+
+	v := opBucket.Get(chanKey)
+	if v == nil {
+		return 0, ErrMissingIndexEntry
+	}
+
+	return decodeOutpointStatus(v)
+}
+
+// decodeOutpointStatus decodes the tlv stream of an outpoint index entry.
+func decodeOutpointStatus(v []byte) (indexStatus, error) {
+	var status uint8
+	statusRecord := tlv.MakePrimitiveRecord(indexStatusType, &status)
+	opStream, err := tlv.NewStream(statusRecord)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := opStream.Decode(bytes.NewReader(v)); err != nil {
+		return 0, err
+	}
+
+	return indexStatus(status), nil
+}
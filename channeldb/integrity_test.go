@@ -0,0 +1,131 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckIntegrity asserts that the integrity check reports missing
+// revocation log entries, entries without amount data and inconsistencies of
+// the outpoint index.
+func TestCheckIntegrity(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	// An empty database doesn't have any issues.
+	report, err := cdb.CheckIntegrity(0)
+	require.NoError(t, err)
+	require.True(t, report.Complete)
+	require.Empty(t, report.Issues)
+
+	// Create a channel with three revoked remote commitments, of which
+	// only two are stored in the revocation log. One of them is missing
+	// the amount data.
+	chanA := createTestChannel(
+		t, cdb, openChannelOption(), func(p *testChannelParams) {
+			p.channel.RemoteCommitment.CommitHeight = 3
+		},
+	)
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, chanA.IdentityPub, &chanA.FundingOutpoint,
+			chanA.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		logBucket, err := chanBucket.CreateBucketIfNotExists(
+			revocationLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		for height, noAmtData := range []bool{false, true} {
+			commit := testChannelCommit
+			commit.CommitHeight = uint64(height)
+			err := putRevocationLog(
+				logBucket, &commit, 0, 1, noAmtData,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// Create a second channel that is missing from the outpoint index,
+	// and add an open entry to the index that doesn't belong to any
+	// channel.
+	chanB := createTestChannel(t, cdb, openChannelOption())
+	danglingOutpoint := wire.OutPoint{Index: 7}
+	err = kvdb.Update(cdb.backend, func(tx kvdb.RwTx) error {
+		opBucket := tx.ReadWriteBucket(outpointBucket)
+
+		var chanPointBuf bytes.Buffer
+		err := writeOutpoint(&chanPointBuf, &chanB.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+		openStatus := opBucket.Get(chanPointBuf.Bytes())
+		require.NotNil(t, openStatus)
+
+		if err := opBucket.Delete(chanPointBuf.Bytes()); err != nil {
+			return err
+		}
+
+		var danglingBuf bytes.Buffer
+		err = writeOutpoint(&danglingBuf, &danglingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		return opBucket.Put(danglingBuf.Bytes(), openStatus)
+	}, func() {})
+	require.NoError(t, err)
+
+	report, err = cdb.CheckIntegrity(0)
+	require.NoError(t, err)
+	require.True(t, report.Complete)
+	require.Equal(t, 2, report.NumChannels)
+	require.EqualValues(t, 2, report.NumRevocationLogs)
+	require.Len(t, report.Issues, 4)
+	require.Equal(t, 2, report.NumCritical())
+
+	issues := make(map[wire.OutPoint][]IntegrityIssue)
+	for _, issue := range report.Issues {
+		issues[issue.ChanPoint] = append(issues[issue.ChanPoint], issue)
+	}
+
+	require.Len(t, issues[chanA.FundingOutpoint], 2)
+	require.True(t, issues[chanA.FundingOutpoint][0].Critical)
+	require.Contains(
+		t, issues[chanA.FundingOutpoint][0].Description,
+		"missing 1 of 3 entries",
+	)
+	require.False(t, issues[chanA.FundingOutpoint][1].Critical)
+
+	require.Len(t, issues[chanB.FundingOutpoint], 1)
+	require.True(t, issues[chanB.FundingOutpoint][0].Critical)
+
+	require.Len(t, issues[danglingOutpoint], 1)
+	require.False(t, issues[danglingOutpoint][0].Critical)
+
+	// Without amount data being stored, missing amounts aren't reported.
+	cdb.parent.noRevLogAmtData = true
+	report, err = cdb.CheckIntegrity(0)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 3)
+	require.Equal(t, 2, report.NumCritical())
+}
@@ -2127,6 +2127,42 @@ func delAMPSettleIndex(invoiceNum []byte, invoices,
 	return nil
 }
 
+// InvoiceIndices returns the highest add index and settle index that have been
+// assigned so far. Both are read from the sequences of their index buckets,
+// which are persisted and never decrease, even if invoices are deleted.
+func (d *DB) InvoiceIndices(_ context.Context) (uint64, uint64, error) {
+	var addIndex, settleIndex uint64
+
+	// Bucket sequences can only be read from a read-write bucket, even
+	// though nothing is written here.
+	err := kvdb.Update(d, func(tx kvdb.RwTx) error {
+		invoices := tx.ReadWriteBucket(invoiceBucket)
+		if invoices == nil {
+			return nil
+		}
+
+		addIdx := invoices.NestedReadWriteBucket(addIndexBucket)
+		if addIdx != nil {
+			addIndex = addIdx.Sequence()
+		}
+
+		settleIdx := invoices.NestedReadWriteBucket(settleIndexBucket)
+		if settleIdx != nil {
+			settleIndex = settleIdx.Sequence()
+		}
+
+		return nil
+	}, func() {
+		addIndex = 0
+		settleIndex = 0
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return addIndex, settleIndex, nil
+}
+
 // DeleteCanceledInvoices deletes all canceled invoices from the database.
 func (d *DB) DeleteCanceledInvoices(_ context.Context) error {
 	return kvdb.Update(d, func(tx kvdb.RwTx) error {
@@ -492,6 +492,56 @@ func TestMigrationDryRun(t *testing.T) {
 		true)
 }
 
+// TestPreMigrationCheck ensures that a failing pre-migration check prevents
+// any migration from being applied.
+func TestPreMigrationCheck(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	require.NoError(t, cdb.PutMeta(&Meta{DbVersionNumber: 0}))
+
+	var migrated bool
+	versions := []mandatoryVersion{
+		{
+			number:    0,
+			migration: nil,
+		},
+		{
+			number: 1,
+			migration: func(kvdb.RwTx) error {
+				migrated = true
+				return nil
+			},
+		},
+	}
+
+	errNoSpace := errors.New("not enough disk space")
+	cdb.preMigrationCheck = func() error {
+		return errNoSpace
+	}
+	require.ErrorIs(t, cdb.syncVersions(versions), errNoSpace)
+	require.False(t, migrated)
+
+	meta, err := cdb.FetchMeta()
+	require.NoError(t, err)
+	require.Zero(t, meta.DbVersionNumber)
+
+	// The optional migrations aren't applied either.
+	err = cdb.applyOptionalVersions(OptionalMiragtionConfig{
+		PruneRevocationLog: true,
+	})
+	require.ErrorIs(t, err, errNoSpace)
+
+	// Once the check passes, the migration is applied.
+	cdb.preMigrationCheck = func() error {
+		return nil
+	}
+	require.NoError(t, cdb.syncVersions(versions))
+	require.True(t, migrated)
+}
+
 // TestOptionalMeta checks the basic read and write for the optional meta.
 func TestOptionalMeta(t *testing.T) {
 	t.Parallel()
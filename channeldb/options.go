@@ -54,6 +54,10 @@ type Options struct {
 	// graph cache, so we can pre-allocate the map accordingly.
 	PreAllocCacheNumNodes int
 
+	// MaxCacheChannels is the maximum number of channels held in the
+	// graph cache. If zero, the size of the cache isn't limited.
+	MaxCacheChannels int
+
 	// UseGraphCache denotes whether the in-memory graph cache should be
 	// used or a fallback version that uses the underlying database for
 	// path finding.
@@ -82,6 +86,10 @@ type Options struct {
 	// storeFinalHtlcResolutions determines whether to persistently store
 	// the final resolution of incoming htlcs.
 	storeFinalHtlcResolutions bool
+
+	// preMigrationCheck is called before any migration is applied. If it
+	// returns an error, the migration isn't started.
+	preMigrationCheck func() error
 }
 
 // DefaultOptions returns an Options populated with default values.
@@ -127,6 +135,13 @@ func OptionSetPreAllocCacheNumNodes(n int) OptionModifier {
 	}
 }
 
+// OptionSetMaxCacheChannels sets the MaxCacheChannels to n.
+func OptionSetMaxCacheChannels(n int) OptionModifier {
+	return func(o *Options) {
+		o.MaxCacheChannels = n
+	}
+}
+
 // OptionSetUseGraphCache sets the UseGraphCache option to the given value.
 func OptionSetUseGraphCache(use bool) OptionModifier {
 	return func(o *Options) {
@@ -213,6 +228,15 @@ func OptionStoreFinalHtlcResolutions(
 	}
 }
 
+// OptionPreMigrationCheck sets a check that is run before any migration is
+// applied, e.g. to make sure there is enough disk space for it. If the check
+// fails, opening the database fails without migrating it.
+func OptionPreMigrationCheck(check func() error) OptionModifier {
+	return func(o *Options) {
+		o.preMigrationCheck = check
+	}
+}
+
 // OptionPruneRevocationLog specifies whether the migration for pruning
 // revocation logs needs to be applied or not.
 func OptionPruneRevocationLog(prune bool) OptionModifier {
@@ -561,6 +561,89 @@ func TestPaymentControlDeletePayments(t *testing.T) {
 	assertPayments(t, db, payments[2:])
 }
 
+// TestPaymentControlPruneFailedPayments tests that PruneFailedPayments only
+// deletes failed payments created before the given time, and reports how many
+// payments and HTLC attempts were deleted, including the attempts of legacy
+// duplicate payments.
+func TestPaymentControlPruneFailedPayments(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to init db")
+
+	pControl := NewPaymentControl(db)
+
+	// Register two failed payments with two failed attempts each, one
+	// successful and one in-flight payment.
+	payments := []*payment{
+		{status: StatusFailed},
+		{status: StatusSucceeded},
+		{status: StatusInFlight},
+		{status: StatusFailed},
+	}
+	createTestPayments(t, pControl, payments)
+	assertPayments(t, db, payments)
+
+	// None of the payments were created before an hour ago.
+	numPayments, numAttempts, err := db.PruneFailedPayments(
+		time.Now().Add(-time.Hour),
+	)
+	require.NoError(t, err)
+	require.Zero(t, numPayments)
+	require.Zero(t, numAttempts)
+	assertPayments(t, db, payments)
+
+	// Give the first failed payment a legacy duplicate payment with an
+	// attempt, which is deleted along with it.
+	appendDuplicatePayment(
+		t, db, payments[0].id, 100, lntypes.Preimage{},
+	)
+	addDuplicateAttempt(t, db, payments[0].id, 100)
+
+	// Only the failed payments are deleted, together with their attempts.
+	// Deleting one payment per batch, they're deleted in separate
+	// transactions.
+	numPayments, numAttempts, err = db.pruneFailedPayments(
+		time.Now().Add(time.Minute), 1,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, numPayments)
+	require.Equal(t, 5, numAttempts)
+	assertPayments(t, db, payments[1:3])
+
+	// Nothing is left to prune.
+	numPayments, _, err = db.PruneFailedPayments(
+		time.Now().Add(time.Minute),
+	)
+	require.NoError(t, err)
+	require.Zero(t, numPayments)
+}
+
+// addDuplicateAttempt stores an attempt for the legacy duplicate payment with
+// the given sequence number of the given payment.
+func addDuplicateAttempt(t *testing.T, db *DB, paymentHash lntypes.Hash,
+	seqNr uint64) {
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		bucket, err := fetchPaymentBucketUpdate(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		var sequenceKey [8]byte
+		byteOrder.PutUint64(sequenceKey[:], seqNr)
+
+		duplicate := bucket.NestedReadWriteBucket(
+			duplicatePaymentsBucket,
+		).NestedReadWriteBucket(sequenceKey[:])
+
+		return duplicate.Put(
+			duplicatePaymentAttemptInfoKey, []byte{0},
+		)
+	}, func() {})
+	require.NoError(t, err)
+}
+
 // TestPaymentControlDeleteSinglePayment tests that DeletePayment correctly
 // deletes information about a completed payment from the database.
 func TestPaymentControlDeleteSinglePayment(t *testing.T) {
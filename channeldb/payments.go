@@ -950,6 +950,176 @@ func (d *DB) DeletePayments(failedOnly, failedHtlcsOnly bool) error {
 	}, func() {})
 }
 
+// pruneFailedPaymentsBatchSize is the maximum number of payments deleted
+// within a single database transaction when pruning failed payments. Each of
+// them deletes at least the payment bucket and its index entry, so this keeps
+// every transaction to a few thousand keys.
+const pruneFailedPaymentsBatchSize = 1000
+
+// PruneFailedPayments deletes all failed payments that were created before the
+// given time, including their HTLC attempts. Successful and in-flight payments
+// are never deleted. The payments are deleted in bounded batches, each within
+// its own database transaction, so pruning a large number of payments doesn't
+// hold a single huge write transaction. The number of deleted payments and
+// HTLC attempts is returned, including the ones of batches deleted before an
+// error occurred.
+func (d *DB) PruneFailedPayments(before time.Time) (int, int, error) {
+	return d.pruneFailedPayments(before, pruneFailedPaymentsBatchSize)
+}
+
+// pruneBatch is the outcome of deleting a single batch of failed payments.
+type pruneBatch struct {
+	// numPayments is the number of payments deleted in the batch.
+	numPayments int
+
+	// numAttempts is the number of HTLC attempts deleted in the batch.
+	numAttempts int
+
+	// lastKey is the key of the last payment scanned, after which the next
+	// batch resumes.
+	lastKey []byte
+
+	// done is true if all payments were scanned.
+	done bool
+}
+
+// pruneFailedPayments deletes all failed payments that were created before
+// the given time in batches of at most the given number of payments.
+func (d *DB) pruneFailedPayments(before time.Time,
+	batchSize int) (int, int, error) {
+
+	var (
+		numPayments, numAttempts int
+		lastKey                  []byte
+	)
+	for {
+		var batch *pruneBatch
+		err := kvdb.Update(d, func(tx kvdb.RwTx) error {
+			var err error
+			batch, err = pruneFailedPaymentsBatch(
+				tx, before, lastKey, batchSize,
+			)
+
+			return err
+		}, func() {
+			batch = nil
+		})
+		if err != nil {
+			return numPayments, numAttempts, err
+		}
+
+		numPayments += batch.numPayments
+		numAttempts += batch.numAttempts
+
+		if batch.done {
+			return numPayments, numAttempts, nil
+		}
+		lastKey = batch.lastKey
+	}
+}
+
+// pruneFailedPaymentsBatch deletes at most batchSize failed payments created
+// before the given time, scanning the payments after the given key.
+func pruneFailedPaymentsBatch(tx kvdb.RwTx, before time.Time, afterKey []byte,
+	batchSize int) (*pruneBatch, error) {
+
+	payments := tx.ReadWriteBucket(paymentsRootBucket)
+	if payments == nil {
+		return &pruneBatch{done: true}, nil
+	}
+
+	var (
+		deleteBuckets, deleteIndexes [][]byte
+		numAttempts                  int
+		lastKey                      []byte
+	)
+
+	cursor := payments.ReadCursor()
+	k, _ := cursor.First()
+	if afterKey != nil {
+		k, _ = cursor.Seek(afterKey)
+		if bytes.Equal(k, afterKey) {
+			k, _ = cursor.Next()
+		}
+	}
+
+	for ; k != nil && len(deleteBuckets) < batchSize; k, _ = cursor.Next() {
+		lastKey = append([]byte(nil), k...)
+
+		bucket := payments.NestedReadBucket(k)
+		if bucket == nil {
+			// We only expect sub-buckets to be found in this
+			// top-level bucket.
+			return nil, fmt.Errorf("non bucket element in " +
+				"payments bucket")
+		}
+
+		// A failed payment has no in-flight HTLCs left, so it's always
+		// safe to delete.
+		paymentStatus, err := fetchPaymentStatus(bucket)
+		if err != nil {
+			return nil, err
+		}
+		if paymentStatus != StatusFailed {
+			continue
+		}
+
+		creationInfo, err := fetchCreationInfo(bucket)
+		if err != nil {
+			return nil, err
+		}
+		if !creationInfo.CreationTime.Before(before) {
+			continue
+		}
+
+		failedHtlcs, err := fetchFailedHtlcKeys(bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		// The attempts of legacy duplicate payments are deleted along
+		// with the payment, so we count them too.
+		duplicateAttempts, err := countDuplicateAttempts(bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get all the sequence number associated with the payment,
+		// including duplicates.
+		seqNrs, err := fetchSequenceNumbers(bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		deleteBuckets = append(deleteBuckets, lastKey)
+		deleteIndexes = append(deleteIndexes, seqNrs...)
+		numAttempts += len(failedHtlcs) + duplicateAttempts
+	}
+
+	for _, key := range deleteBuckets {
+		if err := payments.DeleteNestedBucket(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get our index bucket and delete all indexes pointing to the payments
+	// we are deleting.
+	indexBucket := tx.ReadWriteBucket(paymentsIndexBucket)
+	for _, key := range deleteIndexes {
+		if err := indexBucket.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// All payments were scanned once the cursor ran out of keys.
+	return &pruneBatch{
+		numPayments: len(deleteBuckets),
+		numAttempts: numAttempts,
+		lastKey:     lastKey,
+		done:        k == nil,
+	}, nil
+}
+
 // fetchSequenceNumbers fetches all the sequence numbers associated with a
 // payment, including those belonging to any duplicate payments.
 func fetchSequenceNumbers(paymentBucket kvdb.RBucket) ([][]byte, error) {
@@ -0,0 +1,292 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const (
+	// DefaultPeerAddrMaxAge is the default time after which an address we
+	// last connected to a peer over successfully is considered stale and
+	// evicted from the peer address cache.
+	DefaultPeerAddrMaxAge = 30 * 24 * time.Hour
+)
+
+var (
+	// peerAddrCacheBucket is the top level bucket of the peer address
+	// cache. It holds a sub-bucket for each peer, keyed by its compressed
+	// public key, that maps the serialized addresses we connected to the
+	// peer over to the unix nano timestamp of the last successful
+	// connection.
+	//
+	// peer-addr-cache
+	//    |
+	//    |-- <peer pubkey>
+	//    |      |-- <serialized address>: <last success>
+	//    |      |-- ...
+	//    |-- ...
+	peerAddrCacheBucket = []byte("peer-addr-cache")
+
+	// ErrPeerAddrCacheNotFound is returned if the peer address cache
+	// bucket doesn't exist.
+	ErrPeerAddrCacheNotFound = errors.New("peer address cache not found")
+)
+
+// CachedPeerAddr is an address we connected to a peer over successfully.
+type CachedPeerAddr struct {
+	// Address is the address of the peer.
+	Address net.Addr
+
+	// LastSuccess is the time of the last successful connection to the
+	// peer over the address.
+	LastSuccess time.Time
+}
+
+// PeerAddrCacheConfig houses the parameters of a PeerAddrCache.
+type PeerAddrCacheConfig struct {
+	// MaxAddrs is the maximum number of addresses that are cached per
+	// peer. Once exceeded, the least recently successful addresses are
+	// evicted.
+	MaxAddrs int
+
+	// MaxAge is the time after which an address that wasn't connected to
+	// successfully again is evicted.
+	MaxAge time.Duration
+
+	// Clock is the time source used to timestamp successful connections
+	// and determine stale addresses.
+	Clock clock.Clock
+}
+
+// PeerAddrCache persists the addresses we recently connected to peers over
+// successfully, so they can be tried on reconnect even if the peer's address
+// changed before its gossip caught up.
+type PeerAddrCache struct {
+	cfg PeerAddrCacheConfig
+	db  kvdb.Backend
+}
+
+// NewPeerAddrCache creates a new PeerAddrCache backed by the given database.
+func NewPeerAddrCache(db kvdb.Backend,
+	cfg PeerAddrCacheConfig) (*PeerAddrCache, error) {
+
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(peerAddrCacheBucket)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PeerAddrCache{
+		cfg: cfg,
+		db:  db,
+	}, nil
+}
+
+// AddPeerAddr records a successful connection to the given peer over the
+// given address. Stale addresses of the peer and the least recently successful
+// ones exceeding the size limit are evicted.
+func (c *PeerAddrCache) AddPeerAddr(pub *btcec.PublicKey,
+	addr net.Addr) error {
+
+	var addrBuf bytes.Buffer
+	if err := serializeAddr(&addrBuf, addr); err != nil {
+		return err
+	}
+
+	now := c.cfg.Clock.Now()
+	var tsBuf [8]byte
+	byteOrder.PutUint64(tsBuf[:], uint64(now.UnixNano()))
+
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		cacheBucket := tx.ReadWriteBucket(peerAddrCacheBucket)
+		if cacheBucket == nil {
+			return ErrPeerAddrCacheNotFound
+		}
+
+		peerBucket, err := cacheBucket.CreateBucketIfNotExists(
+			pub.SerializeCompressed(),
+		)
+		if err != nil {
+			return err
+		}
+
+		err = peerBucket.Put(addrBuf.Bytes(), tsBuf[:])
+		if err != nil {
+			return err
+		}
+
+		return c.evict(peerBucket, now)
+	}, func() {})
+}
+
+// cachedEntry is a raw entry of a peer's sub-bucket.
+type cachedEntry struct {
+	key         []byte
+	lastSuccess time.Time
+}
+
+// evict removes the stale entries of the given peer bucket and the least
+// recently successful ones exceeding the size limit.
+func (c *PeerAddrCache) evict(peerBucket kvdb.RwBucket, now time.Time) error {
+	var entries []cachedEntry
+	err := peerBucket.ForEach(func(k, v []byte) error {
+		if len(v) != 8 {
+			return nil
+		}
+
+		entries = append(entries, cachedEntry{
+			key:         append([]byte(nil), k...),
+			lastSuccess: time.Unix(0, int64(byteOrder.Uint64(v))),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sort the entries with the most recently successful first, so all
+	// entries past the size limit can be evicted.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastSuccess.After(entries[j].lastSuccess)
+	})
+
+	for i, entry := range entries {
+		if i < c.cfg.MaxAddrs && !c.isStale(entry.lastSuccess, now) {
+			continue
+		}
+
+		if err := peerBucket.Delete(entry.key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isStale returns true if an address last connected to successfully at the
+// given time is stale.
+func (c *PeerAddrCache) isStale(lastSuccess, now time.Time) bool {
+	return c.cfg.MaxAge > 0 && now.Sub(lastSuccess) > c.cfg.MaxAge
+}
+
+// FetchPeerAddrs returns the cached addresses of the given peer that aren't
+// stale, with the most recently successful one first.
+func (c *PeerAddrCache) FetchPeerAddrs(
+	pub *btcec.PublicKey) ([]CachedPeerAddr, error) {
+
+	now := c.cfg.Clock.Now()
+
+	var addrs []CachedPeerAddr
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		cacheBucket := tx.ReadBucket(peerAddrCacheBucket)
+		if cacheBucket == nil {
+			return ErrPeerAddrCacheNotFound
+		}
+
+		peerBucket := cacheBucket.NestedReadBucket(
+			pub.SerializeCompressed(),
+		)
+		if peerBucket == nil {
+			return nil
+		}
+
+		return peerBucket.ForEach(func(k, v []byte) error {
+			if len(v) != 8 {
+				return nil
+			}
+
+			lastSuccess := time.Unix(0, int64(byteOrder.Uint64(v)))
+			if c.isStale(lastSuccess, now) {
+				return nil
+			}
+
+			addr, err := deserializeAddr(bytes.NewReader(k))
+			if err != nil {
+				return err
+			}
+
+			addrs = append(addrs, CachedPeerAddr{
+				Address:     addr,
+				LastSuccess: lastSuccess,
+			})
+
+			return nil
+		})
+	}, func() {
+		addrs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].LastSuccess.After(addrs[j].LastSuccess)
+	})
+
+	return addrs, nil
+}
+
+// Prune evicts the stale addresses of all peers and removes peers without any
+// remaining addresses.
+func (c *PeerAddrCache) Prune() error {
+	now := c.cfg.Clock.Now()
+
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		cacheBucket := tx.ReadWriteBucket(peerAddrCacheBucket)
+		if cacheBucket == nil {
+			return ErrPeerAddrCacheNotFound
+		}
+
+		// Collect the peers first, as the bucket must not be modified
+		// while iterating over it.
+		var peers [][]byte
+		err := cacheBucket.ForEach(func(k, _ []byte) error {
+			peers = append(peers, append([]byte(nil), k...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, peer := range peers {
+			peerBucket := cacheBucket.NestedReadWriteBucket(peer)
+			if peerBucket == nil {
+				continue
+			}
+
+			if err := c.evict(peerBucket, now); err != nil {
+				return err
+			}
+
+			// Remove the peer's bucket altogether if all of its
+			// addresses were evicted.
+			empty := true
+			err := peerBucket.ForEach(func(_, _ []byte) error {
+				empty = false
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if empty {
+				err := cacheBucket.DeleteNestedBucket(peer)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}, func() {})
+}
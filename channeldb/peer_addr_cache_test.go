@@ -0,0 +1,80 @@
+package channeldb
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerAddrCache tests that successful peer addresses are cached with the
+// most recent one first, and that stale addresses and those exceeding the size
+// limit are evicted.
+func TestPeerAddrCache(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	startTime := time.Unix(1_700_000_000, 0)
+	testClock := clock.NewTestClock(startTime)
+
+	cache, err := NewPeerAddrCache(fullDB, PeerAddrCacheConfig{
+		MaxAddrs: 2,
+		MaxAge:   time.Hour,
+		Clock:    testClock,
+	})
+	require.NoError(t, err)
+
+	_, pub := btcec.PrivKeyFromBytes(key[:])
+
+	addr1 := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9735}
+	addr2 := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+	addr3 := &net.TCPAddr{IP: net.ParseIP("10.0.0.3"), Port: 9735}
+
+	assertAddrs := func(expected ...net.Addr) {
+		t.Helper()
+
+		cached, err := cache.FetchPeerAddrs(pub)
+		require.NoError(t, err)
+		require.Len(t, cached, len(expected))
+		for i, addr := range expected {
+			require.Equal(
+				t, addr.String(), cached[i].Address.String(),
+			)
+		}
+	}
+
+	// Nothing is cached for an unknown peer.
+	assertAddrs()
+
+	require.NoError(t, cache.AddPeerAddr(pub, addr1))
+	testClock.SetTime(startTime.Add(time.Minute))
+	require.NoError(t, cache.AddPeerAddr(pub, addr2))
+	assertAddrs(addr2, addr1)
+
+	// Connecting over the first address again makes it the most recent
+	// one.
+	testClock.SetTime(startTime.Add(2 * time.Minute))
+	require.NoError(t, cache.AddPeerAddr(pub, addr1))
+	assertAddrs(addr1, addr2)
+
+	// Adding a third address evicts the least recently successful one.
+	testClock.SetTime(startTime.Add(3 * time.Minute))
+	require.NoError(t, cache.AddPeerAddr(pub, addr3))
+	assertAddrs(addr3, addr1)
+
+	// Once an address is stale, it's no longer returned and pruned from
+	// the database.
+	testClock.SetTime(startTime.Add(time.Hour + 150*time.Second))
+	assertAddrs(addr3)
+
+	// Pruning removes the stale entries from the database, so they aren't
+	// returned even without a maximum age.
+	require.NoError(t, cache.Prune())
+	cache.cfg.MaxAge = 0
+	assertAddrs(addr3)
+}
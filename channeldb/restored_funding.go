@@ -0,0 +1,67 @@
+package channeldb
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// restoredFundingBucket is the top-level bucket that stores the outcome of
+// looking up the funding outputs of channels restored from a static channel
+// backup on chain, keyed by their channel point.
+var restoredFundingBucket = []byte("restored-funding-outputs")
+
+// PutRestoredFundingStatus stores the status of the funding output of the
+// given restored channel, replacing any status stored before. The status is
+// opaque to the channel DB.
+func (d *DB) PutRestoredFundingStatus(chanPoint wire.OutPoint,
+	status uint8) error {
+
+	var key bytes.Buffer
+	if err := writeOutpoint(&key, &chanPoint); err != nil {
+		return err
+	}
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(restoredFundingBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key.Bytes(), []byte{status})
+	}, func() {})
+}
+
+// FetchRestoredFundingStatus returns the stored status of the funding output
+// of the given restored channel. Zero is returned if no status was stored.
+func (d *DB) FetchRestoredFundingStatus(chanPoint wire.OutPoint) (uint8,
+	error) {
+
+	var key bytes.Buffer
+	if err := writeOutpoint(&key, &chanPoint); err != nil {
+		return 0, err
+	}
+
+	var status uint8
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(restoredFundingBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		value := bucket.Get(key.Bytes())
+		if len(value) == 1 {
+			status = value[0]
+		}
+
+		return nil
+	}, func() {
+		status = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return status, nil
+}
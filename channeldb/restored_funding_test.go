@@ -0,0 +1,41 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRestoredFundingStatus tests that the status of the funding output of a
+// restored channel is persisted and that the latest status is kept.
+func TestRestoredFundingStatus(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	first := wire.OutPoint{Index: 1}
+	second := wire.OutPoint{Index: 2}
+
+	status, err := db.FetchRestoredFundingStatus(first)
+	require.NoError(t, err)
+	require.Zero(t, status)
+
+	require.NoError(t, db.PutRestoredFundingStatus(first, 2))
+	require.NoError(t, db.PutRestoredFundingStatus(second, 1))
+
+	status, err = db.FetchRestoredFundingStatus(first)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, status)
+
+	require.NoError(t, db.PutRestoredFundingStatus(first, 3))
+
+	status, err = db.FetchRestoredFundingStatus(first)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, status)
+
+	status, err = db.FetchRestoredFundingStatus(second)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, status)
+}
@@ -0,0 +1,82 @@
+package channeldb
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// safeReconnectBucket is the top-level bucket that stores the state of
+	// the safe reconnect mode. It lives in the channel DB itself, so that
+	// restoring an older copy of the channel DB also restores the state
+	// that belongs to it.
+	safeReconnectBucket = []byte("safe-reconnect")
+
+	// safeReconnectHaltedKey stores the channel point of the channel whose
+	// outdated local state halted all broadcasts.
+	safeReconnectHaltedKey = []byte("halted-by")
+)
+
+// SafeReconnectState is the persisted state of the safe reconnect mode.
+type SafeReconnectState struct {
+	// HaltedBy is the channel point of the channel whose outdated local
+	// state halted all broadcasts. It is nil if broadcasts aren't halted.
+	HaltedBy *wire.OutPoint
+}
+
+// FetchSafeReconnectState returns the persisted state of the safe reconnect
+// mode.
+func (d *DB) FetchSafeReconnectState() (*SafeReconnectState, error) {
+	var state *SafeReconnectState
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(safeReconnectBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		haltedBy := bucket.Get(safeReconnectHaltedKey)
+		if haltedBy == nil {
+			return nil
+		}
+
+		var chanPoint wire.OutPoint
+		err := readOutpoint(bytes.NewReader(haltedBy), &chanPoint)
+		if err != nil {
+			return err
+		}
+		state.HaltedBy = &chanPoint
+
+		return nil
+	}, func() {
+		state = &SafeReconnectState{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// HaltBroadcasts persistently records that the outdated local state of the
+// given channel halted all broadcasts. The first channel recorded is kept.
+func (d *DB) HaltBroadcasts(chanPoint wire.OutPoint) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(safeReconnectBucket)
+		if err != nil {
+			return err
+		}
+
+		if bucket.Get(safeReconnectHaltedKey) != nil {
+			return nil
+		}
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, &chanPoint); err != nil {
+			return err
+		}
+
+		return bucket.Put(safeReconnectHaltedKey, b.Bytes())
+	}, func() {})
+}
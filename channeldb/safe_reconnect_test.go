@@ -0,0 +1,30 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSafeReconnectState tests that the state of the safe reconnect mode is
+// persisted and that the first channel halting broadcasts is kept.
+func TestSafeReconnectState(t *testing.T) {
+	t.Parallel()
+
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	state, err := db.FetchSafeReconnectState()
+	require.NoError(t, err)
+	require.Equal(t, &SafeReconnectState{}, state)
+
+	first := wire.OutPoint{Index: 1}
+	second := wire.OutPoint{Index: 2}
+	require.NoError(t, db.HaltBroadcasts(first))
+	require.NoError(t, db.HaltBroadcasts(second))
+
+	state, err = db.FetchSafeReconnectState()
+	require.NoError(t, err)
+	require.Equal(t, &first, state.HaltedBy)
+}
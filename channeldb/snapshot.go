@@ -0,0 +1,66 @@
+package channeldb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteSnapshot writes a consistent point-in-time copy of the whole database
+// to a new file at the given path and returns its size in bytes. The copy is
+// created within a single read transaction of the backend, so it is consistent
+// across all buckets and the live database is never modified. The snapshot is
+// first written to a temporary file in the target directory and only linked to
+// the target path once it is complete. An existing file is never overwritten,
+// even if it is created while the snapshot is being written.
+//
+// NOTE: Only backends that support copying their content, such as bolt and
+// etcd, can be snapshotted.
+func (d *DB) WriteSnapshot(path string) (int64, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("snapshot file %v already exists", path)
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	tempFile, err := os.CreateTemp(
+		filepath.Dir(path), filepath.Base(path)+".tmp-*",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create snapshot file: %w", err)
+	}
+
+	// The temporary file is always removed. Once it was linked to the
+	// target path, this only removes the temporary name.
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	if err := d.Backend.Copy(tempFile); err != nil {
+		return 0, fmt.Errorf("unable to copy database: %w", err)
+	}
+
+	// Make sure the snapshot is fully written to disk before it appears
+	// at the target path.
+	if err := tempFile.Sync(); err != nil {
+		return 0, err
+	}
+
+	info, err := tempFile.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return 0, err
+	}
+
+	// Unlike a rename, creating a link fails if the target path exists,
+	// so a file that appeared in the meantime isn't replaced.
+	if err := os.Link(tempFile.Name(), path); err != nil {
+		return 0, fmt.Errorf("unable to move snapshot file: %w", err)
+	}
+
+	return info.Size(), nil
+}
@@ -0,0 +1,66 @@
+package channeldb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteSnapshot asserts that a snapshot of the database contains the data
+// that was written before it was taken, and that existing files aren't
+// overwritten.
+func TestWriteSnapshot(t *testing.T) {
+	t.Parallel()
+
+	if kvdb.PostgresBackend || kvdb.SqliteBackend || kvdb.EtcdBackend {
+		t.Skip("snapshot is only tested with the bolt backend")
+	}
+
+	cdb, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	preimage := lntypes.Preimage(rev)
+	require.NoError(t, cdb.NewWitnessCache().AddSha256Witnesses(preimage))
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+	size, err := cdb.WriteSnapshot(snapshotPath)
+	require.NoError(t, err)
+	require.Positive(t, size)
+
+	// Data written after the snapshot was taken isn't part of it.
+	laterPreimage := lntypes.Preimage(key)
+	require.NoError(t, cdb.NewWitnessCache().AddSha256Witnesses(
+		laterPreimage,
+	))
+
+	snapshot, err := kvdb.Open(
+		kvdb.BoltBackendName, snapshotPath, true,
+		kvdb.DefaultDBTimeout,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, snapshot.Close())
+	})
+
+	wCache := NewWitnessCache(snapshot, nil)
+	dbPreimage, err := wCache.LookupSha256Witness(preimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, preimage, dbPreimage)
+
+	_, err = wCache.LookupSha256Witness(laterPreimage.Hash())
+	require.ErrorIs(t, err, ErrNoWitnesses)
+
+	// An existing snapshot is never overwritten.
+	_, err = cdb.WriteSnapshot(snapshotPath)
+	require.Error(t, err)
+
+	// No temporary files are left behind, whether the snapshot was written
+	// or not.
+	files, err := os.ReadDir(filepath.Dir(snapshotPath))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -1,9 +1,11 @@
 package channeldb
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lntypes"
 )
 
@@ -58,15 +60,29 @@ var (
 // use this cache to detect duplicate witnesses.
 //
 // TODO(roasbeef): need expiry policy?
-//   - encrypt?
 type WitnessCache struct {
-	db *DB
+	backend kvdb.Backend
+
+	// encrypter, if set, is used to encrypt all witnesses before they're
+	// written to disk, and to decrypt them again when they're looked up.
+	encrypter lnencrypt.EncrypterDecrypter
 }
 
-// NewWitnessCache returns a new instance of the witness cache.
+// NewWitnessCache returns a new instance of the witness cache that stores
+// witnesses unencrypted within the channel database.
 func (d *DB) NewWitnessCache() *WitnessCache {
+	return NewWitnessCache(d.Backend, nil)
+}
+
+// NewWitnessCache returns a new instance of the witness cache that stores
+// witnesses within the given database backend. If an encrypter is passed, the
+// witnesses are encrypted at rest.
+func NewWitnessCache(backend kvdb.Backend,
+	encrypter lnencrypt.EncrypterDecrypter) *WitnessCache {
+
 	return &WitnessCache{
-		db: d,
+		backend:   backend,
+		encrypter: encrypter,
 	}
 }
 
@@ -106,7 +122,29 @@ func (w *WitnessCache) addWitnessEntries(wType WitnessType,
 		return nil
 	}
 
-	return kvdb.Batch(w.db.Backend, func(tx kvdb.RwTx) error {
+	// Encrypt the witnesses before starting the db transaction, as the
+	// batch closure might be executed more than once.
+	if w.encrypter != nil {
+		encrypted := make([]witnessEntry, 0, len(entries))
+		for _, entry := range entries {
+			var b bytes.Buffer
+			err := w.encrypter.EncryptPayloadToWriter(
+				entry.witness, &b,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to encrypt "+
+					"witness: %w", err)
+			}
+
+			encrypted = append(encrypted, witnessEntry{
+				key:     entry.key,
+				witness: b.Bytes(),
+			})
+		}
+		entries = encrypted
+	}
+
+	return kvdb.Batch(w.backend, func(tx kvdb.RwTx) error {
 		witnessBucket, err := tx.CreateTopLevelBucket(witnessBucketKey)
 		if err != nil {
 			return err
@@ -150,7 +188,7 @@ func (w *WitnessCache) LookupSha256Witness(hash lntypes.Hash) (lntypes.Preimage,
 // will be returned.
 func (w *WitnessCache) lookupWitness(wType WitnessType, witnessKey []byte) ([]byte, error) {
 	var witness []byte
-	err := kvdb.View(w.db, func(tx kvdb.RTx) error {
+	err := kvdb.View(w.backend, func(tx kvdb.RTx) error {
 		witnessBucket := tx.ReadBucket(witnessBucketKey)
 		if witnessBucket == nil {
 			return ErrNoWitnesses
@@ -181,6 +219,17 @@ func (w *WitnessCache) lookupWitness(wType WitnessType, witnessKey []byte) ([]by
 		return nil, err
 	}
 
+	if w.encrypter == nil {
+		return witness, nil
+	}
+
+	witness, err = w.encrypter.DecryptPayloadFromReader(
+		bytes.NewReader(witness),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt witness: %w", err)
+	}
+
 	return witness, nil
 }
 
@@ -191,7 +240,7 @@ func (w *WitnessCache) DeleteSha256Witness(hash lntypes.Hash) error {
 
 // deleteWitness attempts to delete a particular witness from the database.
 func (w *WitnessCache) deleteWitness(wType WitnessType, witnessKey []byte) error {
-	return kvdb.Batch(w.db.Backend, func(tx kvdb.RwTx) error {
+	return kvdb.Batch(w.backend, func(tx kvdb.RwTx) error {
 		witnessBucket, err := tx.CreateTopLevelBucket(witnessBucketKey)
 		if err != nil {
 			return err
@@ -215,7 +264,7 @@ func (w *WitnessCache) deleteWitness(wType WitnessType, witnessKey []byte) error
 // DeleteWitnessClass attempts to delete an *entire* class of witnesses. After
 // this function return with a non-nil error,
 func (w *WitnessCache) DeleteWitnessClass(wType WitnessType) error {
-	return kvdb.Batch(w.db.Backend, func(tx kvdb.RwTx) error {
+	return kvdb.Batch(w.backend, func(tx kvdb.RwTx) error {
 		witnessBucket, err := tx.CreateTopLevelBucket(witnessBucketKey)
 		if err != nil {
 			return err
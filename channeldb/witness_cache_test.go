@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"testing"
 
+	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/stretchr/testify/require"
 )
@@ -94,6 +95,36 @@ func TestWitnessCacheSha256Deletion(t *testing.T) {
 	}
 }
 
+// TestWitnessCacheEncryption tests that preimages added to an encrypted witness
+// cache can be looked up again, but aren't stored in plaintext.
+func TestWitnessCacheEncryption(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	encrypter, err := lnencrypt.KeyRingEncrypter(&lnencrypt.MockKeyRing{})
+	require.NoError(t, err)
+
+	wCache := NewWitnessCache(cdb.Backend, encrypter)
+
+	preimage := lntypes.Preimage(rev)
+	require.NoError(t, wCache.AddSha256Witnesses(preimage))
+
+	dbPreimage, err := wCache.LookupSha256Witness(preimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, preimage, dbPreimage)
+
+	// Reading the same witness without the encrypter doesn't reveal the
+	// preimage.
+	hash := preimage.Hash()
+	ciphertext, err := cdb.NewWitnessCache().lookupWitness(
+		Sha256HashWitness, hash[:],
+	)
+	require.NoError(t, err)
+	require.NotContains(t, string(ciphertext), string(preimage[:]))
+}
+
 // TestWitnessCacheUnknownWitness tests that we get an error if we attempt to
 // query/add/delete an unknown witness.
 func TestWitnessCacheUnknownWitness(t *testing.T) {
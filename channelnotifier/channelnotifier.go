@@ -1,6 +1,7 @@
 package channelnotifier
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/btcsuite/btcd/wire"
@@ -80,6 +81,88 @@ type FullyResolvedChannelEvent struct {
 	ChannelPoint *wire.OutPoint
 }
 
+// ReestablishFailureReason describes why the channel state couldn't be
+// synchronized with the peer when the channel was reestablished.
+type ReestablishFailureReason uint8
+
+const (
+	// ReestablishFailureUnknown is used for failures that don't have a
+	// more specific reason, for example if the peer didn't respond.
+	ReestablishFailureUnknown ReestablishFailureReason = iota
+
+	// ReestablishFailureRemoteDataLoss means that the peer's state is
+	// behind ours, so it likely lost data.
+	ReestablishFailureRemoteDataLoss
+
+	// ReestablishFailureLocalDataLoss means that the peer proved that our
+	// state is behind, so we likely lost data.
+	ReestablishFailureLocalDataLoss
+
+	// ReestablishFailureInvalidCommitSecret means that the peer sent an
+	// invalid last commitment secret.
+	ReestablishFailureInvalidCommitSecret
+
+	// ReestablishFailureInvalidCommitPoint means that the peer sent a
+	// commitment point that differs from the one it sent before.
+	ReestablishFailureInvalidCommitPoint
+
+	// ReestablishFailureCannotSyncCommitChains means that the commitment
+	// chains of both sides are out of sync in a way that can't be
+	// recovered from.
+	ReestablishFailureCannotSyncCommitChains
+)
+
+// String returns a human readable representation of the failure reason.
+func (r ReestablishFailureReason) String() string {
+	switch r {
+	case ReestablishFailureUnknown:
+		return "unknown"
+
+	case ReestablishFailureRemoteDataLoss:
+		return "remote_data_loss"
+
+	case ReestablishFailureLocalDataLoss:
+		return "local_data_loss"
+
+	case ReestablishFailureInvalidCommitSecret:
+		return "invalid_commit_secret"
+
+	case ReestablishFailureInvalidCommitPoint:
+		return "invalid_commit_point"
+
+	case ReestablishFailureCannotSyncCommitChains:
+		return "cannot_sync_commit_chains"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(r))
+	}
+}
+
+// MightBeStaleLocalState returns true if the failure reason indicates that our
+// local state of the channel might be outdated. Only a local data loss counts,
+// as the peer proved it by revealing a commitment secret we can verify. Other
+// failures are based on unverified claims of the peer.
+func (r ReestablishFailureReason) MightBeStaleLocalState() bool {
+	return r == ReestablishFailureLocalDataLoss
+}
+
+// ReestablishFailedEvent represents a new event where the channel state
+// couldn't be synchronized with the peer when the channel was reestablished.
+type ReestablishFailedEvent struct {
+	// ChannelPoint is the channel point of the channel.
+	ChannelPoint *wire.OutPoint
+
+	// Reason is the reason of the failure.
+	Reason ReestablishFailureReason
+
+	// Err is the error that caused the failure.
+	Err error
+
+	// ForceClose is true if the channel is force closed because of the
+	// failure.
+	ForceClose bool
+}
+
 // New creates a new channel notifier. The ChannelNotifier gets channel
 // events from peers and from the chain arbitrator, and dispatches them to
 // its clients.
@@ -219,3 +302,20 @@ func (c *ChannelNotifier) NotifyInactiveChannelEvent(chanPoint wire.OutPoint) {
 		log.Warnf("Unable to send inactive channel update: %v", err)
 	}
 }
+
+// NotifyReestablishFailedEvent notifies the channelEventNotifier goroutine that
+// the channel state couldn't be synchronized with the peer when the channel was
+// reestablished.
+func (c *ChannelNotifier) NotifyReestablishFailedEvent(chanPoint wire.OutPoint,
+	reason ReestablishFailureReason, err error, forceClose bool) {
+
+	event := ReestablishFailedEvent{
+		ChannelPoint: &chanPoint,
+		Reason:       reason,
+		Err:          err,
+		ForceClose:   forceClose,
+	}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send reestablish failed update: %v", err)
+	}
+}
@@ -1,18 +1,28 @@
 package lnd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math"
 	"net"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/shachain"
 )
 
@@ -26,8 +36,32 @@ const (
 	// testnet3 chain of the date when SCBs first were released in lnd
 	// (v0.6.0-beta). The block date is 4/16/2019, 08:04 AM UTC.
 	testnetSCBLaunchBlock = 1489300
+
+	// restoredChanReestablishTimeout is how long we wait for the peer of
+	// restored channels to come online and reestablish them before we
+	// move on to the next peer. The peer is still connected to in the
+	// background after that.
+	restoredChanReestablishTimeout = 2 * time.Minute
 )
 
+// scbLaunchHeight returns the approximate height at which SCBs were launched
+// on the given chain. It serves as the earliest height a channel of a backup
+// can have been opened at.
+func scbLaunchHeight(chainHash chainhash.Hash) uint32 {
+	switch {
+	case chainHash.IsEqual(chaincfg.MainNetParams.GenesisHash):
+		return mainnetSCBLaunchBlock
+
+	case chainHash.IsEqual(chaincfg.TestNet3Params.GenesisHash):
+		return testnetSCBLaunchBlock
+
+	default:
+		// Worst case: We have no height hint and start at block 1.
+		// Should only happen for SCBs in regtest and simnet.
+		return 1
+	}
+}
+
 // chanDBRestorer is an implementation of the chanbackup.ChannelRestorer
 // interface that is able to properly map a Single backup, into a
 // channeldb.ChannelShell which is required to fully restore a channel. We also
@@ -219,20 +253,9 @@ func (c *chanDBRestorer) RestoreChansFromSingles(backups ...chanbackup.Single) e
 	// In case there were only unconfirmed channels, we will have to scan
 	// the chain beginning from the launch date of SCBs.
 	if firstChanHeight == math.MaxUint32 {
-		chainHash := channelShells[0].Chan.ChainHash
-		switch {
-		case chainHash.IsEqual(chaincfg.MainNetParams.GenesisHash):
-			firstChanHeight = mainnetSCBLaunchBlock
-
-		case chainHash.IsEqual(chaincfg.TestNet3Params.GenesisHash):
-			firstChanHeight = testnetSCBLaunchBlock
-
-		default:
-			// Worst case: We have no height hint and start at
-			// block 1. Should only happen for SCBs in regtest
-			// and simnet.
-			firstChanHeight = 1
-		}
+		firstChanHeight = scbLaunchHeight(
+			channelShells[0].Chan.ChainHash,
+		)
 	}
 
 	// If there were channels in the backup that were not confirmed at the
@@ -296,6 +319,309 @@ func (c *chanDBRestorer) RestoreChansFromSingles(backups ...chanbackup.Single) e
 // chanbackup.ChannelRestorer.
 var _ chanbackup.ChannelRestorer = (*chanDBRestorer)(nil)
 
+// chainFundingVerifier is an implementation of the
+// chanbackup.FundingOutputVerifier interface that looks up the funding output
+// of a channel to restore through the chain backend.
+type chainFundingVerifier struct {
+	chainIO lnwallet.BlockChainIO
+
+	keyRing keychain.KeyRing
+}
+
+// VerifyFundingOutput re-creates the funding output of the channel of the
+// given backup and checks that it still exists unspent on chain.
+//
+// NOTE: Part of the chanbackup.FundingOutputVerifier interface.
+func (c *chainFundingVerifier) VerifyFundingOutput(backup *chanbackup.Single,
+	quit <-chan struct{}) (chanbackup.FundingOutputStatus, error) {
+
+	// The btcd and bitcoind backends report an output that isn't confirmed
+	// as spent, so we can't look up the funding output of a channel that
+	// was still pending when it was backed up. Just like when restoring
+	// the channel, a zero TxIndex tells us that the block height is only
+	// the broadcast height of the funding transaction.
+	if backup.ShortChannelID.BlockHeight == 0 ||
+		backup.ShortChannelID.TxIndex == 0 {
+
+		return chanbackup.FundingOutputUnconfirmed, nil
+	}
+
+	// Only the locator of our multisig key is part of the backup, so we
+	// need to derive the key to re-create the funding output.
+	localKey, err := c.keyRing.DeriveKey(
+		backup.LocalChanCfg.MultiSigKey.KeyLocator,
+	)
+	if err != nil {
+		return chanbackup.FundingOutputUnverified, fmt.Errorf("unable "+
+			"to derive multi sig key: %w", err)
+	}
+	remoteKey := backup.RemoteChanCfg.MultiSigKey.PubKey
+
+	var fundingOutput *wire.TxOut
+	if backup.Version == chanbackup.SimpleTaprootVersion {
+		_, fundingOutput, err = input.GenTaprootFundingScript(
+			localKey.PubKey, remoteKey, int64(backup.Capacity),
+		)
+	} else {
+		_, fundingOutput, err = input.GenFundingPkScript(
+			localKey.PubKey.SerializeCompressed(),
+			remoteKey.SerializeCompressed(),
+			int64(backup.Capacity),
+		)
+	}
+	if err != nil {
+		return chanbackup.FundingOutputUnverified, fmt.Errorf("unable "+
+			"to create funding output: %w", err)
+	}
+
+	// Light clients need to scan the chain for the output, so we'll start
+	// at the confirmation height of the channel.
+	txOut, err := c.chainIO.GetUtxo(
+		&backup.FundingOutpoint, fundingOutput.PkScript,
+		backup.ShortChannelID.BlockHeight, quit,
+	)
+	switch {
+	case errors.Is(err, btcwallet.ErrOutputSpent):
+		return chanbackup.FundingOutputSpent, nil
+
+	case errors.Is(err, btcwallet.ErrOutputNotFound):
+		return chanbackup.FundingOutputNotFound, nil
+
+	case err != nil:
+		return chanbackup.FundingOutputUnverified, err
+	}
+
+	if txOut.Value != fundingOutput.Value ||
+		!bytes.Equal(txOut.PkScript, fundingOutput.PkScript) {
+
+		return chanbackup.FundingOutputMismatch, nil
+	}
+
+	return chanbackup.FundingOutputUnspent, nil
+}
+
+// A compile-time constraint to ensure chainFundingVerifier implements
+// chanbackup.FundingOutputVerifier.
+var _ chanbackup.FundingOutputVerifier = (*chainFundingVerifier)(nil)
+
+// fundingOutputVerifier returns the verifier used to check the funding outputs
+// of restored channels on chain, or nil if the verification is disabled.
+func (s *server) fundingOutputVerifier() chanbackup.FundingOutputVerifier {
+	if !s.cfg.VerifyRestoredChannels {
+		return nil
+	}
+
+	return &chainFundingVerifier{
+		chainIO: s.cc.ChainIO,
+		keyRing: s.cc.KeyRing,
+	}
+}
+
+// verifyRestoredFundingOutputs checks the funding outputs of the channels of
+// the given backups on chain and persists the outcome, so that it can be
+// queried through the ChannelRecoveryStatus RPC later on. The status of each
+// funding output is returned in the order of the backups. Funding outputs
+// that couldn't be looked up keep the status stored before.
+func (s *server) verifyRestoredFundingOutputs(backups []chanbackup.Single,
+	quit <-chan struct{}) []chanbackup.FundingOutputStatus {
+
+	verifier := s.fundingOutputVerifier()
+	if verifier == nil {
+		return make([]chanbackup.FundingOutputStatus, len(backups))
+	}
+
+	statuses := chanbackup.VerifyFundingOutputs(backups, verifier, quit)
+	for i, status := range statuses {
+		if status == chanbackup.FundingOutputUnverified {
+			continue
+		}
+
+		chanPoint := backups[i].FundingOutpoint
+		err := s.chanStateDB.PutRestoredFundingStatus(
+			chanPoint, uint8(status),
+		)
+		if err != nil {
+			srvrLog.Errorf("Unable to store funding output status "+
+				"of ChannelPoint(%v): %v", chanPoint, err)
+		}
+	}
+
+	return statuses
+}
+
+// restoreChansFromBackups stores the shells of the channels of the backups that
+// were passed to the server on startup and returns the backups of the channels
+// that were restored.
+func (s *server) restoreChansFromBackups(
+	restorer chanbackup.ChannelRestorer) ([]chanbackup.Single, error) {
+
+	var backups []chanbackup.Single
+	if len(s.chansToRestore.PackedSingleChanBackups) != 0 {
+		singles, err := s.chansToRestore.PackedSingleChanBackups.Unpack(
+			s.cc.KeyRing,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unpack single "+
+				"backups: %v", err)
+		}
+
+		backups = append(backups, singles...)
+	}
+	if len(s.chansToRestore.PackedMultiChanBackup) != 0 {
+		multi, err := s.chansToRestore.PackedMultiChanBackup.Unpack(
+			s.cc.KeyRing,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unpack chan "+
+				"backup: %v", err)
+		}
+
+		backups = append(backups, multi.StaticBackups...)
+	}
+
+	results, err := chanbackup.RestoreFiltered(backups, nil, restorer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to restore chan backups: %v",
+			err)
+	}
+
+	return chanbackup.RestoredBackups(backups, results), nil
+}
+
+// recoverRestoredChannels connects to the peers of the given restored channels
+// to start the data loss recovery protocol. The peers may need a while to come
+// online, so they're connected to in the background.
+func (s *server) recoverRestoredChannels(backups []chanbackup.Single) {
+	if len(backups) == 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := chanbackup.ConnectChannelPeers(
+			backups, s.cfg.Recovery.Concurrency, s,
+		)
+		if err != nil {
+			ltndLog.Errorf("Unable to connect to the peers of "+
+				"restored channels: %v", err)
+		}
+	}()
+}
+
+// WaitForReestablish waits until the target node reestablished the restored
+// channels with the given channel points, for up to
+// restoredChanReestablishTimeout.
+//
+// NOTE: Part of the chanbackup.ChannelReestablisher interface.
+func (s *server) WaitForReestablish(nodePub *btcec.PublicKey,
+	chanPoints []wire.OutPoint) (int, error) {
+
+	// We subscribe to the events before looking at the channels, so we
+	// can't miss any of them.
+	peerSub, err := s.peerNotifier.SubscribePeerEvents()
+	if err != nil {
+		return 0, err
+	}
+	defer peerSub.Cancel()
+
+	chanSub, err := s.channelNotifier.SubscribeChannelEvents()
+	if err != nil {
+		return 0, err
+	}
+	defer chanSub.Cancel()
+
+	var node [33]byte
+	copy(node[:], nodePub.SerializeCompressed())
+
+	// The peer may have reestablished some of the channels since we
+	// connected to it. Once it proved that we lost data, the channel is
+	// marked accordingly, and once it force closed the channel, it's no
+	// longer open.
+	pending := make(map[wire.OutPoint]struct{}, len(chanPoints))
+	for _, chanPoint := range chanPoints {
+		channel, err := s.chanStateDB.FetchChannel(nil, chanPoint)
+		switch {
+		case errors.Is(err, channeldb.ErrChannelNotFound):
+			continue
+
+		case err != nil:
+			return 0, err
+
+		case channel.HasChanStatus(channeldb.ChanStatusLocalDataLoss):
+			continue
+		}
+
+		pending[chanPoint] = struct{}{}
+	}
+
+	timeout := time.After(restoredChanReestablishTimeout)
+	for len(pending) > 0 {
+		select {
+		case update := <-peerSub.Updates():
+			e, ok := update.(peernotifier.PeerOnlineEvent)
+			if !ok || e.PubKey != node {
+				continue
+			}
+
+			ltndLog.Infof("Peer(%x) is online, waiting for it to "+
+				"reestablish %d restored channel(s)", node[:],
+				len(pending))
+
+		// A restored channel can never be synced, so the peer
+		// reestablishing it always shows up as a failure.
+		case update := <-chanSub.Updates():
+			e, ok := update.(channelnotifier.ReestablishFailedEvent)
+			if !ok {
+				continue
+			}
+
+			chanPoint := *e.ChannelPoint
+			if _, ok := pending[chanPoint]; !ok {
+				continue
+			}
+			delete(pending, chanPoint)
+
+			// Only if the peer proved that we lost data, it also
+			// sent us what we need to recover our funds.
+			localDataLoss := channelnotifier.
+				ReestablishFailureLocalDataLoss
+			if e.Reason == localDataLoss {
+				ltndLog.Infof("Peer(%x) reestablished "+
+					"ChannelPoint(%v), waiting for it to "+
+					"force close the channel", node[:],
+					chanPoint)
+
+				continue
+			}
+
+			ltndLog.Warnf("Peer(%x) reestablished "+
+				"ChannelPoint(%v) without sending the data "+
+				"needed to recover our funds: %v", node[:],
+				chanPoint, e.Reason)
+
+		case <-timeout:
+			ltndLog.Warnf("Peer(%x) didn't reestablish %d "+
+				"restored channel(s) within %v, connecting to "+
+				"it in the background", node[:], len(pending),
+				restoredChanReestablishTimeout)
+
+			return len(chanPoints) - len(pending), nil
+
+		case <-s.quit:
+			return len(chanPoints) - len(pending),
+				ErrServerShuttingDown
+		}
+	}
+
+	return len(chanPoints), nil
+}
+
+// A compile-time constraint to ensure server implements
+// chanbackup.ChannelReestablisher.
+var _ chanbackup.ChannelReestablisher = (*server)(nil)
+
 // ConnectPeer attempts to connect to the target node at the set of available
 // addresses. Once this method returns with a non-nil error, the connector
 // should attempt to persistently connect to the target peer in the background
@@ -349,3 +675,159 @@ func (s *server) ConnectPeer(nodePub *btcec.PublicKey, addrs []net.Addr) error {
 	return fmt.Errorf("unable to connect to peer %x for SCB restore",
 		nodePub.SerializeCompressed())
 }
+
+// restoredChanCheckInterval is the interval at which we check whether the
+// peers of restored channels sent the data needed to recover our funds.
+const restoredChanCheckInterval = 10 * time.Minute
+
+// restoredChanTimedOut returns true if the given channel was restored from a
+// static channel backup, but its peer didn't send the data needed to recover
+// our funds within the timeout. Channels restored by versions that didn't
+// record the restore time are never considered timed out.
+func restoredChanTimedOut(channel *channeldb.OpenChannel,
+	timeout time.Duration, now time.Time) bool {
+
+	switch {
+	case timeout == 0 || channel.RestoredAt.IsZero():
+		return false
+
+	case !channel.HasChanStatus(channeldb.ChanStatusRestored):
+		return false
+
+	// Once the peer sent its commitment point, we're only waiting for its
+	// force close to confirm.
+	case channel.HasChanStatus(channeldb.ChanStatusLocalDataLoss):
+		return false
+	}
+
+	return now.Sub(channel.RestoredAt) >= timeout
+}
+
+// restoredChanChecker periodically checks for restored channels whose peer
+// didn't send the data needed to recover our funds within the configured
+// timeout.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) restoredChanChecker() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(restoredChanCheckInterval)
+	defer ticker.Stop()
+
+	var (
+		// reported holds the channels we already logged as needing
+		// manual intervention.
+		reported = make(map[wire.OutPoint]struct{})
+
+		// asked holds the connection to the peer of each channel that
+		// we last asked to force close the channel.
+		asked = make(map[wire.OutPoint]lnpeer.Peer)
+	)
+
+	for {
+		err := s.checkRestoredChannels(reported, asked)
+		if err != nil {
+			srvrLog.Errorf("Unable to check restored channels: %v",
+				err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// logRestoredChanTimeout explains to the operator why the given restored
+// channel needs manual intervention and what can be done about it.
+func logRestoredChanTimeout(channel *channeldb.OpenChannel, tweakless bool) {
+	chanPoint := channel.FundingOutpoint
+	peerPub := channel.IdentityPub.SerializeCompressed()
+	waited := time.Since(channel.RestoredAt).Round(time.Minute)
+
+	srvrLog.Warnf("ChannelPoint(%v) was restored %v ago, but peer %x "+
+		"hasn't sent the data needed to recover our funds yet. The "+
+		"channel needs manual intervention: make sure the peer can "+
+		"be reached, e.g. with lncli connect, or contact its operator "+
+		"and ask them to force close the channel. lnd won't "+
+		"broadcast anything for the channel, as it doesn't know its "+
+		"latest state.", chanPoint, waited, peerPub)
+
+	if tweakless {
+		srvrLog.Warnf("Our funds in ChannelPoint(%v) can be swept as "+
+			"soon as peer %x force closes it, we'll ask the peer "+
+			"to do so whenever it is connected", chanPoint, peerPub)
+
+		return
+	}
+
+	srvrLog.Warnf("Our funds in ChannelPoint(%v) can only be swept with "+
+		"the data of peer %x, which it needs to send after "+
+		"reconnecting, even if it already closed the channel",
+		chanPoint, peerPub)
+}
+
+// checkRestoredChannels reports the restored channels whose peer didn't send
+// the data needed to recover our funds in time as needing manual intervention.
+// We never broadcast anything for a restored channel, as we don't know its
+// latest state. But if we can sweep our funds without the peer's data once the
+// channel is closed, we ask the peer to force close the channel by sending it
+// an error each time it is connected.
+func (s *server) checkRestoredChannels(reported map[wire.OutPoint]struct{},
+	asked map[wire.OutPoint]lnpeer.Peer) error {
+
+	channels, err := s.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return err
+	}
+
+	timeout := s.cfg.Recovery.PeerTimeout
+	for _, channel := range channels {
+		if !restoredChanTimedOut(channel, timeout, time.Now()) {
+			continue
+		}
+
+		chanPoint := channel.FundingOutpoint
+		peerPub := channel.IdentityPub.SerializeCompressed()
+
+		// The funds of tweakless channels pay to a static key in the
+		// peer's commitment, so we don't need the peer's commitment
+		// point to sweep them.
+		tweakless := channel.ChanType.IsTweakless()
+
+		if _, ok := reported[chanPoint]; !ok {
+			reported[chanPoint] = struct{}{}
+			logRestoredChanTimeout(channel, tweakless)
+		}
+
+		if !tweakless {
+			continue
+		}
+
+		// We only ask the peer once per connection.
+		peer, err := s.FindPeer(channel.IdentityPub)
+		if err != nil {
+			continue
+		}
+		if prevPeer, ok := asked[chanPoint]; ok && prevPeer == peer {
+			continue
+		}
+		asked[chanPoint] = peer
+
+		srvrLog.Infof("Asking peer %x to force close restored "+
+			"ChannelPoint(%v)", peerPub, chanPoint)
+
+		err = peer.SendMessage(false, &lnwire.Error{
+			ChanID: lnwire.NewChanIDFromOutPoint(chanPoint),
+			Data: lnwire.ErrorData("channel was restored from a " +
+				"backup, please force close it"),
+		})
+		if err != nil {
+			srvrLog.Errorf("Unable to ask peer %x to force close "+
+				"ChannelPoint(%v): %v", peerPub, chanPoint, err)
+		}
+	}
+
+	return nil
+}
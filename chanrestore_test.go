@@ -0,0 +1,43 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyUnconfirmedFundingOutput tests that the funding output of a
+// channel that was unconfirmed when it was backed up isn't looked up, as the
+// chain backends would report it as spent.
+func TestVerifyUnconfirmedFundingOutput(t *testing.T) {
+	t.Parallel()
+
+	// No chain backend is needed, as neither channel is looked up.
+	verifier := &chainFundingVerifier{}
+
+	testCases := []struct {
+		name string
+		scid lnwire.ShortChannelID
+	}{
+		{
+			name: "legacy backup",
+			scid: lnwire.ShortChannelID{},
+		},
+		{
+			name: "broadcast height",
+			scid: lnwire.ShortChannelID{BlockHeight: 800_000},
+		},
+	}
+
+	for _, tc := range testCases {
+		status, err := verifier.VerifyFundingOutput(&chanbackup.Single{
+			ShortChannelID: tc.scid,
+		}, nil)
+		require.NoError(t, err, tc.name)
+		require.Equal(
+			t, chanbackup.FundingOutputUnconfirmed, status, tc.name,
+		)
+	}
+}
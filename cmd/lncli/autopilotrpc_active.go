@@ -142,6 +142,32 @@ loop:
 	return nil
 }
 
+var listRecommendationsCommand = cli.Command{
+	Name:  "recommendations",
+	Usage: "List the channels the autopilot recommends to open.",
+	Description: `
+	List the channels the autopilot agent would open if it wasn't running
+	in recommend-only mode. Recommendations can be acted upon by opening
+	the channels manually with the openchannel command.`,
+	Action: actionDecorator(listRecommendations),
+}
+
+func listRecommendations(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getAutopilotClient(ctx)
+	defer cleanUp()
+
+	req := &autopilotrpc.ListRecommendationsRequest{}
+
+	resp, err := client.ListRecommendations(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // autopilotCommands will return the set of commands to enable for autopilotrpc
 // builds.
 func autopilotCommands() []cli.Command {
@@ -156,6 +182,7 @@ func autopilotCommands() []cli.Command {
 				enableCommand,
 				disableCommand,
 				queryScoresCommand,
+				listRecommendationsCommand,
 			},
 		},
 	}
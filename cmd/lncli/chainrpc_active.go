@@ -6,10 +6,13 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd"
+	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/urfave/cli"
 )
@@ -26,6 +29,9 @@ func chainCommands() []cli.Command {
 				getBestBlockCommand,
 				getBlockHashCommand,
 				getBlockHeaderCommand,
+				getBackendInfoCommand,
+				exportHeightHintsCommand,
+				importHeightHintsCommand,
 			},
 		},
 	}
@@ -41,6 +47,18 @@ func getChainClient(ctx *cli.Context) (chainrpc.ChainKitClient, func()) {
 	return chainrpc.NewChainKitClient(conn), cleanUp
 }
 
+func getChainNotifierClient(ctx *cli.Context) (chainrpc.ChainNotifierClient,
+	func()) {
+
+	conn := getClientConn(ctx, false)
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return chainrpc.NewChainNotifierClient(conn), cleanUp
+}
+
 var getBlockCommand = cli.Command{
 	Name:        "getblock",
 	Category:    "On-chain",
@@ -257,3 +275,147 @@ func getBlockHash(ctx *cli.Context) error {
 
 	return nil
 }
+
+var getBackendInfoCommand = cli.Command{
+	Name:     "getbackendinfo",
+	Category: "On-chain",
+	Usage:    "Get the state of the chain backend.",
+	Description: "Returns the type and connection status of the chain " +
+		"backend, its best block and how many blocks it lags behind " +
+		"the best block known on the network. For bitcoind, the " +
+		"number of transactions in its mempool is returned as well.",
+	Action: actionDecorator(getBackendInfo),
+}
+
+func getBackendInfo(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	client, cleanUp := getChainClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetBackendInfo(
+		ctxc, &chainrpc.GetBackendInfoRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Cast gRPC block hash bytes as chain hash type, if the backend
+	// could be queried.
+	var bestBlockHash string
+	if resp.Connected {
+		var blockHash chainhash.Hash
+		copy(blockHash[:], resp.BestBlockHash)
+		bestBlockHash = blockHash.String()
+	}
+
+	printJSON(struct {
+		Backend         string `json:"backend"`
+		Connected       bool   `json:"connected"`
+		Error           string `json:"error,omitempty"`
+		BestBlockHash   string `json:"best_block_hash"`
+		BestBlockHeight int32  `json:"best_block_height"`
+		TipHeight       int32  `json:"tip_height"`
+		BlocksBehind    uint32 `json:"blocks_behind"`
+		MempoolSize     int64  `json:"mempool_size"`
+	}{
+		Backend:         resp.Backend,
+		Connected:       resp.Connected,
+		Error:           resp.Error,
+		BestBlockHash:   bestBlockHash,
+		BestBlockHeight: resp.BestBlockHeight,
+		TipHeight:       resp.TipHeight,
+		BlocksBehind:    resp.BlocksBehind,
+		MempoolSize:     resp.MempoolSize,
+	})
+
+	return nil
+}
+
+var exportHeightHintsCommand = cli.Command{
+	Name:     "exportheighthints",
+	Category: "On-chain",
+	Usage:    "Export the height hint cache of the chain notifier.",
+	Description: "Returns all spend and confirm height hints currently " +
+		"stored in the height hint cache. The output can be edited " +
+		"and passed to importheighthints to correct hints that cause " +
+		"slow or incomplete rescans.",
+	Action: actionDecorator(exportHeightHints),
+}
+
+func exportHeightHints(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	client, cleanUp := getChainNotifierClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportHeightHints(
+		ctxc, &chainrpc.ExportHeightHintsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var importHeightHintsCommand = cli.Command{
+	Name:      "importheighthints",
+	Category:  "On-chain",
+	Usage:     "Import height hints into the chain notifier's cache.",
+	ArgsUsage: "hints_file",
+	Description: "Imports the height hints of the given JSON file, which " +
+		"uses the format returned by exportheighthints. An existing " +
+		"hint with the same key is only replaced by a lower one. " +
+		"Each hint must be " +
+		"between 1 and the current best block height, otherwise none " +
+		"of the hints are imported.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "hints_file",
+			Usage: "the path to the JSON file containing the hints",
+		},
+	},
+	Action: actionDecorator(importHeightHints),
+}
+
+func importHeightHints(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	var hintsFile string
+	switch {
+	case ctx.IsSet("hints_file"):
+		hintsFile = ctx.String("hints_file")
+
+	case ctx.Args().Present():
+		hintsFile = ctx.Args().First()
+
+	default:
+		return cli.ShowCommandHelp(ctx, "importheighthints")
+	}
+
+	jsonBytes, err := os.ReadFile(lnd.CleanAndExpandPath(hintsFile))
+	if err != nil {
+		return fmt.Errorf("unable to read hints file: %w", err)
+	}
+
+	req := &chainrpc.ImportHeightHintsRequest{}
+	err = lnrpc.ProtoJSONUnmarshalOpts.Unmarshal(jsonBytes, req)
+	if err != nil {
+		return fmt.Errorf("unable to parse hints file: %w", err)
+	}
+
+	client, cleanUp := getChainNotifierClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportHeightHints(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -41,6 +41,34 @@ func getDebugInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var getRuntimeConfigCommand = cli.Command{
+	Name:     "getruntimeconfig",
+	Category: "Debug",
+	Usage:    "Returns the configuration the active daemon is running with.",
+	Description: `
+	Returns the resolved configuration of the daemon after merging the
+	config file and the command line flags. Sensitive values like passwords
+	are redacted. Deprecated options that are set are listed separately.
+	`,
+	Action: actionDecorator(getRuntimeConfig),
+}
+
+func getRuntimeConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GetRuntimeConfigRequest{}
+	resp, err := client.GetRuntimeConfig(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 type DebugPackage struct {
 	EphemeralPubKey  string `json:"ephemeral_public_key"`
 	EncryptedPayload string `json:"encrypted_payload"`
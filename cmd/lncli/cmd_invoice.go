@@ -82,6 +82,19 @@ var addInvoiceCommand = cli.Command{
 			Usage: "creates an AMP invoice. If true, preimage " +
 				"should not be set.",
 		},
+		cli.Int64SliceFlag{
+			Name: "set_feature",
+			Usage: "a feature bit to set in the invoice on top " +
+				"of the default invoice features, replacing " +
+				"the other bit of the feature pair. Can be " +
+				"set multiple times in the same command",
+		},
+		cli.Int64SliceFlag{
+			Name: "unset_feature",
+			Usage: "a feature bit whose feature should be " +
+				"removed from the default invoice features. " +
+				"Can be set multiple times in the same command",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -138,6 +151,8 @@ func addInvoice(ctx *cli.Context) error {
 		CltvExpiry:      ctx.Uint64("cltv_expiry_delta"),
 		Private:         ctx.Bool("private"),
 		IsAmp:           ctx.Bool("amp"),
+		SetFeatures:     featureBitsFlag(ctx, "set_feature"),
+		UnsetFeatures:   featureBitsFlag(ctx, "unset_feature"),
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -260,6 +275,17 @@ var listInvoicesCommand = cli.Command{
 	Action: actionDecorator(listInvoices),
 }
 
+// featureBitsFlag returns the feature bits set in the int64 slice flag with
+// the given name.
+func featureBitsFlag(ctx *cli.Context, name string) []lnrpc.FeatureBit {
+	var bits []lnrpc.FeatureBit
+	for _, bit := range ctx.Int64Slice(name) {
+		bits = append(bits, lnrpc.FeatureBit(bit))
+	}
+
+	return bits
+}
+
 func listInvoices(ctx *cli.Context) error {
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
@@ -284,6 +310,36 @@ func listInvoices(ctx *cli.Context) error {
 	return nil
 }
 
+var getInvoiceIndicesCommand = cli.Command{
+	Name:     "getinvoiceindices",
+	Category: "Invoices",
+	Usage: "Returns the highest add and settle index assigned to " +
+		"invoices so far.",
+	Description: `
+	Returns the add_index of the most recently added invoice and the
+	settle_index of the most recently settled invoice. The indices can be
+	stored and passed to a later invoice subscription to catch up on all
+	invoices that were added or settled in the meantime.`,
+	Action: actionDecorator(getInvoiceIndices),
+}
+
+func getInvoiceIndices(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetInvoiceIndices(
+		ctxc, &lnrpc.GetInvoiceIndicesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var decodePayReqCommand = cli.Command{
 	Name:        "decodepayreq",
 	Category:    "Invoices",
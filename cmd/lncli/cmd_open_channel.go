@@ -282,6 +282,23 @@ var openChannelCommand = cli.Command{
 				has no bearing on the channel's operation. Max
 				allowed length is 500 characters`,
 		},
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) the name of the wallet account to " +
+				"fund the channel from; only its UTXOs are " +
+				"used and change is sent back to it",
+		},
+		cli.Uint64Flag{
+			Name: "funding_confs",
+			Usage: "(optional) the number of confirmations of " +
+				"the funding transaction to wait for before " +
+				"the channel is considered open; if the " +
+				"remote peer requires more, its number is " +
+				"used. Must not be lower than the configured " +
+				"funding.min-funding-confs and can't be set " +
+				"for zero-conf channels",
+		},
+		coinSelectionStrategyFlag,
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -310,6 +327,11 @@ func openChannel(ctx *cli.Context) error {
 		return err
 	}
 
+	coinSelectionStrategy, err := parseCoinSelectionStrategy(ctx)
+	if err != nil {
+		return err
+	}
+
 	minConfs := int32(ctx.Uint64("min_confs"))
 	req := &lnrpc.OpenChannelRequest{
 		TargetConf:                 int32(ctx.Int64("conf_target")),
@@ -326,6 +348,9 @@ func openChannel(ctx *cli.Context) error {
 		RemoteChanReserveSat:       ctx.Uint64("remote_reserve_sats"),
 		FundMax:                    ctx.Bool("fundmax"),
 		Memo:                       ctx.String("memo"),
+		Account:                    ctx.String("account"),
+		FundingConfs:               uint32(ctx.Uint64("funding_confs")),
+		CoinSelectionStrategy:      coinSelectionStrategy,
 	}
 
 	switch {
@@ -817,6 +842,12 @@ var batchOpenChannelCommand = cli.Command{
 				"wallet after publishing it",
 		},
 		coinSelectionStrategyFlag,
+		cli.StringFlag{
+			Name: "account",
+			Usage: "(optional) the name of the wallet account to " +
+				"fund the batch transaction from; only its " +
+				"UTXOs are used and change is sent back to it",
+		},
 	},
 	Action: actionDecorator(batchOpenChannel),
 }
@@ -858,6 +889,7 @@ func batchOpenChannel(ctx *cli.Context) error {
 		SpendUnconfirmed:      minConfs == 0,
 		Label:                 ctx.String("label"),
 		CoinSelectionStrategy: coinSelectionStrategy,
+		Account:               ctx.String("account"),
 	}
 
 	// Let's try and parse the JSON part of the CLI now. Fortunately we can
@@ -918,6 +950,87 @@ func batchOpenChannel(ctx *cli.Context) error {
 	return nil
 }
 
+var abortChannelOpenCommand = cli.Command{
+	Name:     "abortchannelopen",
+	Category: "Channels",
+	Usage:    "Abort a pending channel open that is still negotiated.",
+	Description: `
+	Abort a channel open that is still being negotiated with the peer, for
+	example because the peer doesn't respond, and release the wallet inputs
+	reserved for it. Either a single channel open is aborted, identified by
+	its pending channel ID, or all channel opens with the given peer.
+
+	A channel open can only be aborted until both sides signed the
+	commitment transactions, as the funding transaction may be broadcast
+	after that point. Use abandonchannel for channels whose funding
+	transaction will never confirm instead.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pending_chan_id",
+			Usage: "the hex encoded pending channel ID to abort",
+		},
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "the identity public key of the peer whose " +
+				"channel opens should be aborted",
+		},
+	},
+	Action: actionDecorator(abortChannelOpen),
+}
+
+func abortChannelOpen(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.AbortChannelOpenRequest{}
+
+	var err error
+	switch {
+	case ctx.IsSet("pending_chan_id") && ctx.IsSet("node_key"):
+		return fmt.Errorf("only one of pending_chan_id and node_key " +
+			"can be set")
+
+	case ctx.IsSet("pending_chan_id"):
+		req.PendingChanId, err = hex.DecodeString(
+			ctx.String("pending_chan_id"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode pending_chan_id: "+
+				"%w", err)
+		}
+
+	case ctx.IsSet("node_key"):
+		req.NodePubkey, err = hex.DecodeString(ctx.String("node_key"))
+		if err != nil {
+			return fmt.Errorf("unable to decode node_key: %w", err)
+		}
+
+	default:
+		return fmt.Errorf("either pending_chan_id or node_key must " +
+			"be set")
+	}
+
+	resp, err := client.AbortChannelOpen(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	aborted := make([]string, 0, len(resp.AbortedPendingChanIds))
+	for _, pendingChanID := range resp.AbortedPendingChanIds {
+		aborted = append(aborted, hex.EncodeToString(pendingChanID))
+	}
+
+	printJSON(struct {
+		AbortedPendingChanIDs []string `json:"aborted_pending_chan_ids"`
+	}{
+		AbortedPendingChanIDs: aborted,
+	})
+
+	return nil
+}
+
 // printChanOpen prints the channel point of the channel open message.
 func printChanOpen(update *lnrpc.OpenStatusUpdate_ChanOpen) error {
 	channelPoint := update.ChanOpen.ChannelPoint
@@ -1907,6 +1907,116 @@ func estimateRouteFee(ctx *cli.Context) error {
 	return nil
 }
 
+var diagnosePaymentCommand = cli.Command{
+	Name:     "diagnosepayment",
+	Category: "Payments",
+	Usage:    "Diagnose why a payment to a destination can't be routed.",
+	Description: `
+	Runs path finding for a payment of the given amount to the destination
+	and reports the issues preventing it, such as a too low fee or cltv
+	limit, insufficient outbound liquidity or failures recorded by mission
+	control. No HTLCs are sent in the process.`,
+	ArgsUsage: "dest amt",
+	Action:    actionDecorator(diagnosePayment),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest",
+			Usage: "the 33-byte hex-encoded public key of the " +
+				"payment destination",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the payment amount in satoshis",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "the maximum fee in satoshis the payment may " +
+				"pay, defaults to the limit lnd applies when " +
+				"querying routes",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_limit",
+			Usage: "the maximum total time lock of the payment, " +
+				"defaults to the configured maximum",
+		},
+		cli.Int64Flag{
+			Name: "final_cltv_delta",
+			Usage: "the cltv delta of the final hop, defaults to " +
+				"the invoice default",
+		},
+	},
+}
+
+func diagnosePayment(ctx *cli.Context) error {
+	ctxc := getContext()
+	args := ctx.Args()
+
+	var destStr string
+	switch {
+	case ctx.IsSet("dest"):
+		destStr = ctx.String("dest")
+
+	case args.Present():
+		destStr = args.First()
+		args = args.Tail()
+
+	default:
+		return cli.ShowCommandHelp(ctx, "diagnosepayment")
+	}
+
+	dest, err := hex.DecodeString(destStr)
+	if err != nil {
+		return err
+	}
+
+	if len(dest) != 33 {
+		return fmt.Errorf("dest node pubkey must be exactly 33 bytes, "+
+			"is instead: %v", len(dest))
+	}
+
+	var amtSat int64
+	switch {
+	case ctx.IsSet("amt"):
+		amtSat = ctx.Int64("amt")
+
+	case args.Present():
+		amtSat, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt: %w", err)
+		}
+	}
+
+	if amtSat <= 0 {
+		return fmt.Errorf("non-zero amount required")
+	}
+
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.DiagnosePaymentRequest{
+		Dest: dest,
+		AmtMsat: int64(lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(amtSat),
+		)),
+		FeeLimitMsat: int64(lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(ctx.Int64("fee_limit")),
+		)),
+		CltvLimit:      uint32(ctx.Uint64("cltv_limit")),
+		FinalCltvDelta: int32(ctx.Int64("final_cltv_delta")),
+	}
+
+	resp, err := client.DiagnosePayment(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 // ESC is the ASCII code for escape character.
 const ESC = 27
 
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -202,10 +203,10 @@ func newAddress(ctx *cli.Context) error {
 var coinSelectionStrategyFlag = cli.StringFlag{
 	Name: "coin_selection_strategy",
 	Usage: "(optional) the strategy to use for selecting " +
-		"coins. Possible values are 'largest', 'random', or " +
-		"'global-config'. If either 'largest' or 'random' is " +
-		"specified, it will override the globally configured " +
-		"strategy in lnd.conf",
+		"coins. Possible values are 'largest', 'random', 'bnb' " +
+		"or 'global-config'. If either 'largest', 'random' or " +
+		"'bnb' is specified, it will override the globally " +
+		"configured strategy in lnd.conf",
 	Value: "global-config",
 }
 
@@ -331,6 +332,12 @@ var sendCoinsCommand = cli.Command{
 		},
 		coinSelectionStrategyFlag,
 		txLabelFlag,
+		cli.BoolFlag{
+			Name: "override_max_send_amount",
+			Usage: "(optional) send the amount even if it " +
+				"exceeds the maximum send amount configured " +
+				"in lnd",
+		},
 	},
 	Action: actionDecorator(sendCoins),
 }
@@ -440,6 +447,7 @@ func sendCoins(ctx *cli.Context) error {
 		MinConfs:              minConfs,
 		SpendUnconfirmed:      minConfs == 0,
 		CoinSelectionStrategy: coinSelectionStrategy,
+		OverrideMaxSendAmount: ctx.Bool("override_max_send_amount"),
 	}
 	txid, err := client.SendCoins(ctxc, req)
 	if err != nil {
@@ -610,6 +618,12 @@ var sendManyCommand = cli.Command{
 		},
 		coinSelectionStrategyFlag,
 		txLabelFlag,
+		cli.BoolFlag{
+			Name: "override_max_send_amount",
+			Usage: "(optional) send the amount even if it " +
+				"exceeds the maximum send amount configured " +
+				"in lnd",
+		},
 	},
 	Action: actionDecorator(sendMany),
 }
@@ -656,6 +670,7 @@ func sendMany(ctx *cli.Context) error {
 		MinConfs:              minConfs,
 		SpendUnconfirmed:      minConfs == 0,
 		CoinSelectionStrategy: coinSelectionStrategy,
+		OverrideMaxSendAmount: ctx.Bool("override_max_send_amount"),
 	})
 	if err != nil {
 		return err
@@ -727,6 +742,62 @@ func connectPeer(ctx *cli.Context) error {
 	return nil
 }
 
+var testConnectionCommand = cli.Command{
+	Name:      "testconnection",
+	Category:  "Peers",
+	Usage:     "Test the connection to a remote lightning peer.",
+	ArgsUsage: "<pubkey>@host",
+	Description: `
+	Check whether a peer can be reached using its <pubkey> and host without
+	establishing a lasting connection. The peer is dialed, the handshake
+	and init messages are exchanged and the connection is closed right
+	away. The latency and the features advertised by the peer are
+	returned.
+
+	This fails if we're already connected to the peer, as the test
+	connection would replace the existing one.
+	`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "The timeout of the whole connection test. " +
+				"Valid uints are {ms, s, m, h}.\n" +
+				"If not set, the global connection " +
+				"timeout value (default to 120s) is used.",
+		},
+	},
+	Action: actionDecorator(testConnection),
+}
+
+func testConnection(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	targetAddress := ctx.Args().First()
+	splitAddr := strings.Split(targetAddress, "@")
+	if len(splitAddr) != 2 {
+		return fmt.Errorf("target address expected in format: " +
+			"pubkey@host:port")
+	}
+
+	req := &lnrpc.TestConnectionRequest{
+		Addr: &lnrpc.LightningAddress{
+			Pubkey: splitAddr[0],
+			Host:   splitAddr[1],
+		},
+		Timeout: uint64(ctx.Duration("timeout").Seconds()),
+	}
+
+	resp, err := client.TestConnection(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var disconnectCommand = cli.Command{
 	Name:     "disconnect",
 	Category: "Peers",
@@ -771,6 +842,150 @@ func disconnectPeer(ctx *cli.Context) error {
 	return nil
 }
 
+var banPeerCommand = cli.Command{
+	Name:     "banpeer",
+	Category: "Peers",
+	Usage: "Disconnect a remote lightning peer and refuse any " +
+		"connection with it for a while.",
+	Description: `
+	Disconnect the peer identified by the given public key and refuse any
+	connection with it until the ban expires. Channels with the peer are
+	not affected, the connection is re-established once the ban expires.
+
+	Bans are not persisted and are lifted when lnd restarts.`,
+	ArgsUsage: "<pubkey> <duration>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "The hex-encoded compressed public key of the " +
+				"peer to ban",
+		},
+		cli.DurationFlag{
+			Name: "duration",
+			Usage: "The duration of the ban, e.g. 30m or 24h, " +
+				"must be at least one second",
+		},
+	},
+	Action: actionDecorator(banPeer),
+}
+
+func banPeer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("node_key"):
+		pubKey = ctx.String("node_key")
+	case args.Present():
+		pubKey = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("must specify target public key")
+	}
+
+	var (
+		duration time.Duration
+		err      error
+	)
+	switch {
+	case ctx.IsSet("duration"):
+		duration = ctx.Duration("duration")
+	case args.Present():
+		duration, err = time.ParseDuration(args.First())
+		if err != nil {
+			return fmt.Errorf("unable to parse duration: %w", err)
+		}
+	default:
+		return fmt.Errorf("must specify ban duration")
+	}
+
+	if duration < time.Second {
+		return fmt.Errorf("ban duration must be at least one second")
+	}
+
+	req := &lnrpc.BanPeerRequest{
+		PubKey:       pubKey,
+		DurationSecs: uint64(duration.Seconds()),
+	}
+
+	resp, err := client.BanPeer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var unbanPeerCommand = cli.Command{
+	Name:      "unbanpeer",
+	Category:  "Peers",
+	Usage:     "Lift the ban of a remote lightning peer.",
+	ArgsUsage: "<pubkey>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "The hex-encoded compressed public key of the " +
+				"peer to lift the ban of",
+		},
+	},
+	Action: actionDecorator(unbanPeer),
+}
+
+func unbanPeer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("node_key"):
+		pubKey = ctx.String("node_key")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return fmt.Errorf("must specify target public key")
+	}
+
+	req := &lnrpc.UnbanPeerRequest{
+		PubKey: pubKey,
+	}
+
+	resp, err := client.UnbanPeer(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listBannedPeersCommand = cli.Command{
+	Name:     "listbannedpeers",
+	Category: "Peers",
+	Usage:    "List all peers that are currently banned.",
+	Action:   actionDecorator(listBannedPeers),
+}
+
+func listBannedPeers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListBannedPeersRequest{}
+	resp, err := client.ListBannedPeers(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // TODO(roasbeef): also allow short relative channel ID.
 
 var closeChannelCommand = cli.Command{
@@ -1231,6 +1446,63 @@ func promptForConfirmation(msg string) bool {
 	}
 }
 
+var estimateCloseCostsCommand = cli.Command{
+	Name:     "estimateclosecosts",
+	Category: "Channels",
+	Usage:    "Estimate the on-chain fees of closing all channels.",
+	Description: `
+	Estimate the on-chain fees of closing all open channels at the current
+	fee rates, without closing any of them.
+
+	For each channel, the fee of a cooperative close is shown next to the
+	fees of a force close, which consist of the fee committed to by the
+	commitment transaction, the fee of bumping it through its anchor and
+	the fee of sweeping the outputs back into the wallet. The totals only
+	include the fees paid by this node, as the fees of the closing and
+	commitment transactions are paid by the channel initiator.
+
+	The fee rate can be set via either the --conf_target or
+	--sat_per_vbyte arguments. By default, a confirmation target of 6
+	blocks is used.`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"closing transactions should confirm in, " +
+				"used for fee estimation",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte to base the estimates on",
+		},
+	},
+	Action: actionDecorator(estimateCloseCosts),
+}
+
+func estimateCloseCosts(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return fmt.Errorf("either conf_target or sat_per_vbyte " +
+			"should be set, but not both")
+	}
+
+	req := &lnrpc.EstimateCloseCostsRequest{
+		TargetConf:  int32(ctx.Int64("conf_target")),
+		SatPerVbyte: ctx.Uint64("sat_per_vbyte"),
+	}
+	resp, err := client.EstimateCloseCosts(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var abandonChannelCommand = cli.Command{
 	Name:     "abandonchannel",
 	Category: "Channels",
@@ -1419,6 +1691,37 @@ func walletBalance(ctx *cli.Context) error {
 	return nil
 }
 
+var balanceBreakdownCommand = cli.Command{
+	Name:     "balancebreakdown",
+	Category: "Wallet",
+	Usage: "Display a detailed breakdown of the wallet's on-chain " +
+		"funds.",
+	Description: `
+	Shows all on-chain funds split into the available, reserved,
+	unconfirmed, locked and immature balances, which add up to the total
+	balance. The reserved balance is held back to fee bump anchor channels,
+	the locked balance is leased for other usage and the immature balance
+	consists of outputs of force closed channels that can't be swept into
+	the wallet yet.
+	`,
+	Action: actionDecorator(balanceBreakdown),
+}
+
+func balanceBreakdown(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.BalanceBreakdownRequest{}
+	resp, err := client.BalanceBreakdown(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var channelBalanceCommand = cli.Command{
 	Name:     "channelbalance",
 	Category: "Channels",
@@ -1552,6 +1855,101 @@ func pendingChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var listContractResolutionsCommand = cli.Command{
+	Name:     "listcontractresolutions",
+	Category: "Channels",
+	Usage: "List the on-chain resolution progress of closed " +
+		"channels.",
+	Description: `
+	Lists every closed channel whose outputs are still being resolved on
+	chain. For each output, such as HTLCs and the commitment output, the
+	command shows whether it is waiting for its timelock to expire, being
+	swept or resolved, and the height at which it matures.
+	`,
+	Action: actionDecorator(listContractResolutions),
+}
+
+func listContractResolutions(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListContractResolutions(
+		ctxc, &lnrpc.ListContractResolutionsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var getChanCommitStateCommand = cli.Command{
+	Name:     "getchancommitstate",
+	Category: "Channels",
+	Usage:    "Show the raw commitment state of an open channel.",
+	Description: `
+	Shows the commitment state of an open channel as stored in the channel
+	database, for diagnostic purposes. This includes the balances, HTLCs,
+	commitment heights and fee rates of the local and remote commitments,
+	and of a pending remote commitment. The channel state is never modified.
+
+	To view which funding_txids/output_indexes can be used for this command,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+	},
+	Action: actionDecorator(getChanCommitState),
+}
+
+func getChanCommitState(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "getchancommitstate")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.GetChannelCommitState(
+		ctxc, &lnrpc.ChannelCommitStateRequest{
+			ChanPoint: channelPoint,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var listChannelsCommand = cli.Command{
 	Name:     "listchannels",
 	Category: "Channels",
@@ -2173,7 +2571,44 @@ func feeReport(ctx *cli.Context) error {
 	return nil
 }
 
-var updateChannelPolicyCommand = cli.Command{
+var forwardingPoliciesCommand = cli.Command{
+	Name:     "fwdpolicies",
+	Category: "Channels",
+	Usage: "Display the effective forwarding policies of all open " +
+		"channels.",
+	Description: `
+	Returns the forwarding policy that is currently in effect for each open
+	channel, along with its source. For channels with an active link this is
+	the policy enforced by the link, otherwise it is the policy announced in
+	the graph or the node's default policy.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "chan_id",
+			Usage: "(optional) only return the policy of the " +
+				"channel with the given short channel ID",
+		},
+	},
+	Action: actionDecorator(forwardingPolicies),
+}
+
+func forwardingPolicies(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ForwardingPoliciesRequest{
+		ChanId: ctx.Uint64("chan_id"),
+	}
+	resp, err := client.ForwardingPolicies(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var updateChannelPolicyCommand = cli.Command{
 	Name:     "updatechanpolicy",
 	Category: "Channels",
 	Usage: "Update the channel policy for all channels, or a single " +
@@ -2185,6 +2620,9 @@ var updateChannelPolicyCommand = cli.Command{
         channel identified by its channel point. The update will be committed, 
 	and broadcast to the rest of the network within the next batch. Channel
         points are encoded as: funding_txid:output_index
+
+	An update of all channels is queued and applied in batches like with
+	the queuechanpolicy command.
 	`,
 	Flags: []cli.Flag{
 		cli.Int64Flag{
@@ -2310,6 +2748,44 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
+	req, err := parsePolicyUpdateRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.UpdateChannelPolicy(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printFailedUpdates(resp.FailedUpdates)
+
+	return nil
+}
+
+// printFailedUpdates prints the given failed policy updates. This filters out
+// the raw txid bytes from each failed update's outpoint and only prints the
+// txid string.
+func printFailedUpdates(failedUpdates []*lnrpc.FailedUpdate) {
+	var listFailedUpdateResp = struct {
+		FailedUpdates []*FailedUpdate `json:"failed_updates"`
+	}{
+		FailedUpdates: make([]*FailedUpdate, 0, len(failedUpdates)),
+	}
+	for _, protoUpdate := range failedUpdates {
+		failedUpdate := NewFailedUpdateFromProto(protoUpdate)
+		listFailedUpdateResp.FailedUpdates = append(
+			listFailedUpdateResp.FailedUpdates, failedUpdate)
+	}
+
+	printJSON(listFailedUpdateResp)
+}
+
+// parsePolicyUpdateRequest parses a policy update request from the arguments
+// and the flags of the updatechanpolicy command.
+func parsePolicyUpdateRequest(ctx *cli.Context) (*lnrpc.PolicyUpdateRequest,
+	error) {
+
 	var (
 		baseFee       int64
 		feeRate       float64
@@ -2325,17 +2801,18 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	case args.Present():
 		baseFee, err = strconv.ParseInt(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode base_fee_msat: %w",
-				err)
+			return nil, fmt.Errorf("unable to decode "+
+				"base_fee_msat: %w", err)
 		}
 		args = args.Tail()
 	default:
-		return fmt.Errorf("base_fee_msat argument missing")
+		return nil, fmt.Errorf("base_fee_msat argument missing")
 	}
 
 	switch {
 	case ctx.IsSet("fee_rate") && ctx.IsSet("fee_rate_ppm"):
-		return fmt.Errorf("fee_rate or fee_rate_ppm can not both be set")
+		return nil, fmt.Errorf("fee_rate or fee_rate_ppm can not " +
+			"both be set")
 	case ctx.IsSet("fee_rate"):
 		feeRate = ctx.Float64("fee_rate")
 	case ctx.IsSet("fee_rate_ppm"):
@@ -2343,12 +2820,14 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	case args.Present():
 		feeRate, err = strconv.ParseFloat(args.First(), 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode fee_rate: %w", err)
+			return nil, fmt.Errorf("unable to decode fee_rate: %w",
+				err)
 		}
 
 		args = args.Tail()
 	default:
-		return fmt.Errorf("fee_rate or fee_rate_ppm argument missing")
+		return nil, fmt.Errorf("fee_rate or fee_rate_ppm argument " +
+			"missing")
 	}
 
 	switch {
@@ -2356,17 +2835,17 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		timeLockDeltaStr := ctx.String("time_lock_delta")
 		timeLockDelta, err = parseTimeLockDelta(timeLockDeltaStr)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	case args.Present():
 		timeLockDelta, err = parseTimeLockDelta(args.First())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		args = args.Tail()
 	default:
-		return fmt.Errorf("time_lock_delta argument missing")
+		return nil, fmt.Errorf("time_lock_delta argument missing")
 	}
 
 	var (
@@ -2384,7 +2863,8 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	if chanPointStr != "" {
 		chanPoint, err = parseChanPoint(chanPointStr)
 		if err != nil {
-			return fmt.Errorf("unable to parse chan_point: %w", err)
+			return nil, fmt.Errorf("unable to parse chan_point: %w",
+				err)
 		}
 	}
 
@@ -2392,14 +2872,14 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	if inboundBaseFeeMsat < math.MinInt32 ||
 		inboundBaseFeeMsat > math.MaxInt32 {
 
-		return errors.New("inbound_base_fee_msat out of range")
+		return nil, errors.New("inbound_base_fee_msat out of range")
 	}
 
 	inboundFeeRatePpm := ctx.Int64("inbound_fee_rate_ppm")
 	if inboundFeeRatePpm < math.MinInt32 ||
 		inboundFeeRatePpm > math.MaxInt32 {
 
-		return errors.New("inbound_fee_rate_ppm out of range")
+		return nil, errors.New("inbound_fee_rate_ppm out of range")
 	}
 
 	// Inbound fees are optional. However, if an update is required,
@@ -2408,7 +2888,7 @@ func updateChannelPolicy(ctx *cli.Context) error {
 	if ctx.IsSet("inbound_base_fee_msat") !=
 		ctx.IsSet("inbound_fee_rate_ppm") {
 
-		return errors.New("both parameters must be provided: " +
+		return nil, errors.New("both parameters must be provided: " +
 			"inbound_base_fee_msat and inbound_fee_rate_ppm")
 	}
 
@@ -2447,26 +2927,175 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		req.FeeRatePpm = uint32(feeRatePpm)
 	}
 
-	resp, err := client.UpdateChannelPolicy(ctxc, req)
+	return req, nil
+}
+
+var queueChannelPolicyCommand = cli.Command{
+	Name:     "queuechanpolicy",
+	Category: "Channels",
+	Usage: "Queue a channel policy update for all channels, or a " +
+		"single channel.",
+	ArgsUsage: "base_fee_msat fee_rate time_lock_delta " +
+		"[--max_htlc_msat=N] [channel_point]",
+	Description: `
+	Queues a channel policy update for all channels, or just a particular
+	channel identified by its channel point. Instead of updating all
+	channels at once, the queued updates are applied in batches, so that
+	the network isn't flooded with channel updates. The progress can be
+	followed with the chanpolicyqueue command.
+
+	If --urgent is set, the update of a single channel is applied right
+	away and replaces a queued update of the channel.
+	`,
+	Flags: append([]cli.Flag{
+		cli.BoolFlag{
+			Name: "urgent",
+			Usage: "apply the update of a single channel right " +
+				"away instead of queueing it",
+		},
+	}, updateChannelPolicyCommand.Flags...),
+	Action: actionDecorator(queueChannelPolicy),
+}
+
+func queueChannelPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	update, err := parsePolicyUpdateRequest(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Parse the response into the final json object that will be printed
-	// to stdout. At the moment, this filters out the raw txid bytes from
-	// each failed update's outpoint and only prints the txid string.
-	var listFailedUpdateResp = struct {
-		FailedUpdates []*FailedUpdate `json:"failed_updates"`
-	}{
-		FailedUpdates: make([]*FailedUpdate, 0, len(resp.FailedUpdates)),
+	req := &lnrpc.QueuePolicyUpdatesRequest{
+		Updates: []*lnrpc.PolicyUpdateRequest{update},
+		Urgent:  ctx.Bool("urgent"),
 	}
-	for _, protoUpdate := range resp.FailedUpdates {
-		failedUpdate := NewFailedUpdateFromProto(protoUpdate)
-		listFailedUpdateResp.FailedUpdates = append(
-			listFailedUpdateResp.FailedUpdates, failedUpdate)
+	resp, err := client.QueuePolicyUpdates(ctxc, req)
+	if err != nil {
+		return err
 	}
 
-	printJSON(listFailedUpdateResp)
+	if req.Urgent {
+		printFailedUpdates(resp.FailedUpdates)
+
+		return nil
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var policyUpdateQueueCommand = cli.Command{
+	Name:     "chanpolicyqueue",
+	Category: "Channels",
+	Usage:    "Show the progress of queued channel policy updates.",
+	Description: `
+	Shows the queued channel policy updates along with the time at which
+	they are expected to be applied, as well as the number of updates that
+	were applied or failed so far.
+	`,
+	Action: actionDecorator(policyUpdateQueue),
+}
+
+func policyUpdateQueue(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PolicyUpdateQueueStatusRequest{}
+	resp, err := client.PolicyUpdateQueueStatus(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var simulateFeeChangeCommand = cli.Command{
+	Name:     "simulatefeechange",
+	Category: "Channels",
+	Usage: "Estimate how a fee change would affect the routing volume " +
+		"of channels.",
+	Description: `
+	Estimate how changing the fees of one or more channels to the given
+	base fee and fee rate would affect their routing volume, without
+	changing anything.
+
+	Random payments between other nodes of the graph are simulated and
+	the cheapest routes for them are compared with the current and the
+	proposed fees. For each channel, the number of simulated payments it
+	would forward and the fees it would earn from them are shown. Parallel
+	channels to the same peer compete with each other, so lowering the
+	fee of only one of them may have no effect.
+
+	If no channel is given, only the current routing volume is estimated.`,
+	Flags: []cli.Flag{
+		cli.Int64SliceFlag{
+			Name: "chan_id",
+			Usage: "the short channel id of a channel to change " +
+				"the fees of; can be specified multiple " +
+				"times in the same command",
+			Value: &cli.Int64Slice{},
+		},
+		cli.Int64Flag{
+			Name: "base_fee_msat",
+			Usage: "the proposed base fee in milli-satoshis of " +
+				"the channels",
+		},
+		cli.Uint64Flag{
+			Name: "fee_rate_ppm",
+			Usage: "the proposed fee rate in parts per million " +
+				"of the channels",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "(optional) the amount in satoshis of the " +
+				"simulated payments, defaults to 100000",
+		},
+		cli.Uint64Flag{
+			Name: "num_samples",
+			Usage: "(optional) the number of payments to " +
+				"simulate, at most 1000, defaults to 100",
+		},
+	},
+	Action: actionDecorator(simulateFeeChange),
+}
+
+func simulateFeeChange(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanIDs := ctx.Int64Slice("chan_id")
+	if len(chanIDs) > 0 && (!ctx.IsSet("base_fee_msat") ||
+		!ctx.IsSet("fee_rate_ppm")) {
+
+		return fmt.Errorf("base_fee_msat and fee_rate_ppm must be " +
+			"set to change the fees of channels")
+	}
+
+	req := &lnrpc.SimulateFeeChangeRequest{
+		AmtSat:     ctx.Int64("amt"),
+		NumSamples: uint32(ctx.Uint64("num_samples")),
+	}
+	for _, chanID := range chanIDs {
+		req.Changes = append(req.Changes, &lnrpc.ProposedFeeChange{
+			ChanId:      uint64(chanID),
+			BaseFeeMsat: ctx.Int64("base_fee_msat"),
+			FeeRatePpm:  uint32(ctx.Uint64("fee_rate_ppm")),
+		})
+	}
+
+	resp, err := client.SimulateFeeChange(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
 
 	return nil
 }
@@ -2745,6 +3374,197 @@ func verifyChanBackup(ctx *cli.Context) error {
 	return nil
 }
 
+var checkChanBackupFileCommand = cli.Command{
+	Name:     "checkchanbackupfile",
+	Category: "Channels",
+	Usage: "Check that the channel backup file on disk contains the " +
+		"current channels.",
+	Description: `
+	Re-derives the static channel backups of all current channels and
+	compares them against the channel backup file of the node. Channels
+	that are missing from the file and channels that are in the file but
+	no longer exist are listed. This helps to detect a backup file that
+	stopped being updated.
+	`,
+	Action: actionDecorator(checkChanBackupFile),
+}
+
+func checkChanBackupFile(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CheckChanBackupFile(
+		ctxc, &lnrpc.CheckChanBackupFileRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var snapshotChannelDBCommand = cli.Command{
+	Name:      "snapshotchanneldb",
+	Category:  "Channels",
+	Usage:     "Write a consistent snapshot of the channel database.",
+	ArgsUsage: "path",
+	Description: `
+	Writes a consistent point-in-time copy of the full channel database to
+	a new file at the given absolute path on the machine lnd is running on.
+	Unlike static channel backups, the snapshot contains the complete
+	channel state. The live database is never modified. Only supported by
+	the bolt and etcd database backends.
+
+	WARNING: The snapshot is outdated as soon as a channel is updated after
+	it was taken. Starting lnd with an outdated snapshot makes it broadcast
+	revoked channel states, which lets the channel peers claim ALL funds of
+	those channels with justice transactions. Never restore a snapshot to
+	recover funds, use static channel backups (SCB) for that instead.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "path",
+			Usage: "the absolute path of the snapshot file to " +
+				"create, the file must not exist yet",
+		},
+	},
+	Action: actionDecorator(snapshotChannelDB),
+}
+
+func snapshotChannelDB(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var path string
+	switch {
+	case ctx.IsSet("path"):
+		path = ctx.String("path")
+
+	case ctx.Args().Present():
+		path = ctx.Args().First()
+
+	default:
+		return cli.ShowCommandHelp(ctx, "snapshotchanneldb")
+	}
+
+	resp, err := client.SnapshotChannelDB(
+		ctxc, &lnrpc.SnapshotChannelDBRequest{
+			Path: path,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var compactChannelDBCommand = cli.Command{
+	Name:     "compactchanneldb",
+	Category: "Channels",
+	Usage:    "Compact the channel database while lnd is running.",
+	Description: `
+	Compacts the channel database to reclaim the space of deleted data
+	without restarting lnd, and reports the size of the database file
+	before and after the compaction. Reads and writes continue while the
+	compacted copy is created, writes are only paused to swap it in. If
+	data was written meanwhile, the copy is created again, so no write is
+	lost. If the compaction doesn't finish within the timeout, it is
+	aborted and the database is left untouched. Only supported by the
+	bolt database backend, and only if db.bolt.compact-on-demand is set.
+	`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum time the compaction may take " +
+				"before it is aborted, e.g. 10m; if unset " +
+				"the compaction runs until it's done",
+		},
+	},
+	Action: actionDecorator(compactChannelDB),
+}
+
+func compactChannelDB(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// The timeout is sent in whole seconds, so a shorter one would
+	// silently disable it.
+	timeout := ctx.Duration("timeout")
+	if timeout != 0 && timeout < time.Second {
+		return fmt.Errorf("timeout must be at least one second")
+	}
+
+	resp, err := client.CompactChannelDB(
+		ctxc, &lnrpc.CompactChannelDBRequest{
+			TimeoutSeconds: uint32(timeout.Seconds()),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportChanBackupFileCommand = cli.Command{
+	Name:      "exportchanbackupfile",
+	Category:  "Channels",
+	Usage:     "Write a backup of all channels to a file.",
+	ArgsUsage: "path",
+	Description: `
+	Writes a multi-channel backup of all channels, including pending
+	channels, to the given absolute path on the machine lnd is running on.
+	The file is written atomically and must not exist yet. The backup can
+	be restored with the restorechanbackup command just like the auto
+	updated channel.backup file.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "path",
+			Usage: "the absolute path of the backup file to " +
+				"create, the file must not exist yet",
+		},
+	},
+	Action: actionDecorator(exportChanBackupFile),
+}
+
+func exportChanBackupFile(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var path string
+	switch {
+	case ctx.IsSet("path"):
+		path = ctx.String("path")
+
+	case ctx.Args().Present():
+		path = ctx.Args().First()
+
+	default:
+		return cli.ShowCommandHelp(ctx, "exportchanbackupfile")
+	}
+
+	resp, err := client.ExportAllChannelBackupsToFile(
+		ctxc, &lnrpc.ExportAllChannelBackupsToFileRequest{
+			Path: path,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var restoreChanBackupCommand = cli.Command{
 	Name:     "restorechanbackup",
 	Category: "Channels",
@@ -2799,6 +3619,20 @@ var restoreChanBackupCommand = cli.Command{
 			Usage:     "the path to a multi-channel back up file",
 			TakesFile: true,
 		},
+		cli.StringSliceFlag{
+			Name: "chan_point",
+			Usage: "only restore the channel with this channel " +
+				"point (funding_txid:output_index), other " +
+				"channels in the backup are skipped. Can be " +
+				"set multiple times in the same command",
+		},
+		cli.StringSliceFlag{
+			Name: "peer",
+			Usage: "only restore the channels with this hex " +
+				"encoded peer public key, other channels in " +
+				"the backup are skipped. Can be set multiple " +
+				"times in the same command",
+		},
 	},
 	Action: actionDecorator(restoreChanBackup),
 }
@@ -2900,10 +3734,76 @@ func restoreChanBackup(ctx *cli.Context) error {
 
 	req.Backup = backups.Backup
 
-	_, err = client.RestoreChannelBackups(ctxc, &req)
+	for _, chanPointStr := range ctx.StringSlice("chan_point") {
+		chanPoint, err := parseChanPoint(chanPointStr)
+		if err != nil {
+			return fmt.Errorf("unable to parse chan_point: %w", err)
+		}
+		req.ChanPoints = append(req.ChanPoints, chanPoint)
+	}
+
+	for _, peer := range ctx.StringSlice("peer") {
+		pubKey, err := hex.DecodeString(peer)
+		if err != nil {
+			return fmt.Errorf("unable to decode peer: %w", err)
+		}
+		req.Peers = append(req.Peers, pubKey)
+	}
+
+	resp, err := client.RestoreChannelBackups(ctxc, &req)
 	if err != nil {
 		return fmt.Errorf("unable to restore chan backups: %w", err)
 	}
 
+	printRespJSON(resp)
+
+	return nil
+}
+
+var channelRecoveryStatusCommand = cli.Command{
+	Name:     "channelrecoverystatus",
+	Category: "Channels",
+	Usage: "Show the recovery state of channels restored from a static " +
+		"channel backup.",
+	Description: `
+	Lists the channels that were restored from a static channel backup
+	together with the state of their recovery:
+
+	   * RECOVERY_WAITING_FOR_PEER: the peer hasn't sent the data needed to
+	     recover our funds yet.
+
+	   * RECOVERY_DLP_RECEIVED: the peer sent the data and was asked to
+	     force close the channel.
+
+	   * RECOVERY_CLOSE_CONFIRMED: the force close confirmed, but not all
+	     outputs of the channel are resolved yet.
+
+	   * RECOVERY_COMPLETE: the channel is fully resolved.
+
+	   * RECOVERY_NO_PEER_ADDRESS: the peer hasn't sent the data and there
+	     isn't any known address to reach it at. The recovery continues once
+	     the peer connects or announces an address.
+
+	   * RECOVERY_NEEDS_INTERVENTION: the peer didn't send the data within
+	     recovery.peer-timeout. Make sure the peer can be reached, e.g. with
+	     the connect command, or ask its operator to force close the
+	     channel.
+	`,
+	Action: actionDecorator(channelRecoveryStatus),
+}
+
+func channelRecoveryStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ChannelRecoveryStatus(
+		ctxc, &lnrpc.ChannelRecoveryStatusRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
 	return nil
 }
@@ -198,6 +198,19 @@ var addHoldInvoiceCommand = cli.Command{
 				"private channels in order to assist the " +
 				"payer in reaching you",
 		},
+		cli.Int64SliceFlag{
+			Name: "set_feature",
+			Usage: "a feature bit to set in the invoice on top " +
+				"of the default invoice features, replacing " +
+				"the other bit of the feature pair. Can be " +
+				"set multiple times in the same command",
+		},
+		cli.Int64SliceFlag{
+			Name: "unset_feature",
+			Usage: "a feature bit whose feature should be " +
+				"removed from the default invoice features. " +
+				"Can be set multiple times in the same command",
+		},
 	},
 	Action: actionDecorator(addHoldInvoice),
 }
@@ -251,6 +264,8 @@ func addHoldInvoice(ctx *cli.Context) error {
 		Expiry:          ctx.Int64("expiry"),
 		CltvExpiry:      ctx.Uint64("cltv_expiry_delta"),
 		Private:         ctx.Bool("private"),
+		SetFeatures:     featureBitsFlag(ctx, "set_feature"),
+		UnsetFeatures:   featureBitsFlag(ctx, "unset_feature"),
 	}
 
 	resp, err := client.AddHoldInvoice(ctxc, invoice)
@@ -450,27 +450,38 @@ func main() {
 		sendCoinsCommand,
 		listUnspentCommand,
 		connectCommand,
+		testConnectionCommand,
 		disconnectCommand,
+		banPeerCommand,
+		unbanPeerCommand,
+		listBannedPeersCommand,
 		openChannelCommand,
 		batchOpenChannelCommand,
+		abortChannelOpenCommand,
 		closeChannelCommand,
 		closeAllChannelsCommand,
+		estimateCloseCostsCommand,
 		abandonChannelCommand,
 		listPeersCommand,
 		walletBalanceCommand,
+		balanceBreakdownCommand,
 		channelBalanceCommand,
 		getInfoCommand,
 		getDebugInfoCommand,
+		getRuntimeConfigCommand,
 		encryptDebugPackageCommand,
 		decryptDebugPackageCommand,
 		getRecoveryInfoCommand,
 		pendingChannelsCommand,
+		listContractResolutionsCommand,
+		getChanCommitStateCommand,
 		sendPaymentCommand,
 		payInvoiceCommand,
 		sendToRouteCommand,
 		addInvoiceCommand,
 		lookupInvoiceCommand,
 		listInvoicesCommand,
+		getInvoiceIndicesCommand,
 		listChannelsCommand,
 		closedChannelsCommand,
 		listPaymentsCommand,
@@ -487,11 +498,20 @@ func main() {
 		signMessageCommand,
 		verifyMessageCommand,
 		feeReportCommand,
+		forwardingPoliciesCommand,
 		updateChannelPolicyCommand,
+		queueChannelPolicyCommand,
+		policyUpdateQueueCommand,
+		simulateFeeChangeCommand,
 		forwardingHistoryCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
+		checkChanBackupFileCommand,
+		snapshotChannelDBCommand,
+		compactChannelDBCommand,
+		exportChanBackupFileCommand,
 		restoreChanBackupCommand,
+		channelRecoveryStatusCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
 		deleteMacaroonIDCommand,
@@ -508,6 +528,7 @@ func main() {
 		fishCompletionCommand,
 		listAliasesCommand,
 		estimateRouteFeeCommand,
+		diagnosePaymentCommand,
 		generateManPageCommand,
 	}
 
@@ -587,6 +608,9 @@ func parseCoinSelectionStrategy(ctx *cli.Context) (
 	case "random":
 		return lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM, nil
 
+	case "bnb":
+		return lnrpc.CoinSelectionStrategy_STRATEGY_BNB, nil
+
 	default:
 		return 0, fmt.Errorf("unknown coin selection strategy "+
 			"%v", strategy)
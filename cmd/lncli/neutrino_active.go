@@ -45,6 +45,33 @@ func getNeutrinoStatus(ctx *cli.Context) error {
 	return nil
 }
 
+var getSyncProgressCommand = cli.Command{
+	Name:     "syncprogress",
+	Usage:    "Returns the sync progress of neutrino per phase.",
+	Category: "Neutrino",
+	Description: "Returns the sync progress of the block headers and the " +
+		"filter headers of the neutrino light client, each with its " +
+		"current and target height.",
+	Action: actionDecorator(getSyncProgress),
+}
+
+func getSyncProgress(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getNeutrinoKitClient(ctx)
+	defer cleanUp()
+
+	req := &neutrinorpc.GetSyncProgressRequest{}
+
+	resp, err := client.GetSyncProgress(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var addPeerCommand = cli.Command{
 	Name:     "addpeer",
 	Usage:    "Add a peer.",
@@ -236,6 +263,7 @@ func neutrinoCommands() []cli.Command {
 			Description: "",
 			Subcommands: []cli.Command{
 				getNeutrinoStatusCommand,
+				getSyncProgressCommand,
 				addPeerCommand,
 				disconnectPeerCommand,
 				isBannedCommand,
@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
 	"github.com/urfave/cli"
@@ -43,11 +44,13 @@ var updateNodeAnnouncementCommand = cli.Command{
 	Update the node's information and broadcast a new node announcement.
 
 	Add or remove addresses where your node can be reached at, change the
-	alias/color of the node or enable/disable supported feature bits without
-	restarting the node. A node announcement with the new information will
-	be created and brodcasted to the network.`,
+	alias/color of the node, enable/disable supported feature bits or set
+	custom TLV records without restarting the node. A node announcement
+	with the new information will be created and brodcasted to the
+	network.`,
 	ArgsUsage: "[--address_add=] [--address_remove=] [--alias=] " +
-		"[--color=] [--feature_bit_add=] [--feature_bit_remove=]",
+		"[--color=] [--feature_bit_add=] [--feature_bit_remove=] " +
+		"[--tlv_record_add=] [--tlv_record_remove=]",
 	Flags: []cli.Flag{
 		cli.StringSliceFlag{
 			Name: "address_add",
@@ -79,6 +82,21 @@ var updateNodeAnnouncementCommand = cli.Command{
 			Usage: "a feature bit that needs to be disabled. " +
 				"Can be set multiple times in the same command",
 		},
+		cli.StringSliceFlag{
+			Name: "tlv_record_add",
+			Usage: "a custom TLV record in the form " +
+				"type:hexvalue that should be appended to " +
+				"the node announcement, replacing any record " +
+				"of the same type. The type must be at least " +
+				"65536. Can be set multiple times in the " +
+				"same command",
+		},
+		cli.Int64SliceFlag{
+			Name: "tlv_record_remove",
+			Usage: "the type of a custom TLV record that needs " +
+				"to be removed from the node announcement. " +
+				"Can be set multiple times in the same command",
+		},
 	},
 	Action: actionDecorator(updateNodeAnnouncement),
 }
@@ -146,6 +164,40 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 		}
 	}
 
+	if ctx.IsSet("tlv_record_add") {
+		change = true
+		records, err := lncfg.ParseTLVRecords(
+			ctx.StringSlice("tlv_record_add"),
+		)
+		if err != nil {
+			return err
+		}
+
+		for recordType, value := range records {
+			action := &peersrpc.UpdateTlvRecordAction{
+				Action: peersrpc.UpdateAction_ADD,
+				Type:   recordType,
+				Value:  value,
+			}
+			req.TlvRecordUpdates = append(
+				req.TlvRecordUpdates, action,
+			)
+		}
+	}
+
+	if ctx.IsSet("tlv_record_remove") {
+		change = true
+		for _, recordType := range ctx.Int64Slice("tlv_record_remove") {
+			action := &peersrpc.UpdateTlvRecordAction{
+				Action: peersrpc.UpdateAction_REMOVE,
+				Type:   uint64(recordType),
+			}
+			req.TlvRecordUpdates = append(
+				req.TlvRecordUpdates, action,
+			)
+		}
+	}
+
 	if !change {
 		return fmt.Errorf("no changes for the node information " +
 			"detected")
@@ -80,12 +80,14 @@ func walletCommands() []cli.Command {
 			Description: "",
 			Subcommands: []cli.Command{
 				estimateFeeRateCommand,
+				estimateFeeRatesCommand,
 				pendingSweepsCommand,
 				bumpFeeCommand,
 				bumpCloseFeeCommand,
 				bumpForceCloseFeeCommand,
 				listSweepsCommand,
 				labelTxCommand,
+				queryTxsCommand,
 				publishTxCommand,
 				getTxCommand,
 				removeTxCommand,
@@ -176,6 +178,58 @@ func estimateFeeRate(ctx *cli.Context) error {
 	return nil
 }
 
+// defaultFeeRateConfTargets are the confirmation targets fee rates are
+// estimated for if none are given to the estimatefeerates command.
+var defaultFeeRateConfTargets = []uint32{2, 3, 6, 12, 24, 144, 1008}
+
+var estimateFeeRatesCommand = cli.Command{
+	Name: "estimatefeerates",
+	Usage: "Estimates the on-chain fee rates for multiple confirmation " +
+		"targets.",
+	ArgsUsage: "[conf_target...]",
+	Description: `
+	Returns the fee rate estimates for on-chain transactions in sat/kw and
+	sat/vb for each of the given confirmation targets, together with the
+	source of the estimates and how long ago they were last updated. If no
+	confirmation targets are given, a default set ranging from 2 to 1008
+	blocks is used.
+	`,
+	Action: actionDecorator(estimateFeeRates),
+}
+
+func estimateFeeRates(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	confTargets := defaultFeeRateConfTargets
+	if ctx.NArg() > 0 {
+		confTargets = make([]uint32, 0, ctx.NArg())
+		for _, arg := range ctx.Args() {
+			confTarget, err := strconv.ParseUint(arg, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid conf_target %q: %w",
+					arg, err)
+			}
+
+			confTargets = append(confTargets, uint32(confTarget))
+		}
+	}
+
+	resp, err := client.EstimateFeeRates(
+		ctxc, &walletrpc.EstimateFeeRatesRequest{
+			ConfTargets: confTargets,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var pendingSweepsCommand = cli.Command{
 	Name:      "pendingsweeps",
 	Usage:     "List all outputs that are pending to be swept within lnd.",
@@ -682,6 +736,111 @@ func labelTransaction(ctx *cli.Context) error {
 	return nil
 }
 
+var queryTxsCommand = cli.Command{
+	Name:  "querytxs",
+	Usage: "Lists the wallet transactions matching the given filters.",
+	Description: `
+	List the wallet transactions that match all of the given filters. The
+	amount filters apply to the absolute net value of a transaction from
+	the point of view of the wallet. Use --min_confs=1 to exclude
+	unconfirmed transactions. The result can be paginated with
+	--index_offset and --max_transactions.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "label",
+			Usage: "only list transactions whose label contains " +
+				"this string, ignoring case",
+		},
+		cli.Int64Flag{
+			Name: "min_amt",
+			Usage: "only list transactions with at least this " +
+				"absolute net value in satoshis",
+		},
+		cli.Int64Flag{
+			Name: "max_amt",
+			Usage: "only list transactions with at most this " +
+				"absolute net value in satoshis",
+		},
+		cli.Int64Flag{
+			Name: "min_confs",
+			Usage: "only list transactions with at least this " +
+				"many confirmations",
+		},
+		cli.Int64Flag{
+			Name: "max_confs",
+			Usage: "only list transactions with at most this " +
+				"many confirmations",
+		},
+		cli.Int64Flag{
+			Name: "start_time",
+			Usage: "only list transactions with a timestamp at " +
+				"or after this unix timestamp",
+		},
+		cli.Int64Flag{
+			Name: "end_time",
+			Usage: "only list transactions with a timestamp at " +
+				"or before this unix timestamp",
+		},
+		cli.StringFlag{
+			Name: "addr",
+			Usage: "only list transactions paying to or " +
+				"spending from this address",
+		},
+		cli.StringFlag{
+			Name: "account",
+			Usage: "only list transactions relevant to this " +
+				"account",
+		},
+		cli.Uint64Flag{
+			Name:  "index_offset",
+			Usage: "the number of matching transactions to skip",
+		},
+		cli.Uint64Flag{
+			Name: "max_transactions",
+			Usage: "the maximum number of transactions to list, " +
+				"0 lists all",
+		},
+	},
+	Action: actionDecorator(queryTransactions),
+}
+
+func queryTransactions(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we got any positional
+	// arguments.
+	if ctx.NArg() != 0 {
+		return cli.ShowCommandHelp(ctx, "querytxs")
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := walletClient.QueryTransactions(
+		ctxc, &walletrpc.QueryTransactionsRequest{
+			LabelContains:   ctx.String("label"),
+			MinAmountSat:    ctx.Int64("min_amt"),
+			MaxAmountSat:    ctx.Int64("max_amt"),
+			MinConfs:        int32(ctx.Int64("min_confs")),
+			MaxConfs:        int32(ctx.Int64("max_confs")),
+			StartTime:       ctx.Int64("start_time"),
+			EndTime:         ctx.Int64("end_time"),
+			Address:         ctx.String("addr"),
+			Account:         ctx.String("account"),
+			IndexOffset:     ctx.Uint64("index_offset"),
+			MaxTransactions: ctx.Uint64("max_transactions"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var publishTxCommand = cli.Command{
 	Name:      "publishtx",
 	Usage:     "Attempts to publish the passed transaction to the network.",
@@ -15,16 +15,19 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -33,6 +36,7 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
@@ -40,8 +44,13 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/localchans"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/tor"
 )
@@ -75,6 +84,7 @@ const (
 	defaultMaxLogFileSize                = 10
 	defaultMinBackoff                    = time.Second
 	defaultMaxBackoff                    = time.Hour
+	defaultPeerAddrCacheSize             = 3
 	defaultLetsEncryptDirname            = "letsencrypt"
 	defaultLetsEncryptListen             = ":80"
 
@@ -116,6 +126,19 @@ const (
 	// initiated the channel closure.
 	defaultCoopCloseTargetConfs = 6
 
+	// defaultRescanConcurrency is the default number of historical rescans
+	// the chain notifier performs concurrently.
+	defaultRescanConcurrency = chainntnfs.DefaultRescanConcurrency
+
+	// defaultCoopCloseFinalConfs is the default number of confirmations
+	// after which a cooperative close transaction is considered final.
+	defaultCoopCloseFinalConfs = 1
+
+	// defaultCltvDeltaCheckInterval is the default interval at which the
+	// CLTV deltas we advertise are compared against the ones required by
+	// our connected peers.
+	defaultCltvDeltaCheckInterval = 24 * time.Hour
+
 	// defaultBlockCacheSize is the size (in bytes) of blocks that will be
 	// keep in memory if no size is specified.
 	defaultBlockCacheSize uint64 = 20 * 1024 * 1024 // 20 MB
@@ -141,6 +164,13 @@ const (
 	defaultDiskBackoff  = time.Minute
 	defaultDiskAttempts = 0
 
+	// defaultDiskOpMultiplier is the default safety margin applied to the
+	// estimated disk space a database compaction or migration needs before
+	// it is started. Unlike the health check, this check is enabled by
+	// default, as running out of disk space during such an operation is
+	// much worse than refusing to start it.
+	defaultDiskOpMultiplier = 1.5
+
 	// Set defaults for a health check which ensures that the TLS certificate
 	// is not expired. Although this check is off by default (not all setups
 	// require it), we still set the other default values so that the health
@@ -169,6 +199,31 @@ const (
 	defaultRSBackoff  = time.Second * 30
 	defaultRSAttempts = 1
 
+	// Set defaults for a health check which ensures that the fee
+	// estimates of an external fee URL are up to date. Although this check
+	// is off by default (only active when a fee URL is used), we still set
+	// the other default values so that the health check can be easily
+	// enabled with sane defaults.
+	defaultFeeInterval     = time.Minute
+	defaultFeeTimeout      = time.Second * 5
+	defaultFeeBackoff      = time.Minute
+	defaultFeeAttempts     = 0
+	defaultFeeMaxStaleness = time.Hour
+
+	// Set defaults for a health check which alarms when the chain backend
+	// lags behind the network. This check is only active if one of the
+	// chain.max-tip-lag options is set, and is off by default so that a
+	// lagging backend only pauses forwarding until it caught up.
+	defaultTipLagInterval = time.Minute
+	defaultTipLagTimeout  = time.Second * 5
+	defaultTipLagBackoff  = time.Minute
+	defaultTipLagAttempts = 0
+
+	// defaultMinFundingConfs is the lowest number of funding confirmations
+	// an open channel request may ask for by default. This value can be
+	// overridden with --funding.min-funding-confs.
+	defaultMinFundingConfs = 1
+
 	// defaultRemoteMaxHtlcs specifies the default limit for maximum
 	// concurrent HTLCs the remote party may add to commitment transactions.
 	// This value can be overridden with --default-remote-max-htlcs.
@@ -207,6 +262,17 @@ const (
 	// used by default to fund transactions.
 	defaultCoinSelectionStrategy = "largest"
 
+	// preimageStoreChannelDB stores preimages in the channel database.
+	preimageStoreChannelDB = "channeldb"
+
+	// preimageStoreSeparate stores preimages encrypted in a separate bolt
+	// database.
+	preimageStoreSeparate = "separate"
+
+	// defaultPreimageStoreFilename is the default file name of the separate
+	// preimage database.
+	defaultPreimageStoreFilename = "preimages.db"
+
 	// defaultKeepFailedPaymentAttempts is the default setting for whether
 	// to keep failed payments in the database.
 	defaultKeepFailedPaymentAttempts = false
@@ -273,6 +339,12 @@ var (
 	defaultBitcoindEstimateMode = "CONSERVATIVE"
 	bitcoindEstimateModes       = [2]string{"ECONOMICAL", defaultBitcoindEstimateMode}
 
+	// coinSelectionStrategies defines all the legal values for the coin
+	// selection strategy.
+	coinSelectionStrategies = [3]string{
+		defaultCoinSelectionStrategy, "random", "bnb",
+	}
+
 	defaultPrunedNodeMaxPeers = 4
 )
 
@@ -284,14 +356,19 @@ var (
 //nolint:lll
 type Config struct {
 	ShowVersion bool `short:"V" long:"version" description:"Display version information and exit"`
+	CheckConfig bool `long:"checkconfig" description:"Validate the configuration, print the resolved config with secrets redacted and exit. Exits with a non-zero code if the config is invalid"`
 
 	LndDir       string `long:"lnddir" description:"The base directory that contains lnd's data, logs, configuration file, etc. This option overwrites all other directory options."`
 	ConfigFile   string `short:"C" long:"configfile" description:"Path to configuration file"`
 	DataDir      string `short:"b" long:"datadir" description:"The directory to store lnd's data within"`
 	SyncFreelist bool   `long:"sync-freelist" description:"Whether the databases used within lnd should sync their freelist to disk. This is disabled by default resulting in improved memory performance during operation, but with an increase in startup time."`
 
+	Includes []string `long:"include" description:"Path to an additional configuration file that is loaded after the file that includes it, overriding its options. Options that can be specified multiple times aren't overridden, the values of all configuration files and the command line are combined instead. Relative paths are resolved against the directory of the including file, or the working directory if given on the command line. Command line options override all configuration files. Can be specified multiple times."`
+
 	TLSCertPath        string        `long:"tlscertpath" description:"Path to write the TLS certificate for lnd's RPC and REST services"`
 	TLSKeyPath         string        `long:"tlskeypath" description:"Path to write the TLS private key for lnd's RPC and REST services"`
+	TLSCertBlob        string        `long:"tlscertblob" description:"The PEM encoded TLS certificate for lnd's RPC and REST services, e.g. $TLS_CERT_PEM to read it from an environment variable. Line breaks can be written as \\n. The certificate is only kept in memory and never renewed, so tlsautorefresh has no effect. Requires tlskeyblob and can't be combined with tlscertpath"`
+	TLSKeyBlob         string        `long:"tlskeyblob" description:"The PEM encoded TLS private key for lnd's RPC and REST services, e.g. $TLS_KEY_PEM to read it from an environment variable. Line breaks can be written as \\n. Requires tlscertblob and can't be combined with tlskeypath or tlsencryptkey"`
 	TLSExtraIPs        []string      `long:"tlsextraip" description:"Adds an extra ip to the generated certificate"`
 	TLSExtraDomains    []string      `long:"tlsextradomain" description:"Adds an extra domain to the generated certificate"`
 	TLSAutoRefresh     bool          `long:"tlsautorefresh" description:"Re-generate TLS certificate and key if the IPs or domains are changed"`
@@ -316,16 +393,20 @@ type Config struct {
 	// loadConfig function. We need to expose the 'raw' strings so the
 	// command line library can access them.
 	// Only the parsed net.Addrs should be used!
-	RawRPCListeners   []string `long:"rpclisten" description:"Add an interface/port/socket to listen for RPC connections"`
-	RawRESTListeners  []string `long:"restlisten" description:"Add an interface/port/socket to listen for REST connections"`
+	RawRPCListeners   []string `long:"rpclisten" description:"Add an interface/port/socket to listen for RPC connections. A listener can use its own TLS certificate instead of the global one by appending ,tlscertpath=<path>,tlskeypath=<path> to its address"`
+	RawRESTListeners  []string `long:"restlisten" description:"Add an interface/port/socket to listen for REST connections. A listener can use its own TLS certificate instead of the global one by appending ,tlscertpath=<path>,tlskeypath=<path> to its address, or disable TLS by appending ,notls"`
 	RawListeners      []string `long:"listen" description:"Add an interface/port to listen for peer connections"`
 	RawExternalIPs    []string `long:"externalip" description:"Add an ip:port to the list of local addresses we claim to listen on to peers. If a port is not specified, the default (9735) will be used regardless of other parameters"`
 	ExternalHosts     []string `long:"externalhosts" description:"Add a hostname:port that should be periodically resolved to announce IPs for. If a port is not specified, the default (9735) will be used."`
+	RawAnnounceTypes  []string `long:"announce-addr-type" description:"Only advertise addresses of this type in the node announcement. Can be specified multiple times. Valid types are ipv4, ipv6, torv2, torv3 and opaque. If unset, all addresses are advertised, unless Tor is active without tor.skip-proxy-for-clearnet-targets, in which case only onion addresses are advertised."`
 	RPCListeners      []net.Addr
 	RESTListeners     []net.Addr
+	RPCListenerTLS    map[string]*lncfg.ListenerTLS
+	RESTListenerTLS   map[string]*lncfg.ListenerTLS
 	RestCORS          []string `long:"restcors" description:"Add an ip:port/hostname to allow cross origin access from. To allow all origins, set as \"*\"."`
 	Listeners         []net.Addr
 	ExternalIPs       []net.Addr
+	AnnounceTypes     []netann.AddrType
 	DisableListen     bool          `long:"nolisten" description:"Disable listening for incoming peer connections"`
 	DisableRest       bool          `long:"norest" description:"Disable REST API"`
 	DisableRestTLS    bool          `long:"no-rest-tls" description:"Disable TLS for REST connections"`
@@ -333,11 +414,19 @@ type Config struct {
 	WSPongWait        time.Duration `long:"ws-pong-wait" description:"The time we wait for a pong response message on REST based WebSocket connections before the connection is closed as inactive"`
 	NAT               bool          `long:"nat" description:"Toggle NAT traversal support (using either UPnP or NAT-PMP) to automatically advertise your external IP address to the network -- NOTE this does not support devices behind multiple NATs"`
 	AddPeers          []string      `long:"addpeer" description:"Specify peers to connect to first"`
+	RawPeerDenylist   []string      `long:"peer-denylist" description:"The hex encoded public key of a peer lnd should never automatically reconnect to, e.g. to isolate a misbehaving peer without closing its channels. Inbound and manual connections are still possible. Can be specified multiple times."`
+	RawPeerAllowlist  []string      `long:"peer-allowlist" description:"The hex encoded public key of a peer that is always prioritized when reconnecting. Its connection is established first on startup and retried with the minimum backoff. Can be specified multiple times."`
+	PeerDenylist      map[route.Vertex]struct{}
+	PeerAllowlist     map[route.Vertex]struct{}
+	InboundNets       []*net.IPNet
+	RawInboundNets    []string      `long:"peer-inbound-allowed-nets" description:"Only accept inbound peer connections from this network in CIDR notation, e.g. 203.0.113.0/24 or 2001:db8::/32. Connections from other networks are closed before the handshake. Can be specified multiple times. If unset, inbound connections from all networks are accepted."`
+	InboundAllowTor   bool          `long:"peer-inbound-allow-tor" description:"Also accept inbound peer connections from the loopback interface if peer-inbound-allowed-nets is set. Connections through our Tor onion service originate from there."`
+	PeerAddrCacheSize int           `long:"peer-address-cache-size" description:"The maximum number of addresses per peer that lnd persists after connecting to the peer over them successfully. They are tried on reconnect in addition to the addresses learned from gossip, so a changed address doesn't prevent reconnecting until gossip catches up. Addresses not connected to successfully again within 30 days are evicted. Set to 0 to disable the cache."`
 	MinBackoff        time.Duration `long:"minbackoff" description:"Shortest backoff when reconnecting to persistent peers. Valid time units are {s, m, h}."`
 	MaxBackoff        time.Duration `long:"maxbackoff" description:"Longest backoff when reconnecting to persistent peers. Valid time units are {s, m, h}."`
 	ConnectionTimeout time.Duration `long:"connectiontimeout" description:"The timeout value for network connections. Valid time units are {ms, s, m, h}."`
 
-	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <global-level>,<subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <global-level>,<subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems -- A SIGHUP re-reads this option from the config file"`
 
 	CPUProfile string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
 
@@ -346,10 +435,25 @@ type Config struct {
 	BlockingProfile int `long:"blockingprofile" description:"Used to enable a blocking profile to be served on the profiling port. This takes a value from 0 to 1, with 1 including every blocking event, and 0 including no events."`
 	MutexProfile    int `long:"mutexprofile" description:"Used to Enable a mutex profile to be served on the profiling port. This takes a value from 0 to 1, with 1 including every mutex event, and 0 including no events."`
 
-	UnsafeDisconnect   bool   `long:"unsafe-disconnect" description:"DEPRECATED: Allows the rpcserver to intentionally disconnect from peers with open channels. THIS FLAG WILL BE REMOVED IN 0.10.0" hidden:"true"`
-	UnsafeReplay       bool   `long:"unsafe-replay" description:"Causes a link to replay the adds on its commitment txn after starting up, this enables testing of the sphinx replay logic."`
-	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
-	BackupFilePath     string `long:"backupfilepath" description:"The target location of the channel backup file"`
+	UnsafeDisconnect         bool   `long:"unsafe-disconnect" description:"DEPRECATED: Allows the rpcserver to intentionally disconnect from peers with open channels. THIS FLAG WILL BE REMOVED IN 0.10.0" hidden:"true"`
+	UnsafeReplay             bool   `long:"unsafe-replay" description:"Causes a link to replay the adds on its commitment txn after starting up, this enables testing of the sphinx replay logic."`
+	MaxPendingChannels       int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
+	MaxPendingChannelsGlobal int    `long:"max-pending-channels-global" description:"The maximum number of pending channels permitted across all peers. Channels we open ourselves, including batch opens, count towards the limit, but only incoming channels are rejected once it's reached. Must not be lower than maxpendingchannels. Set to 0 to disable the limit."`
+	MaxChannelsPerPeer       int    `long:"max-channels-per-peer" description:"The maximum number of channels, including pending ones, permitted with a single peer. Channels opened by the peer and channels we open ourselves both count towards the limit. Set to 0 to disable the limit."`
+	BackupFilePath           string `long:"backupfilepath" description:"The target location of the channel backup file"`
+	VerifyRestoredChannels   bool   `long:"verify-restored-channels" description:"After restoring channels from a static channel backup, check while connecting to their peers through the chain backend that their funding outputs still exist unspent and match the backup. Channels that appear to be closed are reported in the logs and the RestoreChannelBackups response, but are restored nonetheless. Channels that were unconfirmed when they were backed up are not checked. With neutrino, each check requires a rescan starting at the confirmation height of the channel."`
+
+	RawMaxPendingChannelsOverrides []string `long:"maxpendingchannels-override" description:"Overrides maxpendingchannels for a single peer, in the format <pubkey>:<n> with the hex encoded public key of the peer. Peers without an override use maxpendingchannels. Can be specified multiple times."`
+	MaxPendingChannelsOverrides    map[route.Vertex]int
+
+	RawMinChanSizeOverrides []string `long:"minchansize-override" description:"Overrides minchansize for a single peer, in the format <pubkey>:<sat> with the hex encoded public key of the peer. The override may be lower than minchansize, but not lower than the dust limit. Peers without an override use minchansize. Can be specified multiple times."`
+	MinChanSizeOverrides    map[route.Vertex]btcutil.Amount
+
+	RawMinChanSizeNetOverrides []string `long:"minchansize-net-override" description:"Overrides minchansize for peers connecting from a network, in the format <cidr>:<sat>, e.g. 10.8.0.0/24:1000. If several networks contain the address of a peer, the most specific one applies. A minchansize-override of the peer takes precedence. Peers connected over Tor never match, including those connecting through our onion service from the loopback interface, so loopback networks are rejected. The override may be lower than minchansize, but not lower than the dust limit. Can be specified multiple times."`
+	MinChanSizeNetOverrides    []funding.NetMinChanSize
+
+	PreimageStore     string `long:"preimage-store" description:"Where the preimages learned when settling HTLCs are stored. 'channeldb' stores them in the channel database. 'separate' stores them encrypted with a key derived from the wallet seed in a separate bolt database at preimage-store-path, preimages that were stored in the channel database before remain available. When switching back to 'channeldb', an existing separate database is still read. 'separate' can't be used with the remote etcd and postgres database backends, as the separate database isn't replicated." choice:"channeldb" choice:"separate"`
+	PreimageStorePath string `long:"preimage-store-path" description:"The location of the separate preimage database, only used if preimage-store=separate. Defaults to preimages.db in the graph database directory."`
 
 	FeeURL string `long:"feeurl" description:"DEPRECATED: Use 'fee.url' option. Optional URL for external fee estimation. If no URL is specified, the method for fee estimation will depend on the chosen backend and network. Must be set for neutrino on mainnet." hidden:"true"`
 
@@ -376,7 +480,7 @@ type Config struct {
 
 	ResetWalletTransactions bool `long:"reset-wallet-transactions" description:"Removes all transaction history from the on-chain wallet on startup, forcing a full chain rescan starting at the wallet's birthday. Implements the same functionality as btcwallet's dropwtxmgr command. Should be set to false after successful execution to avoid rescanning on every restart of lnd."`
 
-	CoinSelectionStrategy string `long:"coin-selection-strategy" description:"The strategy to use for selecting coins for wallet transactions." choice:"largest" choice:"random"`
+	CoinSelectionStrategy string `long:"coin-selection-strategy" description:"The strategy to use for selecting coins for wallet transactions. 'bnb' searches for a set of coins that doesn't require a change output and falls back to 'largest' if there is none." choice:"largest" choice:"random" choice:"bnb"`
 
 	PaymentsExpirationGracePeriod time.Duration `long:"payments-expiration-grace-period" description:"A period to wait before force closing channels with outgoing htlcs that have timed-out and are a result of this node initiated payments."`
 	TrickleDelay                  int           `long:"trickledelay" description:"Time in milliseconds between each release of announcements to the network"`
@@ -384,11 +488,17 @@ type Config struct {
 	ChanDisableTimeout            time.Duration `long:"chan-disable-timeout" description:"The duration that must elapse after first detecting that an already active channel is actually inactive and sending channel update disabling it to the network. The pending disable can be canceled if the peer reconnects and becomes stable for chan-enable-timeout before the disable update is sent."`
 	ChanStatusSampleInterval      time.Duration `long:"chan-status-sample-interval" description:"The polling interval between attempts to detect if an active channel has become inactive due to its peer going offline."`
 	HeightHintCacheQueryDisable   bool          `long:"height-hint-cache-query-disable" description:"Disable queries from the height-hint cache to try to recover channels stuck in the pending close state. Disabling height hint queries may cause longer chain rescans, resulting in a performance hit. Unset this after channels are unstuck so you can get better performance again."`
+	RescanConcurrency             uint32        `long:"rescan-concurrency" description:"The maximum number of historical rescans the chain notifier performs concurrently, for example when recovering channels stuck in the pending close state. Higher values speed up recovery of many channels but put more load on the chain backend. Must not be above 64, 0 does not limit rescans."`
 	Alias                         string        `long:"alias" description:"The node alias. Used as a moniker by peers and intelligence services"`
 	Color                         string        `long:"color" description:"The color of the node in hex format (i.e. '#3399FF'). Used to customize node appearance in intelligence services"`
+	NodeAnnTLVRecords             []string      `long:"node-ann-tlv" description:"A custom TLV record to append to the node announcement, in the form type:hexvalue. The type must be in the custom range (>= 65536). Can be specified multiple times."`
 	MinChanSize                   int64         `long:"minchansize" description:"The smallest channel size (in satoshis) that we should accept. Incoming channels smaller than this will be rejected"`
 	MaxChanSize                   int64         `long:"maxchansize" description:"The largest channel size (in satoshis) that we should accept. Incoming channels larger than this will be rejected"`
 	CoopCloseTargetConfs          uint32        `long:"coop-close-target-confs" description:"The target number of blocks that a cooperative channel close transaction should confirm in. This is used to estimate the fee to use as the lower bound during fee negotiation for the channel closure."`
+	CoopCloseFinalConfs           uint32        `long:"coop-close-final-confs" description:"The number of confirmations a cooperative channel close transaction needs before the channel is considered closed and removed from the set of pending channels. Increasing this protects large channels against reorgs of the close transaction."`
+	CoopCloseAddr                 string        `long:"coop-close-addr" description:"An address all cooperative closes pay our balance out to, unless the channel has an upfront shutdown script or a delivery address is requested for the close. Paying all closes out to a single address reduces the fragmentation of the wallet's UTXOs. Taproot addresses are only used if the peer supports the shutdown-any-segwit feature, a fresh address is used otherwise."`
+	CoopCloseConsolidate          bool          `long:"coop-close-consolidate" description:"If set, a fresh wallet address is generated on startup that all cooperative closes pay our balance out to, with the same exceptions as coop-close-addr. Cannot be used together with coop-close-addr."`
+	MaxConcurrentForceCloses      uint32        `long:"max-concurrent-force-closes" description:"The maximum number of force closes that are in flight at the same time. Further force closes are delayed until a commitment transaction of the others confirmed, the ones with the nearest HTLC deadline first. Force closes whose HTLCs are about to expire and force closes requested by the user are never delayed, no force close is delayed by more than 144 blocks. Set to 0 to disable the limit."`
 
 	ChannelCommitInterval time.Duration `long:"channel-commit-interval" description:"The maximum time that is allowed to pass between receiving a channel state update and signing the next commitment. Setting this to a longer duration allows for more efficient channel operations at the cost of latency."`
 
@@ -398,6 +508,8 @@ type Config struct {
 
 	KeepFailedPaymentAttempts bool `long:"keep-failed-payment-attempts" description:"Keeps persistent record of all failed payment attempts for successfully settled payments."`
 
+	PaymentsAutoPrune time.Duration `long:"payments-auto-prune" description:"If set, failed payments that were created longer ago than this are periodically removed from the database, together with their HTLC attempts. Successful and in-flight payments are never removed. Set to 0 to keep all failed payments."`
+
 	StoreFinalHtlcResolutions bool `long:"store-final-htlc-resolutions" description:"Persistently store the final resolution of incoming htlcs."`
 
 	DefaultRemoteMaxHtlcs uint16 `long:"default-remote-max-htlcs" description:"The default max_htlc applied when opening or accepting channels. This value limits the number of concurrent HTLCs that the remote party can add to the commitment. The maximum possible value is 483."`
@@ -421,10 +533,20 @@ type Config struct {
 
 	MaxOutgoingCltvExpiry uint32 `long:"max-cltv-expiry" description:"The maximum number of blocks funds could be locked up for when forwarding payments."`
 
+	MaxOutgoingCltvExpiryPolicy htlcswitch.MaxCltvExpiryPolicy `long:"max-cltv-expiry-policy" choice:"fail" choice:"clamp" description:"How to handle forwarded HTLCs whose outgoing time lock exceeds max-cltv-expiry. 'fail' fails them back to the sender, 'clamp' lowers their outgoing time lock to max-cltv-expiry and forwards them if the lowered time lock still leaves enough time before the HTLC expires. The next hop may reject a clamped HTLC."`
+
 	MaxChannelFeeAllocation float64 `long:"max-channel-fee-allocation" description:"The maximum percentage of total funds that can be allocated to a channel's commitment fee. This only applies for the initiator of the channel. Valid values are within [0.1, 1]."`
 
 	MaxCommitFeeRateAnchors uint64 `long:"max-commit-fee-rate-anchors" description:"The maximum fee rate in sat/vbyte that will be used for commitments of channels of the anchors type. Must be large enough to ensure transaction propagation"`
 
+	MinRelayFeeRateOverride float64 `long:"min-relay-feerate-override" description:"A hard floor in sat/vbyte for the fee rate of all transactions lnd broadcasts, such as sweeps, closes and funding transactions, independent of the fee estimator's relay fee. Explicitly requested fee rates below it are rejected. Must be at least 1 sat/vbyte and not larger than max-commit-fee-rate-anchors. Set to 0 to disable."`
+
+	MaxFeeRate uint64 `long:"max-feerate" description:"A hard ceiling in sat/vbyte for the fee rate of all transactions lnd creates, such as sends, funding transactions, sweeps and closes. Fee rate estimates above the ceiling are lowered to it, explicitly requested fee rates above it are rejected. The sweeper's max fee rate is lowered to the ceiling if it is higher. Must be at least max-commit-fee-rate-anchors and 100 sat/vbyte, so anchor channels can still be fee bumped. Set to 0 to disable."`
+
+	AnchorReserve int64 `long:"anchor-reserve" description:"The amount in satoshis that is held back in the wallet for each public anchor channel in order to fee bump its commitment transaction after a force close. Wallet funds below the total reserve are not used for channel funding or on-chain sends. Set to 0 to disable the reserve."`
+
+	MaxAnchorReserve int64 `long:"max-anchor-reserve" description:"The maximum total amount in satoshis that is held back in the wallet for anchor channel fee bumping, regardless of the number of open anchor channels. Defaults to 10 times anchor-reserve if unset."`
+
 	DryRunMigration bool `long:"dry-run-migration" description:"If true, lnd will abort committing a migration if it would otherwise have been successful. This leaves the database unmodified, and still compatible with the previously active version of lnd."`
 
 	net tor.Net
@@ -479,6 +601,22 @@ type Config struct {
 
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
+	Funding *lncfg.Funding `group:"funding" namespace:"funding"`
+
+	OnionMessages *lncfg.OnionMessages `group:"onion-messages" namespace:"onion-messages"`
+
+	Backup *lncfg.Backup `group:"backup" namespace:"backup"`
+
+	FeeAutopilot *lncfg.FeeAutopilot `group:"fee-autopilot" namespace:"fee-autopilot"`
+
+	DLP *lncfg.DLP `group:"dlp" namespace:"dlp"`
+
+	Recovery *lncfg.Recovery `group:"recovery" namespace:"recovery"`
+
+	Wallet *lncfg.Wallet `group:"wallet" namespace:"wallet"`
+
+	ChainOptions *lncfg.ChainOptions `group:"chain" namespace:"chain"`
+
 	GRPC *GRPCConfig `group:"grpc" namespace:"grpc"`
 
 	// LogWriter is the root logger that all of the daemon's subloggers are
@@ -490,9 +628,18 @@ type Config struct {
 	// network.
 	networkDir string
 
+	// configFilePath is the path of the config file the configuration was
+	// loaded from. It is used to reload the log levels.
+	configFilePath string
+
 	// ActiveNetParams contains parameters of the target chain.
 	ActiveNetParams chainreg.BitcoinNetParams
 
+	// OutgoingCltvRejectDelta is the number of blocks before the expiry of
+	// an HTLC at which the links refuse to offer it. The max outgoing time
+	// lock is validated against it.
+	OutgoingCltvRejectDelta uint32
+
 	// Estimator is used to estimate routing probabilities.
 	Estimator routing.Estimator
 
@@ -574,16 +721,21 @@ func DefaultConfig() Config {
 			EstimateMode:       defaultBitcoindEstimateMode,
 			PrunedNodeMaxPeers: defaultPrunedNodeMaxPeers,
 			ZMQReadDeadline:    defaultZMQReadDeadline,
+			Fallback:           &lncfg.BitcoindFallback{},
 		},
 		NeutrinoMode: &lncfg.Neutrino{
 			UserAgentName:    neutrino.UserAgentName,
 			UserAgentVersion: neutrino.UserAgentVersion,
+			FilterCacheSize:  neutrino.DefaultFilterCacheSize,
+			MinPeersTimeout:  lncfg.DefaultNeutrinoMinPeersTimeout,
+			BanDuration:      lncfg.DefaultNeutrinoBanDuration,
 		},
 		BlockCacheSize:     defaultBlockCacheSize,
 		MaxPendingChannels: lncfg.DefaultMaxPendingChannels,
 		NoSeedBackup:       defaultNoSeedBackup,
 		MinBackoff:         defaultMinBackoff,
 		MaxBackoff:         defaultMaxBackoff,
+		PeerAddrCacheSize:  defaultPeerAddrCacheSize,
 		ConnectionTimeout:  tor.DefaultConnTimeout,
 
 		Fee: &lncfg.Fee{
@@ -597,12 +749,13 @@ func DefaultConfig() Config {
 			PeersRPC:  &peersrpc.Config{},
 		},
 		Autopilot: &lncfg.AutoPilot{
-			MaxChannels:    5,
-			Allocation:     0.6,
-			MinChannelSize: int64(funding.MinChanFundingSize),
-			MaxChannelSize: int64(MaxFundingAmount),
-			MinConfs:       1,
-			ConfTarget:     autopilot.DefaultConfTarget,
+			MaxChannels:     5,
+			Allocation:      0.6,
+			MinChannelSize:  int64(funding.MinChanFundingSize),
+			MaxChannelSize:  int64(MaxFundingAmount),
+			MinConfs:        1,
+			ConfTarget:      autopilot.DefaultConfTarget,
+			FeeBudgetPeriod: autopilot.DefaultFeeBudgetPeriod,
 			Heuristic: map[string]float64{
 				"top_centrality": 1.0,
 			},
@@ -613,11 +766,13 @@ func DefaultConfig() Config {
 		ChanEnableTimeout:             defaultChanEnableTimeout,
 		ChanDisableTimeout:            defaultChanDisableTimeout,
 		HeightHintCacheQueryDisable:   defaultHeightHintCacheQueryDisable,
+		RescanConcurrency:             defaultRescanConcurrency,
 		Alias:                         defaultAlias,
 		Color:                         defaultColor,
 		MinChanSize:                   int64(funding.MinChanFundingSize),
 		MaxChanSize:                   int64(0),
 		CoopCloseTargetConfs:          defaultCoopCloseTargetConfs,
+		CoopCloseFinalConfs:           defaultCoopCloseFinalConfs,
 		DefaultRemoteMaxHtlcs:         defaultRemoteMaxHtlcs,
 		NumGraphSyncPeers:             defaultMinPeers,
 		HistoricalSyncInterval:        discovery.DefaultHistoricalSyncInterval,
@@ -646,7 +801,8 @@ func DefaultConfig() Config {
 				Backoff:  defaultChainBackoff,
 			},
 			DiskCheck: &lncfg.DiskCheckConfig{
-				RequiredRemaining: defaultRequiredDisk,
+				RequiredRemaining:   defaultRequiredDisk,
+				OperationMultiplier: defaultDiskOpMultiplier,
 				CheckConfig: &lncfg.CheckConfig{
 					Interval: defaultDiskInterval,
 					Attempts: defaultDiskAttempts,
@@ -672,29 +828,63 @@ func DefaultConfig() Config {
 				Attempts: defaultRSAttempts,
 				Backoff:  defaultRSBackoff,
 			},
+			FeeEstimator: &lncfg.FeeCheckConfig{
+				MaxStaleness: defaultFeeMaxStaleness,
+				CheckConfig: &lncfg.CheckConfig{
+					Interval: defaultFeeInterval,
+					Timeout:  defaultFeeTimeout,
+					Attempts: defaultFeeAttempts,
+					Backoff:  defaultFeeBackoff,
+				},
+			},
+			ChainTipLag: &lncfg.CheckConfig{
+				Interval: defaultTipLagInterval,
+				Timeout:  defaultTipLagTimeout,
+				Attempts: defaultTipLagAttempts,
+				Backoff:  defaultTipLagBackoff,
+			},
 		},
 		Gossip: &lncfg.Gossip{
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
 			ChannelUpdateInterval: discovery.DefaultChannelUpdateInterval,
 			SubBatchDelay:         discovery.DefaultSubBatchDelay,
+			PolicyUpdateBatchSize: localchans.DefaultBatchSize,
+			PolicyUpdateInterval:  localchans.DefaultInterval,
 		},
 		Invoices: &lncfg.Invoices{
-			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
+			HoldExpiryDelta:       lncfg.DefaultHoldInvoiceExpiryDelta,
+			FallbackAddrMode:      lncfg.FallbackAddrModeNone,
+			FallbackAddrMaxUnused: lncfg.DefaultFallbackAddrMaxUnused,
+			AllowZeroAmount:       true,
 		},
-		MaxOutgoingCltvExpiry:     htlcswitch.DefaultMaxOutgoingCltvExpiry,
-		MaxChannelFeeAllocation:   htlcswitch.DefaultMaxLinkFeeAllocation,
-		MaxCommitFeeRateAnchors:   lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
-		DustThreshold:             uint64(htlcswitch.DefaultDustThreshold.ToSatoshis()),
-		LogWriter:                 build.NewRotatingLogWriter(),
-		DB:                        lncfg.DefaultDB(),
-		Cluster:                   lncfg.DefaultCluster(),
-		RPCMiddleware:             lncfg.DefaultRPCMiddleware(),
-		ActiveNetParams:           chainreg.BitcoinTestNetParams,
-		ChannelCommitInterval:     defaultChannelCommitInterval,
-		PendingCommitInterval:     defaultPendingCommitInterval,
-		ChannelCommitBatchSize:    defaultChannelCommitBatchSize,
-		CoinSelectionStrategy:     defaultCoinSelectionStrategy,
-		KeepFailedPaymentAttempts: defaultKeepFailedPaymentAttempts,
+		Routing: &lncfg.Routing{
+			CltvDeltaCheckInterval: defaultCltvDeltaCheckInterval,
+			ProbeAmount: uint64(
+				routing.DefaultProbeAmount.ToSatoshis(),
+			),
+			ProbeFeeLimit: uint64(
+				routing.DefaultProbeFeeLimit.ToSatoshis(),
+			),
+			ProbeTargetPolicy: routing.ProbeTargetRandom,
+		},
+		MaxOutgoingCltvExpiry:       htlcswitch.DefaultMaxOutgoingCltvExpiry,
+		MaxOutgoingCltvExpiryPolicy: htlcswitch.MaxCltvExpiryPolicyFail,
+		MaxChannelFeeAllocation:     htlcswitch.DefaultMaxLinkFeeAllocation,
+		MaxCommitFeeRateAnchors:     lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
+		AnchorReserve:               int64(lnwallet.AnchorChanReservedValue),
+		DustThreshold:               uint64(htlcswitch.DefaultDustThreshold.ToSatoshis()),
+		LogWriter:                   build.NewRotatingLogWriter(),
+		DB:                          lncfg.DefaultDB(),
+		Cluster:                     lncfg.DefaultCluster(),
+		RPCMiddleware:               lncfg.DefaultRPCMiddleware(),
+		ActiveNetParams:             chainreg.BitcoinTestNetParams,
+		OutgoingCltvRejectDelta:     lncfg.DefaultOutgoingCltvRejectDelta,
+		ChannelCommitInterval:       defaultChannelCommitInterval,
+		PendingCommitInterval:       defaultPendingCommitInterval,
+		ChannelCommitBatchSize:      defaultChannelCommitBatchSize,
+		CoinSelectionStrategy:       defaultCoinSelectionStrategy,
+		PreimageStore:               preimageStoreChannelDB,
+		KeepFailedPaymentAttempts:   defaultKeepFailedPaymentAttempts,
 		RemoteSigner: &lncfg.RemoteSigner{
 			Timeout: lncfg.DefaultRemoteSignerRPCTimeout,
 		},
@@ -702,6 +892,16 @@ func DefaultConfig() Config {
 		Htlcswitch: &lncfg.Htlcswitch{
 			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
 		},
+		Funding: &lncfg.Funding{
+			MinFundingConfs: defaultMinFundingConfs,
+		},
+		OnionMessages: lncfg.DefaultOnionMessages(),
+		Backup:        lncfg.DefaultBackup(),
+		FeeAutopilot:  lncfg.DefaultFeeAutopilot(),
+		DLP:           lncfg.DefaultDLP(),
+		Recovery:      lncfg.DefaultRecovery(),
+		Wallet:        lncfg.DefaultWallet(),
+		ChainOptions:  lncfg.DefaultChainOptions(),
 		GRPC: &GRPCConfig{
 			ServerPingTime:    defaultGrpcServerPingTime,
 			ServerPingTimeout: defaultGrpcServerPingTimeout,
@@ -763,9 +963,14 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		}
 	}
 
+	// Files included on the command line are loaded after the config
+	// file and the files it includes.
+	cliIncludes := preCfg.Includes
+
 	// Next, load any additional configuration options from the file.
 	var configFileError error
 	cfg := preCfg
+	cfg.Includes = nil
 	fileParser := flags.NewParser(&cfg, flags.Default)
 	err := flags.NewIniParser(fileParser).ParseFile(configFilePath)
 	if err != nil {
@@ -781,6 +986,27 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		configFileError = err
 	}
 
+	// Then load the files included by the config file, followed by the
+	// ones included on the command line, each after the files they
+	// include in turn.
+	configFileAbs, err := filepath.Abs(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	included, err := loadConfigIncludes(
+		fileParser, &cfg, filepath.Dir(configFilePath), cfg.Includes,
+		[]string{configFileAbs},
+	)
+	if err != nil {
+		return nil, err
+	}
+	cliIncluded, err := loadConfigIncludes(
+		fileParser, &cfg, "", cliIncludes, []string{configFileAbs},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Finally, parse the remaining command line options again to ensure
 	// they take precedence.
 	flagParser := flags.NewParser(&cfg, flags.Default)
@@ -788,10 +1014,19 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		return nil, err
 	}
 
+	// We keep track of all loaded files, so the resolved config lists
+	// them.
+	cfg.Includes = append(included, cliIncluded...)
+
+	// Any string option can reference an environment variable, which we
+	// replace with its value now that all options are parsed.
+	expandEnvValues(&cfg)
+
 	// Make sure everything we just loaded makes sense.
-	cleanCfg, err := ValidateConfig(
-		cfg, interceptor, fileParser, flagParser,
-	)
+	cleanCfg, err := ValidateConfig(cfg, fileParser, flagParser)
+	if err == nil {
+		err = cleanCfg.setupLogging(interceptor)
+	}
 	if usageErr, ok := err.(*usageError); ok {
 		// The logging system might not yet be initialized, so we also
 		// write to stderr to make sure the error appears somewhere.
@@ -814,12 +1049,31 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		return nil, err
 	}
 
+	// Only now that the config is known to be valid, we create the
+	// directories and the log file, unless we were only asked to check the
+	// config, which must not leave anything behind on disk.
+	if !cleanCfg.CheckConfig {
+		if err := cleanCfg.createDirsAndLogFile(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Warn about missing config file only after all other configuration is
 	// done. This prevents the warning on help messages and invalid options.
 	// Note this should go directly before the return.
 	if configFileError != nil {
 		ltndLog.Warnf("%v", configFileError)
 	}
+	cleanCfg.configFilePath = configFilePath
+
+	// If we were only asked to check the config, we print the resolved
+	// config instead of starting the node.
+	if cleanCfg.CheckConfig {
+		if err := writeResolvedConfig(os.Stdout, *cleanCfg); err != nil {
+			return nil, err
+		}
+		os.Exit(0)
+	}
 
 	// Finally, log warnings for deprecated config options if they are set.
 	logWarningsForDeprecation(*cleanCfg)
@@ -827,6 +1081,112 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 	return cleanCfg, nil
 }
 
+// loadConfigIncludes loads the given included config files into cfg in order,
+// each followed by the files it includes in turn, so options of an included
+// file override the ones of the files loaded before it. Relative paths are
+// resolved against dir. The parents are the absolute paths of the files that
+// include the given ones and are used to reject circular includes. The
+// absolute paths of all loaded files are returned in the order they were
+// loaded.
+//
+// NOTE: Options that can be specified multiple times accumulate the values of
+// all loaded files.
+func loadConfigIncludes(parser *flags.Parser, cfg *Config, dir string,
+	includes, parents []string) ([]string, error) {
+
+	var loaded []string
+	for _, include := range includes {
+		path := CleanAndExpandPath(include)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range parents {
+			if parent != path {
+				continue
+			}
+
+			chain := strings.Join(append(parents, path), " -> ")
+			return nil, fmt.Errorf("circular config file include: "+
+				"%v", chain)
+		}
+
+		// The parser appends to the includes of the previous files,
+		// so we reset them to only learn about the ones of this file.
+		cfg.Includes = nil
+		err = flags.NewIniParser(parser).ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load included "+
+				"config file %v: %w", path, err)
+		}
+		loaded = append(loaded, path)
+
+		nestedParents := make([]string, 0, len(parents)+1)
+		nestedParents = append(nestedParents, parents...)
+		nestedParents = append(nestedParents, path)
+		nested, err := loadConfigIncludes(
+			parser, cfg, filepath.Dir(path), cfg.Includes,
+			nestedParents,
+		)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, nested...)
+	}
+
+	return loaded, nil
+}
+
+// reloadDebugLevel re-reads the debuglevel option from the config file and
+// applies it to the loggers. All other options in the file are ignored. If the
+// option is missing or invalid, the current log levels are kept.
+func (c *Config) reloadDebugLevel() (string, error) {
+	if c.configFilePath == "" {
+		return "", errors.New("no config file loaded")
+	}
+
+	// We parse the whole file into a fresh config, so all sections are
+	// known, but only use the debug level from it.
+	fileCfg := DefaultConfig()
+	fileCfg.DebugLevel = ""
+	fileCfg.Includes = nil
+	fileParser := flags.NewParser(&fileCfg, flags.IgnoreUnknown)
+	err := flags.NewIniParser(fileParser).ParseFile(c.configFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	// The debug level may also be set in an included file.
+	configFileAbs, err := filepath.Abs(c.configFilePath)
+	if err != nil {
+		return "", err
+	}
+	_, err = loadConfigIncludes(
+		fileParser, &fileCfg, filepath.Dir(c.configFilePath),
+		fileCfg.Includes, []string{configFileAbs},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if fileCfg.DebugLevel == "" {
+		return "", fmt.Errorf("debuglevel not set in %v",
+			c.configFilePath)
+	}
+
+	debugLevel := expandEnvValue(fileCfg.DebugLevel)
+	err = build.ParseAndSetDebugLevels(debugLevel, c.LogWriter)
+	if err != nil {
+		return "", err
+	}
+
+	return debugLevel, nil
+}
+
 // usageError is an error type that signals a problem with the supplied flags.
 type usageError struct {
 	err error
@@ -841,9 +1201,11 @@ func (u *usageError) Error() string {
 
 // ValidateConfig check the given configuration to be sane. This makes sure no
 // illegal values or combination of values are set. All file system paths are
-// normalized. The cleaned up config is returned on success.
-func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
-	flagParser *flags.Parser) (*Config, error) {
+// normalized. The cleaned up config is returned on success. No directories or
+// files are created and the logging isn't set up, which LoadConfig does once
+// the config is valid.
+func ValidateConfig(cfg Config, fileParser, flagParser *flags.Parser) (*Config,
+	error) {
 
 	// If the provided lnd directory is not the default, we'll modify the
 	// path to all of the files and directories that will live within it.
@@ -871,42 +1233,11 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	mkErr := func(format string, args ...interface{}) error {
 		return fmt.Errorf(funcName+": "+format, args...)
 	}
-	makeDirectory := func(dir string) error {
-		err := os.MkdirAll(dir, 0700)
-		if err != nil {
-			// Show a nicer error message if it's because a symlink
-			// is linked to a directory that does not exist
-			// (probably because it's not mounted).
-			if e, ok := err.(*os.PathError); ok && os.IsExist(err) {
-				link, lerr := os.Readlink(e.Path)
-				if lerr == nil {
-					str := "is symlink %s -> %s mounted?"
-					err = fmt.Errorf(str, e.Path, link)
-				}
-			}
-
-			str := "Failed to create lnd directory '%s': %v"
-			return mkErr(str, dir, err)
-		}
-
-		return nil
-	}
-
-	// IsSet returns true if an option has been set in either the config
-	// file or by a flag.
-	isSet := func(field string) (bool, error) {
-		fieldName, ok := reflect.TypeOf(Config{}).FieldByName(field)
-		if !ok {
-			str := "could not find field %s"
-			return false, mkErr(str, field)
-		}
-
-		long, ok := fieldName.Tag.Lookup("long")
-		if !ok {
-			str := "field %s does not have a long tag"
-			return false, mkErr(str, field)
-		}
 
+	// isLongSet returns true if the option with the given long name,
+	// including its namespace, has been set in either the config file or
+	// by a flag.
+	isLongSet := func(long string) bool {
 		// The user has the option to set the flag in either the config
 		// file or as a command line flag. If any is set, we consider it
 		// to be set, not applying any precedence rules here (since it
@@ -925,10 +1256,27 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 
 		return (fileOption != nil && fileOption.IsSet()) ||
-				(fileOptionNested != nil && fileOptionNested.IsSet()) ||
-				(flagOption != nil && flagOption.IsSet()) ||
-				(flagOptionNested != nil && flagOptionNested.IsSet()),
-			nil
+			(fileOptionNested != nil && fileOptionNested.IsSet()) ||
+			(flagOption != nil && flagOption.IsSet()) ||
+			(flagOptionNested != nil && flagOptionNested.IsSet())
+	}
+
+	// IsSet returns true if an option has been set in either the config
+	// file or by a flag.
+	isSet := func(field string) (bool, error) {
+		fieldName, ok := reflect.TypeOf(Config{}).FieldByName(field)
+		if !ok {
+			str := "could not find field %s"
+			return false, mkErr(str, field)
+		}
+
+		long, ok := fieldName.Tag.Lookup("long")
+		if !ok {
+			str := "field %s does not have a long tag"
+			return false, mkErr(str, field)
+		}
+
+		return isLongSet(long), nil
 	}
 
 	// As soon as we're done parsing configuration options, ensure all paths
@@ -948,14 +1296,26 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.BitcoindMode.ConfigPath,
 	)
 	cfg.BitcoindMode.RPCCookie = CleanAndExpandPath(cfg.BitcoindMode.RPCCookie)
+	cfg.BitcoindMode.Fallback.RPCCookie = CleanAndExpandPath(
+		cfg.BitcoindMode.Fallback.RPCCookie,
+	)
 	cfg.Tor.PrivateKeyPath = CleanAndExpandPath(cfg.Tor.PrivateKeyPath)
 	cfg.Tor.WatchtowerKeyPath = CleanAndExpandPath(cfg.Tor.WatchtowerKeyPath)
 	cfg.Watchtower.TowerDir = CleanAndExpandPath(cfg.Watchtower.TowerDir)
 	cfg.BackupFilePath = CleanAndExpandPath(cfg.BackupFilePath)
+	cfg.PreimageStorePath = CleanAndExpandPath(cfg.PreimageStorePath)
+	cfg.Routing.AttemptTraceFile = CleanAndExpandPath(
+		cfg.Routing.AttemptTraceFile,
+	)
 	cfg.WalletUnlockPasswordFile = CleanAndExpandPath(
 		cfg.WalletUnlockPasswordFile,
 	)
 
+	err := checkCoinSelectionStrategy(cfg.CoinSelectionStrategy)
+	if err != nil {
+		return nil, mkErr("invalid coin-selection-strategy: %v", err)
+	}
+
 	// Ensure that the user didn't attempt to specify negative values for
 	// any of the autopilot params.
 	if cfg.Autopilot.MaxChannels < 0 {
@@ -988,6 +1348,21 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 		return nil, mkErr(str)
 	}
+	if cfg.Autopilot.FeeBudget < 0 {
+		str := "autopilot.fee-budget must be non-negative"
+
+		return nil, mkErr(str)
+	}
+	if cfg.Autopilot.FeeBudget > 0 && cfg.Autopilot.FeeBudgetPeriod <= 0 {
+		str := "autopilot.fee-budget-period must be positive"
+
+		return nil, mkErr(str)
+	}
+	if cfg.Autopilot.ActivationDelay < 0 {
+		str := "autopilot.activation-delay must be non-negative"
+
+		return nil, mkErr(str)
+	}
 
 	// Ensure that the specified values for the min and max channel size
 	// are within the bounds of the normal chan size constraints.
@@ -1036,6 +1411,56 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
+	// The per peer minimum channel sizes may be lower than the global one,
+	// but must still be within the bounds of the protocol.
+	cfg.MinChanSizeOverrides, err = parseMinChanSizeOverrides(
+		cfg.RawMinChanSizeOverrides,
+	)
+	if err != nil {
+		return nil, mkErr("invalid minchansize-override: %v", err)
+	}
+	for peer, minChanSize := range cfg.MinChanSizeOverrides {
+		if minChanSize > btcutil.Amount(cfg.MaxChanSize) {
+			return nil, mkErr("invalid minchansize-override of "+
+				"peer %v: min channel size %v must be no "+
+				"greater than max channel size %v", peer,
+				int64(minChanSize), cfg.MaxChanSize)
+		}
+	}
+	cfg.MinChanSizeNetOverrides, err = parseMinChanSizeNetOverrides(
+		cfg.RawMinChanSizeNetOverrides,
+	)
+	if err != nil {
+		return nil, mkErr("invalid minchansize-net-override: %v", err)
+	}
+	for _, override := range cfg.MinChanSizeNetOverrides {
+		if override.MinChanSize > btcutil.Amount(cfg.MaxChanSize) {
+			return nil, mkErr("invalid minchansize-net-override "+
+				"of network %v: min channel size %v must be "+
+				"no greater than max channel size %v",
+				override.Net, int64(override.MinChanSize),
+				cfg.MaxChanSize)
+		}
+	}
+
+	// Limit the number of concurrent rescans to avoid overwhelming the
+	// chain backend. Zero doesn't limit them at all.
+	if cfg.RescanConcurrency > chainntnfs.MaxRescanConcurrency {
+		return nil, mkErr("invalid rescan-concurrency %d, must not "+
+			"be above %d", cfg.RescanConcurrency,
+			chainntnfs.MaxRescanConcurrency)
+	}
+
+	// Ensure that the number of confirmations required for a cooperative
+	// close is within the range our chain notifiers can handle.
+	if cfg.CoopCloseFinalConfs < 1 ||
+		cfg.CoopCloseFinalConfs > chainntnfs.MaxNumConfs {
+
+		return nil, mkErr("invalid coop-close-final-confs %d, must be "+
+			"between 1 and %d", cfg.CoopCloseFinalConfs,
+			chainntnfs.MaxNumConfs)
+	}
+
 	// Ensure that the amount data for revoked commitment transactions is
 	// stored if the watchtower client is active.
 	if cfg.DB.NoRevLogAmtData && cfg.WtClient.Active {
@@ -1043,6 +1468,27 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			"if the watchtower client is active")
 	}
 
+	// The max outgoing time lock must leave room for HTLCs that don't
+	// expire too soon to be offered by the links, otherwise every clamped
+	// HTLC would be failed anyway. It also must not exceed the largest CLTV
+	// delta we support.
+	if cfg.MaxOutgoingCltvExpiry <= cfg.OutgoingCltvRejectDelta ||
+		cfg.MaxOutgoingCltvExpiry > MaxTimeLockDelta {
+
+		return nil, mkErr("invalid max cltv expiry: %v, must be "+
+			"within (%v, %v]", cfg.MaxOutgoingCltvExpiry,
+			cfg.OutgoingCltvRejectDelta, MaxTimeLockDelta)
+	}
+
+	switch cfg.MaxOutgoingCltvExpiryPolicy {
+	case htlcswitch.MaxCltvExpiryPolicyFail,
+		htlcswitch.MaxCltvExpiryPolicyClamp:
+
+	default:
+		return nil, mkErr("invalid max cltv expiry policy: %v",
+			cfg.MaxOutgoingCltvExpiryPolicy)
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, mkErr("invalid max channel fee allocation: %v, "+
@@ -1055,6 +1501,132 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			cfg.MaxCommitFeeRateAnchors)
 	}
 
+	// A minimum relay fee rate override must be a valid fee rate that our
+	// anchor channel commitments are still allowed to pay, otherwise they
+	// could never satisfy the floor.
+	if cfg.MinRelayFeeRateOverride != 0 {
+		if cfg.MinRelayFeeRateOverride < 1 {
+			return nil, mkErr("invalid min relay fee rate "+
+				"override: %v, must be at least 1 sat/vByte",
+				cfg.MinRelayFeeRateOverride)
+		}
+
+		maxAnchorsFeeRate := float64(cfg.MaxCommitFeeRateAnchors)
+		if cfg.MinRelayFeeRateOverride > maxAnchorsFeeRate {
+			return nil, mkErr("min relay fee rate override "+
+				"(%v sat/vByte) must not be larger than max "+
+				"commit fee rate anchors (%v sat/vByte)",
+				cfg.MinRelayFeeRateOverride,
+				cfg.MaxCommitFeeRateAnchors)
+		}
+	}
+
+	// A max fee rate must leave room for the fee rates our anchor channels
+	// rely on: the commitment fee rate and the fee rates needed to CPFP a
+	// commitment or sweep its outputs before their deadlines.
+	if cfg.MaxFeeRate != 0 {
+		minMaxFeeRate := uint64(lncfg.MaxFeeRateFloor)
+		if cfg.MaxCommitFeeRateAnchors > minMaxFeeRate {
+			minMaxFeeRate = cfg.MaxCommitFeeRateAnchors
+		}
+		if cfg.MaxFeeRate < minMaxFeeRate {
+			return nil, mkErr("invalid max fee rate: %v "+
+				"sat/vByte, must be at least %v sat/vByte to "+
+				"not conflict with max commit fee rate "+
+				"anchors (%v sat/vByte) and anchor sweeps",
+				cfg.MaxFeeRate, minMaxFeeRate,
+				cfg.MaxCommitFeeRateAnchors)
+		}
+
+		if float64(cfg.MaxFeeRate) < cfg.MinRelayFeeRateOverride {
+			return nil, mkErr("max fee rate (%v sat/vByte) must "+
+				"not be smaller than min relay fee rate "+
+				"override (%v sat/vByte)", cfg.MaxFeeRate,
+				cfg.MinRelayFeeRateOverride)
+		}
+
+		// The sweeper must not exceed the ceiling either.
+		maxFeeRate := chainfee.SatPerVByte(cfg.MaxFeeRate)
+		if cfg.Sweeper.MaxFeeRate > maxFeeRate {
+			cfg.Sweeper.MaxFeeRate = maxFeeRate
+		}
+	}
+
+	// Ensure the anchor reserve parameters are sane. A zero reserve
+	// disables holding back funds for anchor fee bumping. Unless set, the
+	// maximum follows the configured reserve, so raising the reserve alone
+	// doesn't require raising the maximum too.
+	if cfg.AnchorReserve < 0 || cfg.MaxAnchorReserve < 0 {
+		return nil, mkErr("invalid anchor reserve: anchor-reserve "+
+			"(%v) and max-anchor-reserve (%v) must not be negative",
+			cfg.AnchorReserve, cfg.MaxAnchorReserve)
+	}
+	if cfg.MaxAnchorReserve == 0 {
+		cfg.MaxAnchorReserve = lnwallet.MaxAnchorChanReservedFactor *
+			cfg.AnchorReserve
+	}
+	if cfg.MaxAnchorReserve < cfg.AnchorReserve {
+		return nil, mkErr("invalid anchor reserve: max-anchor-reserve "+
+			"(%v) must not be smaller than anchor-reserve (%v)",
+			cfg.MaxAnchorReserve, cfg.AnchorReserve)
+	}
+
+	// A fee cache TTL below the maximum update interval would render the
+	// estimates stale between two regular updates.
+	if cfg.Fee.CacheTTL < 0 || (cfg.Fee.CacheTTL != 0 &&
+		cfg.Fee.CacheTTL <= cfg.Fee.MaxUpdateTimeout) {
+
+		return nil, mkErr("fee.cache-ttl (%v) must be greater than "+
+			"fee.max-update-timeout (%v)", cfg.Fee.CacheTTL,
+			cfg.Fee.MaxUpdateTimeout)
+	}
+
+	// Make sure the fee URL schema is well-formed, so we don't only fail
+	// once we try to parse the first fee response.
+	if cfg.Fee.URLSchema != "" {
+		if cfg.Fee.URL == "" && cfg.FeeURL == "" {
+			return nil, mkErr("fee.url-schema requires " +
+				"fee.url to be set")
+		}
+
+		_, err := chainfee.ParseFeeSchema(cfg.Fee.URLSchema)
+		if err != nil {
+			return nil, mkErr("invalid fee.url-schema: %v", err)
+		}
+	}
+
+	if cfg.Routing.CltvDeltaCheckInterval < 0 {
+		return nil, mkErr("routing.cltv-delta-check-interval must not "+
+			"be negative, got %v", cfg.Routing.CltvDeltaCheckInterval)
+	}
+
+	if cfg.Routing.MaxGraphChannels < 0 {
+		return nil, mkErr("routing.max-graph-channels must not be "+
+			"negative, got %v", cfg.Routing.MaxGraphChannels)
+	}
+
+	// The mission control decay half-life is unset by default, in which
+	// case the decay configured for the estimator is used. Once set, it
+	// must be positive.
+	if isLongSet("routing.mc-decay-halflife") &&
+		cfg.Routing.McDecayHalfLife <= 0 {
+
+		return nil, mkErr("routing.mc-decay-halflife must be "+
+			"positive, got %v", cfg.Routing.McDecayHalfLife)
+	}
+
+	if cfg.Routing.ProbeInterval != 0 {
+		if cfg.Routing.ProbeInterval < routing.MinProbeInterval {
+			return nil, mkErr("routing.probe-interval must be at "+
+				"least %v, got %v", routing.MinProbeInterval,
+				cfg.Routing.ProbeInterval)
+		}
+
+		if cfg.Routing.ProbeAmount == 0 {
+			return nil, mkErr("routing.probe-amt must be positive")
+		}
+	}
+
 	// Validate the Tor config parameters.
 	socks, err := lncfg.ParseAddressString(
 		cfg.Tor.SOCKS, strconv.Itoa(defaultTorSOCKSPort),
@@ -1223,10 +1795,21 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 		cfg.ActiveNetParams.Params = &chainParams
 	}
+	if cfg.ChainOptions.ParamsFile != "" {
+		numNets++
+
+		paramsFile := CleanAndExpandPath(cfg.ChainOptions.ParamsFile)
+		netParams, err := chainreg.LoadNetParamsFile(paramsFile)
+		if err != nil {
+			return nil, mkErr("unable to load chain params file: "+
+				"%v", err)
+		}
+		cfg.ActiveNetParams = netParams
+	}
 	if numNets > 1 {
-		str := "The mainnet, testnet, regtest, simnet and signet " +
-			"params can't be used together -- choose one " +
-			"of the five"
+		str := "The mainnet, testnet, regtest, simnet, signet and " +
+			"custom chain params can't be used together -- " +
+			"choose one of the six"
 
 		return nil, mkErr(str)
 	}
@@ -1235,8 +1818,8 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	// know how to initialize the daemon.
 	if numNets == 0 {
 		str := "either --bitcoin.mainnet, or bitcoin.testnet," +
-			"bitcoin.simnet, bitcoin.regtest or bitcoin.signet " +
-			"must be specified"
+			"bitcoin.simnet, bitcoin.regtest, bitcoin.signet " +
+			"or chain.params-file must be specified"
 
 		return nil, mkErr(str)
 	}
@@ -1369,29 +1952,6 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
-	towerDir := filepath.Join(
-		cfg.Watchtower.TowerDir, BitcoinChainName,
-		lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
-	)
-
-	// Create the lnd directory and all other sub-directories if they don't
-	// already exist. This makes sure that directory trees are also created
-	// for files that point to outside the lnddir.
-	dirs := []string{
-		lndDir, cfg.DataDir, cfg.networkDir,
-		cfg.LetsEncryptDir, towerDir, cfg.graphDatabaseDir(),
-		filepath.Dir(cfg.TLSCertPath), filepath.Dir(cfg.TLSKeyPath),
-		filepath.Dir(cfg.AdminMacPath), filepath.Dir(cfg.ReadMacPath),
-		filepath.Dir(cfg.InvoiceMacPath),
-		filepath.Dir(cfg.Tor.PrivateKeyPath),
-		filepath.Dir(cfg.Tor.WatchtowerKeyPath),
-	}
-	for _, dir := range dirs {
-		if err := makeDirectory(dir); err != nil {
-			return nil, err
-		}
-	}
-
 	// Similarly, if a custom back up file path wasn't specified, then
 	// we'll update the file location to match our set network directory.
 	if cfg.BackupFilePath == "" {
@@ -1400,6 +1960,18 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		)
 	}
 
+	if err := checkPreimageStore(cfg.PreimageStore, cfg.DB); err != nil {
+		return nil, mkErr("%v", err)
+	}
+
+	// The separate preimage database is stored next to the channel
+	// database unless a custom location was specified.
+	if cfg.PreimageStorePath == "" {
+		cfg.PreimageStorePath = filepath.Join(
+			cfg.graphDatabaseDir(), defaultPreimageStoreFilename,
+		)
+	}
+
 	// Append the network type to the log directory so it is "namespaced"
 	// per network in the same fashion as the data directory.
 	cfg.LogDir = filepath.Join(
@@ -1413,31 +1985,6 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("log writer missing in config")
 	}
 
-	// Special show command to list supported subsystems and exit.
-	if cfg.DebugLevel == "show" {
-		fmt.Println("Supported subsystems",
-			cfg.LogWriter.SupportedSubsystems())
-		os.Exit(0)
-	}
-
-	// Initialize logging at the default logging level.
-	SetupLoggers(cfg.LogWriter, interceptor)
-	err = cfg.LogWriter.InitLogRotator(
-		filepath.Join(cfg.LogDir, defaultLogFilename),
-		cfg.MaxLogFileSize, cfg.MaxLogFiles,
-	)
-	if err != nil {
-		str := "log rotation setup failed: %v"
-		return nil, mkErr(str, err)
-	}
-
-	// Parse, validate, and set debug log level(s).
-	err = build.ParseAndSetDebugLevels(cfg.DebugLevel, cfg.LogWriter)
-	if err != nil {
-		str := "error parsing debug level: %v"
-		return nil, &usageError{mkErr(str, err)}
-	}
-
 	// At least one RPCListener is required. So listen on localhost per
 	// default.
 	if len(cfg.RawRPCListeners) == 0 {
@@ -1465,9 +2012,10 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	}
 
 	// Add default port to all RPC listener addresses if needed and remove
-	// duplicate addresses.
-	cfg.RPCListeners, err = lncfg.NormalizeAddresses(
-		cfg.RawRPCListeners, strconv.Itoa(defaultRPCPort),
+	// duplicate addresses. Each listener can carry its own TLS certificate,
+	// but TLS can't be disabled for gRPC connections.
+	cfg.RPCListeners, cfg.RPCListenerTLS, err = parseListeners(
+		cfg.RawRPCListeners, defaultRPCPort, false,
 		cfg.net.ResolveTCPAddr,
 	)
 	if err != nil {
@@ -1476,8 +2024,8 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 
 	// Add default port to all REST listener addresses if needed and remove
 	// duplicate addresses.
-	cfg.RESTListeners, err = lncfg.NormalizeAddresses(
-		cfg.RawRESTListeners, strconv.Itoa(defaultRESTPort),
+	cfg.RESTListeners, cfg.RESTListenerTLS, err = parseListeners(
+		cfg.RawRESTListeners, defaultRESTPort, true,
 		cfg.net.ResolveTCPAddr,
 	)
 	if err != nil {
@@ -1519,12 +2067,22 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		ltndLog.Infof("REST API is disabled!")
 		cfg.RESTListeners = nil
 	} else {
-		err = lncfg.EnforceSafeAuthentication(
-			cfg.RESTListeners, !cfg.NoMacaroons, !cfg.DisableRestTLS,
-		)
-		if err != nil {
-			return nil, mkErr("error enforcing safe "+
-				"authentication on REST ports: %v", err)
+		// TLS can be enabled or disabled for each REST listener
+		// individually, so we check them one by one.
+		for _, addr := range cfg.RESTListeners {
+			tlsActive := !cfg.DisableRestTLS
+			listenerTLS := cfg.RESTListenerTLS[addr.String()]
+			if listenerTLS != nil {
+				tlsActive = !listenerTLS.DisableTLS
+			}
+
+			err = lncfg.EnforceSafeAuthentication(
+				[]net.Addr{addr}, !cfg.NoMacaroons, tlsActive,
+			)
+			if err != nil {
+				return nil, mkErr("error enforcing safe "+
+					"authentication on REST ports: %v", err)
+			}
 		}
 	}
 
@@ -1568,12 +2126,150 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		}
 	}
 
+	// Parse the types of addresses we advertise in our node announcement.
+	// A Tor-only node must never reveal its clearnet addresses, so unless
+	// the user explicitly asks for them, only onion addresses are
+	// advertised in that case.
+	for _, rawType := range cfg.RawAnnounceTypes {
+		addrType, err := netann.ParseAddrType(rawType)
+		if err != nil {
+			return nil, mkErr("invalid announce-addr-type: %v", err)
+		}
+		cfg.AnnounceTypes = append(cfg.AnnounceTypes, addrType)
+	}
+	torOnly := cfg.Tor.Active && !cfg.Tor.SkipProxyForClearNetTargets
+	if torOnly && len(cfg.AnnounceTypes) == 0 {
+		cfg.AnnounceTypes = []netann.AddrType{
+			netann.AddrTypeTorV2, netann.AddrTypeTorV3,
+		}
+	}
+	for _, addrType := range cfg.AnnounceTypes {
+		if torOnly && addrType.IsClearnet() {
+			ltndLog.Warnf("Advertising %v addresses while running "+
+				"in Tor-only mode reveals the network "+
+				"location of this node", addrType)
+		}
+	}
+
+	// Parse the peer reconnection lists. A peer can't be denied and
+	// prioritized at the same time.
+	cfg.PeerDenylist, err = parsePeerList(cfg.RawPeerDenylist)
+	if err != nil {
+		return nil, mkErr("invalid peer-denylist: %v", err)
+	}
+	cfg.PeerAllowlist, err = parsePeerList(cfg.RawPeerAllowlist)
+	if err != nil {
+		return nil, mkErr("invalid peer-allowlist: %v", err)
+	}
+	for peer := range cfg.PeerDenylist {
+		if _, ok := cfg.PeerAllowlist[peer]; ok {
+			return nil, mkErr("peer %v can't be in both "+
+				"peer-denylist and peer-allowlist", peer)
+		}
+	}
+
+	// Parse the networks inbound peer connections are accepted from.
+	cfg.InboundNets, err = parseInboundNets(
+		cfg.RawInboundNets, cfg.InboundAllowTor,
+	)
+	if err != nil {
+		return nil, mkErr("invalid peer-inbound-allowed-nets: %v", err)
+	}
+	onionService := cfg.Tor.Active && (cfg.Tor.V2 || cfg.Tor.V3)
+	if len(cfg.InboundNets) > 0 && onionService &&
+		!cfg.InboundAllowTor {
+
+		ltndLog.Warnf("Inbound connections through the Tor onion " +
+			"service are rejected unless peer-inbound-allow-tor " +
+			"is set or the loopback interface is part of " +
+			"peer-inbound-allowed-nets")
+	}
+
 	// Ensure that the specified minimum backoff is below or equal to the
 	// maximum backoff.
 	if cfg.MinBackoff > cfg.MaxBackoff {
 		return nil, mkErr("maxbackoff must be greater than minbackoff")
 	}
 
+	if cfg.MaxPendingChannelsGlobal < 0 {
+		return nil, mkErr("max-pending-channels-global must be " +
+			"non-negative")
+	}
+	if cfg.MaxPendingChannelsGlobal != 0 &&
+		cfg.MaxPendingChannelsGlobal < cfg.MaxPendingChannels {
+
+		return nil, mkErr("max-pending-channels-global must not be " +
+			"lower than maxpendingchannels")
+	}
+
+	cfg.MaxPendingChannelsOverrides, err = parseMaxPendingChannelsOverrides(
+		cfg.RawMaxPendingChannelsOverrides,
+	)
+	if err != nil {
+		return nil, mkErr("invalid maxpendingchannels-override: %v", err)
+	}
+	for peer, maxPending := range cfg.MaxPendingChannelsOverrides {
+		if cfg.MaxPendingChannelsGlobal != 0 &&
+			cfg.MaxPendingChannelsGlobal < maxPending {
+
+			return nil, mkErr("max-pending-channels-global must "+
+				"not be lower than maxpendingchannels-override "+
+				"of peer %v", peer)
+		}
+	}
+
+	if cfg.MaxChannelsPerPeer < 0 {
+		return nil, mkErr("max-channels-per-peer must be non-negative")
+	}
+
+	if cfg.PeerAddrCacheSize < 0 {
+		return nil, mkErr("peer-address-cache-size must be " +
+			"non-negative")
+	}
+
+	if cfg.CoopCloseAddr != "" {
+		if cfg.CoopCloseConsolidate {
+			return nil, mkErr("coop-close-addr and " +
+				"coop-close-consolidate are mutually exclusive")
+		}
+
+		_, err := chancloser.ParseUpfrontShutdownAddress(
+			cfg.CoopCloseAddr, cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, mkErr("invalid coop-close-addr: %v", err)
+		}
+	}
+
+	// The TLS certificate and key can also be supplied directly, e.g.
+	// from a secrets manager through environment variables, in which case
+	// they are never written to disk.
+	cfg.TLSCertBlob = decodePEMBlob(cfg.TLSCertBlob)
+	cfg.TLSKeyBlob = decodePEMBlob(cfg.TLSKeyBlob)
+	certPathSet, err := isSet("TLSCertPath")
+	if err != nil {
+		return nil, mkErr("error parsing tlscertpath flag: %v", err)
+	}
+	keyPathSet, err := isSet("TLSKeyPath")
+	if err != nil {
+		return nil, mkErr("error parsing tlskeypath flag: %v", err)
+	}
+	err = checkTLSBlobs(
+		cfg.TLSCertBlob, cfg.TLSKeyBlob, certPathSet, keyPathSet,
+		cfg.TLSEncryptKey,
+	)
+	if err != nil {
+		return nil, mkErr("%v", err)
+	}
+
+	// We can't rewrite a certificate we don't own, so it's never
+	// refreshed.
+	if cfg.TLSCertBlob != "" && cfg.TLSAutoRefresh {
+		ltndLog.Warnf("Ignoring tlsautorefresh, as the TLS " +
+			"certificate is supplied by tlscertblob")
+		cfg.TLSAutoRefresh = false
+	}
+
 	// Newer versions of lnd added a new sub-config for bolt-specific
 	// parameters. However, we want to also allow existing users to use the
 	// value on the top-level config. If the outer config value is set,
@@ -1652,10 +2348,29 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			maxPendingCommitInterval)
 	}
 
+	if cfg.PaymentsAutoPrune < 0 {
+		return nil, mkErr("payments-auto-prune must be non-negative")
+	}
+
 	if err := cfg.Gossip.Parse(); err != nil {
 		return nil, mkErr("error parsing gossip syncer: %v", err)
 	}
 
+	if cfg.Gossip.MaxFilterWindow < 0 {
+		return nil, mkErr("gossip.max-filter-window must not be "+
+			"negative, got %v", cfg.Gossip.MaxFilterWindow)
+	}
+
+	if cfg.Gossip.PolicyUpdateBatchSize <= 0 {
+		return nil, mkErr("gossip.policy-update-batch-size must be "+
+			"positive, got %v", cfg.Gossip.PolicyUpdateBatchSize)
+	}
+
+	if cfg.Gossip.PolicyUpdateInterval <= 0 {
+		return nil, mkErr("gossip.policy-update-interval must be "+
+			"positive, got %v", cfg.Gossip.PolicyUpdateInterval)
+	}
+
 	// Log a warning if our expiry delta is not greater than our incoming
 	// broadcast delta. We do not fail here because this value may be set
 	// to zero to intentionally keep lnd's behavior unchanged from when we
@@ -1678,6 +2393,18 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("custom-message: %v", err)
 	}
 
+	// Conflicts of the custom feature bits with bits set by lnd itself are
+	// detected by the feature manager once the server starts, but the
+	// other custom feature options can already be checked here.
+	err = lncfg.ValidateCustomFeatureBits(
+		cfg.ProtocolOptions.CustomFeatureBits,
+		cfg.ProtocolOptions.CustomInit,
+		cfg.ProtocolOptions.CustomNodeAnn,
+	)
+	if err != nil {
+		return nil, mkErr("protocol.custom-feature-bits: %v", err)
+	}
+
 	// Validate the subconfigs for workers, caches, and the tower client.
 	err = lncfg.Validate(
 		cfg.Workers,
@@ -1690,11 +2417,58 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
 		cfg.Htlcswitch,
+		cfg.Funding,
+		cfg.OnionMessages,
+		cfg.Backup,
+		cfg.FeeAutopilot,
+		cfg.DLP,
+		cfg.Recovery,
+		cfg.Invoices,
+		cfg.Wallet,
+		cfg.ChainOptions,
+		cfg.NeutrinoMode,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Like the custom message overrides, the label template is global
+	// state that is set once during startup before any transaction is
+	// labelled.
+	if err := labels.SetTemplate(cfg.Wallet.LabelTemplate); err != nil {
+		return nil, mkErr("wallet.label-template: %v", err)
+	}
+
+	// A negative request timeout would silently disable the etcd request
+	// timeouts and make the leader election check below meaningless.
+	if cfg.DB.Backend == lncfg.EtcdBackend &&
+		cfg.DB.Etcd.RequestTimeout < 0 {
+
+		return nil, mkErr("db.etcd.request_timeout must not be " +
+			"negative")
+	}
+
+	if err := cfg.Cluster.ValidateDBTimeouts(cfg.DB); err != nil {
+		return nil, mkErr("cluster: %v", err)
+	}
+
+	// Make sure a fixed invoice fallback address is valid for the active
+	// network.
+	if cfg.Invoices.FallbackAddrMode == lncfg.FallbackAddrModeFixed {
+		addr, err := btcutil.DecodeAddress(
+			cfg.Invoices.FallbackAddr, cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, mkErr("invalid invoices.fallback-address: "+
+				"%v", err)
+		}
+
+		if !addr.IsForNet(cfg.ActiveNetParams.Params) {
+			return nil, mkErr("invoices.fallback-address is not "+
+				"for %s", cfg.ActiveNetParams.Name)
+		}
+	}
+
 	// Finally, ensure that the user's color is correctly formatted,
 	// otherwise the server will not be able to start after the unlocking
 	// the wallet.
@@ -1703,10 +2477,317 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		return nil, mkErr("unable to parse node color: %v", err)
 	}
 
+	// Likewise, make sure the extra TLV records of the node announcement
+	// can be encoded.
+	nodeAnnRecords, err := lncfg.ParseTLVRecords(cfg.NodeAnnTLVRecords)
+	if err != nil {
+		return nil, mkErr("invalid node-ann-tlv: %v", err)
+	}
+	_, err = netann.EncodeNodeAnnExtraRecords(nodeAnnRecords)
+	if err != nil {
+		return nil, mkErr("invalid node-ann-tlv: %v", err)
+	}
+
 	// All good, return the sanitized result.
 	return &cfg, nil
 }
 
+// setupLogging registers the loggers of all subsystems with the log writer of
+// the config and sets their debug levels. Nothing is written to disk yet.
+func (c *Config) setupLogging(interceptor signal.Interceptor) error {
+	SetupLoggers(c.LogWriter, interceptor)
+
+	// Special show command to list supported subsystems and exit.
+	if c.DebugLevel == "show" {
+		fmt.Println("Supported subsystems",
+			c.LogWriter.SupportedSubsystems())
+		os.Exit(0)
+	}
+
+	// Parse, validate, and set debug log level(s).
+	err := build.ParseAndSetDebugLevels(c.DebugLevel, c.LogWriter)
+	if err != nil {
+		return &usageError{fmt.Errorf("error parsing debug level: %w",
+			err)}
+	}
+
+	return nil
+}
+
+// createDirsAndLogFile creates the lnd directory and all other directories
+// used by the config if they don't already exist, and starts writing the logs
+// to the rotated log file.
+func (c *Config) createDirsAndLogFile() error {
+	towerDir := filepath.Join(
+		c.Watchtower.TowerDir, BitcoinChainName,
+		lncfg.NormalizeNetwork(c.ActiveNetParams.Name),
+	)
+
+	// Create the lnd directory and all other sub-directories if they don't
+	// already exist. This makes sure that directory trees are also created
+	// for files that point to outside the lnddir.
+	dirs := []string{
+		CleanAndExpandPath(c.LndDir), c.DataDir, c.networkDir,
+		c.LetsEncryptDir, towerDir, c.graphDatabaseDir(),
+		filepath.Dir(c.TLSCertPath), filepath.Dir(c.TLSKeyPath),
+		filepath.Dir(c.AdminMacPath), filepath.Dir(c.ReadMacPath),
+		filepath.Dir(c.InvoiceMacPath),
+		filepath.Dir(c.Tor.PrivateKeyPath),
+		filepath.Dir(c.Tor.WatchtowerKeyPath),
+	}
+	for _, dir := range dirs {
+		err := os.MkdirAll(dir, 0700)
+		if err == nil {
+			continue
+		}
+
+		// Show a nicer error message if it's because a symlink is
+		// linked to a directory that does not exist (probably because
+		// it's not mounted).
+		if e, ok := err.(*os.PathError); ok && os.IsExist(err) {
+			link, lerr := os.Readlink(e.Path)
+			if lerr == nil {
+				str := "is symlink %s -> %s mounted?"
+				err = fmt.Errorf(str, e.Path, link)
+			}
+		}
+
+		return fmt.Errorf("failed to create lnd directory '%s': %w",
+			dir, err)
+	}
+
+	err := c.LogWriter.InitLogRotator(
+		filepath.Join(c.LogDir, defaultLogFilename),
+		c.MaxLogFileSize, c.MaxLogFiles,
+	)
+	if err != nil {
+		return fmt.Errorf("log rotation setup failed: %w", err)
+	}
+
+	return nil
+}
+
+// parsePeerList parses a list of hex encoded peer public keys into a set.
+func parsePeerList(rawPubKeys []string) (map[route.Vertex]struct{}, error) {
+	peers := make(map[route.Vertex]struct{}, len(rawPubKeys))
+	for _, rawPubKey := range rawPubKeys {
+		pubKeyBytes, err := hex.DecodeString(rawPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+
+		peers[route.NewVertex(pubKey)] = struct{}{}
+	}
+
+	return peers, nil
+}
+
+// parseMaxPendingChannelsOverrides parses a list of per peer overrides of the
+// maximum number of pending channels in the format <pubkey>:<n>.
+func parseMaxPendingChannelsOverrides(
+	rawOverrides []string) (map[route.Vertex]int, error) {
+
+	overrides := make(map[route.Vertex]int, len(rawOverrides))
+	for _, rawOverride := range rawOverrides {
+		rawPubKey, rawMaxPending, ok := strings.Cut(rawOverride, ":")
+		if !ok {
+			return nil, fmt.Errorf("override %q must be in the "+
+				"format <pubkey>:<n>", rawOverride)
+		}
+
+		pubKeyBytes, err := hex.DecodeString(rawPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+
+		maxPending, err := strconv.Atoi(rawMaxPending)
+		if err != nil || maxPending < 0 {
+			return nil, fmt.Errorf("invalid number of pending "+
+				"channels %q for peer %v, must be a "+
+				"non-negative integer", rawMaxPending, rawPubKey)
+		}
+
+		peer := route.NewVertex(pubKey)
+		if _, ok := overrides[peer]; ok {
+			return nil, fmt.Errorf("duplicate override for peer %v",
+				peer)
+		}
+		overrides[peer] = maxPending
+	}
+
+	return overrides, nil
+}
+
+// parseMinChanSizeOverrides parses a list of per peer overrides of the minimum
+// channel size in the format <pubkey>:<sat>. An override can't be lower than
+// the dust limit, as the funding output of a smaller channel wouldn't be
+// relayed.
+func parseMinChanSizeOverrides(
+	rawOverrides []string) (map[route.Vertex]btcutil.Amount, error) {
+
+	dustLimit := lnwallet.DustLimitUnknownWitness()
+
+	overrides := make(map[route.Vertex]btcutil.Amount, len(rawOverrides))
+	for _, rawOverride := range rawOverrides {
+		rawPubKey, rawMinChanSize, ok := strings.Cut(rawOverride, ":")
+		if !ok {
+			return nil, fmt.Errorf("override %q must be in the "+
+				"format <pubkey>:<sat>", rawOverride)
+		}
+
+		pubKeyBytes, err := hex.DecodeString(rawPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w",
+				rawPubKey, err)
+		}
+
+		minChanSize, err := strconv.ParseInt(rawMinChanSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min channel size %q "+
+				"for peer %v: %w", rawMinChanSize, rawPubKey,
+				err)
+		}
+		if btcutil.Amount(minChanSize) < dustLimit {
+			return nil, fmt.Errorf("min channel size %v for peer "+
+				"%v is below the dust limit of %v sat",
+				minChanSize, rawPubKey, int64(dustLimit))
+		}
+
+		peer := route.NewVertex(pubKey)
+		if _, ok := overrides[peer]; ok {
+			return nil, fmt.Errorf("duplicate override for peer %v",
+				peer)
+		}
+		overrides[peer] = btcutil.Amount(minChanSize)
+	}
+
+	return overrides, nil
+}
+
+// parseMinChanSizeNetOverrides parses a list of overrides of the minimum
+// channel size for the peers connecting from a network, in the format
+// <cidr>:<sat>. Like the per peer overrides, they can't be lower than the dust
+// limit.
+func parseMinChanSizeNetOverrides(
+	rawOverrides []string) ([]funding.NetMinChanSize, error) {
+
+	dustLimit := lnwallet.DustLimitUnknownWitness()
+
+	overrides := make([]funding.NetMinChanSize, 0, len(rawOverrides))
+	for _, rawOverride := range rawOverrides {
+		// An IPv6 network contains colons itself, so we split at the
+		// last one.
+		sep := strings.LastIndex(rawOverride, ":")
+		if sep == -1 {
+			return nil, fmt.Errorf("override %q must be in the "+
+				"format <cidr>:<sat>", rawOverride)
+		}
+		rawNet, rawMinChanSize := rawOverride[:sep], rawOverride[sep+1:]
+
+		_, ipNet, err := net.ParseCIDR(rawNet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w",
+				rawNet, err)
+		}
+
+		// Peers connecting through our onion service come from the
+		// loopback interface, so it can't be told apart from Tor.
+		if ipNet.IP.IsLoopback() {
+			return nil, fmt.Errorf("network %v is a loopback "+
+				"network, which connections through Tor come "+
+				"from", rawNet)
+		}
+
+		minChanSize, err := strconv.ParseInt(rawMinChanSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min channel size %q "+
+				"for network %v: %w", rawMinChanSize, rawNet,
+				err)
+		}
+		if btcutil.Amount(minChanSize) < dustLimit {
+			return nil, fmt.Errorf("min channel size %v for "+
+				"network %v is below the dust limit of %v sat",
+				minChanSize, rawNet, int64(dustLimit))
+		}
+
+		for _, override := range overrides {
+			if override.Net.String() == ipNet.String() {
+				return nil, fmt.Errorf("duplicate override "+
+					"for network %v", ipNet)
+			}
+		}
+
+		overrides = append(overrides, funding.NetMinChanSize{
+			Net:         ipNet,
+			MinChanSize: btcutil.Amount(minChanSize),
+		})
+	}
+
+	return overrides, nil
+}
+
+// parseInboundNets parses a list of networks in CIDR notation. If allowTor is
+// set and any networks are given, the loopback networks are added as well, as
+// that's where connections through our Tor onion service originate from.
+func parseInboundNets(rawNets []string, allowTor bool) ([]*net.IPNet,
+	error) {
+
+	if len(rawNets) == 0 {
+		return nil, nil
+	}
+
+	// Copy the list, so we don't modify the config when adding the
+	// loopback networks.
+	allRawNets := append([]string(nil), rawNets...)
+	if allowTor {
+		allRawNets = append(allRawNets, "127.0.0.0/8", "::1/128")
+	}
+
+	nets := make([]*net.IPNet, 0, len(allRawNets))
+	for _, rawNet := range allRawNets {
+		_, ipNet, err := net.ParseCIDR(rawNet)
+		if err != nil {
+			return nil, err
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+// maxFeeRate returns the configured fee rate ceiling for all transactions lnd
+// creates, or zero if no ceiling is set.
+func (c *Config) maxFeeRate() chainfee.SatPerKWeight {
+	return chainfee.SatPerKVByte(c.MaxFeeRate * 1000).FeePerKWeight()
+}
+
+// minRelayFeeRate returns the configured min relay fee rate override in
+// sat/kw. A zero value means no override is set.
+func (c *Config) minRelayFeeRate() chainfee.SatPerKWeight {
+	return chainfee.SatPerKVByte(
+		c.MinRelayFeeRateOverride * 1000,
+	).FeePerKWeight()
+}
+
 // graphDatabaseDir returns the default directory where the local bolt graph db
 // files are stored.
 func (c *Config) graphDatabaseDir() string {
@@ -1778,6 +2859,146 @@ func CleanAndExpandPath(path string) string {
 	return filepath.Clean(os.ExpandEnv(path))
 }
 
+// decodePEMBlob turns the escaped line breaks of a PEM block supplied as a
+// single line, e.g. in a config file, into actual line breaks. The base64
+// body of a PEM block never contains a backslash, so this doesn't alter
+// anything else.
+func decodePEMBlob(blob string) string {
+	return strings.ReplaceAll(blob, `\n`, "\n")
+}
+
+// checkTLSBlobs makes sure the TLS certificate and key are either both
+// supplied directly or both read from files, and that a key supplied directly
+// isn't supposed to be encrypted, as that would require rewriting it.
+func checkTLSBlobs(certBlob, keyBlob string, certPathSet, keyPathSet,
+	encryptKey bool) error {
+
+	switch {
+	case certBlob == "" && keyBlob == "":
+		return nil
+
+	case certBlob == "" || keyBlob == "":
+		return errors.New("tlscertblob and tlskeyblob must be set " +
+			"together")
+
+	case certPathSet:
+		return errors.New("tlscertpath and tlscertblob can't both " +
+			"be set")
+
+	case keyPathSet:
+		return errors.New("tlskeypath and tlskeyblob can't both be " +
+			"set")
+
+	case encryptKey:
+		return errors.New("tlsencryptkey can't be used with " +
+			"tlskeyblob, as the key isn't written to disk")
+	}
+
+	return nil
+}
+
+// checkPreimageStore makes sure that preimages are only stored in a separate
+// database if the channel state is stored locally as well. The separate
+// database is a local bolt file, so with a remote database backend the
+// preimages wouldn't be replicated along with the channel state and would be
+// lost on a cluster failover.
+func checkPreimageStore(preimageStore string, db *lncfg.DB) error {
+	if preimageStore != preimageStoreSeparate {
+		return nil
+	}
+
+	switch db.Backend {
+	case lncfg.EtcdBackend, lncfg.PostgresBackend:
+		return fmt.Errorf("preimage-store=%v can't be used with the "+
+			"remote %v database backend, as the separate preimage "+
+			"database isn't replicated", preimageStoreSeparate,
+			db.Backend)
+	}
+
+	return nil
+}
+
+// parseListeners normalizes the addresses of the given raw RPC or REST
+// listener entries and parses the TLS options the entries carry. The TLS
+// options are keyed by the normalized address of their listener. Listeners
+// without TLS options use the global TLS certificate. A listener with TLS
+// options can't be specified more than once, as it would be ambiguous which
+// options apply.
+func parseListeners(rawListeners []string, defaultPort int, allowNoTLS bool,
+	tcpResolver lncfg.TCPResolver) ([]net.Addr,
+	map[string]*lncfg.ListenerTLS, error) {
+
+	var (
+		rawAddrs    = make([]string, 0, len(rawListeners))
+		listenerTLS = make(map[string]*lncfg.ListenerTLS)
+
+		// hasOptions tells for each listener seen so far whether it
+		// carried TLS options.
+		hasOptions = make(map[string]bool, len(rawListeners))
+	)
+	for _, rawListener := range rawListeners {
+		rawAddr, tlsOpts, err := lncfg.ParseListenerTLS(rawListener)
+		if err != nil {
+			return nil, nil, err
+		}
+		rawAddrs = append(rawAddrs, rawAddr)
+
+		addr, err := lncfg.ParseAddressString(
+			rawAddr, strconv.Itoa(defaultPort), tcpResolver,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse address %s failed: "+
+				"%w", rawAddr, err)
+		}
+
+		// Listeners without TLS options are still deduplicated like
+		// before, but TLS options must not be silently dropped or
+		// applied to another entry of the same listener.
+		options, seen := hasOptions[addr.String()]
+		if seen && (options || tlsOpts != nil) {
+			return nil, nil, fmt.Errorf("duplicate listener %v "+
+				"with TLS options", addr)
+		}
+		hasOptions[addr.String()] = tlsOpts != nil
+
+		if tlsOpts == nil {
+			continue
+		}
+
+		switch {
+		case tlsOpts.DisableTLS && !allowNoTLS:
+			return nil, nil, fmt.Errorf("TLS cannot be disabled "+
+				"for listener %v", addr)
+
+		case !tlsOpts.DisableTLS:
+			tlsOpts.CertPath = CleanAndExpandPath(tlsOpts.CertPath)
+			tlsOpts.KeyPath = CleanAndExpandPath(tlsOpts.KeyPath)
+
+			if !lnrpc.FileExists(tlsOpts.CertPath) {
+				return nil, nil, fmt.Errorf("TLS certificate "+
+					"%s of listener %v does not exist",
+					tlsOpts.CertPath, addr)
+			}
+			if !lnrpc.FileExists(tlsOpts.KeyPath) {
+				return nil, nil, fmt.Errorf("TLS key %s of "+
+					"listener %v does not exist",
+					tlsOpts.KeyPath, addr)
+			}
+		}
+
+		listenerTLS[addr.String()] = tlsOpts
+	}
+
+	addrs, err := lncfg.NormalizeAddresses(
+		rawAddrs, strconv.Itoa(defaultPort), tcpResolver,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return addrs, listenerTLS, nil
+}
+
 func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 	netParams chainreg.BitcoinNetParams) error {
 
@@ -1787,11 +3008,6 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 	var daemonName, confDir, confFile, confFileBase string
 	switch conf := nodeConfig.(type) {
 	case *lncfg.Btcd:
-		// Resolves environment variable references in RPCUser and
-		// RPCPass fields.
-		conf.RPCUser = supplyEnvValue(conf.RPCUser)
-		conf.RPCPass = supplyEnvValue(conf.RPCPass)
-
 		// If both RPCUser and RPCPass are set, we assume those
 		// credentials are good to use.
 		if conf.RPCUser != "" && conf.RPCPass != "" {
@@ -1831,16 +3047,32 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 			}
 		}
 
+		// The fallback node is remote in general, so its credentials
+		// can't be extracted from a local config file and must be
+		// complete on their own.
+		if err := parseBitcoindFallbackParams(conf); err != nil {
+			return err
+		}
+
 		// Set the daemon name for displaying proper errors.
 		daemonName = bitcoindBackendName
 		confDir = conf.Dir
 		confFile = conf.ConfigPath
 		confFileBase = BitcoinChainName
 
-		// Resolves environment variable references in RPCUser
-		// and RPCPass fields.
-		conf.RPCUser = supplyEnvValue(conf.RPCUser)
-		conf.RPCPass = supplyEnvValue(conf.RPCPass)
+		// The cookie can only be refreshed if we know where to find
+		// it. If no credentials are given, the cookie may still be
+		// found automatically below.
+		if conf.RPCCookieRefresh < 0 {
+			return fmt.Errorf("%v.rpccookie-refresh-interval must "+
+				"not be negative", daemonName)
+		}
+		if conf.RPCCookieRefresh > 0 && conf.RPCCookie == "" &&
+			(conf.RPCUser != "" || conf.RPCPass != "") {
+
+			return fmt.Errorf("%[1]v.rpccookie-refresh-interval "+
+				"requires %[1]v.rpccookie to be set", daemonName)
+		}
 
 		// Check that cookie and credentials don't contradict each
 		// other.
@@ -1854,19 +3086,13 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 
 		// We convert the cookie into a user name and password.
 		if conf.RPCCookie != "" {
-			cookie, err := os.ReadFile(conf.RPCCookie)
+			user, pass, err := lncfg.ReadBitcoindCookie(
+				conf.RPCCookie,
+			)
 			if err != nil {
-				return fmt.Errorf("cannot read cookie file: %w",
-					err)
-			}
-
-			splitCookie := strings.Split(string(cookie), ":")
-			if len(splitCookie) != 2 {
-				return fmt.Errorf("cookie file has a wrong " +
-					"format")
+				return err
 			}
-			conf.RPCUser = splitCookie[0]
-			conf.RPCPass = splitCookie[1]
+			conf.RPCUser, conf.RPCPass = user, pass
 		}
 
 		if conf.RPCUser != "" && conf.RPCPass != "" {
@@ -1922,7 +3148,7 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 
 	case bitcoindBackendName:
 		nConf := nodeConfig.(*lncfg.Bitcoind)
-		rpcUser, rpcPass, zmqBlockHost, zmqTxHost, err :=
+		rpcUser, rpcPass, zmqBlockHost, zmqTxHost, cookiePath, err :=
 			extractBitcoindRPCParams(netParams.Params.Name,
 				nConf.Dir, confFile, nConf.RPCCookie)
 		if err != nil {
@@ -1931,12 +3157,98 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 		}
 		nConf.RPCUser, nConf.RPCPass = rpcUser, rpcPass
 		nConf.ZMQPubRawBlock, nConf.ZMQPubRawTx = zmqBlockHost, zmqTxHost
+
+		// We remember where the cookie was found, so it can be
+		// refreshed just like a configured one.
+		if nConf.RPCCookieRefresh > 0 && cookiePath == "" {
+			return fmt.Errorf("%v.rpccookie-refresh-interval "+
+				"requires a cookie, but the RPC credentials "+
+				"were found in %v", daemonName, confFile)
+		}
+		nConf.RPCCookie = cookiePath
 	}
 
 	fmt.Printf("Automatically obtained %v's RPC credentials\n", daemonName)
 	return nil
 }
 
+// parseBitcoindFallbackParams validates the connection parameters of the
+// fallback bitcoind node, if one is configured, and resolves its credentials.
+func parseBitcoindFallbackParams(conf *lncfg.Bitcoind) error {
+	fallback := conf.Fallback
+	if !fallback.Enabled() {
+		if fallback != nil && (fallback.RPCUser != "" ||
+			fallback.RPCPass != "" || fallback.RPCCookie != "") {
+
+			return errors.New("bitcoind.fallback.rpchost must be " +
+				"set to use a fallback bitcoind node")
+		}
+
+		return nil
+	}
+
+	// The ZMQ notifications are streamed from a single node and can't be
+	// moved over to another node, so we need to poll for them instead.
+	if !conf.RPCPolling {
+		return errors.New("bitcoind.fallback.rpchost requires " +
+			"bitcoind.rpcpolling to be enabled")
+	}
+
+	if fallback.RPCHost == conf.RPCHost {
+		return errors.New("bitcoind.fallback.rpchost must differ " +
+			"from bitcoind.rpchost")
+	}
+
+	if _, _, err := net.SplitHostPort(fallback.RPCHost); err != nil {
+		return fmt.Errorf("bitcoind.fallback.rpchost must include "+
+			"the port: %w", err)
+	}
+
+	if fallback.RPCCookie != "" {
+		if fallback.RPCUser != "" || fallback.RPCPass != "" {
+			return errors.New("please only provide either " +
+				"bitcoind.fallback.rpccookie or " +
+				"bitcoind.fallback.rpcuser and " +
+				"bitcoind.fallback.rpcpass")
+		}
+
+		user, pass, err := lncfg.ReadBitcoindCookie(fallback.RPCCookie)
+		if err != nil {
+			return err
+		}
+		fallback.RPCUser, fallback.RPCPass = user, pass
+	}
+
+	if fallback.RPCUser == "" || fallback.RPCPass == "" {
+		return errors.New("please set bitcoind.fallback.rpcuser and " +
+			"bitcoind.fallback.rpcpass (or " +
+			"bitcoind.fallback.rpccookie)")
+	}
+
+	return nil
+}
+
+var (
+	// reEnvVar matches the $ENV_VAR format.
+	reEnvVar = regexp.MustCompile(`^\$([a-zA-Z_][a-zA-Z0-9_]*)$`)
+
+	// reEnvVarWithBrackets matches the ${ENV_VAR} format.
+	reEnvVarWithBrackets = regexp.MustCompile(
+		`^\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}$`,
+	)
+
+	// reEnvVarWithDefault matches the ${ENV_VAR:-DEFAULT} format.
+	reEnvVarWithDefault = regexp.MustCompile(
+		`^\$\{([a-zA-Z_][a-zA-Z0-9_]*):-([\S]+)\}$`,
+	)
+
+	// reEnvVarRef matches the $ENV_VAR and ${ENV_VAR} formats, which
+	// don't provide a default value.
+	reEnvVarRef = regexp.MustCompile(
+		`^\$(?:([a-zA-Z_][a-zA-Z0-9_]*)|\{([a-zA-Z_][a-zA-Z0-9_]*)\})$`,
+	)
+)
+
 // supplyEnvValue supplies the value of an environment variable from a string.
 // It supports the following formats:
 // 1) $ENV_VAR
@@ -1958,19 +3270,6 @@ func parseRPCParams(cConfig *lncfg.Chain, nodeConfig interface{},
 // value if provided, or the original input string if no matching variable is
 // found or set.
 func supplyEnvValue(value string) string {
-	// Regex for $ENV_VAR format.
-	var reEnvVar = regexp.MustCompile(`^\$([a-zA-Z_][a-zA-Z0-9_]*)$`)
-
-	// Regex for ${ENV_VAR} format.
-	var reEnvVarWithBrackets = regexp.MustCompile(
-		`^\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}$`,
-	)
-
-	// Regex for ${ENV_VAR:-DEFAULT} format.
-	var reEnvVarWithDefault = regexp.MustCompile(
-		`^\$\{([a-zA-Z_][a-zA-Z0-9_]*):-([\S]+)\}$`,
-	)
-
 	// Match against supported formats.
 	switch {
 	case reEnvVarWithDefault.MatchString(value):
@@ -2001,6 +3300,87 @@ func supplyEnvValue(value string) string {
 	return value
 }
 
+// expandEnvValue replaces the given config value with the value of the
+// environment variable it references. Values that aren't a valid reference
+// and references without a default value to a variable that isn't set are
+// left untouched, so a literal value such as a password starting with a $
+// keeps working.
+func expandEnvValue(value string) string {
+	matches := reEnvVarRef.FindStringSubmatch(value)
+	if matches != nil {
+		envVariable := matches[1] + matches[2]
+		if _, ok := os.LookupEnv(envVariable); !ok {
+			return value
+		}
+	}
+
+	return supplyEnvValue(value)
+}
+
+// expandEnvValues runs expandEnvValue over all string and string slice config
+// values of the given config struct, so any of them can reference an
+// environment variable.
+func expandEnvValues(cfg *Config) {
+	// expand is the helper function that goes into nested structs
+	// recursively. Because we call it recursively, we need to declare it
+	// before we define it.
+	var expand func(reflect.Value)
+	expand = func(obj reflect.Value) {
+		// Turn struct pointers into the actual struct, so we can
+		// iterate over the fields as we would with a struct value.
+		if obj.Kind() == reflect.Ptr {
+			obj = obj.Elem()
+		}
+
+		// Abort on nil values.
+		if !obj.IsValid() || obj.Kind() != reflect.Struct {
+			return
+		}
+
+		for i := 0; i < obj.NumField(); i++ {
+			field := obj.Field(i)
+			fieldType := obj.Type().Field(i)
+
+			if !field.CanSet() {
+				continue
+			}
+
+			switch {
+			// We have a long name defined, this is a config value.
+			case fieldType.Tag.Get("long") != "":
+				expandEnvField(field)
+
+			// Nested structs are either namespaced, just used to
+			// group config values or embedded.
+			case fieldType.Tag.Get("namespace") != "",
+				fieldType.Tag.Get("group") != "",
+				fieldType.Anonymous:
+
+				expand(field)
+			}
+		}
+	}
+
+	expand(reflect.ValueOf(cfg))
+}
+
+// expandEnvField replaces environment variable references in the given string
+// or string slice config value.
+func expandEnvField(field reflect.Value) {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(expandEnvValue(field.String()))
+
+	case field.Kind() == reflect.Slice &&
+		field.Type().Elem().Kind() == reflect.String:
+
+		for i := 0; i < field.Len(); i++ {
+			elem := field.Index(i)
+			elem.SetString(expandEnvValue(elem.String()))
+		}
+	}
+}
+
 // extractBtcdRPCParams attempts to extract the RPC credentials for an existing
 // btcd instance. The passed path is expected to be the location of btcd's
 // application data directory on the target system.
@@ -2051,15 +3431,16 @@ func extractBtcdRPCParams(btcdConfigPath string) (string, string, error) {
 // extractBitcoindRPCParams attempts to extract the RPC credentials for an
 // existing bitcoind node instance. The routine looks for a cookie first,
 // optionally following the datadir configuration option in the bitcoin.conf. If
-// it doesn't find one, it looks for rpcuser/rpcpassword.
+// it doesn't find one, it looks for rpcuser/rpcpassword. The path of the cookie
+// is returned if the credentials were read from one.
 func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
-	rpcCookiePath string) (string, string, string, string, error) {
+	rpcCookiePath string) (string, string, string, string, string, error) {
 
 	// First, we'll open up the bitcoind configuration file found at the
 	// target destination.
 	bitcoindConfigFile, err := os.Open(bitcoindConfigPath)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	defer func() { _ = bitcoindConfigFile.Close() }()
 
@@ -2067,7 +3448,7 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	// we can attempt to locate the RPC credentials.
 	configContents, err := io.ReadAll(bitcoindConfigFile)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 
 	// First, we'll look for the ZMQ hosts providing raw block and raw
@@ -2076,26 +3457,26 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 		`(?m)^\s*zmqpubrawblock\s*=\s*([^\s]+)`,
 	)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	zmqBlockHostSubmatches := zmqBlockHostRE.FindSubmatch(configContents)
 	if len(zmqBlockHostSubmatches) < 2 {
-		return "", "", "", "", fmt.Errorf("unable to find " +
+		return "", "", "", "", "", fmt.Errorf("unable to find " +
 			"zmqpubrawblock in config")
 	}
 	zmqTxHostRE, err := regexp.Compile(`(?m)^\s*zmqpubrawtx\s*=\s*([^\s]+)`)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	zmqTxHostSubmatches := zmqTxHostRE.FindSubmatch(configContents)
 	if len(zmqTxHostSubmatches) < 2 {
-		return "", "", "", "", errors.New("unable to find zmqpubrawtx " +
-			"in config")
+		return "", "", "", "", "", errors.New("unable to find " +
+			"zmqpubrawtx in config")
 	}
 	zmqBlockHost := string(zmqBlockHostSubmatches[1])
 	zmqTxHost := string(zmqTxHostSubmatches[1])
 	if err := checkZMQOptions(zmqBlockHost, zmqTxHost); err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 
 	// Next, we'll try to find an auth cookie. We need to detect the chain
@@ -2106,7 +3487,7 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	}
 	dataDirRE, err := regexp.Compile(`(?m)^\s*datadir\s*=\s*([^\s]+)`)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	dataDirSubmatches := dataDirRE.FindSubmatch(configContents)
 	if dataDirSubmatches != nil {
@@ -2120,7 +3501,8 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	case "regtest", "testnet3", "signet":
 		chainDir = networkName
 	default:
-		return "", "", "", "", fmt.Errorf("unexpected networkname %v", networkName)
+		return "", "", "", "", "", fmt.Errorf("unexpected "+
+			"networkname %v", networkName)
 	}
 
 	cookiePath := filepath.Join(dataDir, chainDir, ".cookie")
@@ -2132,7 +3514,7 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 		splitCookie := strings.Split(string(cookie), ":")
 		if len(splitCookie) == 2 {
 			return splitCookie[0], splitCookie[1], zmqBlockHost,
-				zmqTxHost, nil
+				zmqTxHost, cookiePath, nil
 		}
 	}
 
@@ -2141,7 +3523,7 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	// expression then we'll exit with an error.
 	rpcUserRegexp, err := regexp.Compile(`(?m)^\s*rpcuser\s*=\s*([^\s]+)`)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	userSubmatches := rpcUserRegexp.FindSubmatch(configContents)
 
@@ -2150,24 +3532,24 @@ func extractBitcoindRPCParams(networkName, bitcoindDataDir, bitcoindConfigPath,
 	// error.
 	rpcPassRegexp, err := regexp.Compile(`(?m)^\s*rpcpassword\s*=\s*([^\s]+)`)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", "", err
 	}
 	passSubmatches := rpcPassRegexp.FindSubmatch(configContents)
 
 	// Exit with an error if the cookie file, is defined in config, and
 	// can not be found, with both rpcuser and rpcpassword undefined.
 	if rpcCookiePath != "" && userSubmatches == nil && passSubmatches == nil {
-		return "", "", "", "", fmt.Errorf("unable to open cookie file (%v)",
-			rpcCookiePath)
+		return "", "", "", "", "", fmt.Errorf("unable to open "+
+			"cookie file (%v)", rpcCookiePath)
 	}
 
 	if userSubmatches == nil {
-		return "", "", "", "", fmt.Errorf("unable to find rpcuser in " +
-			"config")
+		return "", "", "", "", "", fmt.Errorf("unable to find " +
+			"rpcuser in config")
 	}
 	if passSubmatches == nil {
-		return "", "", "", "", fmt.Errorf("unable to find rpcpassword " +
-			"in config")
+		return "", "", "", "", "", fmt.Errorf("unable to find " +
+			"rpcpassword in config")
 	}
 
 	return supplyEnvValue(string(userSubmatches[1])),
@@ -2186,6 +3568,19 @@ func checkZMQOptions(zmqBlockHost, zmqTxHost string) error {
 	return nil
 }
 
+// checkCoinSelectionStrategy ensures that the provided coin selection strategy
+// is known.
+func checkCoinSelectionStrategy(strategy string) error {
+	for _, known := range coinSelectionStrategies {
+		if strategy == known {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("coin selection strategy must be one of the "+
+		"following: %v", coinSelectionStrategies[:])
+}
+
 // checkEstimateMode ensures that the provided estimate mode is legal.
 func checkEstimateMode(estimateMode string) error {
 	for _, mode := range bitcoindEstimateModes {
@@ -2219,6 +3614,7 @@ func configToFlatMap(cfg Config) (map[string]string,
 			"pass",
 			"password",
 			"dsn",
+			"keyblob",
 		}
 		for _, suffix := range sensitiveKeySuffixes {
 			if strings.HasSuffix(key, suffix) {
@@ -2306,6 +3702,45 @@ func configToFlatMap(cfg Config) (map[string]string,
 	return result, deprecated, nil
 }
 
+// writeResolvedConfig writes the given config to the writer, one sorted
+// option per line in the format of the config file, followed by the deprecated
+// options that are set. Sensitive values are redacted.
+func writeResolvedConfig(w io.Writer, cfg Config) error {
+	flatMap, deprecated, err := configToFlatMap(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to convert config to map: %w", err)
+	}
+
+	keys := make([]string, 0, len(flatMap))
+	for key := range flatMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, err := fmt.Fprintf(w, "%s=%s\n", key, flatMap[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	deprecatedKeys := make([]string, 0, len(deprecated))
+	for key := range deprecated {
+		deprecatedKeys = append(deprecatedKeys, key)
+	}
+	sort.Strings(deprecatedKeys)
+
+	for _, key := range deprecatedKeys {
+		_, err := fmt.Fprintf(w, "; Config '%s' is deprecated, please "+
+			"remove it\n", key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // logWarningsForDeprecation logs a warning if a deprecated config option is
 // set.
 func logWarningsForDeprecation(cfg Config) {
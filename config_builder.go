@@ -15,6 +15,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
@@ -40,6 +41,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/rpcperms"
@@ -290,7 +292,15 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	// this information.
 	walletInitParams.Birthday = time.Now()
 
-	d.pwService.SetLoaderOpts([]btcwallet.LoaderOption{dbs.WalletDB})
+	// The wallet can be recovered on creation or unlock, so all loaders
+	// need to respect the configured recovery gap limit.
+	loaderOpts := []btcwallet.LoaderOption{
+		dbs.WalletDB, btcwallet.LoaderWithRecoveryGapLimit(
+			d.cfg.Wallet.RecoveryGapLimit,
+		),
+	}
+
+	d.pwService.SetLoaderOpts(loaderOpts)
 	d.pwService.SetMacaroonDB(dbs.MacaroonDB)
 	walletExists, err := d.pwService.WalletExists()
 	if err != nil {
@@ -371,7 +381,7 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		}
 
 		params, err := waitForWalletPassword(
-			d.cfg, d.pwService, []btcwallet.LoaderOption{dbs.WalletDB},
+			d.cfg, d.pwService, loaderOpts,
 			d.interceptor.ShutdownChannel(),
 		)
 		if err != nil {
@@ -391,6 +401,12 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 			}
 		})
 
+		// The wallet loaders already raised the recovery window to
+		// the gap limit, so we report the same look-ahead.
+		walletInitParams.RecoveryWindow = btcwallet.RecoveryWindow(
+			walletInitParams.RecoveryWindow,
+			d.cfg.Wallet.RecoveryGapLimit,
+		)
 		if walletInitParams.RecoveryWindow > 0 {
 			d.logger.Infof("Wallet recovery mode enabled with "+
 				"address lookahead of %d addresses",
@@ -539,9 +555,12 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	// When we create the chain control, we need storage for the height
 	// hints and also the wallet itself, for these two we want them to be
 	// replicated, so we'll pass in the remote channel DB instance.
+	chainOptions := d.cfg.ChainOptions
 	chainControlCfg := &chainreg.Config{
 		Bitcoin:                     d.cfg.Bitcoin,
 		HeightHintCacheQueryDisable: d.cfg.HeightHintCacheQueryDisable,
+		RescanConcurrency:           d.cfg.RescanConcurrency,
+		BlockDownloadConcurrency:    chainOptions.BlockDownloadConcurrency,
 		NeutrinoMode:                d.cfg.NeutrinoMode,
 		BitcoindMode:                d.cfg.BitcoindMode,
 		BtcdMode:                    d.cfg.BtcdMode,
@@ -554,6 +573,8 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 			URL:              d.cfg.Fee.URL,
 			MinUpdateTimeout: d.cfg.Fee.MinUpdateTimeout,
 			MaxUpdateTimeout: d.cfg.Fee.MaxUpdateTimeout,
+			CacheTTL:         d.cfg.Fee.CacheTTL,
+			URLSchema:        d.cfg.Fee.URLSchema,
 		},
 		Dialer: func(addr string) (net.Conn, error) {
 			return d.cfg.net.Dial(
@@ -562,6 +583,17 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 		},
 		BlockCache:         blockCache,
 		WalletUnlockParams: &walletInitParams,
+
+		MinRelayFeeRate: d.cfg.minRelayFeeRate(),
+		MaxFeeRate:      d.cfg.maxFeeRate(),
+	}
+
+	// We fail over to a fallback bitcoind node one health check before
+	// the chain backend would be considered dead, so that the last attempt
+	// checks the fallback node.
+	chainControlCfg.BackendFailoverAfter = 1
+	if attempts := d.cfg.HealthChecks.ChainCheck.Attempts; attempts > 1 {
+		chainControlCfg.BackendFailoverAfter = attempts - 1
 	}
 
 	// Let's go ahead and create the partial chain control now that is only
@@ -579,17 +611,20 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	}
 
 	walletConfig := &btcwallet.Config{
-		PrivatePass:      privateWalletPw,
-		PublicPass:       publicWalletPw,
-		Birthday:         walletInitParams.Birthday,
-		RecoveryWindow:   walletInitParams.RecoveryWindow,
-		NetParams:        d.cfg.ActiveNetParams.Params,
-		CoinType:         d.cfg.ActiveNetParams.CoinType,
-		Wallet:           walletInitParams.Wallet,
-		LoaderOptions:    []btcwallet.LoaderOption{dbs.WalletDB},
-		ChainSource:      partialChainControl.ChainSource,
-		WatchOnly:        d.watchOnly,
-		MigrateWatchOnly: d.migrateWatchOnly,
+		PrivatePass:                privateWalletPw,
+		PublicPass:                 publicWalletPw,
+		Birthday:                   walletInitParams.Birthday,
+		RecoveryWindow:             walletInitParams.RecoveryWindow,
+		NetParams:                  d.cfg.ActiveNetParams.Params,
+		CoinType:                   d.cfg.ActiveNetParams.CoinType,
+		Wallet:                     walletInitParams.Wallet,
+		LoaderOptions:              loaderOpts,
+		ChainSource:                partialChainControl.ChainSource,
+		WatchOnly:                  d.watchOnly,
+		MigrateWatchOnly:           d.migrateWatchOnly,
+		AnchorChanReservedValue:    btcutil.Amount(d.cfg.AnchorReserve),
+		MaxAnchorChanReservedValue: btcutil.Amount(d.cfg.MaxAnchorReserve),
+		NoAnchorChanReserve:        d.cfg.AnchorReserve == 0,
 	}
 
 	// Parse coin selection strategy.
@@ -600,11 +635,36 @@ func (d *DefaultWalletImpl) BuildWalletConfig(ctx context.Context,
 	case "random":
 		walletConfig.CoinSelectionStrategy = wallet.CoinSelectionRandom
 
+	case "bnb":
+		walletConfig.CoinSelectionStrategy =
+			chanfunding.CoinSelectionBranchAndBound
+
 	default:
 		return nil, nil, nil, fmt.Errorf("unknown coin selection "+
 			"strategy %v", d.cfg.CoinSelectionStrategy)
 	}
 
+	spendUnconfirmed, err := lnwallet.ParseSpendUnconfirmedPolicy(
+		d.cfg.Wallet.SpendUnconfirmed,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	walletConfig.SpendUnconfirmed = spendUnconfirmed
+
+	// A birthday hint bounds the initial rescan of a wallet recovery.
+	birthdayHeight, birthdayTime, err := d.cfg.Wallet.ParseBirthday()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if birthdayHeight != 0 || !birthdayTime.IsZero() {
+		walletConfig.BirthdayHint = &btcwallet.BirthdayHint{
+			Height: birthdayHeight,
+			Time:   birthdayTime,
+		}
+		walletConfig.Quit = d.interceptor.ShutdownChannel()
+	}
+
 	earlyExit = false
 	return partialChainControl, walletConfig, cleanUp, nil
 }
@@ -703,6 +763,7 @@ func (d *DefaultWalletImpl) BuildChainControl(
 		ChainIO:               walletController,
 		NetParams:             *walletConfig.NetParams,
 		CoinSelectionStrategy: walletConfig.CoinSelectionStrategy,
+		SpendUnconfirmed:      walletConfig.SpendUnconfirmed,
 	}
 
 	// The broadcast is already always active for neutrino nodes, so we
@@ -818,6 +879,7 @@ func (d *RPCSignerWalletImpl) BuildChainControl(
 		ChainIO:               walletController,
 		NetParams:             *walletConfig.NetParams,
 		CoinSelectionStrategy: walletConfig.CoinSelectionStrategy,
+		SpendUnconfirmed:      walletConfig.SpendUnconfirmed,
 	}
 
 	// We've created the wallet configuration now, so we can finish
@@ -882,6 +944,17 @@ type DatabaseInstances struct {
 	// for native SQL queries for tables that already support it. This may
 	// be nil if the use-native-sql flag was not set.
 	NativeSQLStore *sqldb.BaseDB
+
+	// PreimageDB is the separate database that stores the preimages
+	// learned when settling HTLCs. This is nil if preimages are stored in
+	// the channel state DB and no separate database exists.
+	PreimageDB kvdb.Backend
+
+	// PreimageDBFallback is true if preimages are stored in the channel
+	// state DB, but a separate preimage database from an earlier run
+	// exists. PreimageDB is then only used to look up the preimages that
+	// were stored there before.
+	PreimageDBFallback bool
 }
 
 // DefaultDatabaseBuilder is a type that builds the default database backends
@@ -921,11 +994,14 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 
 	startOpenTime := time.Now()
 
+	towerServerDBDir := filepath.Join(
+		cfg.Watchtower.TowerDir, BitcoinChainName,
+		lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
+	)
 	databaseBackends, err := cfg.DB.GetBackends(
-		ctx, cfg.graphDatabaseDir(), cfg.networkDir, filepath.Join(
-			cfg.Watchtower.TowerDir, BitcoinChainName,
-			lncfg.NormalizeNetwork(cfg.ActiveNetParams.Name),
-		), cfg.WtClient.Active, cfg.Watchtower.Active, d.logger,
+		ctx, cfg.graphDatabaseDir(), cfg.networkDir, towerServerDBDir,
+		cfg.WtClient.Active, cfg.Watchtower.Active,
+		cfg.HealthChecks.DiskCheck.CheckOperationSpace, d.logger,
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to obtain database "+
@@ -971,6 +1047,9 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		),
 		channeldb.OptionDryRunMigration(cfg.DryRunMigration),
 		channeldb.OptionSetUseGraphCache(!cfg.DB.NoGraphCache),
+		channeldb.OptionSetMaxCacheChannels(
+			cfg.Routing.MaxGraphChannels,
+		),
 		channeldb.OptionKeepFailedPaymentAttempts(
 			cfg.KeepFailedPaymentAttempts,
 		),
@@ -991,6 +1070,47 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		)
 	}
 
+	// Make sure there's enough disk space before migrating any of the
+	// bolt databases.
+	var towerClientOpts, towerServerOpts []wtdb.DBOption
+	if cfg.DB.Backend == lncfg.BoltBackend {
+		diskCheck := cfg.HealthChecks.DiskCheck
+		dbOptions = append(
+			dbOptions, channeldb.OptionPreMigrationCheck(
+				boltMigrationCheck(
+					diskCheck, cfg.graphDatabaseDir(),
+					lncfg.ChannelDBName,
+				),
+			),
+		)
+		towerClientOpts = append(
+			towerClientOpts, wtdb.WithPreMigrationCheck(
+				boltMigrationCheck(
+					diskCheck, cfg.graphDatabaseDir(),
+					lncfg.TowerClientDBName,
+				),
+			),
+		)
+		towerServerOpts = append(
+			towerServerOpts, wtdb.WithPreMigrationCheck(
+				boltMigrationCheck(
+					diskCheck, towerServerDBDir,
+					lncfg.TowerServerDBName,
+				),
+			),
+		)
+
+		// The wallet is migrated by btcwallet when it's unlocked, so
+		// we check beforehand if it will be.
+		err = checkWalletMigrationSpace(
+			diskCheck, cfg.networkDir, cfg.DB.Bolt,
+		)
+		if err != nil {
+			cleanUp()
+			return nil, nil, err
+		}
+	}
+
 	// Otherwise, we'll open two instances, one for the state we only need
 	// locally, and the other for things we want to ensure are replicated.
 	dbs.GraphDB, err = channeldb.CreateWithBackend(
@@ -1025,6 +1145,18 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// using the same struct (and DB backend) instance.
 	dbs.ChanStateDB = dbs.GraphDB
 
+	// Run the optional consistency check of the channel state before any
+	// subsystem starts to use it.
+	if d.cfg.DB.StartupIntegrityCheck {
+		err := d.checkChanStateIntegrity(dbs.ChanStateDB)
+		if err != nil {
+			cleanUp()
+			d.logger.Error(err)
+
+			return nil, nil, err
+		}
+	}
+
 	// Instantiate a native SQL invoice store if the flag is set.
 	if d.cfg.DB.UseNativeSQL {
 		// KV invoice db resides in the same database as the graph and
@@ -1072,7 +1204,7 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// Wrap the watchtower client DB and make sure we clean up.
 	if cfg.WtClient.Active {
 		dbs.TowerClientDB, err = wtdb.OpenClientDB(
-			databaseBackends.TowerClientDB,
+			databaseBackends.TowerClientDB, towerClientOpts...,
 		)
 		if err != nil {
 			cleanUp()
@@ -1087,7 +1219,7 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	// Wrap the watchtower server DB and make sure we clean up.
 	if cfg.Watchtower.Active {
 		dbs.TowerServerDB, err = wtdb.OpenTowerDB(
-			databaseBackends.TowerServerDB,
+			databaseBackends.TowerServerDB, towerServerOpts...,
 		)
 		if err != nil {
 			cleanUp()
@@ -1099,12 +1231,164 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 		}
 	}
 
+	// Open the separate preimage database and make sure we clean up. If
+	// we switched back to storing preimages in the channel database, we
+	// still open an existing separate database, so the preimages stored
+	// there remain available to resolve contracts.
+	separateStore := cfg.PreimageStore == preimageStoreSeparate
+	if !separateStore && lnrpc.FileExists(cfg.PreimageStorePath) {
+		d.logger.Warnf("Preimage database %v exists, still looking up "+
+			"preimages stored there", cfg.PreimageStorePath)
+
+		dbs.PreimageDBFallback = true
+	}
+	if separateStore || dbs.PreimageDBFallback {
+		bolt := cfg.DB.Bolt
+		preimageDBCfg := &kvdb.BoltBackendConfig{
+			DBPath:            filepath.Dir(cfg.PreimageStorePath),
+			DBFileName:        filepath.Base(cfg.PreimageStorePath),
+			DBTimeout:         bolt.DBTimeout,
+			NoFreelistSync:    bolt.NoFreelistSync,
+			AutoCompact:       bolt.AutoCompact,
+			AutoCompactMinAge: bolt.AutoCompactMinAge,
+		}
+		dbs.PreimageDB, err = kvdb.GetBoltBackend(preimageDBCfg)
+		if err != nil {
+			cleanUp()
+
+			err := fmt.Errorf("unable to open preimage database: "+
+				"%w", err)
+			d.logger.Error(err)
+			return nil, nil, err
+		}
+
+		closeDBs := cleanUp
+		cleanUp = func() {
+			closeDBs()
+
+			if err := dbs.PreimageDB.Close(); err != nil {
+				d.logger.Errorf("Error closing preimage "+
+					"database: %v", err)
+			}
+		}
+	}
+
 	openTime := time.Since(startOpenTime)
 	d.logger.Infof("Database(s) now open (time_to_open=%v)!", openTime)
 
 	return dbs, cleanUp, nil
 }
 
+// boltMigrationCheck returns a check that refuses to migrate the bolt database
+// file with the given name in the given directory if there isn't enough disk
+// space for it. Migrating a bolt database can write up to another copy of the
+// database file before the pages of the old data are freed.
+func boltMigrationCheck(diskCheck *lncfg.DiskCheckConfig, dir,
+	fileName string) func() error {
+
+	dbFile := filepath.Join(dir, fileName)
+
+	return func() error {
+		fi, err := os.Stat(dbFile)
+		if err != nil {
+			return err
+		}
+
+		return diskCheck.CheckOperationSpace(
+			"migrating "+dbFile, dir, uint64(fi.Size()),
+		)
+	}
+}
+
+// checkWalletMigrationSpace returns an error if btcwallet will migrate the
+// bolt wallet database in the given directory once the wallet is unlocked,
+// but there isn't enough disk space for it.
+func checkWalletMigrationSpace(diskCheck *lncfg.DiskCheckConfig, dir string,
+	boltCfg *kvdb.BoltConfig) error {
+
+	dbFile := filepath.Join(dir, lncfg.WalletDBName)
+	if diskCheck.OperationMultiplier == 0 || !lnrpc.FileExists(dbFile) {
+		return nil
+	}
+
+	// Nothing else has opened the wallet database yet, so we can briefly
+	// open it to look up the versions of the wallet.
+	db, err := kvdb.Open(
+		kvdb.BoltBackendName, dbFile, boltCfg.NoFreelistSync,
+		boltCfg.DBTimeout,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to open wallet database: %w", err)
+	}
+
+	needsMigration, err := btcwallet.WalletNeedsMigration(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to determine if the wallet database "+
+			"needs to be migrated: %w", err)
+	}
+
+	if !needsMigration {
+		return nil
+	}
+
+	err = boltMigrationCheck(diskCheck, dir, lncfg.WalletDBName)()
+	if err != nil {
+		return fmt.Errorf("refusing to migrate wallet database: %w",
+			err)
+	}
+
+	return nil
+}
+
+// checkChanStateIntegrity runs the consistency check of the channel state
+// database and logs all issues found. An error is returned if critical issues
+// were found and the strict mode is enabled.
+func (d *DefaultDatabaseBuilder) checkChanStateIntegrity(
+	chanStateDB *channeldb.DB) error {
+
+	dbCfg := d.cfg.DB
+
+	d.logger.Infof("Running channel database integrity check "+
+		"(timeout=%v)", dbCfg.StartupIntegrityCheckTimeout)
+
+	startTime := time.Now()
+	report, err := chanStateDB.ChannelStateDB().CheckIntegrity(
+		dbCfg.StartupIntegrityCheckTimeout,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to run channel database integrity "+
+			"check: %w", err)
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Critical {
+			d.logger.Errorf("Channel database integrity issue: %v",
+				issue)
+		} else {
+			d.logger.Warnf("Channel database integrity issue: %v",
+				issue)
+		}
+	}
+
+	numCritical := report.NumCritical()
+	d.logger.Infof("Channel database integrity check finished "+
+		"(complete=%v, time=%v): checked %d channels and %d "+
+		"revocation log entries, found %d critical issues and %d "+
+		"warnings", report.Complete, time.Since(startTime),
+		report.NumChannels, report.NumRevocationLogs, numCritical,
+		len(report.Issues)-numCritical)
+
+	if numCritical > 0 && dbCfg.StartupIntegrityCheckStrict {
+		return fmt.Errorf("channel database integrity check found %d "+
+			"critical issues, refusing to start", numCritical)
+	}
+
+	return nil
+}
+
 // waitForWalletPassword blocks until a password is provided by the user to
 // this RPC server.
 func waitForWalletPassword(cfg *Config,
@@ -1412,10 +1696,11 @@ func initNeutrinoBackend(ctx context.Context, cfg *Config, chainDir string,
 		BlockCache:         blockCache.Cache,
 		BroadcastTimeout:   cfg.NeutrinoMode.BroadcastTimeout,
 		PersistToDisk:      cfg.NeutrinoMode.PersistFilters,
+		FilterCacheSize:    cfg.NeutrinoMode.FilterCacheSize,
 	}
 
-	neutrino.MaxPeers = 8
-	neutrino.BanDuration = time.Hour * 48
+	neutrino.MaxPeers = lncfg.NeutrinoMaxPeers
+	neutrino.BanDuration = cfg.NeutrinoMode.BanDuration
 	neutrino.UserAgentName = cfg.NeutrinoMode.UserAgentName
 	neutrino.UserAgentVersion = cfg.NeutrinoMode.UserAgentVersion
 
@@ -1426,12 +1711,21 @@ func initNeutrinoBackend(ctx context.Context, cfg *Config, chainDir string,
 			"client: %v", err)
 	}
 
+	// Track how well the filter cache performs, so its size can be tuned
+	// for the rescans of the node.
+	chainreg.TrackFilterCache(neutrinoCS, cfg.NeutrinoMode.FilterCacheSize)
+
 	if err := neutrinoCS.Start(); err != nil {
 		db.Close()
 		return nil, nil, err
 	}
 
 	cleanUp := func() {
+		stats, err := chainreg.NeutrinoFilterCacheStats(neutrinoCS)
+		if err == nil {
+			ltndLog.Infof("Neutrino filter cache stats: %v", stats)
+		}
+
 		if err := neutrinoCS.Stop(); err != nil {
 			ltndLog.Infof("Unable to stop neutrino light client: "+
 				"%v", err)
@@ -1439,6 +1733,26 @@ func initNeutrinoBackend(ctx context.Context, cfg *Config, chainDir string,
 		db.Close()
 	}
 
+	// With too few peers, we might be fed unreliable data, so we give
+	// neutrino some time to connect to enough peers if requested.
+	if minPeers := cfg.NeutrinoMode.MinPeersAtStartup; minPeers > 0 {
+		err := chainreg.WaitForNeutrinoPeers(
+			ctx, neutrinoCS.ConnectedCount, minPeers,
+			cfg.NeutrinoMode.MinPeersTimeout,
+		)
+		switch {
+		case errors.Is(err, chainreg.ErrMinPeersTimeout):
+			ltndLog.Warnf("Neutrino connected to only %d of %d "+
+				"peers within %v, continuing startup",
+				neutrinoCS.ConnectedCount(), minPeers,
+				cfg.NeutrinoMode.MinPeersTimeout)
+
+		case err != nil:
+			cleanUp()
+			return nil, nil, err
+		}
+	}
+
 	return neutrinoCS, cleanUp, nil
 }
 
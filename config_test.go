@@ -1,11 +1,26 @@
 package lnd
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btclog"
+	"github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,6 +38,7 @@ func TestConfigToFlatMap(t *testing.T) {
 	cfg.Tor.Password = testPassword
 	cfg.DB.Etcd.Pass = testPassword
 	cfg.DB.Postgres.Dsn = testPassword
+	cfg.TLSKeyBlob = testPassword
 
 	// Set a deprecated field.
 	cfg.Bitcoin.Active = true
@@ -51,6 +67,38 @@ func TestConfigToFlatMap(t *testing.T) {
 	require.Equal(t, redactedPassword, result["tor.password"])
 	require.Equal(t, redactedPassword, result["db.etcd.pass"])
 	require.Equal(t, redactedPassword, result["db.postgres.dsn"])
+	require.Equal(t, redactedPassword, result["tlskeyblob"])
+}
+
+// TestWriteResolvedConfig tests that the resolved config is written sorted
+// with sensitive values redacted and the deprecated options reported.
+func TestWriteResolvedConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Alias = "my-node"
+	cfg.BitcoindMode.RPCPass = testPassword
+
+	// Set a deprecated field.
+	cfg.Bitcoin.Active = true
+
+	var buf bytes.Buffer
+	require.NoError(t, writeResolvedConfig(&buf, cfg))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Contains(t, lines, "alias=my-node")
+	require.Contains(t, lines, "bitcoind.rpcpass="+redactedPassword)
+	require.NotContains(t, buf.String(), testPassword)
+
+	// The options are sorted by key and followed by the deprecated ones.
+	keys := make([]string, 0, len(lines)-1)
+	for _, line := range lines[:len(lines)-1] {
+		key, _, _ := strings.Cut(line, "=")
+		keys = append(keys, key)
+	}
+	require.True(t, sort.StringsAreSorted(keys))
+	require.Equal(
+		t, "; Config 'bitcoin.active' is deprecated, please remove it",
+		lines[len(lines)-1],
+	)
 }
 
 // TestSupplyEnvValue tests that the supplyEnvValue function works as
@@ -115,3 +163,665 @@ func TestSupplyEnvValue(t *testing.T) {
 		})
 	}
 }
+
+// TestExpandEnvValues tests that environment variables are expanded in all
+// string and string slice config values, while sensitive values are still
+// redacted.
+func TestExpandEnvValues(t *testing.T) {
+	t.Setenv("LND_ALIAS", "my-node")
+	t.Setenv("LND_HOST", "node.example.com")
+	t.Setenv("LND_TOWER_DIR", "/data/tower")
+	t.Setenv("LND_RPC_PASS", testPassword)
+
+	cfg := DefaultConfig()
+	cfg.Alias = "${LND_ALIAS}"
+	cfg.ExternalHosts = []string{"$LND_HOST", "other.example.com"}
+	cfg.Watchtower.TowerDir = "${LND_TOWER_DIR:-/tower}"
+	cfg.BitcoindMode.RPCPass = "$LND_RPC_PASS"
+	cfg.Tor.Password = "pa$$word"
+	cfg.Color = "$"
+
+	expandEnvValues(&cfg)
+
+	require.Equal(t, "my-node", cfg.Alias)
+	require.Equal(
+		t, []string{"node.example.com", "other.example.com"},
+		cfg.ExternalHosts,
+	)
+	require.Equal(t, "/data/tower", cfg.Watchtower.TowerDir)
+	require.Equal(t, testPassword, cfg.BitcoindMode.RPCPass)
+
+	// A literal $ that isn't a valid variable reference is kept.
+	require.Equal(t, "pa$$word", cfg.Tor.Password)
+	require.Equal(t, "$", cfg.Color)
+
+	result, _, err := configToFlatMap(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "my-node", result["alias"])
+	require.Equal(t, redactedPassword, result["bitcoind.rpcpass"])
+
+	// A reference to a variable that isn't set is kept as it is, unless
+	// it has a default value. A variable that is set to an empty string is
+	// replaced.
+	t.Setenv("LND_EMPTY", "")
+
+	cfg = DefaultConfig()
+	cfg.Alias = "$LND_EMPTY"
+	cfg.Watchtower.TowerDir = "${LND_UNSET_DIR:-/tower}"
+	cfg.BitcoindMode.RPCPass = "${LND_UNSET_PASS}"
+	cfg.ExternalHosts = []string{"node.example.com", "$LND_UNSET_HOST"}
+	expandEnvValues(&cfg)
+
+	require.Empty(t, cfg.Alias)
+	require.Equal(t, "/tower", cfg.Watchtower.TowerDir)
+	require.Equal(t, "${LND_UNSET_PASS}", cfg.BitcoindMode.RPCPass)
+	require.Equal(
+		t, []string{"node.example.com", "$LND_UNSET_HOST"},
+		cfg.ExternalHosts,
+	)
+
+	// Expanding a value that was expanded already doesn't change it.
+	t.Setenv("LND_NESTED", "$LND_ALIAS")
+	cfg = DefaultConfig()
+	cfg.Alias = "$LND_NESTED"
+	expandEnvValues(&cfg)
+	require.Equal(t, "$LND_ALIAS", cfg.Alias)
+}
+
+// TestParsePeerList tests that peer lists only accept valid public keys.
+func TestParsePeerList(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+	pubKeyHex := hex.EncodeToString(pubKey.SerializeCompressed())
+
+	peers, err := parsePeerList(nil)
+	require.NoError(t, err)
+	require.Empty(t, peers)
+
+	_, err = parsePeerList([]string{"not-hex"})
+	require.Error(t, err)
+
+	_, err = parsePeerList([]string{"02abcd"})
+	require.Error(t, err)
+
+	// Duplicate entries are collapsed into a single peer.
+	peers, err = parsePeerList([]string{pubKeyHex, pubKeyHex})
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]struct{}{
+		route.NewVertex(pubKey): {},
+	}, peers)
+}
+
+// TestParseMaxPendingChannelsOverrides tests that the per peer overrides of the
+// maximum number of pending channels are parsed and validated.
+func TestParseMaxPendingChannelsOverrides(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+	pubKeyHex := hex.EncodeToString(pubKey.SerializeCompressed())
+
+	overrides, err := parseMaxPendingChannelsOverrides(nil)
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	overrides, err = parseMaxPendingChannelsOverrides(
+		[]string{pubKeyHex + ":5"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]int{
+		route.NewVertex(pubKey): 5,
+	}, overrides)
+
+	invalid := []string{
+		pubKeyHex,
+		pubKeyHex + ":",
+		pubKeyHex + ":-1",
+		pubKeyHex + ":many",
+		"not-hex:5",
+		"02abcd:5",
+	}
+	for _, rawOverride := range invalid {
+		_, err := parseMaxPendingChannelsOverrides(
+			[]string{rawOverride},
+		)
+		require.Error(t, err, rawOverride)
+	}
+
+	// A peer can only have a single override.
+	_, err = parseMaxPendingChannelsOverrides(
+		[]string{pubKeyHex + ":5", pubKeyHex + ":2"},
+	)
+	require.Error(t, err)
+}
+
+// TestParseMinChanSizeOverrides tests that the per peer overrides of the
+// minimum channel size are parsed and can't go below the dust limit.
+func TestParseMinChanSizeOverrides(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+	pubKeyHex := hex.EncodeToString(pubKey.SerializeCompressed())
+
+	overrides, err := parseMinChanSizeOverrides(nil)
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	// An override may be lower than the default minimum channel size.
+	dustLimit := lnwallet.DustLimitUnknownWitness()
+	overrides, err = parseMinChanSizeOverrides([]string{
+		fmt.Sprintf("%v:%d", pubKeyHex, dustLimit),
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]btcutil.Amount{
+		route.NewVertex(pubKey): dustLimit,
+	}, overrides)
+
+	invalid := []string{
+		pubKeyHex,
+		pubKeyHex + ":",
+		pubKeyHex + ":-1",
+		pubKeyHex + ":small",
+		fmt.Sprintf("%v:%d", pubKeyHex, dustLimit-1),
+		"not-hex:20000",
+		"02abcd:20000",
+	}
+	for _, rawOverride := range invalid {
+		_, err := parseMinChanSizeOverrides([]string{rawOverride})
+		require.Error(t, err, rawOverride)
+	}
+
+	// The offending peer is named in the error.
+	_, err = parseMinChanSizeOverrides([]string{pubKeyHex + ":1"})
+	require.ErrorContains(t, err, pubKeyHex)
+
+	// A peer can only have a single override.
+	_, err = parseMinChanSizeOverrides(
+		[]string{pubKeyHex + ":20000", pubKeyHex + ":50000"},
+	)
+	require.Error(t, err)
+}
+
+// TestParseMinChanSizeNetOverrides tests that the per network overrides of
+// the minimum channel size are parsed and can't go below the dust limit.
+func TestParseMinChanSizeNetOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := parseMinChanSizeNetOverrides(nil)
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	// Both IPv4 and IPv6 networks are accepted, and an override may be
+	// lower than the default minimum channel size.
+	dustLimit := lnwallet.DustLimitUnknownWitness()
+	overrides, err = parseMinChanSizeNetOverrides([]string{
+		fmt.Sprintf("10.8.0.1/24:%d", dustLimit),
+		"2001:db8::/32:50000",
+	})
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+	require.Equal(t, "10.8.0.0/24", overrides[0].Net.String())
+	require.Equal(t, dustLimit, overrides[0].MinChanSize)
+	require.Equal(t, "2001:db8::/32", overrides[1].Net.String())
+	require.EqualValues(t, 50000, overrides[1].MinChanSize)
+
+	invalid := []string{
+		"10.8.0.0/24",
+		"10.8.0.0/24:",
+		"10.8.0.0/24:small",
+		"10.8.0.1:20000",
+		"10.8.0.0/33:20000",
+		fmt.Sprintf("10.8.0.0/24:%d", dustLimit-1),
+		"127.0.0.0/8:20000",
+		"::1/128:20000",
+	}
+	for _, rawOverride := range invalid {
+		_, err := parseMinChanSizeNetOverrides([]string{rawOverride})
+		require.Error(t, err, rawOverride)
+	}
+
+	// The offending network is named in the error.
+	_, err = parseMinChanSizeNetOverrides([]string{"10.8.0.0/24:1"})
+	require.ErrorContains(t, err, "10.8.0.0/24")
+
+	// A network can only have a single override.
+	_, err = parseMinChanSizeNetOverrides(
+		[]string{"10.8.0.0/24:20000", "10.8.0.1/24:50000"},
+	)
+	require.Error(t, err)
+}
+
+// TestParseInboundNets tests that the networks inbound peer connections are
+// accepted from are parsed correctly.
+func TestParseInboundNets(t *testing.T) {
+	t.Parallel()
+
+	contains := func(nets []*net.IPNet, ip string) bool {
+		for _, ipNet := range nets {
+			if ipNet.Contains(net.ParseIP(ip)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// Without any networks, no filter is set up, even if Tor is allowed.
+	nets, err := parseInboundNets(nil, true)
+	require.NoError(t, err)
+	require.Empty(t, nets)
+
+	_, err = parseInboundNets([]string{"203.0.113.1"}, false)
+	require.Error(t, err)
+
+	_, err = parseInboundNets([]string{"203.0.113.0/33"}, false)
+	require.Error(t, err)
+
+	rawNets := []string{"203.0.113.0/24", "2001:db8::/32"}
+	nets, err = parseInboundNets(rawNets, false)
+	require.NoError(t, err)
+	require.Len(t, nets, 2)
+
+	require.True(t, contains(nets, "203.0.113.42"))
+	require.True(t, contains(nets, "::ffff:203.0.113.42"))
+	require.True(t, contains(nets, "2001:db8::1"))
+	require.False(t, contains(nets, "198.51.100.1"))
+	require.False(t, contains(nets, "127.0.0.1"))
+	require.False(t, contains(nets, "::1"))
+
+	// Allowing Tor adds the loopback networks.
+	nets, err = parseInboundNets(rawNets, true)
+	require.NoError(t, err)
+	require.True(t, contains(nets, "127.0.0.1"))
+	require.True(t, contains(nets, "::1"))
+	require.False(t, contains(nets, "198.51.100.1"))
+}
+
+// TestParseBitcoindFallbackParams tests that the connection parameters of a
+// fallback bitcoind node are validated.
+func TestParseBitcoindFallbackParams(t *testing.T) {
+	t.Parallel()
+
+	cookieFile := filepath.Join(t.TempDir(), ".cookie")
+	err := os.WriteFile(cookieFile, []byte("__cookie__:secret"), 0600)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		rpcPolling   bool
+		fallback     lncfg.BitcoindFallback
+		expectedUser string
+		expectedPass string
+		valid        bool
+	}{{
+		name:  "no fallback",
+		valid: true,
+	}, {
+		name: "credentials without host",
+		fallback: lncfg.BitcoindFallback{
+			RPCUser: "user",
+			RPCPass: "pass",
+		},
+	}, {
+		name: "zmq notifications",
+		fallback: lncfg.BitcoindFallback{
+			RPCHost: "10.0.0.2:8332",
+			RPCUser: "user",
+			RPCPass: "pass",
+		},
+	}, {
+		name:       "host without port",
+		rpcPolling: true,
+		fallback: lncfg.BitcoindFallback{
+			RPCHost: "10.0.0.2",
+			RPCUser: "user",
+			RPCPass: "pass",
+		},
+	}, {
+		name:       "missing password",
+		rpcPolling: true,
+		fallback: lncfg.BitcoindFallback{
+			RPCHost: "10.0.0.2:8332",
+			RPCUser: "user",
+		},
+	}, {
+		name:       "cookie and password",
+		rpcPolling: true,
+		fallback: lncfg.BitcoindFallback{
+			RPCHost:   "10.0.0.2:8332",
+			RPCUser:   "user",
+			RPCPass:   "pass",
+			RPCCookie: cookieFile,
+		},
+	}, {
+		name:       "user and password",
+		rpcPolling: true,
+		fallback: lncfg.BitcoindFallback{
+			RPCHost: "10.0.0.2:8332",
+			RPCUser: "user",
+			RPCPass: "pass",
+		},
+		expectedUser: "user",
+		expectedPass: "pass",
+		valid:        true,
+	}, {
+		name:       "cookie",
+		rpcPolling: true,
+		fallback: lncfg.BitcoindFallback{
+			RPCHost:   "10.0.0.2:8332",
+			RPCCookie: cookieFile,
+		},
+		expectedUser: "__cookie__",
+		expectedPass: "secret",
+		valid:        true,
+	}}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fallback := test.fallback
+			conf := &lncfg.Bitcoind{
+				RPCHost:    "localhost",
+				RPCPolling: test.rpcPolling,
+				Fallback:   &fallback,
+			}
+
+			err := parseBitcoindFallbackParams(conf)
+			if !test.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedUser, fallback.RPCUser)
+			require.Equal(t, test.expectedPass, fallback.RPCPass)
+		})
+	}
+}
+
+// TestExtractBitcoindRPCParamsCookiePath tests that the path of the cookie is
+// returned if the RPC credentials of bitcoind were read from one, so that the
+// cookie can be refreshed.
+func TestExtractBitcoindRPCParamsCookiePath(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	confFile := filepath.Join(dataDir, "bitcoin.conf")
+	conf := "zmqpubrawblock=tcp://127.0.0.1:28332\n" +
+		"zmqpubrawtx=tcp://127.0.0.1:28333\n" +
+		"rpcuser=user\nrpcpassword=pass\n"
+	require.NoError(t, os.WriteFile(confFile, []byte(conf), 0600))
+
+	// Without a cookie the credentials are read from the config file.
+	user, pass, _, _, cookiePath, err := extractBitcoindRPCParams(
+		"regtest", "", confFile, "",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "user", user)
+	require.Equal(t, "pass", pass)
+	require.Empty(t, cookiePath)
+
+	// The cookie found under the data dir takes precedence.
+	chainDir := filepath.Join(dataDir, "regtest")
+	require.NoError(t, os.MkdirAll(chainDir, 0700))
+	cookieFile := filepath.Join(chainDir, ".cookie")
+	err = os.WriteFile(cookieFile, []byte("__cookie__:secret"), 0600)
+	require.NoError(t, err)
+
+	user, pass, _, _, cookiePath, err = extractBitcoindRPCParams(
+		"regtest", "", confFile, "",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "__cookie__", user)
+	require.Equal(t, "secret", pass)
+	require.Equal(t, cookieFile, cookiePath)
+}
+
+// TestParseListeners tests that the TLS options of the RPC and REST listeners
+// are keyed by the normalized listener address and that the certificate files
+// of a listener must exist.
+func TestParseListeners(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	certPath := filepath.Join(tempDir, "tls.cert")
+	keyPath := filepath.Join(tempDir, "tls.key")
+	require.NoError(t, os.WriteFile(certPath, nil, 0600))
+	require.NoError(t, os.WriteFile(keyPath, nil, 0600))
+
+	tlsOpts := fmt.Sprintf(",tlscertpath=%s,tlskeypath=%s", certPath,
+		keyPath)
+
+	addrs, listenerTLS, err := parseListeners(
+		[]string{"localhost", "127.0.0.2" + tlsOpts, "127.0.0.3,notls"},
+		defaultRESTPort, true, net.ResolveTCPAddr,
+	)
+	require.NoError(t, err)
+	require.Len(t, addrs, 3)
+	require.Equal(t, map[string]*lncfg.ListenerTLS{
+		"127.0.0.2:8080": {CertPath: certPath, KeyPath: keyPath},
+		"127.0.0.3:8080": {DisableTLS: true},
+	}, listenerTLS)
+
+	// TLS can't be disabled for gRPC listeners.
+	_, _, err = parseListeners(
+		[]string{"127.0.0.3,notls"}, defaultRPCPort, false,
+		net.ResolveTCPAddr,
+	)
+	require.Error(t, err)
+
+	// The certificate of a listener must exist.
+	_, _, err = parseListeners(
+		[]string{"127.0.0.2,tlscertpath=missing,tlskeypath=" + keyPath},
+		defaultRPCPort, false, net.ResolveTCPAddr,
+	)
+	require.Error(t, err)
+
+	// Duplicate listeners without TLS options are merged, but a listener
+	// with TLS options can't be specified again.
+	addrs, _, err = parseListeners(
+		[]string{"localhost", "localhost:8080"}, defaultRESTPort,
+		true, net.ResolveTCPAddr,
+	)
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+
+	for _, rawListeners := range [][]string{
+		{"127.0.0.2" + tlsOpts, "127.0.0.2:8080,notls"},
+		{"127.0.0.2" + tlsOpts, "127.0.0.2"},
+		{"127.0.0.2", "127.0.0.2" + tlsOpts},
+	} {
+		_, _, err = parseListeners(
+			rawListeners, defaultRESTPort, true,
+			net.ResolveTCPAddr,
+		)
+		require.ErrorContains(t, err, "duplicate listener")
+	}
+}
+
+// TestCheckCoinSelectionStrategy tests that only the known coin selection
+// strategies are accepted.
+func TestCheckCoinSelectionStrategy(t *testing.T) {
+	for _, strategy := range []string{"largest", "random", "bnb"} {
+		require.NoError(t, checkCoinSelectionStrategy(strategy))
+	}
+
+	require.Error(t, checkCoinSelectionStrategy(""))
+	require.Error(t, checkCoinSelectionStrategy("smallest"))
+}
+
+// TestCheckPreimageStore tests that preimages can only be stored separately
+// with a local database backend.
+func TestCheckPreimageStore(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range []string{
+		lncfg.BoltBackend, lncfg.SqliteBackend, lncfg.EtcdBackend,
+		lncfg.PostgresBackend,
+	} {
+		db := &lncfg.DB{Backend: backend}
+		require.NoError(
+			t, checkPreimageStore(preimageStoreChannelDB, db),
+		)
+	}
+
+	for _, backend := range []string{
+		lncfg.BoltBackend, lncfg.SqliteBackend,
+	} {
+		db := &lncfg.DB{Backend: backend}
+		require.NoError(
+			t, checkPreimageStore(preimageStoreSeparate, db),
+		)
+	}
+
+	for _, backend := range []string{
+		lncfg.EtcdBackend, lncfg.PostgresBackend,
+	} {
+		db := &lncfg.DB{Backend: backend}
+		err := checkPreimageStore(preimageStoreSeparate, db)
+		require.ErrorContains(t, err, "isn't replicated")
+	}
+}
+
+// TestReloadDebugLevel tests that only the debuglevel option is re-read from
+// the config file and that invalid levels keep the previous log levels.
+func TestReloadDebugLevel(t *testing.T) {
+	t.Parallel()
+
+	logWriter := build.NewRotatingLogWriter()
+	logger := logWriter.GenSubLogger("TEST", func() {})
+	logWriter.RegisterSubLogger("TEST", logger)
+
+	cfg := &Config{
+		LogWriter:      logWriter,
+		configFilePath: filepath.Join(t.TempDir(), "lnd.conf"),
+	}
+	writeConfig := func(content string) {
+		err := os.WriteFile(cfg.configFilePath, []byte(content), 0600)
+		require.NoError(t, err)
+	}
+
+	// Other options and sections in the file are ignored.
+	writeConfig("[Application Options]\nalias=test\ndebuglevel=info," +
+		"TEST=trace\n\n[Bitcoin]\nbitcoin.mainnet=true\n")
+	debugLevel, err := cfg.reloadDebugLevel()
+	require.NoError(t, err)
+	require.Equal(t, "info,TEST=trace", debugLevel)
+	require.Equal(t, btclog.LevelTrace, logger.Level())
+
+	// An invalid level is rejected without changing any level.
+	writeConfig("[Application Options]\ndebuglevel=debug,TEST=loud\n")
+	_, err = cfg.reloadDebugLevel()
+	require.Error(t, err)
+	require.Equal(t, btclog.LevelTrace, logger.Level())
+
+	// Without a debuglevel, the levels are kept as well.
+	writeConfig("[Application Options]\nalias=test\n")
+	_, err = cfg.reloadDebugLevel()
+	require.Error(t, err)
+	require.Equal(t, btclog.LevelTrace, logger.Level())
+}
+
+// TestLoadConfigIncludes tests that included config files override the options
+// of the files including them, while options that can be specified multiple
+// times are combined, that relative paths are resolved against the including
+// file and that circular includes are rejected.
+func TestLoadConfigIncludes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0700))
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte(content), 0600)
+		require.NoError(t, err)
+
+		return path
+	}
+
+	mainPath := writeConfig("lnd.conf", "alias=main\n"+
+		"maxpendingchannels=2\ntlsextraip=10.0.0.1\n"+
+		"include=sub/a.conf\n")
+	aPath := writeConfig("sub/a.conf", "alias=a\ninclude=b.conf\n")
+	bPath := writeConfig("sub/b.conf", "maxpendingchannels=5\n"+
+		"tlsextraip=10.0.0.2\n")
+
+	loadConfig := func() (Config, []string, error) {
+		cfg := DefaultConfig()
+		parser := flags.NewParser(&cfg, flags.Default)
+		err := flags.NewIniParser(parser).ParseFile(mainPath)
+		require.NoError(t, err)
+
+		loaded, err := loadConfigIncludes(
+			parser, &cfg, dir, cfg.Includes, []string{mainPath},
+		)
+
+		return cfg, loaded, err
+	}
+
+	cfg, loaded, err := loadConfig()
+	require.NoError(t, err)
+	require.Equal(t, "a", cfg.Alias)
+	require.Equal(t, 5, cfg.MaxPendingChannels)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, cfg.TLSExtraIPs)
+	require.Equal(t, []string{aPath, bPath}, loaded)
+
+	// A file including one of the files that include it is rejected.
+	writeConfig("sub/b.conf", "include=../lnd.conf\n")
+	_, _, err = loadConfig()
+	require.ErrorContains(t, err, "circular config file include")
+
+	// A missing included file is an error, unlike a missing config file.
+	writeConfig("sub/b.conf", "include=missing.conf\n")
+	_, _, err = loadConfig()
+	require.ErrorContains(t, err, "unable to load included config file")
+}
+
+// TestCheckTLSBlobs tests that the TLS certificate and key can only be
+// supplied directly together and not in addition to their files.
+func TestCheckTLSBlobs(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkTLSBlobs("", "", true, true, true))
+	require.NoError(t, checkTLSBlobs("cert", "key", false, false, false))
+
+	require.ErrorContains(
+		t, checkTLSBlobs("cert", "", false, false, false),
+		"must be set together",
+	)
+	require.ErrorContains(
+		t, checkTLSBlobs("", "key", false, false, false),
+		"must be set together",
+	)
+	require.ErrorContains(
+		t, checkTLSBlobs("cert", "key", true, false, false),
+		"tlscertpath and tlscertblob",
+	)
+	require.ErrorContains(
+		t, checkTLSBlobs("cert", "key", false, true, false),
+		"tlskeypath and tlskeyblob",
+	)
+	require.ErrorContains(
+		t, checkTLSBlobs("cert", "key", false, false, true),
+		"tlsencryptkey",
+	)
+
+	// Escaped line breaks of a single line PEM block are decoded.
+	require.Equal(
+		t, "-----BEGIN CERTIFICATE-----\nMIIB\n"+
+			"-----END CERTIFICATE-----",
+		decodePEMBlob(`-----BEGIN CERTIFICATE-----\nMIIB\n`+
+			`-----END CERTIFICATE-----`),
+	)
+}
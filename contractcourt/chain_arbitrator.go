@@ -3,6 +3,7 @@ package contractcourt
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -217,6 +218,21 @@ type ChainArbitratorConfig struct {
 	// meanwhile, turn `PaymentCircuit` into an interface or bring it to a
 	// lower package.
 	QueryIncomingCircuit func(circuit models.CircuitKey) *models.CircuitKey
+
+	// CoopCloseFinalConfs is the number of confirmations a cooperative
+	// close transaction needs before the channel is considered closed.
+	CoopCloseFinalConfs uint32
+
+	// SafeReconnect, if set, halts all force closes and broadcasts as soon
+	// as any channel reports that our local state is outdated while the
+	// safe reconnect mode is active, as this likely means the whole
+	// channel state was restored from an old backup.
+	SafeReconnect *SafeReconnectGuard
+
+	// MaxConcurrentForceCloses is the maximum number of force closes that
+	// are in flight at the same time. Force closes beyond the limit are
+	// delayed unless their HTLCs are about to expire. Zero means no limit.
+	MaxConcurrentForceCloses uint32
 }
 
 // ChainArbitrator is a sub-system that oversees the on-chain resolution of all
@@ -250,6 +266,10 @@ type ChainArbitrator struct {
 	// active channels that it must still watch over.
 	chanSource *channeldb.DB
 
+	// forceCloseThrottle limits the number of concurrent force closes of
+	// all channel arbitrators.
+	forceCloseThrottle *ForceCloseThrottle
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -260,15 +280,28 @@ type ChainArbitrator struct {
 func NewChainArbitrator(cfg ChainArbitratorConfig,
 	db *channeldb.DB) *ChainArbitrator {
 
+	throttle := NewForceCloseThrottle(
+		cfg.MaxConcurrentForceCloses, DefaultMaxForceCloseDelay,
+	)
+
 	return &ChainArbitrator{
-		cfg:            cfg,
-		activeChannels: make(map[wire.OutPoint]*ChannelArbitrator),
-		activeWatchers: make(map[wire.OutPoint]*chainWatcher),
-		chanSource:     db,
-		quit:           make(chan struct{}),
+		cfg:                cfg,
+		activeChannels:     make(map[wire.OutPoint]*ChannelArbitrator),
+		activeWatchers:     make(map[wire.OutPoint]*chainWatcher),
+		chanSource:         db,
+		forceCloseThrottle: throttle,
+		quit:               make(chan struct{}),
 	}
 }
 
+// NotifyStaleLocalState is called when the channel reestablish message of a
+// peer shows that our local state of the given channel is outdated. If the
+// safe reconnect mode is active, all further force closes and broadcasts are
+// halted, as the state of the other channels is likely outdated as well.
+func (c *ChainArbitrator) NotifyStaleLocalState(chanPoint wire.OutPoint) {
+	c.cfg.SafeReconnect.NotifyStaleLocalState(chanPoint)
+}
+
 // arbChannel is a wrapper around an open channel that channel arbitrators
 // interact with.
 type arbChannel struct {
@@ -317,6 +350,14 @@ func (a *arbChannel) NewAnchorResolutions() (*lnwallet.AnchorResolutions,
 //
 // NOTE: Part of the ArbChannel interface.
 func (a *arbChannel) ForceCloseChan() (*lnwallet.LocalForceCloseSummary, error) {
+	// If we detected that we're running on an outdated channel state,
+	// broadcasting any of our commitments could mean broadcasting a
+	// revoked state, so we refuse to do so.
+	if a.c.cfg.SafeReconnect.Halted() {
+		return nil, fmt.Errorf("%w: safe reconnect mode halted all "+
+			"force closes", lnwallet.ErrForceCloseLocalDataLoss)
+	}
+
 	// First, we mark the channel as borked, this ensure
 	// that no new state transitions can happen, and also
 	// that the link won't be loaded into the switch.
@@ -406,6 +447,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 				channel.ShortChanID(), htlc,
 			)
 		},
+		ForceCloseThrottle: c.forceCloseThrottle,
 	}
 
 	// The final component needed is an arbitrator log that the arbitrator
@@ -557,6 +599,7 @@ func (c *ChainArbitrator) Start() error {
 				isOurAddr:           c.cfg.IsOurAddress,
 				contractBreach:      breachClosure,
 				extractStateNumHint: lnwallet.GetStateNumHint,
+				coopCloseFinalConfs: c.cfg.CoopCloseFinalConfs,
 			},
 		)
 		if err != nil {
@@ -1077,6 +1120,50 @@ func (c *ChainArbitrator) GetChannelArbitrator(chanPoint wire.OutPoint) (
 	return arbitrator, nil
 }
 
+// ChannelResolutionReport describes the progress of the on-chain resolution
+// of the outputs of a closed channel.
+type ChannelResolutionReport struct {
+	// ChanPoint is the outpoint of the closed channel.
+	ChanPoint wire.OutPoint
+
+	// Contracts holds a report for each output of the channel that is
+	// being resolved.
+	Contracts []*ContractReport
+}
+
+// ResolutionReports returns a report for every channel that has outputs that
+// are being resolved on chain, sorted by channel point.
+func (c *ChainArbitrator) ResolutionReports() []*ChannelResolutionReport {
+	c.Lock()
+	arbitrators := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arbitrator := range c.activeChannels {
+		arbitrators = append(arbitrators, arbitrator)
+	}
+	c.Unlock()
+
+	var reports []*ChannelResolutionReport
+	for _, arbitrator := range arbitrators {
+		// Only arbitrators with active resolvers have anything to
+		// report, all others aren't resolving any outputs.
+		contracts := arbitrator.Report()
+		if len(contracts) == 0 {
+			continue
+		}
+
+		reports = append(reports, &ChannelResolutionReport{
+			ChanPoint: arbitrator.cfg.ChanPoint,
+			Contracts: contracts,
+		})
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ChanPoint.String() <
+			reports[j].ChanPoint.String()
+	})
+
+	return reports
+}
+
 // forceCloseReq is a request sent from an outside sub-system to the arbitrator
 // that watches a particular channel to broadcast the commitment transaction,
 // and enter the resolution phase of the channel.
@@ -1186,6 +1273,7 @@ func (c *ChainArbitrator) WatchNewChannel(newChan *channeldb.OpenChannel) error
 				)
 			},
 			extractStateNumHint: lnwallet.GetStateNumHint,
+			coopCloseFinalConfs: c.cfg.CoopCloseFinalConfs,
 		},
 	)
 	if err != nil {
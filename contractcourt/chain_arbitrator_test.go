@@ -226,3 +226,95 @@ func TestResolveContract(t *testing.T) {
 	err = chainArb.ResolveContract(channel.FundingOutpoint)
 	require.NoError(t, err, "second resolve call shouldn't fail")
 }
+
+// mockSafeReconnectStore is an in-memory SafeReconnectStore.
+type mockSafeReconnectStore struct {
+	state channeldb.SafeReconnectState
+}
+
+func (m *mockSafeReconnectStore) FetchSafeReconnectState() (
+	*channeldb.SafeReconnectState, error) {
+
+	state := m.state
+	return &state, nil
+}
+
+func (m *mockSafeReconnectStore) HaltBroadcasts(chanPoint wire.OutPoint) error {
+	if m.state.HaltedBy == nil {
+		m.state.HaltedBy = &chanPoint
+	}
+
+	return nil
+}
+
+// TestNotifyStaleLocalState asserts that force closes and broadcasts are only
+// halted after a stale local state was reported if safe reconnect mode is
+// active, and that they stay halted after a restart.
+func TestNotifyStaleLocalState(t *testing.T) {
+	t.Parallel()
+
+	var chanPoint wire.OutPoint
+
+	published := 0
+	publish := func(*wire.MsgTx, string) error {
+		published++
+		return nil
+	}
+
+	// Without safe reconnect mode, reporting a stale state must not halt
+	// anything.
+	store := &mockSafeReconnectStore{}
+	guard, err := NewSafeReconnectGuard(false, store)
+	require.NoError(t, err)
+
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		SafeReconnect: guard,
+	}, nil)
+	chainArb.NotifyStaleLocalState(chanPoint)
+	require.False(t, guard.Halted())
+	require.Nil(t, store.state.HaltedBy)
+	require.NoError(t, guard.PublishTx(publish)(wire.NewMsgTx(2), ""))
+	require.Equal(t, 1, published)
+
+	// Once the mode is enabled by the config, a stale state halts
+	// everything.
+	guard, err = NewSafeReconnectGuard(true, store)
+	require.NoError(t, err)
+
+	chainArb = NewChainArbitrator(ChainArbitratorConfig{
+		SafeReconnect: guard,
+	}, nil)
+
+	arbChan := &arbChannel{
+		channel: &channeldb.OpenChannel{FundingOutpoint: chanPoint},
+		c:       chainArb,
+	}
+
+	// With safe reconnect mode, force closes and broadcasts are refused
+	// once a stale state was reported, before the channel is touched at
+	// all.
+	chainArb.NotifyStaleLocalState(chanPoint)
+	require.True(t, guard.Halted())
+	require.Equal(t, &chanPoint, store.state.HaltedBy)
+
+	_, err = arbChan.ForceCloseChan()
+	require.ErrorIs(t, err, lnwallet.ErrForceCloseLocalDataLoss)
+
+	err = guard.PublishTx(publish)(wire.NewMsgTx(2), "")
+	require.ErrorIs(t, err, ErrBroadcastHalted)
+	require.Equal(t, 1, published)
+
+	// Reporting another stale channel keeps broadcasts halted.
+	chainArb.NotifyStaleLocalState(wire.OutPoint{Index: 1})
+	require.True(t, guard.Halted())
+	require.Equal(t, &chanPoint, store.state.HaltedBy)
+
+	// Broadcasts stay halted after a restart, even if the mode isn't
+	// enabled by the config.
+	guard, err = NewSafeReconnectGuard(false, store)
+	require.NoError(t, err)
+	require.True(t, guard.Halted())
+
+	err = guard.PublishTx(publish)(wire.NewMsgTx(2), "")
+	require.ErrorIs(t, err, ErrBroadcastHalted)
+}
@@ -2,6 +2,7 @@ package contractcourt
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
@@ -23,6 +24,10 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+// errCoopCloseReorged is returned if a cooperative close tx was reorged out
+// of the chain before it reached the required number of confirmations.
+var errCoopCloseReorged = errors.New("coop close tx reorged out")
+
 const (
 	// minCommitPointPollTimeout is the minimum time we'll wait before
 	// polling the database for a channel's commitpoint.
@@ -190,6 +195,11 @@ type chainWatcherConfig struct {
 	// obfuscater. This is used by the chain watcher to identify which
 	// state was broadcast and confirmed on-chain.
 	extractStateNumHint func(*wire.MsgTx, [lnwallet.StateHintSize]byte) uint64
+
+	// coopCloseFinalConfs is the number of confirmations a cooperative
+	// close transaction needs before the channel is considered closed. A
+	// value of zero is treated as a single confirmation.
+	coopCloseFinalConfs uint32
 }
 
 // chainWatcher is a system that's assigned to every active channel. The duty
@@ -693,6 +703,16 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			// TODO(roasbeef): rare but possible, need itest case
 			// for
 			err := c.dispatchCooperativeClose(commitSpend)
+			if errors.Is(err, errCoopCloseReorged) {
+				// The funding output is unspent again, so
+				// we'll start over and wait for the next
+				// spend, which may be the same close tx once
+				// it's mined again or a different one.
+				spendNtfn.Cancel()
+				c.restartCloseObserver()
+
+				return
+			}
 			if err != nil {
 				log.Errorf("unable to handle co op close: %v", err)
 			}
@@ -1047,6 +1067,17 @@ func (c *chainWatcher) dispatchCooperativeClose(commitSpend *chainntnfs.SpendDet
 	log.Infof("Cooperative closure for ChannelPoint(%v): %v",
 		c.cfg.chanState.FundingOutpoint, spew.Sdump(broadcastTx))
 
+	// The spend was already detected with one confirmation. If more are
+	// required, we'll wait for them before marking the channel as closed,
+	// so that we don't forget about a channel whose close tx could still
+	// be reorged out.
+	if c.cfg.coopCloseFinalConfs > 1 {
+		err := c.waitForCoopCloseConfs(commitSpend)
+		if err != nil {
+			return err
+		}
+	}
+
 	// If the input *is* final, then we'll check to see which output is
 	// ours.
 	localAmt := c.toSelfAmount(broadcastTx)
@@ -1101,6 +1132,79 @@ func (c *chainWatcher) dispatchCooperativeClose(commitSpend *chainntnfs.SpendDet
 	return nil
 }
 
+// waitForCoopCloseConfs blocks until the given cooperative close transaction
+// reached the configured number of confirmations. If the transaction is
+// reorged out before, errCoopCloseReorged is returned.
+func (c *chainWatcher) waitForCoopCloseConfs(
+	commitSpend *chainntnfs.SpendDetail) error {
+
+	numConfs := c.cfg.coopCloseFinalConfs
+	chanPoint := c.cfg.chanState.FundingOutpoint
+
+	log.Infof("Waiting for cooperative close tx %v of ChannelPoint(%v) "+
+		"to reach %d confirmations", commitSpend.SpenderTxHash,
+		chanPoint, numConfs)
+
+	confNtfn, err := c.cfg.notifier.RegisterConfirmationsNtfn(
+		commitSpend.SpenderTxHash,
+		commitSpend.SpendingTx.TxOut[0].PkScript, numConfs,
+		uint32(commitSpend.SpendingHeight),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register for confirmations of "+
+			"coop close tx: %w", err)
+	}
+	defer confNtfn.Cancel()
+
+	select {
+	case _, ok := <-confNtfn.Confirmed:
+		// If the channel was closed, then this means that the notifier
+		// exited, so we will as well.
+		if !ok {
+			return fmt.Errorf("exiting")
+		}
+
+		return nil
+
+	// The close tx was reorged out before it reached the required depth.
+	// It might never confirm again if a conflicting tx is mined instead,
+	// so the caller needs to watch the funding output again.
+	case depth, ok := <-confNtfn.NegativeConf:
+		if !ok {
+			return fmt.Errorf("exiting")
+		}
+
+		log.Warnf("Cooperative close tx %v of ChannelPoint(%v) was "+
+			"reorged out with depth %d", commitSpend.SpenderTxHash,
+			chanPoint, depth)
+
+		return errCoopCloseReorged
+
+	case <-c.quit:
+		return fmt.Errorf("exiting")
+	}
+}
+
+// restartCloseObserver registers a new spend notification for the funding
+// output and dispatches a new close observer for it. This is used once the
+// spend we acted on was reorged out of the chain.
+func (c *chainWatcher) restartCloseObserver() {
+	spendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
+		&c.cfg.chanState.FundingOutpoint, c.fundingPkScript,
+		c.heightHint,
+	)
+	if err != nil {
+		log.Errorf("Unable to re-register spend notification for "+
+			"ChannelPoint(%v): %v", c.cfg.chanState.FundingOutpoint,
+			err)
+
+		return
+	}
+
+	c.wg.Add(1)
+	go c.closeObserver(spendNtfn)
+}
+
 // dispatchLocalForceClose processes a unilateral close by us being confirmed.
 func (c *chainWatcher) dispatchLocalForceClose(
 	commitSpend *chainntnfs.SpendDetail,
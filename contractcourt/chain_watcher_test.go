@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -599,3 +600,156 @@ func TestChainWatcherLocalForceCloseDetect(t *testing.T) {
 		})
 	}
 }
+
+// TestChainWatcherCoopCloseFinalConfs tests that the chain watcher only
+// dispatches a cooperative close once the close transaction reached the
+// configured number of confirmations.
+func TestChainWatcherCoopCloseFinalConfs(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		isOurAddr:           func(btcutil.Address) bool { return false },
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		coopCloseFinalConfs: 6,
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	err = aliceChainWatcher.Start()
+	require.NoError(t, err, "unable to start chain watcher")
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	// We'll now simulate a cooperative close transaction spending the
+	// funding output, which is identified by its final sequence number.
+	coopTx := wire.NewMsgTx(2)
+	coopTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: aliceChannel.State().FundingOutpoint,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coopTx.AddTxOut(&wire.TxOut{
+		Value:    1000,
+		PkScript: make([]byte, 22),
+	})
+	coopTxHash := coopTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash:  &coopTxHash,
+		SpendingTx:     coopTx,
+		SpendingHeight: 100,
+	}
+
+	// The close must not be dispatched before the close transaction is
+	// sufficiently confirmed.
+	select {
+	case <-chanEvents.CooperativeClosure:
+		t.Fatalf("coop close dispatched before final confirmation")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Once the transaction reached the required depth, we expect the
+	// cooperative close to be dispatched.
+	aliceNotifier.ConfChan <- &chainntnfs.TxConfirmation{}
+
+	select {
+	case closeInfo := <-chanEvents.CooperativeClosure:
+		require.Equal(t, coopTxHash, closeInfo.ClosingTXID)
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive coop close event")
+	}
+}
+
+// TestChainWatcherCoopCloseReorg tests that the chain watcher watches the
+// funding output again if the cooperative close transaction is reorged out
+// before it reached the configured number of confirmations.
+func TestChainWatcherCoopCloseReorg(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan:   make(chan *chainntnfs.SpendDetail),
+		EpochChan:   make(chan *chainntnfs.BlockEpoch),
+		ConfChan:    make(chan *chainntnfs.TxConfirmation),
+		NegConfChan: make(chan int32),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		isOurAddr:           func(btcutil.Address) bool { return false },
+		extractStateNumHint: lnwallet.GetStateNumHint,
+		coopCloseFinalConfs: 6,
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	err = aliceChainWatcher.Start()
+	require.NoError(t, err, "unable to start chain watcher")
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	coopTx := wire.NewMsgTx(2)
+	coopTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: aliceChannel.State().FundingOutpoint,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coopTx.AddTxOut(&wire.TxOut{
+		Value:    1000,
+		PkScript: make([]byte, 22),
+	})
+	coopTxHash := coopTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash:  &coopTxHash,
+		SpendingTx:     coopTx,
+		SpendingHeight: 100,
+	}
+
+	// The close tx is now reorged out before it reached the final depth.
+	select {
+	case aliceNotifier.NegConfChan <- 1:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("chain watcher didn't wait for confirmations")
+	}
+
+	// The chain watcher must pick up the next spend of the funding output,
+	// which is the same close tx mined again at a later height.
+	select {
+	case aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash:  &coopTxHash,
+		SpendingTx:     coopTx,
+		SpendingHeight: 102,
+	}:
+	case <-time.After(time.Second * 15):
+		t.Fatalf("chain watcher didn't watch the funding output again")
+	}
+
+	select {
+	case <-chanEvents.CooperativeClosure:
+		t.Fatalf("coop close dispatched before final confirmation")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	aliceNotifier.ConfChan <- &chainntnfs.TxConfirmation{}
+
+	select {
+	case closeInfo := <-chanEvents.CooperativeClosure:
+		require.Equal(t, coopTxHash, closeInfo.ClosingTXID)
+		require.EqualValues(t, 102, closeInfo.CloseHeight)
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive coop close event")
+	}
+}
@@ -172,6 +172,10 @@ type ChannelArbitratorConfig struct {
 	// spend his/her outgoing HTLC via the timeout path.
 	FindOutgoingHTLCDeadline func(htlc channeldb.HTLC) fn.Option[int32]
 
+	// ForceCloseThrottle limits the number of concurrent force closes
+	// across all channels. If nil, force closes aren't limited.
+	ForceCloseThrottle *ForceCloseThrottle
+
 	ChainArbitratorConfig
 }
 
@@ -376,6 +380,12 @@ type ChannelArbitrator struct {
 	// upon start up to decide which actions to take.
 	state ArbitratorState
 
+	// throttledClose is set if the broadcast of our commitment was delayed
+	// by the ForceCloseThrottle, in which case the broadcast is retried on
+	// every new block. The link was already shut down, so the channel is
+	// only force closed once.
+	throttledClose *lnwallet.LocalForceCloseSummary
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -473,6 +483,12 @@ func (c *ChannelArbitrator) Start(state *chanArbStartState) error {
 	// Set our state from our starting state.
 	c.state = state.currentState
 
+	// A commitment that was broadcast before the restart still counts
+	// towards the limit of concurrent force closes.
+	if c.state == StateCommitmentBroadcasted {
+		c.cfg.ForceCloseThrottle.MarkActive(c.cfg.ChanPoint)
+	}
+
 	_, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
 	if err != nil {
 		return err
@@ -836,6 +852,8 @@ func (c *ChannelArbitrator) Stop() error {
 	close(c.quit)
 	c.wg.Wait()
 
+	c.cfg.ForceCloseThrottle.Release(c.cfg.ChanPoint)
+
 	return nil
 }
 
@@ -1056,8 +1074,14 @@ func (c *ChannelArbitrator) stateStep(
 
 		// We'll tell the switch that it should remove the link for
 		// this channel, in addition to fetching the force close
-		// summary needed to close this channel on chain.
-		closeSummary, err := c.cfg.Channel.ForceCloseChan()
+		// summary needed to close this channel on chain. If this was
+		// done before and only the broadcast was delayed, we reuse
+		// the summary we got back then.
+		var err error
+		closeSummary := c.throttledClose
+		if closeSummary == nil {
+			closeSummary, err = c.cfg.Channel.ForceCloseChan()
+		}
 		if err != nil {
 			log.Errorf("ChannelArbitrator(%v): unable to "+
 				"force close: %v", c.cfg.ChanPoint, err)
@@ -1079,6 +1103,16 @@ func (c *ChannelArbitrator) stateStep(
 		}
 		closeTx = closeSummary.CloseTx
 
+		// The link is shut down at this point, so the channel can't
+		// be used anymore. Broadcasting the commitment of force
+		// closes that aren't urgent is delayed though if too many
+		// other force closes are already in flight.
+		if !c.acquireForceCloseSlot(triggerHeight, trigger) {
+			c.throttledClose = closeSummary
+			return StateBroadcastCommit, nil, nil
+		}
+		c.throttledClose = nil
+
 		// Before publishing the transaction, we store it to the
 		// database, such that we can re-publish later in case it
 		// didn't propagate. We initiated the force close, so we
@@ -1644,7 +1678,112 @@ func (c *ChannelArbitrator) advanceState(
 			return priorState, nil, err
 		}
 		c.state = nextState
+
+		// Once a commitment confirmed, the force close doesn't need
+		// to be throttled anymore.
+		switch nextState {
+		case StateDefault, StateBroadcastCommit,
+			StateCommitmentBroadcasted:
+
+		default:
+			c.cfg.ForceCloseThrottle.Release(c.cfg.ChanPoint)
+		}
+	}
+}
+
+// acquireForceCloseSlot returns true if the commitment of the channel may be
+// broadcast now. Force closes requested by the user are never delayed, all
+// other force closes are subject to the ForceCloseThrottle, which prioritizes
+// them by the deadline of their most urgent HTLC.
+func (c *ChannelArbitrator) acquireForceCloseSlot(height uint32,
+	trigger transitionTrigger) bool {
+
+	throttle := c.cfg.ForceCloseThrottle
+	if trigger == userTrigger {
+		throttle.MarkActive(c.cfg.ChanPoint)
+		return true
+	}
+
+	deadline, err := c.findForceCloseDeadline(c.activeHTLCs)
+	if err != nil {
+		// Without a deadline we can't tell whether the force close
+		// may be delayed, so we err on the side of caution.
+		log.Errorf("ChannelArbitrator(%v): unable to find force close "+
+			"deadline: %v", c.cfg.ChanPoint, err)
+
+		throttle.MarkActive(c.cfg.ChanPoint)
+		return true
+	}
+
+	if !throttle.Acquire(c.cfg.ChanPoint, deadline, height) {
+		log.Debugf("ChannelArbitrator(%v): delaying force close at "+
+			"height=%v, too many force closes in flight",
+			c.cfg.ChanPoint, height)
+
+		return false
+	}
+
+	return true
+}
+
+// findForceCloseDeadline returns the height at which the commitment must be
+// broadcast at the latest to resolve the most urgent HTLC of the given sets in
+// time. Each HTLC must be resolved before its own deadline, which is the expiry
+// of the incoming HTLC for outgoing HTLCs that were forwarded, and the expiry
+// of the HTLC itself for incoming HTLCs whose preimage we know. The broadcast
+// delta of the HTLC's direction is subtracted from that deadline, so a delayed
+// force close is started no later than we'd go to chain for the HTLC. The
+// minimum over all HTLCs is returned. If no HTLC is time sensitive, fn.None is
+// returned.
+func (c *ChannelArbitrator) findForceCloseDeadline(
+	htlcSets map[HtlcSetKey]htlcSet) (fn.Option[int32], error) {
+
+	deadline := fn.None[int32]()
+	updateDeadline := func(d int32, delta uint32) {
+		d -= int32(delta)
+		if deadline.IsNone() || d < deadline.UnwrapOr(d) {
+			deadline = fn.Some(d)
+		}
+	}
+
+	for _, htlcs := range htlcSets {
+		for _, htlc := range htlcs.outgoingHTLCs {
+			// Dust HTLCs don't need to be resolved on chain.
+			if htlc.OutputIndex < 0 {
+				continue
+			}
+
+			c.cfg.FindOutgoingHTLCDeadline(htlc).WhenSome(
+				func(d int32) {
+					updateDeadline(
+						d, c.cfg.OutgoingBroadcastDelta,
+					)
+				},
+			)
+		}
+
+		for _, htlc := range htlcs.incomingHTLCs {
+			if htlc.OutputIndex < 0 {
+				continue
+			}
+
+			preimageAvailable, err := c.isPreimageAvailable(
+				htlc.RHash,
+			)
+			if err != nil {
+				return fn.None[int32](), err
+			}
+
+			if preimageAvailable {
+				updateDeadline(
+					int32(htlc.RefundTimeout),
+					c.cfg.IncomingBroadcastDelta,
+				)
+			}
+		}
 	}
+
+	return deadline, nil
 }
 
 // ChainAction is an enum that encompasses all possible on-chain actions
@@ -2798,8 +2937,11 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 
 			// If we're not in the default state, then we can
 			// ignore this signal as we're waiting for contract
-			// resolution.
-			if c.state != StateDefault {
+			// resolution, unless our force close was delayed and
+			// needs to be retried.
+			retryForceClose := c.state == StateBroadcastCommit &&
+				c.throttledClose != nil
+			if c.state != StateDefault && !retryForceClose {
 				continue
 			}
 
@@ -3092,7 +3234,11 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			log.Infof("ChannelArbitrator(%v): received force "+
 				"close request", c.cfg.ChanPoint)
 
-			if c.state != StateDefault {
+			// A force close that was delayed by the throttle can
+			// still be pushed through by the user.
+			delayed := c.state == StateBroadcastCommit &&
+				c.throttledClose != nil
+			if c.state != StateDefault && !delayed {
 				select {
 				case closeReq.closeTx <- nil:
 				case <-c.quit:
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -2089,6 +2090,86 @@ func TestChannelArbitratorPendingExpiredHTLC(t *testing.T) {
 	)
 }
 
+// TestChannelArbitratorForceCloseThrottle asserts that a force close triggered
+// by an expiring HTLC is delayed while the ForceCloseThrottle is exhausted and
+// is retried once a slot became free.
+func TestChannelArbitratorForceCloseThrottle(t *testing.T) {
+	t.Parallel()
+
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+		resolvers: make(map[ContractResolver]struct{}),
+	}
+	chanArbCtx, err := createTestChannelArbitrator(t, log)
+	require.NoError(t, err, "unable to create ChannelArbitrator")
+	chanArb := chanArbCtx.chanArb
+
+	// Another channel already occupies the only force close slot.
+	otherChanPoint := wire.OutPoint{Index: 1}
+	throttle := NewForceCloseThrottle(1, DefaultMaxForceCloseDelay)
+	throttle.MarkActive(otherChanPoint)
+	chanArb.cfg.ForceCloseThrottle = throttle
+
+	// The HTLC we'll add is forwarded, and its incoming HTLC expires far
+	// enough in the future for the force close to be delayed.
+	htlcExpiry := uint32(10)
+	chanArb.cfg.IsForwardedHTLC = func(chanID lnwire.ShortChannelID,
+		htlcIndex uint64) bool {
+
+		return true
+	}
+	chanArb.cfg.FindOutgoingHTLCDeadline = func(
+		htlc channeldb.HTLC) fn.Option[int32] {
+
+		return fn.Some(int32(htlcExpiry + 80))
+	}
+
+	require.NoError(t, chanArb.Start(nil))
+	t.Cleanup(func() {
+		require.NoError(t, chanArb.Stop())
+	})
+
+	chanArb.UpdateContractSignals(&ContractSignals{
+		ShortChanID: lnwire.ShortChannelID{},
+	})
+	chanArb.notifyContractUpdate(&ContractUpdate{
+		HtlcKey: RemoteHtlcSet,
+		Htlcs: []channeldb.HTLC{{
+			Amt:           10000,
+			HtlcIndex:     99,
+			RefundTimeout: htlcExpiry,
+		}},
+	})
+
+	// Once the HTLC is about to expire, the arbitrator decides to go to
+	// chain. The link is shut down right away, but the broadcast is
+	// delayed by the throttle.
+	mockChan, _ := chanArb.cfg.Channel.(*mockChannel)
+	chanArb.blocks <- 6
+	chanArbCtx.AssertStateTransitions(StateBroadcastCommit)
+	require.Eventually(t, func() bool {
+		return mockChan.numForceCloses.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Retrying the broadcast on the next block doesn't force close the
+	// channel again.
+	chanArb.blocks <- 7
+	select {
+	case state := <-log.newStates:
+		t.Fatalf("unexpected state transition to %v", state)
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.EqualValues(t, 1, mockChan.numForceCloses.Load())
+
+	// After the other force close was resolved, the next block retries
+	// and broadcasts the commitment.
+	throttle.Release(otherChanPoint)
+	chanArb.blocks <- 8
+	chanArbCtx.AssertStateTransitions(StateCommitmentBroadcasted)
+	require.EqualValues(t, 1, mockChan.numForceCloses.Load())
+}
+
 // TestRemoteCloseInitiator tests the setting of close initiator statuses
 // for remote force closes and breaches.
 func TestRemoteCloseInitiator(t *testing.T) {
@@ -2979,6 +3060,9 @@ type mockChannel struct {
 	anchorResolutions *lnwallet.AnchorResolutions
 
 	forceCloseErr error
+
+	// numForceCloses counts the calls of ForceCloseChan.
+	numForceCloses atomic.Uint32
 }
 
 func (m *mockChannel) NewAnchorResolutions() (*lnwallet.AnchorResolutions,
@@ -2992,6 +3076,8 @@ func (m *mockChannel) NewAnchorResolutions() (*lnwallet.AnchorResolutions,
 }
 
 func (m *mockChannel) ForceCloseChan() (*lnwallet.LocalForceCloseSummary, error) {
+	m.numForceCloses.Add(1)
+
 	if m.forceCloseErr != nil {
 		return nil, m.forceCloseErr
 	}
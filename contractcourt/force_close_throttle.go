@@ -0,0 +1,146 @@
+package contractcourt
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+)
+
+// DefaultMaxForceCloseDelay is the maximum number of blocks the broadcast of a
+// force close is delayed by the ForceCloseThrottle, even if the channel has no
+// time-sensitive HTLCs at all.
+const DefaultMaxForceCloseDelay = 144
+
+// ForceCloseThrottle limits the number of force closes that are in flight at
+// the same time, which protects the fee-bumping budget of the node during a
+// mass force close event. A force close is in flight from the broadcast of
+// the commitment transaction until a commitment transaction confirmed. Force
+// closes beyond the limit are delayed, the free slots are handed out to the
+// closes with the nearest deadline first. A nil ForceCloseThrottle doesn't
+// limit force closes at all.
+type ForceCloseThrottle struct {
+	maxActive int
+
+	// maxDelay is the maximum number of blocks a force close is delayed.
+	maxDelay uint32
+
+	// active is the set of channels that are currently being force
+	// closed.
+	active map[wire.OutPoint]struct{}
+
+	// waiting maps the channels that are waiting to be force closed to
+	// the height at which they must be started at the latest.
+	waiting map[wire.OutPoint]uint32
+
+	mu sync.Mutex
+}
+
+// NewForceCloseThrottle returns a new ForceCloseThrottle that allows up to
+// maxActive concurrent force closes. No force close is delayed by more than
+// maxDelay blocks. If maxActive is zero, nil is returned, which doesn't limit
+// force closes.
+func NewForceCloseThrottle(maxActive, maxDelay uint32) *ForceCloseThrottle {
+	if maxActive == 0 {
+		return nil
+	}
+
+	return &ForceCloseThrottle{
+		maxActive: int(maxActive),
+		maxDelay:  maxDelay,
+		active:    make(map[wire.OutPoint]struct{}),
+		waiting:   make(map[wire.OutPoint]uint32),
+	}
+}
+
+// Acquire returns true if the given channel may be force closed at the given
+// height. The deadline is the height at which the commitment must be broadcast
+// at the latest to resolve the most urgent HTLC of the channel in time,
+// channels without time-sensitive HTLCs pass fn.None. If false is returned,
+// the channel is queued and the caller should try again once a new block
+// arrived.
+func (t *ForceCloseThrottle) Acquire(chanPoint wire.OutPoint,
+	deadline fn.Option[int32], height uint32) bool {
+
+	if t == nil {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.active[chanPoint]; ok {
+		return true
+	}
+
+	// A close is delayed by at most maxDelay blocks from the height it
+	// was first queued at, even if none of its HTLCs is urgent.
+	startHeight, ok := t.waiting[chanPoint]
+	if !ok {
+		startHeight = height + t.maxDelay
+	}
+	deadline.WhenSome(func(d int32) {
+		startHeight = min(startHeight, uint32(max(d, 0)))
+	})
+
+	// A close that can't wait any longer is always started, even if that
+	// exceeds the limit.
+	if startHeight <= height {
+		t.markActive(chanPoint)
+		return true
+	}
+
+	// Waiting closes with an earlier deadline get the free slots first.
+	numQueued := 0
+	for op, h := range t.waiting {
+		if op != chanPoint && h < startHeight {
+			numQueued++
+		}
+	}
+
+	if len(t.active)+numQueued < t.maxActive {
+		t.markActive(chanPoint)
+		return true
+	}
+
+	t.waiting[chanPoint] = startHeight
+
+	return false
+}
+
+// MarkActive marks the given channel as being force closed without checking
+// the limit. This is used for force closes requested by the user and for
+// force closes that were already broadcast before a restart.
+func (t *ForceCloseThrottle) MarkActive(chanPoint wire.OutPoint) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.markActive(chanPoint)
+}
+
+// markActive moves the given channel to the set of active force closes.
+//
+// NOTE: The caller must hold the mutex.
+func (t *ForceCloseThrottle) markActive(chanPoint wire.OutPoint) {
+	delete(t.waiting, chanPoint)
+	t.active[chanPoint] = struct{}{}
+}
+
+// Release removes the given channel from the throttle, either because a
+// commitment transaction confirmed or because the channel isn't arbitrated
+// anymore.
+func (t *ForceCloseThrottle) Release(chanPoint wire.OutPoint) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.waiting, chanPoint)
+	delete(t.active, chanPoint)
+}
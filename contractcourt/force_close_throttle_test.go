@@ -0,0 +1,76 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForceCloseThrottle asserts that the ForceCloseThrottle limits the number
+// of concurrent force closes, prioritizes them by their deadline, never
+// delays urgent ones and caps the delay of all others.
+func TestForceCloseThrottle(t *testing.T) {
+	t.Parallel()
+
+	const (
+		height   = 100
+		maxDelay = 50
+	)
+
+	var (
+		chanA = wire.OutPoint{Index: 1}
+		chanB = wire.OutPoint{Index: 2}
+		chanC = wire.OutPoint{Index: 3}
+		chanD = wire.OutPoint{Index: 4}
+		chanE = wire.OutPoint{Index: 5}
+	)
+
+	// A nil throttle never delays a force close.
+	var unlimited *ForceCloseThrottle
+	require.Nil(t, NewForceCloseThrottle(0, maxDelay))
+	require.True(t, unlimited.Acquire(chanA, fn.None[int32](), height))
+	unlimited.MarkActive(chanA)
+	unlimited.Release(chanA)
+
+	throttle := NewForceCloseThrottle(1, maxDelay)
+
+	// The first force close takes the only slot, acquiring it again
+	// succeeds.
+	require.True(t, throttle.Acquire(chanA, fn.None[int32](), height))
+	require.True(t, throttle.Acquire(chanA, fn.None[int32](), height))
+
+	// Force closes that aren't urgent need to wait.
+	late := fn.Some[int32](height + 40)
+	early := fn.Some[int32](height + 20)
+	require.False(t, throttle.Acquire(chanB, late, height))
+	require.False(t, throttle.Acquire(chanC, early, height))
+
+	// A force close without HTLCs needs to wait as well.
+	require.False(t, throttle.Acquire(chanE, fn.None[int32](), height))
+
+	// An urgent force close is started regardless of the limit.
+	urgent := fn.Some[int32](height)
+	require.True(t, throttle.Acquire(chanD, urgent, height))
+
+	// Once the other force closes are released, the free slot goes to the
+	// waiting close with the earliest deadline.
+	throttle.Release(chanA)
+	throttle.Release(chanD)
+	require.False(t, throttle.Acquire(chanE, fn.None[int32](), height+1))
+	require.False(t, throttle.Acquire(chanB, late, height+1))
+	require.True(t, throttle.Acquire(chanC, early, height+1))
+
+	// A close whose deadline is reached becomes urgent.
+	require.True(t, throttle.Acquire(chanB, late, height+40))
+
+	// A close without HTLCs is started once it waited for the maximum
+	// delay.
+	require.False(t, throttle.Acquire(
+		chanE, fn.None[int32](), height+maxDelay-1,
+	))
+	require.True(t, throttle.Acquire(
+		chanE, fn.None[int32](), height+maxDelay,
+	))
+}
@@ -0,0 +1,142 @@
+package contractcourt
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// ErrBroadcastHalted is returned instead of publishing a transaction once the
+// safe reconnect mode halted all broadcasts.
+var ErrBroadcastHalted = errors.New("safe reconnect mode halted all " +
+	"broadcasts")
+
+// SafeReconnectStore persists the state of the safe reconnect mode.
+type SafeReconnectStore interface {
+	// FetchSafeReconnectState returns the persisted state of the safe
+	// reconnect mode.
+	FetchSafeReconnectState() (*channeldb.SafeReconnectState, error)
+
+	// HaltBroadcasts persistently records that the outdated local state
+	// of the given channel halted all broadcasts.
+	HaltBroadcasts(chanPoint wire.OutPoint) error
+}
+
+// SafeReconnectGuard halts all broadcasts of lnd as soon as a peer shows that
+// our local state of any channel is outdated while the safe reconnect mode is
+// active. This likely means that the whole channel state was restored from an
+// old backup, so any transaction we'd publish could spend from or reveal a
+// revoked state. Once halted, broadcasts stay halted across restarts. Justice
+// transactions don't go through the guard, as they only spend from revoked
+// states of the peer.
+type SafeReconnectGuard struct {
+	store SafeReconnectStore
+
+	mu       sync.Mutex
+	enabled  bool
+	haltedBy *wire.OutPoint
+}
+
+// NewSafeReconnectGuard creates a new guard from the persisted state of the
+// safe reconnect mode. The mode is active if it's enabled by the config, while
+// broadcasts halted before stay halted either way.
+func NewSafeReconnectGuard(enabled bool,
+	store SafeReconnectStore) (*SafeReconnectGuard, error) {
+
+	state, err := store.FetchSafeReconnectState()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &SafeReconnectGuard{
+		store:    store,
+		enabled:  enabled,
+		haltedBy: state.HaltedBy,
+	}
+
+	if g.haltedBy != nil {
+		log.Criticalf("Broadcasts remain halted, as the local state "+
+			"of ChannelPoint(%v) was found to be outdated. %v",
+			g.haltedBy, recoveryInstructions)
+	}
+
+	return g, nil
+}
+
+// recoveryInstructions tells the operator how to recover once broadcasts were
+// halted.
+const recoveryInstructions = "To recover: stop lnd and restore the most " +
+	"recent channel database if one exists. Otherwise start from a fresh " +
+	"channel database and recover the funds with the static channel " +
+	"backup using 'lncli restorechanbackup', which asks the peers to " +
+	"force close the channels."
+
+// NotifyStaleLocalState is called when the channel reestablish message of a
+// peer shows that our local state of the given channel is outdated. If the
+// safe reconnect mode is active, all further broadcasts are halted, as the
+// state of the other channels is likely outdated as well.
+func (g *SafeReconnectGuard) NotifyStaleLocalState(chanPoint wire.OutPoint) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.enabled {
+		return
+	}
+
+	// Only alert the operator once, all broadcasts are halted anyway.
+	if g.haltedBy != nil {
+		log.Warnf("Local state of ChannelPoint(%v) is outdated, "+
+			"broadcasts remain halted", chanPoint)
+
+		return
+	}
+
+	// We halt the broadcasts even if they can't be persisted, as
+	// publishing anything now could lose all funds of a channel.
+	if err := g.store.HaltBroadcasts(chanPoint); err != nil {
+		log.Errorf("Unable to persist halted broadcasts: %v", err)
+	}
+	g.haltedBy = &chanPoint
+
+	log.Criticalf("Local state of ChannelPoint(%v) is outdated, this "+
+		"node was likely restored from an old backup. Safe reconnect "+
+		"mode is active, so no transaction will be broadcast anymore, "+
+		"as it could spend from or reveal a revoked state. %v",
+		chanPoint, recoveryInstructions)
+}
+
+// Halted returns true if all broadcasts are halted.
+func (g *SafeReconnectGuard) Halted() bool {
+	if g == nil {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.haltedBy != nil
+}
+
+// PublishTx wraps the given function publishing transactions so that it
+// returns ErrBroadcastHalted instead once all broadcasts are halted.
+func (g *SafeReconnectGuard) PublishTx(
+	publish func(*wire.MsgTx, string) error) func(*wire.MsgTx,
+	string) error {
+
+	return func(tx *wire.MsgTx, label string) error {
+		if g.Halted() {
+			log.Warnf("Not publishing tx %v: %v", tx.TxHash(),
+				ErrBroadcastHalted)
+
+			return ErrBroadcastHalted
+		}
+
+		return publish(tx, label)
+	}
+}
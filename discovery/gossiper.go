@@ -297,6 +297,12 @@ type Config struct {
 	// graph on connect.
 	IgnoreHistoricalFilters bool
 
+	// MaxFilterWindow is the maximum duration into the past for which
+	// syncers will reply with historical data when the remote peer sets a
+	// gossip_timestamp_range. A value of zero means the requested range is
+	// honored in full.
+	MaxFilterWindow time.Duration
+
 	// PinnedSyncers is a set of peers that will always transition to
 	// ActiveSync upon connection. These peers will never transition to
 	// PassiveSync.
@@ -522,6 +528,7 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		NumActiveSyncers:        cfg.NumActiveSyncers,
 		NoTimestampQueries:      cfg.NoTimestampQueries,
 		IgnoreHistoricalFilters: cfg.IgnoreHistoricalFilters,
+		MaxFilterWindow:         cfg.MaxFilterWindow,
 		BestHeight:              gossiper.latestHeight,
 		PinnedSyncers:           cfg.PinnedSyncers,
 		IsStillZombieChannel:    cfg.IsStillZombieChannel,
@@ -95,6 +95,12 @@ type SyncManagerCfg struct {
 	// graph on connect.
 	IgnoreHistoricalFilters bool
 
+	// MaxFilterWindow is the maximum duration into the past for which
+	// syncers will reply with historical data when the remote peer sets a
+	// gossip_timestamp_range. A value of zero means the requested range is
+	// honored in full.
+	MaxFilterWindow time.Duration
+
 	// BestHeight returns the latest height known of the chain.
 	BestHeight func() uint32
 
@@ -500,6 +506,7 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 			return peer.SendMessageLazy(true, msgs...)
 		},
 		ignoreHistoricalFilters:   m.cfg.IgnoreHistoricalFilters,
+		maxFilterWindow:           m.cfg.MaxFilterWindow,
 		maxUndelayedQueryReplies:  DefaultMaxUndelayedQueryReplies,
 		delayedQueryReplyInterval: DefaultDelayedQueryReplyInterval,
 		bestHeight:                m.cfg.BestHeight,
@@ -286,6 +286,13 @@ type gossipSyncerCfg struct {
 	// graph on connect.
 	ignoreHistoricalFilters bool
 
+	// maxFilterWindow is the maximum duration into the past for which
+	// we'll reply with historical data when the remote peer sets a
+	// gossip_timestamp_range. If the range starts further in the past,
+	// we'll only send the data within the window. A value of zero means
+	// the requested range is honored in full.
+	maxFilterWindow time.Duration
+
 	// bestHeight returns the latest height known of the chain.
 	bestHeight func() uint32
 
@@ -1295,18 +1302,39 @@ func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) er
 		return nil
 	}
 
+	// If the remote peer requested more history than we're willing to
+	// serve, we'll only send the backlog within our window. The filter
+	// itself still applies in full to any new messages going forward.
+	backlogStart := startTime
+	if g.cfg.maxFilterWindow != 0 {
+		windowStart := time.Now().Add(-g.cfg.maxFilterWindow)
+		if backlogStart.Before(windowStart) {
+			log.Debugf("GossipSyncer(%x): capping historical "+
+				"filter start=%v to start=%v", g.cfg.peerPub[:],
+				backlogStart, windowStart)
+
+			backlogStart = windowStart
+		}
+
+		// If the capped window starts after the end of the requested
+		// range, there's no backlog to send.
+		if !backlogStart.Before(endTime) {
+			return nil
+		}
+	}
+
 	// Now that the remote peer has applied their filter, we'll query the
 	// database for all the messages that are beyond this filter.
 	newUpdatestoSend, err := g.cfg.channelSeries.UpdatesInHorizon(
-		g.cfg.chainHash, startTime, endTime,
+		g.cfg.chainHash, backlogStart, endTime,
 	)
 	if err != nil {
 		return err
 	}
 
 	log.Infof("GossipSyncer(%x): applying new update horizon: start=%v, "+
-		"end=%v, backlog_size=%v", g.cfg.peerPub[:], startTime, endTime,
-		len(newUpdatestoSend))
+		"end=%v, backlog_size=%v", g.cfg.peerPub[:], backlogStart,
+		endTime, len(newUpdatestoSend))
 
 	// If we don't have any to send, then we can return early.
 	if len(newUpdatestoSend) == 0 {
@@ -462,6 +462,59 @@ func TestGossipSyncerApplyNoHistoricalGossipFilter(t *testing.T) {
 	}
 }
 
+// TestGossipSyncerApplyGossipFilterWindow tests that the historical data we
+// reply with is capped by the configured filter window, while the filter
+// itself is still applied in full.
+func TestGossipSyncerApplyGossipFilterWindow(t *testing.T) {
+	t.Parallel()
+
+	_, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+	syncer.cfg.maxFilterWindow = time.Hour
+
+	// We'll apply a horizon that starts way before our window and ends in
+	// the future.
+	now := time.Now()
+	remoteHorizon := &lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(now.Add(-24 * time.Hour).Unix()),
+		TimestampRange: uint32((48 * time.Hour).Seconds()),
+	}
+
+	chanSeries.horizonResp <- []lnwire.Message{}
+	err := syncer.ApplyGossipFilter(remoteHorizon)
+	require.NoError(t, err)
+
+	// The backlog query should only start at the beginning of our window,
+	// while the remote horizon itself should be unchanged.
+	select {
+	case query := <-chanSeries.horizonReq:
+		windowStart := now.Add(-time.Hour)
+		require.WithinDuration(t, windowStart, query.start, time.Minute)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no query received")
+	}
+	require.Equal(t, remoteHorizon, syncer.remoteUpdateHorizon)
+
+	// A horizon that ends before our window starts shouldn't result in
+	// any query at all.
+	remoteHorizon = &lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(now.Add(-24 * time.Hour).Unix()),
+		TimestampRange: uint32(time.Hour.Seconds()),
+	}
+	err = syncer.ApplyGossipFilter(remoteHorizon)
+	require.NoError(t, err)
+
+	select {
+	case <-chanSeries.horizonReq:
+		t.Fatalf("chan series should not have been queried")
+
+	case <-time.After(time.Second):
+	}
+}
+
 // TestGossipSyncerApplyGossipFilter tests that once a gossip filter is applied
 // for the remote peer, then we send the peer all known messages which are
 // within their desired time horizon.
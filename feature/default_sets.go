@@ -88,6 +88,10 @@ var defaultSetDesc = setDesc{
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
 	},
+	lnwire.OnionMessagesOptional: {
+		SetInit:    {}, // I
+		SetNodeAnn: {}, // N
+	},
 	lnwire.SimpleTaprootChannelsOptionalStaging: {
 		SetInit:    {}, // I
 		SetNodeAnn: {}, // N
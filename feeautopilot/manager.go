@@ -0,0 +1,410 @@
+// Package feeautopilot adjusts the routing fees of our channels based on their
+// observed forwarding flow and local balance.
+package feeautopilot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/localchans"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultInterval is the default interval at which the fees of our
+	// channels are evaluated.
+	DefaultInterval = time.Hour
+
+	// MinInterval is the minimum interval at which the fees of our
+	// channels are evaluated.
+	MinInterval = time.Minute
+
+	// DefaultMinUpdateInterval is the default minimum time between two
+	// policy updates of a channel.
+	DefaultMinUpdateInterval = 6 * time.Hour
+
+	// MinMinUpdateInterval is the lowest allowed minimum time between two
+	// policy updates of a channel. Every update is gossiped to the whole
+	// network, so updating more often would amount to gossip spam.
+	MinMinUpdateInterval = time.Hour
+
+	// DefaultMinFeeRate is the default lower bound of the fee rate in parts
+	// per million.
+	DefaultMinFeeRate = 1
+
+	// DefaultMaxFeeRate is the default upper bound of the fee rate in parts
+	// per million.
+	DefaultMaxFeeRate = 2_000
+
+	// MaxAllowedFeeRate is the highest upper bound of the fee rate in parts
+	// per million that can be configured, which is 10% of the forwarded
+	// amount.
+	MaxAllowedFeeRate = 100_000
+
+	// DefaultStep is the default relative change of the fee rate of a
+	// single adjustment.
+	DefaultStep = 0.1
+
+	// lowLiquidityRatio is the share of the capacity of a channel below
+	// which its local balance is considered scarce.
+	lowLiquidityRatio = 0.2
+
+	// highLiquidityRatio is the share of the capacity of a channel above
+	// which its local balance is considered abundant.
+	highLiquidityRatio = 0.8
+
+	// maxForwardingEvents is the maximum number of forwarding events that
+	// are queried at once.
+	maxForwardingEvents = 50_000
+)
+
+// Config holds the configuration of the fee autopilot.
+type Config struct {
+	// MinUpdateInterval is the minimum time between two policy updates of
+	// a channel. It is checked against the last update of the policy, so
+	// manual updates also hold back the autopilot. The flow of a channel
+	// is observed over the same period of time.
+	MinUpdateInterval time.Duration
+
+	// MinFeeRate is the lowest fee rate in parts per million the
+	// autopilot sets.
+	MinFeeRate uint32
+
+	// MaxFeeRate is the highest fee rate in parts per million the
+	// autopilot sets.
+	MaxFeeRate uint32
+
+	// Step is the relative change of the fee rate of a single adjustment.
+	Step float64
+
+	// Ticker is the ticker that triggers the evaluation of the fees of
+	// our channels.
+	Ticker ticker.Ticker
+
+	// Clock is the clock used to determine the age of policies and the
+	// window of the observed flow.
+	Clock clock.Clock
+
+	// ForAllOutgoingChannels iterates over all our channels along with
+	// our policies of them.
+	ForAllOutgoingChannels func(cb func(kvdb.RTx, *models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy) error) error
+
+	// FetchAllOpenChannels returns all our open channels.
+	FetchAllOpenChannels func() ([]*channeldb.OpenChannel, error)
+
+	// QueryForwardingLog queries the forwarding events of a time slice.
+	QueryForwardingLog func(channeldb.ForwardingEventQuery) (
+		channeldb.ForwardingLogTimeSlice, error)
+
+	// QueuePolicyUpdates queues the given policy updates, which are then
+	// applied gradually so that adjusting the fees of many channels
+	// doesn't cause a burst of channel updates.
+	QueuePolicyUpdates func(...localchans.QueuedUpdate)
+}
+
+// validate checks that the config is sane.
+func (c *Config) validate() error {
+	if c.MinUpdateInterval < MinMinUpdateInterval {
+		return fmt.Errorf("min update interval must be at least %v",
+			MinMinUpdateInterval)
+	}
+
+	if c.MaxFeeRate > MaxAllowedFeeRate {
+		return fmt.Errorf("max fee rate must not exceed %v ppm",
+			MaxAllowedFeeRate)
+	}
+
+	if c.MinFeeRate > c.MaxFeeRate {
+		return fmt.Errorf("min fee rate %v ppm exceeds max fee rate "+
+			"%v ppm", c.MinFeeRate, c.MaxFeeRate)
+	}
+
+	if c.Step <= 0 || c.Step >= 1 {
+		return fmt.Errorf("step must be between 0 and 1, got %v",
+			c.Step)
+	}
+
+	return nil
+}
+
+// flow is the amount forwarded through a channel in each direction.
+type flow struct {
+	// in is the amount received over the channel.
+	in lnwire.MilliSatoshi
+
+	// out is the amount sent over the channel.
+	out lnwire.MilliSatoshi
+}
+
+// Manager periodically adjusts the fee rates of our channels. The fee rate of
+// a channel is raised if its local balance is scarce or drained by forwards,
+// and lowered if its local balance is abundant or filled up by forwards. Fee
+// rates always stay within the configured bounds, and the base fees and all
+// other policy parameters are left untouched.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager returns a new fee autopilot with the given config.
+func NewManager(cfg *Config) (*Manager, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start starts adjusting the fees of our channels.
+func (m *Manager) Start() error {
+	m.started.Do(func() {
+		log.Infof("Fee autopilot starting, keeping fee rates between "+
+			"%v and %v ppm", m.cfg.MinFeeRate, m.cfg.MaxFeeRate)
+
+		m.cfg.Ticker.Resume()
+
+		m.wg.Add(1)
+		go m.adjustLoop()
+	})
+
+	return nil
+}
+
+// Stop stops adjusting the fees of our channels.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Fee autopilot shutting down...")
+		defer log.Debug("Fee autopilot shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// adjustLoop adjusts the fees of our channels on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) adjustLoop() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			if err := m.adjustFees(); err != nil {
+				log.Errorf("Unable to adjust fees: %v", err)
+			}
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// adjustFees evaluates the fees of all our channels and updates the ones that
+// need to be adjusted.
+func (m *Manager) adjustFees() error {
+	now := m.cfg.Clock.Now()
+
+	flows, err := m.queryFlows(now.Add(-m.cfg.MinUpdateInterval), now)
+	if err != nil {
+		return err
+	}
+
+	openChannels, err := m.cfg.FetchAllOpenChannels()
+	if err != nil {
+		return err
+	}
+	localBalances := make(map[wire.OutPoint]lnwire.MilliSatoshi)
+	for _, channel := range openChannels {
+		localBalances[channel.FundingOutpoint] =
+			channel.LocalCommitment.LocalBalance
+	}
+
+	var updates []localchans.QueuedUpdate
+
+	err = m.cfg.ForAllOutgoingChannels(func(_ kvdb.RTx,
+		info *models.ChannelEdgeInfo,
+		edge *models.ChannelEdgePolicy) error {
+
+		if edge == nil || info.Capacity == 0 {
+			return nil
+		}
+
+		// Respect the minimum time between two updates, regardless
+		// of whether the last one was made by us.
+		if now.Sub(edge.LastUpdate) < m.cfg.MinUpdateInterval {
+			return nil
+		}
+
+		// Disabled channels can't forward anyway, and pending or
+		// closing channels have no usable local balance.
+		if edge.ChannelFlags&lnwire.ChanUpdateDisabled != 0 {
+			return nil
+		}
+		localBalance, ok := localBalances[info.ChannelPoint]
+		if !ok {
+			return nil
+		}
+
+		capacity := lnwire.NewMSatFromSatoshis(info.Capacity)
+		ratio := float64(localBalance) / float64(capacity)
+
+		feeRate := uint32(edge.FeeProportionalMillionths)
+		newFeeRate := m.nextFeeRate(
+			feeRate, ratio, flows[info.ChannelID],
+		)
+		if newFeeRate == feeRate {
+			return nil
+		}
+
+		log.Debugf("Adjusting fee rate of channel %v from %v to %v "+
+			"ppm, local balance ratio=%.2f, flow=%+v",
+			info.ChannelPoint, feeRate, newFeeRate, ratio,
+			flows[info.ChannelID])
+
+		// Only the fee rate is changed. Leaving the max htlc and
+		// the min htlc unset keeps their current values.
+		updates = append(updates, localchans.QueuedUpdate{
+			ChanPoint: info.ChannelPoint,
+			Policy: routing.ChannelPolicy{
+				FeeSchema: routing.FeeSchema{
+					BaseFee: edge.FeeBaseMSat,
+					FeeRate: newFeeRate,
+				},
+				TimeLockDelta: uint32(edge.TimeLockDelta),
+			},
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The updates are queued after iterating over the channels, as
+	// updating a policy requires a write transaction. The queue records
+	// failed updates, so they show up in its status.
+	if len(updates) > 0 {
+		m.cfg.QueuePolicyUpdates(updates...)
+
+		log.Infof("Queued fee rate adjustments of %v channels",
+			len(updates))
+	}
+
+	return nil
+}
+
+// queryFlows returns the amounts forwarded through each channel in the given
+// time slice, keyed by the short channel id.
+func (m *Manager) queryFlows(start, end time.Time) (map[uint64]*flow,
+	error) {
+
+	flows := make(map[uint64]*flow)
+	getFlow := func(chanID lnwire.ShortChannelID) *flow {
+		f, ok := flows[chanID.ToUint64()]
+		if !ok {
+			f = &flow{}
+			flows[chanID.ToUint64()] = f
+		}
+
+		return f
+	}
+
+	query := channeldb.ForwardingEventQuery{
+		StartTime:    start,
+		EndTime:      end,
+		NumMaxEvents: maxForwardingEvents,
+	}
+	for {
+		timeSlice, err := m.cfg.QueryForwardingLog(query)
+		switch {
+		case errors.Is(err, channeldb.ErrNoForwardingEvents):
+			return flows, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		for _, event := range timeSlice.ForwardingEvents {
+			getFlow(event.IncomingChanID).in += event.AmtIn
+			getFlow(event.OutgoingChanID).out += event.AmtOut
+		}
+
+		if len(timeSlice.ForwardingEvents) < maxForwardingEvents {
+			return flows, nil
+		}
+		query.IndexOffset = timeSlice.LastIndexOffset
+	}
+}
+
+// nextFeeRate returns the fee rate of a channel with the given local balance
+// ratio and flow, clamped to the configured bounds.
+func (m *Manager) nextFeeRate(feeRate uint32, ratio float64, f *flow) uint32 {
+	if f == nil {
+		f = &flow{}
+	}
+	draining := f.out > f.in
+	filling := f.in > f.out
+
+	// Every adjustment changes the fee rate by at least 1 ppm, so that
+	// small fee rates aren't stuck.
+	delta := int64(math.Round(float64(feeRate) * m.cfg.Step))
+	if delta < 1 {
+		delta = 1
+	}
+
+	next := int64(feeRate)
+	switch {
+	// Our local balance is scarce, so it's raised to keep the remaining
+	// liquidity for the forwards that pay the most.
+	case ratio < lowLiquidityRatio:
+		next += delta
+
+	// Our local balance is abundant and isn't drained, so it's lowered
+	// to attract forwards.
+	case ratio > highLiquidityRatio && !draining:
+		next -= delta
+
+	// Forwards drain the channel below its midpoint.
+	case draining && ratio < 0.5:
+		next += delta
+
+	// Forwards fill up the channel above its midpoint.
+	case filling && ratio > 0.5:
+		next -= delta
+	}
+
+	switch {
+	case next < int64(m.cfg.MinFeeRate):
+		return m.cfg.MinFeeRate
+
+	case next > int64(m.cfg.MaxFeeRate):
+		return m.cfg.MaxFeeRate
+
+	default:
+		return uint32(next)
+	}
+}
@@ -0,0 +1,335 @@
+package feeautopilot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/localchans"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Unix(1_700_000_000, 0)
+
+// testConfig returns a valid config without any dependencies.
+func testConfig() *Config {
+	return &Config{
+		MinUpdateInterval: DefaultMinUpdateInterval,
+		MinFeeRate:        DefaultMinFeeRate,
+		MaxFeeRate:        DefaultMaxFeeRate,
+		Step:              DefaultStep,
+		Ticker:            ticker.NewForce(DefaultInterval),
+		Clock:             clock.NewTestClock(testTime),
+	}
+}
+
+// TestConfigValidate tests that invalid configs are rejected.
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, testConfig().validate())
+
+	cfg := testConfig()
+	cfg.MinUpdateInterval = time.Minute
+	require.Error(t, cfg.validate())
+
+	cfg = testConfig()
+	cfg.MaxFeeRate = MaxAllowedFeeRate + 1
+	require.Error(t, cfg.validate())
+
+	cfg = testConfig()
+	cfg.MinFeeRate = cfg.MaxFeeRate + 1
+	require.Error(t, cfg.validate())
+
+	cfg = testConfig()
+	cfg.Step = 0
+	require.Error(t, cfg.validate())
+
+	cfg = testConfig()
+	cfg.Step = 1
+	require.Error(t, cfg.validate())
+}
+
+// TestNextFeeRate tests the adjustment of fee rates based on the local balance
+// and the flow of a channel.
+func TestNextFeeRate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		noFlow   = &flow{}
+		draining = &flow{in: 1_000, out: 2_000}
+		filling  = &flow{in: 2_000, out: 1_000}
+	)
+
+	tests := []struct {
+		name    string
+		feeRate uint32
+		ratio   float64
+		flow    *flow
+		next    uint32
+	}{
+		{
+			name:    "scarce balance",
+			feeRate: 100,
+			ratio:   0.1,
+			flow:    filling,
+			next:    110,
+		},
+		{
+			name:    "scarce balance at max fee rate",
+			feeRate: DefaultMaxFeeRate,
+			ratio:   0.1,
+			flow:    noFlow,
+			next:    DefaultMaxFeeRate,
+		},
+		{
+			name:    "small fee rate raised",
+			feeRate: 1,
+			ratio:   0.1,
+			flow:    noFlow,
+			next:    2,
+		},
+		{
+			name:    "abundant idle balance",
+			feeRate: 100,
+			ratio:   0.9,
+			flow:    noFlow,
+			next:    90,
+		},
+		{
+			name:    "abundant drained balance",
+			feeRate: 100,
+			ratio:   0.9,
+			flow:    draining,
+			next:    100,
+		},
+		{
+			name:    "abundant balance at min fee rate",
+			feeRate: DefaultMinFeeRate,
+			ratio:   0.9,
+			flow:    nil,
+			next:    DefaultMinFeeRate,
+		},
+		{
+			name:    "drained below midpoint",
+			feeRate: 100,
+			ratio:   0.4,
+			flow:    draining,
+			next:    110,
+		},
+		{
+			name:    "drained above midpoint",
+			feeRate: 100,
+			ratio:   0.6,
+			flow:    draining,
+			next:    100,
+		},
+		{
+			name:    "filled above midpoint",
+			feeRate: 100,
+			ratio:   0.6,
+			flow:    filling,
+			next:    90,
+		},
+		{
+			name:    "filled below midpoint",
+			feeRate: 100,
+			ratio:   0.4,
+			flow:    filling,
+			next:    100,
+		},
+		{
+			name:    "fee rate above bounds",
+			feeRate: 5_000,
+			ratio:   0.5,
+			flow:    noFlow,
+			next:    DefaultMaxFeeRate,
+		},
+		{
+			name:    "fee rate below bounds",
+			feeRate: 0,
+			ratio:   0.5,
+			flow:    noFlow,
+			next:    DefaultMinFeeRate,
+		},
+	}
+
+	m, err := NewManager(testConfig())
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := m.nextFeeRate(
+				test.feeRate, test.ratio, test.flow,
+			)
+			require.Equal(t, test.next, next)
+		})
+	}
+}
+
+// testChannel is a channel of the adjustFees test.
+type testChannel struct {
+	chanPoint    wire.OutPoint
+	chanID       lnwire.ShortChannelID
+	localBalance lnwire.MilliSatoshi
+	lastUpdate   time.Time
+	open         bool
+}
+
+// TestAdjustFees tests that the fee rates of our channels are updated based on
+// their local balance and forwarding flow, respecting the minimum time between
+// two updates.
+func TestAdjustFees(t *testing.T) {
+	t.Parallel()
+
+	const capacity = btcutil.Amount(1_000_000)
+
+	oldUpdate := testTime.Add(-DefaultMinUpdateInterval)
+	recentUpdate := testTime.Add(-time.Hour)
+
+	channels := []*testChannel{
+		// A channel with scarce local balance is raised.
+		{
+			chanPoint:    wire.OutPoint{Index: 1},
+			chanID:       lnwire.NewShortChanIDFromInt(1),
+			localBalance: 100_000_000,
+			lastUpdate:   oldUpdate,
+			open:         true,
+		},
+		// A balanced channel that is filled up by forwards is lowered.
+		{
+			chanPoint:    wire.OutPoint{Index: 2},
+			chanID:       lnwire.NewShortChanIDFromInt(2),
+			localBalance: 600_000_000,
+			lastUpdate:   oldUpdate,
+			open:         true,
+		},
+		// A recently updated channel is left alone.
+		{
+			chanPoint:    wire.OutPoint{Index: 3},
+			chanID:       lnwire.NewShortChanIDFromInt(3),
+			localBalance: 100_000_000,
+			lastUpdate:   recentUpdate,
+			open:         true,
+		},
+		// A channel that isn't open anymore is left alone.
+		{
+			chanPoint:  wire.OutPoint{Index: 4},
+			chanID:     lnwire.NewShortChanIDFromInt(4),
+			lastUpdate: oldUpdate,
+		},
+		// A balanced channel without any flow is left alone.
+		{
+			chanPoint:    wire.OutPoint{Index: 5},
+			chanID:       lnwire.NewShortChanIDFromInt(5),
+			localBalance: 500_000_000,
+			lastUpdate:   oldUpdate,
+			open:         true,
+		},
+	}
+
+	cfg := testConfig()
+	cfg.ForAllOutgoingChannels = func(cb func(kvdb.RTx,
+		*models.ChannelEdgeInfo,
+		*models.ChannelEdgePolicy) error) error {
+
+		for _, channel := range channels {
+			info := &models.ChannelEdgeInfo{
+				ChannelID:    channel.chanID.ToUint64(),
+				ChannelPoint: channel.chanPoint,
+				Capacity:     capacity,
+			}
+			edge := &models.ChannelEdgePolicy{
+				ChannelID:                 info.ChannelID,
+				LastUpdate:                channel.lastUpdate,
+				TimeLockDelta:             80,
+				FeeBaseMSat:               1_000,
+				FeeProportionalMillionths: 100,
+			}
+			if err := cb(nil, info, edge); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	cfg.FetchAllOpenChannels = func() ([]*channeldb.OpenChannel, error) {
+		var open []*channeldb.OpenChannel
+		for _, channel := range channels {
+			if !channel.open {
+				continue
+			}
+
+			openChannel := &channeldb.OpenChannel{
+				FundingOutpoint: channel.chanPoint,
+			}
+			openChannel.LocalCommitment.LocalBalance =
+				channel.localBalance
+			open = append(open, openChannel)
+		}
+
+		return open, nil
+	}
+
+	var queries []channeldb.ForwardingEventQuery
+	cfg.QueryForwardingLog = func(q channeldb.ForwardingEventQuery) (
+		channeldb.ForwardingLogTimeSlice, error) {
+
+		queries = append(queries, q)
+
+		return channeldb.ForwardingLogTimeSlice{
+			ForwardingEventQuery: q,
+			ForwardingEvents: []channeldb.ForwardingEvent{{
+				IncomingChanID: channels[1].chanID,
+				OutgoingChanID: channels[0].chanID,
+				AmtIn:          1_001_000,
+				AmtOut:         1_000_000,
+			}},
+		}, nil
+	}
+
+	updates := make(map[wire.OutPoint]routing.ChannelPolicy)
+	cfg.QueuePolicyUpdates = func(queued ...localchans.QueuedUpdate) {
+		for _, update := range queued {
+			updates[update.ChanPoint] = update.Policy
+		}
+	}
+
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	require.NoError(t, m.adjustFees())
+
+	// The flow is observed over the minimum time between two updates.
+	require.Len(t, queries, 1)
+	require.Equal(t, oldUpdate, queries[0].StartTime)
+	require.Equal(t, testTime, queries[0].EndTime)
+
+	// Only the fee rate is changed, all other parameters are kept.
+	require.Equal(t, map[wire.OutPoint]routing.ChannelPolicy{
+		channels[0].chanPoint: {
+			FeeSchema: routing.FeeSchema{
+				BaseFee: 1_000,
+				FeeRate: 110,
+			},
+			TimeLockDelta: 80,
+		},
+		channels[1].chanPoint: {
+			FeeSchema: routing.FeeSchema{
+				BaseFee: 1_000,
+				FeeRate: 90,
+			},
+			TimeLockDelta: 80,
+		},
+	}, updates)
+}
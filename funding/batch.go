@@ -16,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"golang.org/x/sync/errgroup"
 )
@@ -172,6 +173,12 @@ type BatchConfig struct {
 	// Quit is the channel that is selected on to recognize if the main
 	// server is shutting down.
 	Quit chan struct{}
+
+	// MinInputConfs is the minimum number of confirmations each wallet
+	// input used to fund the batch transaction must have, regardless of
+	// the number of confirmations requested. A value of zero leaves it up
+	// to the request.
+	MinInputConfs int32
 }
 
 // Batcher is a type that can be used to perform an atomic funding of multiple
@@ -332,7 +339,16 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	// anyway.
 	firstReq := b.channels[0].fundingReq
 	feeRateSatPerVByte := firstReq.FundingFeePerKw.FeePerVByte()
+	minConfs := enforceMinInputConfs(firstReq.MinConfs, b.cfg.MinInputConfs)
 	changeType := walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
+
+	// Custom accounts are bound to a single key scope, so the change type
+	// is determined by the account itself.
+	if req.Account != "" && req.Account != lnwallet.DefaultAccountName {
+		//nolint:lll
+		changeType = walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_UNSPECIFIED
+	}
+
 	fundPsbtReq := &walletrpc.FundPsbtRequest{
 		Template: &walletrpc.FundPsbtRequest_Raw{
 			Raw: txTemplate,
@@ -340,10 +356,11 @@ func (b *Batcher) BatchFund(ctx context.Context,
 		Fees: &walletrpc.FundPsbtRequest_SatPerVbyte{
 			SatPerVbyte: uint64(feeRateSatPerVByte),
 		},
-		MinConfs:              firstReq.MinConfs,
-		SpendUnconfirmed:      firstReq.MinConfs == 0,
+		MinConfs:              minConfs,
+		SpendUnconfirmed:      minConfs == 0,
 		ChangeType:            changeType,
 		CoinSelectionStrategy: req.CoinSelectionStrategy,
+		Account:               req.Account,
 	}
 	fundPsbtResp, err := b.cfg.WalletKitServer.FundPsbt(ctx, fundPsbtReq)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"net"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -124,6 +126,20 @@ var (
 	ErrConfirmationTimeout = errors.New("timeout waiting for funding " +
 		"confirmation")
 
+	// ErrChannelOpenAborted is the error a funding flow fails with if the
+	// channel open was aborted by the user.
+	ErrChannelOpenAborted = errors.New("channel open aborted")
+
+	// ErrAbortTooLate is returned when attempting to abort a channel open
+	// that passed the point of no return, as the funding transaction may
+	// already be broadcast.
+	ErrAbortTooLate = errors.New("channel open passed the point of no " +
+		"return, the funding transaction may already be broadcast")
+
+	// ErrUnknownChannelOpen is returned when attempting to abort a channel
+	// open that isn't known.
+	ErrUnknownChannelOpen = errors.New("no pending channel open found")
+
 	// errUpfrontShutdownScriptNotSupported is returned if an upfront
 	// shutdown script is set for a peer that does not support the feature
 	// bit.
@@ -165,6 +181,11 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// fundingConfs is the number of confirmations of the funding
+	// transaction the initiator of the channel requested. Zero means no
+	// number was requested.
+	fundingConfs uint16
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -298,6 +319,23 @@ type InitFundingMsg struct {
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// Account is the name of the wallet account the channel is funded
+	// from. If empty, the default account is used.
+	Account string
+
+	// CoinSelectionStrategy is the strategy used to select the coins that
+	// fund the channel. If nil, the strategy configured for the wallet is
+	// used.
+	CoinSelectionStrategy wallet.CoinSelectionStrategy
+
+	// FundingConfs is the number of confirmations of the funding
+	// transaction we require before the channel is considered open. The
+	// number of confirmations required by the remote peer is always
+	// respected, so the higher of the two is used. If zero, only the
+	// requirement of the remote peer applies. It must not be lower than
+	// the configured minimum and can't be set for zero-conf channels.
+	FundingConfs uint16
+
 	// Updates is a channel which updates to the opening status of the
 	// channel are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -340,6 +378,17 @@ type DevConfig struct {
 	ProcessChannelReadyWait time.Duration
 }
 
+// NetMinChanSize is the smallest inbound channel size we accept from peers
+// connecting from a network.
+type NetMinChanSize struct {
+	// Net is the network the peers connect from.
+	Net *net.IPNet
+
+	// MinChanSize is the smallest inbound channel size we accept from
+	// peers connecting from Net.
+	MinChanSize btcutil.Amount
+}
+
 // Config defines the configuration for the FundingManager. All elements
 // within the configuration MUST be non-nil for the FundingManager to carry out
 // its duties.
@@ -490,6 +539,20 @@ type Config struct {
 	// due to fees.
 	MinChanSize btcutil.Amount
 
+	// MinChanSizeOverrides maps the serialized public keys of peers to the
+	// smallest inbound channel size we accept from them. Peers without an
+	// entry use MinChanSize.
+	MinChanSizeOverrides map[[33]byte]btcutil.Amount
+
+	// MinChanSizeNetOverrides are the smallest inbound channel sizes we
+	// accept from peers connecting from the given networks. If several
+	// networks contain the address of a peer, the most specific one
+	// applies. Peers with an entry in MinChanSizeOverrides use that
+	// instead. Connections from the loopback interface never match, as
+	// that's where the peers connecting through our onion service come
+	// from.
+	MinChanSizeNetOverrides []NetMinChanSize
+
 	// MaxChanSize is the largest channel size that we'll accept as an
 	// inbound channel. We have such a parameter, so that you may decide how
 	// WUMBO you would like your channel.
@@ -499,10 +562,41 @@ type Config struct {
 	// allow for each peer.
 	MaxPendingChannels int
 
+	// MaxPendingChannelsOverrides maps the serialized public keys of peers
+	// to the maximum number of pending channels we allow for them. Peers
+	// without an entry use MaxPendingChannels.
+	MaxPendingChannelsOverrides map[[33]byte]int
+
+	// MaxPendingChannelsGlobal is the maximum number of pending channels
+	// we allow across all peers. Channels we initiated ourselves, such as
+	// batch opens, count towards the limit, but only channels initiated by
+	// peers are rejected once it's reached. A value of zero disables the
+	// limit.
+	MaxPendingChannelsGlobal int
+
+	// MaxChannelsPerPeer is the maximum number of channels, including the
+	// pending ones, we allow with each peer. Channels opened by the peer
+	// and by us both count towards the limit. A value of zero disables the
+	// limit.
+	MaxChannelsPerPeer int
+
 	// RejectPush is set true if the fundingmanager should reject any
 	// incoming channels having a non-zero push amount.
 	RejectPush bool
 
+	// RejectTorOnlyPeers is set true if the fundingmanager should reject
+	// incoming channels from peers that are only reachable over Tor. It
+	// has no effect if TorActive is set, as we can reach such peers
+	// ourselves then.
+	RejectTorOnlyPeers bool
+
+	// TorActive is set true if we connect to peers through Tor.
+	TorActive bool
+
+	// IsTorOnlyPeer determines whether the given peer is only reachable
+	// over Tor. It must be set if RejectTorOnlyPeers is.
+	IsTorOnlyPeer func(peer lnpeer.Peer) (bool, error)
+
 	// MaxLocalCSVDelay is the maximum csv delay we will allow for our
 	// commit output. Channels that exceed this value will be failed.
 	MaxLocalCSVDelay uint16
@@ -543,6 +637,17 @@ type Config struct {
 	// backed funding flow to not use utxos still being swept by the sweeper
 	// subsystem.
 	IsSweeperOutpoint func(wire.OutPoint) bool
+
+	// MinInputConfs is the minimum number of confirmations each wallet
+	// input used to fund a channel must have, regardless of the number of
+	// confirmations requested when initiating the funding flow. A value of
+	// zero leaves it up to the individual request.
+	MinInputConfs int32
+
+	// MinFundingConfs is the lowest number of confirmations of the
+	// funding transaction a funding request may ask for. Requests that
+	// don't set a number aren't affected.
+	MinFundingConfs uint16
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -593,8 +698,21 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
-	// goroutine safe.
+	// completedReservations maps the pending channel ID of reservations
+	// that completed the funding flow to their channel point, until the
+	// funding transaction confirms. It allows us to tell attempts to abort
+	// a channel open that passed the point of no return apart from
+	// attempts to abort an unknown one.
+	//
+	// NOTE: The pending channel ID isn't stored with the channel, so this
+	// map is only kept in memory. After a restart, an attempt to abort a
+	// completed channel open by its pending channel ID is reported as
+	// unknown instead. Aborting the channel opens of a peer still detects
+	// its pending channels in the database.
+	completedReservations map[[32]byte]wire.OutPoint
+
+	// resMtx guards the maps above to ensure that all access is goroutine
+	// safe.
 	resMtx sync.RWMutex
 
 	// fundingMsgs is a channel that relays fundingMsg structs from
@@ -605,6 +723,10 @@ type Manager struct {
 	// requests from a local subsystem within the daemon.
 	fundingRequests chan *InitFundingMsg
 
+	// abortRequests is a channel used to receive requests to abort
+	// pending channel opens.
+	abortRequests chan *abortOpenMsg
+
 	localDiscoverySignals *lnutils.SyncMap[lnwire.ChannelID, chan struct{}]
 
 	handleChannelReadyBarriers *lnutils.SyncMap[lnwire.ChannelID, struct{}]
@@ -661,12 +783,14 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		signedReservations: make(
 			map[lnwire.ChannelID][32]byte,
 		),
+		completedReservations: make(map[[32]byte]wire.OutPoint),
 		fundingMsgs: make(
 			chan *fundingMsg, msgBufferSize,
 		),
 		fundingRequests: make(
 			chan *InitFundingMsg, msgBufferSize,
 		),
+		abortRequests: make(chan *abortOpenMsg),
 		localDiscoverySignals: &lnutils.SyncMap[
 			lnwire.ChannelID, chan struct{},
 		]{},
@@ -855,6 +979,163 @@ func (f *Manager) CancelPeerReservations(nodePub [33]byte) {
 	delete(f.activeReservations, nodePub)
 }
 
+// abortOpenMsg is a request to abort pending channel opens that didn't pass
+// the point of no return yet.
+type abortOpenMsg struct {
+	// peerKey, if set, selects all channel opens with the given peer.
+	peerKey *btcec.PublicKey
+
+	// pendingChanID selects a single channel open if no peerKey is set.
+	pendingChanID [32]byte
+
+	// aborted receives the pending channel IDs of the aborted channel
+	// opens.
+	aborted chan [][32]byte
+
+	// err receives the error if no channel open could be aborted.
+	err chan error
+}
+
+// AbortChannelOpen aborts the channel open with the given pending channel ID,
+// releasing the wallet inputs reserved for it. A channel open can only be
+// aborted as long as it didn't pass the point of no return, which is once
+// both sides signed the commitment transactions and the funding transaction
+// can be broadcast. ErrAbortTooLate is returned after that point.
+func (f *Manager) AbortChannelOpen(pendingChanID [32]byte) error {
+	_, err := f.abortOpens(&abortOpenMsg{pendingChanID: pendingChanID})
+
+	return err
+}
+
+// AbortPeerChannelOpens aborts all channel opens with the given peer that
+// didn't pass the point of no return yet, and returns their pending channel
+// IDs. ErrAbortTooLate is returned if there's no such channel open, but a
+// channel with the peer is pending.
+func (f *Manager) AbortPeerChannelOpens(peerKey *btcec.PublicKey) ([][32]byte,
+	error) {
+
+	return f.abortOpens(&abortOpenMsg{peerKey: peerKey})
+}
+
+// abortOpens hands the abort request to the reservation coordinator, so that
+// it's serialized with the processing of funding messages, and waits for the
+// result.
+func (f *Manager) abortOpens(msg *abortOpenMsg) ([][32]byte, error) {
+	msg.aborted = make(chan [][32]byte, 1)
+	msg.err = make(chan error, 1)
+
+	select {
+	case f.abortRequests <- msg:
+	case <-f.quit:
+		return nil, ErrFundingManagerShuttingDown
+	}
+
+	select {
+	case aborted := <-msg.aborted:
+		return aborted, nil
+
+	case err := <-msg.err:
+		return nil, err
+
+	case <-f.quit:
+		return nil, ErrFundingManagerShuttingDown
+	}
+}
+
+// handleAbortOpen aborts the channel opens selected by the given request. As
+// long as a reservation is active, neither side can broadcast the funding
+// transaction yet, so the funding flow is failed, which cancels the
+// reservation and lets the peer know.
+func (f *Manager) handleAbortOpen(msg *abortOpenMsg) {
+	var peerIDKey serializedPubKey
+	if msg.peerKey != nil {
+		peerIDKey = newSerializedKey(msg.peerKey)
+	}
+
+	f.resMtx.Lock()
+	targets := make(pendingChannels)
+	for resPeerKey, reservations := range f.activeReservations {
+		for pendingChanID, resCtx := range reservations {
+			switch {
+			case msg.peerKey != nil && resPeerKey != peerIDKey:
+				continue
+
+			case msg.peerKey == nil &&
+				pendingChanID != msg.pendingChanID:
+
+				continue
+			}
+
+			targets[pendingChanID] = resCtx
+		}
+	}
+
+	// If we already sent our signature for the commitment transaction of
+	// the peer, it references the channel by its permanent ID. We'll
+	// need it to clean up the pending channel of the peer.
+	chanIDs := make(map[[32]byte]lnwire.ChannelID)
+	for chanID, pendingChanID := range f.signedReservations {
+		if _, ok := targets[pendingChanID]; ok {
+			chanIDs[pendingChanID] = chanID
+			delete(f.signedReservations, chanID)
+		}
+	}
+
+	_, completed := f.completedReservations[msg.pendingChanID]
+	f.resMtx.Unlock()
+
+	if len(targets) == 0 {
+		msg.err <- f.abortOpenError(msg, completed)
+
+		return
+	}
+
+	aborted := make([][32]byte, 0, len(targets))
+	for pendingChanID, resCtx := range targets {
+		log.Infof("Aborting channel open with peer %x, "+
+			"pending_chan_id=%x",
+			resCtx.peer.IdentityKey().SerializeCompressed(),
+			pendingChanID[:])
+
+		cid := newChanIdentifier(pendingChanID)
+		if chanID, ok := chanIDs[pendingChanID]; ok {
+			cid.setChanID(chanID)
+		}
+		f.failFundingFlow(resCtx.peer, cid, ErrChannelOpenAborted)
+
+		aborted = append(aborted, pendingChanID)
+	}
+
+	msg.aborted <- aborted
+}
+
+// abortOpenError returns the error for an abort request that didn't match any
+// active reservation, telling whether the channel open is unknown or passed
+// the point of no return.
+func (f *Manager) abortOpenError(msg *abortOpenMsg, completed bool) error {
+	if msg.peerKey == nil {
+		if completed {
+			return ErrAbortTooLate
+		}
+
+		return fmt.Errorf("%w: pending_chan_id=%x",
+			ErrUnknownChannelOpen, msg.pendingChanID[:])
+	}
+
+	channels, err := f.cfg.ChannelDB.FetchOpenChannels(msg.peerKey)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if channel.IsPending {
+			return ErrAbortTooLate
+		}
+	}
+
+	return fmt.Errorf("%w: peer=%x", ErrUnknownChannelOpen,
+		msg.peerKey.SerializeCompressed())
+}
+
 // chanIdentifier wraps pending channel ID and channel ID into one struct so
 // it's easier to identify a specific channel.
 //
@@ -1027,6 +1308,9 @@ func (f *Manager) reservationCoordinator() {
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
 
+		case req := <-f.abortRequests:
+			f.handleAbortOpen(req)
+
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
 
@@ -1059,6 +1343,12 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 				channel.FundingOutpoint, err)
 			return
 		}
+
+		// The funding transaction is confirmed, so we no longer need
+		// to remember the completed reservation.
+		f.resMtx.Lock()
+		delete(f.completedReservations, pendingChanID)
+		f.resMtx.Unlock()
 	}
 
 	// We create the state-machine object which wraps the database state.
@@ -1369,6 +1659,109 @@ func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
 	}
 }
 
+// numPendingGlobal returns the number of pending channels across all peers,
+// given the channels pending in the database. Both the active reservations
+// and the pending channels are counted, except for those created through a
+// canned funding shim.
+func (f *Manager) numPendingGlobal(pendingChans []*channeldb.OpenChannel) int {
+	numPending := 0
+
+	f.resMtx.RLock()
+	for _, reservations := range f.activeReservations {
+		for _, res := range reservations {
+			if !res.reservation.IsCannedShim() {
+				numPending++
+			}
+		}
+	}
+	f.resMtx.RUnlock()
+
+	for _, c := range pendingChans {
+		if c.ThawHeight == 0 {
+			numPending++
+		}
+	}
+
+	return numPending
+}
+
+// checkMaxChannelsPerPeer returns an error if we can't open another channel
+// with the given peer without exceeding the maximum number of channels per
+// peer. The active reservations as well as the pending and open channels in
+// the database are counted.
+func (f *Manager) checkMaxChannelsPerPeer(peerKey *btcec.PublicKey) error {
+	if f.cfg.MaxChannelsPerPeer == 0 {
+		return nil
+	}
+
+	f.resMtx.RLock()
+	numChans := len(f.activeReservations[newSerializedKey(peerKey)])
+	f.resMtx.RUnlock()
+
+	channels, err := f.cfg.ChannelDB.FetchOpenChannels(peerKey)
+	if err != nil {
+		return err
+	}
+	numChans += len(channels)
+
+	if numChans >= f.cfg.MaxChannelsPerPeer {
+		return lnwallet.ErrTooManyChannels(
+			numChans, f.cfg.MaxChannelsPerPeer,
+		)
+	}
+
+	return nil
+}
+
+// maxPendingChannels returns the maximum number of pending channels we allow
+// for the given peer, preferring a configured override for the peer over the
+// default limit.
+func (f *Manager) maxPendingChannels(peerKey *btcec.PublicKey) int {
+	var serializedKey [33]byte
+	copy(serializedKey[:], peerKey.SerializeCompressed())
+
+	if maxPending, ok := f.cfg.MaxPendingChannelsOverrides[serializedKey]; ok {
+		return maxPending
+	}
+
+	return f.cfg.MaxPendingChannels
+}
+
+// minChanSize returns the smallest inbound channel size we accept from the
+// given peer. A configured override for the peer is preferred over the one of
+// the most specific network the peer connected from, which is preferred over
+// the default minimum.
+func (f *Manager) minChanSize(peer lnpeer.Peer) btcutil.Amount {
+	if minChanSize, ok := f.cfg.MinChanSizeOverrides[peer.PubKey()]; ok {
+		return minChanSize
+	}
+
+	// Only peers connected over TCP have an IP address we can match, peers
+	// connected over Tor use the default minimum. That includes the peers
+	// connecting through our onion service, which appear to come from the
+	// loopback interface.
+	tcpAddr, ok := peer.Address().(*net.TCPAddr)
+	if !ok || tcpAddr.IP.IsLoopback() {
+		return f.cfg.MinChanSize
+	}
+
+	minChanSize := f.cfg.MinChanSize
+	matchedPrefixLen := -1
+	for _, override := range f.cfg.MinChanSizeNetOverrides {
+		if !override.Net.Contains(tcpAddr.IP) {
+			continue
+		}
+
+		prefixLen, _ := override.Net.Mask.Size()
+		if prefixLen > matchedPrefixLen {
+			minChanSize = override.MinChanSize
+			matchedPrefixLen = prefixLen
+		}
+	}
+
+	return minChanSize
+}
+
 // fundeeProcessOpenChannel creates an initial 'ChannelReservation' within the
 // wallet, then responds to the source peer with an accept channel message
 // progressing the funding workflow.
@@ -1429,7 +1822,7 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 
 	// TODO(roasbeef): modify to only accept a _single_ pending channel per
 	// block unless white listed
-	if numPending >= f.cfg.MaxPendingChannels {
+	if numPending >= f.maxPendingChannels(peerPubKey) {
 		f.failFundingFlow(peer, cid, lnwire.ErrMaxPendingChannels)
 
 		return
@@ -1447,6 +1840,35 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// Also enforce the configured limit of pending channels across all
+	// peers, counting them the same way as for the per peer limit.
+	if f.cfg.MaxPendingChannelsGlobal > 0 {
+		numPendingGlobal := f.numPendingGlobal(pendingChans)
+		if numPendingGlobal >= f.cfg.MaxPendingChannelsGlobal {
+			log.Warnf("Rejecting channel open from peer %x, %d "+
+				"channels pending across all peers",
+				peerPubKey.SerializeCompressed(),
+				numPendingGlobal)
+
+			f.failFundingFlow(
+				peer, cid, lnwire.ErrMaxPendingChannels,
+			)
+
+			return
+		}
+	}
+
+	// Reject the channel if the peer already has as many channels with us
+	// as we allow.
+	if err := f.checkMaxChannelsPerPeer(peerPubKey); err != nil {
+		log.Warnf("Rejecting channel open from peer %x: %v",
+			peerPubKey.SerializeCompressed(), err)
+
+		f.failFundingFlow(peer, cid, err)
+
+		return
+	}
+
 	// We'll also reject any requests to create channels until we're fully
 	// synced to the network as we won't be able to properly validate the
 	// confirmation of the funding transaction.
@@ -1471,10 +1893,9 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 
 	// We'll, also ensure that the remote party isn't attempting to propose
 	// a channel that's below our current min channel size.
-	if amt < f.cfg.MinChanSize {
+	if minChanSize := f.minChanSize(peer); amt < minChanSize {
 		f.failFundingFlow(
-			peer, cid,
-			lnwallet.ErrChanTooSmall(amt, f.cfg.MinChanSize),
+			peer, cid, lnwallet.ErrChanTooSmall(amt, minChanSize),
 		)
 		return
 	}
@@ -1486,6 +1907,25 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// Reject the channel if the peer is only reachable over Tor, which we
+	// can't reach without Tor, and we don't accept channels from such
+	// peers.
+	if f.cfg.RejectTorOnlyPeers && !f.cfg.TorActive {
+		torOnly, err := f.cfg.IsTorOnlyPeer(peer)
+		if err != nil {
+			f.failFundingFlow(peer, cid, err)
+			return
+		}
+
+		if torOnly {
+			log.Infof("Rejecting channel open from tor-only "+
+				"peer %x", peerPubKey.SerializeCompressed())
+
+			f.failFundingFlow(peer, cid, lnwallet.ErrTorOnlyPeer())
+			return
+		}
+	}
+
 	// Send the OpenChannel request to the ChannelAcceptor to determine
 	// whether this node will accept the channel.
 	chanReq := &chanacceptor.ChannelAcceptRequest{
@@ -2032,6 +2472,17 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 		minDepth = 1
 	}
 
+	// If we requested more confirmations than the responder, we'll wait
+	// for our number instead. We never wait for fewer confirmations than
+	// the responder requires, so the higher of both numbers is used.
+	if uint32(resCtx.fundingConfs) > minDepth {
+		log.Infof("Using requested funding confirmation depth of %v "+
+			"for pending_id=%v instead of %v required by responder",
+			resCtx.fundingConfs, cid.tempChanID, minDepth)
+
+		minDepth = uint32(resCtx.fundingConfs)
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
@@ -2405,6 +2856,7 @@ func (f *Manager) fundeeProcessFundingCreated(peer lnpeer.Peer,
 	// The channel is marked IsPending in the database, and can be removed
 	// from the set of active reservations.
 	f.deleteReservationCtx(peerKey, cid.tempChanID)
+	f.markReservationCompleted(cid.tempChanID, completeChan.FundingOutpoint)
 
 	// If something goes wrong before the funding transaction is confirmed,
 	// we use this convenience method to delete the pending OpenChannel
@@ -2648,6 +3100,7 @@ func (f *Manager) funderProcessFundingSigned(peer lnpeer.Peer,
 	// The channel is now marked IsPending in the database, and we can
 	// delete it from our set of active reservations.
 	f.deleteReservationCtx(peerKey, pendingChanID)
+	f.markReservationCompleted(pendingChanID, completeChan.FundingOutpoint)
 
 	// Broadcast the finalized funding transaction to the network, but only
 	// if we actually have the funding transaction.
@@ -4483,10 +4936,34 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		maxCSV = f.cfg.MaxLocalCSVDelay
 	}
 
+	// Make sure the wallet inputs funding this channel have at least the
+	// number of confirmations we require for all channel openings.
+	minConfs := enforceMinInputConfs(msg.MinConfs, f.cfg.MinInputConfs)
+
+	// The number of confirmations of the funding transaction must not be
+	// greater than the maximum number of confirmations the ChainNotifier
+	// is able to dispatch.
+	if uint32(msg.FundingConfs) > chainntnfs.MaxNumConfs {
+		msg.Err <- lnwallet.ErrNumConfsTooLarge(
+			uint32(msg.FundingConfs), chainntnfs.MaxNumConfs,
+		)
+
+		return
+	}
+
+	// Lowering the number of confirmations is only allowed down to the
+	// configured minimum.
+	if msg.FundingConfs != 0 && msg.FundingConfs < f.cfg.MinFundingConfs {
+		msg.Err <- fmt.Errorf("funding confirmations (%v) must be at "+
+			"least %v", msg.FundingConfs, f.cfg.MinFundingConfs)
+
+		return
+	}
+
 	log.Infof("Initiating fundingRequest(local_amt=%v "+
 		"(subtract_fees=%v), push_amt=%v, chain_hash=%v, peer=%x, "+
 		"min_confs=%v)", localAmt, msg.SubtractFees, msg.PushAmt,
-		msg.ChainHash, peerKey.SerializeCompressed(), msg.MinConfs)
+		msg.ChainHash, peerKey.SerializeCompressed(), minConfs)
 
 	// We set the channel flags to indicate whether we want this channel to
 	// be announced to the network.
@@ -4496,6 +4973,16 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		channelFlags = lnwire.FFAnnounceChannel
 	}
 
+	// Don't open another channel with the peer if we already have as many
+	// channels with it as we allow.
+	if err := f.checkMaxChannelsPerPeer(peerKey); err != nil {
+		log.Errorf("Unable to open channel with peer %x: %v",
+			peerKey.SerializeCompressed(), err)
+		msg.Err <- err
+
+		return
+	}
+
 	// If the caller specified their own channel ID, then we'll use that.
 	// Otherwise we'll generate a fresh one as normal.  This will be used
 	// to track this reservation throughout its lifetime.
@@ -4568,6 +5055,17 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		}
 	}
 
+	// A zero-conf channel is used right away, so requiring confirmations
+	// of its funding transaction is contradictory.
+	if zeroConf && msg.FundingConfs != 0 {
+		err = fmt.Errorf("funding confirmations can't be set for " +
+			"zero-conf channel")
+		log.Error(err)
+		msg.Err <- err
+
+		return
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
@@ -4611,7 +5109,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		FundingFeePerKw:   msg.FundingFeePerKw,
 		PushMSat:          msg.PushAmt,
 		Flags:             channelFlags,
-		MinConfs:          msg.MinConfs,
+		MinConfs:          minConfs,
 		CommitType:        commitType,
 		ChanFunder:        msg.ChanFunder,
 		// Unconfirmed Utxos which are marked by the sweeper subsystem
@@ -4634,6 +5132,9 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		OptionScidAlias:  scid,
 		ScidAliasFeature: scidFeatureVal,
 		Memo:             msg.Memo,
+		Account:          msg.Account,
+
+		CoinSelectionStrategy: msg.CoinSelectionStrategy,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -4738,6 +5239,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       maxCSV,
 		channelType:       chanType,
+		fundingConfs:      msg.FundingConfs,
 		reservation:       reservation,
 		peer:              msg.Peer,
 		updates:           msg.Updates,
@@ -4988,6 +5490,18 @@ func (f *Manager) deleteReservationCtx(peerKey *btcec.PublicKey,
 	}
 }
 
+// markReservationCompleted remembers that the reservation with the given
+// pending channel ID completed the funding flow, which means the channel open
+// can no longer be aborted.
+func (f *Manager) markReservationCompleted(pendingChanID [32]byte,
+	chanPoint wire.OutPoint) {
+
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	f.completedReservations[pendingChanID] = chanPoint
+}
+
 // getReservationCtx returns the reservation context for a particular pending
 // channel ID for a target peer.
 func (f *Manager) getReservationCtx(peerKey *btcec.PublicKey,
@@ -5165,3 +5679,18 @@ func (f *Manager) waitForPeerOnline(peerPubkey *btcec.PublicKey) (lnpeer.Peer,
 	}
 	return peer, nil
 }
+
+// enforceMinInputConfs returns the number of confirmations the wallet inputs
+// of a funding transaction must have, given the number requested for the
+// channel open and the configured minimum. Inputs that have exactly the
+// configured number of confirmations remain eligible.
+func enforceMinInputConfs(requested, minInputConfs int32) int32 {
+	if requested >= minInputConfs {
+		return requested
+	}
+
+	log.Debugf("Raising min confs of funding inputs from %v to configured "+
+		"minimum of %v", requested, minInputConfs)
+
+	return minInputConfs
+}
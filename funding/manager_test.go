@@ -3501,19 +3501,98 @@ func TestFundingManagerInvalidChanReserve(t *testing.T) {
 }
 
 // TestFundingManagerMaxPendingChannels checks that trying to open another
-// channel with the same peer when MaxPending channels are pending fails.
+// channel with the same peer when MaxPending channels are pending fails, both
+// if the per peer and the global limit are reached.
 func TestFundingManagerMaxPendingChannels(t *testing.T) {
 	t.Parallel()
 
-	alice, bob := setupFundingManagers(
-		t, func(cfg *Config) {
+	var aliceKey, bobKey [33]byte
+	copy(aliceKey[:], alicePubKey.SerializeCompressed())
+	copy(bobKey[:], bobPubKey.SerializeCompressed())
+
+	t.Run("per peer", func(t *testing.T) {
+		t.Parallel()
+
+		testMaxPendingChannels(t, 0, func(cfg *Config) {
 			cfg.MaxPendingChannels = maxPending
-		},
-	)
+		})
+	})
+
+	t.Run("override raises limit", func(t *testing.T) {
+		t.Parallel()
+
+		testMaxPendingChannels(t, 0, func(cfg *Config) {
+			cfg.MaxPendingChannels = 1
+			cfg.MaxPendingChannelsOverrides = map[[33]byte]int{
+				aliceKey: maxPending,
+			}
+		})
+	})
+
+	t.Run("override lowers limit", func(t *testing.T) {
+		t.Parallel()
+
+		testMaxPendingChannels(t, 0, func(cfg *Config) {
+			cfg.MaxPendingChannels = 2 * maxPending
+			cfg.MaxPendingChannelsOverrides = map[[33]byte]int{
+				aliceKey: maxPending,
+			}
+		})
+	})
+
+	t.Run("override of other peer", func(t *testing.T) {
+		t.Parallel()
+
+		// Bob only opens channels to Alice here, so his override
+		// doesn't apply.
+		testMaxPendingChannels(t, 0, func(cfg *Config) {
+			cfg.MaxPendingChannels = maxPending
+			cfg.MaxPendingChannelsOverrides = map[[33]byte]int{
+				bobKey: 2 * maxPending,
+			}
+		})
+	})
+
+	t.Run("global", func(t *testing.T) {
+		t.Parallel()
+
+		// The global limit can't be lower than any per peer limit, so
+		// Bob already has a channel pending with another peer that
+		// makes him reach the global limit before the one of Alice.
+		testMaxPendingChannels(t, 1, func(cfg *Config) {
+			cfg.MaxPendingChannels = maxPending + 1
+			cfg.MaxPendingChannelsGlobal = maxPending + 1
+		})
+	})
+}
+
+// testMaxPendingChannels asserts that exactly maxPending channels can be
+// pending with the funding managers created with the given config option,
+// while Bob already has the given number of channels pending with other
+// peers.
+func testMaxPendingChannels(t *testing.T, otherPending int,
+	cfgOption func(*Config)) {
+
+	alice, bob := setupFundingManagers(t, cfgOption)
 	t.Cleanup(func() {
 		tearDownFundingManagers(t, alice, bob)
 	})
 
+	// Register the channels pending with other peers as locked
+	// reservations, which are never pruned.
+	for i := 0; i < otherPending; i++ {
+		var peerKey serializedPubKey
+		peerKey[0] = byte(i + 1)
+
+		bob.fundingMgr.resMtx.Lock()
+		bob.fundingMgr.activeReservations[peerKey] = pendingChannels{
+			[32]byte{byte(i + 1)}: {
+				reservation: &lnwallet.ChannelReservation{},
+			},
+		}
+		bob.fundingMgr.resMtx.Unlock()
+	}
+
 	// Create InitFundingMsg structs for maxPending+1 channels.
 	var initReqs []*InitFundingMsg
 	for i := 0; i < maxPending+1; i++ {
@@ -3676,6 +3755,334 @@ func TestFundingManagerMaxPendingChannels(t *testing.T) {
 	).(*lnwire.AcceptChannel)
 }
 
+// TestFundingManagerMaxChannelsPerPeer checks that neither the peer nor we can
+// open more channels than the maximum number of channels per peer, counting
+// the channels that are still pending.
+func TestFundingManagerMaxChannelsPerPeer(t *testing.T) {
+	t.Parallel()
+
+	const maxChannels = 1
+
+	// The pending channel limit is raised, so that only the channel limit
+	// is hit.
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.MaxPendingChannels = maxPending
+		cfg.MaxChannelsPerPeer = maxChannels
+	})
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	newInitReq := func() *InitFundingMsg {
+		return &InitFundingMsg{
+			Peer:            bob,
+			TargetPubkey:    bob.privKey.PubKey(),
+			ChainHash:       *fundingNetParams.GenesisHash,
+			LocalFundingAmt: 5000000,
+			PushAmt:         lnwire.NewMSatFromSatoshis(0),
+			Updates:         make(chan *lnrpc.OpenStatusUpdate),
+			Err:             make(chan error, 1),
+		}
+	}
+
+	// Alice opens the first channel, which Bob accepts.
+	initReq := newInitReq()
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	_ = assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+
+	// Alice can't open another channel with Bob while the first one is
+	// still pending.
+	initReq = newInitReq()
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "max channels per peer is 1")
+
+	case msg := <-alice.msgChan:
+		t.Fatalf("expected error, alice sent %T", msg)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail the funding workflow")
+	}
+
+	// Bob also rejects another channel from Alice, telling her why.
+	secondOpen := *openChannelReq
+	secondOpen.PendingChannelID = [32]byte{1}
+	bob.fundingMgr.ProcessFundingMsg(&secondOpen, alice)
+
+	errMsg := assertFundingMsgSent(t, bob.msgChan, "Error").(*lnwire.Error)
+	require.Contains(t, string(errMsg.Data), "max channels per peer is 1")
+}
+
+// TestFundingManagerAbortChannelOpen tests that a channel open can be aborted
+// until it passes the point of no return.
+func TestFundingManagerAbortChannelOpen(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// Alice starts a channel open, which Bob accepts.
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		PushAmt:         lnwire.NewMSatFromSatoshis(0),
+		Updates:         make(chan *lnrpc.OpenStatusUpdate),
+		Err:             make(chan error, 1),
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	_ = assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+
+	// Alice aborts the channel open before signing anything. The funding
+	// flow fails and Bob is told about it. The test peers deliver
+	// messages synchronously, so the abort must run in the background.
+	pendingChanID := openChannelReq.PendingChannelID
+	abortErr := make(chan error, 1)
+	go func() {
+		abortErr <- alice.fundingMgr.AbortChannelOpen(pendingChanID)
+	}()
+
+	errMsg := assertFundingMsgSent(t, alice.msgChan, "Error").(*lnwire.Error)
+	require.Equal(t, lnwire.ChannelID(pendingChanID), errMsg.ChanID)
+	bob.fundingMgr.ProcessFundingMsg(errMsg, alice)
+
+	select {
+	case err := <-abortErr:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("abort did not complete")
+	}
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorIs(t, err, ErrChannelOpenAborted)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail the funding workflow")
+	}
+
+	assertNumPendingReservations(t, alice, bob.privKey.PubKey(), 0)
+
+	// The channel open is gone, so it can't be aborted again.
+	err := alice.fundingMgr.AbortChannelOpen(pendingChanID)
+	require.ErrorIs(t, err, ErrUnknownChannelOpen)
+
+	// Once a channel open completed the funding flow, the funding
+	// transaction may be broadcast, so it's too late to abort it.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	_, _ = openChannel(
+		t, alice, bob, 500000, 0, 1, updateChan, true, nil,
+	)
+
+	_, err = alice.fundingMgr.AbortPeerChannelOpens(bob.privKey.PubKey())
+	require.ErrorIs(t, err, ErrAbortTooLate)
+}
+
+// TestFundingManagerFundingConfs tests that the funder waits for the number of
+// funding confirmations it requested, unless the responder requires more.
+func TestFundingManagerFundingConfs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		fundingConfs uint16
+		expectedConf uint16
+	}{
+		{
+			// Bob requires 3 confirmations, which is used if we
+			// don't request any.
+			name:         "default",
+			fundingConfs: 0,
+			expectedConf: 3,
+		},
+		{
+			// Alice never waits for fewer confirmations than Bob
+			// requires.
+			name:         "fewer than responder",
+			fundingConfs: 1,
+			expectedConf: 3,
+		},
+		{
+			name:         "more than responder",
+			fundingConfs: 5,
+			expectedConf: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testFundingConfs(t, tc.fundingConfs, tc.expectedConf)
+		})
+	}
+
+	// A number of confirmations the chain notifier can't dispatch is
+	// rejected.
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingConfs:    chainntnfs.MaxNumConfs + 1,
+		Updates:         make(chan *lnrpc.OpenStatusUpdate),
+		Err:             make(chan error, 1),
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "too large")
+
+	case msg := <-alice.msgChan:
+		t.Fatalf("expected error, alice sent %T", msg)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail the funding workflow")
+	}
+}
+
+// TestFundingManagerMinFundingConfs tests that a funding request asking for
+// fewer confirmations than the configured minimum is rejected.
+func TestFundingManagerMinFundingConfs(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.MinFundingConfs = 2
+	})
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingConfs:    1,
+		Updates:         make(chan *lnrpc.OpenStatusUpdate),
+		Err:             make(chan error, 1),
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	select {
+	case err := <-initReq.Err:
+		require.ErrorContains(t, err, "must be at least 2")
+
+	case msg := <-alice.msgChan:
+		t.Fatalf("expected error, alice sent %T", msg)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not fail the funding workflow")
+	}
+}
+
+// testFundingConfs runs a funding flow in which Alice requests the given
+// number of funding confirmations, and asserts the number of confirmations
+// her pending channel waits for.
+func testFundingConfs(t *testing.T, fundingConfs, expectedConfs uint16) {
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingFeePerKw: 1000,
+		FundingConfs:    fundingConfs,
+		Updates:         updateChan,
+		Err:             make(chan error, 1),
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+	acceptChannel := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+
+	alice.fundingMgr.ProcessFundingMsg(acceptChannel, bob)
+	fundingCreated := assertFundingMsgSent(
+		t, alice.msgChan, "FundingCreated",
+	).(*lnwire.FundingCreated)
+
+	bob.fundingMgr.ProcessFundingMsg(fundingCreated, alice)
+	fundingSigned := assertFundingMsgSent(
+		t, bob.msgChan, "FundingSigned",
+	).(*lnwire.FundingSigned)
+
+	alice.fundingMgr.ProcessFundingMsg(fundingSigned, bob)
+
+	select {
+	case <-updateChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error during funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenStatusUpdate_ChanPending")
+	}
+
+	pendingChannels, err := alice.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChannels, 1)
+	require.Equal(t, expectedConfs, pendingChannels[0].NumConfsRequired)
+}
+
 // TestFundingManagerRejectPush checks behaviour of 'rejectpush'
 // option, namely that non-zero incoming push amounts are disabled.
 func TestFundingManagerRejectPush(t *testing.T) {
@@ -3740,6 +4147,271 @@ func TestFundingManagerRejectPush(t *testing.T) {
 	)
 }
 
+// TestFundingManagerRejectTorOnlyPeers checks that incoming channels from
+// peers that are only reachable over Tor are rejected, unless Tor is active.
+func TestFundingManagerRejectTorOnlyPeers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		torActive bool
+		accept    bool
+	}{{
+		name:      "tor inactive",
+		torActive: false,
+		accept:    false,
+	}, {
+		name:      "tor active",
+		torActive: true,
+		accept:    true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRejectTorOnlyPeers(t, tc.torActive, tc.accept)
+		})
+	}
+}
+
+// testRejectTorOnlyPeers asserts whether an incoming channel from a peer that
+// is only reachable over Tor is accepted, with funding.reject-tor-only-peers
+// set.
+func testRejectTorOnlyPeers(t *testing.T, torActive, accept bool) {
+	// Consider all peers Tor-only and initialize funding managers.
+	alice, bob := setupFundingManagers(
+		t, func(cfg *Config) {
+			cfg.RejectTorOnlyPeers = true
+			cfg.TorActive = torActive
+			cfg.IsTorOnlyPeer = func(lnpeer.Peer) (bool, error) {
+				return true, nil
+			}
+		},
+	)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// Create a funding request and start the workflow.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		Private:         true,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	// Alice should have sent the OpenChannel message to Bob.
+	var aliceMsg lnwire.Message
+	select {
+	case aliceMsg = <-alice.msgChan:
+	case err := <-initReq.Err:
+		t.Fatalf("error init funding workflow: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenChannel message")
+	}
+
+	openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+	require.True(t, ok, "expected OpenChannel, got %T", aliceMsg)
+
+	// Let Bob handle the init message.
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+
+	// With Tor active, Bob can reach Alice himself and accepts the
+	// channel.
+	if accept {
+		assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
+		return
+	}
+
+	// Otherwise, Bob responds with an ErrTorOnlyPeer error.
+	err := assertFundingMsgSent(t, bob.msgChan, "Error").(*lnwire.Error)
+	require.ErrorContains(
+		t, err, "channels from tor-only peers are not accepted",
+	)
+}
+
+// TestFundingManagerMinChanSizeOverrides checks that the per peer and per
+// network overrides of the minimum channel size are enforced for incoming
+// channels, and only for the peers they apply to.
+func TestFundingManagerMinChanSizeOverrides(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = 1_000_000
+		chanSize    = 500_000
+		lowMin      = 100_000
+	)
+
+	var aliceKey, bobKey [33]byte
+	copy(aliceKey[:], alicePubKey.SerializeCompressed())
+	copy(bobKey[:], bobPubKey.SerializeCompressed())
+
+	mustParseCIDR := func(rawNet string) *net.IPNet {
+		_, ipNet, err := net.ParseCIDR(rawNet)
+		require.NoError(t, err)
+
+		return ipNet
+	}
+
+	// Both Alice and Bob connect from 10.0.0.2.
+	testCases := []struct {
+		name         string
+		peerOverride map[[33]byte]btcutil.Amount
+		netOverrides []NetMinChanSize
+		loopback     bool
+		accept       bool
+	}{{
+		name:   "no override",
+		accept: false,
+	}, {
+		name: "peer override",
+		peerOverride: map[[33]byte]btcutil.Amount{
+			aliceKey: lowMin,
+		},
+		accept: true,
+	}, {
+		name: "override of other peer",
+		peerOverride: map[[33]byte]btcutil.Amount{
+			bobKey: lowMin,
+		},
+		accept: false,
+	}, {
+		name: "network override",
+		netOverrides: []NetMinChanSize{{
+			Net:         mustParseCIDR("10.0.0.0/8"),
+			MinChanSize: lowMin,
+		}},
+		accept: true,
+	}, {
+		name: "override of other network",
+		netOverrides: []NetMinChanSize{{
+			Net:         mustParseCIDR("192.0.2.0/24"),
+			MinChanSize: lowMin,
+		}},
+		accept: false,
+	}, {
+		name: "most specific network",
+		netOverrides: []NetMinChanSize{{
+			Net:         mustParseCIDR("10.0.0.0/8"),
+			MinChanSize: lowMin,
+		}, {
+			Net:         mustParseCIDR("10.0.0.0/24"),
+			MinChanSize: minChanSize,
+		}},
+		accept: false,
+	}, {
+		name: "peer override before network",
+		peerOverride: map[[33]byte]btcutil.Amount{
+			aliceKey: minChanSize,
+		},
+		netOverrides: []NetMinChanSize{{
+			Net:         mustParseCIDR("10.0.0.0/8"),
+			MinChanSize: lowMin,
+		}},
+		accept: false,
+	}, {
+		// Peers connecting through our onion service come from the
+		// loopback interface, which never matches.
+		name: "loopback connection",
+		netOverrides: []NetMinChanSize{{
+			Net:         mustParseCIDR("0.0.0.0/0"),
+			MinChanSize: lowMin,
+		}},
+		loopback: true,
+		accept:   false,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			alice, bob := setupFundingManagers(
+				t, func(cfg *Config) {
+					cfg.MinChanSize = minChanSize
+					cfg.MinChanSizeOverrides =
+						tc.peerOverride
+					cfg.MinChanSizeNetOverrides =
+						tc.netOverrides
+				},
+			)
+			t.Cleanup(func() {
+				tearDownFundingManagers(t, alice, bob)
+			})
+
+			if tc.loopback {
+				alice.addr = &lnwire.NetAddress{
+					IdentityKey: alicePubKey,
+					Address: &net.TCPAddr{
+						IP:   net.IPv4(127, 0, 0, 1),
+						Port: 9001,
+					},
+				}
+			}
+
+			initReq := &InitFundingMsg{
+				Peer:            bob,
+				TargetPubkey:    bob.privKey.PubKey(),
+				ChainHash:       *fundingNetParams.GenesisHash,
+				LocalFundingAmt: chanSize,
+				Private:         true,
+				Updates: make(
+					chan *lnrpc.OpenStatusUpdate,
+				),
+				Err: make(chan error, 1),
+			}
+			alice.fundingMgr.InitFundingWorkflow(initReq)
+
+			// Alice should have sent the OpenChannel message to
+			// Bob.
+			var aliceMsg lnwire.Message
+			select {
+			case aliceMsg = <-alice.msgChan:
+			case err := <-initReq.Err:
+				t.Fatalf("error init funding workflow: %v",
+					err)
+			case <-time.After(time.Second * 5):
+				t.Fatalf("alice did not send OpenChannel " +
+					"message")
+			}
+
+			openChannelReq, ok := aliceMsg.(*lnwire.OpenChannel)
+			require.True(
+				t, ok, "expected OpenChannel, got %T", aliceMsg,
+			)
+
+			// Let Bob handle the init message.
+			bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+
+			if tc.accept {
+				assertFundingMsgSent(
+					t, bob.msgChan, "AcceptChannel",
+				)
+
+				return
+			}
+
+			err := assertFundingMsgSent(
+				t, bob.msgChan, "Error",
+			).(*lnwire.Error)
+			require.ErrorContains(
+				t, err, "is below min chan size",
+			)
+		})
+	}
+}
+
 // TestFundingManagerMaxConfs ensures that we don't accept a funding proposal
 // that proposes a MinAcceptDepth greater than the maximum number of
 // confirmations we're willing to accept.
@@ -4961,3 +5633,48 @@ func TestFundingManagerCoinbase(t *testing.T) {
 	// channel.
 	assertHandleChannelReady(t, alice, bob)
 }
+
+// TestEnforceMinInputConfs tests that the configured minimum number of
+// confirmations for funding inputs overrides lower requested values only.
+func TestEnforceMinInputConfs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		requested     int32
+		minInputConfs int32
+		expected      int32
+	}{{
+		name:          "no minimum configured",
+		requested:     0,
+		minInputConfs: 0,
+		expected:      0,
+	}, {
+		name:          "unconfirmed inputs raised",
+		requested:     0,
+		minInputConfs: 3,
+		expected:      3,
+	}, {
+		name:          "requested equals minimum",
+		requested:     3,
+		minInputConfs: 3,
+		expected:      3,
+	}, {
+		name:          "requested above minimum",
+		requested:     6,
+		minInputConfs: 3,
+		expected:      6,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, enforceMinInputConfs(
+				tc.requested, tc.minInputConfs,
+			))
+		})
+	}
+}
@@ -203,6 +203,14 @@ replace github.com/gogo/protobuf => github.com/gogo/protobuf v1.3.2
 // allows us to specify that as an option.
 replace google.golang.org/protobuf => github.com/lightninglabs/protobuf-go-hex-display v1.30.0-hex-display
 
+// The database compaction and transaction retries depend on changes to the
+// kvdb module that are not part of a tagged version yet.
+replace github.com/lightningnetwork/lnd/kvdb => ./kvdb
+
+// The invoice SQL store depends on queries that are not part of a tagged
+// version of the sqldb module yet.
+replace github.com/lightningnetwork/lnd/sqldb => ./sqldb
+
 // If you change this please also update .github/pull_request_template.md and
 // docs/INSTALL.md.
 go 1.21.4
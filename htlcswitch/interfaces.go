@@ -239,6 +239,11 @@ type ChannelLink interface {
 	// policy to govern if it an incoming HTLC should be forwarded or not.
 	UpdateForwardingPolicy(models.ForwardingPolicy)
 
+	// ForwardingPolicy returns the forwarding policy that the ChannelLink
+	// currently uses to govern whether an incoming HTLC should be
+	// forwarded or not.
+	ForwardingPolicy() models.ForwardingPolicy
+
 	// CheckHtlcForward should return a nil error if the passed HTLC details
 	// satisfy the current forwarding policy fo the target link. Otherwise,
 	// a LinkError with a valid protocol failure message should be returned
@@ -18,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
@@ -65,6 +66,25 @@ const (
 	DefaultMaxLinkFeeAllocation float64 = 0.5
 )
 
+// MaxCltvExpiryPolicy determines how a link handles forwarded HTLCs whose
+// outgoing time lock exceeds the configured MaxOutgoingCltvExpiry.
+type MaxCltvExpiryPolicy string
+
+const (
+	// MaxCltvExpiryPolicyFail fails HTLCs that exceed the maximum outgoing
+	// time lock back to the sender with an expiry_too_far error.
+	MaxCltvExpiryPolicyFail MaxCltvExpiryPolicy = "fail"
+
+	// MaxCltvExpiryPolicyClamp lowers the outgoing time lock of HTLCs that
+	// exceed the maximum to the maximum and forwards them, as long as the
+	// lowered time lock still leaves the HTLC enough time before it
+	// expires. Lowering the outgoing time lock only widens the gap to the
+	// incoming time lock, so our funds are never put at risk. The next
+	// hop may still reject the HTLC if the lowered time lock no longer
+	// matches its onion payload.
+	MaxCltvExpiryPolicyClamp MaxCltvExpiryPolicy = "clamp"
+)
+
 // ExpectedFee computes the expected fee for a given htlc amount. The value
 // returned from this function is to be used as a sanity check when forwarding
 // HTLC's to ensure that an incoming HTLC properly adheres to our propagated
@@ -228,6 +248,11 @@ type ChannelLinkConfig struct {
 	// current block height.
 	MaxOutgoingCltvExpiry uint32
 
+	// MaxOutgoingCltvExpiryPolicy determines how forwarded HTLCs whose
+	// outgoing time lock exceeds MaxOutgoingCltvExpiry are handled. If
+	// not set, such HTLCs are failed.
+	MaxOutgoingCltvExpiryPolicy MaxCltvExpiryPolicy
+
 	// MaxFeeAllocation is the highest allocation we'll allow a channel's
 	// commitment fee to be of its balance. This only applies to the
 	// initiator of the channel.
@@ -253,6 +278,11 @@ type ChannelLinkConfig struct {
 	// ChannelNotifier when a channel link become inactive.
 	NotifyInactiveLinkEvent func(wire.OutPoint)
 
+	// NotifyReestablishFailed allows the link to tell the ChannelNotifier
+	// when the channel state couldn't be synchronized with the peer.
+	NotifyReestablishFailed func(wire.OutPoint,
+		channelnotifier.ReestablishFailureReason, error, bool)
+
 	// HtlcNotifier is an instance of a htlcNotifier which we will pipe htlc
 	// events through.
 	HtlcNotifier htlcNotifier
@@ -278,6 +308,57 @@ type ChannelLinkConfig struct {
 	// by failing back any blinding-related payloads as if they were
 	// invalid.
 	DisallowRouteBlinding bool
+
+	// ManualDataLossRecovery, if set, prevents the link from force closing
+	// the channel if the peer's channel reestablish message shows that it
+	// lost state or sent invalid commitment secrets. The channel is only
+	// failed instead, leaving the decision to force close it to the
+	// operator.
+	ManualDataLossRecovery bool
+}
+
+// syncFailureReason maps an error returned when syncing the channel states
+// with the peer to the reason reported to the ChannelNotifier.
+func syncFailureReason(err error) channelnotifier.ReestablishFailureReason {
+	_, localDataLoss := err.(*lnwallet.ErrCommitSyncLocalDataLoss)
+
+	switch {
+	case errors.Is(err, lnwallet.ErrCommitSyncRemoteDataLoss):
+		return channelnotifier.ReestablishFailureRemoteDataLoss
+
+	case localDataLoss:
+		return channelnotifier.ReestablishFailureLocalDataLoss
+
+	case errors.Is(err, lnwallet.ErrInvalidLastCommitSecret):
+		return channelnotifier.ReestablishFailureInvalidCommitSecret
+
+	case errors.Is(err, lnwallet.ErrInvalidLocalUnrevokedCommitPoint):
+		return channelnotifier.ReestablishFailureInvalidCommitPoint
+
+	case errors.Is(err, lnwallet.ErrCannotSyncCommitChains):
+		return channelnotifier.ReestablishFailureCannotSyncCommitChains
+
+	default:
+		return channelnotifier.ReestablishFailureUnknown
+	}
+}
+
+// syncFailureForceCloses returns true if a sync failure with the given reason
+// leads to the channel being force closed, unless the operator opted into
+// manual data loss recovery.
+func syncFailureForceCloses(
+	reason channelnotifier.ReestablishFailureReason) bool {
+
+	switch reason {
+	case channelnotifier.ReestablishFailureRemoteDataLoss,
+		channelnotifier.ReestablishFailureInvalidCommitSecret,
+		channelnotifier.ReestablishFailureInvalidCommitPoint:
+
+		return true
+
+	default:
+		return false
+	}
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -1112,6 +1193,19 @@ func (l *channelLink) htlcManager() {
 			errDataLoss, localDataLoss :=
 				err.(*lnwallet.ErrCommitSyncLocalDataLoss)
 
+			// Let subscribers know that the channel couldn't be
+			// reestablished, unless we're just shutting down.
+			if err != ErrLinkShuttingDown {
+				reason := syncFailureReason(err)
+				forceClose := !l.cfg.ManualDataLossRecovery &&
+					syncFailureForceCloses(reason)
+
+				l.cfg.NotifyReestablishFailed(
+					l.ChannelPoint(), reason, err,
+					forceClose,
+				)
+			}
+
 			switch {
 			case err == ErrLinkShuttingDown:
 				l.log.Debugf("unable to sync channel states, " +
@@ -1134,6 +1228,17 @@ func (l *channelLink) htlcManager() {
 			// what they sent us before.
 			// TODO(halseth): ban peer?
 			case err == lnwallet.ErrInvalidLocalUnrevokedCommitPoint:
+				// If the operator wants to review such
+				// channels, we'll only fail the link below
+				// without force closing the channel.
+				if l.cfg.ManualDataLossRecovery {
+					l.log.Errorf("Channel requires "+
+						"manual review, not force "+
+						"closing it: %v", err)
+
+					break
+				}
+
 				// We'll fail the link and tell the peer to
 				// force close the channel. Note that the
 				// database state is not updated here, but will
@@ -2813,6 +2918,18 @@ func (l *channelLink) UpdateForwardingPolicy(
 	l.cfg.FwrdingPolicy = newPolicy
 }
 
+// ForwardingPolicy returns the forwarding policy that the link currently uses
+// to govern whether an incoming HTLC should be forwarded or not. This reflects
+// any policy updates that were applied after the link was created.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ForwardingPolicy() models.ForwardingPolicy {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.cfg.FwrdingPolicy
+}
+
 // CheckHtlcForward should return a nil error if the passed HTLC details
 // satisfy the current forwarding policy fo the target link. Otherwise,
 // a LinkError with a valid protocol failure message should be returned
@@ -2980,8 +3097,10 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 	// Check absolute max delta.
 	if timeout > l.cfg.MaxOutgoingCltvExpiry+heightNow {
 		l.log.Warnf("outgoing htlc(%x) has a time lock too far in "+
-			"the future: got %v, but maximum is %v", payHash[:],
-			timeout-heightNow, l.cfg.MaxOutgoingCltvExpiry)
+			"the future: got %v, but maximum is %v, "+
+			"outgoing_expiry=%v, best_height=%v", payHash[:],
+			timeout-heightNow, l.cfg.MaxOutgoingCltvExpiry,
+			timeout, heightNow)
 
 		return NewLinkError(&lnwire.FailExpiryTooFar{})
 	}
@@ -3002,6 +3121,56 @@ func (l *channelLink) canSendHtlc(policy models.ForwardingPolicy,
 	return nil
 }
 
+// clampOutgoingExpiry returns the outgoing time lock to use for the forwarded
+// HTLC. If the link is configured to clamp HTLCs that exceed the maximum
+// outgoing time lock, a time lock beyond the maximum is lowered to the
+// maximum. The original time lock is returned if the clamped value wouldn't be
+// safe, in which case the HTLC is failed by the regular forwarding checks.
+func (l *channelLink) clampOutgoingExpiry(pd *lnwallet.PaymentDescriptor,
+	outgoingTimeout, heightNow uint32) uint32 {
+
+	if l.cfg.MaxOutgoingCltvExpiryPolicy != MaxCltvExpiryPolicyClamp {
+		return outgoingTimeout
+	}
+
+	maxTimeout := heightNow + l.cfg.MaxOutgoingCltvExpiry
+	if outgoingTimeout <= maxTimeout {
+		return outgoingTimeout
+	}
+
+	// An incoming HTLC that expires before the outgoing one violates any
+	// forwarding policy. We don't want a lowered time lock to hide that,
+	// so we leave it to the forwarding checks to fail the HTLC.
+	if pd.Timeout < outgoingTimeout {
+		l.log.Warnf("not clamping outgoing htlc(%x) with "+
+			"incoming_htlc_id=%v: incoming_expiry=%v is below "+
+			"outgoing_expiry=%v", pd.RHash[:], pd.HtlcIndex,
+			pd.Timeout, outgoingTimeout)
+
+		return outgoingTimeout
+	}
+
+	// We also never offer an HTLC that expires too soon, as that would
+	// risk a channel closure.
+	if maxTimeout <= heightNow+l.cfg.OutgoingCltvRejectDelta {
+		l.log.Warnf("not clamping outgoing htlc(%x) with "+
+			"incoming_htlc_id=%v: clamped expiry %v is too soon, "+
+			"best_height=%v", pd.RHash[:], pd.HtlcIndex,
+			maxTimeout, heightNow)
+
+		return outgoingTimeout
+	}
+
+	l.log.Infof("clamping outgoing htlc(%x) with incoming_htlc_id=%v: "+
+		"outgoing_expiry=%v (%v blocks) exceeds maximum of %v "+
+		"blocks, forwarding with expiry=%v, incoming_expiry=%v, "+
+		"best_height=%v", pd.RHash[:], pd.HtlcIndex, outgoingTimeout,
+		outgoingTimeout-heightNow, l.cfg.MaxOutgoingCltvExpiry,
+		maxTimeout, pd.Timeout, heightNow)
+
+	return maxTimeout
+}
+
 // Stats returns the statistics of channel link.
 //
 // NOTE: Part of the ChannelLink interface.
@@ -3414,6 +3583,13 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				continue
 			}
 
+			// Depending on our policy, an outgoing time lock that
+			// is too far in the future may be lowered before the
+			// HTLC is handed to the switch.
+			fwdInfo.OutgoingCTLV = l.clampOutgoingExpiry(
+				pd, fwdInfo.OutgoingCTLV, heightNow,
+			)
+
 			switch fwdPkg.State {
 			case channeldb.FwdStateProcessed:
 				// This add was not forwarded on the previous
@@ -26,6 +26,7 @@ import (
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
@@ -1983,6 +1984,57 @@ func TestChannelLinkExpiryTooSoonMidNode(t *testing.T) {
 	}
 }
 
+// TestChannelLinkExpiryTooFarMidNode tests that a forwarded HTLC whose
+// outgoing time lock exceeds the maximum of the middle hop is failed back by
+// that hop with an expiry_too_far error instead of being forwarded.
+func TestChannelLinkExpiryTooFarMidNode(t *testing.T) {
+	t.Parallel()
+
+	channels, _, err := createClusterChannels(
+		t, btcutil.SatoshiPerBitcoin*3, btcutil.SatoshiPerBitcoin*5,
+	)
+	require.NoError(t, err, "unable to create channel")
+
+	n := newThreeHopNetwork(t, channels.aliceToBob, channels.bobToAlice,
+		channels.bobToCarol, channels.carolToBob, testStartingHeight)
+
+	// Bob's outgoing time lock towards Carol will be the invoice's final
+	// CLTV delta, so we lower his maximum just below it.
+	n.secondBobChannelLink.cfg.MaxOutgoingCltvExpiry =
+		testInvoiceCltvExpiry - 1
+
+	if err := n.start(); err != nil {
+		t.Fatalf("unable to start three hop network: %v", err)
+	}
+	t.Cleanup(n.stop)
+
+	carolBandwidthBefore := n.carolChannelLink.Bandwidth()
+	secondBobBandwidthBefore := n.secondBobChannelLink.Bandwidth()
+
+	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
+	htlcAmt, totalTimelock, hops := generateHops(amount,
+		testStartingHeight, n.firstBobChannelLink, n.carolChannelLink)
+
+	firstHop := n.firstBobChannelLink.ShortChanID()
+	_, err = makePayment(
+		n.aliceServer, n.carolServer, firstHop, hops, amount, htlcAmt,
+		totalTimelock,
+	).Wait(30 * time.Second)
+	require.Error(t, err, "payment should have failed")
+
+	// The failure must originate from Bob, the first hop of the route.
+	var fwdErr *ForwardingError
+	require.ErrorAs(t, err, &fwdErr)
+	require.Equal(t, 1, fwdErr.FailureSourceIdx)
+	require.IsType(t, &lnwire.FailExpiryTooFar{}, fwdErr.WireMessage())
+
+	// Nothing should have been forwarded to Carol.
+	require.Equal(
+		t, secondBobBandwidthBefore, n.secondBobChannelLink.Bandwidth(),
+	)
+	require.Equal(t, carolBandwidthBefore, n.carolChannelLink.Bandwidth())
+}
+
 // TestChannelLinkSingleHopMessageOrdering test checks ordering of message which
 // flying around between Alice and Bob are correct when Bob sends payments to
 // Alice.
@@ -2231,6 +2283,7 @@ func newSingleLinkTestHarness(t *testing.T, chanAmt,
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifyReestablishFailed: noopReestablishFailed,
 		HtlcNotifier:            aliceSwitch.cfg.HtlcNotifier,
 		GetAliases:              getAliases,
 	}
@@ -4894,6 +4947,7 @@ func (h *persistentLinkHarness) restartLink(
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifyReestablishFailed: noopReestablishFailed,
 		HtlcNotifier:            h.hSwitch.cfg.HtlcNotifier,
 		SyncStates:              syncStates,
 		GetAliases:              getAliases,
@@ -7489,3 +7543,283 @@ func TestLinkFlushHooksCalled(t *testing.T) {
 	ctx.receiveRevAndAckAliceToBob()
 	assertHookCalled(true)
 }
+
+// TestSyncFailureReason asserts that errors returned when syncing the channel
+// states are mapped to the correct reestablish failure reason, and that only
+// the expected reasons lead to a force close.
+func TestSyncFailureReason(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		err        error
+		reason     channelnotifier.ReestablishFailureReason
+		forceClose bool
+	}{
+		{
+			name: "remote data loss",
+			err:  lnwallet.ErrCommitSyncRemoteDataLoss,
+			reason: channelnotifier.
+				ReestablishFailureRemoteDataLoss,
+			forceClose: true,
+		},
+		{
+			name: "local data loss",
+			err:  &lnwallet.ErrCommitSyncLocalDataLoss{},
+			reason: channelnotifier.
+				ReestablishFailureLocalDataLoss,
+		},
+		{
+			name: "invalid commit secret",
+			err:  lnwallet.ErrInvalidLastCommitSecret,
+			reason: channelnotifier.
+				ReestablishFailureInvalidCommitSecret,
+			forceClose: true,
+		},
+		{
+			name: "invalid commit point",
+			err:  lnwallet.ErrInvalidLocalUnrevokedCommitPoint,
+			reason: channelnotifier.
+				ReestablishFailureInvalidCommitPoint,
+			forceClose: true,
+		},
+		{
+			name: "cannot sync commit chains",
+			err:  lnwallet.ErrCannotSyncCommitChains,
+			reason: channelnotifier.
+				ReestablishFailureCannotSyncCommitChains,
+		},
+		{
+			name: "unknown",
+			err:  fmt.Errorf("peer didn't respond"),
+			reason: channelnotifier.
+				ReestablishFailureUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reason := syncFailureReason(tc.err)
+			require.Equal(t, tc.reason, reason)
+
+			forceClose := syncFailureForceCloses(reason)
+			require.Equal(t, tc.forceClose, forceClose)
+		})
+	}
+}
+
+// TestChannelLinkDataLossPolicy asserts that a link force closes the channel
+// if the peer's channel reestablish message contains an invalid commitment
+// secret, unless it's configured for manual data loss recovery, in which case
+// the channel is only failed.
+func TestChannelLinkDataLossPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		manual     bool
+		code       errorCode
+		action     LinkFailureAction
+		forceClose bool
+	}{
+		{
+			name:       "force close",
+			code:       ErrSyncError,
+			action:     LinkFailureForceClose,
+			forceClose: true,
+		},
+		{
+			name:   "manual",
+			manual: true,
+			code:   ErrRecoveryError,
+			action: LinkFailureForceNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testDataLossPolicy(
+				t, tc.manual, tc.code, tc.action, tc.forceClose,
+			)
+		})
+	}
+}
+
+func testDataLossPolicy(t *testing.T, manual bool, code errorCode,
+	action LinkFailureAction, forceClose bool) {
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	harness, err := newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	err = harness.start()
+	require.NoError(t, err)
+
+	alice := newPersistentLinkHarness(
+		t, harness.aliceSwitch, harness.aliceLink,
+		harness.aliceBatchTicker, harness.aliceRestore,
+	)
+
+	// Restart Alice so she sends and accepts ChannelReestablish.
+	alice.restart(false, true)
+	t.Cleanup(alice.link.Stop)
+
+	linkErrors := make(chan LinkFailureError, 1)
+	alice.coreLink.cfg.OnChannelFailure = func(_ lnwire.ChannelID,
+		_ lnwire.ShortChannelID, linkErr LinkFailureError) {
+
+		linkErrors <- linkErr
+	}
+
+	notifiedForceClose := make(chan bool, 1)
+	alice.coreLink.cfg.NotifyReestablishFailed = func(_ wire.OutPoint,
+		_ channelnotifier.ReestablishFailureReason, _ error,
+		forceClose bool) {
+
+		notifiedForceClose <- forceClose
+	}
+	alice.coreLink.cfg.ManualDataLossRecovery = manual
+
+	// --reestablish->
+	select {
+	case msg := <-alice.msgs:
+		_, ok := msg.(*lnwire.ChannelReestablish)
+		require.True(t, ok)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("did not receive message")
+	}
+
+	// <-reestablish--
+	// Bob claims to have received a revocation from Alice, but sends an
+	// invalid commitment secret for it.
+	bobReest, err := harness.bobChannel.State().ChanSyncMsg()
+	require.NoError(t, err)
+	bobReest.RemoteCommitTailHeight = 1
+	bobReest.LastRemoteCommitSecret = [32]byte{1}
+	alice.link.HandleChannelUpdate(bobReest)
+
+	select {
+	case linkErr := <-linkErrors:
+		require.Equal(t, code, linkErr.code)
+		require.Equal(t, action, linkErr.FailureAction)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("link wasn't failed")
+	}
+
+	select {
+	case notified := <-notifiedForceClose:
+		require.Equal(t, forceClose, notified)
+
+	case <-time.After(15 * time.Second):
+		t.Fatalf("reestablish failure wasn't notified")
+	}
+}
+
+// TestChannelLinkClampOutgoingExpiry tests that the outgoing time lock of a
+// forwarded HTLC is only lowered to the maximum if the link is configured to
+// clamp and doing so is safe.
+func TestChannelLinkClampOutgoingExpiry(t *testing.T) {
+	t.Parallel()
+
+	const (
+		heightNow    = 100
+		maxExpiry    = DefaultMaxOutgoingCltvExpiry
+		rejectDelta  = 13
+		maxTimeout   = heightNow + maxExpiry
+		tooFarExpiry = maxTimeout + 10
+	)
+
+	testCases := []struct {
+		name            string
+		policy          MaxCltvExpiryPolicy
+		maxExpiry       uint32
+		incomingTimeout uint32
+		outgoingTimeout uint32
+		expectedTimeout uint32
+	}{
+		{
+			name:            "unset policy fails",
+			incomingTimeout: tooFarExpiry + 40,
+			outgoingTimeout: tooFarExpiry,
+			expectedTimeout: tooFarExpiry,
+		},
+		{
+			name:            "fail policy",
+			policy:          MaxCltvExpiryPolicyFail,
+			incomingTimeout: tooFarExpiry + 40,
+			outgoingTimeout: tooFarExpiry,
+			expectedTimeout: tooFarExpiry,
+		},
+		{
+			name:            "clamp within maximum",
+			policy:          MaxCltvExpiryPolicyClamp,
+			incomingTimeout: maxTimeout + 40,
+			outgoingTimeout: maxTimeout,
+			expectedTimeout: maxTimeout,
+		},
+		{
+			name:            "clamp beyond maximum",
+			policy:          MaxCltvExpiryPolicyClamp,
+			incomingTimeout: tooFarExpiry + 40,
+			outgoingTimeout: tooFarExpiry,
+			expectedTimeout: maxTimeout,
+		},
+		{
+			name:            "clamp incoming below outgoing",
+			policy:          MaxCltvExpiryPolicyClamp,
+			incomingTimeout: tooFarExpiry - 1,
+			outgoingTimeout: tooFarExpiry,
+			expectedTimeout: tooFarExpiry,
+		},
+		{
+			name:            "clamp expiry too soon",
+			policy:          MaxCltvExpiryPolicyClamp,
+			maxExpiry:       rejectDelta,
+			incomingTimeout: tooFarExpiry + 40,
+			outgoingTimeout: tooFarExpiry,
+			expectedTimeout: tooFarExpiry,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfgMaxExpiry := uint32(maxExpiry)
+			if testCase.maxExpiry != 0 {
+				cfgMaxExpiry = testCase.maxExpiry
+			}
+
+			link := &channelLink{
+				cfg: ChannelLinkConfig{
+					MaxOutgoingCltvExpiry:       cfgMaxExpiry,
+					MaxOutgoingCltvExpiryPolicy: testCase.policy,
+					OutgoingCltvRejectDelta:     rejectDelta,
+				},
+				log: log,
+			}
+
+			pd := &lnwallet.PaymentDescriptor{
+				Timeout: testCase.incomingTimeout,
+			}
+
+			timeout := link.clampOutgoingExpiry(
+				pd, testCase.outgoingTimeout, heightNow,
+			)
+			require.Equal(t, testCase.expectedTimeout, timeout)
+		})
+	}
+}
@@ -734,6 +734,8 @@ type mockChannelLink struct {
 		incoming bool) *lnwire.ChannelUpdate
 
 	confirmedZC bool
+
+	policy models.ForwardingPolicy
 }
 
 // completeCircuit is a helper method for adding the finalized payment circuit
@@ -831,8 +833,16 @@ func (f *mockChannelLink) getDustClosure() dustClosure {
 func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 }
 
-func (f *mockChannelLink) UpdateForwardingPolicy(_ models.ForwardingPolicy) {
+func (f *mockChannelLink) UpdateForwardingPolicy(
+	policy models.ForwardingPolicy) {
+
+	f.policy = policy
 }
+
+func (f *mockChannelLink) ForwardingPolicy() models.ForwardingPolicy {
+	return f.policy
+}
+
 func (f *mockChannelLink) CheckHtlcForward([32]byte, lnwire.MilliSatoshi,
 	lnwire.MilliSatoshi, uint32, uint32, models.InboundFee, uint32,
 	lnwire.ShortChannelID) *LinkError {
@@ -236,6 +236,10 @@ type Switch struct {
 	started  int32 // To be used atomically.
 	shutdown int32 // To be used atomically.
 
+	// forwardsPaused is set to 1 while the switch rejects all onward
+	// HTLCs. To be used atomically.
+	forwardsPaused int32
+
 	// bestHeight is the best known height of the main chain. The links will
 	// be used this information to govern decisions based on HTLC timeouts.
 	// This will be retrieved by the registered links atomically.
@@ -636,6 +640,24 @@ func (s *Switch) UpdateForwardingPolicies(
 	s.indexMtx.RUnlock()
 }
 
+// ForwardingPolicies returns the forwarding policies that are currently
+// enforced by all links known to the switch, keyed by their channel ID. As
+// these are the policies the links use to decide whether to forward an HTLC,
+// they include any updates that haven't been gossiped yet.
+func (s *Switch) ForwardingPolicies() map[lnwire.ChannelID]models.ForwardingPolicy {
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	policies := make(
+		map[lnwire.ChannelID]models.ForwardingPolicy, len(s.linkIndex),
+	)
+	for chanID, link := range s.linkIndex {
+		policies[chanID] = link.ForwardingPolicy()
+	}
+
+	return policies
+}
+
 // IsForwardedHTLC checks for a given channel and htlc index if it is related
 // to an opened circuit that represents a forwarded payment.
 func (s *Switch) IsForwardedHTLC(chanID lnwire.ShortChannelID,
@@ -1113,6 +1135,17 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, failure)
 		}
 
+		// Also reject onward HTLCs while forwards are paused, as we
+		// can't safely enforce their timelocks in that case.
+		if atomic.LoadInt32(&s.forwardsPaused) == 1 {
+			failure := NewDetailedLinkError(
+				&lnwire.FailTemporaryNodeFailure{},
+				OutgoingFailureForwardsDisabled,
+			)
+
+			return s.failAddPacket(packet, failure)
+		}
+
 		// Before we attempt to find a non-strict forwarding path for
 		// this htlc, check whether the htlc is being routed over the
 		// same incoming and outgoing channel. If our node does not
@@ -2765,6 +2798,24 @@ func (s *Switch) FlushForwardingEvents() error {
 	return s.cfg.FwdingLog.AddForwardingEvents(events)
 }
 
+// PauseForwards instructs the switch to reject all onward HTLCs until
+// ResumeForwards is called. HTLCs that we send or receive ourselves are not
+// affected. This is used to protect our channels while our view of the chain
+// can't be trusted, e.g. because the chain backend fell behind the network.
+func (s *Switch) PauseForwards() {
+	if atomic.CompareAndSwapInt32(&s.forwardsPaused, 0, 1) {
+		log.Warnf("Pausing HTLC forwards")
+	}
+}
+
+// ResumeForwards lets the switch forward onward HTLCs again after they were
+// paused with PauseForwards.
+func (s *Switch) ResumeForwards() {
+	if atomic.CompareAndSwapInt32(&s.forwardsPaused, 1, 0) {
+		log.Infof("Resuming HTLC forwards")
+	}
+}
+
 // BestHeight returns the best height known to the switch.
 func (s *Switch) BestHeight() uint32 {
 	return atomic.LoadUint32(&s.bestHeight)
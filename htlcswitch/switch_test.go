@@ -127,6 +127,66 @@ func TestSwitchHasActiveLink(t *testing.T) {
 	}
 }
 
+// TestSwitchForwardingPolicies tests that the switch reports the forwarding
+// policies that are currently enforced by its links.
+func TestSwitchForwardingPolicies(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create alice server")
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err, "unable to create bob server")
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err, "unable to init switch")
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	// Update the policy of one of the links, the switch should report the
+	// updated policy for it and the initial one for the other link.
+	alicePolicy := models.ForwardingPolicy{
+		MinHTLCOut:    1_000,
+		MaxHTLC:       100_000_000,
+		BaseFee:       1_000,
+		FeeRate:       100,
+		TimeLockDelta: 80,
+		InboundFee: models.InboundFee{
+			Base: -500,
+			Rate: -10,
+		},
+	}
+	aliceChannelLink.UpdateForwardingPolicy(alicePolicy)
+
+	policies := s.ForwardingPolicies()
+	require.Len(t, policies, 2)
+	require.Equal(t, alicePolicy, policies[chanID1])
+	require.Equal(t, models.ForwardingPolicy{}, policies[chanID2])
+
+	// Once a link is removed, its policy is no longer reported.
+	s.RemoveLink(chanID2)
+
+	policies = s.ForwardingPolicies()
+	require.Len(t, policies, 1)
+	require.Contains(t, policies, chanID1)
+}
+
 // TestSwitchSendPending checks the inability of htlc switch to forward adds
 // over pending links.
 func TestSwitchSendPending(t *testing.T) {
@@ -981,6 +1041,94 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchPauseForwards checks that onward HTLCs are failed back while
+// forwards are paused and are forwarded again once they're resumed.
+func TestSwitchPauseForwards(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer func() {
+		_ = s.Stop()
+	}()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	newPacket := func(htlcID uint64) *htlcPacket {
+		preimage, err := genPreimage()
+		require.NoError(t, err)
+
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: sha256.Sum256(preimage[:]),
+				Amount:      1,
+			},
+		}
+	}
+
+	// While forwards are paused, the HTLC should be failed back to Alice
+	// without reaching Bob.
+	s.PauseForwards()
+	require.NoError(t, s.ForwardPackets(nil, newPacket(0)))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		require.NotNil(t, pkt.linkFailure)
+		require.IsType(
+			t, &lnwire.FailTemporaryNodeFailure{},
+			pkt.linkFailure.msg,
+		)
+		require.Equal(
+			t, OutgoingFailureForwardsDisabled,
+			pkt.linkFailure.FailureDetail,
+		)
+
+	case <-bobChannelLink.packets:
+		t.Fatal("htlc forwarded while forwards are paused")
+
+	case <-time.After(time.Second):
+		t.Fatal("htlc was not failed back")
+	}
+
+	// Once forwards are resumed, the next HTLC should reach Bob.
+	s.ResumeForwards()
+	packet := newPacket(1)
+	require.NoError(t, s.ForwardPackets(nil, packet))
+
+	select {
+	case <-bobChannelLink.packets:
+		require.NoError(t, bobChannelLink.completeCircuit(packet))
+
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+}
+
 func TestSwitchForwardFailAfterFullAdd(t *testing.T) {
 	t.Parallel()
 
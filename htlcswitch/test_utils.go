@@ -25,6 +25,7 @@ import (
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
@@ -1166,6 +1167,7 @@ func (h *hopNetwork) createChannelLink(server, peer *mockServer,
 			NotifyActiveChannel:     func(wire.OutPoint) {},
 			NotifyInactiveChannel:   func(wire.OutPoint) {},
 			NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+			NotifyReestablishFailed: noopReestablishFailed,
 			HtlcNotifier:            server.htlcSwitch.cfg.HtlcNotifier,
 			GetAliases:              getAliases,
 		},
@@ -1410,3 +1412,8 @@ func timeout() func() {
 		close(done)
 	}
 }
+
+// noopReestablishFailed is a no-op NotifyReestablishFailed link callback.
+func noopReestablishFailed(wire.OutPoint,
+	channelnotifier.ReestablishFailureReason, error, bool) {
+}
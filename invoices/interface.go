@@ -85,6 +85,12 @@ type InvoiceDB interface {
 	// DeleteCanceledInvoices removes all canceled invoices from the
 	// database.
 	DeleteCanceledInvoices(ctx context.Context) error
+
+	// InvoiceIndices returns the highest add index and settle index that
+	// have been assigned so far. Callers can use them to resume the add
+	// and settle time series later on without missing any invoices.
+	InvoiceIndices(ctx context.Context) (addIndex, settleIndex uint64,
+		err error)
 }
 
 // Payload abstracts access to any additional fields provided in the final hop's
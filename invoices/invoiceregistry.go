@@ -587,6 +587,15 @@ func (i *InvoiceRegistry) LookupInvoice(ctx context.Context,
 	return i.idb.LookupInvoice(ctx, ref)
 }
 
+// InvoiceIndices returns the highest add index and settle index that have been
+// assigned so far. A subscriber that stores them can later pass them to
+// SubscribeNotifications to resume without missing any invoices.
+func (i *InvoiceRegistry) InvoiceIndices(ctx context.Context) (uint64, uint64,
+	error) {
+
+	return i.idb.InvoiceIndices(ctx)
+}
+
 // LookupInvoiceByRef looks up an invoice by the given reference, if found
 // then we're able to pull the funds pending within an HTLC.
 func (i *InvoiceRegistry) LookupInvoiceByRef(ctx context.Context,
@@ -211,6 +211,10 @@ func TestInvoices(t *testing.T) {
 			name: "AddInvoiceInvalidFeatureDeps",
 			test: testAddInvoiceInvalidFeatureDeps,
 		},
+		{
+			name: "InvoiceIndices",
+			test: testInvoiceIndices,
+		},
 	}
 
 	makeKeyValueDB := func(t *testing.T) invpkg.InvoiceDB {
@@ -2750,3 +2754,80 @@ func testAddInvoiceInvalidFeatureDeps(t *testing.T,
 		lnwire.PaymentAddrOptional,
 	))
 }
+
+// testInvoiceIndices asserts that the add and settle index watermarks track
+// the highest indices assigned to invoices.
+func testInvoiceIndices(t *testing.T,
+	makeDB func(t *testing.T) invpkg.InvoiceDB) {
+
+	t.Parallel()
+	db := makeDB(t)
+	ctxb := context.Background()
+
+	// An empty database has no indices yet.
+	addIndex, settleIndex, err := db.InvoiceIndices(ctxb)
+	require.NoError(t, err)
+	require.Zero(t, addIndex)
+	require.Zero(t, settleIndex)
+
+	var refs []invpkg.InvoiceRef
+	for i := 0; i < 3; i++ {
+		invoice, err := randInvoice(lnwire.MilliSatoshi(i + 1))
+		require.NoError(t, err)
+
+		paymentHash := invoice.Terms.PaymentPreimage.Hash()
+		_, err = db.AddInvoice(ctxb, invoice, paymentHash)
+		require.NoError(t, err)
+
+		refs = append(refs, invpkg.InvoiceRefByHash(paymentHash))
+	}
+
+	addIndex, settleIndex, err = db.InvoiceIndices(ctxb)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, addIndex)
+	require.Zero(t, settleIndex)
+
+	// Settle the last and then the first invoice, the settle index follows
+	// the order in which they were settled.
+	for i, ref := range []invpkg.InvoiceRef{refs[2], refs[0]} {
+		_, err = db.UpdateInvoice(
+			ctxb, ref, nil, getUpdateInvoice(uint64(i), 10),
+		)
+		require.NoError(t, err)
+	}
+
+	addIndex, settleIndex, err = db.InvoiceIndices(ctxb)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, addIndex)
+	require.EqualValues(t, 2, settleIndex)
+
+	// Deleting the most recent invoice doesn't decrease the add index,
+	// and its add index isn't reused by the next invoice.
+	invoice, err := randInvoice(4)
+	require.NoError(t, err)
+
+	paymentHash := invoice.Terms.PaymentPreimage.Hash()
+	addIndex, err = db.AddInvoice(ctxb, invoice, paymentHash)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, addIndex)
+
+	err = db.DeleteInvoice(ctxb, []invpkg.InvoiceDeleteRef{{
+		PayHash:  paymentHash,
+		PayAddr:  &invoice.Terms.PaymentAddr,
+		AddIndex: addIndex,
+	}})
+	require.NoError(t, err)
+
+	addIndex, _, err = db.InvoiceIndices(ctxb)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, addIndex)
+
+	invoice, err = randInvoice(5)
+	require.NoError(t, err)
+
+	addIndex, err = db.AddInvoice(
+		ctxb, invoice, invoice.Terms.PaymentPreimage.Hash(),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, addIndex)
+}
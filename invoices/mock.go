@@ -83,3 +83,11 @@ func (m *MockInvoiceDB) DeleteCanceledInvoices(ctx context.Context) error {
 
 	return args.Error(0)
 }
+
+func (m *MockInvoiceDB) InvoiceIndices(ctx context.Context) (uint64, uint64,
+	error) {
+
+	args := m.Called(ctx)
+
+	return args.Get(0).(uint64), args.Get(1).(uint64), args.Error(2)
+}
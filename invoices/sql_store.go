@@ -61,8 +61,13 @@ type SQLInvoiceQueries interface { //nolint:interfacebloat
 	UpdateInvoiceAmountPaid(ctx context.Context,
 		arg sqlc.UpdateInvoiceAmountPaidParams) (sql.Result, error)
 
+	NextInvoiceAddIndex(ctx context.Context) (int64, error)
+
 	NextInvoiceSettleIndex(ctx context.Context) (int64, error)
 
+	GetInvoiceIndices(ctx context.Context) (sqlc.GetInvoiceIndicesRow,
+		error)
+
 	UpdateInvoiceHTLC(ctx context.Context,
 		arg sqlc.UpdateInvoiceHTLCParams) error
 
@@ -232,7 +237,16 @@ func (i *SQLStore) AddInvoice(ctx context.Context,
 			params.PaymentAddr = newInvoice.Terms.PaymentAddr[:]
 		}
 
+		// The id of the invoice is its add index, which is taken from
+		// a sequence so that the ids of deleted invoices are never
+		// reused.
 		var err error
+		params.ID, err = db.NextInvoiceAddIndex(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to get next add index: %w",
+				err)
+		}
+
 		invoiceID, err = db.InsertInvoice(ctx, params)
 		if err != nil {
 			return fmt.Errorf("unable to insert invoice: %w", err)
@@ -1405,6 +1419,39 @@ func (i *SQLStore) DeleteCanceledInvoices(ctx context.Context) error {
 	return nil
 }
 
+// InvoiceIndices returns the highest add index and settle index that have been
+// assigned so far.
+//
+// NOTE: Both indices are read from their sequences, which are never
+// decreased, even if invoices are deleted.
+func (i *SQLStore) InvoiceIndices(ctx context.Context) (uint64, uint64,
+	error) {
+
+	var addIndex, settleIndex uint64
+
+	readTxOpt := NewSQLInvoiceQueryReadTx()
+	err := i.db.ExecTx(ctx, &readTxOpt, func(db SQLInvoiceQueries) error {
+		row, err := db.GetInvoiceIndices(ctx)
+		if err != nil {
+			return err
+		}
+
+		addIndex = uint64(row.AddIndex)
+		settleIndex = uint64(row.SettleIndex)
+
+		return nil
+	}, func() {
+		addIndex = 0
+		settleIndex = 0
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to get invoice indices: %w",
+			err)
+	}
+
+	return addIndex, settleIndex, nil
+}
+
 // fetchInvoiceData fetches additional data for the given invoice. If the
 // invoice is AMP and the setID is not nil, then it will also fetch the AMP
 // state and HTLCs for the given setID, otherwise for all AMP sub invoices of
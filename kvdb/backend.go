@@ -43,6 +43,11 @@ func fileExists(path string) bool {
 	return true
 }
 
+// DiskSpaceCheck returns an error if the file system of the given directory
+// doesn't have enough free space for an operation that writes up to the given
+// number of bytes. The operation describes what is about to be started.
+type DiskSpaceCheck func(operation, dir string, required uint64) error
+
 // BoltBackendConfig is a struct that holds settings specific to the bolt
 // database backend.
 type BoltBackendConfig struct {
@@ -70,14 +75,74 @@ type BoltBackendConfig struct {
 	// be considered again.
 	AutoCompactMinAge time.Duration
 
+	// AutoCompactInterval specifies how often the database is compacted
+	// while it is open, if the minimum age since the last compaction has
+	// been reached. Zero disables compaction while running.
+	AutoCompactInterval time.Duration
+
+	// AutoCompactIdleTime is the time no write transaction must have been
+	// committed on the database for a periodic compaction to start. Zero
+	// starts the compaction right away.
+	AutoCompactIdleTime time.Duration
+
+	// CompactOnDemand allows the database to be compacted through the
+	// Compacter interface while it is open, even if AutoCompactInterval
+	// is zero.
+	CompactOnDemand bool
+
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration
+
+	// DiskSpaceCheck, if set, is called before the database is compacted
+	// with an estimate of the disk space the compaction needs. If it
+	// returns an error, the compaction isn't started. As compacting on
+	// startup is optional, it's then skipped with a warning and the
+	// database is opened as is.
+	DiskSpaceCheck DiskSpaceCheck
+}
+
+// checkDiskSpace runs the configured disk space check, if any, for an
+// operation that writes the given number of copies of the database file. The
+// size of the database file is used as the size of each copy, which is a
+// conservative estimate as compacting the database never grows it.
+func checkDiskSpace(cfg *BoltBackendConfig, operation string,
+	copies uint64) error {
+
+	if cfg.DiskSpaceCheck == nil {
+		return nil
+	}
+
+	fi, err := os.Stat(filepath.Join(cfg.DBPath, cfg.DBFileName))
+	if err != nil {
+		return err
+	}
+
+	return cfg.DiskSpaceCheck(
+		operation, cfg.DBPath, copies*uint64(fi.Size()),
+	)
 }
 
 // GetBoltBackend opens (or creates if doesn't exits) a bbolt backed database
 // and returns a kvdb.Backend wrapping it.
 func GetBoltBackend(cfg *BoltBackendConfig) (Backend, error) {
+	db, err := openBoltBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The database might also need to be compacted periodically or on
+	// demand while it's open.
+	if cfg.AutoCompactInterval != 0 || cfg.CompactOnDemand {
+		return newCompactingBackend(cfg, db), nil
+	}
+
+	return db, nil
+}
+
+// openBoltBackend opens (or creates if doesn't exits) a bbolt backed database,
+// compacting it first if configured to.
+func openBoltBackend(cfg *BoltBackendConfig) (Backend, error) {
 	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
 
 	// Is this a new database?
@@ -137,6 +202,17 @@ func compactAndSwap(cfg *BoltBackendConfig) error {
 		return nil
 	}
 
+	// The compacted copy is written next to the database file. Without
+	// enough space for it, we rather skip this optional compaction than
+	// refuse to start.
+	err = checkDiskSpace(cfg, "compacting "+sourceFilePath, 1)
+	if err != nil {
+		log.Warnf("Skipping compaction of database file at %v: %v",
+			sourceFilePath, err)
+
+		return nil
+	}
+
 	log.Infof("Compacting database file at %v", sourceFilePath)
 
 	// If the old temporary DB file still exists, then we'll delete it
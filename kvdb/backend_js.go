@@ -32,6 +32,21 @@ type BoltBackendConfig struct {
 	// be considered again.
 	AutoCompactMinAge time.Duration
 
+	// AutoCompactInterval specifies how often the database is compacted
+	// while it is open, if the minimum age since the last compaction has
+	// been reached. Zero disables compaction while running.
+	AutoCompactInterval time.Duration
+
+	// AutoCompactIdleTime is the time no write transaction must have been
+	// committed on the database for a periodic compaction to start. Zero
+	// starts the compaction right away.
+	AutoCompactIdleTime time.Duration
+
+	// CompactOnDemand allows the database to be compacted through the
+	// Compacter interface while it is open, even if AutoCompactInterval
+	// is zero.
+	CompactOnDemand bool
+
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration
@@ -46,6 +46,9 @@ type compacter struct {
 
 	// dbTimeout specifies the timeout value used when opening the db.
 	dbTimeout time.Duration
+
+	// abort, if set, aborts the compaction once it is closed.
+	abort <-chan struct{}
 }
 
 // execute opens the source and destination databases and then compacts the
@@ -145,6 +148,12 @@ func (cmd *compacter) compact(dst, src *bbolt.DB) error {
 	}()
 
 	if err := cmd.walk(src, func(keys [][]byte, k, v []byte, seq uint64) error {
+		select {
+		case <-cmd.abort:
+			return errCompactionAborted
+		default:
+		}
+
 		// On each key/value, check if we have exceeded tx size.
 		sz := int64(len(k) + len(v))
 		if size+sz > cmd.txMaxSize && cmd.txMaxSize != 0 {
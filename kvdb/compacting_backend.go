@@ -0,0 +1,716 @@
+//go:build !js
+// +build !js
+
+package kvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// snapshotDBFileName is the name of the file that holds a consistent
+	// snapshot of a database that is compacted while running.
+	snapshotDBFileName = "temp-snapshot-dont-use.db"
+
+	// replacedDBFileName is the name the database file is moved to while
+	// the compacted copy is swapped in.
+	replacedDBFileName = "temp-replaced-dont-use.db"
+
+	// maxCompactionAttempts is the number of times a compacted copy is
+	// created before giving up, if the database is written to each time
+	// while the copy is created.
+	maxCompactionAttempts = 3
+)
+
+var (
+	// errCompactionAborted is returned if a compaction is aborted before
+	// the compacted database file is swapped in.
+	errCompactionAborted = errors.New("database compaction aborted")
+
+	// errWrittenDuringCompaction is returned if the database was written
+	// to while its compacted copy was created, so the copy is outdated.
+	errWrittenDuringCompaction = errors.New("database was written to " +
+		"while it was compacted")
+)
+
+// compactingBackend is a bolt backed database that is compacted periodically or
+// on demand while it is open. Reads and writes continue while the compacted
+// copy is created. New write transactions are only paused while the copy is
+// swapped in, which doesn't depend on the size of the database. If the database
+// was written to in the meantime, the outdated copy is discarded and created
+// again, up to maxCompactionAttempts times, so no write is ever lost. Read
+// transactions that are still open on the old file finish there.
+type compactingBackend struct {
+	cfg *BoltBackendConfig
+
+	// idleTime is the time no write transaction must have been committed
+	// for a periodic compaction to start.
+	idleTime time.Duration
+
+	// db is the currently open database.
+	db Backend
+
+	// numReaders and numWriters are the numbers of open read and write
+	// transactions.
+	numReaders int
+	numWriters int
+
+	// blockWrites pauses new write transactions while set.
+	blockWrites bool
+
+	// lastWrite is the time the last write transaction was committed.
+	lastWrite time.Time
+
+	// numWrites is the number of write transactions that finished, which
+	// tells whether the database might have changed since a compacted
+	// copy was started.
+	numWrites uint64
+
+	// mu guards all of the above fields, cond is signaled whenever one of
+	// them changes.
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// compactMtx ensures that only a single compaction runs at a time.
+	compactMtx sync.Mutex
+
+	// closed is set once the backend is closed, after which no further
+	// compaction starts. It is guarded by compactMtx.
+	closed bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile-time check to ensure compactingBackend implements the Backend,
+// walletdb.BatchDB and Compacter interfaces.
+var (
+	_ walletdb.BatchDB = (*compactingBackend)(nil)
+	_ Compacter        = (*compactingBackend)(nil)
+)
+
+// newCompactingBackend wraps the given open bolt database and starts to
+// compact it in the interval set in the config, if any.
+func newCompactingBackend(cfg *BoltBackendConfig,
+	db Backend) *compactingBackend {
+
+	b := &compactingBackend{
+		cfg:       cfg,
+		idleTime:  cfg.AutoCompactIdleTime,
+		db:        db,
+		lastWrite: time.Now(),
+		quit:      make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	if cfg.AutoCompactInterval != 0 {
+		b.wg.Add(1)
+		go b.compactionScheduler()
+	}
+
+	return b
+}
+
+// acquire blocks until a new transaction may be started and returns the
+// database to start it on.
+func (b *compactingBackend) acquire(write bool) Backend {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for write && b.blockWrites {
+		b.cond.Wait()
+	}
+
+	if write {
+		b.numWriters++
+	} else {
+		b.numReaders++
+	}
+
+	return b.db
+}
+
+// release marks a transaction that was started after a call to acquire as
+// finished. Only committed write transactions count as a write to the
+// database, rolled back ones didn't change it.
+func (b *compactingBackend) release(write, committed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if write {
+		b.numWriters--
+		b.numWrites++
+		if committed {
+			b.lastWrite = time.Now()
+		}
+	} else {
+		b.numReaders--
+	}
+
+	b.cond.Broadcast()
+}
+
+// BeginReadTx opens a database read transaction.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) BeginReadTx() (walletdb.ReadTx, error) {
+	tx, err := b.acquire(false).BeginReadTx()
+	if err != nil {
+		b.release(false, false)
+		return nil, err
+	}
+
+	return &compactingReadTx{
+		ReadTx:  tx,
+		release: b.releaseOnce(false),
+	}, nil
+}
+
+// BeginReadWriteTx opens a database read+write transaction.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	tx, err := b.acquire(true).BeginReadWriteTx()
+	if err != nil {
+		b.release(true, false)
+		return nil, err
+	}
+
+	return &compactingReadWriteTx{
+		ReadWriteTx: tx,
+		release:     b.releaseOnce(true),
+	}, nil
+}
+
+// releaseOnce returns a closure that releases a transaction the first time it
+// is called, as transactions may be rolled back after they were committed.
+func (b *compactingBackend) releaseOnce(write bool) func(committed bool) {
+	var once sync.Once
+	return func(committed bool) {
+		once.Do(func() {
+			b.release(write, committed)
+		})
+	}
+}
+
+// Copy writes a copy of the database to the provided writer.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) Copy(w io.Writer) error {
+	defer b.release(false, false)
+	return b.acquire(false).Copy(w)
+}
+
+// Close stops the compaction and cleanly shuts down the database.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) Close() error {
+	// A running compaction notices the shutdown and is aborted. Once we
+	// hold the compaction mutex, no compaction runs anymore and none can
+	// start, so nothing is added to the wait group while we wait for the
+	// scheduler and the close of any replaced database.
+	close(b.quit)
+
+	b.compactMtx.Lock()
+	b.closed = true
+	b.compactMtx.Unlock()
+
+	b.wg.Wait()
+
+	return b.db.Close()
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) PrintStats() string {
+	defer b.release(false, false)
+	return b.acquire(false).PrintStats()
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) View(f func(tx walletdb.ReadTx) error,
+	reset func()) error {
+
+	defer b.release(false, false)
+	return b.acquire(false).View(f, reset)
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *compactingBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	err := b.acquire(true).Update(f, reset)
+	b.release(true, err == nil)
+
+	return err
+}
+
+// Batch is similar to the Update method, but it will attempt to
+// optimistically combine the invocation of several transaction functions into
+// a single db write transaction.
+//
+// NOTE: This is part of the walletdb.BatchDB interface.
+func (b *compactingBackend) Batch(f func(tx walletdb.ReadWriteTx) error) error {
+	err := Batch(b.acquire(true), f)
+	b.release(true, err == nil)
+
+	return err
+}
+
+// compactionScheduler compacts the database in the configured interval, once
+// it wasn't written to for the configured idle time.
+//
+// NOTE: This method must be run as a goroutine.
+func (b *compactingBackend) compactionScheduler() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.cfg.AutoCompactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.quit:
+			return
+		}
+
+		if !b.waitForIdle() {
+			return
+		}
+
+		if err := b.compact(); err != nil {
+			log.Errorf("Unable to compact database file %v: %v",
+				filepath.Join(b.cfg.DBPath, b.cfg.DBFileName),
+				err)
+		}
+	}
+}
+
+// waitForIdle blocks until no write transaction was committed for the idle
+// time. False is returned if the backend is shutting down.
+func (b *compactingBackend) waitForIdle() bool {
+	for {
+		b.mu.Lock()
+		idleFor := time.Since(b.lastWrite)
+		b.mu.Unlock()
+
+		if idleFor >= b.idleTime {
+			return true
+		}
+
+		select {
+		case <-time.After(b.idleTime - idleFor):
+		case <-b.quit:
+			return false
+		}
+	}
+}
+
+// Compact creates a compacted copy of the database and swaps it in, regardless
+// of when the database was last compacted. The size of the database file
+// before and after the compaction is returned.
+//
+// NOTE: This is part of the Compacter interface.
+func (b *compactingBackend) Compact(ctx context.Context) (int64, int64,
+	error) {
+
+	// The compaction is aborted if either the context is canceled or the
+	// backend is shutting down.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-b.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return b.compactNow(ctx.Done())
+}
+
+// compact creates a compacted copy of the database and swaps it in, if the
+// minimum age since the last compaction has been reached.
+func (b *compactingBackend) compact() error {
+	sourceFilePath := filepath.Join(b.cfg.DBPath, b.cfg.DBFileName)
+
+	lastCompactionDate, err := lastCompactionDate(sourceFilePath)
+	if err != nil {
+		return fmt.Errorf("cannot determine last compaction date of "+
+			"source DB file: %w", err)
+	}
+	compactAge := time.Since(lastCompactionDate)
+	if b.cfg.AutoCompactMinAge != 0 &&
+		compactAge <= b.cfg.AutoCompactMinAge {
+
+		log.Debugf("Not compacting database file at %v, it was last "+
+			"compacted %v ago", sourceFilePath,
+			compactAge.Truncate(time.Second))
+
+		return nil
+	}
+
+	_, _, err = b.compactNow(b.quit)
+
+	return err
+}
+
+// compactNow creates a compacted copy of the database and swaps it in. The
+// compaction is aborted if the abort channel is closed before the compacted
+// copy is swapped in. The size of the database file before and after the
+// compaction is returned.
+func (b *compactingBackend) compactNow(abort <-chan struct{}) (int64, int64,
+	error) {
+
+	b.compactMtx.Lock()
+	defer b.compactMtx.Unlock()
+
+	if b.closed {
+		return 0, 0, errCompactionAborted
+	}
+
+	sourceFilePath := filepath.Join(b.cfg.DBPath, b.cfg.DBFileName)
+
+	// Both the snapshot and the compacted copy are written next to the
+	// database file.
+	err := checkDiskSpace(b.cfg, "compacting "+sourceFilePath, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fi, err := os.Stat(sourceFilePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	initialSize := fi.Size()
+
+	// A copy that missed a write is never swapped in, so we create it
+	// again until no write happens meanwhile.
+	for attempt := 1; ; attempt++ {
+		err = b.compactOnce(abort)
+		if !errors.Is(err, errWrittenDuringCompaction) ||
+			attempt == maxCompactionAttempts {
+
+			break
+		}
+
+		log.Debugf("Database file at %v was written to while it was "+
+			"compacted, retrying", sourceFilePath)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fi, err = os.Stat(sourceFilePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	newSize := fi.Size()
+
+	log.Infof("DB compaction of %v successful, %d -> %d bytes, reclaimed "+
+		"%d bytes", sourceFilePath, initialSize, newSize,
+		initialSize-newSize)
+
+	err = updateLastCompactionDate(sourceFilePath)
+	if err != nil {
+		log.Warnf("Could not update last compaction timestamp in "+
+			"%s%s: %v", sourceFilePath,
+			LastCompactionFileNameSuffix, err)
+	}
+
+	return initialSize, newSize, nil
+}
+
+// pauseWrites blocks new write transactions and waits for the open ones to
+// finish. If the abort channel is closed before that, writes are resumed and
+// errCompactionAborted is returned.
+func (b *compactingBackend) pauseWrites(abort <-chan struct{}) error {
+	// The wait for the open write transactions below can only be woken up
+	// by a broadcast, so we send one once the compaction is aborted.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-abort:
+			b.mu.Lock()
+			b.cond.Broadcast()
+			b.mu.Unlock()
+
+		case <-done:
+		}
+	}()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.blockWrites = true
+	for b.numWriters > 0 && !isAborted(abort) {
+		b.cond.Wait()
+	}
+
+	if isAborted(abort) {
+		b.blockWrites = false
+		b.cond.Broadcast()
+
+		return errCompactionAborted
+	}
+
+	return nil
+}
+
+// resumeWrites unblocks the write transactions paused by pauseWrites.
+func (b *compactingBackend) resumeWrites() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.blockWrites = false
+	b.cond.Broadcast()
+}
+
+// compactOnce creates a compacted copy of a snapshot of the database and swaps
+// it in. Writes are only paused for the swap. If the database was written to
+// since the snapshot was taken, errWrittenDuringCompaction is returned and the
+// database is left untouched.
+func (b *compactingBackend) compactOnce(abort <-chan struct{}) error {
+	sourceFilePath := filepath.Join(b.cfg.DBPath, b.cfg.DBFileName)
+	snapshotFilePath := filepath.Join(b.cfg.DBPath, snapshotDBFileName)
+	tempDestFilePath := filepath.Join(b.cfg.DBPath, DefaultTempDBFileName)
+
+	defer func() {
+		_ = os.Remove(snapshotFilePath)
+		_ = os.Remove(tempDestFilePath)
+	}()
+
+	log.Infof("Compacting database file at %v", sourceFilePath)
+
+	// The open database file can't be compacted directly, so we compact
+	// a consistent snapshot of it instead.
+	numWrites, err := b.writeSnapshot(snapshotFilePath, abort)
+	if err != nil {
+		return err
+	}
+
+	c := &compacter{
+		srcPath:   snapshotFilePath,
+		dstPath:   tempDestFilePath,
+		dbTimeout: b.cfg.DBTimeout,
+		abort:     abort,
+	}
+	if _, _, err := c.execute(); err != nil {
+		return fmt.Errorf("error during compact: %w", err)
+	}
+
+	// No write may happen while the compacted copy is swapped in,
+	// otherwise it would be lost. Reads continue meanwhile.
+	if err := b.pauseWrites(abort); err != nil {
+		return err
+	}
+	defer b.resumeWrites()
+
+	b.mu.Lock()
+	written := b.numWrites != numWrites
+	b.mu.Unlock()
+
+	if written {
+		return errWrittenDuringCompaction
+	}
+
+	return b.swap(tempDestFilePath, sourceFilePath, abort)
+}
+
+// writeSnapshot writes a consistent copy of the open database to the given
+// file. Writing the copy stops once the abort channel is closed. The number of
+// finished write transactions before the copy was started is returned, so any
+// write the copy might have missed can be detected.
+func (b *compactingBackend) writeSnapshot(snapshotFilePath string,
+	abort <-chan struct{}) (uint64, error) {
+
+	snapshot, err := os.OpenFile(
+		snapshotFilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create snapshot file: %w", err)
+	}
+
+	b.mu.Lock()
+	numWrites := b.numWrites
+	b.mu.Unlock()
+
+	w := &abortableWriter{w: snapshot, abort: abort}
+	if err := b.Copy(w); err != nil {
+		_ = snapshot.Close()
+
+		// The error of the writer isn't wrapped by bolt, so we can't
+		// match it.
+		if isAborted(abort) {
+			return 0, errCompactionAborted
+		}
+
+		return 0, fmt.Errorf("unable to write snapshot: %w", err)
+	}
+
+	return numWrites, snapshot.Close()
+}
+
+// abortableWriter is a writer that fails once its abort channel is closed, so
+// that copying a large database can be stopped part way.
+type abortableWriter struct {
+	w     io.Writer
+	abort <-chan struct{}
+}
+
+// Write writes the given bytes to the underlying writer, unless the abort
+// channel was closed.
+//
+// NOTE: This is part of the io.Writer interface.
+func (w *abortableWriter) Write(p []byte) (int, error) {
+	select {
+	case <-w.abort:
+		return 0, errCompactionAborted
+	default:
+	}
+
+	return w.w.Write(p)
+}
+
+// isAborted returns true if the given abort channel is closed.
+func isAborted(abort <-chan struct{}) bool {
+	select {
+	case <-abort:
+		return true
+	default:
+		return false
+	}
+}
+
+// swap replaces the database file with the compacted one. Writes must be
+// paused by the caller. The compacted file is opened before the new database
+// is put in place, so reads aren't blocked meanwhile. If the compacted file
+// can't be opened, the current database file is restored and stays in use.
+// Read transactions that are still open on the current database finish there
+// before it is closed.
+func (b *compactingBackend) swap(compactedFilePath, sourceFilePath string,
+	abort <-chan struct{}) error {
+
+	// This is the last chance to abort, the database remains untouched
+	// until the compacted copy is swapped in.
+	if isAborted(abort) {
+		return errCompactionAborted
+	}
+
+	log.Infof("Swapping compacted DB file %v to %v", compactedFilePath,
+		sourceFilePath)
+
+	// We move the current file out of the way instead of overwriting it,
+	// so we can move it back if the compacted file can't be opened. The
+	// open database isn't affected by the rename.
+	replacedFilePath := filepath.Join(b.cfg.DBPath, replacedDBFileName)
+	if err := os.Rename(sourceFilePath, replacedFilePath); err != nil {
+		return fmt.Errorf("unable to move database file: %w", err)
+	}
+
+	restore := func() error {
+		return os.Rename(replacedFilePath, sourceFilePath)
+	}
+
+	if err := os.Rename(compactedFilePath, sourceFilePath); err != nil {
+		if restoreErr := restore(); restoreErr != nil {
+			return fmt.Errorf("unable to restore database file "+
+				"after failed swap: %w", restoreErr)
+		}
+
+		return fmt.Errorf("unable to swap compacted database file: %w",
+			err)
+	}
+
+	db, err := Open(
+		BoltBackendName, sourceFilePath, b.cfg.NoFreelistSync,
+		b.cfg.DBTimeout,
+	)
+	if err != nil {
+		if restoreErr := restore(); restoreErr != nil {
+			return fmt.Errorf("unable to restore database file "+
+				"after failed swap: %w", restoreErr)
+		}
+
+		return fmt.Errorf("unable to open compacted database: %w", err)
+	}
+
+	b.mu.Lock()
+	oldDB := b.db
+	b.db = db
+	b.mu.Unlock()
+
+	// Closing the old database blocks until all read transactions on it
+	// are done, which must not hold up the compaction. The file stays
+	// accessible to them until then.
+	_ = os.Remove(replacedFilePath)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		if err := oldDB.Close(); err != nil {
+			log.Errorf("Unable to close database replaced by "+
+				"compacted file %v: %v", sourceFilePath, err)
+		}
+	}()
+
+	return nil
+}
+
+// compactingReadTx is a read transaction of a compactingBackend that releases
+// its slot once it is rolled back.
+type compactingReadTx struct {
+	walletdb.ReadTx
+
+	release func(committed bool)
+}
+
+// Rollback closes the transaction, discarding changes (if any) if the
+// database was modified by a write transaction.
+func (tx *compactingReadTx) Rollback() error {
+	defer tx.release(false)
+	return tx.ReadTx.Rollback()
+}
+
+// compactingReadWriteTx is a read+write transaction of a compactingBackend
+// that releases its slot once it is committed or rolled back.
+type compactingReadWriteTx struct {
+	walletdb.ReadWriteTx
+
+	release func(committed bool)
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to persistent storage.
+func (tx *compactingReadWriteTx) Commit() error {
+	err := tx.ReadWriteTx.Commit()
+	tx.release(err == nil)
+
+	return err
+}
+
+// Rollback closes the transaction, discarding changes (if any) if the
+// database was modified by a write transaction.
+func (tx *compactingReadWriteTx) Rollback() error {
+	defer tx.release(false)
+	return tx.ReadWriteTx.Rollback()
+}
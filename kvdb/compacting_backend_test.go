@@ -0,0 +1,565 @@
+package kvdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompactingBackend asserts that a bolt database can be compacted while
+// it is open, without losing any data.
+func TestCompactingBackend(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:              t.TempDir(),
+		DBFileName:          "test.db",
+		NoFreelistSync:      true,
+		DBTimeout:           DefaultDBTimeout,
+		AutoCompactInterval: time.Hour,
+	}
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	backend, ok := db.(*compactingBackend)
+	require.True(t, ok)
+
+	// Fill the database and delete most of the data again, which leaves
+	// a lot of free pages behind.
+	bucketKey := []byte("bucket")
+	value := make([]byte, 1024)
+	err = Update(db, func(tx RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Put(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	err = Update(db, func(tx RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketKey)
+		for i := 10; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(dbFilePath)
+	require.NoError(t, err)
+	initialSize := fi.Size()
+
+	// A failing disk space check prevents the compaction. It needs space
+	// for both the snapshot and the compacted copy.
+	errNoSpace := errors.New("no space left")
+	var required uint64
+	backend.cfg.DiskSpaceCheck = func(_, dir string, r uint64) error {
+		require.Equal(t, cfg.DBPath, dir)
+		required = r
+
+		return errNoSpace
+	}
+	require.ErrorIs(t, backend.compact(), errNoSpace)
+	require.EqualValues(t, 2*initialSize, required)
+	backend.cfg.DiskSpaceCheck = nil
+
+	// An open read transaction doesn't prevent the compaction, it keeps
+	// reading from the old file until it is done.
+	readTx, err := db.BeginReadTx()
+	require.NoError(t, err)
+	require.NoError(t, backend.compact())
+	require.Equal(
+		t, value, readTx.ReadBucket(bucketKey).Get([]byte("key-0")),
+	)
+	require.NoError(t, readTx.Rollback())
+
+	fi, err = os.Stat(dbFilePath)
+	require.NoError(t, err)
+	require.Less(t, fi.Size(), initialSize)
+
+	// The remaining data is still available, and can be written to.
+	err = Update(db, func(tx RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketKey)
+		for i := 0; i < 10; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			require.Equal(t, value, bucket.Get(key))
+		}
+
+		return bucket.Put([]byte("new-key"), value)
+	}, func() {})
+	require.NoError(t, err)
+
+	err = View(db, func(tx RTx) error {
+		bucket := tx.ReadBucket(bucketKey)
+		require.Equal(t, value, bucket.Get([]byte("new-key")))
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// A recent compaction prevents another one if a minimum age is set.
+	lastCompaction, err := lastCompactionDate(dbFilePath)
+	require.NoError(t, err)
+
+	backend.cfg.AutoCompactMinAge = time.Hour
+	require.NoError(t, backend.compact())
+
+	lastCompactionAfter, err := lastCompactionDate(dbFilePath)
+	require.NoError(t, err)
+	require.Equal(t, lastCompaction, lastCompactionAfter)
+
+	require.Zero(t, backend.numReaders)
+	require.Zero(t, backend.numWriters)
+
+	// Rolled back write transactions don't count as a write to the
+	// database.
+	lastWrite := backend.lastWrite
+	errRollback := errors.New("rollback")
+	err = Update(db, func(tx RwTx) error {
+		return errRollback
+	}, func() {})
+	require.ErrorIs(t, err, errRollback)
+
+	tx, err := db.BeginReadWriteTx()
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	require.Equal(t, lastWrite, backend.lastWrite)
+	require.Zero(t, backend.numWriters)
+}
+
+// TestCompactingBackendOnDemand asserts that a bolt database can be compacted
+// on demand regardless of the last compaction, and that an aborted compaction
+// leaves the database untouched.
+func TestCompactingBackendOnDemand(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:            t.TempDir(),
+		DBFileName:        "test.db",
+		NoFreelistSync:    true,
+		DBTimeout:         DefaultDBTimeout,
+		AutoCompactMinAge: time.Hour,
+		CompactOnDemand:   true,
+	}
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	compacter, ok := db.(Compacter)
+	require.True(t, ok)
+
+	bucketKey := []byte("bucket")
+	value := make([]byte, 1024)
+	err = Update(db, func(tx RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Put(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	err = Update(db, func(tx RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketKey)
+		for i := 10; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(dbFilePath)
+	require.NoError(t, err)
+	initialSize := fi.Size()
+
+	// A canceled context aborts the compaction before the compacted file
+	// is swapped in.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = compacter.Compact(ctx)
+	require.ErrorIs(t, err, errCompactionAborted)
+
+	fi, err = os.Stat(dbFilePath)
+	require.NoError(t, err)
+	require.Equal(t, initialSize, fi.Size())
+	require.NoFileExists(t, filepath.Join(cfg.DBPath, snapshotDBFileName))
+	require.NoFileExists(
+		t, filepath.Join(cfg.DBPath, DefaultTempDBFileName),
+	)
+
+	// The compaction isn't prevented by the minimum age, and can be run
+	// again right away.
+	for i := 0; i < 2; i++ {
+		sizeBefore, sizeAfter, err := compacter.Compact(
+			context.Background(),
+		)
+		require.NoError(t, err)
+
+		fi, err = os.Stat(dbFilePath)
+		require.NoError(t, err)
+		require.Equal(t, fi.Size(), sizeAfter)
+		require.LessOrEqual(t, sizeAfter, sizeBefore)
+	}
+	require.Less(t, fi.Size(), initialSize)
+
+	// Writes resume once the compaction is done.
+	err = Update(db, func(tx RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketKey)
+		require.Equal(t, value, bucket.Get([]byte("key-0")))
+
+		return bucket.Put([]byte("new-key"), value)
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestCompactingBackendConcurrentWrites asserts that writes that happen while
+// a database is compacted are never lost.
+func TestCompactingBackendConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:          t.TempDir(),
+		DBFileName:      "test.db",
+		NoFreelistSync:  true,
+		DBTimeout:       DefaultDBTimeout,
+		CompactOnDemand: true,
+	}
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	compacter, ok := db.(Compacter)
+	require.True(t, ok)
+
+	bucketKey := []byte("bucket")
+	value := make([]byte, 1024)
+	err = Update(db, func(tx RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Put(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	// Keep writing while the database is compacted.
+	const numWrites = 200
+	writesDone := make(chan error, 1)
+	go func() {
+		for i := 0; i < numWrites; i++ {
+			err := Update(db, func(tx RwTx) error {
+				key := []byte(fmt.Sprintf("new-key-%d", i))
+				return tx.ReadWriteBucket(bucketKey).Put(
+					key, value,
+				)
+			}, func() {})
+			if err != nil {
+				writesDone <- err
+				return
+			}
+		}
+
+		writesDone <- nil
+	}()
+
+	// Writes continue while the compacted copy is created, so a
+	// compaction either swaps in a copy that contains every write so far
+	// or gives up after it was outdated on each attempt.
+	for i := 0; i < 3; i++ {
+		_, _, err := compacter.Compact(context.Background())
+		if err != nil {
+			require.ErrorIs(t, err, errWrittenDuringCompaction)
+		}
+	}
+	require.NoError(t, <-writesDone)
+
+	// Without any further writes, the compaction succeeds.
+	_, _, err = compacter.Compact(context.Background())
+	require.NoError(t, err)
+
+	// None of the writes were lost.
+	err = View(db, func(tx RTx) error {
+		bucket := tx.ReadBucket(bucketKey)
+		for i := 0; i < numWrites; i++ {
+			key := []byte(fmt.Sprintf("new-key-%d", i))
+			require.Equal(t, value, bucket.Get(key))
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
+
+// TestCompactingBackendAbortWaitingForWriters asserts that a compaction that
+// waits for an open write transaction to finish before swapping in the
+// compacted file can be aborted, and that writes resume afterwards.
+func TestCompactingBackendAbortWaitingForWriters(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:          t.TempDir(),
+		DBFileName:      "test.db",
+		NoFreelistSync:  true,
+		DBTimeout:       DefaultDBTimeout,
+		CompactOnDemand: true,
+	}
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	compacter, ok := db.(Compacter)
+	require.True(t, ok)
+
+	bucketKey := []byte("bucket")
+	err = Update(db, func(tx RwTx) error {
+		_, err := tx.CreateTopLevelBucket(bucketKey)
+		return err
+	}, func() {})
+	require.NoError(t, err)
+
+	// The open write transaction keeps the compacted file from being
+	// swapped in until the compaction times out.
+	tx, err := db.BeginReadWriteTx()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 100*time.Millisecond,
+	)
+	defer cancel()
+
+	compactErr := make(chan error, 1)
+	go func() {
+		_, _, err := compacter.Compact(ctx)
+		compactErr <- err
+	}()
+
+	select {
+	case err := <-compactErr:
+		require.ErrorIs(t, err, errCompactionAborted)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("compaction waiting for writers was not aborted")
+	}
+
+	// The write transaction can still be committed, and new writes aren't
+	// blocked.
+	_, err = tx.CreateTopLevelBucket([]byte("other-bucket"))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	err = Update(db, func(tx RwTx) error {
+		return tx.ReadWriteBucket(bucketKey).Put(
+			[]byte("key"), []byte("value"),
+		)
+	}, func() {})
+	require.NoError(t, err)
+
+	require.NoFileExists(t, filepath.Join(cfg.DBPath, snapshotDBFileName))
+	require.NoFileExists(
+		t, filepath.Join(cfg.DBPath, DefaultTempDBFileName),
+	)
+}
+
+// TestCompactingBackendCloseDuringCompaction asserts that closing the database
+// aborts a running on-demand compaction, and that no compaction starts once the
+// database is closed.
+func TestCompactingBackendCloseDuringCompaction(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:          t.TempDir(),
+		DBFileName:      "test.db",
+		NoFreelistSync:  true,
+		DBTimeout:       DefaultDBTimeout,
+		CompactOnDemand: true,
+	}
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+
+	compacter, ok := db.(Compacter)
+	require.True(t, ok)
+
+	value := make([]byte, 1024)
+	err = Update(db, func(tx RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < 5000; i++ {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			if err := bucket.Put(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	compactErr := make(chan error, 1)
+	go func() {
+		_, _, err := compacter.Compact(context.Background())
+		compactErr <- err
+	}()
+
+	require.NoError(t, db.Close())
+
+	// The compaction either finished before the database was closed or
+	// was aborted.
+	select {
+	case err := <-compactErr:
+		if err != nil {
+			require.ErrorIs(t, err, errCompactionAborted)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("compaction was not aborted on close")
+	}
+
+	_, _, err = compacter.Compact(context.Background())
+	require.ErrorIs(t, err, errCompactionAborted)
+}
+
+// TestCompactingBackendIdleTime asserts that a periodic compaction waits for
+// the configured idle time after the last committed write before it starts.
+func TestCompactingBackendIdleTime(t *testing.T) {
+	t.Parallel()
+
+	const idleTime = 200 * time.Millisecond
+	cfg := &BoltBackendConfig{
+		DBPath:              t.TempDir(),
+		DBFileName:          "test.db",
+		NoFreelistSync:      true,
+		DBTimeout:           DefaultDBTimeout,
+		AutoCompactIdleTime: idleTime,
+		CompactOnDemand:     true,
+	}
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	backend, ok := db.(*compactingBackend)
+	require.True(t, ok)
+
+	err = Update(db, func(tx RwTx) error {
+		_, err := tx.CreateTopLevelBucket([]byte("bucket"))
+		return err
+	}, func() {})
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.True(t, backend.waitForIdle())
+	require.GreaterOrEqual(t, time.Since(start), idleTime/2)
+
+	// Without an idle time the compaction starts right away, even if the
+	// database was just written to.
+	backend.idleTime = 0
+	err = Update(db, func(tx RwTx) error {
+		return tx.ReadWriteBucket([]byte("bucket")).Put(
+			[]byte("key"), []byte("value"),
+		)
+	}, func() {})
+	require.NoError(t, err)
+
+	start = time.Now()
+	require.True(t, backend.waitForIdle())
+	require.Less(t, time.Since(start), idleTime/2)
+}
+
+// TestStartupCompactionLowDiskSpace asserts that the optional compaction on
+// startup is skipped instead of failing to open the database if there isn't
+// enough disk space for it.
+func TestStartupCompactionLowDiskSpace(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BoltBackendConfig{
+		DBPath:         t.TempDir(),
+		DBFileName:     "test.db",
+		NoFreelistSync: true,
+		DBTimeout:      DefaultDBTimeout,
+	}
+	dbFilePath := filepath.Join(cfg.DBPath, cfg.DBFileName)
+
+	db, err := GetBoltBackend(cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	var checked bool
+	cfg.AutoCompact = true
+	cfg.DiskSpaceCheck = func(_, dir string, _ uint64) error {
+		require.Equal(t, cfg.DBPath, dir)
+		checked = true
+
+		return errors.New("no space left")
+	}
+
+	db, err = GetBoltBackend(cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	require.True(t, checked)
+
+	// The skipped compaction didn't record a compaction date.
+	_, err = os.Stat(dbFilePath + LastCompactionFileNameSuffix)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
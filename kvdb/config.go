@@ -41,5 +41,11 @@ type BoltConfig struct {
 
 	AutoCompactMinAge time.Duration `long:"auto-compact-min-age" description:"How long ago the last compaction of a database file must be for it to be considered for auto compaction again. Can be set to 0 to compact on every startup."`
 
+	AutoCompactInterval time.Duration `long:"auto-compact-interval" description:"How often the channel database should be compacted while lnd is running, if the last compaction is older than auto-compact-min-age. Writes are only paused while the compacted copy is swapped in. Set to 0 to only compact on startup."`
+
+	AutoCompactIdleTime time.Duration `long:"auto-compact-idle-time" description:"How long no data must have been written to the channel database before a periodic compaction starts, to avoid pausing a burst of writes. Set to 0 to start the compaction right away."`
+
+	CompactOnDemand bool `long:"compact-on-demand" description:"Whether the channel database can be compacted through the CompactChannelDB RPC while lnd is running. This tracks every transaction on the channel database, so writes can be paused while the compacted copy is swapped in, which adds a small overhead to each transaction. It is disabled by default and only needed if the RPC is used."`
+
 	DBTimeout time.Duration `long:"dbtimeout" description:"Specify the timeout value used when opening the database."`
 }
@@ -1,6 +1,13 @@
 package etcd
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// RequestRetryDelay is the delay between two attempts of a read request to
+// etcd that is retried because it timed out or failed with a transient error.
+const RequestRetryDelay = 100 * time.Millisecond
 
 // Config holds etcd configuration alongside with configuration related to our higher level interface.
 //
@@ -34,11 +41,28 @@ type Config struct {
 
 	MaxMsgSize int `long:"max_msg_size" description:"The maximum message size in bytes that we may send to etcd."`
 
+	RequestTimeout time.Duration `long:"request_timeout" description:"The timeout for a single request to etcd made by a database transaction, such as a read or the final commit. This is independent of the leader election timings. Set to 0 to disable."`
+
+	RequestRetries uint32 `long:"request_retries" description:"The number of times a read request of a database transaction is retried if it timed out or failed with a transient error. Commits are never retried, as it's unknown whether a timed out commit was applied."`
+
 	// SingleWriter should be set to true if we intend to only allow a
 	// single writer to the database at a time.
 	SingleWriter bool
 }
 
+// MaxRequestDuration returns the maximum time a single read request of a
+// database transaction may take including all retries, or zero if requests
+// don't time out.
+func (c *Config) MaxRequestDuration() time.Duration {
+	if c.RequestTimeout == 0 {
+		return 0
+	}
+
+	retries := time.Duration(c.RequestRetries)
+
+	return c.RequestTimeout*(retries+1) + RequestRetryDelay*retries
+}
+
 // CloneWithSubNamespace clones the current configuration and returns a new
 // instance with the given sub namespace applied by appending it to the main
 // namespace.
@@ -64,6 +88,8 @@ func (c *Config) CloneWithSubNamespace(subNamespace string) *Config {
 		InsecureSkipVerify: c.InsecureSkipVerify,
 		CollectStats:       c.CollectStats,
 		MaxMsgSize:         c.MaxMsgSize,
+		RequestTimeout:     c.RequestTimeout,
+		RequestRetries:     c.RequestRetries,
 		SingleWriter:       c.SingleWriter,
 	}
 }
@@ -85,6 +111,8 @@ func (c *Config) CloneWithSingleWriter() *Config {
 		InsecureSkipVerify: c.InsecureSkipVerify,
 		CollectStats:       c.CollectStats,
 		MaxMsgSize:         c.MaxMsgSize,
+		RequestTimeout:     c.RequestTimeout,
+		RequestRetries:     c.RequestRetries,
 		SingleWriter:       true,
 	}
 }
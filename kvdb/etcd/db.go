@@ -203,6 +203,8 @@ func newEtcdBackend(ctx context.Context, cfg Config) (*db, error) {
 func (db *db) getSTMOptions() []STMOptionFunc {
 	opts := []STMOptionFunc{
 		WithAbortContext(db.ctx),
+		WithRequestTimeout(db.cfg.RequestTimeout),
+		WithRequestRetries(db.cfg.RequestRetries),
 	}
 
 	if db.cfg.CollectStats {
@@ -0,0 +1,55 @@
+//go:build kvdb_etcd
+// +build kvdb_etcd
+
+package etcd
+
+import (
+	"errors"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// transientErrors are the errors of the etcd server that are caused by a
+// temporary unavailability of the cluster.
+var transientErrors = []error{
+	rpctypes.ErrTimeout,
+	rpctypes.ErrTimeoutDueToLeaderFail,
+	rpctypes.ErrTimeoutDueToConnectionLost,
+	rpctypes.ErrLeaderChanged,
+	rpctypes.ErrNoLeader,
+	rpctypes.ErrTooManyRequests,
+}
+
+// rejectedErrors are the errors of the etcd server that are returned before a
+// request is proposed to the cluster. A request that failed with one of them
+// was guaranteed not to be applied. Timeouts aren't part of this list, as a
+// request that timed out may still be committed by the cluster.
+var rejectedErrors = []error{
+	rpctypes.ErrNoLeader,
+	rpctypes.ErrTooManyRequests,
+}
+
+// IsTransientError returns true if the given error is caused by a temporary
+// unavailability of the etcd cluster.
+func IsTransientError(err error) bool {
+	for _, transientErr := range transientErrors {
+		if errors.Is(err, transientErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRejectedError returns true if the given error is caused by a temporary
+// unavailability of the etcd cluster that made it reject the request before it
+// was proposed, so the request was guaranteed not to be applied.
+func IsRejectedError(err error) bool {
+	for _, rejectedErr := range rejectedErrors {
+		if errors.Is(err, rejectedErr) {
+			return true
+		}
+	}
+
+	return false
+}
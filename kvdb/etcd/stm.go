@@ -5,9 +5,11 @@ package etcd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/google/btree"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -106,7 +108,7 @@ type DatabaseError struct {
 }
 
 // Unwrap returns the wrapped error in a DatabaseError.
-func (e *DatabaseError) Unwrap() error {
+func (e DatabaseError) Unwrap() error {
 	return e.err
 }
 
@@ -189,6 +191,14 @@ type STMOptions struct {
 	// ctx holds an externally provided abort context.
 	ctx                 context.Context
 	commitStatsCallback func(bool, CommitStats)
+
+	// requestTimeout is the timeout of a single request to etcd. Zero
+	// means requests only end with the abort context.
+	requestTimeout time.Duration
+
+	// requestRetries is the number of times a read request is retried if
+	// it timed out or failed with a transient error.
+	requestRetries uint32
 }
 
 // STMOptionFunc is a function that updates the passed STMOptions.
@@ -208,6 +218,22 @@ func WithCommitStatsCallback(cb func(bool, CommitStats)) STMOptionFunc {
 	}
 }
 
+// WithRequestTimeout specifies the timeout of every single request to etcd
+// made by the transaction.
+func WithRequestTimeout(timeout time.Duration) STMOptionFunc {
+	return func(so *STMOptions) {
+		so.requestTimeout = timeout
+	}
+}
+
+// WithRequestRetries specifies how many times a read request of the
+// transaction is retried if it timed out or failed with a transient error.
+func WithRequestRetries(retries uint32) STMOptionFunc {
+	return func(so *STMOptions) {
+		so.requestRetries = retries
+	}
+}
+
 // RunSTM runs the apply function by creating an STM using serializable snapshot
 // isolation, passing it to the apply and handling commit errors and retries.
 func RunSTM(cli *v3.Client, apply func(STM) error, txQueue *commitQueue,
@@ -594,6 +620,48 @@ func (ws writeSet) puts() []v3.Op {
 	return puts
 }
 
+// doRequest executes the given request to etcd with the configured request
+// timeout. If retry is true and the request timed out or failed with a
+// transient error, it's retried up to the configured number of times. Only
+// read requests may be retried, as it's unknown whether a timed out commit was
+// applied or not.
+func (s *stm) doRequest(retry bool,
+	request func(ctx context.Context) error) error {
+
+	for attempt := uint32(0); ; attempt++ {
+		ctx, cancel := s.options.ctx, func() {}
+		if s.options.requestTimeout > 0 {
+			ctx, cancel = context.WithTimeout(
+				s.options.ctx, s.options.requestTimeout,
+			)
+		}
+
+		err := request(ctx)
+		cancel()
+
+		if err == nil || !retry || attempt == s.options.requestRetries {
+			return err
+		}
+
+		// Never retry once the transaction itself was aborted.
+		if s.options.ctx.Err() != nil {
+			return err
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) &&
+			!IsTransientError(err) {
+
+			return err
+		}
+
+		select {
+		case <-time.After(RequestRetryDelay):
+		case <-s.options.ctx.Done():
+			return err
+		}
+	}
+}
+
 // FetchRangePaginatedRaw will fetch the range with the passed prefix up to the
 // passed limit per page.
 func (s *stm) FetchRangePaginatedRaw(prefix string, limit int64,
@@ -609,9 +677,15 @@ func (s *stm) FetchRangePaginatedRaw(prefix string, limit int64,
 
 	key := prefix
 	for {
-		resp, err := s.client.Get(
-			s.options.ctx, key, append(opts, s.getOpts...)...,
-		)
+		var resp *v3.GetResponse
+		err := s.doRequest(true, func(ctx context.Context) error {
+			var err error
+			resp, err = s.client.Get(
+				ctx, key, append(opts, s.getOpts...)...,
+			)
+
+			return err
+		})
 		if err != nil {
 			return DatabaseError{
 				msg: "stm.fetch() failed",
@@ -645,9 +719,16 @@ func (s *stm) FetchRangePaginatedRaw(prefix string, limit int64,
 // We'll also cache the returned key/value in the read set.
 func (s *stm) fetch(key string, opts ...v3.OpOption) ([]KV, error) {
 	s.callCount++
-	resp, err := s.client.Get(
-		s.options.ctx, key, append(opts, s.getOpts...)...,
-	)
+
+	var resp *v3.GetResponse
+	err := s.doRequest(true, func(ctx context.Context) error {
+		var err error
+		resp, err = s.client.Get(
+			ctx, key, append(opts, s.getOpts...)...,
+		)
+
+		return err
+	})
 	if err != nil {
 		return nil, DatabaseError{
 			msg: "stm.fetch() failed",
@@ -1049,7 +1130,6 @@ func (s *stm) Prefetch(keys []string, prefixes []string) {
 		[]v3.OpOption{v3.WithPrefix()}, s.getOpts...,
 	)
 
-	txn := s.client.Txn(s.options.ctx)
 	ops := make([]v3.Op, 0, len(fetchKeys)+len(fetchPrefixes))
 
 	for _, key := range fetchKeys {
@@ -1059,8 +1139,14 @@ func (s *stm) Prefetch(keys []string, prefixes []string) {
 		ops = append(ops, v3.OpGet(key, prefixOpts...))
 	}
 
-	txn.Then(ops...)
-	txnresp, err := txn.Commit()
+	// The prefetch transaction only reads, so it can safely be retried.
+	var txnresp *v3.TxnResponse
+	err := s.doRequest(true, func(ctx context.Context) error {
+		var err error
+		txnresp, err = s.client.Txn(ctx).Then(ops...).Commit()
+
+		return err
+	})
 	s.callCount++
 
 	if err != nil {
@@ -1103,19 +1189,28 @@ func (s *stm) commit() (CommitStats, error) {
 
 	// Create the compare set.
 	cmps := append(rset, wset...)
-	// Create a transaction with the optional abort context.
-	txn := s.client.Txn(s.options.ctx)
+	s.callCount++
 
-	// If the compare set holds, try executing the puts.
-	txn = txn.If(cmps...)
-	txn = txn.Then(s.wset.puts()...)
+	// The commit isn't retried, as a request that timed out may still
+	// have been applied.
+	var txnresp *v3.TxnResponse
+	err := s.doRequest(false, func(ctx context.Context) error {
+		// Create a transaction with the optional abort context.
+		txn := s.client.Txn(ctx)
 
-	// Prefetch keys and ranges in case of conflict to save as many
-	// round-trips as possible.
-	txn = txn.Else(s.rset.prefetchSet()...)
+		// If the compare set holds, try executing the puts.
+		txn = txn.If(cmps...)
+		txn = txn.Then(s.wset.puts()...)
 
-	s.callCount++
-	txnresp, err := txn.Commit()
+		// Prefetch keys and ranges in case of conflict to save as
+		// many round-trips as possible.
+		txn = txn.Else(s.rset.prefetchSet()...)
+
+		var err error
+		txnresp, err = txn.Commit()
+
+		return err
+	})
 	if err != nil {
 		return stats, DatabaseError{
 			msg: "stm.Commit() failed",
@@ -7,8 +7,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 )
 
 func reverseKVs(a []KV) []KV {
@@ -410,3 +412,147 @@ func TestManualTxError(t *testing.T) {
 	// We expect that the transacton indeed did not commit.
 	require.Equal(t, "def", f.Get("123"))
 }
+
+// TestRequestRetries asserts that only read requests that timed out or failed
+// with a transient error are retried, up to the configured number of times.
+func TestRequestRetries(t *testing.T) {
+	t.Parallel()
+
+	s := &stm{
+		options: &STMOptions{
+			ctx:            context.Background(),
+			requestTimeout: time.Minute,
+			requestRetries: 2,
+		},
+	}
+
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		name          string
+		retry         bool
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{{
+		name:          "success",
+		retry:         true,
+		errs:          []error{nil},
+		expectedCalls: 1,
+	}, {
+		name:  "transient error",
+		retry: true,
+		errs: []error{
+			rpctypes.ErrNoLeader, context.DeadlineExceeded, nil,
+		},
+		expectedCalls: 3,
+	}, {
+		name:  "retry limit",
+		retry: true,
+		errs: []error{
+			context.DeadlineExceeded, context.DeadlineExceeded,
+			context.DeadlineExceeded, nil,
+		},
+		expectedCalls: 3,
+		expectedErr:   context.DeadlineExceeded,
+	}, {
+		name:          "no retry",
+		retry:         false,
+		errs:          []error{context.DeadlineExceeded, nil},
+		expectedCalls: 1,
+		expectedErr:   context.DeadlineExceeded,
+	}, {
+		name:          "permanent error",
+		retry:         true,
+		errs:          []error{errFailed, nil},
+		expectedCalls: 1,
+		expectedErr:   errFailed,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			request := func(ctx context.Context) error {
+				// Every request is bounded by the timeout.
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+
+				calls++
+
+				return tc.errs[calls-1]
+			}
+
+			err := s.doRequest(tc.retry, request)
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+// TestIsRejectedError asserts that only errors that guarantee a request wasn't
+// applied are considered rejected, also if they are wrapped in a
+// DatabaseError, while timeouts never are.
+func TestIsRejectedError(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(err error) error {
+		return DatabaseError{msg: "stm.Commit() failed", err: err}
+	}
+
+	require.True(t, IsRejectedError(wrap(rpctypes.ErrNoLeader)))
+	require.True(t, IsRejectedError(wrap(rpctypes.ErrTooManyRequests)))
+
+	require.False(t, IsRejectedError(wrap(rpctypes.ErrTimeout)))
+	require.False(t, IsRejectedError(
+		wrap(rpctypes.ErrTimeoutDueToLeaderFail),
+	))
+	require.False(t, IsRejectedError(
+		wrap(rpctypes.ErrTimeoutDueToConnectionLost),
+	))
+	require.False(t, IsRejectedError(wrap(context.DeadlineExceeded)))
+	require.False(t, IsRejectedError(wrap(errors.New("failed"))))
+}
+
+// TestRequestTimeout asserts that a request to etcd that exceeds the request
+// timeout fails the transaction.
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	f := NewEtcdTestFixture(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	txQueue := NewCommitQueue(ctx)
+	t.Cleanup(func() {
+		cancel()
+		txQueue.Stop()
+	})
+
+	db, err := newEtcdBackend(ctx, f.BackendConfig())
+	require.NoError(t, err)
+
+	f.Put("123", "abc")
+
+	apply := func(stm STM) error {
+		_, err := stm.Get("123")
+		return err
+	}
+
+	// A generous timeout doesn't affect the transaction.
+	_, err = RunSTM(
+		db.cli, apply, txQueue, WithRequestTimeout(time.Minute),
+		WithRequestRetries(1),
+	)
+	require.NoError(t, err)
+
+	// A request can't finish within a nanosecond, so the transaction fails
+	// even after a retry.
+	_, err = RunSTM(
+		db.cli, apply, txQueue, WithRequestTimeout(time.Nanosecond),
+		WithRequestRetries(1),
+	)
+	require.ErrorContains(t, err, context.DeadlineExceeded.Error())
+}
@@ -1,6 +1,8 @@
 package kvdb
 
 import (
+	"context"
+
 	"github.com/btcsuite/btcwallet/walletdb"
 )
 
@@ -158,3 +160,17 @@ var (
 	// before it is opened or after it is closed.
 	ErrDatabaseNotOpen = walletdb.ErrDbNotOpen
 )
+
+// Compacter is implemented by database backends that can be compacted while
+// they are open.
+type Compacter interface {
+	// Compact creates a compacted copy of the database and swaps it in,
+	// regardless of when the database was last compacted. Writes are
+	// only paused while the compacted copy is swapped in. If the database
+	// is written to on every attempt to create the copy, an error is
+	// returned instead. The size of the database file
+	// before and after the compaction is returned. If the context is
+	// canceled before the compacted copy is swapped in, the compaction is
+	// aborted and the database is left untouched.
+	Compact(ctx context.Context) (int64, int64, error)
+}
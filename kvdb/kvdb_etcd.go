@@ -11,6 +11,16 @@ import (
 // defined, allowing testing our database code with etcd backend.
 const EtcdBackend = true
 
+// isTransientEtcdError returns true if the given error is caused by a
+// temporary unavailability of the etcd cluster that guarantees the transaction
+// wasn't committed. Timeouts are never considered transient, as a commit that
+// timed out may still have been applied, and retrying it could apply the
+// transaction twice. Conflicts with other transactions are already retried by
+// the STM itself.
+func isTransientEtcdError(err error) bool {
+	return etcd.IsRejectedError(err)
+}
+
 // GetEtcdTestBackend creates an embedded etcd backend for testing
 // storig the database at the passed path.
 func StartEtcdTestBackend(path string, clientPort, peerPort uint16,
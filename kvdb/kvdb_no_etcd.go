@@ -15,6 +15,12 @@ const EtcdBackend = false
 
 var errEtcdNotAvailable = fmt.Errorf("etcd backend not available")
 
+// isTransientEtcdError always returns false as the etcd backend isn't
+// available.
+func isTransientEtcdError(_ error) bool {
+	return false
+}
+
 // StartEtcdTestBackend  is a stub returning nil, and errEtcdNotAvailable error.
 func StartEtcdTestBackend(path string, clientPort, peerPort uint16,
 	logFile string) (*etcd.Config, func(), error) {
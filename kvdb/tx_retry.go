@@ -0,0 +1,127 @@
+package kvdb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/sqldb"
+)
+
+const (
+	// DefaultTxRetryBackoff is the default delay before the first retry of
+	// a transaction that failed with a transient error. The delay doubles
+	// with every further retry.
+	DefaultTxRetryBackoff = 50 * time.Millisecond
+
+	// MaxTxRetryBackoff is the maximum delay between two retries of a
+	// transaction.
+	MaxTxRetryBackoff = 5 * time.Second
+)
+
+// IsTransientTxError returns true if the given error of a database
+// transaction is caused by contention or a temporary unavailability of the
+// database backend, which means the transaction may succeed if it is retried.
+// Only errors that guarantee the transaction wasn't committed are considered
+// transient, so a retry never applies a transaction twice. Logic errors
+// returned by a transaction closure are never considered transient.
+func IsTransientTxError(err error) bool {
+	switch {
+	case err == nil:
+		return false
+
+	// The SQL backends already retry serialization errors a number of
+	// times before giving up.
+	case errors.Is(err, sqldb.ErrRetriesExceeded):
+		return true
+
+	case sqldb.IsSerializationError(sqldb.MapSQLError(err)):
+		return true
+
+	default:
+		return isTransientEtcdError(err)
+	}
+}
+
+// txRetryBackend is a database backend that retries transactions that failed
+// with a transient error, on top of any retries the wrapped backend performs
+// itself.
+type txRetryBackend struct {
+	Backend
+
+	// retryLimit is the maximum number of times a transaction is retried.
+	retryLimit uint32
+
+	// backoff is the delay before the first retry.
+	backoff time.Duration
+}
+
+// NewTxRetryBackend returns a backend that retries View and Update
+// transactions that failed with a transient error up to retryLimit times,
+// waiting for the given backoff before the first retry and doubling it for
+// every further retry. Transactions that were started manually with
+// BeginReadTx or BeginReadWriteTx are not retried, as their lifetime is
+// controlled by the caller. If retryLimit is zero, the backend is returned
+// as is.
+func NewTxRetryBackend(db Backend, retryLimit uint32,
+	backoff time.Duration) Backend {
+
+	if retryLimit == 0 {
+		return db
+	}
+
+	return &txRetryBackend{
+		Backend:    db,
+		retryLimit: retryLimit,
+		backoff:    backoff,
+	}
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter. The transaction is retried if it fails
+// with a transient error.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *txRetryBackend) View(f func(tx walletdb.ReadTx) error,
+	reset func()) error {
+
+	return b.withRetries(func() error {
+		return b.Backend.View(f, reset)
+	})
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter. The transaction is retried if it
+// fails with a transient error.
+//
+// NOTE: This is part of the walletdb.DB interface.
+func (b *txRetryBackend) Update(f func(tx walletdb.ReadWriteTx) error,
+	reset func()) error {
+
+	return b.withRetries(func() error {
+		return b.Backend.Update(f, reset)
+	})
+}
+
+// withRetries executes the given transaction and retries it as long as it
+// fails with a transient error and the retry limit isn't reached.
+func (b *txRetryBackend) withRetries(execTx func() error) error {
+	backoff := b.backoff
+
+	for attempt := uint32(0); ; attempt++ {
+		err := execTx()
+		if !IsTransientTxError(err) || attempt == b.retryLimit {
+			return err
+		}
+
+		log.Debugf("Retrying transaction after transient error in "+
+			"%v, attempt_number=%d: %v", backoff, attempt+1, err)
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > MaxTxRetryBackoff {
+			backoff = MaxTxRetryBackoff
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package kvdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTxRetryBackend asserts that only transactions that failed with a
+// transient error are retried, up to the configured limit.
+func TestTxRetryBackend(t *testing.T) {
+	t.Parallel()
+
+	backend := NewBoltFixture(t).NewBackend()
+	db := NewTxRetryBackend(backend, 3, time.Millisecond)
+
+	transientErr := &sqldb.ErrSerializationError{
+		DBError: errors.New("could not serialize access"),
+	}
+	logicErr := errors.New("logic error")
+
+	// failFirst returns a transaction closure that fails with the given
+	// error the given number of times, and counts its invocations.
+	failFirst := func(numFailures int, err error,
+		attempts *int) func(tx RwTx) error {
+
+		return func(tx RwTx) error {
+			*attempts++
+			if *attempts <= numFailures {
+				return err
+			}
+
+			return nil
+		}
+	}
+
+	// A transaction that fails with a transient error is retried until it
+	// succeeds.
+	var attempts int
+	err := Update(db, failFirst(2, transientErr, &attempts), func() {})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	// Read transactions are retried as well.
+	attempts = 0
+	err = View(db, func(tx RTx) error {
+		attempts++
+		if attempts == 1 {
+			return sqldb.ErrRetriesExceeded
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	// Logic errors are never retried.
+	attempts = 0
+	err = Update(db, failFirst(1, logicErr, &attempts), func() {})
+	require.ErrorIs(t, err, logicErr)
+	require.Equal(t, 1, attempts)
+
+	// Once the retry limit is reached, the transient error is returned.
+	attempts = 0
+	err = Update(db, failFirst(10, transientErr, &attempts), func() {})
+	require.ErrorIs(t, err, transientErr)
+	require.Equal(t, 4, attempts)
+
+	// Without a retry limit, the backend isn't wrapped at all.
+	require.Equal(t, backend, NewTxRetryBackend(backend, 0, time.Second))
+}
@@ -12,10 +12,19 @@
 // For version 0 we have the following optional data fields defined:
 //   - shortchanid: the short channel ID that a transaction is associated with,
 //     with its value set to the uint64 short channel id.
+//
+// The layout of the labels can be changed with a label template, in which the
+// placeholders {version}, {type} and {shortchanid} are replaced with the
+// respective values. Colon separated parts of the template that contain a
+// placeholder whose value isn't known are left out.
 package labels
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -68,8 +77,21 @@ const (
 
 	// LabelTypeSweepTransaction is used to label sweeps.
 	LabelTypeSweepTransaction LabelType = "sweep"
+
+	// LabelTypeConsolidation is used to label transactions that
+	// consolidate small wallet UTXOs.
+	LabelTypeConsolidation LabelType = "consolidation"
 )
 
+// labelTypes is the list of all label types.
+var labelTypes = []LabelType{
+	LabelTypeChannelOpen,
+	LabelTypeChannelClose,
+	LabelTypeJusticeTransaction,
+	LabelTypeSweepTransaction,
+	LabelTypeConsolidation,
+}
+
 // LabelField is used to tag a value within a label.
 type LabelField string
 
@@ -78,15 +100,156 @@ const (
 	ShortChanID LabelField = "shortchanid"
 )
 
-// MakeLabel creates a label with the provided type and short channel id. If
-// our short channel ID is not known, we simply return version:label_type. If
-// we do have a short channel ID set, the label will also contain its value:
+const (
+	// placeholderVersion is replaced with the label version.
+	placeholderVersion = "{version}"
+
+	// placeholderType is replaced with the label type.
+	placeholderType = "{type}"
+
+	// placeholderShortChanID is replaced with the uint64 short channel ID.
+	placeholderShortChanID = "{shortchanid}"
+
+	// templateSeparator separates the parts of a label template.
+	templateSeparator = ":"
+
+	// DefaultTemplate is the label template that produces the version 0
+	// label format.
+	DefaultTemplate = placeholderVersion + templateSeparator +
+		placeholderType + templateSeparator + string(ShortChanID) +
+		"-" + placeholderShortChanID
+)
+
+var (
+	// placeholderRegex matches all placeholders of a label template.
+	placeholderRegex = regexp.MustCompile(`{[^{}]*}`)
+
+	// labelTemplate is the template used to create labels.
+	//
+	// Note: This global is protected by the labelTemplateMtx mutex.
+	labelTemplate = DefaultTemplate
+
+	// labelTemplateMtx manages concurrent access to labelTemplate.
+	labelTemplateMtx sync.RWMutex
+)
+
+// ValidateTemplate checks that the given label template only contains known
+// placeholders, includes the label type so that labels can be categorized,
+// and that the labels it creates never exceed the label length limit. As parts
+// with the short channel ID are left out if it isn't known, the label type
+// must be in a different part than the short channel ID.
+func ValidateTemplate(template string) error {
+	if !strings.Contains(template, placeholderType) {
+		return fmt.Errorf("label template must contain %v",
+			placeholderType)
+	}
+
+	for _, part := range strings.Split(template, templateSeparator) {
+		if strings.Contains(part, placeholderType) &&
+			strings.Contains(part, placeholderShortChanID) {
+
+			return fmt.Errorf("%v and %v must be separated by %q "+
+				"in the label template, otherwise the label "+
+				"type is left out if the short channel ID "+
+				"isn't known", placeholderType,
+				placeholderShortChanID, templateSeparator)
+		}
+	}
+
+	placeholders := placeholderRegex.FindAllString(template, -1)
+	for _, placeholder := range placeholders {
+		switch placeholder {
+		case placeholderVersion, placeholderType,
+			placeholderShortChanID:
+
+		default:
+			return fmt.Errorf("unknown placeholder %v in label "+
+				"template", placeholder)
+		}
+	}
+
+	// Any braces left after removing all placeholders are unbalanced.
+	rest := placeholderRegex.ReplaceAllString(template, "")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unbalanced braces in label template")
+	}
+
+	// Make sure the longest possible label doesn't exceed the limit.
+	var longestType LabelType
+	for _, labelType := range labelTypes {
+		if len(labelType) > len(longestType) {
+			longestType = labelType
+		}
+	}
+	maxChanID := lnwire.NewShortChanIDFromInt(math.MaxUint64)
+
+	label := renderLabel(template, longestType, &maxChanID)
+	if len(label) > wtxmgr.TxLabelLimit {
+		return fmt.Errorf("label template creates labels of up to %v "+
+			"characters, exceeding the limit of %v", len(label),
+			wtxmgr.TxLabelLimit)
+	}
+
+	return nil
+}
+
+// SetTemplate validates the given label template and sets it as the template
+// for all labels created by MakeLabel.
+func SetTemplate(template string) error {
+	if err := ValidateTemplate(template); err != nil {
+		return err
+	}
+
+	labelTemplateMtx.Lock()
+	defer labelTemplateMtx.Unlock()
+
+	labelTemplate = template
+
+	return nil
+}
+
+// MakeLabel creates a label with the provided type and short channel id using
+// the current label template. With the default template, the label is
+// version:label_type if our short channel ID is not known. If we do have a
+// short channel ID set, the label will also contain its value:
 // shortchanid-{int64 chan ID}.
 func MakeLabel(labelType LabelType, channelID *lnwire.ShortChannelID) string {
-	if channelID == nil {
-		return fmt.Sprintf("%v:%v", LabelVersionZero, labelType)
+	labelTemplateMtx.RLock()
+	defer labelTemplateMtx.RUnlock()
+
+	return renderLabel(labelTemplate, labelType, channelID)
+}
+
+// renderLabel replaces the placeholders of the template with the given
+// values. Parts of the template that contain the short channel ID placeholder
+// are left out if no short channel ID is given.
+func renderLabel(template string, labelType LabelType,
+	channelID *lnwire.ShortChannelID) string {
+
+	parts := strings.Split(template, templateSeparator)
+	rendered := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.Contains(part, placeholderShortChanID) {
+			if channelID == nil {
+				continue
+			}
+
+			part = strings.ReplaceAll(
+				part, placeholderShortChanID,
+				fmt.Sprintf("%v", channelID.ToUint64()),
+			)
+		}
+
+		part = strings.ReplaceAll(
+			part, placeholderVersion,
+			fmt.Sprintf("%v", LabelVersionZero),
+		)
+		part = strings.ReplaceAll(
+			part, placeholderType, string(labelType),
+		)
+
+		rendered = append(rendered, part)
 	}
 
-	return fmt.Sprintf("%v:%v:%v-%v", LabelVersionZero, labelType,
-		ShortChanID, channelID.ToUint64())
+	return strings.Join(rendered, templateSeparator)
 }
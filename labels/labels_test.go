@@ -0,0 +1,89 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenderLabel tests that label templates are rendered correctly, leaving
+// out the parts that contain an unknown short channel ID.
+func TestRenderLabel(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(1234)
+
+	testCases := []struct {
+		name      string
+		template  string
+		labelType LabelType
+		chanID    *lnwire.ShortChannelID
+		expected  string
+	}{{
+		name:      "default without channel",
+		template:  DefaultTemplate,
+		labelType: LabelTypeSweepTransaction,
+		expected:  "0:sweep",
+	}, {
+		name:      "default with channel",
+		template:  DefaultTemplate,
+		labelType: LabelTypeChannelOpen,
+		chanID:    &chanID,
+		expected:  "0:openchannel:shortchanid-1234",
+	}, {
+		name:      "custom with channel",
+		template:  "lnd:{type}:scid={shortchanid}:v{version}",
+		labelType: LabelTypeChannelClose,
+		chanID:    &chanID,
+		expected:  "lnd:closechannel:scid=1234:v0",
+	}, {
+		name:      "custom without channel",
+		template:  "lnd:{type}:scid={shortchanid}:v{version}",
+		labelType: LabelTypeJusticeTransaction,
+		expected:  "lnd:justicetx:v0",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			label := renderLabel(
+				tc.template, tc.labelType, tc.chanID,
+			)
+			require.Equal(t, tc.expected, label)
+		})
+	}
+}
+
+// TestValidateTemplate tests that invalid label templates are rejected.
+func TestValidateTemplate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateTemplate(DefaultTemplate))
+	require.NoError(t, ValidateTemplate("acct/{type}:{shortchanid}"))
+
+	require.ErrorContains(
+		t, ValidateTemplate("{version}:{shortchanid}"), "must contain",
+	)
+	require.ErrorContains(
+		t, ValidateTemplate("{type}:{chanpoint}"), "unknown",
+	)
+	require.ErrorContains(
+		t, ValidateTemplate("{type}:{shortchanid"), "unbalanced",
+	)
+
+	// The label type would be lost with an unknown short channel ID if
+	// both were in the same part.
+	require.ErrorContains(
+		t, ValidateTemplate("{version}:{type}-{shortchanid}"),
+		"must be separated",
+	)
+
+	tooLong := "{type}:" + strings.Repeat("x", wtxmgr.TxLabelLimit)
+	require.ErrorContains(t, ValidateTemplate(tooLong), "exceeding")
+}
@@ -1,16 +1,24 @@
 package lncfg
 
+import "time"
+
 // AutoPilot holds the configuration options for the daemon's autopilot.
 //
 //nolint:lll
 type AutoPilot struct {
-	Active         bool               `long:"active" description:"If the autopilot agent should be active or not."`
-	Heuristic      map[string]float64 `long:"heuristic" description:"Heuristic to activate, and the weight to give it during scoring."`
-	MaxChannels    int                `long:"maxchannels" description:"The maximum number of channels that should be created"`
-	Allocation     float64            `long:"allocation" description:"The percentage of total funds that should be committed to automatic channel establishment"`
-	MinChannelSize int64              `long:"minchansize" description:"The smallest channel that the autopilot agent should create"`
-	MaxChannelSize int64              `long:"maxchansize" description:"The largest channel that the autopilot agent should create"`
-	Private        bool               `long:"private" description:"Whether the channels created by the autopilot agent should be private or not. Private channels won't be announced to the network."`
-	MinConfs       int32              `long:"minconfs" description:"The minimum number of confirmations each of your inputs in funding transactions created by the autopilot agent must have."`
-	ConfTarget     uint32             `long:"conftarget" description:"The confirmation target (in blocks) for channels opened by autopilot."`
+	Active                    bool               `long:"active" description:"If the autopilot agent should be active or not."`
+	Heuristic                 map[string]float64 `long:"heuristic" description:"Heuristic to activate, and the weight to give it during scoring."`
+	MaxChannels               int                `long:"maxchannels" description:"The maximum number of channels that should be created"`
+	Allocation                float64            `long:"allocation" description:"The percentage of total funds that should be committed to automatic channel establishment"`
+	MinChannelSize            int64              `long:"minchansize" description:"The smallest channel that the autopilot agent should create"`
+	MaxChannelSize            int64              `long:"maxchansize" description:"The largest channel that the autopilot agent should create"`
+	Private                   bool               `long:"private" description:"Whether the channels created by the autopilot agent should be private or not. Private channels won't be announced to the network."`
+	MinConfs                  int32              `long:"minconfs" description:"The minimum number of confirmations each of your inputs in funding transactions created by the autopilot agent must have."`
+	ConfTarget                uint32             `long:"conftarget" description:"The confirmation target (in blocks) for channels opened by autopilot."`
+	RecommendOnly             bool               `long:"recommend-only" description:"If set, the autopilot agent only computes the channels it would open and exposes them as recommendations over RPC instead of opening them."`
+	FeeBudget                 int64              `long:"fee-budget" description:"The maximum amount of on-chain fees in satoshis the autopilot agent may spend on opening channels within a single fee budget period. Once exceeded, no channels are opened until the period ends. Set to 0 to disable the fee budget."`
+	FeeBudgetPeriod           time.Duration      `long:"fee-budget-period" description:"The length of the period the fee budget applies to. The fees spent are persisted, so a restart doesn't reset them within the period."`
+	ActivationDelay           time.Duration      `long:"activation-delay" description:"The time that must pass after the autopilot agent was first started before it opens any channels. The start time is persisted, so a restart doesn't reset the delay."`
+	ActivationDelayBlocks     uint32             `long:"activation-delay-blocks" description:"The number of blocks that must be mined after the autopilot agent was first started before it opens any channels. The start height is persisted, so a restart doesn't reset the delay. If both delays are set, both must pass."`
+	RequireManualFirstChannel bool               `long:"require-manual-first-channel" description:"If set, the autopilot agent doesn't open any channels until at least one channel was opened manually."`
 }
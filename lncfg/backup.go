@@ -0,0 +1,53 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxBackupDebounce is the maximum quiet period before the channel backup file
+// is updated. It limits how long the backup file may lag behind the channel
+// state.
+const MaxBackupDebounce = 10 * time.Minute
+
+// DefaultBackupDebounceMaxWait is the default maximum time an update of the
+// channel backup file is delayed by continuous channel changes.
+const DefaultBackupDebounceMaxWait = MaxBackupDebounce
+
+// Backup holds the configuration options for the static channel backup file.
+//
+//nolint:lll
+type Backup struct {
+	Debounce time.Duration `long:"debounce" description:"The quiet period after a channel change before the channel backup file is rewritten. Further changes within this period are coalesced into a single write, and a pending update is written on shutdown. Set to 0 to rewrite the file on every change."`
+
+	DebounceMaxWait time.Duration `long:"debounce-max-wait" description:"The maximum time an update of the channel backup file is delayed after the first pending channel change, even if channels keep changing within the debounce period. Must not be below the debounce period."`
+}
+
+// DefaultBackup returns the default configuration for the static channel
+// backup file.
+func DefaultBackup() *Backup {
+	return &Backup{
+		DebounceMaxWait: DefaultBackupDebounceMaxWait,
+	}
+}
+
+// Validate checks the values configured for the channel backup file.
+func (b *Backup) Validate() error {
+	if b.Debounce < 0 || b.Debounce > MaxBackupDebounce {
+		return fmt.Errorf("debounce must be between 0 and %v, got %v",
+			MaxBackupDebounce, b.Debounce)
+	}
+
+	if b.Debounce != 0 && (b.DebounceMaxWait < b.Debounce ||
+		b.DebounceMaxWait > MaxBackupDebounce) {
+
+		return fmt.Errorf("debounce-max-wait must be between "+
+			"debounce (%v) and %v, got %v", b.Debounce,
+			MaxBackupDebounce, b.DebounceMaxWait)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Backup implements the Validator interface.
+var _ Validator = (*Backup)(nil)
@@ -1,6 +1,12 @@
 package lncfg
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
 
 const (
 	// DefaultTxPollingJitter defines the default TxPollingIntervalJitter
@@ -16,6 +22,7 @@ type Bitcoind struct {
 	Dir                  string        `long:"dir" description:"The base directory that contains the node's data, logs, configuration file, etc."`
 	ConfigPath           string        `long:"config" description:"Configuration filepath. If not set, will default to the default filename under 'dir'."`
 	RPCCookie            string        `long:"rpccookie" description:"Authentication cookie file for RPC connections. If not set, will default to .cookie under 'dir'."`
+	RPCCookieRefresh     time.Duration `long:"rpccookie-refresh-interval" description:"The interval at which the rpccookie file is re-read, so lnd picks up the new cookie after bitcoind restarted. Set to 0 to read the cookie only once at startup."`
 	RPCHost              string        `long:"rpchost" description:"The daemon's rpc listening address. If a port is omitted, then the default port for the selected chain parameters will be used."`
 	RPCUser              string        `long:"rpcuser" description:"Username for RPC connections"`
 	RPCPass              string        `long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
@@ -27,4 +34,39 @@ type Bitcoind struct {
 	RPCPolling           bool          `long:"rpcpolling" description:"Poll the bitcoind RPC interface for block and transaction notifications instead of using the ZMQ interface"`
 	BlockPollingInterval time.Duration `long:"blockpollinginterval" description:"The interval that will be used to poll bitcoind for new blocks. Only used if rpcpolling is true."`
 	TxPollingInterval    time.Duration `long:"txpollinginterval" description:"The interval that will be used to poll bitcoind for new tx. Only used if rpcpolling is true."`
+
+	Fallback *BitcoindFallback `group:"fallback" namespace:"fallback"`
+}
+
+// BitcoindFallback holds the configuration options for the connection to a
+// fallback bitcoind node, which lnd switches to if the primary node becomes
+// unavailable.
+//
+//nolint:lll
+type BitcoindFallback struct {
+	RPCHost   string `long:"rpchost" description:"The fallback node's rpc listening address, including the port. If set, lnd fails over to this node once the chain backend health check fails consistently. Requires rpcpolling to be enabled."`
+	RPCUser   string `long:"rpcuser" description:"Username for RPC connections to the fallback node"`
+	RPCPass   string `long:"rpcpass" default-mask:"-" description:"Password for RPC connections to the fallback node"`
+	RPCCookie string `long:"rpccookie" description:"Authentication cookie file for RPC connections to the fallback node"`
+}
+
+// Enabled returns true if a fallback bitcoind node is configured.
+func (f *BitcoindFallback) Enabled() bool {
+	return f != nil && f.RPCHost != ""
+}
+
+// ReadBitcoindCookie reads the RPC user name and password from the given
+// bitcoind cookie file.
+func ReadBitcoindCookie(path string) (string, string, error) {
+	cookie, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read cookie file: %w", err)
+	}
+
+	splitCookie := strings.Split(string(cookie), ":")
+	if len(splitCookie) != 2 {
+		return "", "", errors.New("cookie file has a wrong format")
+	}
+
+	return splitCookie[0], splitCookie[1], nil
 }
@@ -0,0 +1,82 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+const (
+	// DefaultTipLagCheckInterval is the default interval at which the
+	// chain backend is checked for lagging behind the network.
+	DefaultTipLagCheckInterval = 30 * time.Second
+
+	// MinTipLagCheckInterval is the minimum interval at which the chain
+	// backend can be checked for lagging behind the network.
+	MinTipLagCheckInterval = time.Second
+)
+
+// ChainOptions holds chain related configuration options that apply
+// independently of the selected network and chain backend.
+//
+//nolint:lll
+type ChainOptions struct {
+	ParamsFile string `long:"params-file" description:"Path to a JSON file that defines the parameters of a custom network, such as its genesis block, net magic, ports and HD coin type. The network is used instead of one of the well-known networks selected through the bitcoin.* options."`
+
+	MaxTipLag uint32 `long:"max-tip-lag" description:"The maximum number of blocks the chain backend may lag behind the best block known on the network. If exceeded, lnd stops forwarding HTLCs until the backend caught up. Set to 0 to disable."`
+
+	MaxTipLagTime time.Duration `long:"max-tip-lag-time" description:"The maximum time that may pass without the chain backend advancing its best block. If exceeded, lnd stops forwarding HTLCs until the backend caught up. Set to 0 to disable."`
+
+	TipLagCheckInterval time.Duration `long:"tip-lag-check-interval" description:"The interval at which the chain backend is checked for lagging behind the network."`
+
+	BlockDownloadConcurrency uint32 `long:"block-download-concurrency" description:"The number of blocks fetched in parallel when the chain is scanned for historical confirmations and spends, for example during a rescan. Blocks are still processed in order. Only applies to the btcd and bitcoind backends. Must be between 1 and 16."`
+}
+
+// DefaultChainOptions returns the default chain related configuration.
+func DefaultChainOptions() *ChainOptions {
+	return &ChainOptions{
+		TipLagCheckInterval: DefaultTipLagCheckInterval,
+		BlockDownloadConcurrency: uint32(
+			chainntnfs.DefaultBlockDownloadConcurrency,
+		),
+	}
+}
+
+// TipLagMonitorEnabled returns true if any of the chain backend lag checks is
+// enabled.
+func (c *ChainOptions) TipLagMonitorEnabled() bool {
+	return c.MaxTipLag != 0 || c.MaxTipLagTime != 0
+}
+
+// Validate checks the chain related configuration options.
+func (c *ChainOptions) Validate() error {
+	if c.BlockDownloadConcurrency < 1 ||
+		c.BlockDownloadConcurrency > chainntnfs.MaxBlockDownloadConcurrency {
+
+		return fmt.Errorf("block-download-concurrency must be between "+
+			"1 and %d, got %d", chainntnfs.MaxBlockDownloadConcurrency,
+			c.BlockDownloadConcurrency)
+	}
+
+	if !c.TipLagMonitorEnabled() {
+		return nil
+	}
+
+	if c.MaxTipLagTime < 0 {
+		return fmt.Errorf("max-tip-lag-time must not be negative, "+
+			"got %v", c.MaxTipLagTime)
+	}
+
+	if c.TipLagCheckInterval < MinTipLagCheckInterval {
+		return fmt.Errorf("tip-lag-check-interval must be at least "+
+			"%v, got %v", MinTipLagCheckInterval,
+			c.TipLagCheckInterval)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure ChainOptions implements the Validator
+// interface.
+var _ Validator = (*ChainOptions)(nil)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/lightningnetwork/lnd/cluster"
 )
@@ -72,5 +73,27 @@ func (c *Cluster) Validate() error {
 	}
 }
 
+// ValidateDBTimeouts makes sure that the request timeouts of an etcd database
+// backend don't conflict with the leader election. A single database request
+// including all of its retries must fail before the leader session expires,
+// otherwise a slow etcd cluster could cost this node its leadership while it
+// is still waiting for a database request.
+func (c *Cluster) ValidateDBTimeouts(db *DB) error {
+	if !c.EnableLeaderElection || db.Backend != EtcdBackend {
+		return nil
+	}
+
+	maxRequestDuration := db.Etcd.MaxRequestDuration()
+	sessionTTL := time.Duration(c.LeaderSessionTTL) * time.Second
+	if maxRequestDuration >= sessionTTL {
+		return fmt.Errorf("the maximum duration of an etcd database "+
+			"request including retries (%v) must be shorter than "+
+			"the leader session TTL (%v)", maxRequestDuration,
+			sessionTTL)
+	}
+
+	return nil
+}
+
 // Compile-time constraint to ensure Workers implements the Validator interface.
 var _ Validator = (*Cluster)(nil)
@@ -0,0 +1,34 @@
+package lncfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClusterValidateDBTimeouts tests that etcd database request timeouts
+// must be shorter than the leader session TTL.
+func TestClusterValidateDBTimeouts(t *testing.T) {
+	cluster := lncfg.DefaultCluster()
+	cluster.EnableLeaderElection = true
+	cluster.LeaderSessionTTL = 10
+
+	db := lncfg.DefaultDB()
+	db.Backend = lncfg.EtcdBackend
+	require.NoError(t, cluster.ValidateDBTimeouts(db))
+
+	// Two attempts of 4s each plus the retry delay are within the TTL.
+	db.Etcd.RequestTimeout = 4 * time.Second
+	db.Etcd.RequestRetries = 1
+	require.NoError(t, cluster.ValidateDBTimeouts(db))
+
+	// A third attempt exceeds the TTL.
+	db.Etcd.RequestRetries = 2
+	require.Error(t, cluster.ValidateDBTimeouts(db))
+
+	// Without leader election, the timeouts aren't restricted.
+	cluster.EnableLeaderElection = false
+	require.NoError(t, cluster.ValidateDBTimeouts(db))
+}
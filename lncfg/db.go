@@ -38,6 +38,10 @@ const (
 	SqliteBackend              = "sqlite"
 	DefaultBatchCommitInterval = 500 * time.Millisecond
 
+	// DefaultIntegrityCheckTimeout is the default maximum runtime
+	// of the startup integrity check of the channel database.
+	DefaultIntegrityCheckTimeout = 5 * time.Minute
+
 	defaultPostgresMaxConnections = 50
 	defaultSqliteMaxConnections   = 2
 
@@ -77,6 +81,16 @@ type DB struct {
 
 	BatchCommitInterval time.Duration `long:"batch-commit-interval" description:"The maximum duration the channel graph batch schedulers will wait before attempting to commit a batch of pending updates. This can be tradeoff database contenion for commit latency."`
 
+	TxRetryLimit uint32 `long:"txn-retry-limit" description:"The number of times a transaction on the channel database is retried if it failed with a transient error that guarantees it wasn't committed, such as a serialization error under contention or an etcd cluster without a leader. Logic errors and etcd timeouts are never retried. Only applies to the etcd, postgres and sqlite backends, in addition to their own retries. Set to 0 to disable."`
+
+	TxRetryBackoff time.Duration `long:"txn-retry-backoff" description:"The delay before the first retry of a failed transaction, doubled for every further retry up to a maximum of 5s."`
+
+	StartupIntegrityCheck bool `long:"startup-integrity-check" description:"If set, a consistency check of the channel database is run on startup. It reports open channels that are missing revocation log entries or revocation log amount data, and inconsistencies of the channel outpoint index."`
+
+	StartupIntegrityCheckStrict bool `long:"startup-integrity-check-strict" description:"If set, lnd refuses to start if the startup integrity check finds a critical inconsistency, such as missing revocation log entries. Has no effect unless startup-integrity-check is set."`
+
+	StartupIntegrityCheckTimeout time.Duration `long:"startup-integrity-check-timeout" description:"The maximum time the startup integrity check may take. If the check takes longer it is aborted and startup continues with only a part of the database checked. Set to 0 to disable the limit."`
+
 	Etcd *etcd.Config `group:"etcd" namespace:"etcd" description:"Etcd settings."`
 
 	Bolt *kvdb.BoltConfig `group:"bolt" namespace:"bolt" description:"Bolt settings."`
@@ -97,8 +111,10 @@ type DB struct {
 // DefaultDB creates and returns a new default DB config.
 func DefaultDB() *DB {
 	return &DB{
-		Backend:             BoltBackend,
-		BatchCommitInterval: DefaultBatchCommitInterval,
+		Backend:                      BoltBackend,
+		BatchCommitInterval:          DefaultBatchCommitInterval,
+		TxRetryBackoff:               kvdb.DefaultTxRetryBackoff,
+		StartupIntegrityCheckTimeout: DefaultIntegrityCheckTimeout,
 		Bolt: &kvdb.BoltConfig{
 			NoFreelistSync:    true,
 			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
@@ -163,9 +179,27 @@ func (db *DB) Validate() error {
 			"backend '%v'", db.Backend)
 	}
 
+	if db.TxRetryBackoff < 0 || db.TxRetryBackoff > kvdb.MaxTxRetryBackoff {
+		return fmt.Errorf("txn-retry-backoff must be between 0 and %v",
+			kvdb.MaxTxRetryBackoff)
+	}
+
+	if db.StartupIntegrityCheckTimeout < 0 {
+		return fmt.Errorf("startup-integrity-check-timeout must not " +
+			"be negative")
+	}
+
 	return nil
 }
 
+// withTxRetries wraps the given remote database backend to retry transactions
+// that failed with a transient error, if configured.
+func (db *DB) withTxRetries(backend kvdb.Backend) kvdb.Backend {
+	return kvdb.NewTxRetryBackend(
+		backend, db.TxRetryLimit, db.TxRetryBackoff,
+	)
+}
+
 // Init should be called upon start to pre-initialize database access dependent
 // on configuration.
 func (db *DB) Init(ctx context.Context, dbPath string) error {
@@ -268,8 +302,8 @@ func GetSqliteConfigKVDB(cfg *sqldb.SqliteConfig) *sqlite.Config {
 // GetBackends returns a set of kvdb.Backends as set in the DB config.
 func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 	walletDBPath, towerServerDBPath string, towerClientEnabled,
-	towerServerEnabled bool, logger btclog.Logger) (*DatabaseBackends,
-	error) {
+	towerServerEnabled bool, diskSpaceCheck kvdb.DiskSpaceCheck,
+	logger btclog.Logger) (*DatabaseBackends, error) {
 
 	// We keep track of all the kvdb backends we actually open and return a
 	// reference to their close function so they can be cleaned up properly
@@ -308,6 +342,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 			return nil, fmt.Errorf("error opening etcd DB: %w", err)
 		}
 		closeFuncs[NSChannelDB] = etcdBackend.Close
+		etcdBackend = db.withTxRetries(etcdBackend)
 
 		etcdMacaroonBackend, err := kvdb.Open(
 			kvdb.EtcdBackendName, ctx,
@@ -398,6 +433,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 				"DB: %v", err)
 		}
 		closeFuncs[NSChannelDB] = postgresBackend.Close
+		postgresBackend = db.withTxRetries(postgresBackend)
 
 		postgresMacaroonBackend, err := kvdb.Open(
 			kvdb.PostgresBackendName, ctx,
@@ -520,6 +556,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 				"DB: %v", err)
 		}
 		closeFuncs[NSChannelDB] = sqliteBackend.Close
+		sqliteBackend = db.withTxRetries(sqliteBackend)
 
 		sqliteMacaroonBackend, err := kvdb.Open(
 			kvdb.SqliteBackendName, ctx, sqliteConfig, walletDBPath,
@@ -620,12 +657,16 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 
 	// We're using all bbolt based databases by default.
 	boltBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
-		DBPath:            chanDBPath,
-		DBFileName:        ChannelDBName,
-		DBTimeout:         db.Bolt.DBTimeout,
-		NoFreelistSync:    db.Bolt.NoFreelistSync,
-		AutoCompact:       db.Bolt.AutoCompact,
-		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		DBPath:              chanDBPath,
+		DBFileName:          ChannelDBName,
+		DBTimeout:           db.Bolt.DBTimeout,
+		NoFreelistSync:      db.Bolt.NoFreelistSync,
+		AutoCompact:         db.Bolt.AutoCompact,
+		AutoCompactMinAge:   db.Bolt.AutoCompactMinAge,
+		AutoCompactInterval: db.Bolt.AutoCompactInterval,
+		AutoCompactIdleTime: db.Bolt.AutoCompactIdleTime,
+		CompactOnDemand:     db.Bolt.CompactOnDemand,
+		DiskSpaceCheck:      diskSpaceCheck,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening bolt DB: %w", err)
@@ -639,6 +680,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		NoFreelistSync:    db.Bolt.NoFreelistSync,
 		AutoCompact:       db.Bolt.AutoCompact,
 		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		DiskSpaceCheck:    diskSpaceCheck,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening macaroon DB: %w", err)
@@ -652,6 +694,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		NoFreelistSync:    db.Bolt.NoFreelistSync,
 		AutoCompact:       db.Bolt.AutoCompact,
 		AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+		DiskSpaceCheck:    diskSpaceCheck,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error opening decayed log DB: %w", err)
@@ -670,6 +713,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 				NoFreelistSync:    db.Bolt.NoFreelistSync,
 				AutoCompact:       db.Bolt.AutoCompact,
 				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+				DiskSpaceCheck:    diskSpaceCheck,
 			},
 		)
 		if err != nil {
@@ -691,6 +735,7 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 				NoFreelistSync:    db.Bolt.NoFreelistSync,
 				AutoCompact:       db.Bolt.AutoCompact,
 				AutoCompactMinAge: db.Bolt.AutoCompactMinAge,
+				DiskSpaceCheck:    diskSpaceCheck,
 			},
 		)
 		if err != nil {
@@ -0,0 +1,46 @@
+package lncfg
+
+import "fmt"
+
+const (
+	// DLPPolicyForceClose force closes a channel if the channel
+	// reestablish message of the peer shows that it lost state or that it
+	// misbehaved.
+	DLPPolicyForceClose = "force-close"
+
+	// DLPPolicyManual fails such a channel without force closing it, so
+	// that an operator can review it and decide whether to force close it
+	// manually.
+	DLPPolicyManual = "manual"
+)
+
+// DLP holds the configuration options for handling the data loss protection
+// fields of a peer's channel reestablish message.
+//
+//nolint:lll
+type DLP struct {
+	Policy string `long:"policy" choice:"force-close" choice:"manual" description:"How to react if the peer's channel reestablish message shows that it lost channel state or sent invalid commitment secrets. 'force-close' force closes the channel to protect our funds, 'manual' only fails the channel and logs an error so that an operator can review it and force close it manually."`
+
+	SafeReconnectMode bool `long:"safe-reconnect-mode" description:"If set, lnd stops broadcasting commitment transactions of all channels and any contract resolution or sweep transaction as soon as a peer proves that our local state of any channel is outdated, for example because lnd was restored from an old backup. An alert with recovery instructions is logged. The halt is stored in the channel database and stays in place after a restart. Justice transactions are still broadcast. Channels restored from a static channel backup never trigger the halt."`
+}
+
+// DefaultDLP returns the default data loss protection configuration.
+func DefaultDLP() *DLP {
+	return &DLP{
+		Policy: DLPPolicyForceClose,
+	}
+}
+
+// Validate checks the values configured for data loss protection.
+func (d *DLP) Validate() error {
+	switch d.Policy {
+	case DLPPolicyForceClose, DLPPolicyManual:
+		return nil
+
+	default:
+		return fmt.Errorf("invalid dlp policy: %v", d.Policy)
+	}
+}
+
+// Compile-time constraint to ensure DLP implements the Validator interface.
+var _ Validator = (*DLP)(nil)
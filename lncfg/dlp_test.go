@@ -0,0 +1,81 @@
+package lncfg_test
+
+import (
+	"testing"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDLPPolicy asserts that each data loss protection policy can be parsed
+// from the command line and passes validation, while any other value is
+// rejected by both.
+func TestDLPPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy string
+		valid  bool
+	}{
+		{
+			name:   "force close",
+			policy: lncfg.DLPPolicyForceClose,
+			valid:  true,
+		},
+		{
+			name:   "manual",
+			policy: lncfg.DLPPolicyManual,
+			valid:  true,
+		},
+		{
+			name:   "unknown",
+			policy: "ignore",
+		},
+		{
+			name:   "wrong case",
+			policy: "Manual",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := lncfg.DefaultDLP()
+			_, err := flags.ParseArgs(
+				cfg, []string{"--policy=" + test.policy},
+			)
+			if !test.valid {
+				require.Error(t, err)
+
+				// Validation must reject the value too, in
+				// case it was set without the parser.
+				cfg.Policy = test.policy
+				require.Error(t, cfg.Validate())
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.policy, cfg.Policy)
+			require.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+// TestDefaultDLP asserts that channels are force closed by default.
+func TestDefaultDLP(t *testing.T) {
+	t.Parallel()
+
+	cfg := lncfg.DefaultDLP()
+	require.Equal(t, lncfg.DLPPolicyForceClose, cfg.Policy)
+	require.False(t, cfg.SafeReconnectMode)
+	require.NoError(t, cfg.Validate())
+}
@@ -17,4 +17,6 @@ type Fee struct {
 	URL              string        `long:"url" description:"Optional URL for external fee estimation. If no URL is specified, the method for fee estimation will depend on the chosen backend and network. Must be set for neutrino on mainnet."`
 	MinUpdateTimeout time.Duration `long:"min-update-timeout" description:"The minimum interval in which fees will be updated from the specified fee URL."`
 	MaxUpdateTimeout time.Duration `long:"max-update-timeout" description:"The maximum interval in which fees will be updated from the specified fee URL."`
+	CacheTTL         time.Duration `long:"cache-ttl" description:"The maximum age of the fee estimates fetched from the fee URL. If the fee URL can't be reached for longer than this, fee estimation fails instead of using the outdated estimates. Must be greater than max-update-timeout. Set to 0 to always use the latest estimates, regardless of their age."`
+	URLSchema        string        `long:"url-schema" description:"Optional jq-like expression that describes the JSON layout of the fee URL response. Either a path to an object mapping conf targets to fee rates (e.g. '.fee_by_block_target') or an object mapping conf targets to the paths of individual fee rates (e.g. '{1: .fastestFee, 6: .hourFee}'), optionally followed by a factor to convert the fee rates to sat/kvB (e.g. '. * 1000' for sat/vB). If not set, the response must contain a 'fee_by_block_target' object with fee rates in sat/kvB."`
 }
@@ -0,0 +1,73 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/feeautopilot"
+)
+
+// FeeAutopilot holds the configuration options for the automatic adjustment of
+// the routing fees of our channels.
+//
+//nolint:lll
+type FeeAutopilot struct {
+	Active bool `long:"active" description:"Automatically adjust the fee rates of our channels based on their local balance and the flow of forwards through them. Fee rates are raised if the local balance of a channel is scarce or drained by forwards, and lowered if it is abundant or filled up by forwards. Base fees are left untouched."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the fee rates of our channels are evaluated."`
+
+	MinUpdateInterval time.Duration `long:"min-update-interval" description:"The minimum time between two policy updates of a channel, including manual ones. The flow of a channel is observed over the same period. Every update is gossiped to the network, so this must be at least 1h."`
+
+	MinFeeRate uint32 `long:"min-fee-rate" description:"The lowest fee rate in parts per million that is set."`
+
+	MaxFeeRate uint32 `long:"max-fee-rate" description:"The highest fee rate in parts per million that is set, at most 100000."`
+
+	Step float64 `long:"step" description:"The relative change of the fee rate of a single adjustment, between 0 and 1. Every adjustment changes the fee rate by at least 1 ppm."`
+}
+
+// DefaultFeeAutopilot returns the default configuration for the fee
+// autopilot.
+func DefaultFeeAutopilot() *FeeAutopilot {
+	return &FeeAutopilot{
+		Interval:          feeautopilot.DefaultInterval,
+		MinUpdateInterval: feeautopilot.DefaultMinUpdateInterval,
+		MinFeeRate:        feeautopilot.DefaultMinFeeRate,
+		MaxFeeRate:        feeautopilot.DefaultMaxFeeRate,
+		Step:              feeautopilot.DefaultStep,
+	}
+}
+
+// Validate checks the values configured for the fee autopilot.
+func (f *FeeAutopilot) Validate() error {
+	if f.Interval < feeautopilot.MinInterval {
+		return fmt.Errorf("interval must be at least %v, got %v",
+			feeautopilot.MinInterval, f.Interval)
+	}
+
+	if f.MinUpdateInterval < feeautopilot.MinMinUpdateInterval {
+		return fmt.Errorf("min-update-interval must be at least %v, "+
+			"got %v", feeautopilot.MinMinUpdateInterval,
+			f.MinUpdateInterval)
+	}
+
+	if f.MaxFeeRate > feeautopilot.MaxAllowedFeeRate {
+		return fmt.Errorf("max-fee-rate must be at most %v, got %v",
+			feeautopilot.MaxAllowedFeeRate, f.MaxFeeRate)
+	}
+
+	if f.MinFeeRate > f.MaxFeeRate {
+		return fmt.Errorf("min-fee-rate %v exceeds max-fee-rate %v",
+			f.MinFeeRate, f.MaxFeeRate)
+	}
+
+	if f.Step <= 0 || f.Step >= 1 {
+		return fmt.Errorf("step must be between 0 and 1, got %v",
+			f.Step)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure FeeAutopilot implements the Validator
+// interface.
+var _ Validator = (*FeeAutopilot)(nil)
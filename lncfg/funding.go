@@ -0,0 +1,33 @@
+package lncfg
+
+import "fmt"
+
+// Funding holds the configuration options for the channel funding flow.
+//
+//nolint:lll
+type Funding struct {
+	MinInputConfs int32 `long:"min-input-confs" description:"The minimum number of confirmations each wallet input used to fund a channel must have. This overrides a lower min_confs or spend_unconfirmed setting of individual channel open requests. Set to 0 to keep the per-request behavior."`
+
+	MinFundingConfs uint16 `long:"min-funding-confs" description:"The lowest number of confirmations of the funding transaction that the funding_confs field of an open channel request may ask for. Must be at least 1."`
+
+	RejectTorOnlyPeers bool `long:"reject-tor-only-peers" description:"Reject incoming channels from peers that are only reachable over Tor. A peer is considered Tor-only if all of its advertised addresses are onion addresses or, if it doesn't advertise any, we connected to it over an onion address. Has no effect if tor.active is set, as Tor-only peers can be reached then."`
+}
+
+// Validate checks the values configured for the funding flow.
+func (f *Funding) Validate() error {
+	if f.MinInputConfs < 0 {
+		return fmt.Errorf("min-input-confs must not be negative, "+
+			"got %v", f.MinInputConfs)
+	}
+
+	if f.MinFundingConfs < 1 {
+		return fmt.Errorf("min-funding-confs must be at least 1, "+
+			"got %v", f.MinFundingConfs)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Funding implements the Validator
+// interface.
+var _ Validator = (*Funding)(nil)
@@ -18,6 +18,12 @@ type Gossip struct {
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
 
 	SubBatchDelay time.Duration `long:"sub-batch-delay" description:"The duration to wait before sending the next announcement batch if there are multiple. Use a small value if there are a lot announcements and they need to be broadcast quickly."`
+
+	PolicyUpdateBatchSize int `long:"policy-update-batch-size" description:"The maximum number of our channels whose policy is updated at once when updating the policies of many channels. Further updates are queued and applied in batches, so that we don't flood the network with channel updates."`
+
+	PolicyUpdateInterval time.Duration `long:"policy-update-interval" description:"The interval between two batches of queued policy updates of our channels."`
+
+	MaxFilterWindow time.Duration `long:"max-filter-window" description:"The maximum duration into the past for which historical gossip data is sent in reply to a remote peer's gossip_timestamp_filter. Filters that start further in the past are honored from the start of the window only. A window of at least two weeks ensures peers receive the latest update of every active channel. Set to 0 to not cap the window."`
 }
 
 // Parse the pubkeys for the pinned syncers.
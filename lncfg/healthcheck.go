@@ -3,7 +3,10 @@ package lncfg
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
+
+	"github.com/lightningnetwork/lnd/healthcheck"
 )
 
 var (
@@ -34,6 +37,10 @@ type HealthCheckConfig struct {
 	TorConnection *CheckConfig `group:"torconnection" namespace:"torconnection"`
 
 	RemoteSigner *CheckConfig `group:"remotesigner" namespace:"remotesigner"`
+
+	FeeEstimator *FeeCheckConfig `group:"feeestimator" namespace:"feeestimator"`
+
+	ChainTipLag *CheckConfig `group:"chaintiplag" namespace:"chaintiplag"`
 }
 
 // Validate checks the values configured for our health checks.
@@ -56,10 +63,30 @@ func (h *HealthCheckConfig) Validate() error {
 		return errors.New("disk required ratio must be in [0:1)")
 	}
 
+	if h.DiskCheck.OperationMultiplier != 0 &&
+		h.DiskCheck.OperationMultiplier < 1 {
+
+		return errors.New("disk operation multiplier must be 0 or at " +
+			"least 1")
+	}
+
 	if err := h.TorConnection.validate("tor connection"); err != nil {
 		return err
 	}
 
+	if err := h.FeeEstimator.validate("fee estimator"); err != nil {
+		return err
+	}
+
+	if h.FeeEstimator.Attempts != 0 && h.FeeEstimator.MaxStaleness <= 0 {
+		return errors.New("fee estimator max staleness must be " +
+			"positive")
+	}
+
+	if err := h.ChainTipLag.validate("chain tip lag"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -102,5 +129,76 @@ func (c *CheckConfig) validate(name string) error {
 type DiskCheckConfig struct {
 	RequiredRemaining float64 `long:"diskrequired" description:"The minimum ratio of free disk space to total capacity that we allow before shutting lnd down safely."`
 
+	OperationMultiplier float64 `long:"operationmultiplier" description:"The free disk space required before a database compaction or migration is started, as a multiple of a conservative estimate of the space the operation writes. If less space is available, a migration is refused, which stops lnd from starting, while the optional compaction on startup is skipped with a warning. Set to 0 to disable the check."`
+
+	*CheckConfig
+}
+
+// CheckOperationSpace returns an error if the file system of the given
+// directory doesn't have enough free space for an operation that writes up to
+// the given number of bytes, multiplied by the configured operation
+// multiplier. The operation describes what is about to be started.
+func (c *DiskCheckConfig) CheckOperationSpace(operation, dir string,
+	required uint64) error {
+
+	if c.OperationMultiplier == 0 {
+		return nil
+	}
+
+	available, err := healthcheck.AvailableDiskSpace(dir)
+	if err != nil {
+		return fmt.Errorf("unable to determine free disk space in %v "+
+			"before %v: %w", dir, operation, err)
+	}
+
+	return checkOperationSpace(
+		operation, dir, required, available, c.OperationMultiplier,
+	)
+}
+
+// checkOperationSpace returns an error that explains how to resolve the lack
+// of disk space if less than the required number of bytes multiplied by the
+// multiplier are available.
+func checkOperationSpace(operation, dir string, required, available uint64,
+	multiplier float64) error {
+
+	needed := uint64(math.Ceil(float64(required) * multiplier))
+	if available >= needed {
+		return nil
+	}
+
+	return fmt.Errorf("not enough disk space for %v: %v of free space "+
+		"needed in %v (an estimated %v times "+
+		"healthcheck.diskspace.operationmultiplier=%v), but only %v "+
+		"available. Free up at least %v of disk space or move the "+
+		"data directory to a larger disk. To skip this check at your "+
+		"own risk, set healthcheck.diskspace.operationmultiplier=0",
+		operation, formatBytes(needed), dir, formatBytes(required),
+		multiplier, formatBytes(available),
+		formatBytes(needed-available))
+}
+
+// formatBytes formats the given number of bytes with a decimal unit prefix.
+func formatBytes(bytes uint64) string {
+	const unit = 1000
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div),
+		"kMGTPE"[exp])
+}
+
+// FeeCheckConfig contains configuration for ensuring that the fee estimates
+// fetched from an external fee URL are up to date.
+type FeeCheckConfig struct {
+	MaxStaleness time.Duration `long:"maxstaleness" description:"The maximum age of the fee estimates fetched from the fee URL before the check fails."`
+
 	*CheckConfig
 }
@@ -0,0 +1,47 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckOperationSpace asserts that an operation is only refused if less
+// than its estimated disk space multiplied by the safety margin is available,
+// and that the refusal explains how much space needs to be freed.
+func TestCheckOperationSpace(t *testing.T) {
+	t.Parallel()
+
+	const gb = 1_000_000_000
+
+	err := checkOperationSpace("migrating channel.db", "/lnd", 2*gb, 3*gb,
+		1.5)
+	require.NoError(t, err)
+
+	err = checkOperationSpace("migrating channel.db", "/lnd", 2*gb,
+		3*gb-1, 1.5)
+	require.ErrorContains(t, err, "not enough disk space for migrating "+
+		"channel.db: 3.0 GB of free space needed in /lnd")
+	require.ErrorContains(t, err, "Free up at least 1 B of disk space")
+
+	err = checkOperationSpace("compacting channel.db", "/lnd", 2*gb,
+		500_000_000, 1)
+	require.ErrorContains(t, err, "but only 500.0 MB available")
+	require.ErrorContains(t, err, "Free up at least 1.5 GB of disk space")
+
+	// A disabled check never refuses an operation.
+	cfg := &DiskCheckConfig{}
+	require.NoError(t, cfg.CheckOperationSpace("migrating", "/", 1<<62))
+}
+
+// TestFormatBytes asserts that byte counts are formatted with the largest
+// decimal unit that keeps the value at or above one.
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "0 B", formatBytes(0))
+	require.Equal(t, "999 B", formatBytes(999))
+	require.Equal(t, "1.0 kB", formatBytes(1000))
+	require.Equal(t, "1.5 MB", formatBytes(1_500_000))
+	require.Equal(t, "2.3 TB", formatBytes(2_345_000_000_000))
+}
@@ -1,14 +1,85 @@
 package lncfg
 
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
 // DefaultHoldInvoiceExpiryDelta defines the number of blocks before the expiry
 // height of a hold invoice's htlc that lnd will automatically cancel the
 // invoice to prevent the channel from force closing. This value *must* be
 // greater than DefaultIncomingBroadcastDelta to prevent force closes.
 const DefaultHoldInvoiceExpiryDelta = DefaultIncomingBroadcastDelta + 2
 
+const (
+	// FallbackAddrModeNone doesn't add a fallback address to invoices.
+	FallbackAddrModeNone = "none"
+
+	// FallbackAddrModeFixed adds the same configured fallback address to
+	// all invoices.
+	FallbackAddrModeFixed = "fixed"
+
+	// FallbackAddrModeFresh adds a fresh wallet address as the fallback
+	// address of each invoice.
+	FallbackAddrModeFresh = "fresh"
+
+	// DefaultFallbackAddrMaxUnused is the default maximum number of fresh
+	// fallback addresses that have not received any funds. It matches the
+	// address gap limit of BIP 44.
+	DefaultFallbackAddrMaxUnused = 20
+)
+
 // Invoices holds the configuration options for invoices.
 //
 //nolint:lll
 type Invoices struct {
 	HoldExpiryDelta uint32 `long:"holdexpirydelta" description:"The number of blocks before a hold invoice's htlc expires that the invoice should be canceled to prevent a force close. Force closes will not be prevented if this value is not greater than DefaultIncomingBroadcastDelta."`
+
+	FallbackAddrMode string `long:"fallback-address-mode" description:"Adds an on-chain fallback address to invoices that don't specify one. With 'fixed' the address set with fallback-address is used, with 'fresh' a new wallet address is used for each invoice and on-chain payments to it are reconciled with the invoice." choice:"none" choice:"fixed" choice:"fresh"`
+
+	FallbackAddr string `long:"fallback-address" description:"The fallback address added to invoices if fallback-address-mode is 'fixed'."`
+
+	FallbackAddrMaxUnused int `long:"fallback-address-max-unused" description:"The maximum number of fresh fallback addresses that have not received any funds. The unused address of a settled or canceled invoice is used again for a new invoice, an address that received funds never is. Invoices are created without a fallback address once the limit is reached."`
+
+	MaxAmount btcutil.Amount `long:"max-amount" description:"The maximum amount in satoshis of regular invoices. Requests to create larger invoices are rejected. 0 means only the hard limit of 100k BTC applies."`
+
+	MaxHoldAmount btcutil.Amount `long:"max-hold-amount" description:"The maximum amount in satoshis of hold invoices. Requests to create larger hold invoices are rejected. 0 means only the hard limit of 100k BTC applies."`
+
+	AllowZeroAmount bool `long:"allow-zero-amount" description:"Allow requests to create invoices without an amount, which let the payer choose the amount to pay. Set to false to reject them."`
 }
+
+// Validate checks the values configured for invoices.
+func (i *Invoices) Validate() error {
+	switch {
+	case i.FallbackAddrMode == FallbackAddrModeFixed &&
+		i.FallbackAddr == "":
+
+		return fmt.Errorf("fallback-address must be set for "+
+			"fallback-address-mode=%v", FallbackAddrModeFixed)
+
+	case i.FallbackAddrMode != FallbackAddrModeFixed &&
+		i.FallbackAddr != "":
+
+		return fmt.Errorf("fallback-address can only be set for "+
+			"fallback-address-mode=%v", FallbackAddrModeFixed)
+
+	case i.FallbackAddrMaxUnused < 1:
+		return fmt.Errorf("fallback-address-max-unused must be at "+
+			"least 1, got %d", i.FallbackAddrMaxUnused)
+
+	case i.MaxAmount < 0:
+		return fmt.Errorf("max-amount must not be negative, got %v",
+			i.MaxAmount)
+
+	case i.MaxHoldAmount < 0:
+		return fmt.Errorf("max-hold-amount must not be negative, "+
+			"got %v", i.MaxHoldAmount)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Invoices implements the Validator
+// interface.
+var _ Validator = (*Invoices)(nil)
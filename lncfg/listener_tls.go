@@ -0,0 +1,105 @@
+package lncfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// listenerOptionSeparator separates the address of an RPC or REST
+	// listener from its options, e.g.
+	// "0.0.0.0:8080,tlscertpath=/path/tls.cert,tlskeypath=/path/tls.key".
+	listenerOptionSeparator = ","
+
+	// listenerOptionTLSCertPath is the listener option that sets the path
+	// to the TLS certificate of the listener.
+	listenerOptionTLSCertPath = "tlscertpath"
+
+	// listenerOptionTLSKeyPath is the listener option that sets the path
+	// to the TLS key of the listener.
+	listenerOptionTLSKeyPath = "tlskeypath"
+
+	// listenerOptionNoTLS is the listener option that disables TLS for a
+	// single REST listener.
+	listenerOptionNoTLS = "notls"
+)
+
+// ListenerTLS is the TLS configuration of a single RPC or REST listener that
+// overrides the global TLS configuration.
+type ListenerTLS struct {
+	// CertPath is the path to the TLS certificate of the listener.
+	CertPath string
+
+	// KeyPath is the path to the TLS key of the listener.
+	KeyPath string
+
+	// DisableTLS disables TLS for the listener. This is only allowed for
+	// REST listeners.
+	DisableTLS bool
+}
+
+// ParseListenerTLS splits a raw RPC or REST listener entry into its address
+// and its TLS options. The options follow the address, separated by commas.
+// If the entry carries no options, nil is returned as the TLS configuration
+// and the listener uses the global one.
+func ParseListenerTLS(rawListener string) (string, *ListenerTLS, error) {
+	parts := strings.Split(rawListener, listenerOptionSeparator)
+	addr, options := parts[0], parts[1:]
+	if len(options) == 0 {
+		return addr, nil, nil
+	}
+
+	listenerTLS := &ListenerTLS{}
+	seen := make(map[string]struct{}, len(options))
+	for _, option := range options {
+		key, value, hasValue := strings.Cut(option, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		// An option set twice is most likely a mistake, so we don't
+		// silently pick one of the values.
+		if _, ok := seen[key]; ok {
+			return "", nil, fmt.Errorf("duplicate option %q of "+
+				"listener %v", key, addr)
+		}
+		seen[key] = struct{}{}
+
+		switch {
+		case key == listenerOptionNoTLS && !hasValue:
+			listenerTLS.DisableTLS = true
+
+		case key == listenerOptionTLSCertPath && value != "":
+			listenerTLS.CertPath = value
+
+		case key == listenerOptionTLSKeyPath && value != "":
+			listenerTLS.KeyPath = value
+
+		default:
+			return "", nil, fmt.Errorf("invalid option %q of "+
+				"listener %v", option, addr)
+		}
+	}
+
+	if err := listenerTLS.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid TLS options of listener "+
+			"%v: %w", addr, err)
+	}
+
+	return addr, listenerTLS, nil
+}
+
+// Validate checks that the TLS options of a listener are consistent.
+func (l *ListenerTLS) Validate() error {
+	switch {
+	case l.DisableTLS && (l.CertPath != "" || l.KeyPath != ""):
+		return fmt.Errorf("%v cannot be combined with a TLS "+
+			"certificate", listenerOptionNoTLS)
+
+	case !l.DisableTLS && (l.CertPath == "" || l.KeyPath == ""):
+		return errors.New("both tlscertpath and tlskeypath must be " +
+			"set")
+	}
+
+	return nil
+}
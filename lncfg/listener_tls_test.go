@@ -0,0 +1,90 @@
+package lncfg_test
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseListenerTLS asserts that the TLS options of an RPC or REST listener
+// entry are split from its address and validated.
+func TestParseListenerTLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawListener string
+		addr        string
+		listenerTLS *lncfg.ListenerTLS
+		valid       bool
+	}{
+		{
+			name:        "no options",
+			rawListener: "localhost:10009",
+			addr:        "localhost:10009",
+			valid:       true,
+		},
+		{
+			name: "own certificate",
+			rawListener: "0.0.0.0:8080,tlscertpath=/lnd/tls.cert," +
+				"tlskeypath=/lnd/tls.key",
+			addr: "0.0.0.0:8080",
+			listenerTLS: &lncfg.ListenerTLS{
+				CertPath: "/lnd/tls.cert",
+				KeyPath:  "/lnd/tls.key",
+			},
+			valid: true,
+		},
+		{
+			name:        "tls disabled",
+			rawListener: "unix:///var/run/lnd.sock,notls",
+			addr:        "unix:///var/run/lnd.sock",
+			listenerTLS: &lncfg.ListenerTLS{
+				DisableTLS: true,
+			},
+			valid: true,
+		},
+		{
+			name:        "certificate without key",
+			rawListener: "0.0.0.0:8080,tlscertpath=/lnd/tls.cert",
+		},
+		{
+			name: "tls disabled with certificate",
+			rawListener: "0.0.0.0:8080,notls," +
+				"tlscertpath=/lnd/tls.cert,tlskeypath=/lnd/tls.key",
+		},
+		{
+			name:        "empty path",
+			rawListener: "0.0.0.0:8080,tlscertpath=,tlskeypath=key",
+		},
+		{
+			name:        "unknown option",
+			rawListener: "0.0.0.0:8080,tls=off",
+		},
+		{
+			name: "duplicate certificate",
+			rawListener: "0.0.0.0:8080,tlscertpath=/lnd/a.cert," +
+				"tlskeypath=/lnd/tls.key,tlscertpath=/lnd/b",
+		},
+		{
+			name:        "duplicate notls",
+			rawListener: "unix:///var/run/lnd.sock,notls,notls",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			addr, listenerTLS, err := lncfg.ParseListenerTLS(
+				test.rawListener,
+			)
+			if !test.valid {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.addr, addr)
+			require.Equal(t, test.listenerTLS, listenerTLS)
+		})
+	}
+}
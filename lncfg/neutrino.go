@@ -1,6 +1,31 @@
 package lncfg
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// MinNeutrinoFilterCacheSize is the minimum capacity in bytes of
+	// neutrino's compact filter cache. Smaller caches evict filters before
+	// a batch of them is processed, so they have to be fetched again.
+	MinNeutrinoFilterCacheSize uint64 = 1024 * 1024
+
+	// NeutrinoMaxPeers is the maximum number of peers neutrino connects to.
+	NeutrinoMaxPeers = 8
+
+	// DefaultNeutrinoMinPeersTimeout is the default time we wait for the
+	// minimum number of neutrino peers at startup.
+	DefaultNeutrinoMinPeersTimeout = time.Minute
+
+	// DefaultNeutrinoBanDuration is the default time a misbehaving neutrino
+	// peer stays banned.
+	DefaultNeutrinoBanDuration = 48 * time.Hour
+
+	// MinNeutrinoBanDuration is the minimum time a misbehaving neutrino
+	// peer stays banned.
+	MinNeutrinoBanDuration = time.Second
+)
 
 // Neutrino holds the configuration options for the daemon's connection to
 // neutrino.
@@ -10,7 +35,7 @@ type Neutrino struct {
 	AddPeers           []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
 	ConnectPeers       []string      `long:"connect" description:"Connect only to the specified peers at startup"`
 	MaxPeers           int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
-	BanDuration        time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	BanDuration        time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Bans are persisted in the neutrino database, so they outlast restarts. Valid time units are {s, m, h}.  Minimum 1 second"`
 	BanThreshold       uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
 	AssertFilterHeader string        `long:"assertfilterheader" description:"Optional filter header in height:hash format to assert the state of neutrino's filter header chain on startup. If the assertion does not hold, then the filter header chain will be re-synced from the genesis block."`
 	UserAgentName      string        `long:"useragentname" description:"Used to help identify ourselves to other bitcoin peers"`
@@ -18,4 +43,50 @@ type Neutrino struct {
 	ValidateChannels   bool          `long:"validatechannels" description:"Validate every channel in the graph during sync by downloading the containing block. This is the inverse of routing.assumechanvalid, meaning that for Neutrino the validation is turned off by default for massively increased graph sync performance. This speedup comes at the risk of using an unvalidated view of the network for routing. Overwrites the value of routing.assumechanvalid if Neutrino is used. (default: false)"`
 	BroadcastTimeout   time.Duration `long:"broadcasttimeout" description:"The amount of time to wait before giving up on a transaction broadcast attempt."`
 	PersistFilters     bool          `long:"persistfilters" description:"Whether compact filters fetched from the P2P network should be persisted to disk."`
+	FilterCacheSize    uint64        `long:"filter-cache-size" description:"The maximum capacity in bytes of the in-memory compact filter cache. Filters evicted from the cache are fetched again from disk or the network, so larger caches speed up rescans."`
+	MinPeersAtStartup  int32         `long:"min-peers-at-startup" description:"The minimum number of peers neutrino must be connected to before lnd continues its startup. Set to 0 to not wait for peers."`
+	MinPeersTimeout    time.Duration `long:"min-peers-timeout" description:"The maximum time to wait for min-peers-at-startup peers. Once it elapsed, lnd continues its startup with the peers it has."`
+}
+
+// Validate checks the values configured for neutrino.
+func (n *Neutrino) Validate() error {
+	if n.FilterCacheSize < MinNeutrinoFilterCacheSize {
+		return fmt.Errorf("neutrino.filter-cache-size must be at "+
+			"least %v bytes, got %v", MinNeutrinoFilterCacheSize,
+			n.FilterCacheSize)
+	}
+
+	if n.BanDuration < MinNeutrinoBanDuration {
+		return fmt.Errorf("neutrino.banduration must be at least %v, "+
+			"got %v", MinNeutrinoBanDuration, n.BanDuration)
+	}
+
+	// We can't wait for more peers than neutrino connects to. The
+	// maxpeers option may lower the bound, but neutrino never connects to
+	// more than NeutrinoMaxPeers peers.
+	maxPeers := int32(NeutrinoMaxPeers)
+	if n.MaxPeers > 0 && n.MaxPeers < NeutrinoMaxPeers {
+		maxPeers = int32(n.MaxPeers)
+	}
+
+	switch {
+	case n.MinPeersAtStartup < 0:
+		return fmt.Errorf("neutrino.min-peers-at-startup must not be "+
+			"negative, got %v", n.MinPeersAtStartup)
+
+	case n.MinPeersAtStartup > maxPeers:
+		return fmt.Errorf("neutrino.min-peers-at-startup must not "+
+			"exceed the maximum number of peers %v, got %v",
+			maxPeers, n.MinPeersAtStartup)
+
+	case n.MinPeersAtStartup > 0 && n.MinPeersTimeout <= 0:
+		return fmt.Errorf("neutrino.min-peers-timeout must be "+
+			"positive, got %v", n.MinPeersTimeout)
+	}
+
+	return nil
 }
+
+// Compile-time constraint to ensure Neutrino implements the Validator
+// interface.
+var _ Validator = (*Neutrino)(nil)
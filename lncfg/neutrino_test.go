@@ -0,0 +1,94 @@
+package lncfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNeutrinoMinPeersAtStartup asserts that the minimum number of neutrino
+// peers at startup is bounded by the maximum number of peers and requires a
+// timeout.
+func TestNeutrinoMinPeersAtStartup(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxPeers int
+		minPeers int32
+		timeout  time.Duration
+		valid    bool
+	}{
+		{
+			name:  "disabled",
+			valid: true,
+		},
+		{
+			name:     "within default max peers",
+			minPeers: lncfg.NeutrinoMaxPeers,
+			timeout:  time.Minute,
+			valid:    true,
+		},
+		{
+			name:     "above default max peers",
+			minPeers: lncfg.NeutrinoMaxPeers + 1,
+			timeout:  time.Minute,
+		},
+		{
+			name:     "within configured max peers",
+			maxPeers: 4,
+			minPeers: 4,
+			timeout:  time.Minute,
+			valid:    true,
+		},
+		{
+			name:     "above configured max peers",
+			maxPeers: 4,
+			minPeers: 5,
+			timeout:  time.Minute,
+		},
+		{
+			name:     "negative",
+			minPeers: -1,
+			timeout:  time.Minute,
+		},
+		{
+			name:     "no timeout",
+			minPeers: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &lncfg.Neutrino{
+				FilterCacheSize:   lncfg.MinNeutrinoFilterCacheSize,
+				BanDuration:       lncfg.DefaultNeutrinoBanDuration,
+				MaxPeers:          test.maxPeers,
+				MinPeersAtStartup: test.minPeers,
+				MinPeersTimeout:   test.timeout,
+			}
+
+			err := cfg.Validate()
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestNeutrinoBanDuration asserts that misbehaving neutrino peers are banned
+// for at least the minimum ban duration.
+func TestNeutrinoBanDuration(t *testing.T) {
+	cfg := &lncfg.Neutrino{
+		FilterCacheSize: lncfg.MinNeutrinoFilterCacheSize,
+		BanDuration:     lncfg.MinNeutrinoBanDuration,
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.BanDuration = lncfg.MinNeutrinoBanDuration - 1
+	require.Error(t, cfg.Validate())
+}
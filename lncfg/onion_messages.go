@@ -0,0 +1,57 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/onionmessage"
+)
+
+const (
+	// DefaultOnionMsgPeerRateLimit is the default number of onion messages
+	// per second relayed for a single peer.
+	DefaultOnionMsgPeerRateLimit = 10
+
+	// DefaultOnionMsgPeerBurst is the default number of onion messages a
+	// single peer may send at once before being rate limited.
+	DefaultOnionMsgPeerBurst = 50
+)
+
+// OnionMessages holds the configuration options for relaying onion messages.
+//
+//nolint:lll
+type OnionMessages struct {
+	Relay onionmessage.RelayMode `long:"relay" choice:"off" choice:"peers" choice:"all" description:"Which onion messages to relay. 'off' drops all onion messages and doesn't advertise support for them, 'peers' only relays messages received from and sent to peers we have a channel with, 'all' relays messages between any connected peers."`
+
+	PeerRateLimit float64 `long:"peer-rate-limit" description:"The maximum number of onion messages per second relayed for a single peer. Messages exceeding the limit are dropped."`
+
+	PeerBurst int `long:"peer-burst" description:"The number of onion messages a single peer may send at once before its messages are rate limited."`
+}
+
+// DefaultOnionMessages returns the default configuration for relaying onion
+// messages.
+func DefaultOnionMessages() *OnionMessages {
+	return &OnionMessages{
+		Relay:         onionmessage.RelayOff,
+		PeerRateLimit: DefaultOnionMsgPeerRateLimit,
+		PeerBurst:     DefaultOnionMsgPeerBurst,
+	}
+}
+
+// Validate checks the values configured for relaying onion messages.
+func (o *OnionMessages) Validate() error {
+	if o.PeerRateLimit <= 0 {
+		return fmt.Errorf("peer-rate-limit must be positive, got %v",
+			o.PeerRateLimit)
+	}
+
+	if o.PeerBurst < 1 {
+		return fmt.Errorf("peer-burst must be at least 1, got %v",
+			o.PeerBurst)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure OnionMessages implements the Validator
+// interface.
+var _ Validator = (*OnionMessages)(nil)
@@ -79,6 +79,11 @@ type ProtocolOptions struct {
 	// CustomInvoice specifies custom feature bits to advertise in the
 	// node's invoices.
 	CustomInvoice []uint16 `long:"custom-invoice" description:"custom feature bits — numbers defined in BOLT 9 — to advertise in the node's invoices"`
+
+	// CustomFeatureBits specifies custom feature bits to advertise in the
+	// node's init and announcement messages, optionally designated as
+	// optional or required.
+	CustomFeatureBits []string `long:"custom-feature-bits" description:"custom feature bits to advertise in the node's init and announcement messages, in the form <bit>[:optional|:required]; with a designation, the matching bit of the feature pair is used; bits that lnd already advertises or that are set with custom-init or custom-nodeann are rejected"`
 }
 
 // Wumbo returns true if lnd should permit the creation and acceptance of wumbo
@@ -151,5 +156,17 @@ func (p ProtocolOptions) CustomFeatures() map[feature.Set][]lnwire.FeatureBit {
 	setFeatures(feature.SetNodeAnn, p.CustomNodeAnn)
 	setFeatures(feature.SetInvoice, p.CustomInvoice)
 
+	// The custom feature bits were validated along with the config, so
+	// they can't fail to parse here.
+	customBits, _ := ParseCustomFeatureBits(p.CustomFeatureBits)
+	for _, bit := range customBits {
+		customFeatures[feature.SetInit] = append(
+			customFeatures[feature.SetInit], bit,
+		)
+		customFeatures[feature.SetNodeAnn] = append(
+			customFeatures[feature.SetNodeAnn], bit,
+		)
+	}
+
 	return customFeatures
 }
@@ -0,0 +1,113 @@
+package lncfg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// featureOptional designates a custom feature bit as optional.
+	featureOptional = "optional"
+
+	// featureRequired designates a custom feature bit as required.
+	featureRequired = "required"
+)
+
+// ParseCustomFeatureBit parses a custom feature bit of the form
+// <bit>[:optional|:required]. With a designation, the bit of the feature pair
+// matching it is returned, so 100:optional and 101:optional both yield the
+// optional bit 101. Without a designation, the bit is returned as is.
+func ParseCustomFeatureBit(s string) (lnwire.FeatureBit, error) {
+	bitStr, designation, hasDesignation := strings.Cut(s, ":")
+
+	num, err := strconv.ParseUint(strings.TrimSpace(bitStr), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid feature bit %q: %w", s, err)
+	}
+	bit := lnwire.FeatureBit(num)
+
+	if !hasDesignation {
+		return bit, nil
+	}
+
+	switch strings.TrimSpace(designation) {
+	case featureOptional:
+		return bit | 1, nil
+
+	case featureRequired:
+		return bit &^ 1, nil
+
+	default:
+		return 0, fmt.Errorf("invalid feature bit %q: designation "+
+			"must be either %s or %s", s, featureOptional,
+			featureRequired)
+	}
+}
+
+// ParseCustomFeatureBits parses the given custom feature bits. Each feature
+// pair may only be specified once. Bits that lnd already advertises itself
+// are rejected by the feature manager once the server starts, just like the
+// bits of the custom-init and custom-nodeann options, so bits of known
+// features whose subsystem is disabled can still be advertised.
+func ParseCustomFeatureBits(raw []string) ([]lnwire.FeatureBit, error) {
+	bits := make([]lnwire.FeatureBit, 0, len(raw))
+	pairs := make(map[lnwire.FeatureBit]struct{}, len(raw))
+	for _, s := range raw {
+		bit, err := ParseCustomFeatureBit(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// Both bits of a pair map to the same required bit.
+		pair := bit &^ 1
+		if _, ok := pairs[pair]; ok {
+			return nil, fmt.Errorf("feature bit %d specified "+
+				"more than once", bit)
+		}
+		pairs[pair] = struct{}{}
+
+		bits = append(bits, bit)
+	}
+
+	return bits, nil
+}
+
+// ValidateCustomFeatureBits parses the given custom feature bits and makes
+// sure they don't conflict with the bits of the custom-init and custom-nodeann
+// options, which are advertised in the same messages. A feature pair may only
+// be set by one of the options.
+func ValidateCustomFeatureBits(raw []string, customInit,
+	customNodeAnn []uint16) error {
+
+	bits, err := ParseCustomFeatureBits(raw)
+	if err != nil {
+		return err
+	}
+
+	conflicts := func(bit lnwire.FeatureBit, others []uint16) bool {
+		for _, other := range others {
+			if lnwire.FeatureBit(other)&^1 == bit&^1 {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, bit := range bits {
+		switch {
+		case conflicts(bit, customInit):
+			return fmt.Errorf("feature bit %d conflicts with "+
+				"custom-init", bit)
+
+		case conflicts(bit, customNodeAnn):
+			return fmt.Errorf("feature bit %d conflicts with "+
+				"custom-nodeann", bit)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseCustomFeatureBits tests that custom feature bits are parsed with
+// their designation and that bits of known features and duplicate pairs are
+// rejected.
+func TestParseCustomFeatureBits(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		raw          []string
+		expectedBits []lnwire.FeatureBit
+		expectedErr  string
+	}{{
+		name:         "no designation",
+		raw:          []string{"200", "303"},
+		expectedBits: []lnwire.FeatureBit{200, 303},
+	}, {
+		name:         "optional designation",
+		raw:          []string{"200:optional", "203:optional"},
+		expectedBits: []lnwire.FeatureBit{201, 203},
+	}, {
+		name:         "required designation",
+		raw:          []string{"200:required", "203:required"},
+		expectedBits: []lnwire.FeatureBit{200, 202},
+	}, {
+		name:        "invalid designation",
+		raw:         []string{"200:mandatory"},
+		expectedErr: "designation must be",
+	}, {
+		name:        "invalid bit",
+		raw:         []string{"abc"},
+		expectedErr: "invalid feature bit",
+	}, {
+		// Known features are only rejected by the feature manager if
+		// lnd advertises them itself.
+		name:         "known feature",
+		raw:          []string{"22:required"},
+		expectedBits: []lnwire.FeatureBit{22},
+	}, {
+		name:        "duplicate pair",
+		raw:         []string{"200:optional", "200:required"},
+		expectedErr: "more than once",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bits, err := ParseCustomFeatureBits(tc.raw)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedBits, bits)
+		})
+	}
+}
+
+// TestValidateCustomFeatureBits tests that custom feature bits may not set a
+// feature pair that is also set with the custom-init or custom-nodeann
+// options.
+func TestValidateCustomFeatureBits(t *testing.T) {
+	t.Parallel()
+
+	raw := []string{"200:optional"}
+	require.NoError(t, ValidateCustomFeatureBits(raw, nil, nil))
+	require.NoError(t, ValidateCustomFeatureBits(
+		raw, []uint16{202}, []uint16{203},
+	))
+
+	// Both bits of the pair conflict.
+	require.ErrorContains(t, ValidateCustomFeatureBits(
+		raw, []uint16{200}, nil,
+	), "conflicts with custom-init")
+	require.ErrorContains(t, ValidateCustomFeatureBits(
+		raw, nil, []uint16{201},
+	), "conflicts with custom-nodeann")
+
+	// Parse errors are returned as well.
+	require.ErrorContains(t, ValidateCustomFeatureBits(
+		[]string{"200", "201"}, nil, nil,
+	), "more than once")
+}
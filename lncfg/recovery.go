@@ -0,0 +1,51 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+)
+
+// MinRecoveryPeerTimeout is the smallest peer timeout that can be configured
+// for channel recovery. Peers may be offline for a while, so a shorter timeout
+// would flag channels that are recovered normally.
+const MinRecoveryPeerTimeout = time.Hour
+
+// Recovery holds the configuration options for recovering channels from a
+// static channel backup.
+//
+//nolint:lll
+type Recovery struct {
+	Concurrency int `long:"concurrency" description:"The number of channel peers that are waited for in parallel after channels were restored from a static channel backup. All peers are connected to at once, each of them once for all of its channels. A peer then takes up a slot until it reestablished its channels, which starts the data loss recovery protocol, or for up to two minutes."`
+
+	PeerTimeout time.Duration `long:"peer-timeout" description:"How long to wait for the peer of a restored channel to send the data needed to recover our funds before the channel is reported as needing manual intervention. If the funds can be swept without the peer's data once the channel is closed, the peer is also asked to force close the channel each time it is connected. lnd never broadcasts a commitment of a restored channel. Set to 0 to disable."`
+}
+
+// DefaultRecovery returns the default channel recovery configuration.
+func DefaultRecovery() *Recovery {
+	return &Recovery{
+		Concurrency: chanbackup.DefaultRecoveryConcurrency,
+	}
+}
+
+// Validate checks the values configured for channel recovery.
+func (r *Recovery) Validate() error {
+	if r.Concurrency < 1 ||
+		r.Concurrency > chanbackup.MaxRecoveryConcurrency {
+
+		return fmt.Errorf("concurrency must be between 1 and %d, got "+
+			"%d", chanbackup.MaxRecoveryConcurrency, r.Concurrency)
+	}
+
+	if r.PeerTimeout != 0 && r.PeerTimeout < MinRecoveryPeerTimeout {
+		return fmt.Errorf("peer-timeout must be 0 or at least %v, "+
+			"got %v", MinRecoveryPeerTimeout, r.PeerTimeout)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure Recovery implements the Validator
+// interface.
+var _ Validator = (*Recovery)(nil)
@@ -1,5 +1,7 @@
 package lncfg
 
+import "time"
+
 // Routing holds the configuration options for routing.
 //
 //nolint:lll
@@ -7,4 +9,20 @@ type Routing struct {
 	AssumeChannelValid bool `long:"assumechanvalid" description:"DEPRECATED: Skip checking channel spentness during graph validation. This speedup comes at the risk of using an unvalidated view of the network for routing. (default: false)" hidden:"true"`
 
 	StrictZombiePruning bool `long:"strictgraphpruning" description:"If true, then the graph will be pruned more aggressively for zombies. In practice this means that edges with a single stale edge will be considered a zombie."`
+
+	CltvDeltaCheckInterval time.Duration `long:"cltv-delta-check-interval" description:"The interval at which the CLTV deltas we advertise are compared against the ones commonly used by the connected peers. A warning is logged if ours are outliers that are likely to cause routing failures. The check is advisory only. Set to 0 to disable the check."`
+
+	MaxGraphChannels int `long:"max-graph-channels" description:"The maximum number of channels held in the in-memory graph used for path finding. If exceeded, the channels that were least recently updated or used in our own payments are evicted from memory but remain on disk. Our own channels are never evicted. Set to 0 for no limit."`
+
+	McDecayHalfLife time.Duration `long:"mc-decay-halflife" description:"The duration after which the influence of the payment results mission control recorded for a node pair on its success probability is halved. If set, this overrides the decay of the probability estimator, which is routerrpc.apriori.penaltyhalflife for the apriori estimator and routerrpc.bimodal.decaytime for the bimodal estimator. Results are only reweighted, never discarded. Must be positive if set. If unset, the decay configured for the estimator is used."`
+
+	AttemptTraceFile string `long:"attempt-trace-file" description:"If set, a trace of every settled or failed HTLC attempt of our payments is appended to this file as a line of JSON, for offline analysis of routing. A trace contains the route, the result of each hop and the mission control state of each node pair at the time the attempt was sent. The traces aren't stored in the payment database."`
+
+	ProbeInterval time.Duration `long:"probe-interval" description:"The interval at which probes are sent through the network to keep mission control informed about the liquidity of channels our payments didn't use recently. Probes use a random payment hash, so they always fail at their target and never pay any fees. Must be at least 1m if set. Set to 0 to disable probing."`
+
+	ProbeAmount uint64 `long:"probe-amt" description:"The amount in satoshis that is probed with."`
+
+	ProbeFeeLimit uint64 `long:"probe-fee-limit" description:"The maximum routing fee in satoshis of the route of a probe. Probes can't be settled, so this only bounds the fees at risk should a probe be settled regardless."`
+
+	ProbeTargetPolicy string `long:"probe-target-policy" description:"The policy the targets of probes are selected with. 'random' probes random nodes of the graph, 'stale' probes the node pairs mission control holds the oldest results for." choice:"random" choice:"stale"`
 }
@@ -26,6 +26,8 @@ type Sweeper struct {
 
 	NoDeadlineConfTarget uint32 `long:"nodeadlineconftarget" description:"The conf target to use when sweeping non-time-sensitive outputs. This is useful for sweeping outputs that are not time-sensitive, and can be swept at a lower fee rate."`
 
+	CrossChannelBatching bool `long:"cross-channel-batching" description:"Group all outputs swept without a requested deadline together, even if they were resolved in different channels at different heights. The group uses the earliest default deadline of its outputs. This can consolidate sweeps into fewer transactions, but links the involved channels on chain."`
+
 	Budget *contractcourt.BudgetConfig `group:"sweeper.budget" namespace:"budget" long:"budget" description:"An optional config group that's used for the automatic sweep fee estimation. The Budget config gives options to limits ones fee exposure when sweeping unilateral close outputs and the fee rate calculated from budgets is capped at sweeper.maxfeerate. Check the budget config options for more details."`
 }
 
@@ -0,0 +1,44 @@
+package lncfg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/record"
+)
+
+// ParseTLVRecords parses a list of TLV records, each in the form
+// "type:hexvalue", into a set of custom records. Every type may only be
+// specified once.
+func ParseTLVRecords(rawRecords []string) (record.CustomSet, error) {
+	records := make(record.CustomSet, len(rawRecords))
+	for _, rawRecord := range rawRecords {
+		typeStr, valueStr, ok := strings.Cut(rawRecord, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid TLV record %q, "+
+				"expected type:hexvalue", rawRecord)
+		}
+
+		recordType, err := strconv.ParseUint(typeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLV record type %q: %w",
+				typeStr, err)
+		}
+
+		value, err := hex.DecodeString(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of TLV record "+
+				"type %v: %w", recordType, err)
+		}
+
+		if _, ok := records[recordType]; ok {
+			return nil, fmt.Errorf("duplicate TLV record type %v",
+				recordType)
+		}
+		records[recordType] = value
+	}
+
+	return records, nil
+}
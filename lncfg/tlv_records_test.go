@@ -0,0 +1,70 @@
+package lncfg
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTLVRecords tests parsing TLV records in the form type:hexvalue.
+func TestParseTLVRecords(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		records  []string
+		expected record.CustomSet
+		err      bool
+	}{
+		{
+			name:     "no records",
+			expected: record.CustomSet{},
+		},
+		{
+			name:    "valid records",
+			records: []string{"65536:0102", "65537:"},
+			expected: record.CustomSet{
+				65536: {0x01, 0x02},
+				65537: {},
+			},
+		},
+		{
+			name:    "missing separator",
+			records: []string{"65536"},
+			err:     true,
+		},
+		{
+			name:    "invalid type",
+			records: []string{"abc:0102"},
+			err:     true,
+		},
+		{
+			name:    "invalid value",
+			records: []string{"65536:xyz"},
+			err:     true,
+		},
+		{
+			name:    "duplicate type",
+			records: []string{"65536:01", "65536:02"},
+			err:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			records, err := ParseTLVRecords(tc.records)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, records)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package lncfg
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lightningnetwork/lnd/labels"
+)
+
+const (
+	// DefaultAutoConsolidateMaxUtxoValue is the default largest value in
+	// satoshis of a UTXO that is considered small enough to be
+	// consolidated.
+	DefaultAutoConsolidateMaxUtxoValue = 100_000
+
+	// DefaultSpendUnconfirmed is the default policy for spending
+	// unconfirmed outputs, which allows spending all of them.
+	DefaultSpendUnconfirmed = "any"
+
+	// MaxRecoveryGapLimit is the largest address gap limit allowed for
+	// wallet recoveries. Every address within the gap needs to be derived
+	// and watched during the rescan, so larger values result in very long
+	// recovery times.
+	MaxRecoveryGapLimit = 50_000
+
+	// BirthdayDateLayout is the layout of a wallet birthday that is given
+	// as a date.
+	BirthdayDateLayout = "2006-01-02"
+)
+
+// Wallet holds the configuration options for the on-chain wallet.
+//
+//nolint:lll
+type Wallet struct {
+	AutoConsolidateBelowFeeRate uint64 `long:"auto-consolidate-below-feerate" description:"If set, small UTXOs of the default wallet account are periodically consolidated into a single output whenever the estimated fee rate in sat/vbyte is at or below this value. UTXOs that are leased or needed as reserve for fee bumping anchor channels are never consolidated. Set to 0 to disable."`
+
+	AutoConsolidateMaxUtxoValue int64 `long:"auto-consolidate-max-utxo-value" description:"The largest value in satoshis of a UTXO that is still considered small enough to be consolidated."`
+
+	MaxSendAmount int64 `long:"max-send-amount" description:"The maximum amount in satoshis a single SendCoins or SendMany call may send, unless the call explicitly overrides it. For SendMany the amounts of all outputs are added up. Set to 0 to disable."`
+
+	NoMaxSendAmountOverride bool `long:"no-max-send-amount-override" description:"If set, calls can't override the maximum send amount."`
+
+	MaxSendAmountExemptSendAll bool `long:"max-send-amount-exempt-send-all" description:"If set, sweeping all funds of the wallet with SendCoins and the send_all flag isn't subject to the maximum send amount."`
+
+	SpendUnconfirmed string `long:"spend-unconfirmed" description:"The policy for spending unconfirmed outputs if a transaction allows it. 'any' spends all unconfirmed outputs, 'own-change-only' only spends unconfirmed outputs of transactions that spend our own outputs, such as our change, but not unconfirmed funds received from others, and 'never' only spends confirmed outputs." choice:"any" choice:"own-change-only" choice:"never"`
+
+	RecoveryGapLimit uint32 `long:"recovery-gap-limit" description:"The minimum address gap limit used when recovering the wallet from a seed. If a wallet recovery is requested with a lower recovery window, the gap limit is used instead, so funds sent to addresses beyond long stretches of unused addresses are found. Set to 0 to use the requested recovery window."`
+
+	Birthday string `long:"birthday" description:"A hint for the birthday of a wallet that is recovered from a seed, given as a block height or a date in the format YYYY-MM-DD. The rescan for the transactions of the wallet starts at the birthday instead of the one encoded in the seed, which speeds up the recovery, especially with neutrino. Transactions before the birthday are NOT found, so a birthday that is too late results in missing funds. The birthday is only used for the initial rescan of a wallet recovery. It replaces the birthday stored in wallet.db, so recovering funds before it requires deleting wallet.db and restoring the wallet from its seed again."`
+
+	LabelTemplate string `long:"label-template" description:"The template used to label transactions that lnd creates automatically. The placeholders {version}, {type} and {shortchanid} are replaced with the label version, the transaction type and the short channel ID. Colon separated parts containing a placeholder without a known value are left out. The template must contain {type} in a different part than {shortchanid}."`
+}
+
+// DefaultWallet returns the default configuration for the on-chain wallet.
+func DefaultWallet() *Wallet {
+	return &Wallet{
+		AutoConsolidateMaxUtxoValue: DefaultAutoConsolidateMaxUtxoValue,
+		SpendUnconfirmed:            DefaultSpendUnconfirmed,
+		LabelTemplate:               labels.DefaultTemplate,
+	}
+}
+
+// Validate checks the values configured for the wallet.
+func (w *Wallet) Validate() error {
+	if w.MaxSendAmount < 0 {
+		return fmt.Errorf("max-send-amount must not be negative, got "+
+			"%v", w.MaxSendAmount)
+	}
+
+	if w.RecoveryGapLimit > MaxRecoveryGapLimit {
+		return fmt.Errorf("recovery-gap-limit must not exceed %v, got "+
+			"%v", MaxRecoveryGapLimit, w.RecoveryGapLimit)
+	}
+
+	if err := labels.ValidateTemplate(w.LabelTemplate); err != nil {
+		return fmt.Errorf("invalid label-template: %w", err)
+	}
+
+	height, date, err := w.ParseBirthday()
+	if err != nil {
+		return err
+	}
+
+	// The tip of the chain isn't known yet, so a birthday height is
+	// checked against it once the chain backend is synced. A birthday
+	// date in the future is certainly after the tip.
+	if height == 0 && date.After(time.Now()) {
+		return fmt.Errorf("birthday %v is in the future", w.Birthday)
+	}
+
+	if w.AutoConsolidateBelowFeeRate == 0 {
+		return nil
+	}
+
+	if w.AutoConsolidateMaxUtxoValue <= 0 {
+		return fmt.Errorf("auto-consolidate-max-utxo-value must be "+
+			"positive, got %v", w.AutoConsolidateMaxUtxoValue)
+	}
+
+	return nil
+}
+
+// ParseBirthday parses the wallet birthday, which is either a block height or a
+// date. Zero values are returned if no birthday is set.
+func (w *Wallet) ParseBirthday() (uint32, time.Time, error) {
+	if w.Birthday == "" {
+		return 0, time.Time{}, nil
+	}
+
+	height, err := strconv.ParseUint(w.Birthday, 10, 32)
+	if err == nil {
+		if height == 0 {
+			return 0, time.Time{}, fmt.Errorf("birthday height must " +
+				"be positive")
+		}
+
+		return uint32(height), time.Time{}, nil
+	}
+
+	date, err := time.Parse(BirthdayDateLayout, w.Birthday)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid birthday %q, must "+
+			"be a block height or a date in the format "+
+			"YYYY-MM-DD", w.Birthday)
+	}
+
+	return 0, date, nil
+}
+
+// Compile-time constraint to ensure Wallet implements the Validator
+// interface.
+var _ Validator = (*Wallet)(nil)
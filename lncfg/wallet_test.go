@@ -0,0 +1,80 @@
+package lncfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWalletBirthday asserts that a wallet birthday is parsed as either a block
+// height or a date, and that birthdays in the future are rejected.
+func TestWalletBirthday(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(lncfg.BirthdayDateLayout)
+
+	tests := []struct {
+		name           string
+		birthday       string
+		expectedHeight uint32
+		expectedDate   time.Time
+		valid          bool
+	}{
+		{
+			name:     "no birthday",
+			birthday: "",
+			valid:    true,
+		},
+		{
+			name:           "height",
+			birthday:       "840000",
+			expectedHeight: 840000,
+			valid:          true,
+		},
+		{
+			name:     "date",
+			birthday: "2024-04-20",
+			expectedDate: time.Date(
+				2024, time.April, 20, 0, 0, 0, 0, time.UTC,
+			),
+			valid: true,
+		},
+		{
+			name:     "zero height",
+			birthday: "0",
+		},
+		{
+			name:     "negative height",
+			birthday: "-1",
+		},
+		{
+			name:     "invalid date",
+			birthday: "20/04/2024",
+		},
+		{
+			name:     "future date",
+			birthday: tomorrow,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			cfg := lncfg.DefaultWallet()
+			cfg.Birthday = test.birthday
+
+			err := cfg.Validate()
+			if !test.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			height, date, err := cfg.ParseBirthday()
+			require.NoError(t, err)
+			require.Equal(t, test.expectedHeight, height)
+			require.True(t, test.expectedDate.Equal(date))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package lnd
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -22,6 +23,7 @@ import (
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -69,9 +71,22 @@ const (
 func AdminAuthOptions(cfg *Config, skipMacaroons bool) ([]grpc.DialOption,
 	error) {
 
-	creds, err := credentials.NewClientTLSFromFile(cfg.TLSCertPath, "")
-	if err != nil {
-		return nil, fmt.Errorf("unable to read TLS cert: %w", err)
+	var creds credentials.TransportCredentials
+	if cfg.TLSCertBlob != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(cfg.TLSCertBlob)) {
+			return nil, errors.New("unable to parse TLS cert blob")
+		}
+		creds = credentials.NewClientTLSFromCert(certPool, "")
+	} else {
+		var err error
+		creds, err = credentials.NewClientTLSFromFile(
+			cfg.TLSCertPath, "",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read TLS cert: %w",
+				err)
+		}
 	}
 
 	// Create a dial options array.
@@ -179,6 +194,30 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		strings.Title(BitcoinChainName), network,
 	)
 
+	// Re-read the log levels from the config file on each SIGHUP, so they
+	// can be changed without restarting lnd.
+	go func() {
+		for {
+			select {
+			case <-interceptor.ReloadChannel():
+				debugLevel, err := cfg.reloadDebugLevel()
+				if err != nil {
+					ltndLog.Errorf("Unable to reload debug "+
+						"level, keeping the previous "+
+						"log levels: %v", err)
+
+					continue
+				}
+
+				ltndLog.Infof("Reloaded debuglevel=%v",
+					debugLevel)
+
+			case <-interceptor.ShutdownChannel():
+				return
+			}
+		}
+	}()
+
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -265,16 +304,22 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		TLSDisableAutofill: cfg.TLSDisableAutofill,
 		TLSCertDuration:    cfg.TLSCertDuration,
 
+		TLSCertBlob: []byte(cfg.TLSCertBlob),
+		TLSKeyBlob:  []byte(cfg.TLSKeyBlob),
+
 		LetsEncryptDir:    cfg.LetsEncryptDir,
 		LetsEncryptDomain: cfg.LetsEncryptDomain,
 		LetsEncryptListen: cfg.LetsEncryptListen,
 
 		DisableRestTLS: cfg.DisableRestTLS,
 
+		RPCListenerTLS:  cfg.RPCListenerTLS,
+		RESTListenerTLS: cfg.RESTListenerTLS,
+
 		HTTPHeaderTimeout: cfg.HTTPHeaderTimeout,
 	}
 	tlsManager := NewTLSManager(tlsManagerCfg)
-	serverOpts, restDialOpts, restListen, cleanUp,
+	serverOpts, restDialOpts, rpcListen, restListen, cleanUp,
 		err := tlsManager.SetCertificateBeforeUnlock()
 	if err != nil {
 		return mkErr("error setting cert before unlock: %v", err)
@@ -292,7 +337,7 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		for _, grpcEndpoint := range cfg.RPCListeners {
 			// Start a gRPC server listening for HTTP/2
 			// connections.
-			lis, err := lncfg.ListenOnAddress(grpcEndpoint)
+			lis, err := rpcListen(grpcEndpoint)
 			if err != nil {
 				return mkErr("unable to listen on %s: %v",
 					grpcEndpoint, err)
@@ -683,6 +728,10 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	// We transition the server state to Active, as the server is up.
 	interceptorChain.SetServerActive()
 
+	// Now that we're synced, let the user know if the wallet reserve for
+	// anchor channels is unlikely to be enough to fee bump them.
+	checkAnchorReserve(activeChainControl)
+
 	// Now that the server has started, if the autopilot mode is currently
 	// active, then we'll start the autopilot agent immediately. It will be
 	// stopped together with the autopilot service.
@@ -705,6 +754,50 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	return nil
 }
 
+// checkAnchorReserve logs a warning if the reserve that is held back in the
+// wallet isn't enough to fee bump the commitments of our open anchor channels
+// at the current fee estimate for the next block. The check is only advisory
+// and never prevents the daemon from starting.
+func checkAnchorReserve(cc *chainreg.ChainControl) {
+	numAnchors, err := cc.Wallet.CurrentNumAnchorChans()
+	if err != nil {
+		ltndLog.Errorf("Unable to count anchor channels: %v", err)
+		return
+	}
+
+	if numAnchors == 0 {
+		return
+	}
+
+	feeRate, err := cc.FeeEstimator.EstimateFeePerKW(1)
+	if err != nil {
+		ltndLog.Errorf("Unable to estimate fee rate for anchor "+
+			"reserve check: %v", err)
+		return
+	}
+
+	perChan := lnwallet.AnchorFeeBumpCost(feeRate)
+	reserved := cc.Wallet.RequiredReserve(uint32(numAnchors))
+
+	switch {
+	// The reserve can't even bump a single commitment, which means a
+	// force close at current fee levels might not confirm in time.
+	case reserved < perChan:
+		ltndLog.Warnf("Anchor reserve of %v is not enough to fee bump "+
+			"a single anchor commitment at %v (%v required), "+
+			"consider increasing anchor-reserve", reserved,
+			feeRate.FeePerVByte(), perChan)
+
+	// The reserve can only cover a subset of the channels, which is fine
+	// as long as they don't all close at the same time.
+	case reserved < perChan*btcutil.Amount(numAnchors):
+		ltndLog.Infof("Anchor reserve of %v can fee bump %d of %d "+
+			"anchor commitments at %v concurrently", reserved,
+			int64(reserved/perChan), numAnchors,
+			feeRate.FeePerVByte())
+	}
+}
+
 // bakeMacaroon creates a new macaroon with newest version and the given
 // permissions then returns it binary serialized.
 func bakeMacaroon(ctx context.Context, svc *macaroons.Service,
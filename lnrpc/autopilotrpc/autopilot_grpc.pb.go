@@ -32,6 +32,11 @@ type AutopilotClient interface {
 	// SetScores attempts to set the scores used by the running autopilot agent,
 	// if the external scoring heuristic is enabled.
 	SetScores(ctx context.Context, in *SetScoresRequest, opts ...grpc.CallOption) (*SetScoresResponse, error)
+	// lncli: `autopilot recommendations`
+	// ListRecommendations returns the channels the autopilot agent would open if
+	// it wasn't running in recommend-only mode. The recommendations are
+	// refreshed whenever the agent re-evaluates its state.
+	ListRecommendations(ctx context.Context, in *ListRecommendationsRequest, opts ...grpc.CallOption) (*ListRecommendationsResponse, error)
 }
 
 type autopilotClient struct {
@@ -78,6 +83,15 @@ func (c *autopilotClient) SetScores(ctx context.Context, in *SetScoresRequest, o
 	return out, nil
 }
 
+func (c *autopilotClient) ListRecommendations(ctx context.Context, in *ListRecommendationsRequest, opts ...grpc.CallOption) (*ListRecommendationsResponse, error) {
+	out := new(ListRecommendationsResponse)
+	err := c.cc.Invoke(ctx, "/autopilotrpc.Autopilot/ListRecommendations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AutopilotServer is the server API for Autopilot service.
 // All implementations must embed UnimplementedAutopilotServer
 // for forward compatibility
@@ -96,6 +110,11 @@ type AutopilotServer interface {
 	// SetScores attempts to set the scores used by the running autopilot agent,
 	// if the external scoring heuristic is enabled.
 	SetScores(context.Context, *SetScoresRequest) (*SetScoresResponse, error)
+	// lncli: `autopilot recommendations`
+	// ListRecommendations returns the channels the autopilot agent would open if
+	// it wasn't running in recommend-only mode. The recommendations are
+	// refreshed whenever the agent re-evaluates its state.
+	ListRecommendations(context.Context, *ListRecommendationsRequest) (*ListRecommendationsResponse, error)
 	mustEmbedUnimplementedAutopilotServer()
 }
 
@@ -115,6 +134,9 @@ func (UnimplementedAutopilotServer) QueryScores(context.Context, *QueryScoresReq
 func (UnimplementedAutopilotServer) SetScores(context.Context, *SetScoresRequest) (*SetScoresResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetScores not implemented")
 }
+func (UnimplementedAutopilotServer) ListRecommendations(context.Context, *ListRecommendationsRequest) (*ListRecommendationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecommendations not implemented")
+}
 func (UnimplementedAutopilotServer) mustEmbedUnimplementedAutopilotServer() {}
 
 // UnsafeAutopilotServer may be embedded to opt out of forward compatibility for this service.
@@ -200,6 +222,24 @@ func _Autopilot_SetScores_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Autopilot_ListRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecommendationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutopilotServer).ListRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/autopilotrpc.Autopilot/ListRecommendations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutopilotServer).ListRecommendations(ctx, req.(*ListRecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Autopilot_ServiceDesc is the grpc.ServiceDesc for Autopilot service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -223,6 +263,10 @@ var Autopilot_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetScores",
 			Handler:    _Autopilot_SetScores_Handler,
 		},
+		{
+			MethodName: "ListRecommendations",
+			Handler:    _Autopilot_ListRecommendations_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "autopilotrpc/autopilot.proto",
@@ -49,6 +49,10 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/autopilotrpc.Autopilot/ListRecommendations": {{
+			Entity: "info",
+			Action: "read",
+		}},
 	}
 )
 
@@ -297,3 +301,43 @@ func (s *Server) SetScores(ctx context.Context,
 
 	return &SetScoresResponse{}, nil
 }
+
+// ListRecommendations returns the channels the autopilot agent would open if
+// it wasn't running in recommend-only mode.
+//
+// NOTE: Part of the AutopilotServer interface.
+func (s *Server) ListRecommendations(ctx context.Context,
+	in *ListRecommendationsRequest) (*ListRecommendationsResponse, error) {
+
+	directives, updatedAt, err := s.manager.Recommendations()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListRecommendationsResponse{
+		Recommendations: make(
+			[]*ChannelRecommendation, 0, len(directives),
+		),
+	}
+	if !updatedAt.IsZero() {
+		resp.UpdatedAt = updatedAt.Unix()
+	}
+
+	for _, directive := range directives {
+		addrs := make([]string, 0, len(directive.Addrs))
+		for _, addr := range directive.Addrs {
+			addrs = append(addrs, addr.String())
+		}
+
+		nodeID := directive.NodeID
+		resp.Recommendations = append(
+			resp.Recommendations, &ChannelRecommendation{
+				Pubkey:    hex.EncodeToString(nodeID[:]),
+				AmtSat:    int64(directive.ChanAmt),
+				Addresses: addrs,
+			},
+		)
+	}
+
+	return resp, nil
+}
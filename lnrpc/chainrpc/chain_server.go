@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -15,6 +16,8 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"google.golang.org/grpc"
@@ -57,6 +60,14 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/chainrpc.ChainKit/GetBackendInfo": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/chainrpc.ChainKit/SubscribeBackendFailovers": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/chainrpc.ChainNotifier/RegisterConfirmationsNtfn": {{
 			Entity: "onchain",
 			Action: "read",
@@ -69,6 +80,14 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/chainrpc.ChainNotifier/ExportHeightHints": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/chainrpc.ChainNotifier/ImportHeightHints": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 	}
 
 	// DefaultChainNotifierMacFilename is the default name of the chain
@@ -82,6 +101,11 @@ var (
 	ErrChainNotifierServerShuttingDown = errors.New("chain notifier RPC " +
 		"subserver shutting down")
 
+	// ErrNoBackendFailover is returned when subscribing to chain backend
+	// failovers while no fallback bitcoind node is configured.
+	ErrNoBackendFailover = errors.New("no fallback bitcoind node " +
+		"configured")
+
 	// ErrChainNotifierServerNotActive indicates that the chain notifier hasn't
 	// finished the startup process.
 	ErrChainNotifierServerNotActive = errors.New("chain notifier RPC is " +
@@ -352,6 +376,85 @@ func (s *Server) GetBlockHash(_ context.Context,
 	}, nil
 }
 
+// GetBackendInfo returns the type and connection status of the chain backend,
+// its best block and how many blocks it lags behind the best block known on
+// the network.
+func (s *Server) GetBackendInfo(_ context.Context,
+	_ *GetBackendInfoRequest) (*GetBackendInfoResponse, error) {
+
+	info := s.cfg.BackendInfo()
+	if !info.Connected {
+		return &GetBackendInfoResponse{
+			Backend: info.Backend,
+			Error:   info.ConnErr.Error(),
+		}, nil
+	}
+
+	return &GetBackendInfoResponse{
+		Backend:         info.Backend,
+		Connected:       true,
+		BestBlockHash:   info.BestBlockHash[:],
+		BestBlockHeight: info.BestBlockHeight,
+		TipHeight:       info.TipHeight,
+		BlocksBehind:    info.BlocksBehind(),
+		MempoolSize:     info.MempoolSize,
+	}, nil
+}
+
+// SubscribeBackendFailovers returns a stream that is notified whenever lnd
+// fails over from one bitcoind node to another.
+//
+// NOTE: This is part of the chainrpc.ChainKitServer interface.
+func (s *Server) SubscribeBackendFailovers(_ *SubscribeBackendFailoversRequest,
+	updateStream ChainKit_SubscribeBackendFailoversServer) error {
+
+	failover := s.cfg.BackendFailover
+	if failover == nil || !failover.HasFallback() {
+		return ErrNoBackendFailover
+	}
+
+	client, err := failover.SubscribeFailovers()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			event, ok := update.(*chainreg.BackendFailoverEvent)
+			if !ok {
+				return fmt.Errorf("unexpected failover event "+
+					"type: %T", update)
+			}
+
+			err := updateStream.Send(&BackendFailoverEvent{
+				PreviousHost: event.PreviousHost,
+				ActiveHost:   event.ActiveHost,
+				Reason:       event.Reason.Error(),
+				Timestamp:    event.Timestamp.Unix(),
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return ErrChainNotifierServerShuttingDown
+
+		case <-updateStream.Context().Done():
+			if errors.Is(updateStream.Context().Err(),
+				context.Canceled) {
+
+				return nil
+			}
+			return updateStream.Context().Err()
+
+		case <-s.quit:
+			return ErrChainNotifierServerShuttingDown
+		}
+	}
+}
+
 // RegisterConfirmationsNtfn is a synchronous response-streaming RPC that
 // registers an intent for a client to be notified once a confirmation request
 // has reached its required number of confirmations on-chain.
@@ -660,3 +763,107 @@ func (s *Server) RegisterBlockEpochNtfn(in *BlockEpoch,
 		}
 	}
 }
+
+// ExportHeightHints returns all spend and confirm height hints currently
+// stored in the height hint cache of the chain notifier.
+//
+// NOTE: This is part of the chainrpc.ChainNotifierService interface.
+func (s *Server) ExportHeightHints(_ context.Context,
+	_ *ExportHeightHintsRequest) (*ExportHeightHintsResponse, error) {
+
+	hints, err := s.cfg.HeightHintCache.FetchHeightHints()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcHints := make([]*HeightHint, 0, len(hints))
+	for _, hint := range hints {
+		hintType := HeightHintType_SPEND_HINT
+		if hint.Type == channeldb.ConfirmHint {
+			hintType = HeightHintType_CONFIRM_HINT
+		}
+
+		rpcHints = append(rpcHints, &HeightHint{
+			Type:   hintType,
+			Key:    hint.Key,
+			Height: hint.Height,
+		})
+	}
+
+	return &ExportHeightHintsResponse{
+		Hints: rpcHints,
+	}, nil
+}
+
+// ImportHeightHints stores the given height hints within the height hint cache
+// of the chain notifier. An existing hint with the same key is only replaced by
+// a lower one. Each hint must be between 1 and the current best block height,
+// or none of the hints are imported.
+//
+// NOTE: This is part of the chainrpc.ChainNotifierService interface.
+func (s *Server) ImportHeightHints(_ context.Context,
+	in *ImportHeightHintsRequest) (*ImportHeightHintsResponse, error) {
+
+	// Hints beyond the current chain tip would make us skip blocks once
+	// they are mined, so we validate all hints against it.
+	_, bestHeight, err := s.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch best block: %w", err)
+	}
+
+	hints, err := unmarshalHeightHints(in.Hints, uint32(bestHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	numImported, err := s.cfg.HeightHintCache.ImportHeightHints(hints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImportHeightHintsResponse{
+		NumImported: uint32(numImported),
+	}, nil
+}
+
+// unmarshalHeightHints converts the given RPC height hints into their cache
+// representation and makes sure each of them is between 1 and the given best
+// height.
+func unmarshalHeightHints(rpcHints []*HeightHint,
+	bestHeight uint32) ([]channeldb.HeightHint, error) {
+
+	hints := make([]channeldb.HeightHint, 0, len(rpcHints))
+	for i, rpcHint := range rpcHints {
+		var hintType channeldb.HeightHintType
+		switch rpcHint.Type {
+		case HeightHintType_SPEND_HINT:
+			hintType = channeldb.SpendHint
+
+		case HeightHintType_CONFIRM_HINT:
+			hintType = channeldb.ConfirmHint
+
+		default:
+			return nil, fmt.Errorf("hint %d: unknown type %v", i,
+				rpcHint.Type)
+		}
+
+		if len(rpcHint.Key) == 0 {
+			return nil, fmt.Errorf("hint %d: key must not be empty",
+				i)
+		}
+
+		if rpcHint.Height == 0 || rpcHint.Height > bestHeight {
+			return nil, fmt.Errorf("hint %d: height %d must be "+
+				"between 1 and the best block height %d", i,
+				rpcHint.Height, bestHeight)
+		}
+
+		hints = append(hints, channeldb.HeightHint{
+			Type:   hintType,
+			Key:    rpcHint.Key,
+			Height: rpcHint.Height,
+		})
+	}
+
+	return hints, nil
+}
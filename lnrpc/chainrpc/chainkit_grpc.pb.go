@@ -32,6 +32,16 @@ type ChainKitClient interface {
 	// GetBlockHash returns the hash of the block in the best blockchain
 	// at the given height.
 	GetBlockHash(ctx context.Context, in *GetBlockHashRequest, opts ...grpc.CallOption) (*GetBlockHashResponse, error)
+	// lncli: `chain getbackendinfo`
+	// GetBackendInfo returns the type and connection status of the chain
+	// backend, its best block and how many blocks it lags behind the best block
+	// known on the network.
+	GetBackendInfo(ctx context.Context, in *GetBackendInfoRequest, opts ...grpc.CallOption) (*GetBackendInfoResponse, error)
+	// SubscribeBackendFailovers returns a stream that is notified whenever lnd
+	// fails over from one bitcoind node to another, because the chain backend
+	// health check failed consistently. An error is returned if no fallback
+	// bitcoind node is configured.
+	SubscribeBackendFailovers(ctx context.Context, in *SubscribeBackendFailoversRequest, opts ...grpc.CallOption) (ChainKit_SubscribeBackendFailoversClient, error)
 }
 
 type chainKitClient struct {
@@ -78,6 +88,47 @@ func (c *chainKitClient) GetBlockHash(ctx context.Context, in *GetBlockHashReque
 	return out, nil
 }
 
+func (c *chainKitClient) GetBackendInfo(ctx context.Context, in *GetBackendInfoRequest, opts ...grpc.CallOption) (*GetBackendInfoResponse, error) {
+	out := new(GetBackendInfoResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainKit/GetBackendInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainKitClient) SubscribeBackendFailovers(ctx context.Context, in *SubscribeBackendFailoversRequest, opts ...grpc.CallOption) (ChainKit_SubscribeBackendFailoversClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ChainKit_ServiceDesc.Streams[0], "/chainrpc.ChainKit/SubscribeBackendFailovers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chainKitSubscribeBackendFailoversClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChainKit_SubscribeBackendFailoversClient interface {
+	Recv() (*BackendFailoverEvent, error)
+	grpc.ClientStream
+}
+
+type chainKitSubscribeBackendFailoversClient struct {
+	grpc.ClientStream
+}
+
+func (x *chainKitSubscribeBackendFailoversClient) Recv() (*BackendFailoverEvent, error) {
+	m := new(BackendFailoverEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ChainKitServer is the server API for ChainKit service.
 // All implementations must embed UnimplementedChainKitServer
 // for forward compatibility
@@ -96,6 +147,16 @@ type ChainKitServer interface {
 	// GetBlockHash returns the hash of the block in the best blockchain
 	// at the given height.
 	GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error)
+	// lncli: `chain getbackendinfo`
+	// GetBackendInfo returns the type and connection status of the chain
+	// backend, its best block and how many blocks it lags behind the best block
+	// known on the network.
+	GetBackendInfo(context.Context, *GetBackendInfoRequest) (*GetBackendInfoResponse, error)
+	// SubscribeBackendFailovers returns a stream that is notified whenever lnd
+	// fails over from one bitcoind node to another, because the chain backend
+	// health check failed consistently. An error is returned if no fallback
+	// bitcoind node is configured.
+	SubscribeBackendFailovers(*SubscribeBackendFailoversRequest, ChainKit_SubscribeBackendFailoversServer) error
 	mustEmbedUnimplementedChainKitServer()
 }
 
@@ -115,6 +176,12 @@ func (UnimplementedChainKitServer) GetBestBlock(context.Context, *GetBestBlockRe
 func (UnimplementedChainKitServer) GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHash not implemented")
 }
+func (UnimplementedChainKitServer) GetBackendInfo(context.Context, *GetBackendInfoRequest) (*GetBackendInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBackendInfo not implemented")
+}
+func (UnimplementedChainKitServer) SubscribeBackendFailovers(*SubscribeBackendFailoversRequest, ChainKit_SubscribeBackendFailoversServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBackendFailovers not implemented")
+}
 func (UnimplementedChainKitServer) mustEmbedUnimplementedChainKitServer() {}
 
 // UnsafeChainKitServer may be embedded to opt out of forward compatibility for this service.
@@ -200,6 +267,45 @@ func _ChainKit_GetBlockHash_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChainKit_GetBackendInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBackendInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainKitServer).GetBackendInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainKit/GetBackendInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainKitServer).GetBackendInfo(ctx, req.(*GetBackendInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainKit_SubscribeBackendFailovers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBackendFailoversRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChainKitServer).SubscribeBackendFailovers(m, &chainKitSubscribeBackendFailoversServer{stream})
+}
+
+type ChainKit_SubscribeBackendFailoversServer interface {
+	Send(*BackendFailoverEvent) error
+	grpc.ServerStream
+}
+
+type chainKitSubscribeBackendFailoversServer struct {
+	grpc.ServerStream
+}
+
+func (x *chainKitSubscribeBackendFailoversServer) Send(m *BackendFailoverEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ChainKit_ServiceDesc is the grpc.ServiceDesc for ChainKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -223,7 +329,17 @@ var ChainKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockHash",
 			Handler:    _ChainKit_GetBlockHash_Handler,
 		},
+		{
+			MethodName: "GetBackendInfo",
+			Handler:    _ChainKit_GetBackendInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBackendFailovers",
+			Handler:       _ChainKit_SubscribeBackendFailovers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "chainrpc/chainkit.proto",
 }
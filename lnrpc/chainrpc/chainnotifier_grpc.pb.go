@@ -45,6 +45,17 @@ type ChainNotifierClient interface {
 	// point. This allows clients to be idempotent by ensuring that they do not
 	// missing processing a single block within the chain.
 	RegisterBlockEpochNtfn(ctx context.Context, in *BlockEpoch, opts ...grpc.CallOption) (ChainNotifier_RegisterBlockEpochNtfnClient, error)
+	// ExportHeightHints returns all spend and confirm height hints currently
+	// stored in the height hint cache of the chain notifier.
+	ExportHeightHints(ctx context.Context, in *ExportHeightHintsRequest, opts ...grpc.CallOption) (*ExportHeightHintsResponse, error)
+	// ImportHeightHints stores the given height hints within the height hint
+	// cache of the chain notifier. An existing hint with the same key is only
+	// replaced by a lower one, as a higher hint could make the notifier miss a
+	// spend or confirmation. This can be used to correct hints that cause
+	// incomplete rescans. Each hint must be between 1 and the current best block
+	// height, or none of the hints are imported. The new hints are only used for
+	// notifications registered after the import.
+	ImportHeightHints(ctx context.Context, in *ImportHeightHintsRequest, opts ...grpc.CallOption) (*ImportHeightHintsResponse, error)
 }
 
 type chainNotifierClient struct {
@@ -151,6 +162,24 @@ func (x *chainNotifierRegisterBlockEpochNtfnClient) Recv() (*BlockEpoch, error)
 	return m, nil
 }
 
+func (c *chainNotifierClient) ExportHeightHints(ctx context.Context, in *ExportHeightHintsRequest, opts ...grpc.CallOption) (*ExportHeightHintsResponse, error) {
+	out := new(ExportHeightHintsResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainNotifier/ExportHeightHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainNotifierClient) ImportHeightHints(ctx context.Context, in *ImportHeightHintsRequest, opts ...grpc.CallOption) (*ImportHeightHintsResponse, error) {
+	out := new(ImportHeightHintsResponse)
+	err := c.cc.Invoke(ctx, "/chainrpc.ChainNotifier/ImportHeightHints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChainNotifierServer is the server API for ChainNotifier service.
 // All implementations must embed UnimplementedChainNotifierServer
 // for forward compatibility
@@ -182,6 +211,17 @@ type ChainNotifierServer interface {
 	// point. This allows clients to be idempotent by ensuring that they do not
 	// missing processing a single block within the chain.
 	RegisterBlockEpochNtfn(*BlockEpoch, ChainNotifier_RegisterBlockEpochNtfnServer) error
+	// ExportHeightHints returns all spend and confirm height hints currently
+	// stored in the height hint cache of the chain notifier.
+	ExportHeightHints(context.Context, *ExportHeightHintsRequest) (*ExportHeightHintsResponse, error)
+	// ImportHeightHints stores the given height hints within the height hint
+	// cache of the chain notifier. An existing hint with the same key is only
+	// replaced by a lower one, as a higher hint could make the notifier miss a
+	// spend or confirmation. This can be used to correct hints that cause
+	// incomplete rescans. Each hint must be between 1 and the current best block
+	// height, or none of the hints are imported. The new hints are only used for
+	// notifications registered after the import.
+	ImportHeightHints(context.Context, *ImportHeightHintsRequest) (*ImportHeightHintsResponse, error)
 	mustEmbedUnimplementedChainNotifierServer()
 }
 
@@ -198,6 +238,12 @@ func (UnimplementedChainNotifierServer) RegisterSpendNtfn(*SpendRequest, ChainNo
 func (UnimplementedChainNotifierServer) RegisterBlockEpochNtfn(*BlockEpoch, ChainNotifier_RegisterBlockEpochNtfnServer) error {
 	return status.Errorf(codes.Unimplemented, "method RegisterBlockEpochNtfn not implemented")
 }
+func (UnimplementedChainNotifierServer) ExportHeightHints(context.Context, *ExportHeightHintsRequest) (*ExportHeightHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportHeightHints not implemented")
+}
+func (UnimplementedChainNotifierServer) ImportHeightHints(context.Context, *ImportHeightHintsRequest) (*ImportHeightHintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportHeightHints not implemented")
+}
 func (UnimplementedChainNotifierServer) mustEmbedUnimplementedChainNotifierServer() {}
 
 // UnsafeChainNotifierServer may be embedded to opt out of forward compatibility for this service.
@@ -274,13 +320,58 @@ func (x *chainNotifierRegisterBlockEpochNtfnServer) Send(m *BlockEpoch) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ChainNotifier_ExportHeightHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportHeightHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainNotifierServer).ExportHeightHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainNotifier/ExportHeightHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainNotifierServer).ExportHeightHints(ctx, req.(*ExportHeightHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainNotifier_ImportHeightHints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportHeightHintsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainNotifierServer).ImportHeightHints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chainrpc.ChainNotifier/ImportHeightHints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainNotifierServer).ImportHeightHints(ctx, req.(*ImportHeightHintsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChainNotifier_ServiceDesc is the grpc.ServiceDesc for ChainNotifier service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ChainNotifier_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "chainrpc.ChainNotifier",
 	HandlerType: (*ChainNotifierServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExportHeightHints",
+			Handler:    _ChainNotifier_ExportHeightHints_Handler,
+		},
+		{
+			MethodName: "ImportHeightHints",
+			Handler:    _ChainNotifier_ImportHeightHints_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "RegisterConfirmationsNtfn",
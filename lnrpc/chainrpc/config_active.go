@@ -5,6 +5,8 @@ package chainrpc
 
 import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/macaroons"
 )
@@ -36,4 +38,15 @@ type Config struct {
 
 	// Chain provides access to the most up-to-date blockchain data.
 	Chain lnwallet.BlockChainIO
+
+	// BackendInfo queries the chain backend for its current state.
+	BackendInfo func() *chainreg.BackendInfo
+
+	// BackendFailover switches between the primary and the fallback
+	// bitcoind node. It is nil if lnd connects to bitcoind directly.
+	BackendFailover *chainreg.BitcoindFailover
+
+	// HeightHintCache is the cache of spend and confirm height hints used
+	// by the chain notifier.
+	HeightHintCache *channeldb.HeightHintCache
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -84,6 +85,18 @@ type AddInvoiceConfig struct {
 	// GetAlias allows the peer's alias SCID to be retrieved for private
 	// option_scid_alias channels.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// FallbackAddr, if set, returns the fallback address that is added to
+	// invoices that don't specify one. It may return a nil address, in
+	// which case the invoice has no fallback address.
+	FallbackAddr func(hash lntypes.Hash) (btcutil.Address, error)
+
+	// MaxAmount is the maximum amount of new invoices. If zero, only the
+	// hard limit applies.
+	MaxAmount btcutil.Amount
+
+	// RejectZeroAmount rejects new invoices that don't specify an amount.
+	RejectZeroAmount bool
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -95,13 +108,14 @@ type AddInvoiceData struct {
 	Memo string
 
 	// The preimage which will allow settling an incoming HTLC payable to
-	// this preimage. If Preimage is set, Hash should be nil. If both
-	// Preimage and Hash are nil, a random preimage is generated.
+	// this preimage. If both Preimage and Hash are nil, a random preimage
+	// is generated.
 	Preimage *lntypes.Preimage
 
-	// The hash of the preimage. If Hash is set, Preimage should be nil.
-	// This condition indicates that we have a 'hold invoice' for which the
-	// htlc will be accepted and held until the preimage becomes known.
+	// The hash of the preimage. If Hash is set and Preimage is nil, we
+	// have a 'hold invoice' for which the htlc will be accepted and held
+	// until the preimage becomes known. If both are set, the preimage must
+	// match the hash.
 	Hash *lntypes.Hash
 
 	// The value of this invoice in millisatoshis.
@@ -137,6 +151,16 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually
 	// used to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// SetFeatures are feature bits that are set on top of the default
+	// invoice features. Setting one bit of a feature pair replaces the
+	// other one, so this can be used to switch a feature between optional
+	// and required.
+	SetFeatures []lnwire.FeatureBit
+
+	// UnsetFeatures are features that are removed from the default invoice
+	// features. Both bits of the feature pair are cleared.
+	UnsetFeatures []lnwire.FeatureBit
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -155,6 +179,7 @@ type AddInvoiceData struct {
 //   - Preimage == nil && Hash == nil -> (random preimage, H(random preimage))
 //   - Preimage != nil && Hash == nil -> (Preimage, H(Preimage))
 //   - Preimage == nil && Hash != nil -> (nil, Hash)
+//   - Preimage != nil && Hash != nil -> (Preimage, Hash) if they match
 func (d *AddInvoiceData) paymentHashAndPreimage() (
 	*lntypes.Preimage, lntypes.Hash, error) {
 
@@ -204,10 +229,17 @@ func (d *AddInvoiceData) mppPaymentHashAndPreimage() (*lntypes.Preimage,
 
 	switch {
 
-	// Only either preimage or hash can be set.
+	// If both preimage and hash are set, the caller wants us to verify
+	// that the preimage is the one it expects.
 	case d.Preimage != nil && d.Hash != nil:
-		return nil, lntypes.Hash{},
-			errors.New("preimage and hash both set")
+		if !d.Preimage.Matches(*d.Hash) {
+			return nil, lntypes.Hash{}, fmt.Errorf("preimage "+
+				"doesn't match payment hash %v", *d.Hash)
+		}
+
+		preimage := *d.Preimage
+		paymentPreimage = &preimage
+		paymentHash = *d.Hash
 
 	// If no hash or preimage is given, generate a random preimage.
 	case d.Preimage == nil && d.Hash == nil:
@@ -273,6 +305,18 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		return nil, nil, fmt.Errorf("invoice amount %v is "+
 			"too large, max is %v", invoice.Value.ToSatoshis(),
 			maxInvoiceAmt)
+
+	// Enforce the configured maximum invoice amount, if any.
+	case cfg.MaxAmount > 0 &&
+		invoice.Value > lnwire.NewMSatFromSatoshis(cfg.MaxAmount):
+
+		return nil, nil, fmt.Errorf("invoice amount %v exceeds the "+
+			"configured max invoice amount %v", invoice.Value,
+			cfg.MaxAmount)
+
+	case cfg.RejectZeroAmount && invoice.Value == 0:
+		return nil, nil, errors.New("invoices without an amount are " +
+			"not allowed")
 	}
 
 	amtMSat := invoice.Value
@@ -309,6 +353,22 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		options = append(options, zpay32.FallbackAddr(addr))
 	}
 
+	// Otherwise add the configured fallback address. AMP invoices can be
+	// paid multiple times, so we can't reconcile on-chain payments to them.
+	if len(invoice.FallbackAddr) == 0 && cfg.FallbackAddr != nil &&
+		!invoice.Amp {
+
+		addr, err := cfg.FallbackAddr(paymentHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to get fallback "+
+				"address: %w", err)
+		}
+
+		if addr != nil {
+			options = append(options, zpay32.FallbackAddr(addr))
+		}
+	}
+
 	switch {
 	// If expiry is set, specify it. If it is not provided, no expiry time
 	// will be explicitly added to this payment request, which will imply
@@ -428,6 +488,12 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	} else {
 		invoiceFeatures = cfg.GenInvoiceFeatures()
 	}
+	invoiceFeatures, err = applyFeatureOverrides(
+		invoiceFeatures, invoice.SetFeatures, invoice.UnsetFeatures,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
 	options = append(options, zpay32.Features(invoiceFeatures))
 
 	// Generate and set a random payment address for this invoice. If the
@@ -487,6 +553,84 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	return &paymentHash, newInvoice, nil
 }
 
+// fixedInvoiceFeatures are invoice features that can't be changed through
+// feature overrides. The TLV onion is needed to receive any payment and the AMP
+// features are determined by the invoice type.
+var fixedInvoiceFeatures = map[lnwire.FeatureBit]struct{}{
+	lnwire.TLVOnionPayloadRequired: {},
+	lnwire.TLVOnionPayloadOptional: {},
+	lnwire.AMPRequired:             {},
+	lnwire.AMPOptional:             {},
+}
+
+// applyFeatureOverrides returns a copy of the default invoice features with the
+// given bits set and unset. Only features that are part of the defaults can be
+// changed, since those are the ones we are able to fulfill when receiving the
+// payment.
+func applyFeatureOverrides(defaults *lnwire.FeatureVector, set,
+	unset []lnwire.FeatureBit) (*lnwire.FeatureVector, error) {
+
+	if len(set) == 0 && len(unset) == 0 {
+		return defaults, nil
+	}
+
+	checkBit := func(bit lnwire.FeatureBit) error {
+		if _, ok := fixedInvoiceFeatures[bit]; ok {
+			return fmt.Errorf("invoice feature %v(%d) can't be "+
+				"changed", defaults.Name(bit), bit)
+		}
+
+		if !defaults.HasFeature(bit) {
+			return fmt.Errorf("invoice feature %d is not "+
+				"supported", bit)
+		}
+
+		return nil
+	}
+
+	features := defaults.Clone()
+	for _, bit := range unset {
+		if err := checkBit(bit); err != nil {
+			return nil, err
+		}
+
+		// Every invoice must commit to a payment address, so the
+		// feature can only be switched between optional and required.
+		if bit == lnwire.PaymentAddrRequired ||
+			bit == lnwire.PaymentAddrOptional {
+
+			return nil, fmt.Errorf("invoice feature %v(%d) can't "+
+				"be unset", defaults.Name(bit), bit)
+		}
+
+		features.Unset(bit)
+		features.Unset(bit ^ 1)
+	}
+
+	for _, bit := range set {
+		if err := checkBit(bit); err != nil {
+			return nil, err
+		}
+
+		for _, unsetBit := range unset {
+			if unsetBit == bit || unsetBit == bit^1 {
+				return nil, fmt.Errorf("invoice feature "+
+					"%v(%d) is both set and unset",
+					defaults.Name(bit), bit)
+			}
+		}
+
+		features.Unset(bit ^ 1)
+		features.Set(bit)
+	}
+
+	if err := feature.ValidateDeps(features); err != nil {
+		return nil, fmt.Errorf("invalid invoice features: %w", err)
+	}
+
+	return features, nil
+}
+
 // chanCanBeHopHint returns true if the target channel is eligible to be a hop
 // hint.
 func chanCanBeHopHint(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
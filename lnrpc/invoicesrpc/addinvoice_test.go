@@ -1,6 +1,7 @@
 package invoicesrpc
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/mock"
@@ -896,3 +898,148 @@ func TestPopulateHopHints(t *testing.T) {
 		})
 	}
 }
+
+// defaultInvoiceFeatures returns the default features of a non-AMP invoice.
+func defaultInvoiceFeatures() *lnwire.FeatureVector {
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadRequired,
+			lnwire.PaymentAddrRequired,
+			lnwire.MPPOptional,
+			lnwire.RouteBlindingOptional,
+		), lnwire.Features,
+	)
+}
+
+var applyFeatureOverridesTestCases = []struct {
+	name        string
+	set         []lnwire.FeatureBit
+	unset       []lnwire.FeatureBit
+	expected    []lnwire.FeatureBit
+	expectedErr string
+}{{
+	name: "no overrides",
+	expected: []lnwire.FeatureBit{
+		lnwire.TLVOnionPayloadRequired,
+		lnwire.PaymentAddrRequired,
+		lnwire.MPPOptional,
+		lnwire.RouteBlindingOptional,
+	},
+}, {
+	name: "require mpp and make payment addr optional",
+	set: []lnwire.FeatureBit{
+		lnwire.MPPRequired, lnwire.PaymentAddrOptional,
+	},
+	expected: []lnwire.FeatureBit{
+		lnwire.TLVOnionPayloadRequired,
+		lnwire.PaymentAddrOptional,
+		lnwire.MPPRequired,
+		lnwire.RouteBlindingOptional,
+	},
+}, {
+	name: "remove mpp and route blinding",
+	unset: []lnwire.FeatureBit{
+		lnwire.MPPOptional, lnwire.RouteBlindingRequired,
+	},
+	expected: []lnwire.FeatureBit{
+		lnwire.TLVOnionPayloadRequired,
+		lnwire.PaymentAddrRequired,
+	},
+}, {
+	name:        "unsupported feature",
+	set:         []lnwire.FeatureBit{lnwire.AnchorsZeroFeeHtlcTxOptional},
+	expectedErr: "not supported",
+}, {
+	name:        "fixed feature",
+	set:         []lnwire.FeatureBit{lnwire.AMPRequired},
+	expectedErr: "can't be changed",
+}, {
+	name:        "remove payment addr",
+	unset:       []lnwire.FeatureBit{lnwire.PaymentAddrRequired},
+	expectedErr: "can't be unset",
+}, {
+	name:        "set and unset",
+	set:         []lnwire.FeatureBit{lnwire.MPPRequired},
+	unset:       []lnwire.FeatureBit{lnwire.MPPOptional},
+	expectedErr: "both set and unset",
+}}
+
+// TestApplyFeatureOverrides asserts that the invoice feature overrides are
+// applied to the default features and that features we can't fulfill are
+// rejected.
+func TestApplyFeatureOverrides(t *testing.T) {
+	for _, tc := range applyFeatureOverridesTestCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			defaults := defaultInvoiceFeatures()
+			features, err := applyFeatureOverrides(
+				defaults, tc.set, tc.unset,
+			)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			expected := lnwire.NewRawFeatureVector(tc.expected...)
+			require.Equal(t, expected, features.RawFeatureVector)
+
+			// The defaults must not be modified.
+			require.Equal(
+				t, defaultInvoiceFeatures().RawFeatureVector,
+				defaults.RawFeatureVector,
+			)
+		})
+	}
+}
+
+// TestMppPaymentHashAndPreimage asserts that a caller supplied preimage is
+// only accepted together with a payment hash if both match.
+func TestMppPaymentHashAndPreimage(t *testing.T) {
+	t.Parallel()
+
+	preimage := lntypes.Preimage{1}
+	hash := preimage.Hash()
+
+	// A preimage on its own determines the payment hash.
+	data := &AddInvoiceData{Preimage: &preimage}
+	gotPreimage, gotHash, err := data.paymentHashAndPreimage()
+	require.NoError(t, err)
+	require.Equal(t, preimage, *gotPreimage)
+	require.Equal(t, hash, gotHash)
+
+	// A matching payment hash is accepted.
+	data = &AddInvoiceData{Preimage: &preimage, Hash: &hash}
+	gotPreimage, gotHash, err = data.paymentHashAndPreimage()
+	require.NoError(t, err)
+	require.Equal(t, preimage, *gotPreimage)
+	require.Equal(t, hash, gotHash)
+
+	// A payment hash that doesn't match the preimage is rejected.
+	otherHash := lntypes.Hash{2}
+	data = &AddInvoiceData{Preimage: &preimage, Hash: &otherHash}
+	_, _, err = data.paymentHashAndPreimage()
+	require.ErrorContains(t, err, "doesn't match payment hash")
+}
+
+// TestAddInvoiceAmountLimits asserts that invoices exceeding the configured
+// maximum amount or without an amount are rejected if configured.
+func TestAddInvoiceAmountLimits(t *testing.T) {
+	t.Parallel()
+
+	cfg := &AddInvoiceConfig{
+		MaxAmount:        1000,
+		RejectZeroAmount: true,
+	}
+
+	_, _, err := AddInvoice(context.Background(), cfg, &AddInvoiceData{
+		Value: 1000_001,
+	})
+	require.ErrorContains(t, err, "exceeds the configured max")
+
+	_, _, err = AddInvoice(context.Background(), cfg, &AddInvoiceData{})
+	require.ErrorContains(t, err, "without an amount are not allowed")
+}
@@ -4,9 +4,11 @@
 package invoicesrpc
 
 import (
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
@@ -64,4 +66,15 @@ type Config struct {
 	// GetAlias returns the peer's alias SCID if it exists given the
 	// 32-byte ChannelID.
 	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// FallbackAddr, if set, returns the fallback address that is added to
+	// invoices that don't specify one.
+	FallbackAddr func(hash lntypes.Hash) (btcutil.Address, error)
+
+	// MaxHoldInvoiceAmt is the maximum amount of new hold invoices. If
+	// zero, only the hard limit applies.
+	MaxHoldInvoiceAmt btcutil.Amount
+
+	// RejectZeroAmount rejects new invoices that don't specify an amount.
+	RejectZeroAmount bool
 }
@@ -0,0 +1,335 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// FallbackAddrConfig contains the dependencies of the FallbackAddrManager.
+type FallbackAddrConfig struct {
+	// ChainParams are used to decode the payment requests of pending
+	// invoices on startup.
+	ChainParams *chaincfg.Params
+
+	// NewAddress returns a fresh external address of the wallet.
+	NewAddress func() (btcutil.Address, error)
+
+	// IsOurAddress returns true if the given address belongs to the
+	// wallet.
+	IsOurAddress func(addr btcutil.Address) bool
+
+	// PendingInvoices returns all invoices that are not yet settled or
+	// canceled.
+	PendingInvoices func() ([]invoices.Invoice, error)
+
+	// LookupInvoice looks up the invoice with the given payment hash.
+	LookupInvoice func(hash lntypes.Hash) (invoices.Invoice, error)
+
+	// CancelInvoice cancels the invoice with the given payment hash.
+	CancelInvoice func(hash lntypes.Hash) error
+
+	// SubscribeTransactions subscribes to the transactions of the wallet.
+	SubscribeTransactions func() (lnwallet.TransactionSubscription, error)
+
+	// MaxUnused is the maximum number of addresses that have been handed
+	// out for pending invoices but have not received any funds yet. It
+	// keeps the wallet from moving past its address gap limit.
+	MaxUnused int
+}
+
+// FallbackAddrManager hands out a wallet address as the fallback address of
+// each new invoice and reconciles on-chain payments to those addresses with
+// their invoices.
+//
+// To stay within the wallet's address gap limit, no more than the configured
+// maximum of addresses that haven't received any funds are handed out. Once
+// an invoice is settled or canceled without its fallback address receiving
+// any funds, the address is handed out again for a new invoice instead of
+// deriving a fresh one. An address that received funds is never handed out
+// again.
+type FallbackAddrManager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *FallbackAddrConfig
+
+	// addrs maps the encoded fallback addresses of pending invoices to the
+	// payment hash of their invoice.
+	addrs map[string]lntypes.Hash
+
+	// unused are addresses that haven't received any funds and whose
+	// invoices are settled or canceled, so they can be handed out again.
+	unused []btcutil.Address
+
+	txSub lnwallet.TransactionSubscription
+
+	mu   sync.Mutex
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFallbackAddrManager creates a new FallbackAddrManager.
+func NewFallbackAddrManager(cfg *FallbackAddrConfig) *FallbackAddrManager {
+	return &FallbackAddrManager{
+		cfg:   cfg,
+		addrs: make(map[string]lntypes.Hash),
+		quit:  make(chan struct{}),
+	}
+}
+
+// Start restores the fallback addresses of pending invoices and starts
+// watching the wallet for payments to them.
+func (f *FallbackAddrManager) Start() error {
+	var err error
+	f.started.Do(func() {
+		log.Info("FallbackAddrManager starting")
+
+		if err = f.restore(); err != nil {
+			return
+		}
+
+		f.txSub, err = f.cfg.SubscribeTransactions()
+		if err != nil {
+			return
+		}
+
+		f.wg.Add(1)
+		go f.watchPayments()
+	})
+
+	return err
+}
+
+// Stop stops watching the wallet for payments.
+func (f *FallbackAddrManager) Stop() error {
+	f.stopped.Do(func() {
+		log.Info("FallbackAddrManager shutting down...")
+		defer log.Debug("FallbackAddrManager shutdown complete")
+
+		close(f.quit)
+		if f.txSub != nil {
+			f.txSub.Cancel()
+		}
+		f.wg.Wait()
+	})
+
+	return nil
+}
+
+// restore tracks the wallet owned fallback addresses of all pending invoices.
+func (f *FallbackAddrManager) restore() error {
+	pending, err := f.cfg.PendingInvoices()
+	if err != nil {
+		return fmt.Errorf("unable to fetch pending invoices: %w", err)
+	}
+
+	for _, invoice := range pending {
+		payReq, err := zpay32.Decode(
+			string(invoice.PaymentRequest), f.cfg.ChainParams,
+		)
+		if err != nil {
+			// Keysend and AMP invoices don't have a payment
+			// request.
+			continue
+		}
+
+		addr := payReq.FallbackAddr
+		if addr == nil || payReq.PaymentHash == nil ||
+			!f.cfg.IsOurAddress(addr) {
+
+			continue
+		}
+
+		f.addrs[addr.EncodeAddress()] = *payReq.PaymentHash
+	}
+
+	log.Debugf("Restored %d invoice fallback addresses", len(f.addrs))
+
+	return nil
+}
+
+// FallbackAddr returns the fallback address for the invoice with the given
+// payment hash. A nil address is returned if the maximum number of unused
+// addresses is reached, in which case the invoice should be created without a
+// fallback address.
+func (f *FallbackAddrManager) FallbackAddr(
+	hash lntypes.Hash) (btcutil.Address, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.unused) == 0 {
+		f.prune()
+	}
+
+	// Hand out an address of a settled or canceled invoice again before
+	// we derive a new one.
+	if len(f.unused) > 0 {
+		addr := f.unused[0]
+		f.unused = f.unused[1:]
+		f.addrs[addr.EncodeAddress()] = hash
+
+		return addr, nil
+	}
+
+	if len(f.addrs) >= f.cfg.MaxUnused {
+		log.Warnf("Not adding a fallback address to invoice %v, "+
+			"%d invoice fallback addresses are unused", hash,
+			len(f.addrs))
+
+		return nil, nil
+	}
+
+	addr, err := f.cfg.NewAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	f.addrs[addr.EncodeAddress()] = hash
+
+	return addr, nil
+}
+
+// prune moves the addresses of invoices that are settled or canceled to the
+// addresses that can be handed out again. Only invoices in a final state are
+// considered. An invoice that isn't found may still be added, as its address
+// is handed out before the invoice is added. It must be called with the mutex
+// held.
+func (f *FallbackAddrManager) prune() {
+	for encoded, hash := range f.addrs {
+		invoice, err := f.cfg.LookupInvoice(hash)
+		switch {
+		case errors.Is(err, invoices.ErrInvoiceNotFound):
+			continue
+
+		case err != nil:
+			log.Errorf("Unable to look up invoice %v: %v", hash,
+				err)
+			continue
+
+		case invoice.State != invoices.ContractSettled &&
+			invoice.State != invoices.ContractCanceled:
+
+			continue
+		}
+
+		addr, err := btcutil.DecodeAddress(encoded, f.cfg.ChainParams)
+		if err != nil {
+			log.Errorf("Unable to decode fallback address %v: %v",
+				encoded, err)
+			continue
+		}
+
+		delete(f.addrs, encoded)
+		f.unused = append(f.unused, addr)
+	}
+}
+
+// watchPayments reconciles confirmed wallet transactions that pay to the
+// fallback address of an invoice.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *FallbackAddrManager) watchPayments() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case tx, ok := <-f.txSub.ConfirmedTransactions():
+			if !ok {
+				return
+			}
+
+			f.reconcileTx(tx)
+
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// reconcileTx matches the outputs of the given transaction against the
+// tracked fallback addresses.
+func (f *FallbackAddrManager) reconcileTx(tx *lnwallet.TransactionDetail) {
+	for _, output := range tx.OutputDetails {
+		if !output.IsOurAddress {
+			continue
+		}
+
+		for _, addr := range output.Addresses {
+			encoded := addr.EncodeAddress()
+
+			// The address received funds, so it no longer counts
+			// as unused and is never handed out again.
+			f.mu.Lock()
+			hash, ok := f.addrs[encoded]
+			delete(f.addrs, encoded)
+			f.removeUnused(encoded)
+			f.mu.Unlock()
+
+			if !ok {
+				continue
+			}
+
+			log.Infof("Invoice %v received %v on-chain in %v:%d",
+				hash, output.Value, tx.Hash, output.OutputIndex)
+
+			f.reconcileInvoice(hash, output.Value)
+		}
+	}
+}
+
+// removeUnused removes the given encoded address from the addresses that can
+// be handed out again. It must be called with the mutex held.
+func (f *FallbackAddrManager) removeUnused(encoded string) {
+	for i, addr := range f.unused {
+		if addr.EncodeAddress() == encoded {
+			f.unused = append(f.unused[:i], f.unused[i+1:]...)
+			return
+		}
+	}
+}
+
+// reconcileInvoice cancels the invoice with the given hash if the on-chain
+// payment covers its amount, so that it can't be paid a second time over
+// Lightning.
+func (f *FallbackAddrManager) reconcileInvoice(hash lntypes.Hash,
+	amt btcutil.Amount) {
+
+	invoice, err := f.cfg.LookupInvoice(hash)
+	if err != nil {
+		log.Errorf("Unable to look up invoice %v: %v", hash, err)
+		return
+	}
+
+	if invoice.State != invoices.ContractOpen {
+		log.Warnf("Invoice %v in state %v was also paid on-chain",
+			hash, invoice.State)
+
+		return
+	}
+
+	value := invoice.Terms.Value.ToSatoshis()
+	if amt < value {
+		log.Warnf("On-chain payment of %v to invoice %v is less than "+
+			"the invoice amount %v", amt, hash, value)
+
+		return
+	}
+
+	if err := f.cfg.CancelInvoice(hash); err != nil {
+		log.Errorf("Unable to cancel invoice %v paid on-chain: %v",
+			hash, err)
+
+		return
+	}
+
+	log.Infof("Canceled invoice %v after it was paid on-chain", hash)
+}
@@ -0,0 +1,239 @@
+package invoicesrpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+type txDetail = lnwallet.TransactionDetail
+
+// mockTxSubscription is a mock implementation of the
+// lnwallet.TransactionSubscription interface.
+type mockTxSubscription struct {
+	confirmed chan *txDetail
+}
+
+func (m *mockTxSubscription) ConfirmedTransactions() chan *txDetail {
+	return m.confirmed
+}
+
+func (m *mockTxSubscription) UnconfirmedTransactions() chan *txDetail {
+	return nil
+}
+
+func (m *mockTxSubscription) Cancel() {}
+
+// fallbackAddrHarness holds the mocked dependencies of a FallbackAddrManager.
+type fallbackAddrHarness struct {
+	t *testing.T
+
+	mgr   *FallbackAddrManager
+	txSub *mockTxSubscription
+
+	mu       sync.Mutex
+	nextAddr byte
+	invoices map[lntypes.Hash]invoices.Invoice
+}
+
+func newFallbackAddrHarness(t *testing.T,
+	maxUnused int) *fallbackAddrHarness {
+
+	h := &fallbackAddrHarness{
+		t: t,
+		txSub: &mockTxSubscription{
+			confirmed: make(chan *txDetail),
+		},
+		invoices: make(map[lntypes.Hash]invoices.Invoice),
+	}
+
+	h.mgr = NewFallbackAddrManager(&FallbackAddrConfig{
+		ChainParams: &chaincfg.RegressionNetParams,
+		NewAddress: func() (btcutil.Address, error) {
+			h.nextAddr++
+
+			pkHash := make([]byte, 20)
+			pkHash[0] = h.nextAddr
+
+			return btcutil.NewAddressWitnessPubKeyHash(
+				pkHash, &chaincfg.RegressionNetParams,
+			)
+		},
+		IsOurAddress: func(btcutil.Address) bool {
+			return true
+		},
+		PendingInvoices: func() ([]invoices.Invoice, error) {
+			return nil, nil
+		},
+		LookupInvoice: func(hash lntypes.Hash) (invoices.Invoice,
+			error) {
+
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			invoice, ok := h.invoices[hash]
+			if !ok {
+				return invoices.Invoice{},
+					invoices.ErrInvoiceNotFound
+			}
+
+			return invoice, nil
+		},
+		CancelInvoice: func(hash lntypes.Hash) error {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			invoice := h.invoices[hash]
+			invoice.State = invoices.ContractCanceled
+			h.invoices[hash] = invoice
+
+			return nil
+		},
+		SubscribeTransactions: func() (
+			lnwallet.TransactionSubscription, error) {
+
+			return h.txSub, nil
+		},
+		MaxUnused: maxUnused,
+	})
+
+	require.NoError(t, h.mgr.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.mgr.Stop())
+	})
+
+	return h
+}
+
+// addInvoice adds an open invoice with the given amount and returns the
+// fallback address handed out for it.
+func (h *fallbackAddrHarness) addInvoice(hash lntypes.Hash,
+	amt btcutil.Amount) btcutil.Address {
+
+	addr, err := h.mgr.FallbackAddr(hash)
+	require.NoError(h.t, err)
+
+	h.mu.Lock()
+	h.invoices[hash] = invoices.Invoice{
+		Terms: invoices.ContractTerm{
+			Value: lnwire.NewMSatFromSatoshis(amt),
+		},
+		State: invoices.ContractOpen,
+	}
+	h.mu.Unlock()
+
+	return addr
+}
+
+// setState sets the state of the invoice with the given hash.
+func (h *fallbackAddrHarness) setState(hash lntypes.Hash,
+	state invoices.ContractState) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	invoice := h.invoices[hash]
+	invoice.State = state
+	h.invoices[hash] = invoice
+}
+
+// state returns the state of the invoice with the given hash.
+func (h *fallbackAddrHarness) state(
+	hash lntypes.Hash) invoices.ContractState {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.invoices[hash].State
+}
+
+// pay sends a confirmed transaction paying the given amount to addr.
+func (h *fallbackAddrHarness) pay(addr btcutil.Address, amt btcutil.Amount) {
+	h.txSub.confirmed <- &txDetail{
+		Hash: chainhash.Hash{1},
+		OutputDetails: []lnwallet.OutputDetail{{
+			Addresses:    []btcutil.Address{addr},
+			Value:        amt,
+			IsOurAddress: true,
+		}},
+	}
+}
+
+// TestFallbackAddrMaxUnused asserts that no more than the maximum number of
+// unused addresses is handed out, and that the addresses of settled or
+// canceled invoices are handed out again instead of deriving new ones.
+func TestFallbackAddrMaxUnused(t *testing.T) {
+	t.Parallel()
+
+	h := newFallbackAddrHarness(t, 2)
+
+	first := h.addInvoice(lntypes.Hash{1}, 1000)
+	require.NotNil(t, first)
+	second := h.addInvoice(lntypes.Hash{2}, 1000)
+	require.NotNil(t, second)
+
+	// Both invoices are still open, so no address is handed out for the
+	// third one.
+	require.Nil(t, h.addInvoice(lntypes.Hash{3}, 1000))
+	require.EqualValues(t, 2, h.nextAddr)
+
+	// An address handed out for an invoice that wasn't added yet isn't
+	// handed out again, as the invoice may still be added.
+	h.mu.Lock()
+	delete(h.invoices, lntypes.Hash{2})
+	h.mu.Unlock()
+	require.Nil(t, h.addInvoice(lntypes.Hash{4}, 1000))
+
+	// Once the first invoice is canceled, its unused address is handed out
+	// again for the next invoice instead of deriving a new one.
+	h.setState(lntypes.Hash{1}, invoices.ContractCanceled)
+	reused := h.addInvoice(lntypes.Hash{5}, 1000)
+	require.Equal(t, first.EncodeAddress(), reused.EncodeAddress())
+	require.EqualValues(t, 2, h.nextAddr)
+
+	// The same goes for a settled invoice.
+	h.setState(lntypes.Hash{5}, invoices.ContractSettled)
+	reused = h.addInvoice(lntypes.Hash{6}, 1000)
+	require.Equal(t, first.EncodeAddress(), reused.EncodeAddress())
+	require.EqualValues(t, 2, h.nextAddr)
+}
+
+// TestFallbackAddrReconcile asserts that an invoice is canceled once its
+// fallback address receives an on-chain payment that covers its amount.
+func TestFallbackAddrReconcile(t *testing.T) {
+	t.Parallel()
+
+	h := newFallbackAddrHarness(t, 2)
+
+	// An on-chain payment below the invoice amount leaves the invoice
+	// open. The address received funds, so it is no longer tracked and a
+	// later payment to it isn't reconciled either.
+	underpaid := lntypes.Hash{1}
+	addr := h.addInvoice(underpaid, 1000)
+	h.pay(addr, 500)
+	h.pay(addr, 1000)
+
+	// A payment covering the amount of a second invoice cancels it.
+	paid := lntypes.Hash{2}
+	addr = h.addInvoice(paid, 1000)
+	h.pay(addr, 1000)
+
+	require.Eventually(t, func() bool {
+		return h.state(paid) == invoices.ContractCanceled
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, invoices.ContractOpen, h.state(underpaid))
+
+	// A paid address is not handed out again.
+	h.setState(lntypes.Hash{1}, invoices.ContractCanceled)
+	require.NotNil(t, h.addInvoice(lntypes.Hash{3}, 1000))
+	require.EqualValues(t, 3, h.nextAddr)
+}
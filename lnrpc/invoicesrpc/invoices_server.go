@@ -334,6 +334,9 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
 		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
 		GetAlias:              s.cfg.GetAlias,
+		FallbackAddr:          s.cfg.FallbackAddr,
+		MaxAmount:             s.cfg.MaxHoldInvoiceAmt,
+		RejectZeroAmount:      s.cfg.RejectZeroAmount,
 	}
 
 	hash, err := lntypes.MakeHash(invoice.Hash)
@@ -363,6 +366,8 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		HodlInvoice:     true,
 		Preimage:        nil,
 		RouteHints:      routeHints,
+		SetFeatures:     CreateFeatureBits(invoice.SetFeatures),
+		UnsetFeatures:   CreateFeatureBits(invoice.UnsetFeatures),
 	}
 
 	_, dbInvoice, err := AddInvoice(ctx, addInvoiceCfg, addInvoiceData)
@@ -236,6 +236,21 @@ func CreateRPCFeatures(fv *lnwire.FeatureVector) map[uint32]*lnrpc.Feature {
 	return rpcFeatures
 }
 
+// CreateFeatureBits converts a list of lnrpc feature bits into their lnwire
+// representation.
+func CreateFeatureBits(rpcBits []lnrpc.FeatureBit) []lnwire.FeatureBit {
+	if len(rpcBits) == 0 {
+		return nil
+	}
+
+	bits := make([]lnwire.FeatureBit, 0, len(rpcBits))
+	for _, bit := range rpcBits {
+		bits = append(bits, lnwire.FeatureBit(bit))
+	}
+
+	return bits
+}
+
 // CreateRPCRouteHints takes in the decoded form of an invoice's route hints
 // and converts them into the lnrpc type.
 func CreateRPCRouteHints(routeHints [][]zpay32.HopHint) []*lnrpc.RouteHint {
@@ -23,6 +23,12 @@ type LightningClient interface {
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
 	// of the wallet.
 	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	// lncli: `balancebreakdown`
+	// BalanceBreakdown returns a detailed breakdown of all on-chain funds,
+	// including funds that can't be spent yet. The available, reserved,
+	// unconfirmed, locked and immature balances are disjoint and always add up
+	// to the total balance.
+	BalanceBreakdown(ctx context.Context, in *BalanceBreakdownRequest, opts ...grpc.CallOption) (*BalanceBreakdownResponse, error)
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
 	// categorized in local/remote, pending local/remote and unsettled local/remote
@@ -85,11 +91,33 @@ type LightningClient interface {
 	// the networking level, and is used for communication between nodes. This is
 	// distinct from establishing a channel with a peer.
 	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	// lncli: `testconnection`
+	// TestConnection checks whether a remote peer can be reached over the network
+	// without establishing a lasting connection. It dials the peer, performs the
+	// transport handshake and exchanges init messages, then disconnects right
+	// away. The latency of the connection and the features advertised by the
+	// peer are returned. This fails if we're already connected to the peer, as a
+	// second connection would replace the existing one.
+	TestConnection(ctx context.Context, in *TestConnectionRequest, opts ...grpc.CallOption) (*TestConnectionResponse, error)
 	// lncli: `disconnect`
 	// DisconnectPeer attempts to disconnect one peer from another identified by a
 	// given pubKey. In the case that we currently have a pending or active channel
 	// with the target peer, then this action will be not be allowed.
 	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	// lncli: `banpeer`
+	// BanPeer disconnects the peer identified by the given pubKey and refuses
+	// any connection with it until the ban expires. Unlike DisconnectPeer, this
+	// is allowed while we have channels with the peer. The channels themselves
+	// are not affected, once the ban expires the connection to the peer is
+	// re-established as usual.
+	BanPeer(ctx context.Context, in *BanPeerRequest, opts ...grpc.CallOption) (*BanPeerResponse, error)
+	// lncli: `unbanpeer`
+	// UnbanPeer lifts the ban of the peer identified by the given pubKey before
+	// it expires.
+	UnbanPeer(ctx context.Context, in *UnbanPeerRequest, opts ...grpc.CallOption) (*UnbanPeerResponse, error)
+	// lncli: `listbannedpeers`
+	// ListBannedPeers returns all peers that are currently banned.
+	ListBannedPeers(ctx context.Context, in *ListBannedPeersRequest, opts ...grpc.CallOption) (*ListBannedPeersResponse, error)
 	// lncli: `listpeers`
 	// ListPeers returns a verbose listing of all currently active peers.
 	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
@@ -107,6 +135,12 @@ type LightningClient interface {
 	// and its subsystems. This includes the full configuration and the latest log
 	// entries from the log file.
 	GetDebugInfo(ctx context.Context, in *GetDebugInfoRequest, opts ...grpc.CallOption) (*GetDebugInfoResponse, error)
+	// lncli: `getruntimeconfig`
+	// GetRuntimeConfig returns the resolved configuration the daemon is running
+	// with, after merging the config file and the command line flags. Sensitive
+	// values like passwords are redacted. The deprecated options that are set are
+	// returned as well.
+	GetRuntimeConfig(ctx context.Context, in *GetRuntimeConfigRequest, opts ...grpc.CallOption) (*GetRuntimeConfigResponse, error)
 	// * lncli: `getrecoveryinfo`
 	// GetRecoveryInfo returns information concerning the recovery mode including
 	// whether it's in a recovery mode, whether the recovery is finished, and the
@@ -118,6 +152,20 @@ type LightningClient interface {
 	// workflow and is waiting for confirmations for the funding txn, or is in the
 	// process of closure, either initiated cooperatively or non-cooperatively.
 	PendingChannels(ctx context.Context, in *PendingChannelsRequest, opts ...grpc.CallOption) (*PendingChannelsResponse, error)
+	// lncli: `listcontractresolutions`
+	// ListContractResolutions lists every closed channel whose outputs are still
+	// being resolved on chain. For each output, such as HTLCs and our commitment
+	// output, it reports whether it is waiting for its timelock to expire, being
+	// swept or resolved, together with the height at which it matures.
+	ListContractResolutions(ctx context.Context, in *ListContractResolutionsRequest, opts ...grpc.CallOption) (*ListContractResolutionsResponse, error)
+	// lncli: `getchancommitstate`
+	// GetChannelCommitState returns the raw commitment state of an open channel
+	// as stored in the channel database, for diagnostic purposes. This includes
+	// the balances, HTLCs, commitment heights and fee rates of the local and
+	// remote commitments, and of a pending remote commitment. The call never
+	// modifies the channel state. As the raw state is sensitive, it requires an
+	// admin macaroon.
+	GetChannelCommitState(ctx context.Context, in *ChannelCommitStateRequest, opts ...grpc.CallOption) (*ChannelCommitStateResponse, error)
 	// lncli: `listchannels`
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
@@ -162,6 +210,15 @@ type LightningClient interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(ctx context.Context, in *FundingTransitionMsg, opts ...grpc.CallOption) (*FundingStateStepResp, error)
+	// lncli: `abortchannelopen`
+	// AbortChannelOpen aborts a pending channel open that is still being
+	// negotiated with the peer, and releases the wallet inputs reserved for it.
+	// A channel open can only be aborted until both sides signed the commitment
+	// transactions, after which the funding transaction may be broadcast. Later
+	// attempts fail with a FailedPrecondition error. Completed channel opens are
+	// only remembered by their pending channel ID until lnd restarts, after which
+	// such attempts fail as unknown channel opens instead.
+	AbortChannelOpen(ctx context.Context, in *AbortChannelOpenRequest, opts ...grpc.CallOption) (*AbortChannelOpenResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -177,6 +234,12 @@ type LightningClient interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// lncli: `estimateclosecosts`
+	// EstimateCloseCosts estimates the on-chain fees of closing all open channels
+	// at the current fee rates, both cooperatively and by force. The force close
+	// estimate includes the fees of bumping the commitment through its anchor
+	// and sweeping the outputs back into the wallet. No channel is closed.
+	EstimateCloseCosts(ctx context.Context, in *EstimateCloseCostsRequest, opts ...grpc.CallOption) (*EstimateCloseCostsResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -238,6 +301,12 @@ type LightningClient interface {
 	// of these fields can be set. If no fields are set, then we'll only send out
 	// the latest add/settle events.
 	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	// lncli: `getinvoiceindices`
+	// GetInvoiceIndices returns the highest add_index and settle_index that have
+	// been assigned to invoices so far. A client can store these watermarks and
+	// pass them to SubscribeInvoices later on to be notified of all invoices that
+	// were added or settled in the meantime, without missing or repeating any.
+	GetInvoiceIndices(ctx context.Context, in *GetInvoiceIndicesRequest, opts ...grpc.CallOption) (*GetInvoiceIndicesResponse, error)
 	// lncli: `decodepayreq`
 	// DecodePayReq takes an encoded payment request string and attempts to decode
 	// it, returning a full description of the conditions encoded within the
@@ -301,7 +370,9 @@ type LightningClient interface {
 	// the point of view of the responding node. Events notified include: new
 	// nodes coming online, nodes updating their authenticated attributes, new
 	// channels being advertised, updates in the routing policy for a directional
-	// channel edge, and when channels are closed on-chain.
+	// channel edge, and when channels are closed on-chain. Each update carries a
+	// sequence number that can be used to resume the subscription after a
+	// disconnect. Optionally, a snapshot of the full graph is sent first.
 	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
@@ -313,10 +384,37 @@ type LightningClient interface {
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
 	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
+	// lncli: `fwdpolicies`
+	// ForwardingPolicies returns the effective forwarding policy of our channels.
+	// For channels with an active link, this is the policy the link currently
+	// enforces, which includes overrides that may not have been gossiped yet.
+	// Otherwise it is our latest policy from the graph, or the configured default
+	// policy if we haven't announced one yet.
+	ForwardingPolicies(ctx context.Context, in *ForwardingPoliciesRequest, opts ...grpc.CallOption) (*ForwardingPoliciesResponse, error)
 	// lncli: `updatechanpolicy`
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
-	// channel policies for all channels globally, or a particular channel.
+	// channel policies for all channels globally, or a particular channel. The
+	// update of a particular channel is applied right away, a global update is
+	// queued for all channels and applied gradually like QueuePolicyUpdates.
 	UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error)
+	// lncli: `queuechanpolicy`
+	// QueuePolicyUpdates queues channel policy updates that are applied
+	// gradually, so that updating many channels at once doesn't cause a burst of
+	// channel updates that floods the network. Each batch updates at most
+	// gossip.policy-update-batch-size channels, and a new batch is applied every
+	// gossip.policy-update-interval. Urgent updates of a single channel skip the
+	// queue.
+	QueuePolicyUpdates(ctx context.Context, in *QueuePolicyUpdatesRequest, opts ...grpc.CallOption) (*QueuePolicyUpdatesResponse, error)
+	// lncli: `chanpolicyqueue`
+	// PolicyUpdateQueueStatus reports the progress of the queued channel policy
+	// updates.
+	PolicyUpdateQueueStatus(ctx context.Context, in *PolicyUpdateQueueStatusRequest, opts ...grpc.CallOption) (*PolicyUpdateQueueStatusResponse, error)
+	// lncli: `simulatefeechange`
+	// SimulateFeeChange estimates how proposed fee changes of our channels would
+	// affect their routing volume. It samples random payments between other
+	// nodes of the graph and compares the cheapest routes for them with the
+	// current and the proposed fees. Nothing is changed by this call.
+	SimulateFeeChange(ctx context.Context, in *SimulateFeeChangeRequest, opts ...grpc.CallOption) (*SimulateFeeChangeResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -344,17 +442,61 @@ type LightningClient interface {
 	// as well, which contains a single encrypted blob containing the backups of
 	// each channel.
 	ExportAllChannelBackups(ctx context.Context, in *ChanBackupExportRequest, opts ...grpc.CallOption) (*ChanBackupSnapshot, error)
+	// lncli: `exportchanbackupfile`
+	// ExportAllChannelBackupsToFile writes a multi-channel backup of all
+	// existing channels known to lnd, including pending channels, to the given
+	// path on the machine lnd is running on. It can be used to take a snapshot
+	// of the channel backups at a custom location, independently of the auto
+	// updated channel.backup file. The file is written atomically, so it only
+	// appears once it contains the complete backup. An existing file is never
+	// overwritten.
+	ExportAllChannelBackupsToFile(ctx context.Context, in *ExportAllChannelBackupsToFileRequest, opts ...grpc.CallOption) (*ExportAllChannelBackupsToFileResponse, error)
 	// lncli: `verifychanbackup`
 	// VerifyChanBackup allows a caller to verify the integrity of a channel backup
 	// snapshot. This method will accept either a packed Single or a packed Multi.
 	// Specifying both will result in an error.
 	VerifyChanBackup(ctx context.Context, in *ChanBackupSnapshot, opts ...grpc.CallOption) (*VerifyChanBackupResponse, error)
+	// lncli: `checkchanbackupfile`
+	// CheckChanBackupFile re-derives the static channel backups of all current
+	// channels and compares them against the channel backup file on disk. It
+	// reports the channels that are missing from the file and the channels that
+	// are in the file but no longer exist. The order of the channels is ignored.
+	CheckChanBackupFile(ctx context.Context, in *CheckChanBackupFileRequest, opts ...grpc.CallOption) (*CheckChanBackupFileResponse, error)
+	// lncli: `snapshotchanneldb`
+	// SnapshotChannelDB writes a consistent point-in-time copy of the full
+	// channel database to a new file at the given path on the machine lnd is
+	// running on. Unlike static channel backups, the snapshot contains the
+	// complete channel state. The copy is created within a single read
+	// transaction, so the live database is never modified. Only supported by the
+	// bolt and etcd database backends.
+	//
+	// WARNING: The snapshot is outdated as soon as a channel is updated after it
+	// was taken. Starting lnd with an outdated snapshot makes it broadcast
+	// revoked channel states, which lets the channel peers claim ALL funds of
+	// those channels with justice transactions. Never restore a snapshot to
+	// recover funds, use static channel backups (SCB) for that instead.
+	SnapshotChannelDB(ctx context.Context, in *SnapshotChannelDBRequest, opts ...grpc.CallOption) (*SnapshotChannelDBResponse, error)
+	// lncli: `compactchanneldb`
+	// CompactChannelDB compacts the channel database while lnd is running, to
+	// reclaim the space of deleted data without a restart. Reads and writes
+	// continue while the compacted copy is created, writes are only paused to
+	// swap it in. If data was written meanwhile, the copy is created again, so
+	// no write is lost. The compaction is aborted and the database left
+	// untouched if it doesn't finish within the given timeout or the call is
+	// canceled. Only supported by the bolt database backend, and only if
+	// db.bolt.compact-on-demand is set.
+	CompactChannelDB(ctx context.Context, in *CompactChannelDBRequest, opts ...grpc.CallOption) (*CompactChannelDBResponse, error)
 	// lncli: `restorechanbackup`
 	// RestoreChannelBackups accepts a set of singular channel backups, or a
 	// single encrypted multi-chan backup and attempts to recover any funds
 	// remaining within the channel. If we are able to unpack the backup, then the
 	// new channel will be shown under listchannels, as well as pending channels.
 	RestoreChannelBackups(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error)
+	// lncli: `channelrecoverystatus`
+	// ChannelRecoveryStatus lists the channels that were restored from a static
+	// channel backup together with the state of their recovery, from waiting for
+	// the channel peer to the sweep of our funds being confirmed.
+	ChannelRecoveryStatus(ctx context.Context, in *ChannelRecoveryStatusRequest, opts ...grpc.CallOption) (*ChannelRecoveryStatusResponse, error)
 	// SubscribeChannelBackups allows a client to sub-subscribe to the most up to
 	// date information concerning the state of all channel backups. Each time a
 	// new channel is added, we return the new set of channels, along with a
@@ -435,6 +577,15 @@ func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRe
 	return out, nil
 }
 
+func (c *lightningClient) BalanceBreakdown(ctx context.Context, in *BalanceBreakdownRequest, opts ...grpc.CallOption) (*BalanceBreakdownResponse, error) {
+	out := new(BalanceBreakdownResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BalanceBreakdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
 	out := new(ChannelBalanceResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, opts...)
@@ -557,6 +708,15 @@ func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerReques
 	return out, nil
 }
 
+func (c *lightningClient) TestConnection(ctx context.Context, in *TestConnectionRequest, opts ...grpc.CallOption) (*TestConnectionResponse, error) {
+	out := new(TestConnectionResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/TestConnection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
 	out := new(DisconnectPeerResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, opts...)
@@ -566,6 +726,33 @@ func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeer
 	return out, nil
 }
 
+func (c *lightningClient) BanPeer(ctx context.Context, in *BanPeerRequest, opts ...grpc.CallOption) (*BanPeerResponse, error) {
+	out := new(BanPeerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BanPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UnbanPeer(ctx context.Context, in *UnbanPeerRequest, opts ...grpc.CallOption) (*UnbanPeerResponse, error) {
+	out := new(UnbanPeerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UnbanPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListBannedPeers(ctx context.Context, in *ListBannedPeersRequest, opts ...grpc.CallOption) (*ListBannedPeersResponse, error) {
+	out := new(ListBannedPeersResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListBannedPeers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
 	out := new(ListPeersResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, opts...)
@@ -625,6 +812,15 @@ func (c *lightningClient) GetDebugInfo(ctx context.Context, in *GetDebugInfoRequ
 	return out, nil
 }
 
+func (c *lightningClient) GetRuntimeConfig(ctx context.Context, in *GetRuntimeConfigRequest, opts ...grpc.CallOption) (*GetRuntimeConfigResponse, error) {
+	out := new(GetRuntimeConfigResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetRuntimeConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error) {
 	out := new(GetRecoveryInfoResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetRecoveryInfo", in, out, opts...)
@@ -643,6 +839,24 @@ func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChanne
 	return out, nil
 }
 
+func (c *lightningClient) ListContractResolutions(ctx context.Context, in *ListContractResolutionsRequest, opts ...grpc.CallOption) (*ListContractResolutionsResponse, error) {
+	out := new(ListContractResolutionsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListContractResolutions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetChannelCommitState(ctx context.Context, in *ChannelCommitStateRequest, opts ...grpc.CallOption) (*ChannelCommitStateResponse, error) {
+	out := new(ChannelCommitStateResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetChannelCommitState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
 	out := new(ListChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, opts...)
@@ -752,6 +966,15 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 	return out, nil
 }
 
+func (c *lightningClient) AbortChannelOpen(ctx context.Context, in *AbortChannelOpenRequest, opts ...grpc.CallOption) (*AbortChannelOpenResponse, error) {
+	out := new(AbortChannelOpenResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbortChannelOpen", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
@@ -815,6 +1038,15 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) EstimateCloseCosts(ctx context.Context, in *EstimateCloseCostsRequest, opts ...grpc.CallOption) (*EstimateCloseCostsResponse, error) {
+	out := new(EstimateCloseCostsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/EstimateCloseCosts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -965,6 +1197,15 @@ func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
 	return m, nil
 }
 
+func (c *lightningClient) GetInvoiceIndices(ctx context.Context, in *GetInvoiceIndicesRequest, opts ...grpc.CallOption) (*GetInvoiceIndicesResponse, error) {
+	out := new(GetInvoiceIndicesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetInvoiceIndices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
 	out := new(PayReq)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, opts...)
@@ -1114,6 +1355,15 @@ func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, o
 	return out, nil
 }
 
+func (c *lightningClient) ForwardingPolicies(ctx context.Context, in *ForwardingPoliciesRequest, opts ...grpc.CallOption) (*ForwardingPoliciesResponse, error) {
+	out := new(ForwardingPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ForwardingPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpdateRequest, opts ...grpc.CallOption) (*PolicyUpdateResponse, error) {
 	out := new(PolicyUpdateResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelPolicy", in, out, opts...)
@@ -1123,6 +1373,33 @@ func (c *lightningClient) UpdateChannelPolicy(ctx context.Context, in *PolicyUpd
 	return out, nil
 }
 
+func (c *lightningClient) QueuePolicyUpdates(ctx context.Context, in *QueuePolicyUpdatesRequest, opts ...grpc.CallOption) (*QueuePolicyUpdatesResponse, error) {
+	out := new(QueuePolicyUpdatesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/QueuePolicyUpdates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PolicyUpdateQueueStatus(ctx context.Context, in *PolicyUpdateQueueStatusRequest, opts ...grpc.CallOption) (*PolicyUpdateQueueStatusResponse, error) {
+	out := new(PolicyUpdateQueueStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/PolicyUpdateQueueStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SimulateFeeChange(ctx context.Context, in *SimulateFeeChangeRequest, opts ...grpc.CallOption) (*SimulateFeeChangeResponse, error) {
+	out := new(SimulateFeeChangeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SimulateFeeChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) ForwardingHistory(ctx context.Context, in *ForwardingHistoryRequest, opts ...grpc.CallOption) (*ForwardingHistoryResponse, error) {
 	out := new(ForwardingHistoryResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ForwardingHistory", in, out, opts...)
@@ -1150,6 +1427,15 @@ func (c *lightningClient) ExportAllChannelBackups(ctx context.Context, in *ChanB
 	return out, nil
 }
 
+func (c *lightningClient) ExportAllChannelBackupsToFile(ctx context.Context, in *ExportAllChannelBackupsToFileRequest, opts ...grpc.CallOption) (*ExportAllChannelBackupsToFileResponse, error) {
+	out := new(ExportAllChannelBackupsToFileResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportAllChannelBackupsToFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) VerifyChanBackup(ctx context.Context, in *ChanBackupSnapshot, opts ...grpc.CallOption) (*VerifyChanBackupResponse, error) {
 	out := new(VerifyChanBackupResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/VerifyChanBackup", in, out, opts...)
@@ -1159,6 +1445,33 @@ func (c *lightningClient) VerifyChanBackup(ctx context.Context, in *ChanBackupSn
 	return out, nil
 }
 
+func (c *lightningClient) CheckChanBackupFile(ctx context.Context, in *CheckChanBackupFileRequest, opts ...grpc.CallOption) (*CheckChanBackupFileResponse, error) {
+	out := new(CheckChanBackupFileResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CheckChanBackupFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SnapshotChannelDB(ctx context.Context, in *SnapshotChannelDBRequest, opts ...grpc.CallOption) (*SnapshotChannelDBResponse, error) {
+	out := new(SnapshotChannelDBResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SnapshotChannelDB", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CompactChannelDB(ctx context.Context, in *CompactChannelDBRequest, opts ...grpc.CallOption) (*CompactChannelDBResponse, error) {
+	out := new(CompactChannelDBResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CompactChannelDB", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreBackupResponse, error) {
 	out := new(RestoreBackupResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RestoreChannelBackups", in, out, opts...)
@@ -1168,6 +1481,15 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 	return out, nil
 }
 
+func (c *lightningClient) ChannelRecoveryStatus(ctx context.Context, in *ChannelRecoveryStatusRequest, opts ...grpc.CallOption) (*ChannelRecoveryStatusResponse, error) {
+	out := new(ChannelRecoveryStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ChannelRecoveryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
@@ -1344,6 +1666,12 @@ type LightningServer interface {
 	// confirmed unspent outputs and all unconfirmed unspent outputs under control
 	// of the wallet.
 	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
+	// lncli: `balancebreakdown`
+	// BalanceBreakdown returns a detailed breakdown of all on-chain funds,
+	// including funds that can't be spent yet. The available, reserved,
+	// unconfirmed, locked and immature balances are disjoint and always add up
+	// to the total balance.
+	BalanceBreakdown(context.Context, *BalanceBreakdownRequest) (*BalanceBreakdownResponse, error)
 	// lncli: `channelbalance`
 	// ChannelBalance returns a report on the total funds across all open channels,
 	// categorized in local/remote, pending local/remote and unsettled local/remote
@@ -1406,11 +1734,33 @@ type LightningServer interface {
 	// the networking level, and is used for communication between nodes. This is
 	// distinct from establishing a channel with a peer.
 	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
+	// lncli: `testconnection`
+	// TestConnection checks whether a remote peer can be reached over the network
+	// without establishing a lasting connection. It dials the peer, performs the
+	// transport handshake and exchanges init messages, then disconnects right
+	// away. The latency of the connection and the features advertised by the
+	// peer are returned. This fails if we're already connected to the peer, as a
+	// second connection would replace the existing one.
+	TestConnection(context.Context, *TestConnectionRequest) (*TestConnectionResponse, error)
 	// lncli: `disconnect`
 	// DisconnectPeer attempts to disconnect one peer from another identified by a
 	// given pubKey. In the case that we currently have a pending or active channel
 	// with the target peer, then this action will be not be allowed.
 	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
+	// lncli: `banpeer`
+	// BanPeer disconnects the peer identified by the given pubKey and refuses
+	// any connection with it until the ban expires. Unlike DisconnectPeer, this
+	// is allowed while we have channels with the peer. The channels themselves
+	// are not affected, once the ban expires the connection to the peer is
+	// re-established as usual.
+	BanPeer(context.Context, *BanPeerRequest) (*BanPeerResponse, error)
+	// lncli: `unbanpeer`
+	// UnbanPeer lifts the ban of the peer identified by the given pubKey before
+	// it expires.
+	UnbanPeer(context.Context, *UnbanPeerRequest) (*UnbanPeerResponse, error)
+	// lncli: `listbannedpeers`
+	// ListBannedPeers returns all peers that are currently banned.
+	ListBannedPeers(context.Context, *ListBannedPeersRequest) (*ListBannedPeersResponse, error)
 	// lncli: `listpeers`
 	// ListPeers returns a verbose listing of all currently active peers.
 	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
@@ -1428,6 +1778,12 @@ type LightningServer interface {
 	// and its subsystems. This includes the full configuration and the latest log
 	// entries from the log file.
 	GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error)
+	// lncli: `getruntimeconfig`
+	// GetRuntimeConfig returns the resolved configuration the daemon is running
+	// with, after merging the config file and the command line flags. Sensitive
+	// values like passwords are redacted. The deprecated options that are set are
+	// returned as well.
+	GetRuntimeConfig(context.Context, *GetRuntimeConfigRequest) (*GetRuntimeConfigResponse, error)
 	// * lncli: `getrecoveryinfo`
 	// GetRecoveryInfo returns information concerning the recovery mode including
 	// whether it's in a recovery mode, whether the recovery is finished, and the
@@ -1439,6 +1795,20 @@ type LightningServer interface {
 	// workflow and is waiting for confirmations for the funding txn, or is in the
 	// process of closure, either initiated cooperatively or non-cooperatively.
 	PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error)
+	// lncli: `listcontractresolutions`
+	// ListContractResolutions lists every closed channel whose outputs are still
+	// being resolved on chain. For each output, such as HTLCs and our commitment
+	// output, it reports whether it is waiting for its timelock to expire, being
+	// swept or resolved, together with the height at which it matures.
+	ListContractResolutions(context.Context, *ListContractResolutionsRequest) (*ListContractResolutionsResponse, error)
+	// lncli: `getchancommitstate`
+	// GetChannelCommitState returns the raw commitment state of an open channel
+	// as stored in the channel database, for diagnostic purposes. This includes
+	// the balances, HTLCs, commitment heights and fee rates of the local and
+	// remote commitments, and of a pending remote commitment. The call never
+	// modifies the channel state. As the raw state is sensitive, it requires an
+	// admin macaroon.
+	GetChannelCommitState(context.Context, *ChannelCommitStateRequest) (*ChannelCommitStateResponse, error)
 	// lncli: `listchannels`
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
@@ -1483,6 +1853,15 @@ type LightningServer interface {
 	// Alternatively, this can be used to interactively drive PSBT signing for
 	// funding for partially complete funding transactions.
 	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	// lncli: `abortchannelopen`
+	// AbortChannelOpen aborts a pending channel open that is still being
+	// negotiated with the peer, and releases the wallet inputs reserved for it.
+	// A channel open can only be aborted until both sides signed the commitment
+	// transactions, after which the funding transaction may be broadcast. Later
+	// attempts fail with a FailedPrecondition error. Completed channel opens are
+	// only remembered by their pending channel ID until lnd restarts, after which
+	// such attempts fail as unknown channel opens instead.
+	AbortChannelOpen(context.Context, *AbortChannelOpenRequest) (*AbortChannelOpenResponse, error)
 	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
 	// OpenChannel requests are sent to the client and the client responds with
 	// a boolean that tells LND whether or not to accept the channel. This allows
@@ -1498,6 +1877,12 @@ type LightningServer interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `estimateclosecosts`
+	// EstimateCloseCosts estimates the on-chain fees of closing all open channels
+	// at the current fee rates, both cooperatively and by force. The force close
+	// estimate includes the fees of bumping the commitment through its anchor
+	// and sweeping the outputs back into the wallet. No channel is closed.
+	EstimateCloseCosts(context.Context, *EstimateCloseCostsRequest) (*EstimateCloseCostsResponse, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -1559,6 +1944,12 @@ type LightningServer interface {
 	// of these fields can be set. If no fields are set, then we'll only send out
 	// the latest add/settle events.
 	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
+	// lncli: `getinvoiceindices`
+	// GetInvoiceIndices returns the highest add_index and settle_index that have
+	// been assigned to invoices so far. A client can store these watermarks and
+	// pass them to SubscribeInvoices later on to be notified of all invoices that
+	// were added or settled in the meantime, without missing or repeating any.
+	GetInvoiceIndices(context.Context, *GetInvoiceIndicesRequest) (*GetInvoiceIndicesResponse, error)
 	// lncli: `decodepayreq`
 	// DecodePayReq takes an encoded payment request string and attempts to decode
 	// it, returning a full description of the conditions encoded within the
@@ -1622,7 +2013,9 @@ type LightningServer interface {
 	// the point of view of the responding node. Events notified include: new
 	// nodes coming online, nodes updating their authenticated attributes, new
 	// channels being advertised, updates in the routing policy for a directional
-	// channel edge, and when channels are closed on-chain.
+	// channel edge, and when channels are closed on-chain. Each update carries a
+	// sequence number that can be used to resume the subscription after a
+	// disconnect. Optionally, a snapshot of the full graph is sent first.
 	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
@@ -1634,10 +2027,37 @@ type LightningServer interface {
 	// FeeReport allows the caller to obtain a report detailing the current fee
 	// schedule enforced by the node globally for each channel.
 	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
+	// lncli: `fwdpolicies`
+	// ForwardingPolicies returns the effective forwarding policy of our channels.
+	// For channels with an active link, this is the policy the link currently
+	// enforces, which includes overrides that may not have been gossiped yet.
+	// Otherwise it is our latest policy from the graph, or the configured default
+	// policy if we haven't announced one yet.
+	ForwardingPolicies(context.Context, *ForwardingPoliciesRequest) (*ForwardingPoliciesResponse, error)
 	// lncli: `updatechanpolicy`
 	// UpdateChannelPolicy allows the caller to update the fee schedule and
-	// channel policies for all channels globally, or a particular channel.
+	// channel policies for all channels globally, or a particular channel. The
+	// update of a particular channel is applied right away, a global update is
+	// queued for all channels and applied gradually like QueuePolicyUpdates.
 	UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error)
+	// lncli: `queuechanpolicy`
+	// QueuePolicyUpdates queues channel policy updates that are applied
+	// gradually, so that updating many channels at once doesn't cause a burst of
+	// channel updates that floods the network. Each batch updates at most
+	// gossip.policy-update-batch-size channels, and a new batch is applied every
+	// gossip.policy-update-interval. Urgent updates of a single channel skip the
+	// queue.
+	QueuePolicyUpdates(context.Context, *QueuePolicyUpdatesRequest) (*QueuePolicyUpdatesResponse, error)
+	// lncli: `chanpolicyqueue`
+	// PolicyUpdateQueueStatus reports the progress of the queued channel policy
+	// updates.
+	PolicyUpdateQueueStatus(context.Context, *PolicyUpdateQueueStatusRequest) (*PolicyUpdateQueueStatusResponse, error)
+	// lncli: `simulatefeechange`
+	// SimulateFeeChange estimates how proposed fee changes of our channels would
+	// affect their routing volume. It samples random payments between other
+	// nodes of the graph and compares the cheapest routes for them with the
+	// current and the proposed fees. Nothing is changed by this call.
+	SimulateFeeChange(context.Context, *SimulateFeeChangeRequest) (*SimulateFeeChangeResponse, error)
 	// lncli: `fwdinghistory`
 	// ForwardingHistory allows the caller to query the htlcswitch for a record of
 	// all HTLCs forwarded within the target time range, and integer offset
@@ -1665,17 +2085,61 @@ type LightningServer interface {
 	// as well, which contains a single encrypted blob containing the backups of
 	// each channel.
 	ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error)
+	// lncli: `exportchanbackupfile`
+	// ExportAllChannelBackupsToFile writes a multi-channel backup of all
+	// existing channels known to lnd, including pending channels, to the given
+	// path on the machine lnd is running on. It can be used to take a snapshot
+	// of the channel backups at a custom location, independently of the auto
+	// updated channel.backup file. The file is written atomically, so it only
+	// appears once it contains the complete backup. An existing file is never
+	// overwritten.
+	ExportAllChannelBackupsToFile(context.Context, *ExportAllChannelBackupsToFileRequest) (*ExportAllChannelBackupsToFileResponse, error)
 	// lncli: `verifychanbackup`
 	// VerifyChanBackup allows a caller to verify the integrity of a channel backup
 	// snapshot. This method will accept either a packed Single or a packed Multi.
 	// Specifying both will result in an error.
 	VerifyChanBackup(context.Context, *ChanBackupSnapshot) (*VerifyChanBackupResponse, error)
+	// lncli: `checkchanbackupfile`
+	// CheckChanBackupFile re-derives the static channel backups of all current
+	// channels and compares them against the channel backup file on disk. It
+	// reports the channels that are missing from the file and the channels that
+	// are in the file but no longer exist. The order of the channels is ignored.
+	CheckChanBackupFile(context.Context, *CheckChanBackupFileRequest) (*CheckChanBackupFileResponse, error)
+	// lncli: `snapshotchanneldb`
+	// SnapshotChannelDB writes a consistent point-in-time copy of the full
+	// channel database to a new file at the given path on the machine lnd is
+	// running on. Unlike static channel backups, the snapshot contains the
+	// complete channel state. The copy is created within a single read
+	// transaction, so the live database is never modified. Only supported by the
+	// bolt and etcd database backends.
+	//
+	// WARNING: The snapshot is outdated as soon as a channel is updated after it
+	// was taken. Starting lnd with an outdated snapshot makes it broadcast
+	// revoked channel states, which lets the channel peers claim ALL funds of
+	// those channels with justice transactions. Never restore a snapshot to
+	// recover funds, use static channel backups (SCB) for that instead.
+	SnapshotChannelDB(context.Context, *SnapshotChannelDBRequest) (*SnapshotChannelDBResponse, error)
+	// lncli: `compactchanneldb`
+	// CompactChannelDB compacts the channel database while lnd is running, to
+	// reclaim the space of deleted data without a restart. Reads and writes
+	// continue while the compacted copy is created, writes are only paused to
+	// swap it in. If data was written meanwhile, the copy is created again, so
+	// no write is lost. The compaction is aborted and the database left
+	// untouched if it doesn't finish within the given timeout or the call is
+	// canceled. Only supported by the bolt database backend, and only if
+	// db.bolt.compact-on-demand is set.
+	CompactChannelDB(context.Context, *CompactChannelDBRequest) (*CompactChannelDBResponse, error)
 	// lncli: `restorechanbackup`
 	// RestoreChannelBackups accepts a set of singular channel backups, or a
 	// single encrypted multi-chan backup and attempts to recover any funds
 	// remaining within the channel. If we are able to unpack the backup, then the
 	// new channel will be shown under listchannels, as well as pending channels.
 	RestoreChannelBackups(context.Context, *RestoreChanBackupRequest) (*RestoreBackupResponse, error)
+	// lncli: `channelrecoverystatus`
+	// ChannelRecoveryStatus lists the channels that were restored from a static
+	// channel backup together with the state of their recovery, from waiting for
+	// the channel peer to the sweep of our funds being confirmed.
+	ChannelRecoveryStatus(context.Context, *ChannelRecoveryStatusRequest) (*ChannelRecoveryStatusResponse, error)
 	// SubscribeChannelBackups allows a client to sub-subscribe to the most up to
 	// date information concerning the state of all channel backups. Each time a
 	// new channel is added, we return the new set of channels, along with a
@@ -1747,6 +2211,9 @@ type UnimplementedLightningServer struct {
 func (UnimplementedLightningServer) WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WalletBalance not implemented")
 }
+func (UnimplementedLightningServer) BalanceBreakdown(context.Context, *BalanceBreakdownRequest) (*BalanceBreakdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BalanceBreakdown not implemented")
+}
 func (UnimplementedLightningServer) ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChannelBalance not implemented")
 }
@@ -1780,9 +2247,21 @@ func (UnimplementedLightningServer) VerifyMessage(context.Context, *VerifyMessag
 func (UnimplementedLightningServer) ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConnectPeer not implemented")
 }
+func (UnimplementedLightningServer) TestConnection(context.Context, *TestConnectionRequest) (*TestConnectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestConnection not implemented")
+}
 func (UnimplementedLightningServer) DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DisconnectPeer not implemented")
 }
+func (UnimplementedLightningServer) BanPeer(context.Context, *BanPeerRequest) (*BanPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanPeer not implemented")
+}
+func (UnimplementedLightningServer) UnbanPeer(context.Context, *UnbanPeerRequest) (*UnbanPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnbanPeer not implemented")
+}
+func (UnimplementedLightningServer) ListBannedPeers(context.Context, *ListBannedPeersRequest) (*ListBannedPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBannedPeers not implemented")
+}
 func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
 }
@@ -1795,12 +2274,21 @@ func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*
 func (UnimplementedLightningServer) GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDebugInfo not implemented")
 }
+func (UnimplementedLightningServer) GetRuntimeConfig(context.Context, *GetRuntimeConfigRequest) (*GetRuntimeConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuntimeConfig not implemented")
+}
 func (UnimplementedLightningServer) GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRecoveryInfo not implemented")
 }
 func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PendingChannels not implemented")
 }
+func (UnimplementedLightningServer) ListContractResolutions(context.Context, *ListContractResolutionsRequest) (*ListContractResolutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContractResolutions not implemented")
+}
+func (UnimplementedLightningServer) GetChannelCommitState(context.Context, *ChannelCommitStateRequest) (*ChannelCommitStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChannelCommitState not implemented")
+}
 func (UnimplementedLightningServer) ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
 }
@@ -1822,12 +2310,18 @@ func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpen
 func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
 }
+func (UnimplementedLightningServer) AbortChannelOpen(context.Context, *AbortChannelOpenRequest) (*AbortChannelOpenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortChannelOpen not implemented")
+}
 func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
 }
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) EstimateCloseCosts(context.Context, *EstimateCloseCostsRequest) (*EstimateCloseCostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateCloseCosts not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
@@ -1855,6 +2349,9 @@ func (UnimplementedLightningServer) LookupInvoice(context.Context, *PaymentHash)
 func (UnimplementedLightningServer) SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeInvoices not implemented")
 }
+func (UnimplementedLightningServer) GetInvoiceIndices(context.Context, *GetInvoiceIndicesRequest) (*GetInvoiceIndicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoiceIndices not implemented")
+}
 func (UnimplementedLightningServer) DecodePayReq(context.Context, *PayReqString) (*PayReq, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DecodePayReq not implemented")
 }
@@ -1897,9 +2394,21 @@ func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelReque
 func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
 }
+func (UnimplementedLightningServer) ForwardingPolicies(context.Context, *ForwardingPoliciesRequest) (*ForwardingPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardingPolicies not implemented")
+}
 func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelPolicy not implemented")
 }
+func (UnimplementedLightningServer) QueuePolicyUpdates(context.Context, *QueuePolicyUpdatesRequest) (*QueuePolicyUpdatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueuePolicyUpdates not implemented")
+}
+func (UnimplementedLightningServer) PolicyUpdateQueueStatus(context.Context, *PolicyUpdateQueueStatusRequest) (*PolicyUpdateQueueStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PolicyUpdateQueueStatus not implemented")
+}
+func (UnimplementedLightningServer) SimulateFeeChange(context.Context, *SimulateFeeChangeRequest) (*SimulateFeeChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateFeeChange not implemented")
+}
 func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
 }
@@ -1909,12 +2418,27 @@ func (UnimplementedLightningServer) ExportChannelBackup(context.Context, *Export
 func (UnimplementedLightningServer) ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExportAllChannelBackups not implemented")
 }
+func (UnimplementedLightningServer) ExportAllChannelBackupsToFile(context.Context, *ExportAllChannelBackupsToFileRequest) (*ExportAllChannelBackupsToFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportAllChannelBackupsToFile not implemented")
+}
 func (UnimplementedLightningServer) VerifyChanBackup(context.Context, *ChanBackupSnapshot) (*VerifyChanBackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyChanBackup not implemented")
 }
+func (UnimplementedLightningServer) CheckChanBackupFile(context.Context, *CheckChanBackupFileRequest) (*CheckChanBackupFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckChanBackupFile not implemented")
+}
+func (UnimplementedLightningServer) SnapshotChannelDB(context.Context, *SnapshotChannelDBRequest) (*SnapshotChannelDBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotChannelDB not implemented")
+}
+func (UnimplementedLightningServer) CompactChannelDB(context.Context, *CompactChannelDBRequest) (*CompactChannelDBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompactChannelDB not implemented")
+}
 func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *RestoreChanBackupRequest) (*RestoreBackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestoreChannelBackups not implemented")
 }
+func (UnimplementedLightningServer) ChannelRecoveryStatus(context.Context, *ChannelRecoveryStatusRequest) (*ChannelRecoveryStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelRecoveryStatus not implemented")
+}
 func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
 }
@@ -1979,6 +2503,24 @@ func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_BalanceBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceBreakdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BalanceBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BalanceBreakdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BalanceBreakdown(ctx, req.(*BalanceBreakdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChannelBalanceRequest)
 	if err := dec(in); err != nil {
@@ -2180,6 +2722,24 @@ func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_TestConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).TestConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/TestConnection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).TestConnection(ctx, req.(*TestConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DisconnectPeerRequest)
 	if err := dec(in); err != nil {
@@ -2198,6 +2758,60 @@ func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_BanPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BanPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BanPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BanPeer(ctx, req.(*BanPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_UnbanPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbanPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UnbanPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UnbanPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UnbanPeer(ctx, req.(*UnbanPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListBannedPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBannedPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListBannedPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListBannedPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListBannedPeers(ctx, req.(*ListBannedPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPeersRequest)
 	if err := dec(in); err != nil {
@@ -2273,6 +2887,24 @@ func _Lightning_GetDebugInfo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GetRuntimeConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuntimeConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetRuntimeConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetRuntimeConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetRuntimeConfig(ctx, req.(*GetRuntimeConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRecoveryInfoRequest)
 	if err := dec(in); err != nil {
@@ -2309,6 +2941,42 @@ func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ListContractResolutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListContractResolutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListContractResolutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListContractResolutions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListContractResolutions(ctx, req.(*ListContractResolutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetChannelCommitState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelCommitStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetChannelCommitState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetChannelCommitState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetChannelCommitState(ctx, req.(*ChannelCommitStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListChannelsRequest)
 	if err := dec(in); err != nil {
@@ -2441,6 +3109,24 @@ func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_AbortChannelOpen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortChannelOpenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AbortChannelOpen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AbortChannelOpen",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AbortChannelOpen(ctx, req.(*AbortChannelOpenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
 }
@@ -2488,6 +3174,24 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_EstimateCloseCosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateCloseCostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).EstimateCloseCosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/EstimateCloseCosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).EstimateCloseCosts(ctx, req.(*EstimateCloseCostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -2669,6 +3373,24 @@ func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_GetInvoiceIndices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceIndicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetInvoiceIndices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetInvoiceIndices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetInvoiceIndices(ctx, req.(*GetInvoiceIndicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PayReqString)
 	if err := dec(in); err != nil {
@@ -2924,6 +3646,24 @@ func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ForwardingPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ForwardingPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ForwardingPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ForwardingPolicies(ctx, req.(*ForwardingPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PolicyUpdateRequest)
 	if err := dec(in); err != nil {
@@ -2942,6 +3682,60 @@ func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_QueuePolicyUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueuePolicyUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).QueuePolicyUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/QueuePolicyUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).QueuePolicyUpdates(ctx, req.(*QueuePolicyUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_PolicyUpdateQueueStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyUpdateQueueStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PolicyUpdateQueueStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PolicyUpdateQueueStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PolicyUpdateQueueStatus(ctx, req.(*PolicyUpdateQueueStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SimulateFeeChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateFeeChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SimulateFeeChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SimulateFeeChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SimulateFeeChange(ctx, req.(*SimulateFeeChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ForwardingHistoryRequest)
 	if err := dec(in); err != nil {
@@ -2996,6 +3790,24 @@ func _Lightning_ExportAllChannelBackups_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ExportAllChannelBackupsToFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportAllChannelBackupsToFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportAllChannelBackupsToFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportAllChannelBackupsToFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportAllChannelBackupsToFile(ctx, req.(*ExportAllChannelBackupsToFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_VerifyChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChanBackupSnapshot)
 	if err := dec(in); err != nil {
@@ -3014,6 +3826,60 @@ func _Lightning_VerifyChanBackup_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CheckChanBackupFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckChanBackupFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CheckChanBackupFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CheckChanBackupFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CheckChanBackupFile(ctx, req.(*CheckChanBackupFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SnapshotChannelDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotChannelDBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SnapshotChannelDB(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SnapshotChannelDB",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SnapshotChannelDB(ctx, req.(*SnapshotChannelDBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_CompactChannelDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactChannelDBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CompactChannelDB(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CompactChannelDB",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CompactChannelDB(ctx, req.(*CompactChannelDBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_RestoreChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RestoreChanBackupRequest)
 	if err := dec(in); err != nil {
@@ -3032,6 +3898,24 @@ func _Lightning_RestoreChannelBackups_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_ChannelRecoveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelRecoveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelRecoveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelRecoveryStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelRecoveryStatus(ctx, req.(*ChannelRecoveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SubscribeChannelBackups_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ChannelBackupSubscription)
 	if err := stream.RecvMsg(m); err != nil {
@@ -3255,6 +4139,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WalletBalance",
 			Handler:    _Lightning_WalletBalance_Handler,
 		},
+		{
+			MethodName: "BalanceBreakdown",
+			Handler:    _Lightning_BalanceBreakdown_Handler,
+		},
 		{
 			MethodName: "ChannelBalance",
 			Handler:    _Lightning_ChannelBalance_Handler,
@@ -3295,10 +4183,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ConnectPeer",
 			Handler:    _Lightning_ConnectPeer_Handler,
 		},
+		{
+			MethodName: "TestConnection",
+			Handler:    _Lightning_TestConnection_Handler,
+		},
 		{
 			MethodName: "DisconnectPeer",
 			Handler:    _Lightning_DisconnectPeer_Handler,
 		},
+		{
+			MethodName: "BanPeer",
+			Handler:    _Lightning_BanPeer_Handler,
+		},
+		{
+			MethodName: "UnbanPeer",
+			Handler:    _Lightning_UnbanPeer_Handler,
+		},
+		{
+			MethodName: "ListBannedPeers",
+			Handler:    _Lightning_ListBannedPeers_Handler,
+		},
 		{
 			MethodName: "ListPeers",
 			Handler:    _Lightning_ListPeers_Handler,
@@ -3311,6 +4215,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDebugInfo",
 			Handler:    _Lightning_GetDebugInfo_Handler,
 		},
+		{
+			MethodName: "GetRuntimeConfig",
+			Handler:    _Lightning_GetRuntimeConfig_Handler,
+		},
 		{
 			MethodName: "GetRecoveryInfo",
 			Handler:    _Lightning_GetRecoveryInfo_Handler,
@@ -3319,6 +4227,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PendingChannels",
 			Handler:    _Lightning_PendingChannels_Handler,
 		},
+		{
+			MethodName: "ListContractResolutions",
+			Handler:    _Lightning_ListContractResolutions_Handler,
+		},
+		{
+			MethodName: "GetChannelCommitState",
+			Handler:    _Lightning_GetChannelCommitState_Handler,
+		},
 		{
 			MethodName: "ListChannels",
 			Handler:    _Lightning_ListChannels_Handler,
@@ -3339,6 +4255,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "AbortChannelOpen",
+			Handler:    _Lightning_AbortChannelOpen_Handler,
+		},
+		{
+			MethodName: "EstimateCloseCosts",
+			Handler:    _Lightning_EstimateCloseCosts_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
@@ -3363,6 +4287,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoice",
 			Handler:    _Lightning_LookupInvoice_Handler,
 		},
+		{
+			MethodName: "GetInvoiceIndices",
+			Handler:    _Lightning_GetInvoiceIndices_Handler,
+		},
 		{
 			MethodName: "DecodePayReq",
 			Handler:    _Lightning_DecodePayReq_Handler,
@@ -3415,10 +4343,26 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FeeReport",
 			Handler:    _Lightning_FeeReport_Handler,
 		},
+		{
+			MethodName: "ForwardingPolicies",
+			Handler:    _Lightning_ForwardingPolicies_Handler,
+		},
 		{
 			MethodName: "UpdateChannelPolicy",
 			Handler:    _Lightning_UpdateChannelPolicy_Handler,
 		},
+		{
+			MethodName: "QueuePolicyUpdates",
+			Handler:    _Lightning_QueuePolicyUpdates_Handler,
+		},
+		{
+			MethodName: "PolicyUpdateQueueStatus",
+			Handler:    _Lightning_PolicyUpdateQueueStatus_Handler,
+		},
+		{
+			MethodName: "SimulateFeeChange",
+			Handler:    _Lightning_SimulateFeeChange_Handler,
+		},
 		{
 			MethodName: "ForwardingHistory",
 			Handler:    _Lightning_ForwardingHistory_Handler,
@@ -3431,14 +4375,34 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExportAllChannelBackups",
 			Handler:    _Lightning_ExportAllChannelBackups_Handler,
 		},
+		{
+			MethodName: "ExportAllChannelBackupsToFile",
+			Handler:    _Lightning_ExportAllChannelBackupsToFile_Handler,
+		},
 		{
 			MethodName: "VerifyChanBackup",
 			Handler:    _Lightning_VerifyChanBackup_Handler,
 		},
+		{
+			MethodName: "CheckChanBackupFile",
+			Handler:    _Lightning_CheckChanBackupFile_Handler,
+		},
+		{
+			MethodName: "SnapshotChannelDB",
+			Handler:    _Lightning_SnapshotChannelDB_Handler,
+		},
+		{
+			MethodName: "CompactChannelDB",
+			Handler:    _Lightning_CompactChannelDB_Handler,
+		},
 		{
 			MethodName: "RestoreChannelBackups",
 			Handler:    _Lightning_RestoreChannelBackups_Handler,
 		},
+		{
+			MethodName: "ChannelRecoveryStatus",
+			Handler:    _Lightning_ChannelRecoveryStatus_Handler,
+		},
 		{
 			MethodName: "BakeMacaroon",
 			Handler:    _Lightning_BakeMacaroon_Handler,
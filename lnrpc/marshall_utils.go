@@ -11,6 +11,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -202,6 +203,9 @@ func UnmarshallCoinSelectionStrategy(strategy CoinSelectionStrategy,
 	case CoinSelectionStrategy_STRATEGY_RANDOM:
 		return wallet.CoinSelectionRandom, nil
 
+	case CoinSelectionStrategy_STRATEGY_BNB:
+		return chanfunding.CoinSelectionBranchAndBound, nil
+
 	default:
 		return nil, fmt.Errorf("unknown coin selection strategy "+
 			"%v", strategy)
@@ -47,6 +47,11 @@ type NeutrinoKitClient interface {
 	// Deprecated, use chainrpc.GetBlockHash instead.
 	// GetBlockHash returns the header hash of a block at a given height.
 	GetBlockHash(ctx context.Context, in *GetBlockHashRequest, opts ...grpc.CallOption) (*GetBlockHashResponse, error)
+	// lncli: `neutrino syncprogress`
+	// GetSyncProgress returns the sync progress of neutrino, broken down into
+	// the block header and the filter header sync phases, each with its current
+	// and target height.
+	GetSyncProgress(ctx context.Context, in *GetSyncProgressRequest, opts ...grpc.CallOption) (*GetSyncProgressResponse, error)
 }
 
 type neutrinoKitClient struct {
@@ -130,6 +135,15 @@ func (c *neutrinoKitClient) GetBlockHash(ctx context.Context, in *GetBlockHashRe
 	return out, nil
 }
 
+func (c *neutrinoKitClient) GetSyncProgress(ctx context.Context, in *GetSyncProgressRequest, opts ...grpc.CallOption) (*GetSyncProgressResponse, error) {
+	out := new(GetSyncProgressResponse)
+	err := c.cc.Invoke(ctx, "/neutrinorpc.NeutrinoKit/GetSyncProgress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NeutrinoKitServer is the server API for NeutrinoKit service.
 // All implementations must embed UnimplementedNeutrinoKitServer
 // for forward compatibility
@@ -163,6 +177,11 @@ type NeutrinoKitServer interface {
 	// Deprecated, use chainrpc.GetBlockHash instead.
 	// GetBlockHash returns the header hash of a block at a given height.
 	GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error)
+	// lncli: `neutrino syncprogress`
+	// GetSyncProgress returns the sync progress of neutrino, broken down into
+	// the block header and the filter header sync phases, each with its current
+	// and target height.
+	GetSyncProgress(context.Context, *GetSyncProgressRequest) (*GetSyncProgressResponse, error)
 	mustEmbedUnimplementedNeutrinoKitServer()
 }
 
@@ -194,6 +213,9 @@ func (UnimplementedNeutrinoKitServer) GetCFilter(context.Context, *GetCFilterReq
 func (UnimplementedNeutrinoKitServer) GetBlockHash(context.Context, *GetBlockHashRequest) (*GetBlockHashResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHash not implemented")
 }
+func (UnimplementedNeutrinoKitServer) GetSyncProgress(context.Context, *GetSyncProgressRequest) (*GetSyncProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSyncProgress not implemented")
+}
 func (UnimplementedNeutrinoKitServer) mustEmbedUnimplementedNeutrinoKitServer() {}
 
 // UnsafeNeutrinoKitServer may be embedded to opt out of forward compatibility for this service.
@@ -351,6 +373,24 @@ func _NeutrinoKit_GetBlockHash_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NeutrinoKit_GetSyncProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSyncProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeutrinoKitServer).GetSyncProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/neutrinorpc.NeutrinoKit/GetSyncProgress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeutrinoKitServer).GetSyncProgress(ctx, req.(*GetSyncProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NeutrinoKit_ServiceDesc is the grpc.ServiceDesc for NeutrinoKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -390,6 +430,10 @@ var NeutrinoKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockHash",
 			Handler:    _NeutrinoKit_GetBlockHash_Handler,
 		},
+		{
+			MethodName: "GetSyncProgress",
+			Handler:    _NeutrinoKit_GetSyncProgress_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "neutrinorpc/neutrino.proto",
@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -60,6 +61,10 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/neutrinorpc.NeutrinoKit/GetSyncProgress": {{
+			Entity: "info",
+			Action: "read",
+		}},
 	}
 
 	// ErrNeutrinoNotActive is an error returned when there is no running
@@ -207,13 +212,25 @@ func (s *Server) Status(ctx context.Context,
 		Peers[i] = p.Addr()
 	}
 
-	return &StatusResponse{
+	resp := &StatusResponse{
 		Active:      s.cfg.NeutrinoCS != nil,
 		BlockHeight: bestBlock.Height,
 		BlockHash:   bestBlock.Hash.String(),
 		Synced:      s.cfg.NeutrinoCS.IsCurrent(),
 		Peers:       Peers,
-	}, nil
+	}
+
+	cacheStats, err := chainreg.NeutrinoFilterCacheStats(s.cfg.NeutrinoCS)
+	if err == nil {
+		resp.FilterCacheCapacity = cacheStats.Capacity
+		resp.FilterCacheEntries = uint32(cacheStats.NumEntries)
+		resp.FilterCacheMisses = cacheStats.Misses
+		resp.FilterCacheDiskHits = cacheStats.DiskHits
+		resp.FilterCacheWalletHits = cacheStats.WalletHits
+		resp.FilterCacheWalletMisses = cacheStats.WalletMisses
+	}
+
+	return resp, nil
 }
 
 // AddPeer adds a new peer that has already been connected to the server.
@@ -441,3 +458,38 @@ func (s *Server) GetBlockHash(ctx context.Context,
 
 	return &GetBlockHashResponse{Hash: hash.String()}, nil
 }
+
+// GetSyncProgress returns the sync progress of neutrino, broken down into the
+// block header and the filter header sync phases.
+//
+// NOTE: Part of the NeutrinoKitServer interface.
+func (s *Server) GetSyncProgress(_ context.Context,
+	_ *GetSyncProgressRequest) (*GetSyncProgressResponse, error) {
+
+	if s.cfg.NeutrinoCS == nil {
+		return nil, ErrNeutrinoNotActive
+	}
+
+	progress, err := chainreg.QueryNeutrinoSyncProgress(s.cfg.NeutrinoCS)
+	if err != nil {
+		return nil, fmt.Errorf("could not get sync progress: %w", err)
+	}
+
+	return &GetSyncProgressResponse{
+		BlockHeaders:  marshalSyncPhase(progress.BlockHeaders),
+		FilterHeaders: marshalSyncPhase(progress.FilterHeaders),
+		Synced:        s.cfg.NeutrinoCS.IsCurrent(),
+		NumPeers:      uint32(progress.NumPeers),
+	}, nil
+}
+
+// marshalSyncPhase converts the progress of a sync phase to its RPC
+// representation.
+func marshalSyncPhase(phase chainreg.SyncPhaseProgress) *SyncPhaseProgress {
+	return &SyncPhaseProgress{
+		Height:       phase.Height,
+		TargetHeight: phase.TargetHeight,
+		Synced:       phase.Synced(),
+		Progress:     phase.Progress(),
+	}
+}
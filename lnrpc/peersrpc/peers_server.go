@@ -15,6 +15,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/record"
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 )
@@ -242,6 +243,57 @@ func (s *Server) updateAddresses(currentAddresses []net.Addr,
 	return newAddrs, ops, nil
 }
 
+// updateTLVRecords computes the new set of custom TLV records of the node
+// announcement after executing the update actions.
+func (s *Server) updateTLVRecords(currentRecords record.CustomSet,
+	updates []*UpdateTlvRecordAction) (record.CustomSet, *lnrpc.Op,
+	error) {
+
+	ops := &lnrpc.Op{Entity: "tlv_records"}
+	newRecords := make(record.CustomSet, len(currentRecords))
+	for recordType, value := range currentRecords {
+		newRecords[recordType] = value
+	}
+
+	updated := make(map[uint64]struct{}, len(updates))
+	for _, update := range updates {
+		// Look for any inconsistency trying to update the same record
+		// more than once.
+		if _, ok := updated[update.Type]; ok {
+			return nil, nil, fmt.Errorf("invalid updates for TLV "+
+				"record type %v: updated more than once",
+				update.Type)
+		}
+		updated[update.Type] = struct{}{}
+
+		switch update.Action {
+		case UpdateAction_ADD:
+			newRecords[update.Type] = update.Value
+			ops.Actions = append(
+				ops.Actions,
+				fmt.Sprintf("%d set", update.Type),
+			)
+
+		case UpdateAction_REMOVE:
+			if _, ok := newRecords[update.Type]; !ok {
+				continue
+			}
+
+			delete(newRecords, update.Type)
+			ops.Actions = append(
+				ops.Actions,
+				fmt.Sprintf("%d removed", update.Type),
+			)
+
+		default:
+			return nil, nil, fmt.Errorf("invalid TLV record "+
+				"update action: %v", update.Action)
+		}
+	}
+
+	return newRecords, ops, nil
+}
+
 // updateFeatures computes the new raw SetNodeAnn after executing the update
 // actions.
 func (s *Server) updateFeatures(currentfeatures *lnwire.RawFeatureVector,
@@ -387,6 +439,35 @@ func (s *Server) UpdateNodeAnnouncement(_ context.Context,
 		)
 	}
 
+	if len(req.TlvRecordUpdates) > 0 {
+		currentRecords, err := netann.DecodeNodeAnnExtraRecords(
+			currentNodeAnn.ExtraOpaqueData,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode current TLV "+
+				"records: %w", err)
+		}
+
+		newRecords, ops, err := s.updateTLVRecords(
+			currentRecords, req.TlvRecordUpdates,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error trying to update node "+
+				"TLV records: %w", err)
+		}
+
+		extraData, err := netann.EncodeNodeAnnExtraRecords(newRecords)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLV records: %w", err)
+		}
+
+		resp.Ops = append(resp.Ops, ops)
+		nodeModifiers = append(
+			nodeModifiers,
+			netann.NodeAnnSetExtraData(extraData),
+		)
+	}
+
 	if len(nodeModifiers) == 0 && !featureUpdates {
 		return nil, fmt.Errorf("unable to detect any new values to " +
 			"update the node announcement")
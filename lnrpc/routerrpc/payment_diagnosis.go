@@ -0,0 +1,310 @@
+package routerrpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// noRouteError is implemented by the errors path finding returns if no route
+// satisfying the restrictions of the route request exists.
+type noRouteError interface {
+	error
+
+	// FailureReason returns the payment-level failure of the error.
+	FailureReason() channeldb.FailureReason
+}
+
+// relaxation describes the restrictions of a route request that are lifted to
+// find out which of them prevent a payment.
+type relaxation struct {
+	noFeeLimit       bool
+	noCltvLimit      bool
+	noMissionControl bool
+}
+
+// DiagnosePayment runs path finding for a payment to the given destination and
+// reports the issues preventing it. If no route is found, the fee limit, cltv
+// limit and mission control are lifted one at a time to determine which of
+// them is responsible. No HTLCs are sent in the process.
+func (r *RouterBackend) DiagnosePayment(
+	in *DiagnosePaymentRequest) (*DiagnosePaymentResponse, error) {
+
+	if in.AmtMsat <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+
+	if _, err := route.NewVertexFromBytes(in.Dest); err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+
+	queryReq := &lnrpc.QueryRoutesRequest{
+		PubKey:            hex.EncodeToString(in.Dest),
+		AmtMsat:           in.AmtMsat,
+		FinalCltvDelta:    in.FinalCltvDelta,
+		CltvLimit:         in.CltvLimit,
+		UseMissionControl: true,
+	}
+	if in.FeeLimitMsat != 0 {
+		queryReq.FeeLimit = &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_FixedMsat{
+				FixedMsat: in.FeeLimitMsat,
+			},
+		}
+	}
+
+	routeReq, err := r.parseQueryRoutesRequest(queryReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// If a route satisfying all restrictions exists, there's nothing to
+	// diagnose.
+	rt, prob, err := r.FindRoute(routeReq)
+	if err == nil {
+		return r.diagnosisResponse(true, rt, prob, nil)
+	}
+
+	// Any other error than a missing route can't be diagnosed further.
+	var noRouteErr noRouteError
+	if !errors.As(err, &noRouteErr) {
+		return nil, err
+	}
+
+	// Lifting any restrictions doesn't help if our own channels can't
+	// carry the payment.
+	if noRouteErr.FailureReason() ==
+		channeldb.FailureReasonInsufficientBalance {
+
+		issue := &PaymentDiagnosis{
+			Issue: PaymentIssue_INSUFFICIENT_LOCAL_BALANCE,
+			Description: fmt.Sprintf("the outbound liquidity of "+
+				"our channels is too low to send %v",
+				routeReq.Amount),
+		}
+
+		return r.diagnosisResponse(
+			false, nil, 0, []*PaymentDiagnosis{issue},
+		)
+	}
+
+	// Lift each restriction on its own first, so the issues are only
+	// attributed to the restrictions that prevent the payment by
+	// themselves.
+	relaxations := []relaxation{
+		{noFeeLimit: true},
+		{noCltvLimit: true},
+		{noMissionControl: true},
+	}
+
+	var (
+		issues    []*PaymentDiagnosis
+		issueRt   *route.Route
+		issueProb float64
+	)
+	for _, relax := range relaxations {
+		relaxedRt, relaxedProb, err := r.FindRoute(
+			relaxRouteRequest(routeReq, relax),
+		)
+		if err != nil {
+			continue
+		}
+
+		relaxedIssues := r.routeIssues(routeReq, relaxedRt, relax)
+		if len(relaxedIssues) == 0 {
+			continue
+		}
+
+		issues = append(issues, relaxedIssues...)
+		if issueRt == nil {
+			issueRt, issueProb = relaxedRt, relaxedProb
+		}
+	}
+
+	// If none of the restrictions prevents the payment on its own, they
+	// might still do so together.
+	if len(issues) == 0 {
+		relax := relaxation{
+			noFeeLimit:       true,
+			noCltvLimit:      true,
+			noMissionControl: true,
+		}
+		issueRt, issueProb, err = r.FindRoute(
+			relaxRouteRequest(routeReq, relax),
+		)
+		if err == nil {
+			issues = r.routeIssues(routeReq, issueRt, relax)
+		}
+	}
+
+	// Without restrictions there's either no route to the destination at
+	// all, or the destination can't be paid for other reasons, such as
+	// missing features.
+	if len(issues) == 0 {
+		issue := &PaymentDiagnosis{
+			Issue:       PaymentIssue_NO_ROUTE,
+			Description: noRouteErr.Error(),
+		}
+
+		return r.diagnosisResponse(
+			false, nil, 0, []*PaymentDiagnosis{issue},
+		)
+	}
+
+	return r.diagnosisResponse(false, issueRt, issueProb, issues)
+}
+
+// relaxRouteRequest returns a copy of the given route request with the
+// restrictions of the relaxation lifted.
+func relaxRouteRequest(req *routing.RouteRequest,
+	relax relaxation) *routing.RouteRequest {
+
+	restrictions := *req.Restrictions
+	if relax.noFeeLimit {
+		restrictions.FeeLimit = lnwire.MaxMilliSatoshi
+	}
+	if relax.noCltvLimit {
+		restrictions.CltvLimit = math.MaxUint32
+	}
+	if relax.noMissionControl {
+		restrictions.ProbabilitySource = func(route.Vertex,
+			route.Vertex, lnwire.MilliSatoshi,
+			btcutil.Amount) float64 {
+
+			return 1
+		}
+	}
+
+	relaxed := *req
+	relaxed.Restrictions = &restrictions
+
+	return &relaxed
+}
+
+// routeIssues returns the issues of the given route, which was found with the
+// restrictions of the relaxation lifted, compared to the original route
+// request.
+func (r *RouterBackend) routeIssues(req *routing.RouteRequest,
+	rt *route.Route, relax relaxation) []*PaymentDiagnosis {
+
+	var issues []*PaymentDiagnosis
+
+	feeLimit := req.Restrictions.FeeLimit
+	if relax.noFeeLimit && rt.TotalFees() > feeLimit {
+		issues = append(issues, &PaymentDiagnosis{
+			Issue: PaymentIssue_FEE_LIMIT_TOO_LOW,
+			Description: fmt.Sprintf("the cheapest route requires "+
+				"a fee of %v, which exceeds the fee limit of "+
+				"%v", rt.TotalFees(), feeLimit),
+			RequiredFeeMsat: int64(rt.TotalFees()),
+		})
+	}
+
+	// The cltv limit of the request excludes the final cltv delta, while
+	// the limit of the RPC includes it.
+	cltvLimit := req.Restrictions.CltvLimit + uint32(req.FinalExpiry)
+	requiredCltv := routeCltvDelta(rt, req.FinalExpiry)
+	if relax.noCltvLimit && requiredCltv > cltvLimit {
+		issues = append(issues, &PaymentDiagnosis{
+			Issue: PaymentIssue_CLTV_LIMIT_TOO_LOW,
+			Description: fmt.Sprintf("the route with the lowest "+
+				"time lock requires a cltv limit of %v, which "+
+				"exceeds the limit of %v", requiredCltv,
+				cltvLimit),
+			RequiredCltvLimit: requiredCltv,
+		})
+	}
+
+	// If mission control was lifted, the failures it recorded may make the
+	// route too unlikely to succeed. Those are the failures for amounts
+	// not larger than what the route sends over the pair. Mission control
+	// is also to blame if nothing else explains why the route wasn't
+	// found with it.
+	if relax.noMissionControl {
+		failedPairs := r.failedPairs(rt)
+		if len(failedPairs) > 0 || len(issues) == 0 {
+			issues = append(issues, &PaymentDiagnosis{
+				Issue: PaymentIssue_MISSION_CONTROL_LIQUIDITY,
+				Description: fmt.Sprintf("mission control "+
+					"recorded failures for %d node pairs "+
+					"of the route, making it too unlikely "+
+					"to succeed", len(failedPairs)),
+				FailedPairs: failedPairs,
+			})
+		}
+	}
+
+	return issues
+}
+
+// routeCltvDelta returns the total time lock of the route relative to the
+// current block height, which is the height the final hop expires at minus its
+// cltv delta.
+func routeCltvDelta(rt *route.Route, finalCltvDelta uint16) uint32 {
+	if len(rt.Hops) == 0 {
+		return 0
+	}
+
+	finalHop := rt.Hops[len(rt.Hops)-1]
+	currentHeight := finalHop.OutgoingTimeLock - uint32(finalCltvDelta)
+
+	return rt.TotalTimeLock - currentHeight
+}
+
+// failedPairs returns the node pairs of the route for which mission control
+// recorded a failure of the amount the route sends over them.
+func (r *RouterBackend) failedPairs(rt *route.Route) []*PairHistory {
+	var pairs []*PairHistory
+
+	amt := rt.TotalAmount
+	for i, hop := range rt.Hops {
+		from := rt.SourcePubKey
+		if i > 0 {
+			from = rt.Hops[i-1].PubKeyBytes
+		}
+		to := hop.PubKeyBytes
+
+		history := r.MissionControl.GetPairHistorySnapshot(from, to)
+		if !history.FailTime.IsZero() && amt >= history.FailAmt {
+			pairs = append(pairs, &PairHistory{
+				NodeFrom: from[:],
+				NodeTo:   to[:],
+				History:  toRPCPairData(&history),
+			})
+		}
+
+		amt = hop.AmtToForward
+	}
+
+	return pairs
+}
+
+// diagnosisResponse assembles the response of a payment diagnosis.
+func (r *RouterBackend) diagnosisResponse(routeFound bool, rt *route.Route,
+	prob float64, issues []*PaymentDiagnosis) (*DiagnosePaymentResponse,
+	error) {
+
+	resp := &DiagnosePaymentResponse{
+		RouteFound:  routeFound,
+		SuccessProb: prob,
+		Issues:      issues,
+	}
+
+	if rt != nil {
+		rpcRoute, err := r.MarshallRoute(rt)
+		if err != nil {
+			return nil, err
+		}
+		resp.Route = rpcRoute
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,268 @@
+package routerrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// diagnosisRouteFee is the fee of the only route the diagnosis tests
+	// find.
+	diagnosisRouteFee = lnwire.MilliSatoshi(1_000)
+
+	// diagnosisRouteCltv is the total time lock of the only route the
+	// diagnosis tests find, excluding the final cltv delta.
+	diagnosisRouteCltv = 300
+
+	// diagnosisHeight is the block height the diagnosis tests use.
+	diagnosisHeight = 100
+)
+
+// testNoRouteError is a path finding error signaling that no route was found.
+type testNoRouteError struct {
+	reason channeldb.FailureReason
+}
+
+// Error returns a string representation of the error.
+func (e testNoRouteError) Error() string {
+	return e.reason.String()
+}
+
+// FailureReason returns the payment-level failure of the error.
+func (e testNoRouteError) FailureReason() channeldb.FailureReason {
+	return e.reason
+}
+
+// diagnosisMissionControl is a mission control mock that reports a low
+// probability and a failure for the last pair of the test route if
+// failedPair is set.
+type diagnosisMissionControl struct {
+	mockMissionControl
+
+	failedPair bool
+}
+
+func (m *diagnosisMissionControl) GetProbability(fromNode, toNode route.Vertex,
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
+
+	if m.failedPair && fromNode == node1 {
+		return 0.01
+	}
+
+	return testMissionControlProb
+}
+
+func (m *diagnosisMissionControl) GetPairHistorySnapshot(fromNode,
+	toNode route.Vertex) routing.TimedPairResult {
+
+	if !m.failedPair || fromNode != node1 {
+		return routing.TimedPairResult{}
+	}
+
+	return routing.TimedPairResult{
+		FailTime: time.Unix(1_000, 0),
+		FailAmt:  lnwire.NewMSatFromSatoshis(100),
+	}
+}
+
+// TestDiagnosePayment tests that the restrictions preventing a payment are
+// reported by the diagnosis.
+func TestDiagnosePayment(t *testing.T) {
+	t.Parallel()
+
+	dest, err := route.NewVertexFromStr(destKey)
+	require.NoError(t, err)
+
+	const amt = lnwire.MilliSatoshi(500_000)
+
+	testCases := []struct {
+		name                string
+		feeLimit            lnwire.MilliSatoshi
+		cltvLimit           uint32
+		failedPair          bool
+		insufficientBalance bool
+		expectedRoute       bool
+		expectedIssues      []PaymentIssue
+	}{
+		{
+			name:          "route found",
+			expectedRoute: true,
+		},
+		{
+			name:     "fee limit",
+			feeLimit: diagnosisRouteFee - 1,
+			expectedIssues: []PaymentIssue{
+				PaymentIssue_FEE_LIMIT_TOO_LOW,
+			},
+		},
+		{
+			name:      "cltv limit",
+			cltvLimit: diagnosisRouteCltv - 1,
+			expectedIssues: []PaymentIssue{
+				PaymentIssue_CLTV_LIMIT_TOO_LOW,
+			},
+		},
+		{
+			name:       "mission control",
+			failedPair: true,
+			expectedIssues: []PaymentIssue{
+				PaymentIssue_MISSION_CONTROL_LIQUIDITY,
+			},
+		},
+		{
+			name:       "fee limit and mission control",
+			feeLimit:   diagnosisRouteFee - 1,
+			failedPair: true,
+			expectedIssues: []PaymentIssue{
+				PaymentIssue_FEE_LIMIT_TOO_LOW,
+				PaymentIssue_MISSION_CONTROL_LIQUIDITY,
+			},
+		},
+		{
+			name:                "insufficient balance",
+			insufficientBalance: true,
+			expectedIssues: []PaymentIssue{
+				PaymentIssue_INSUFFICIENT_LOCAL_BALANCE,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			findRoute := func(req *routing.RouteRequest) (
+				*route.Route, float64, error) {
+
+				noRoute := testNoRouteError{
+					reason: channeldb.FailureReasonNoRoute,
+				}
+				if tc.insufficientBalance {
+					noRoute.reason = channeldb.
+						FailureReasonInsufficientBalance
+
+					return nil, 0, noRoute
+				}
+
+				r := req.Restrictions
+				prob := r.ProbabilitySource(node1, dest, amt, 0)
+				switch {
+				case r.FeeLimit < diagnosisRouteFee:
+					return nil, 0, noRoute
+
+				case r.CltvLimit < diagnosisRouteCltv:
+					return nil, 0, noRoute
+
+				case prob < 0.1:
+					return nil, 0, noRoute
+				}
+
+				finalHeight := uint32(diagnosisHeight) +
+					uint32(req.FinalExpiry)
+
+				hops := []*route.Hop{
+					{
+						PubKeyBytes:  node1,
+						ChannelID:    1,
+						AmtToForward: amt,
+					},
+					{
+						PubKeyBytes:  dest,
+						ChannelID:    2,
+						AmtToForward: amt,
+					},
+				}
+				for _, hop := range hops {
+					hop.OutgoingTimeLock = finalHeight
+				}
+
+				return &route.Route{
+					TotalTimeLock: finalHeight +
+						diagnosisRouteCltv,
+					TotalAmount:  amt + diagnosisRouteFee,
+					SourcePubKey: sourceKey,
+					Hops:         hops,
+				}, prob, nil
+			}
+
+			backend := &RouterBackend{
+				FindRoute:             findRoute,
+				SelfNode:              sourceKey,
+				MaxTotalTimelock:      1_000,
+				DefaultFinalCltvDelta: 40,
+				FetchChannelCapacity: func(chanID uint64) (
+					btcutil.Amount, error) {
+
+					return 1_000_000, nil
+				},
+				MissionControl: &diagnosisMissionControl{
+					failedPair: tc.failedPair,
+				},
+			}
+
+			var cltvLimit uint32
+			if tc.cltvLimit != 0 {
+				cltvLimit = tc.cltvLimit +
+					uint32(backend.DefaultFinalCltvDelta)
+			}
+
+			resp, err := backend.DiagnosePayment(
+				&DiagnosePaymentRequest{
+					Dest:         dest[:],
+					AmtMsat:      int64(amt),
+					FeeLimitMsat: int64(tc.feeLimit),
+					CltvLimit:    cltvLimit,
+				},
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedRoute, resp.RouteFound)
+
+			var issues []PaymentIssue
+			for _, issue := range resp.Issues {
+				issues = append(issues, issue.Issue)
+			}
+			require.Equal(t, tc.expectedIssues, issues)
+
+			// A route is returned unless our own channels can't
+			// carry the payment.
+			if tc.insufficientBalance {
+				require.Nil(t, resp.Route)
+				return
+			}
+			require.NotNil(t, resp.Route)
+
+			for _, issue := range resp.Issues {
+				switch issue.Issue {
+				case PaymentIssue_FEE_LIMIT_TOO_LOW:
+					require.EqualValues(
+						t, diagnosisRouteFee,
+						issue.RequiredFeeMsat,
+					)
+
+				case PaymentIssue_CLTV_LIMIT_TOO_LOW:
+					require.EqualValues(
+						t, cltvLimit+1,
+						issue.RequiredCltvLimit,
+					)
+
+				case PaymentIssue_MISSION_CONTROL_LIQUIDITY:
+					require.Len(t, issue.FailedPairs, 1)
+					require.Equal(
+						t, node1[:],
+						issue.FailedPairs[0].NodeFrom,
+					)
+				}
+			}
+		})
+	}
+}
@@ -80,6 +80,13 @@ type RouterClient interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(ctx context.Context, in *QueryProbabilityRequest, opts ...grpc.CallOption) (*QueryProbabilityResponse, error)
+	// lncli: `diagnosepayment`
+	// DiagnosePayment runs path finding for a payment to the given destination
+	// and reports why the payment can't be routed, such as a missing path,
+	// insufficient local balance, a fee or cltv limit that is too low or
+	// channels that mission control considers to lack liquidity. It is
+	// read-only and doesn't send any HTLCs.
+	DiagnosePayment(ctx context.Context, in *DiagnosePaymentRequest, opts ...grpc.CallOption) (*DiagnosePaymentResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -304,6 +311,15 @@ func (c *routerClient) QueryProbability(ctx context.Context, in *QueryProbabilit
 	return out, nil
 }
 
+func (c *routerClient) DiagnosePayment(ctx context.Context, in *DiagnosePaymentRequest, opts ...grpc.CallOption) (*DiagnosePaymentResponse, error) {
+	out := new(DiagnosePaymentResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/DiagnosePayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
 	out := new(BuildRouteResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/BuildRoute", in, out, opts...)
@@ -516,6 +532,13 @@ type RouterServer interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error)
+	// lncli: `diagnosepayment`
+	// DiagnosePayment runs path finding for a payment to the given destination
+	// and reports why the payment can't be routed, such as a missing path,
+	// insufficient local balance, a fee or cltv limit that is too low or
+	// channels that mission control considers to lack liquidity. It is
+	// read-only and doesn't send any HTLCs.
+	DiagnosePayment(context.Context, *DiagnosePaymentRequest) (*DiagnosePaymentResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -595,6 +618,9 @@ func (UnimplementedRouterServer) SetMissionControlConfig(context.Context, *SetMi
 func (UnimplementedRouterServer) QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryProbability not implemented")
 }
+func (UnimplementedRouterServer) DiagnosePayment(context.Context, *DiagnosePaymentRequest) (*DiagnosePaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiagnosePayment not implemented")
+}
 func (UnimplementedRouterServer) BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildRoute not implemented")
 }
@@ -851,6 +877,24 @@ func _Router_QueryProbability_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_DiagnosePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnosePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).DiagnosePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/DiagnosePayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).DiagnosePayment(ctx, req.(*DiagnosePaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BuildRouteRequest)
 	if err := dec(in); err != nil {
@@ -1019,6 +1063,10 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryProbability",
 			Handler:    _Router_QueryProbability_Handler,
 		},
+		{
+			MethodName: "DiagnosePayment",
+			Handler:    _Router_DiagnosePayment_Handler,
+		},
 		{
 			MethodName: "BuildRoute",
 			Handler:    _Router_BuildRoute_Handler,
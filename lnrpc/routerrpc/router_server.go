@@ -113,6 +113,10 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerrpc.Router/DiagnosePayment": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/routerrpc.Router/ResetMissionControl": {{
 			Entity: "offchain",
 			Action: "write",
@@ -1089,6 +1093,14 @@ func toRPCPairData(data *routing.TimedPairResult) *PairData {
 	return &rpcData
 }
 
+// DiagnosePayment runs path finding for a payment and reports the issues
+// preventing it, without sending any HTLCs.
+func (s *Server) DiagnosePayment(_ context.Context,
+	req *DiagnosePaymentRequest) (*DiagnosePaymentResponse, error) {
+
+	return s.cfg.RouterBackend.DiagnosePayment(req)
+}
+
 // XImportMissionControl imports the state provided to our internal mission
 // control. Only entries that are fresher than our existing state will be used.
 func (s *Server) XImportMissionControl(_ context.Context,
@@ -55,6 +55,14 @@ var (
 	RESTJsonUnmarshalOpts = &protojson.UnmarshalOptions{
 		AllowPartial: false,
 	}
+
+	// ErrFeeRateTooHigh is returned when a requested fee rate exceeds the
+	// configured max fee rate.
+	ErrFeeRateTooHigh = errors.New("fee rate too high")
+
+	// ErrFeeRateTooLow is returned when a requested fee rate is below the
+	// configured min relay fee rate.
+	ErrFeeRateTooLow = errors.New("fee rate too low")
 )
 
 // RPCTransaction returns a rpc transaction.
@@ -197,12 +205,42 @@ func GetChanPointFundingTxid(chanPoint *ChannelPoint) (*chainhash.Hash, error) {
 	return chainhash.NewHash(txid)
 }
 
+// CheckMaxFeeRate returns ErrFeeRateTooHigh if the explicitly requested fee
+// rate exceeds the given max fee rate. A zero max fee rate disables the check.
+func CheckMaxFeeRate(feeRate, maxFeeRate chainfee.SatPerKWeight) error {
+	// Silently capping a fee rate the user asked for could lead to a
+	// transaction that doesn't confirm in time, so we refuse it instead.
+	if maxFeeRate != 0 && feeRate > maxFeeRate {
+		return fmt.Errorf("%w: requested fee rate %v exceeds max fee "+
+			"rate %v", ErrFeeRateTooHigh, feeRate, maxFeeRate)
+	}
+
+	return nil
+}
+
+// CheckMinFeeRate returns ErrFeeRateTooLow if the explicitly requested fee
+// rate is below the given min fee rate. A zero fee rate isn't explicitly
+// requested and a zero min fee rate disables the check.
+func CheckMinFeeRate(feeRate, minFeeRate chainfee.SatPerKWeight) error {
+	if feeRate != 0 && feeRate < minFeeRate {
+		return fmt.Errorf("%w: requested fee rate %v is below min "+
+			"relay fee rate %v", ErrFeeRateTooLow, feeRate,
+			minFeeRate)
+	}
+
+	return nil
+}
+
 // CalculateFeeRate uses either satPerByte or satPerVByte, but not both, from a
 // request to calculate the fee rate. It provides compatibility for the
 // deprecated field, satPerByte. Once the field is safe to be removed, the
-// check can then be deleted.
+// check can then be deleted. If maxFeeRate is non-zero, explicitly requested
+// fee rates above it are rejected and estimated fee rates are capped at it.
+// Likewise, explicitly requested fee rates below a non-zero minFeeRate are
+// rejected.
 func CalculateFeeRate(satPerByte, satPerVByte uint64, targetConf uint32,
-	estimator chainfee.Estimator) (chainfee.SatPerKWeight, error) {
+	estimator chainfee.Estimator, minFeeRate,
+	maxFeeRate chainfee.SatPerKWeight) (chainfee.SatPerKWeight, error) {
 
 	var feeRate chainfee.SatPerKWeight
 
@@ -220,14 +258,20 @@ func CalculateFeeRate(satPerByte, satPerVByte uint64, targetConf uint32,
 		).FeePerKWeight()
 	}
 
+	if err := CheckMinFeeRate(satPerKw, minFeeRate); err != nil {
+		return feeRate, err
+	}
+	if err := CheckMaxFeeRate(satPerKw, maxFeeRate); err != nil {
+		return feeRate, err
+	}
+
 	// Based on the passed fee related parameters, we'll determine an
 	// appropriate fee rate for this transaction.
 	feePref := sweep.FeeEstimateInfo{
 		ConfTarget: targetConf,
 		FeeRate:    satPerKw,
 	}
-	// TODO(yy): need to pass the configured max fee here.
-	feeRate, err := feePref.Estimate(estimator, 0)
+	feeRate, err := feePref.Estimate(estimator, maxFeeRate)
 	if err != nil {
 		return feeRate, err
 	}
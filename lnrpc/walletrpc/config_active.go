@@ -43,6 +43,16 @@ type Config struct {
 	// the WalletKit will use to respond to fee estimation requests.
 	FeeEstimator chainfee.Estimator
 
+	// MinFeeRate is the hard floor for explicitly requested fee rates.
+	// Requests with a lower fee rate are rejected. A zero value disables
+	// the floor.
+	MinFeeRate chainfee.SatPerKWeight
+
+	// MaxFeeRate is the hard ceiling for explicitly requested fee rates.
+	// Requests with a higher fee rate are rejected. A zero value disables
+	// the ceiling.
+	MaxFeeRate chainfee.SatPerKWeight
+
 	// Wallet is the primary wallet that the WalletKit will use to proxy
 	// any relevant requests to.
 	Wallet lnwallet.WalletController
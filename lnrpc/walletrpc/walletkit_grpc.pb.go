@@ -168,6 +168,12 @@ type WalletKitClient interface {
 	// determine the fee (in sat/kw) to attach to a transaction in order to
 	// achieve the confirmation target.
 	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	// lncli: `wallet estimatefeerates`
+	// EstimateFeeRates queries the internal fee estimator of the wallet for the
+	// fee rates to use for each of the given confirmation targets, along with the
+	// source and age of the estimates. In contrast to the EstimateFee RPC in the
+	// main Lightning service, no candidate transaction is constructed.
+	EstimateFeeRates(ctx context.Context, in *EstimateFeeRatesRequest, opts ...grpc.CallOption) (*EstimateFeeRatesResponse, error)
 	// lncli: `pendingsweeps`
 	// PendingSweeps returns lists of on-chain outputs that lnd is currently
 	// attempting to sweep within its central batching engine. Outputs with similar
@@ -219,6 +225,13 @@ type WalletKitClient interface {
 	// overwrite the existing transaction label. Labels must not be empty, and
 	// cannot exceed 500 characters.
 	LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error)
+	// lncli: `wallet querytxs`
+	// QueryTransactions returns the wallet transactions that match all of the
+	// given filters, such as a label substring, an amount range, a confirmation
+	// range or an address. The filters are applied by lnd, and the confirmation
+	// range limits the blocks the wallet has to read. The result can be paginated
+	// with an index offset.
+	QueryTransactions(ctx context.Context, in *QueryTransactionsRequest, opts ...grpc.CallOption) (*QueryTransactionsResponse, error)
 	// lncli: `wallet psbt fund`
 	// FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 	// the outputs specified in the template. There are three ways a user can
@@ -464,6 +477,15 @@ func (c *walletKitClient) EstimateFee(ctx context.Context, in *EstimateFeeReques
 	return out, nil
 }
 
+func (c *walletKitClient) EstimateFeeRates(ctx context.Context, in *EstimateFeeRatesRequest, opts ...grpc.CallOption) (*EstimateFeeRatesResponse, error) {
+	out := new(EstimateFeeRatesResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/EstimateFeeRates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error) {
 	out := new(PendingSweepsResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/PendingSweeps", in, out, opts...)
@@ -500,6 +522,15 @@ func (c *walletKitClient) LabelTransaction(ctx context.Context, in *LabelTransac
 	return out, nil
 }
 
+func (c *walletKitClient) QueryTransactions(ctx context.Context, in *QueryTransactionsRequest, opts ...grpc.CallOption) (*QueryTransactionsResponse, error) {
+	out := new(QueryTransactionsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/QueryTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *walletKitClient) FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error) {
 	out := new(FundPsbtResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/FundPsbt", in, out, opts...)
@@ -679,6 +710,12 @@ type WalletKitServer interface {
 	// determine the fee (in sat/kw) to attach to a transaction in order to
 	// achieve the confirmation target.
 	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	// lncli: `wallet estimatefeerates`
+	// EstimateFeeRates queries the internal fee estimator of the wallet for the
+	// fee rates to use for each of the given confirmation targets, along with the
+	// source and age of the estimates. In contrast to the EstimateFee RPC in the
+	// main Lightning service, no candidate transaction is constructed.
+	EstimateFeeRates(context.Context, *EstimateFeeRatesRequest) (*EstimateFeeRatesResponse, error)
 	// lncli: `pendingsweeps`
 	// PendingSweeps returns lists of on-chain outputs that lnd is currently
 	// attempting to sweep within its central batching engine. Outputs with similar
@@ -730,6 +767,13 @@ type WalletKitServer interface {
 	// overwrite the existing transaction label. Labels must not be empty, and
 	// cannot exceed 500 characters.
 	LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error)
+	// lncli: `wallet querytxs`
+	// QueryTransactions returns the wallet transactions that match all of the
+	// given filters, such as a label substring, an amount range, a confirmation
+	// range or an address. The filters are applied by lnd, and the confirmation
+	// range limits the blocks the wallet has to read. The result can be paginated
+	// with an index offset.
+	QueryTransactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error)
 	// lncli: `wallet psbt fund`
 	// FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 	// the outputs specified in the template. There are three ways a user can
@@ -852,6 +896,9 @@ func (UnimplementedWalletKitServer) SendOutputs(context.Context, *SendOutputsReq
 func (UnimplementedWalletKitServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
 }
+func (UnimplementedWalletKitServer) EstimateFeeRates(context.Context, *EstimateFeeRatesRequest) (*EstimateFeeRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFeeRates not implemented")
+}
 func (UnimplementedWalletKitServer) PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PendingSweeps not implemented")
 }
@@ -864,6 +911,9 @@ func (UnimplementedWalletKitServer) ListSweeps(context.Context, *ListSweepsReque
 func (UnimplementedWalletKitServer) LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LabelTransaction not implemented")
 }
+func (UnimplementedWalletKitServer) QueryTransactions(context.Context, *QueryTransactionsRequest) (*QueryTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryTransactions not implemented")
+}
 func (UnimplementedWalletKitServer) FundPsbt(context.Context, *FundPsbtRequest) (*FundPsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FundPsbt not implemented")
 }
@@ -1246,6 +1296,24 @@ func _WalletKit_EstimateFee_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_EstimateFeeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).EstimateFeeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/EstimateFeeRates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).EstimateFeeRates(ctx, req.(*EstimateFeeRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_PendingSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PendingSweepsRequest)
 	if err := dec(in); err != nil {
@@ -1318,6 +1386,24 @@ func _WalletKit_LabelTransaction_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_QueryTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).QueryTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/QueryTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).QueryTransactions(ctx, req.(*QueryTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletKit_FundPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FundPsbtRequest)
 	if err := dec(in); err != nil {
@@ -1459,6 +1545,10 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EstimateFee",
 			Handler:    _WalletKit_EstimateFee_Handler,
 		},
+		{
+			MethodName: "EstimateFeeRates",
+			Handler:    _WalletKit_EstimateFeeRates_Handler,
+		},
 		{
 			MethodName: "PendingSweeps",
 			Handler:    _WalletKit_PendingSweeps_Handler,
@@ -1475,6 +1565,10 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LabelTransaction",
 			Handler:    _WalletKit_LabelTransaction_Handler,
 		},
+		{
+			MethodName: "QueryTransactions",
+			Handler:    _WalletKit_QueryTransactions_Handler,
+		},
 		{
 			MethodName: "FundPsbt",
 			Handler:    _WalletKit_FundPsbt_Handler,
@@ -98,6 +98,10 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/walletrpc.WalletKit/EstimateFeeRates": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/walletrpc.WalletKit/PendingSweeps": {{
 			Entity: "onchain",
 			Action: "read",
@@ -110,6 +114,10 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/walletrpc.WalletKit/QueryTransactions": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/walletrpc.WalletKit/LabelTransaction": {{
 			Entity: "onchain",
 			Action: "write",
@@ -751,6 +759,14 @@ func (w *WalletKit) SendOutputs(ctx context.Context,
 			"to create")
 	}
 
+	feeRate := chainfee.SatPerKWeight(req.SatPerKw)
+	if err := lnrpc.CheckMinFeeRate(feeRate, w.cfg.MinFeeRate); err != nil {
+		return nil, err
+	}
+	if err := lnrpc.CheckMaxFeeRate(feeRate, w.cfg.MaxFeeRate); err != nil {
+		return nil, err
+	}
+
 	// Before we can request this transaction to be created, we'll need to
 	// amp the protos back into the format that the internal wallet will
 	// recognize.
@@ -811,8 +827,8 @@ func (w *WalletKit) SendOutputs(ctx context.Context,
 	// requirement, we can request that the wallet attempts to create this
 	// transaction.
 	tx, err := w.cfg.Wallet.SendOutputs(
-		outputsToCreate, chainfee.SatPerKWeight(req.SatPerKw), minConfs,
-		label, coinSelectionStrategy,
+		outputsToCreate, feeRate, minConfs, label,
+		coinSelectionStrategy,
 	)
 	if err != nil {
 		return nil, err
@@ -854,6 +870,86 @@ func (w *WalletKit) EstimateFee(ctx context.Context,
 	}, nil
 }
 
+// EstimateFeeRates queries the internal fee estimator of the wallet for the
+// fee rates (in sat/kw) to use for each of the given confirmation targets. The
+// source and age of each estimate are reported as well, so no candidate
+// transaction needs to be constructed.
+func (w *WalletKit) EstimateFeeRates(ctx context.Context,
+	req *EstimateFeeRatesRequest) (*EstimateFeeRatesResponse, error) {
+
+	if len(req.ConfTargets) == 0 {
+		return nil, fmt.Errorf("at least one confirmation target " +
+			"must be specified")
+	}
+
+	// We reject the same confirmation targets as EstimateFee does.
+	for _, confTarget := range req.ConfTargets {
+		if confTarget <= 1 {
+			return nil, fmt.Errorf("confirmation target must be "+
+				"greater than 1, got %v", confTarget)
+		}
+	}
+
+	source, lastUpdate := chainfee.EstimatorSource(w.cfg.FeeEstimator)
+	rpcSource := marshallFeeEstimateSource(source)
+
+	var (
+		lastUpdateTimestamp int64
+		stalenessSeconds    uint64
+	)
+	if !lastUpdate.IsZero() {
+		lastUpdateTimestamp = lastUpdate.Unix()
+		stalenessSeconds = uint64(time.Since(lastUpdate).Seconds())
+	}
+
+	estimates := make([]*FeeRateEstimate, 0, len(req.ConfTargets))
+	for _, confTarget := range req.ConfTargets {
+		satPerKw, err := w.cfg.FeeEstimator.EstimateFeePerKW(
+			confTarget,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee rate "+
+				"for conf target %v: %w", confTarget, err)
+		}
+
+		estimates = append(estimates, &FeeRateEstimate{
+			ConfTarget:          confTarget,
+			SatPerKw:            int64(satPerKw),
+			SatPerVbyte:         int64(satPerKw.FeePerVByte()),
+			Source:              rpcSource,
+			LastUpdateTimestamp: lastUpdateTimestamp,
+			StalenessSeconds:    stalenessSeconds,
+		})
+	}
+
+	return &EstimateFeeRatesResponse{
+		Estimates: estimates,
+	}, nil
+}
+
+// marshallFeeEstimateSource converts the source of a fee estimate into its
+// RPC counterpart.
+func marshallFeeEstimateSource(
+	source chainfee.EstimateSource) FeeEstimateSource {
+
+	switch source {
+	case chainfee.EstimateSourceBackend:
+		return FeeEstimateSource_FEE_ESTIMATE_SOURCE_BACKEND
+
+	case chainfee.EstimateSourceWebAPI:
+		return FeeEstimateSource_FEE_ESTIMATE_SOURCE_WEB_API
+
+	case chainfee.EstimateSourceCache:
+		return FeeEstimateSource_FEE_ESTIMATE_SOURCE_CACHE
+
+	case chainfee.EstimateSourceStatic:
+		return FeeEstimateSource_FEE_ESTIMATE_SOURCE_STATIC
+
+	default:
+		return FeeEstimateSource_FEE_ESTIMATE_SOURCE_UNKNOWN
+	}
+}
+
 // PendingSweeps returns lists of on-chain outputs that lnd is currently
 // attempting to sweep within its central batching engine. Outputs with similar
 // fee rates are batched together in order to sweep them within a single
@@ -997,6 +1093,20 @@ func (w *WalletKit) prepareSweepParams(in *BumpFeeRequest,
 		return sweep.Params{}, false, err
 	}
 
+	// The sweeper would reject a starting fee rate above the max fee rate
+	// as well, but only once it tries to sweep the input.
+	err = fn.MapOptionZ(feerate, func(f chainfee.SatPerKWeight) error {
+		err := lnrpc.CheckMinFeeRate(f, w.cfg.MinFeeRate)
+		if err != nil {
+			return err
+		}
+
+		return lnrpc.CheckMaxFeeRate(f, w.cfg.MaxFeeRate)
+	})
+	if err != nil {
+		return sweep.Params{}, false, err
+	}
+
 	// Get the current pending inputs.
 	inputMap, err := w.cfg.Sweeper.PendingInputs()
 	if err != nil {
@@ -1280,6 +1390,84 @@ func (w *WalletKit) LabelTransaction(ctx context.Context,
 	return &LabelTransactionResponse{}, err
 }
 
+// QueryTransactions returns the wallet transactions that match all of the
+// given filters. The confirmation range of the filter is translated into a
+// block height range, so the wallet only reads the relevant blocks. All other
+// filters are applied before the result is paginated.
+func (w *WalletKit) QueryTransactions(ctx context.Context,
+	req *QueryTransactionsRequest) (*QueryTransactionsResponse, error) {
+
+	if req.MinAmountSat < 0 || req.MaxAmountSat < 0 {
+		return nil, fmt.Errorf("amounts must not be negative")
+	}
+	if req.MinConfs < 0 || req.MaxConfs < 0 {
+		return nil, fmt.Errorf("confirmations must not be negative")
+	}
+
+	filter := &lnwallet.TransactionFilter{
+		LabelContains: req.LabelContains,
+		MinAmount:     btcutil.Amount(req.MinAmountSat),
+		MaxAmount:     btcutil.Amount(req.MaxAmountSat),
+		MinConfs:      req.MinConfs,
+		MaxConfs:      req.MaxConfs,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+	}
+
+	if req.Address != "" {
+		addr, err := btcutil.DecodeAddress(
+			req.Address, w.cfg.ChainParams,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %w", err)
+		}
+		filter.Address = addr
+		filter.FetchInputInfo = w.cfg.Wallet.FetchInputInfo
+	}
+
+	_, bestHeight, err := w.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &QueryTransactionsResponse{}
+
+	startHeight, endHeight, ok := filter.HeightRange(bestHeight)
+	if !ok {
+		return resp, nil
+	}
+
+	txs, err := w.cfg.Wallet.ListTransactionDetails(
+		startHeight, endHeight, req.Account,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := lnwallet.FilterTransactions(txs, filter)
+	resp.TotalMatches = uint64(len(matches))
+
+	// Apply the pagination to the matching transactions.
+	offset := req.IndexOffset
+	if offset > resp.TotalMatches {
+		offset = resp.TotalMatches
+	}
+	end := resp.TotalMatches
+	if req.MaxTransactions != 0 && req.MaxTransactions < end-offset {
+		end = offset + req.MaxTransactions
+	}
+
+	resp.Transactions = make([]*lnrpc.Transaction, 0, end-offset)
+	for _, tx := range matches[offset:end] {
+		resp.Transactions = append(
+			resp.Transactions, lnrpc.RPCTransaction(tx),
+		)
+	}
+	resp.NextIndexOffset = end
+
+	return resp, nil
+}
+
 // FundPsbt creates a fully populated PSBT that contains enough inputs to fund
 // the outputs specified in the template. There are three ways a user can
 // specify what we call the template (a list of inputs and outputs to use in the
@@ -1345,6 +1533,15 @@ func (w *WalletKit) FundPsbt(_ context.Context,
 			req.GetSatPerVbyte() * 1000,
 		).FeePerKWeight()
 
+		err := lnrpc.CheckMinFeeRate(feeSatPerKW, w.cfg.MinFeeRate)
+		if err != nil {
+			return nil, err
+		}
+		err = lnrpc.CheckMaxFeeRate(feeSatPerKW, w.cfg.MaxFeeRate)
+		if err != nil {
+			return nil, err
+		}
+
 	default:
 		return nil, fmt.Errorf("fee definition missing, need to " +
 			"specify either target_conf or sat_per_vbyte")
@@ -5,6 +5,7 @@ package walletrpc
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -18,6 +19,8 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -631,3 +634,123 @@ func TestFundPsbtCoinSelect(t *testing.T) {
 		})
 	}
 }
+
+// TestEstimateFeeRates tests that fee rates are estimated for all requested
+// conf targets and that the source of the estimates is reported.
+func TestEstimateFeeRates(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(2500)
+
+	rpcServer, _, err := New(&Config{
+		FeeEstimator: chainfee.NewFloorEstimator(
+			chainfee.NewStaticEstimator(feeRate, 0),
+			chainfee.FeePerKwFloor,
+		),
+	})
+	require.NoError(t, err)
+
+	// Invalid conf targets are rejected.
+	_, err = rpcServer.EstimateFeeRates(
+		context.Background(), &EstimateFeeRatesRequest{},
+	)
+	require.Error(t, err)
+
+	_, err = rpcServer.EstimateFeeRates(
+		context.Background(), &EstimateFeeRatesRequest{
+			ConfTargets: []uint32{6, 1},
+		},
+	)
+	require.Error(t, err)
+
+	resp, err := rpcServer.EstimateFeeRates(
+		context.Background(), &EstimateFeeRatesRequest{
+			ConfTargets: []uint32{6, 2, 144},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Estimates, 3)
+
+	for i, confTarget := range []uint32{6, 2, 144} {
+		estimate := resp.Estimates[i]
+		require.Equal(t, confTarget, estimate.ConfTarget)
+		require.EqualValues(t, feeRate, estimate.SatPerKw)
+		require.EqualValues(t, 10, estimate.SatPerVbyte)
+		require.Equal(
+			t, FeeEstimateSource_FEE_ESTIMATE_SOURCE_STATIC,
+			estimate.Source,
+		)
+		require.Zero(t, estimate.LastUpdateTimestamp)
+		require.Zero(t, estimate.StalenessSeconds)
+	}
+}
+
+// TestMaxFeeRate tests that explicitly requested fee rates above the configured
+// max fee rate are rejected.
+func TestMaxFeeRate(t *testing.T) {
+	t.Parallel()
+
+	const maxFeeRate = chainfee.SatPerKWeight(2500)
+
+	rpcServer, _, err := New(&Config{
+		MaxFeeRate: maxFeeRate,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = rpcServer.SendOutputs(ctx, &SendOutputsRequest{
+		SatPerKw: int64(maxFeeRate + 1),
+		Outputs: []*signrpc.TxOut{{
+			Value:    1000,
+			PkScript: []byte{txscript.OP_TRUE},
+		}},
+	})
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooHigh)
+
+	_, err = rpcServer.FundPsbt(ctx, &FundPsbtRequest{
+		Fees: &FundPsbtRequest_SatPerVbyte{
+			SatPerVbyte: uint64(maxFeeRate.FeePerVByte()) + 1,
+		},
+	})
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooHigh)
+
+	_, _, err = rpcServer.prepareSweepParams(&BumpFeeRequest{
+		SatPerVbyte: uint64(maxFeeRate.FeePerVByte()) + 1,
+	}, wire.OutPoint{}, 100)
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooHigh)
+}
+
+// TestMinFeeRate tests that explicitly requested fee rates below the configured
+// min fee rate are rejected.
+func TestMinFeeRate(t *testing.T) {
+	t.Parallel()
+
+	const minFeeRate = chainfee.SatPerKWeight(2500)
+
+	rpcServer, _, err := New(&Config{
+		MinFeeRate: minFeeRate,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = rpcServer.SendOutputs(ctx, &SendOutputsRequest{
+		SatPerKw: int64(minFeeRate - 1),
+		Outputs: []*signrpc.TxOut{{
+			Value:    1000,
+			PkScript: []byte{txscript.OP_TRUE},
+		}},
+	})
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooLow)
+
+	_, err = rpcServer.FundPsbt(ctx, &FundPsbtRequest{
+		Fees: &FundPsbtRequest_SatPerVbyte{
+			SatPerVbyte: uint64(minFeeRate.FeePerVByte()) - 1,
+		},
+	})
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooLow)
+
+	_, _, err = rpcServer.prepareSweepParams(&BumpFeeRequest{
+		SatPerVbyte: uint64(minFeeRate.FeePerVByte()) - 1,
+	}, wire.OutPoint{}, 100)
+	require.ErrorIs(t, err, lnrpc.ErrFeeRateTooLow)
+}
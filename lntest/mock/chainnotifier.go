@@ -11,6 +11,10 @@ type ChainNotifier struct {
 	SpendChan chan *chainntnfs.SpendDetail
 	EpochChan chan *chainntnfs.BlockEpoch
 	ConfChan  chan *chainntnfs.TxConfirmation
+
+	// NegConfChan is used as the NegativeConf channel of all returned
+	// confirmation events.
+	NegConfChan chan int32
 }
 
 // RegisterConfirmationsNtfn returns a ConfirmationEvent that contains a channel
@@ -20,8 +24,9 @@ func (c *ChainNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
 	opts ...chainntnfs.NotifierOption) (*chainntnfs.ConfirmationEvent, error) {
 
 	return &chainntnfs.ConfirmationEvent{
-		Confirmed: c.ConfChan,
-		Cancel:    func() {},
+		Confirmed:    c.ConfChan,
+		NegativeConf: c.NegConfChan,
+		Cancel:       func() {},
 	}, nil
 }
 
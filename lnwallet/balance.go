@@ -0,0 +1,73 @@
+package lnwallet
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// BalanceBreakdown categorizes all funds of the wallet, including funds that
+// are not spendable yet. The categories are disjoint, so they always add up to
+// the total balance.
+type BalanceBreakdown struct {
+	// Available is the confirmed balance that can be spent freely, which
+	// excludes the reserve held back for anchor channels.
+	Available btcutil.Amount
+
+	// Reserved is the part of the confirmed balance that is held back to
+	// fee bump the commitments of anchor channels.
+	Reserved btcutil.Amount
+
+	// Unconfirmed is the balance of unspent outputs without any
+	// confirmations.
+	Unconfirmed btcutil.Amount
+
+	// Locked is the balance of unspent outputs that are leased for other
+	// usage, such as pending funding transactions.
+	Locked btcutil.Amount
+
+	// Immature is the balance of outputs of closed channels that can't be
+	// swept into the wallet yet, for example because they are still
+	// encumbered by a CSV delay.
+	Immature btcutil.Amount
+}
+
+// NewBalanceBreakdown creates a balance breakdown from the unleased unspent
+// outputs of the wallet, the balance of the leased outputs, the reserve
+// required for anchor channels and the balance of immature outputs. The
+// reserve is taken from the confirmed balance and can't exceed it.
+func NewBalanceBreakdown(utxos []*Utxo, locked, requiredReserve,
+	immature btcutil.Amount) *BalanceBreakdown {
+
+	var confirmed, unconfirmed btcutil.Amount
+	for _, utxo := range utxos {
+		if utxo.Confirmations > 0 {
+			confirmed += utxo.Value
+		} else {
+			unconfirmed += utxo.Value
+		}
+	}
+
+	reserved := requiredReserve
+	if reserved > confirmed {
+		reserved = confirmed
+	}
+
+	return &BalanceBreakdown{
+		Available:   confirmed - reserved,
+		Reserved:    reserved,
+		Unconfirmed: unconfirmed,
+		Locked:      locked,
+		Immature:    immature,
+	}
+}
+
+// Confirmed returns the balance of all confirmed unleased outputs, including
+// the reserve for anchor channels.
+func (b *BalanceBreakdown) Confirmed() btcutil.Amount {
+	return b.Available + b.Reserved
+}
+
+// Total returns the sum of all balance categories.
+func (b *BalanceBreakdown) Total() btcutil.Amount {
+	return b.Available + b.Reserved + b.Unconfirmed + b.Locked +
+		b.Immature
+}
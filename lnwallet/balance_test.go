@@ -0,0 +1,69 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBalanceBreakdown checks that the balance categories are derived
+// correctly and always add up to the total balance.
+func TestBalanceBreakdown(t *testing.T) {
+	t.Parallel()
+
+	utxos := []*Utxo{
+		{Value: 50_000, Confirmations: 6},
+		{Value: 30_000, Confirmations: 1},
+		{Value: 20_000, Confirmations: 0},
+	}
+
+	testCases := []struct {
+		name     string
+		reserve  btcutil.Amount
+		expected BalanceBreakdown
+	}{{
+		name:    "no reserve",
+		reserve: 0,
+		expected: BalanceBreakdown{
+			Available:   80_000,
+			Unconfirmed: 20_000,
+			Locked:      10_000,
+			Immature:    5_000,
+		},
+	}, {
+		name:    "reserve within confirmed balance",
+		reserve: 10_000,
+		expected: BalanceBreakdown{
+			Available:   70_000,
+			Reserved:    10_000,
+			Unconfirmed: 20_000,
+			Locked:      10_000,
+			Immature:    5_000,
+		},
+	}, {
+		name:    "reserve exceeds confirmed balance",
+		reserve: 100_000,
+		expected: BalanceBreakdown{
+			Reserved:    80_000,
+			Unconfirmed: 20_000,
+			Locked:      10_000,
+			Immature:    5_000,
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			breakdown := NewBalanceBreakdown(
+				utxos, 10_000, tc.reserve, 5_000,
+			)
+			require.Equal(t, tc.expected, *breakdown)
+			require.EqualValues(t, 80_000, breakdown.Confirmed())
+			require.EqualValues(t, 115_000, breakdown.Total())
+		})
+	}
+}
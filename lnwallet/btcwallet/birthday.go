@@ -0,0 +1,158 @@
+package btcwallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// birthdayHintMargin is subtracted from the time of a birthday hint.
+	// The wallet locates its birthday block by searching for a block
+	// within two hours of its birthday, which could otherwise be a block
+	// after the hint.
+	birthdayHintMargin = 2 * time.Hour
+
+	// birthdayHeightPollInterval is the interval at which we check whether
+	// the chain backend synced up to the height of a birthday hint.
+	birthdayHeightPollInterval = time.Second
+)
+
+// errBirthdayWaitAborted is returned if lnd shuts down while we wait for the
+// chain backend to sync up to the height of a birthday hint.
+var errBirthdayWaitAborted = errors.New("shutting down while waiting for " +
+	"the chain backend to reach the wallet birthday height")
+
+// BirthdayHint is a user provided birthday of a wallet that is recovered. It
+// bounds the rescan for the transactions of the wallet. Either the block
+// height or the time is set.
+type BirthdayHint struct {
+	// Height is the block height of the birthday.
+	Height uint32
+
+	// Time is the time of the birthday.
+	Time time.Time
+}
+
+// String returns a human readable representation of the birthday hint.
+func (h *BirthdayHint) String() string {
+	if h.Height != 0 {
+		return fmt.Sprintf("height %d", h.Height)
+	}
+
+	return h.Time.String()
+}
+
+// applyBirthdayHint replaces the birthday of the wallet with the configured
+// birthday hint, so that the initial rescan of the recovery starts there. The
+// hint is stored as the birthday of the wallet, so it also applies to later
+// rescans. It's ignored once the wallet located its birthday block, as the
+// rescan already started from it then.
+func (b *BtcWallet) applyBirthdayHint() error {
+	hint := b.cfg.BirthdayHint
+
+	var located bool
+	err := walletdb.View(b.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+		_, _, err := b.wallet.Manager.BirthdayBlock(addrmgrNs)
+		switch {
+		case err == nil:
+			located = true
+
+		case !waddrmgr.IsError(err, waddrmgr.ErrBirthdayBlockNotSet):
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if located {
+		log.Infof("Ignoring wallet birthday hint (%v), the wallet "+
+			"already located its birthday block", hint)
+
+		return nil
+	}
+
+	birthday := hint.Time
+	if hint.Height != 0 {
+		birthday, err = b.birthdayHeightTime(hint.Height)
+		if err != nil {
+			return err
+		}
+	}
+	birthday = birthday.Add(-birthdayHintMargin)
+
+	walletBirthday := b.wallet.Manager.Birthday()
+	if birthday.After(walletBirthday) {
+		log.Warnf("Wallet birthday hint (%v) is after the birthday "+
+			"of the wallet (%v), transactions before the hint "+
+			"will NOT be found by the recovery. The hint is "+
+			"stored as the birthday of the wallet, so if funds "+
+			"are missing, delete wallet.db and restore the "+
+			"wallet from its seed again without the hint.", hint,
+			walletBirthday)
+	}
+
+	log.Infof("Starting wallet recovery rescan at birthday %v from "+
+		"hint (%v)", birthday, hint)
+
+	return walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		return b.wallet.Manager.SetBirthday(addrmgrNs, birthday)
+	})
+}
+
+// birthdayHeightTime returns the time of the block at the given birthday
+// height. The chain backend might still be syncing, so we wait until it
+// reaches the height, unless the quit channel of the config is closed. A
+// height after the tip of the synced chain is rejected.
+func (b *BtcWallet) birthdayHeightTime(height uint32) (time.Time, error) {
+	var logged bool
+	for {
+		_, bestHeight, err := b.chain.GetBestBlock()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if bestHeight >= int32(height) {
+			break
+		}
+
+		if b.chain.IsCurrent() {
+			return time.Time{}, fmt.Errorf("wallet birthday height "+
+				"%d is after the chain tip at height %d",
+				height, bestHeight)
+		}
+
+		if !logged {
+			log.Infof("Waiting for chain backend to sync up to "+
+				"wallet birthday height %d, currently at "+
+				"height %d", height, bestHeight)
+			logged = true
+		}
+
+		select {
+		case <-time.After(birthdayHeightPollInterval):
+		case <-b.cfg.Quit:
+			return time.Time{}, errBirthdayWaitAborted
+		}
+	}
+
+	hash, err := b.chain.GetBlockHash(int64(height))
+	if err != nil {
+		return time.Time{}, err
+	}
+	header, err := b.chain.GetBlockHeader(hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return header.Timestamp, nil
+}
@@ -0,0 +1,83 @@
+package btcwallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBirthdayHeightTime tests that the time of a birthday height is only
+// looked up once the chain backend reached it, and that waiting for it can be
+// aborted.
+func TestBirthdayHeightTime(t *testing.T) {
+	t.Parallel()
+
+	const birthdayHeight = 100
+
+	t.Run("height reached", func(t *testing.T) {
+		t.Parallel()
+
+		hash := chainhash.Hash{1}
+		timestamp := time.Unix(1_700_000_000, 0)
+
+		chain := &lnmock.MockChain{}
+		chain.On("GetBestBlock").Return(nil, int32(birthdayHeight), nil)
+		chain.On("GetBlockHash", int64(birthdayHeight)).Return(
+			&hash, nil,
+		)
+		chain.On("GetBlockHeader", &hash).Return(
+			&wire.BlockHeader{Timestamp: timestamp}, nil,
+		)
+
+		b := &BtcWallet{chain: chain, cfg: &Config{}}
+		birthday, err := b.birthdayHeightTime(birthdayHeight)
+		require.NoError(t, err)
+		require.Equal(t, timestamp, birthday)
+	})
+
+	t.Run("after synced tip", func(t *testing.T) {
+		t.Parallel()
+
+		chain := &lnmock.MockChain{}
+		chain.On("GetBestBlock").Return(nil, int32(birthdayHeight-1), nil)
+		chain.On("IsCurrent").Return(true)
+
+		b := &BtcWallet{chain: chain, cfg: &Config{}}
+		_, err := b.birthdayHeightTime(birthdayHeight)
+		require.ErrorContains(t, err, "after the chain tip")
+	})
+
+	t.Run("aborted while syncing", func(t *testing.T) {
+		t.Parallel()
+
+		chain := &lnmock.MockChain{}
+		chain.On("GetBestBlock").Return(nil, int32(birthdayHeight-1), nil)
+		chain.On("IsCurrent").Return(false)
+
+		quit := make(chan struct{})
+		b := &BtcWallet{chain: chain, cfg: &Config{Quit: quit}}
+
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := b.birthdayHeightTime(birthdayHeight)
+			errChan <- err
+		}()
+
+		close(quit)
+
+		select {
+		case err := <-errChan:
+			require.ErrorIs(t, err, errBirthdayWaitAborted)
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("waiting for birthday height was not aborted")
+		}
+
+		chain.AssertNotCalled(t, "GetBlockHash", mock.Anything)
+	})
+}
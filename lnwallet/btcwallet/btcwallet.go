@@ -23,6 +23,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/walletdb/migration"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/blockcache"
@@ -31,6 +32,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 )
 
 const (
@@ -191,6 +193,10 @@ type loaderCfg struct {
 	dbTimeout      time.Duration
 	useLocalDB     bool
 	externalDB     kvdb.Backend
+
+	// recoveryGapLimit is the minimum address look-ahead used when the
+	// wallet is recovered.
+	recoveryGapLimit uint32
 }
 
 // LoaderOption is a functional option to update the optional loader config.
@@ -215,6 +221,26 @@ func LoaderWithExternalWalletDB(db kvdb.Backend) LoaderOption {
 	}
 }
 
+// LoaderWithRecoveryGapLimit configures the wallet loader to use an address
+// look-ahead of at least the given gap limit when recovering the wallet.
+func LoaderWithRecoveryGapLimit(gapLimit uint32) LoaderOption {
+	return func(cfg *loaderCfg) {
+		cfg.recoveryGapLimit = gapLimit
+	}
+}
+
+// RecoveryWindow returns the address look-ahead to use for a wallet recovery
+// with the requested recovery window, raised to the given gap limit if it is
+// lower. A zero recovery window means no recovery was requested, so it is
+// returned unchanged.
+func RecoveryWindow(recoveryWindow, gapLimit uint32) uint32 {
+	if recoveryWindow == 0 || recoveryWindow >= gapLimit {
+		return recoveryWindow
+	}
+
+	return gapLimit
+}
+
 // NewWalletLoader constructs a wallet loader.
 func NewWalletLoader(chainParams *chaincfg.Params, recoveryWindow uint32,
 	opts ...LoaderOption) (*base.Loader, error) {
@@ -226,6 +252,8 @@ func NewWalletLoader(chainParams *chaincfg.Params, recoveryWindow uint32,
 		o(cfg)
 	}
 
+	recoveryWindow = RecoveryWindow(recoveryWindow, cfg.recoveryGapLimit)
+
 	if cfg.externalDB != nil && cfg.useLocalDB {
 		return nil, fmt.Errorf("wallet can either be in the local or " +
 			"an external db")
@@ -272,6 +300,45 @@ func externalWalletExists(db kvdb.Backend) (bool, error) {
 	return exists, err
 }
 
+// WalletNeedsMigration returns true if btcwallet will migrate the address or
+// transaction manager of the wallet stored in the given database once the
+// wallet is opened. False is returned if the database holds no wallet yet.
+func WalletNeedsMigration(db walletdb.DB) (bool, error) {
+	// The migration managers of btcwallet need a read-write bucket, so we
+	// look up the versions in a transaction that is always rolled back.
+	tx, err := db.BeginReadWriteTx()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	addrMgrBucket := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+	txMgrBucket := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+	if addrMgrBucket == nil || txMgrBucket == nil {
+		return false, nil
+	}
+
+	managers := []migration.Manager{
+		waddrmgr.NewMigrationManager(addrMgrBucket),
+		wtxmgr.NewMigrationManager(txMgrBucket),
+	}
+	for _, mgr := range managers {
+		version, err := mgr.CurrentVersion(nil)
+		if err != nil {
+			return false, err
+		}
+
+		versions := mgr.Versions()
+		if version < versions[len(versions)-1].Number {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // onWalletCreated is executed when btcwallet creates the wallet the first time.
 func onWalletCreated(tx kvdb.RwTx) error {
 	metaBucket, err := tx.CreateTopLevelBucket([]byte(walletMetaBucket))
@@ -440,6 +507,15 @@ func (b *BtcWallet) Start() error {
 		return err
 	}
 
+	// If the wallet is recovered and the user knows when it was first
+	// used, the rescan can start there instead of at its birthday.
+	if b.cfg.BirthdayHint != nil && b.cfg.RecoveryWindow > 0 {
+		if err := b.applyBirthdayHint(); err != nil {
+			return fmt.Errorf("unable to apply wallet birthday "+
+				"hint: %w", err)
+		}
+	}
+
 	// Start the underlying btcwallet core.
 	b.wallet.Start()
 
@@ -720,14 +796,28 @@ func newAccountNotFoundError(name string) error {
 // RequiredReserve returns the minimum amount of satoshis that should be
 // kept in the wallet in order to fee bump anchor channels if necessary.
 // The value scales with the number of public anchor channels but is
-// capped at a maximum.
+// capped at a maximum. Unset values in the config fall back to the defaults.
 func (b *BtcWallet) RequiredReserve(
 	numAnchorChans uint32) btcutil.Amount {
 
-	anchorChanReservedValue := lnwallet.AnchorChanReservedValue
+	if b.cfg.NoAnchorChanReserve {
+		return 0
+	}
+
+	anchorChanReservedValue := b.cfg.AnchorChanReservedValue
+	if anchorChanReservedValue == 0 {
+		anchorChanReservedValue = lnwallet.AnchorChanReservedValue
+	}
+
+	maxReserved := b.cfg.MaxAnchorChanReservedValue
+	if maxReserved == 0 {
+		maxReserved = lnwallet.MaxAnchorChanReservedFactor *
+			anchorChanReservedValue
+	}
+
 	reserved := btcutil.Amount(numAnchorChans) * anchorChanReservedValue
-	if reserved > lnwallet.MaxAnchorChanReservedValue {
-		reserved = lnwallet.MaxAnchorChanReservedValue
+	if reserved > maxReserved {
+		reserved = maxReserved
 	}
 
 	return reserved
@@ -995,10 +1085,50 @@ func (b *BtcWallet) SendOutputs(outputs []*wire.TxOut,
 		return nil, lnwallet.ErrInvalidMinconf
 	}
 
-	return b.wallet.SendOutputs(
-		outputs, nil, defaultAccount, minConfs, feeSatPerKB,
-		strategy, label,
+	minConfs, allowUtxo, err := b.spendUnconfirmedFilter(minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy = targetCoinSelection(strategy, outputs, feeRate, nil)
+
+	if allowUtxo == nil {
+		return b.wallet.SendOutputs(
+			outputs, nil, defaultAccount, minConfs, feeSatPerKB,
+			strategy, label,
+		)
+	}
+
+	// The base wallet's SendOutputs doesn't allow us to filter the coins
+	// it selects, so we create and publish the transaction ourselves in
+	// the same way.
+	for _, output := range outputs {
+		err := txrules.CheckOutput(output, txrules.DefaultRelayFeePerKb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	authoredTx, err := b.wallet.CreateSimpleTx(
+		nil, defaultAccount, outputs, minConfs, feeSatPerKB, strategy,
+		false, base.WithUtxoFilter(allowUtxo),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// A watch-only wallet can't sign the transaction, so the caller needs
+	// to do that.
+	if b.wallet.Manager.WatchOnly() {
+		return authoredTx.Tx, base.ErrTxUnsigned
+	}
+
+	err = b.wallet.PublishTransaction(authoredTx.Tx, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return authoredTx.Tx, nil
 }
 
 // CreateSimpleTx creates a Bitcoin transaction paying to the specified
@@ -1047,12 +1177,113 @@ func (b *BtcWallet) CreateSimpleTx(outputs []*wire.TxOut,
 		}
 	}
 
+	minConfs, allowUtxo, err := b.spendUnconfirmedFilter(minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []base.TxCreateOption
+	if allowUtxo != nil {
+		opts = append(opts, base.WithUtxoFilter(allowUtxo))
+	}
+
+	strategy = targetCoinSelection(strategy, outputs, feeRate, nil)
+
 	return b.wallet.CreateSimpleTx(
 		nil, defaultAccount, outputs, minConfs, feeSatPerKB,
-		strategy, dryRun,
+		strategy, dryRun, opts...,
 	)
 }
 
+// targetCoinSelection prepares the given coin selection strategy for a
+// transaction paying to the given outputs. The base wallet only lets a
+// strategy arrange the coins without telling it the amount to fund, so the
+// branch and bound strategy needs to know the amount upfront to search for a
+// set of coins that doesn't require a change output. All other strategies are
+// returned unchanged.
+func targetCoinSelection(strategy base.CoinSelectionStrategy,
+	outputs []*wire.TxOut, feeRate chainfee.SatPerKWeight,
+	changeScope *waddrmgr.KeyScope) base.CoinSelectionStrategy {
+
+	if _, ok := strategy.(*chanfunding.BranchAndBoundCoinSelector); !ok {
+		return strategy
+	}
+
+	changeScriptSize := input.P2TRSize
+	if changeScope != nil && (*changeScope == waddrmgr.KeyScopeBIP0084 ||
+		*changeScope == waddrmgr.KeyScopeBIP0049Plus) {
+
+		changeScriptSize = input.P2WPKHSize
+	}
+
+	// The base wallet only accepts the selected coins if they can pay for
+	// a change output, even if it ends up dropping it because the change
+	// would be dust. So the target includes the fee of the change output,
+	// and the coins may exceed it by less than the dust limit.
+	var (
+		weightEstimate input.TxWeightEstimator
+		outputAmt      btcutil.Amount
+	)
+	for _, output := range outputs {
+		weightEstimate.AddOutput(output.PkScript)
+		outputAmt += btcutil.Amount(output.Value)
+	}
+	weightEstimate.AddOutput(make([]byte, changeScriptSize))
+
+	target := outputAmt + feeRate.FeeForWeight(weightEstimate.Weight())
+	dustLimit := lnwallet.DustLimitForSize(changeScriptSize)
+
+	return chanfunding.NewTargetedBranchAndBound(target, dustLimit-1)
+}
+
+// spendUnconfirmedFilter enforces the configured spend unconfirmed policy for
+// the coin selection of a transaction that requires the given number of
+// confirmations. It returns the number of confirmations to require instead
+// and a filter for the coins that may be selected, which is nil if all coins
+// with enough confirmations may be used.
+func (b *BtcWallet) spendUnconfirmedFilter(minConfs int32) (int32,
+	func(wtxmgr.Credit) bool, error) {
+
+	// The policy only matters if unconfirmed outputs may be spent at all.
+	if minConfs > 0 {
+		return minConfs, nil, nil
+	}
+
+	switch b.cfg.SpendUnconfirmed {
+	case lnwallet.SpendUnconfirmedAny:
+		return minConfs, nil, nil
+
+	case lnwallet.SpendUnconfirmedNever:
+		return 1, nil, nil
+	}
+
+	// We determine the spendable unconfirmed outputs up front, as looking
+	// up their transactions isn't possible while the base wallet selects
+	// coins. Outputs received after this point are never spendable, which
+	// errs on the safe side.
+	unconfirmed, err := b.ListUnspentWitness(0, 0, "")
+	if err != nil {
+		return 0, nil, err
+	}
+	spendable, err := b.cfg.SpendUnconfirmed.SpendableUnconfirmed(
+		unconfirmed, b.GetTransactionDetails,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return minConfs, func(credit wtxmgr.Credit) bool {
+		// Confirmed outputs have a block height assigned.
+		if credit.Height != -1 {
+			return true
+		}
+
+		_, ok := spendable[credit.OutPoint]
+
+		return ok
+	}, nil
+}
+
 // LeaseOutput locks an output to the given ID, preventing it from being
 // available for any future coin selection attempts. The absolute time of the
 // lock's expiration is returned. The expiration of the lock can be extended by
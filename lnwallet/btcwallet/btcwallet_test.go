@@ -222,3 +222,51 @@ func TestCheckMempoolAcceptance(t *testing.T) {
 	err = wallet.CheckMempoolAcceptance(tx)
 	rt.NoError(err)
 }
+
+// TestRequiredReserve checks that the required anchor reserve scales with the
+// number of anchor channels and is capped by the configured maximum.
+func TestRequiredReserve(t *testing.T) {
+	t.Parallel()
+
+	w := &BtcWallet{
+		cfg: &Config{
+			AnchorChanReservedValue:    20_000,
+			MaxAnchorChanReservedValue: 50_000,
+		},
+	}
+
+	require.Zero(t, w.RequiredReserve(0))
+	require.EqualValues(t, 20_000, w.RequiredReserve(1))
+	require.EqualValues(t, 40_000, w.RequiredReserve(2))
+	require.EqualValues(t, 50_000, w.RequiredReserve(3))
+	require.EqualValues(t, 50_000, w.RequiredReserve(100))
+
+	// A zero config falls back to the default reserve values.
+	w.cfg = &Config{}
+	require.Equal(
+		t, lnwallet.AnchorChanReservedValue, w.RequiredReserve(1),
+	)
+	require.Equal(
+		t, lnwallet.MaxAnchorChanReservedValue,
+		w.RequiredReserve(1000),
+	)
+
+	// Without a maximum, it is derived from the per-channel value.
+	w.cfg = &Config{AnchorChanReservedValue: 20_000}
+	require.EqualValues(t, 200_000, w.RequiredReserve(1000))
+
+	// A disabled reserve holds back nothing.
+	w.cfg = &Config{NoAnchorChanReserve: true}
+	require.Zero(t, w.RequiredReserve(100))
+}
+
+// TestRecoveryWindow checks that the recovery window is raised to the
+// recovery gap limit only if a recovery was requested.
+func TestRecoveryWindow(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, RecoveryWindow(0, 5000))
+	require.EqualValues(t, 5000, RecoveryWindow(2500, 5000))
+	require.EqualValues(t, 10_000, RecoveryWindow(10_000, 5000))
+	require.EqualValues(t, 2500, RecoveryWindow(2500, 0))
+}
@@ -4,10 +4,12 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/lnwallet"
 )
 
 var (
@@ -47,6 +49,14 @@ type Config struct {
 	// default BIP44 derivation paths.
 	RecoveryWindow uint32
 
+	// BirthdayHint, if set, replaces the birthday of a wallet that is
+	// recovered, which bounds the initial rescan of the recovery.
+	BirthdayHint *BirthdayHint
+
+	// Quit, if set, is closed when lnd shuts down. It aborts waiting for
+	// the chain backend to sync up to the height of the birthday hint.
+	Quit <-chan struct{}
+
 	// ChainSource is the primary chain interface. This is used to operate
 	// the wallet and do things such as rescanning, sending transactions,
 	// notifications for received funds, etc.
@@ -72,6 +82,11 @@ type Config struct {
 	// coins when funding a transaction.
 	CoinSelectionStrategy wallet.CoinSelectionStrategy
 
+	// SpendUnconfirmed is the policy that determines which unconfirmed
+	// outputs coin selection may use if a transaction allows spending
+	// unconfirmed outputs.
+	SpendUnconfirmed lnwallet.SpendUnconfirmedPolicy
+
 	// WatchOnly indicates that the wallet was initialized with public key
 	// material only and does not contain any private keys.
 	WatchOnly bool
@@ -82,6 +97,22 @@ type Config struct {
 	// wallet exists and a watch-only one is created directly, or, if the
 	// wallet was previously converted to a watch-only already.
 	MigrateWatchOnly bool
+
+	// AnchorChanReservedValue is the amount we keep around in the wallet
+	// for each public anchor channel, in case we have to fee bump its
+	// commitment on force close. If zero, the default of
+	// lnwallet.AnchorChanReservedValue is used.
+	AnchorChanReservedValue btcutil.Amount
+
+	// MaxAnchorChanReservedValue is the maximum total value we reserve for
+	// anchor channel fee bumping, regardless of the number of channels.
+	// If zero, lnwallet.MaxAnchorChanReservedFactor times the per-channel
+	// value is used.
+	MaxAnchorChanReservedValue btcutil.Amount
+
+	// NoAnchorChanReserve disables holding back any funds for anchor
+	// channel fee bumping.
+	NoAnchorChanReserve bool
 }
 
 // NetworkDir returns the directory name of a network directory to hold wallet
@@ -131,6 +131,27 @@ func (b *BtcWallet) FundPsbt(packet *psbt.Packet, minConfs int32,
 		accountNum = account
 	}
 
+	// If the wallet selects the coins, it needs to respect the spend
+	// unconfirmed policy. Inputs specified by the caller are used as is.
+	if len(packet.UnsignedTx.TxIn) == 0 {
+		var (
+			unconfirmedFilter func(wtxmgr.Credit) bool
+			err               error
+		)
+		minConfs, unconfirmedFilter, err = b.spendUnconfirmedFilter(
+			minConfs,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		allowUtxo = combineUtxoFilters(allowUtxo, unconfirmedFilter)
+	}
+
+	strategy = targetCoinSelection(
+		strategy, packet.UnsignedTx.TxOut, feeRate, changeScope,
+	)
+
 	var opts []wallet.TxCreateOption
 	if changeScope != nil {
 		opts = append(opts, wallet.WithCustomChangeScope(changeScope))
@@ -147,6 +168,24 @@ func (b *BtcWallet) FundPsbt(packet *psbt.Packet, minConfs int32,
 	)
 }
 
+// combineUtxoFilters returns a filter that only allows the coins allowed by
+// both of the given filters. A nil filter allows all coins.
+func combineUtxoFilters(a,
+	b func(wtxmgr.Credit) bool) func(wtxmgr.Credit) bool {
+
+	switch {
+	case a == nil:
+		return b
+
+	case b == nil:
+		return a
+	}
+
+	return func(credit wtxmgr.Credit) bool {
+		return a(credit) && b(credit)
+	}
+}
+
 // SignPsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all unsigned inputs that have all required fields
 // (UTXO information, BIP32 derivation information, witness or sig scripts) set.
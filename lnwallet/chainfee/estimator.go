@@ -54,6 +54,11 @@ var (
 
 	// errEmptyCache is used when the fee rate cache is empty.
 	errEmptyCache = errors.New("fee rate cache is empty")
+
+	// ErrStaleFeeEstimates is returned when the fee estimates of a web
+	// API estimator haven't been updated for longer than allowed, which
+	// usually means that the fee source is unreachable.
+	ErrStaleFeeEstimates = errors.New("fee estimates are stale")
 )
 
 // Estimator provides the ability to estimate on-chain transaction fees for
@@ -636,10 +641,16 @@ type WebAPIFeeSource interface {
 // SparseConfFeeSource is an implementation of the WebAPIFeeSource that utilizes
 // a user-specified fee estimation API for Bitcoin. It expects the response
 // to be in the JSON format: `fee_by_block_target: { ... }` where the value maps
-// block targets to fee estimates (in sat per kilovbyte).
+// block targets to fee estimates (in sat per kilovbyte), unless a different
+// schema is specified.
 type SparseConfFeeSource struct {
 	// URL is the fee estimation API specified by the user.
 	URL string
+
+	// Schema optionally describes how to map an alternative JSON response
+	// layout to block targets and fee estimates. If nil, the default
+	// `fee_by_block_target` layout is expected.
+	Schema *FeeSchema
 }
 
 // parseResponse attempts to parse the body of the response generated by the
@@ -648,6 +659,10 @@ type SparseConfFeeSource struct {
 func (s SparseConfFeeSource) parseResponse(r io.Reader) (
 	map[uint32]uint32, error) {
 
+	if s.Schema != nil {
+		return s.Schema.Apply(r)
+	}
+
 	type jsonResp struct {
 		FeeByBlockTarget map[uint32]uint32 `json:"fee_by_block_target"`
 	}
@@ -727,6 +742,15 @@ type WebAPIEstimator struct {
 	feesMtx          sync.Mutex
 	feeByBlockTarget map[uint32]uint32
 
+	// lastFeeUpdate is the time the fee estimates were last fetched
+	// successfully from the API. It is guarded by feesMtx.
+	lastFeeUpdate time.Time
+
+	// cacheTTL is the maximum age of the cached fee estimates. Once they
+	// are older, no fee rates are returned until the API can be reached
+	// again. A value of zero means the estimates never expire.
+	cacheTTL time.Duration
+
 	// noCache determines whether the web estimator should cache fee
 	// estimates.
 	noCache bool
@@ -745,9 +769,11 @@ type WebAPIEstimator struct {
 
 // NewWebAPIEstimator creates a new WebAPIEstimator from a given URL and a
 // fallback default fee. The fees are updated whenever a new block is mined.
+// If cacheTTL is non-zero, fee estimates older than that are considered stale
+// and aren't used anymore.
 func NewWebAPIEstimator(api WebAPIFeeSource, noCache bool,
-	minFeeUpdateTimeout time.Duration,
-	maxFeeUpdateTimeout time.Duration) (*WebAPIEstimator, error) {
+	minFeeUpdateTimeout time.Duration, maxFeeUpdateTimeout time.Duration,
+	cacheTTL time.Duration) (*WebAPIEstimator, error) {
 
 	if minFeeUpdateTimeout == 0 || maxFeeUpdateTimeout == 0 {
 		return nil, fmt.Errorf("minFeeUpdateTimeout and " +
@@ -760,6 +786,12 @@ func NewWebAPIEstimator(api WebAPIFeeSource, noCache bool,
 			minFeeUpdateTimeout, maxFeeUpdateTimeout)
 	}
 
+	if cacheTTL != 0 && cacheTTL <= maxFeeUpdateTimeout {
+		return nil, fmt.Errorf("cacheTTL of %v must be greater than "+
+			"maxFeeUpdateTimeout of %v", cacheTTL,
+			maxFeeUpdateTimeout)
+	}
+
 	return &WebAPIEstimator{
 		apiSource:           api,
 		feeByBlockTarget:    make(map[uint32]uint32),
@@ -767,6 +799,7 @@ func NewWebAPIEstimator(api WebAPIFeeSource, noCache bool,
 		quit:                make(chan struct{}),
 		minFeeUpdateTimeout: minFeeUpdateTimeout,
 		maxFeeUpdateTimeout: maxFeeUpdateTimeout,
+		cacheTTL:            cacheTTL,
 	}, nil
 }
 
@@ -789,6 +822,14 @@ func (w *WebAPIEstimator) EstimateFeePerKW(numBlocks uint32) (
 		w.updateFeeEstimates()
 	}
 
+	// Rather than silently using outdated fee rates if the API couldn't be
+	// reached for a while, we surface the staleness to the caller.
+	if w.cacheTTL != 0 {
+		if err := w.CheckStaleness(w.cacheTTL); err != nil {
+			return 0, err
+		}
+	}
+
 	feePerKb, err := w.getCachedFee(numBlocks)
 
 	// If the estimator returns an error, a zero value fee rate will be
@@ -880,6 +921,28 @@ func (w *WebAPIEstimator) randomFeeUpdateTimeout() time.Duration {
 	).Round(time.Second)
 }
 
+// CheckStaleness returns an error wrapping ErrStaleFeeEstimates if the fee
+// estimates haven't been fetched successfully within the given duration.
+func (w *WebAPIEstimator) CheckStaleness(maxAge time.Duration) error {
+	w.feesMtx.Lock()
+	lastFeeUpdate := w.lastFeeUpdate
+	w.feesMtx.Unlock()
+
+	if lastFeeUpdate.IsZero() {
+		return fmt.Errorf("%w: no fee estimates fetched yet",
+			ErrStaleFeeEstimates)
+	}
+
+	age := time.Since(lastFeeUpdate)
+	if age > maxAge {
+		return fmt.Errorf("%w: last update was %v ago, maximum "+
+			"allowed age is %v", ErrStaleFeeEstimates,
+			age.Round(time.Second), maxAge)
+	}
+
+	return nil
+}
+
 // getCachedFee takes a conf target and returns the cached fee rate. When the
 // fee rate cannot be found, it will search the cache by decrementing the conf
 // target until a fee rate is found. If still not found, it will return the fee
@@ -964,6 +1027,7 @@ func (w *WebAPIEstimator) updateFeeEstimates() {
 
 	w.feesMtx.Lock()
 	w.feeByBlockTarget = feesByBlockTarget
+	w.lastFeeUpdate = time.Now()
 	w.feesMtx.Unlock()
 }
 
@@ -984,3 +1048,115 @@ func (w *WebAPIEstimator) feeUpdateManager() {
 // A compile-time assertion to ensure that WebAPIEstimator implements the
 // Estimator interface.
 var _ Estimator = (*WebAPIEstimator)(nil)
+
+// FloorEstimator is an implementation of the Estimator interface that wraps
+// another estimator and enforces a hard floor on all fee rates it returns,
+// independent of what the wrapped estimator reports. This makes sure that no
+// transaction is created with a fee rate below a relay fee that is known to be
+// required by the network.
+type FloorEstimator struct {
+	// Estimator is the wrapped fee estimator.
+	Estimator
+
+	// floor is the minimum fee rate returned by this estimator.
+	floor SatPerKWeight
+}
+
+// NewFloorEstimator returns a new fee estimator that wraps the given one and
+// never returns fee rates below the given floor.
+func NewFloorEstimator(estimator Estimator,
+	floor SatPerKWeight) *FloorEstimator {
+
+	return &FloorEstimator{
+		Estimator: estimator,
+		floor:     floor,
+	}
+}
+
+// EstimateFeePerKW returns the fee rate estimated by the wrapped estimator,
+// raised to the floor if it is lower.
+//
+// NOTE: This method is part of the Estimator interface.
+func (e *FloorEstimator) EstimateFeePerKW(
+	numBlocks uint32) (SatPerKWeight, error) {
+
+	feeRate, err := e.Estimator.EstimateFeePerKW(numBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	if feeRate < e.floor {
+		log.Debugf("Raising estimated fee rate of %v for conf target "+
+			"%v to floor of %v", feeRate, numBlocks, e.floor)
+
+		return e.floor, nil
+	}
+
+	return feeRate, nil
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// relayed, which is never below the floor.
+//
+// NOTE: This method is part of the Estimator interface.
+func (e *FloorEstimator) RelayFeePerKW() SatPerKWeight {
+	relayFee := e.Estimator.RelayFeePerKW()
+	if relayFee < e.floor {
+		return e.floor
+	}
+
+	return relayFee
+}
+
+// A compile-time assertion to ensure that FloorEstimator implements the
+// Estimator interface.
+var _ Estimator = (*FloorEstimator)(nil)
+
+// CeilingEstimator is an implementation of the Estimator interface that wraps
+// another estimator and caps all fee rate estimates at a ceiling, independent
+// of what the wrapped estimator reports. This protects against spending
+// excessive fees when the fee estimates spike or are misreported.
+type CeilingEstimator struct {
+	// Estimator is the wrapped fee estimator.
+	Estimator
+
+	// ceiling is the maximum fee rate estimated by this estimator.
+	ceiling SatPerKWeight
+}
+
+// NewCeilingEstimator returns a new fee estimator that wraps the given one and
+// never returns fee rate estimates above the given ceiling.
+func NewCeilingEstimator(estimator Estimator,
+	ceiling SatPerKWeight) *CeilingEstimator {
+
+	return &CeilingEstimator{
+		Estimator: estimator,
+		ceiling:   ceiling,
+	}
+}
+
+// EstimateFeePerKW returns the fee rate estimated by the wrapped estimator,
+// lowered to the ceiling if it is higher.
+//
+// NOTE: This method is part of the Estimator interface.
+func (e *CeilingEstimator) EstimateFeePerKW(
+	numBlocks uint32) (SatPerKWeight, error) {
+
+	feeRate, err := e.Estimator.EstimateFeePerKW(numBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	if feeRate > e.ceiling {
+		log.Warnf("Lowering estimated fee rate of %v for conf target "+
+			"%v to ceiling of %v", feeRate, numBlocks, e.ceiling)
+
+		return e.ceiling, nil
+	}
+
+	return feeRate, nil
+}
+
+// A compile-time assertion to ensure that CeilingEstimator implements the
+// Estimator interface.
+var _ Estimator = (*CeilingEstimator)(nil)
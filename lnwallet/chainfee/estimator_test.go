@@ -204,7 +204,7 @@ func TestWebAPIFeeEstimator(t *testing.T) {
 	feeSource.On("GetFeeMap").Return(feeRateResp, nil)
 
 	estimator, _ := NewWebAPIEstimator(
-		feeSource, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		feeSource, false, minFeeUpdateTimeout, maxFeeUpdateTimeout, 0,
 	)
 
 	// Test that requesting a fee when no fees have been cached won't fail.
@@ -260,7 +260,7 @@ func TestGetCachedFee(t *testing.T) {
 
 	// Create a dummy estimator without WebAPIFeeSource.
 	estimator, _ := NewWebAPIEstimator(
-		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout, 0,
 	)
 
 	// When the cache is empty, an error should be returned.
@@ -336,7 +336,7 @@ func TestRandomFeeUpdateTimeout(t *testing.T) {
 	)
 
 	estimator, _ := NewWebAPIEstimator(
-		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout, 0,
 	)
 
 	for i := 0; i < 1000; i++ {
@@ -356,8 +356,165 @@ func TestInvalidFeeUpdateTimeout(t *testing.T) {
 	)
 
 	_, err := NewWebAPIEstimator(
-		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout, 0,
 	)
 	require.Error(t, err, "NewWebAPIEstimator should return an error "+
 		"when minFeeUpdateTimeout > maxFeeUpdateTimeout")
 }
+
+// TestWebAPIEstimatorStaleness checks that the web API estimator surfaces
+// stale fee estimates instead of silently using them.
+func TestWebAPIEstimatorStaleness(t *testing.T) {
+	t.Parallel()
+
+	var (
+		minFeeUpdateTimeout = 1 * time.Minute
+		maxFeeUpdateTimeout = 2 * time.Minute
+		cacheTTL            = 10 * time.Minute
+	)
+
+	// The cache TTL must be greater than the maximum update timeout.
+	_, err := NewWebAPIEstimator(
+		nil, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		maxFeeUpdateTimeout,
+	)
+	require.Error(t, err)
+
+	feeSource := &mockFeeSource{}
+	feeSource.On("GetFeeMap").Return(map[uint32]uint32{2: 2000}, nil)
+
+	estimator, err := NewWebAPIEstimator(
+		feeSource, false, minFeeUpdateTimeout, maxFeeUpdateTimeout,
+		cacheTTL,
+	)
+	require.NoError(t, err)
+
+	// Without any fetched estimates, the estimator reports staleness.
+	_, err = estimator.EstimateFeePerKW(2)
+	require.ErrorIs(t, err, ErrStaleFeeEstimates)
+	require.ErrorIs(t, estimator.CheckStaleness(cacheTTL),
+		ErrStaleFeeEstimates)
+
+	// Once the estimates are fetched, they can be used.
+	estimator.updateFeeEstimates()
+	feeRate, err := estimator.EstimateFeePerKW(2)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKVByte(2000).FeePerKWeight(), feeRate)
+	require.NoError(t, estimator.CheckStaleness(cacheTTL))
+
+	// If the last successful update is older than the TTL, the estimates
+	// aren't used anymore.
+	estimator.feesMtx.Lock()
+	estimator.lastFeeUpdate = time.Now().Add(-cacheTTL - time.Minute)
+	estimator.feesMtx.Unlock()
+
+	_, err = estimator.EstimateFeePerKW(2)
+	require.ErrorIs(t, err, ErrStaleFeeEstimates)
+	require.ErrorIs(t, estimator.CheckStaleness(cacheTTL),
+		ErrStaleFeeEstimates)
+
+	// A larger threshold, as used by the health check, still accepts
+	// them.
+	require.NoError(t, estimator.CheckStaleness(time.Hour))
+}
+
+// TestFloorEstimator checks that the floor estimator never returns fee rates
+// below its floor, while passing through higher rates unchanged.
+func TestFloorEstimator(t *testing.T) {
+	t.Parallel()
+
+	const floor = SatPerKWeight(1000)
+
+	testCases := []struct {
+		name        string
+		feeRate     SatPerKWeight
+		relayFee    SatPerKWeight
+		expFeeRate  SatPerKWeight
+		expRelayFee SatPerKWeight
+	}{{
+		name:        "below floor",
+		feeRate:     FeePerKwFloor,
+		relayFee:    FeePerKwFloor,
+		expFeeRate:  floor,
+		expRelayFee: floor,
+	}, {
+		name:        "above floor",
+		feeRate:     5000,
+		relayFee:    2000,
+		expFeeRate:  5000,
+		expRelayFee: 2000,
+	}, {
+		name:        "relay fee below floor",
+		feeRate:     5000,
+		relayFee:    FeePerKwFloor,
+		expFeeRate:  5000,
+		expRelayFee: floor,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			estimator := NewFloorEstimator(
+				NewStaticEstimator(tc.feeRate, tc.relayFee),
+				floor,
+			)
+
+			feeRate, err := estimator.EstimateFeePerKW(6)
+			require.NoError(t, err)
+			require.Equal(t, tc.expFeeRate, feeRate)
+			require.Equal(
+				t, tc.expRelayFee, estimator.RelayFeePerKW(),
+			)
+		})
+	}
+}
+
+// TestCeilingEstimator checks that the ceiling estimator never returns fee
+// rate estimates above its ceiling, while passing through lower rates and the
+// relay fee unchanged.
+func TestCeilingEstimator(t *testing.T) {
+	t.Parallel()
+
+	const ceiling = SatPerKWeight(10_000)
+
+	testCases := []struct {
+		name       string
+		feeRate    SatPerKWeight
+		expFeeRate SatPerKWeight
+	}{{
+		name:       "above ceiling",
+		feeRate:    50_000,
+		expFeeRate: ceiling,
+	}, {
+		name:       "at ceiling",
+		feeRate:    ceiling,
+		expFeeRate: ceiling,
+	}, {
+		name:       "below ceiling",
+		feeRate:    5000,
+		expFeeRate: 5000,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			estimator := NewCeilingEstimator(
+				NewStaticEstimator(tc.feeRate, FeePerKwFloor),
+				ceiling,
+			)
+
+			feeRate, err := estimator.EstimateFeePerKW(6)
+			require.NoError(t, err)
+			require.Equal(t, tc.expFeeRate, feeRate)
+			require.Equal(
+				t, FeePerKwFloor, estimator.RelayFeePerKW(),
+			)
+		})
+	}
+}
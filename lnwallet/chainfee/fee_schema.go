@@ -0,0 +1,244 @@
+package chainfee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// errEmptyFeeMap is returned if applying a fee schema to a response
+	// doesn't yield a single fee estimate.
+	errEmptyFeeMap = errors.New("fee schema produced no fee estimates")
+
+	// pathSegmentRegex matches a single segment of a fee schema path.
+	pathSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// FeeSchema describes how the JSON response of a fee estimation API is mapped
+// to a set of confirmation targets and their fee rates in sat/kvB. A schema is
+// written in a small jq-like syntax and has one of the following forms:
+//
+//   - A path that selects an object whose keys are the confirmation targets
+//     and whose values are the fee rates, for example `.fee_by_block_target`.
+//     The path `.` selects the top-level object.
+//   - An object that maps confirmation targets to the paths of individual fee
+//     rates, for example `{1: .fastestFee, 6: .hourFee}`.
+//
+// Both forms can be followed by `* <factor>` to scale all fee rates to
+// sat/kvB, for example `. * 1000` for an API that returns sat/vB.
+type FeeSchema struct {
+	// path is the path to the object that maps confirmation targets to
+	// fee rates. It is only used if targets is nil.
+	path []string
+
+	// targets maps confirmation targets to the paths of their fee rates.
+	targets map[uint32][]string
+
+	// factor is multiplied with every fee rate.
+	factor float64
+}
+
+// ParseFeeSchema parses the given fee schema expression.
+func ParseFeeSchema(schema string) (*FeeSchema, error) {
+	expr := strings.TrimSpace(schema)
+	s := &FeeSchema{
+		factor: 1,
+	}
+
+	// An optional scaling factor can be appended to the expression.
+	if idx := strings.LastIndex(expr, "*"); idx != -1 {
+		factorStr := strings.TrimSpace(expr[idx+1:])
+		factor, err := strconv.ParseFloat(factorStr, 64)
+		if err != nil || factor <= 0 || math.IsInf(factor, 0) {
+			return nil, fmt.Errorf("invalid fee schema factor: %q",
+				factorStr)
+		}
+
+		s.factor = factor
+		expr = strings.TrimSpace(expr[:idx])
+	}
+
+	// If the expression isn't an object, it's the path to the object
+	// holding the fee rates.
+	if !strings.HasPrefix(expr, "{") {
+		path, err := parseSchemaPath(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		s.path = path
+
+		return s, nil
+	}
+
+	if !strings.HasSuffix(expr, "}") {
+		return nil, fmt.Errorf("invalid fee schema object: %q", expr)
+	}
+
+	s.targets = make(map[uint32][]string)
+	entries := strings.Split(expr[1:len(expr)-1], ",")
+	for _, entry := range entries {
+		target, pathStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid fee schema entry %q, "+
+				"expected <conf_target>: <path>", entry)
+		}
+
+		confTarget, err := strconv.ParseUint(
+			strings.TrimSpace(target), 10, 32,
+		)
+		if err != nil || confTarget == 0 {
+			return nil, fmt.Errorf("invalid fee schema conf "+
+				"target: %q", target)
+		}
+
+		path, err := parseSchemaPath(pathStr)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := s.targets[uint32(confTarget)]; ok {
+			return nil, fmt.Errorf("duplicate fee schema conf "+
+				"target: %v", confTarget)
+		}
+		s.targets[uint32(confTarget)] = path
+	}
+
+	return s, nil
+}
+
+// parseSchemaPath parses a jq-like path such as `.data.fees` into its
+// segments.
+func parseSchemaPath(pathStr string) ([]string, error) {
+	pathStr = strings.TrimSpace(pathStr)
+	if !strings.HasPrefix(pathStr, ".") {
+		return nil, fmt.Errorf("invalid fee schema path %q, must "+
+			"start with '.'", pathStr)
+	}
+
+	// The path `.` selects the top-level value.
+	if pathStr == "." {
+		return nil, nil
+	}
+
+	segments := strings.Split(pathStr[1:], ".")
+	for _, segment := range segments {
+		if !pathSegmentRegex.MatchString(segment) {
+			return nil, fmt.Errorf("invalid fee schema path %q",
+				pathStr)
+		}
+	}
+
+	return segments, nil
+}
+
+// Apply parses the given JSON response according to the schema and returns a
+// map of confirmation targets to fee rates in sat/kvB. An error is returned if
+// the response doesn't contain the structure described by the schema.
+func (s *FeeSchema) Apply(r io.Reader) (map[uint32]uint32, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var resp interface{}
+	if err := decoder.Decode(&resp); err != nil {
+		return nil, err
+	}
+
+	fees := make(map[uint32]uint32)
+	if s.targets != nil {
+		for confTarget, path := range s.targets {
+			value, err := lookupSchemaPath(resp, path)
+			if err != nil {
+				return nil, err
+			}
+
+			fee, err := s.feeRate(value)
+			if err != nil {
+				return nil, fmt.Errorf("conf target %v: %w",
+					confTarget, err)
+			}
+			fees[confTarget] = fee
+		}
+
+		return fees, nil
+	}
+
+	value, err := lookupSchemaPath(resp, s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	feeObject, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fee schema path .%s is not an object",
+			strings.Join(s.path, "."))
+	}
+
+	for key, feeValue := range feeObject {
+		confTarget, err := strconv.ParseUint(key, 10, 32)
+		if err != nil || confTarget == 0 {
+			return nil, fmt.Errorf("invalid conf target %q in fee "+
+				"response", key)
+		}
+
+		fee, err := s.feeRate(feeValue)
+		if err != nil {
+			return nil, fmt.Errorf("conf target %v: %w",
+				confTarget, err)
+		}
+		fees[uint32(confTarget)] = fee
+	}
+
+	if len(fees) == 0 {
+		return nil, errEmptyFeeMap
+	}
+
+	return fees, nil
+}
+
+// feeRate converts a JSON value into a fee rate in sat/kvB by applying the
+// schema's factor.
+func (s *FeeSchema) feeRate(value interface{}) (uint32, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("fee rate %v is not a number", value)
+	}
+
+	fee, err := number.Float64()
+	if err != nil {
+		return 0, err
+	}
+
+	fee = math.Round(fee * s.factor)
+	if fee < 0 || fee > math.MaxUint32 {
+		return 0, fmt.Errorf("fee rate %v out of range", fee)
+	}
+
+	return uint32(fee), nil
+}
+
+// lookupSchemaPath returns the value at the given path within the decoded
+// JSON value.
+func lookupSchemaPath(value interface{}, path []string) (interface{}, error) {
+	for i, segment := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("fee schema path .%s is not an "+
+				"object", strings.Join(path[:i], "."))
+		}
+
+		value, ok = object[segment]
+		if !ok {
+			return nil, fmt.Errorf("fee schema path .%s not found "+
+				"in response", strings.Join(path[:i+1], "."))
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,134 @@
+package chainfee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeSchema checks that fee schemas are parsed and applied to different
+// fee API response layouts as expected.
+func TestFeeSchema(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		schema    string
+		response  string
+		parseErr  bool
+		applyErr  bool
+		expFeeMap map[uint32]uint32
+	}{{
+		name:     "default layout",
+		schema:   ".fee_by_block_target",
+		response: `{"fee_by_block_target": {"1": 12345, "6": 42}}`,
+		expFeeMap: map[uint32]uint32{
+			1: 12345,
+			6: 42,
+		},
+	}, {
+		name:     "top-level sat/vB map",
+		schema:   ". * 1000",
+		response: `{"1": 12.5, "144": 1.0005}`,
+		expFeeMap: map[uint32]uint32{
+			1:   12500,
+			144: 1001,
+		},
+	}, {
+		name:     "nested path",
+		schema:   ".data.estimates",
+		response: `{"data": {"estimates": {"2": 2000}}}`,
+		expFeeMap: map[uint32]uint32{
+			2: 2000,
+		},
+	}, {
+		name:   "named targets",
+		schema: "{1: .fastestFee, 3: .halfHourFee, 6: .hourFee} * 1000",
+		response: `{"fastestFee": 20, "halfHourFee": 10, ` +
+			`"hourFee": 5, "economyFee": 1}`,
+		expFeeMap: map[uint32]uint32{
+			1: 20000,
+			3: 10000,
+			6: 5000,
+		},
+	}, {
+		name:     "missing path",
+		schema:   ".fees",
+		response: `{"fee_by_block_target": {"1": 12345}}`,
+		applyErr: true,
+	}, {
+		name:     "path not an object",
+		schema:   ".fees",
+		response: `{"fees": 12345}`,
+		applyErr: true,
+	}, {
+		name:     "non-numeric conf target",
+		schema:   ".",
+		response: `{"fastestFee": 20}`,
+		applyErr: true,
+	}, {
+		name:     "non-numeric fee rate",
+		schema:   "{1: .fastestFee}",
+		response: `{"fastestFee": "20"}`,
+		applyErr: true,
+	}, {
+		name:     "negative fee rate",
+		schema:   ".",
+		response: `{"1": -5}`,
+		applyErr: true,
+	}, {
+		name:     "empty fee map",
+		schema:   ".fee_by_block_target",
+		response: `{"fee_by_block_target": {}}`,
+		applyErr: true,
+	}, {
+		name:     "path without leading dot",
+		schema:   "fee_by_block_target",
+		parseErr: true,
+	}, {
+		name:     "invalid factor",
+		schema:   ". * 0",
+		parseErr: true,
+	}, {
+		name:     "zero conf target",
+		schema:   "{0: .fastestFee}",
+		parseErr: true,
+	}, {
+		name:     "duplicate conf target",
+		schema:   "{1: .fastestFee, 1: .hourFee}",
+		parseErr: true,
+	}, {
+		name:     "unterminated object",
+		schema:   "{1: .fastestFee",
+		parseErr: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := ParseFeeSchema(tc.schema)
+			if tc.parseErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The schema is applied through the fee source, just
+			// like it is when querying the fee API.
+			feeSource := SparseConfFeeSource{Schema: schema}
+			fees, err := feeSource.parseResponse(
+				strings.NewReader(tc.response),
+			)
+			if tc.applyErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expFeeMap, fees)
+		})
+	}
+}
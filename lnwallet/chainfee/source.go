@@ -0,0 +1,85 @@
+package chainfee
+
+import "time"
+
+// EstimateSource describes where the fee rates returned by an estimator
+// originate from.
+type EstimateSource uint8
+
+const (
+	// EstimateSourceUnknown is used for estimators whose source of fee
+	// rates isn't known.
+	EstimateSourceUnknown EstimateSource = iota
+
+	// EstimateSourceBackend indicates that the fee rates are queried from
+	// the chain backend on demand.
+	EstimateSourceBackend
+
+	// EstimateSourceWebAPI indicates that the fee rates are queried from
+	// a fee estimation web API on demand.
+	EstimateSourceWebAPI
+
+	// EstimateSourceCache indicates that the fee rates were fetched from
+	// a fee estimation web API earlier and are served from a cache.
+	EstimateSourceCache
+
+	// EstimateSourceStatic indicates that the fee rates are static and
+	// don't reflect the current state of the mempool.
+	EstimateSourceStatic
+)
+
+// String returns a human readable representation of the estimate source.
+func (s EstimateSource) String() string {
+	switch s {
+	case EstimateSourceBackend:
+		return "backend"
+
+	case EstimateSourceWebAPI:
+		return "web"
+
+	case EstimateSourceCache:
+		return "cache"
+
+	case EstimateSourceStatic:
+		return "static"
+
+	default:
+		return "unknown"
+	}
+}
+
+// EstimatorSource returns the source of the fee rates returned by the given
+// estimator. For cached fee rates, the time of the last successful update of
+// the cache is returned as well. Otherwise the returned time is zero, as the
+// fee rates are queried on demand.
+func EstimatorSource(estimator Estimator) (EstimateSource, time.Time) {
+	switch e := estimator.(type) {
+	// A floor estimator only raises the fee rates of the estimator it
+	// wraps, so the wrapped estimator determines the source.
+	case *FloorEstimator:
+		return EstimatorSource(e.Estimator)
+
+	// The same applies to a ceiling estimator that only caps them.
+	case *CeilingEstimator:
+		return EstimatorSource(e.Estimator)
+
+	case *BtcdEstimator, *BitcoindEstimator:
+		return EstimateSourceBackend, time.Time{}
+
+	case *StaticEstimator:
+		return EstimateSourceStatic, time.Time{}
+
+	case *WebAPIEstimator:
+		if e.noCache {
+			return EstimateSourceWebAPI, time.Time{}
+		}
+
+		e.feesMtx.Lock()
+		defer e.feesMtx.Unlock()
+
+		return EstimateSourceCache, e.lastFeeUpdate
+
+	default:
+		return EstimateSourceUnknown, time.Time{}
+	}
+}
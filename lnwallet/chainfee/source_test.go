@@ -0,0 +1,53 @@
+package chainfee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEstimatorSource checks that the source of an estimator's fee rates and
+// the age of cached fee rates are reported correctly.
+func TestEstimatorSource(t *testing.T) {
+	t.Parallel()
+
+	static := NewStaticEstimator(FeePerKwFloor, 0)
+	source, lastUpdate := EstimatorSource(static)
+	require.Equal(t, EstimateSourceStatic, source)
+	require.True(t, lastUpdate.IsZero())
+
+	// A floor estimator reports the source of the estimator it wraps.
+	floor := NewFloorEstimator(static, FeePerKwFloor)
+	source, _ = EstimatorSource(floor)
+	require.Equal(t, EstimateSourceStatic, source)
+
+	// So does a ceiling estimator, also if it wraps a floor estimator.
+	ceiling := NewCeilingEstimator(floor, 10*FeePerKwFloor)
+	source, _ = EstimatorSource(ceiling)
+	require.Equal(t, EstimateSourceStatic, source)
+
+	feeSource := &mockFeeSource{}
+	feeSource.On("GetFeeMap").Return(map[uint32]uint32{2: 2000}, nil)
+
+	// A web estimator that doesn't cache queries the API on demand.
+	webEstimator, err := NewWebAPIEstimator(
+		feeSource, true, time.Minute, 2*time.Minute, 0,
+	)
+	require.NoError(t, err)
+
+	source, lastUpdate = EstimatorSource(webEstimator)
+	require.Equal(t, EstimateSourceWebAPI, source)
+	require.True(t, lastUpdate.IsZero())
+
+	// A caching web estimator reports the time of the last update.
+	webEstimator, err = NewWebAPIEstimator(
+		feeSource, false, time.Minute, 2*time.Minute, 0,
+	)
+	require.NoError(t, err)
+
+	webEstimator.updateFeeEstimates()
+	source, lastUpdate = EstimatorSource(webEstimator)
+	require.Equal(t, EstimateSourceCache, source)
+	require.WithinDuration(t, time.Now(), lastUpdate, time.Minute)
+}
@@ -0,0 +1,242 @@
+package chancloser
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// CloseCost is an estimate of the on-chain fees of closing a channel, either
+// cooperatively or by force closing our latest commitment.
+type CloseCost struct {
+	// Initiator is true if we opened the channel, in which case we pay the
+	// fee of the closing and the commitment transaction.
+	Initiator bool
+
+	// CoopCloseFee is the fee of a cooperative close transaction at the
+	// target fee rate.
+	CoopCloseFee btcutil.Amount
+
+	// CommitFee is the fee already committed to by our latest commitment
+	// transaction.
+	CommitFee btcutil.Amount
+
+	// AnchorCpfpFee is the fee we'd need to pay from our wallet to bump
+	// the commitment transaction to the target fee rate using our anchor.
+	// It's always zero for channels without anchors.
+	AnchorCpfpFee btcutil.Amount
+
+	// SweepFee is the fee of resolving all non-dust HTLCs through the
+	// second level and sweeping our outputs into the wallet after a force
+	// close.
+	SweepFee btcutil.Amount
+}
+
+// LocalCoopCloseFee returns the part of the cooperative close fee that is paid
+// by us.
+func (c *CloseCost) LocalCoopCloseFee() btcutil.Amount {
+	if !c.Initiator {
+		return 0
+	}
+
+	return c.CoopCloseFee
+}
+
+// LocalForceCloseFee returns the part of the force close fees that is paid by
+// us. The commitment fee is only paid by the initiator, while the anchor and
+// sweep fees are paid by the party that force closes.
+func (c *CloseCost) LocalForceCloseFee() btcutil.Amount {
+	fee := c.AnchorCpfpFee + c.SweepFee
+	if c.Initiator {
+		fee += c.CommitFee
+	}
+
+	return fee
+}
+
+// EstimateCloseCost estimates the fees of closing the given channel at the
+// given fee rate, without touching the channel. If no upfront shutdown script
+// was negotiated, the cooperative close is assumed to pay to a default wallet
+// script. The force close estimate assumes that we broadcast our latest
+// commitment and resolve all of its non-dust HTLCs on-chain.
+func EstimateCloseCost(channel *channeldb.OpenChannel,
+	feeRate chainfee.SatPerKWeight) *CloseCost {
+
+	chanType := channel.ChanType
+	commit := channel.LocalCommitment
+	commitFeeRate := chainfee.SatPerKWeight(commit.FeePerKw)
+
+	cost := &CloseCost{
+		Initiator:    channel.IsInitiator,
+		CoopCloseFee: estimateCoopCloseFee(channel, feeRate),
+		CommitFee:    commit.CommitFee,
+	}
+
+	// Determine the HTLCs that have an output on our commitment and need
+	// to be resolved through a second level transaction.
+	var htlcs []channeldb.HTLC
+	for _, htlc := range commit.Htlcs {
+		isDust := lnwallet.HtlcIsDust(
+			chanType, htlc.Incoming, true, commitFeeRate,
+			htlc.Amt.ToSatoshis(), channel.LocalChanCfg.DustLimit,
+		)
+		if isDust {
+			continue
+		}
+
+		htlcs = append(htlcs, htlc)
+	}
+
+	// With anchors, the commitment is bumped to the target fee rate by
+	// spending our anchor with a child paying for the whole package.
+	if chanType.HasAnchors() {
+		commitWeight := lnwallet.CommitWeight(chanType) +
+			lntypes.WeightUnit(len(htlcs))*input.HTLCWeight
+
+		packageFee := feeRate.FeeForWeight(
+			commitWeight + anchorCpfpWeight(chanType),
+		)
+		if packageFee > commit.CommitFee {
+			cost.AnchorCpfpFee = packageFee - commit.CommitFee
+		}
+	}
+
+	// The outputs that mature on our commitment are swept in a single
+	// transaction once their CSV delay expired.
+	var sweepEstimator input.TxWeightEstimator
+	numSweepInputs := 0
+
+	localBalance := commit.LocalBalance.ToSatoshis()
+	if localBalance >= channel.LocalChanCfg.DustLimit {
+		if chanType.IsTaproot() {
+			sweepEstimator.AddWitnessInput(
+				input.TaprootToLocalWitnessSize,
+			)
+		} else {
+			sweepEstimator.AddWitnessInput(
+				input.ToLocalTimeoutWitnessSize,
+			)
+		}
+		numSweepInputs++
+	}
+
+	for _, htlc := range htlcs {
+		cost.SweepFee += secondLevelFee(
+			chanType, htlc.Incoming, commitFeeRate, feeRate,
+		)
+
+		if chanType.IsTaproot() {
+			sweepEstimator.AddWitnessInput(
+				input.TaprootSecondLevelHtlcWitnessSize,
+			)
+		} else {
+			sweepEstimator.AddWitnessInput(
+				input.ToLocalTimeoutWitnessSize,
+			)
+		}
+		numSweepInputs++
+	}
+
+	if numSweepInputs > 0 {
+		sweepEstimator.AddP2TROutput()
+		cost.SweepFee += feeRate.FeeForWeight(sweepEstimator.Weight())
+	}
+
+	return cost
+}
+
+// estimateCoopCloseFee estimates the fee of a cooperative close transaction of
+// the given channel at the given fee rate.
+func estimateCoopCloseFee(channel *channeldb.OpenChannel,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	// Without an upfront shutdown script, we assume the default script
+	// type the wallet would deliver the funds to.
+	defaultScript := make([]byte, input.P2WPKHSize)
+	if channel.ChanType.IsTaproot() {
+		defaultScript = make([]byte, input.P2TRSize)
+	}
+
+	deliveryOutput := func(balance, dustLimit btcutil.Amount,
+		script []byte) *wire.TxOut {
+
+		// Dust outputs are omitted from the closing transaction.
+		if balance < dustLimit {
+			return nil
+		}
+
+		if len(script) == 0 {
+			script = defaultScript
+		}
+
+		return &wire.TxOut{
+			PkScript: script,
+			Value:    int64(balance),
+		}
+	}
+
+	commit := channel.LocalCommitment
+	localOutput := deliveryOutput(
+		commit.LocalBalance.ToSatoshis(),
+		channel.LocalChanCfg.DustLimit, channel.LocalShutdownScript,
+	)
+	remoteOutput := deliveryOutput(
+		commit.RemoteBalance.ToSatoshis(),
+		channel.RemoteChanCfg.DustLimit, channel.RemoteShutdownScript,
+	)
+
+	estimator := &SimpleCoopFeeEstimator{}
+
+	return estimator.EstimateFee(
+		channel.ChanType, localOutput, remoteOutput, feeRate,
+	)
+}
+
+// anchorCpfpWeight returns the weight of a child transaction that spends our
+// anchor and a wallet input to pay for the commitment.
+func anchorCpfpWeight(chanType channeldb.ChannelType) lntypes.WeightUnit {
+	var estimator input.TxWeightEstimator
+	if chanType.IsTaproot() {
+		estimator.AddWitnessInput(input.TaprootAnchorWitnessSize)
+	} else {
+		estimator.AddWitnessInput(input.AnchorWitnessSize)
+	}
+	estimator.AddP2WKHInput()
+	estimator.AddP2TROutput()
+
+	return estimator.Weight()
+}
+
+// secondLevelFee returns the fee of the second level transaction resolving an
+// HTLC on our commitment. For zero-fee HTLC transactions the fee is added
+// from our wallet at the target fee rate, otherwise it was committed to at
+// the fee rate of the commitment.
+func secondLevelFee(chanType channeldb.ChannelType, incoming bool,
+	commitFeeRate, feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	if !chanType.ZeroHtlcTxFee() && !chanType.IsTaproot() {
+		if incoming {
+			return lnwallet.HtlcSuccessFee(chanType, commitFeeRate)
+		}
+
+		return lnwallet.HtlcTimeoutFee(chanType, commitFeeRate)
+	}
+
+	switch {
+	case chanType.IsTaproot() && incoming:
+		return feeRate.FeeForWeight(input.TaprootHtlcSuccessWeight)
+
+	case chanType.IsTaproot():
+		return feeRate.FeeForWeight(input.TaprootHtlcTimeoutWeight)
+
+	case incoming:
+		return feeRate.FeeForWeight(input.HtlcSuccessWeightConfirmed)
+
+	default:
+		return feeRate.FeeForWeight(input.HtlcTimeoutWeightConfirmed)
+	}
+}
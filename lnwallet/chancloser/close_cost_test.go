@@ -0,0 +1,155 @@
+package chancloser
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// testCloseCostChannel returns a channel of the given type with the given
+// local commitment fee and HTLCs.
+func testCloseCostChannel(chanType channeldb.ChannelType, initiator bool,
+	commitFee btcutil.Amount,
+	htlcs ...channeldb.HTLC) *channeldb.OpenChannel {
+
+	return &channeldb.OpenChannel{
+		ChanType:    chanType,
+		IsInitiator: initiator,
+		LocalChanCfg: channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit: 354,
+			},
+		},
+		RemoteChanCfg: channeldb.ChannelConfig{
+			ChannelConstraints: channeldb.ChannelConstraints{
+				DustLimit: 354,
+			},
+		},
+		LocalCommitment: channeldb.ChannelCommitment{
+			LocalBalance:  lnwire.NewMSatFromSatoshis(500_000),
+			RemoteBalance: lnwire.NewMSatFromSatoshis(400_000),
+			CommitFee:     commitFee,
+			FeePerKw:      253,
+			Htlcs:         htlcs,
+		},
+	}
+}
+
+// TestEstimateCloseCost tests the estimation of the fees of closing a channel.
+func TestEstimateCloseCost(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(10_000)
+
+	legacyType := channeldb.SingleFunderTweaklessBit
+	anchorType := legacyType | channeldb.AnchorOutputsBit |
+		channeldb.ZeroHtlcTxFeeBit
+
+	// The coop close pays to two P2WKH outputs, as no upfront shutdown
+	// scripts are set.
+	p2wkhOutput := func(amt btcutil.Amount) *wire.TxOut {
+		return &wire.TxOut{
+			PkScript: make([]byte, input.P2WPKHSize),
+			Value:    int64(amt),
+		}
+	}
+	coopFee := (&SimpleCoopFeeEstimator{}).EstimateFee(
+		legacyType, p2wkhOutput(500_000), p2wkhOutput(400_000),
+		feeRate,
+	)
+
+	var toLocalSweep input.TxWeightEstimator
+	toLocalSweep.AddWitnessInput(input.ToLocalTimeoutWitnessSize)
+	toLocalSweep.AddP2TROutput()
+	toLocalSweepFee := feeRate.FeeForWeight(toLocalSweep.Weight())
+
+	t.Run("legacy initiator", func(t *testing.T) {
+		t.Parallel()
+
+		channel := testCloseCostChannel(legacyType, true, 1_000)
+		cost := EstimateCloseCost(channel, feeRate)
+
+		require.Equal(t, coopFee, cost.CoopCloseFee)
+		require.Equal(t, coopFee, cost.LocalCoopCloseFee())
+		require.Equal(t, btcutil.Amount(1_000), cost.CommitFee)
+		require.Zero(t, cost.AnchorCpfpFee)
+		require.Equal(t, toLocalSweepFee, cost.SweepFee)
+		require.Equal(
+			t, 1_000+toLocalSweepFee, cost.LocalForceCloseFee(),
+		)
+	})
+
+	t.Run("anchor non-initiator", func(t *testing.T) {
+		t.Parallel()
+
+		channel := testCloseCostChannel(anchorType, false, 1_000)
+		cost := EstimateCloseCost(channel, feeRate)
+
+		packageFee := feeRate.FeeForWeight(
+			input.AnchorCommitWeight + anchorCpfpWeight(anchorType),
+		)
+		require.Equal(t, packageFee-1_000, cost.AnchorCpfpFee)
+
+		// The fees of the closing and commitment transaction are paid
+		// by the initiator.
+		require.Zero(t, cost.LocalCoopCloseFee())
+		require.Equal(
+			t, cost.AnchorCpfpFee+cost.SweepFee,
+			cost.LocalForceCloseFee(),
+		)
+	})
+
+	t.Run("anchor commit fee sufficient", func(t *testing.T) {
+		t.Parallel()
+
+		channel := testCloseCostChannel(anchorType, true, 100_000)
+		cost := EstimateCloseCost(channel, feeRate)
+
+		require.Zero(t, cost.AnchorCpfpFee)
+	})
+
+	t.Run("htlcs", func(t *testing.T) {
+		t.Parallel()
+
+		dustHtlc := channeldb.HTLC{
+			Amt: lnwire.NewMSatFromSatoshis(100),
+		}
+		htlc := channeldb.HTLC{
+			Amt:      lnwire.NewMSatFromSatoshis(50_000),
+			Incoming: true,
+		}
+
+		// Dust HTLCs don't have an output and therefore don't add to
+		// the force close fees.
+		channel := testCloseCostChannel(
+			anchorType, true, 1_000, dustHtlc,
+		)
+		dustCost := EstimateCloseCost(channel, feeRate)
+		require.Equal(t, toLocalSweepFee, dustCost.SweepFee)
+
+		channel = testCloseCostChannel(
+			anchorType, true, 1_000, dustHtlc, htlc,
+		)
+		cost := EstimateCloseCost(channel, feeRate)
+
+		var sweep input.TxWeightEstimator
+		sweep.AddWitnessInput(input.ToLocalTimeoutWitnessSize)
+		sweep.AddWitnessInput(input.ToLocalTimeoutWitnessSize)
+		sweep.AddP2TROutput()
+
+		secondLevelFee := feeRate.FeeForWeight(
+			input.HtlcSuccessWeightConfirmed,
+		)
+		require.Equal(
+			t, secondLevelFee+feeRate.FeeForWeight(sweep.Weight()),
+			cost.SweepFee,
+		)
+		require.Greater(t, cost.AnchorCpfpFee, dustCost.AnchorCpfpFee)
+	})
+}
@@ -88,6 +88,28 @@ func selectInputs(amt btcutil.Amount, coins []wallet.Coin,
 	return 0, nil, &ErrInsufficientFunds{amt, satSelected}
 }
 
+// addInputWeight adds the weight of spending an output with the given script
+// to the weight estimate.
+func addInputWeight(weightEstimate *input.TxWeightEstimator,
+	pkScript []byte) error {
+
+	switch {
+	case txscript.IsPayToWitnessPubKeyHash(pkScript):
+		weightEstimate.AddP2WKHInput()
+
+	case txscript.IsPayToScriptHash(pkScript):
+		weightEstimate.AddNestedP2WKHInput()
+
+	case txscript.IsPayToTaproot(pkScript):
+		weightEstimate.AddTaprootKeySpendInput(txscript.SigHashDefault)
+
+	default:
+		return &errUnsupportedInput{pkScript}
+	}
+
+	return nil
+}
+
 // calculateFees returns for the specified utxos and fee rate two fee
 // estimates, one calculated using a change output and one without. The weight
 // added to the estimator from a change output is for a P2WKH output.
@@ -97,20 +119,9 @@ func calculateFees(utxos []wallet.Coin, feeRate chainfee.SatPerKWeight,
 
 	weightEstimate := existingWeight
 	for _, utxo := range utxos {
-		switch {
-		case txscript.IsPayToWitnessPubKeyHash(utxo.PkScript):
-			weightEstimate.AddP2WKHInput()
-
-		case txscript.IsPayToScriptHash(utxo.PkScript):
-			weightEstimate.AddNestedP2WKHInput()
-
-		case txscript.IsPayToTaproot(utxo.PkScript):
-			weightEstimate.AddTaprootKeySpendInput(
-				txscript.SigHashDefault,
-			)
-
-		default:
-			return 0, 0, &errUnsupportedInput{utxo.PkScript}
+		err := addInputWeight(&weightEstimate, utxo.PkScript)
+		if err != nil {
+			return 0, 0, err
 		}
 	}
 
@@ -165,6 +176,23 @@ func CoinSelect(feeRate chainfee.SatPerKWeight, amt, dustLimit btcutil.Amount,
 	existingWeight input.TxWeightEstimator,
 	changeType ChangeAddressType) ([]wallet.Coin, btcutil.Amount, error) {
 
+	// The branch and bound strategy first searches for a set of coins
+	// that doesn't require a change output. If there is none, it falls
+	// back to selecting the largest coins first.
+	_, isBnB := strategy.(*BranchAndBoundCoinSelector)
+	if isBnB && changeType != ExistingChangeAddress {
+		selected, ok, err := coinSelectChangeless(
+			feeRate, amt, dustLimit, coins, existingWeight,
+			changeType,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			return selected, 0, nil
+		}
+	}
+
 	amtNeeded := amt
 	for {
 		// First perform an initial round of coin selection to estimate
@@ -0,0 +1,256 @@
+package chanfunding
+
+import (
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// bnbMaxTries is the maximum number of branches the branch and bound search
+// visits before it gives up and returns the best solution found so far. This
+// bounds the time spent on wallets with many coins.
+const bnbMaxTries = 100_000
+
+// CoinSelectionBranchAndBound searches for a set of coins that funds a
+// transaction without a change output, minimizing the amount that exceeds the
+// target. If there is no such set, the largest coins are selected first.
+var CoinSelectionBranchAndBound wallet.CoinSelectionStrategy = &BranchAndBoundCoinSelector{}
+
+// BranchAndBoundCoinSelector is an implementation of the
+// wallet.CoinSelectionStrategy that uses a branch and bound search to find a
+// set of coins that doesn't require a change output. Avoiding change saves
+// the fees for creating and later spending the change output and doesn't link
+// a new output to the transaction.
+//
+// The coin selection of this package runs the search itself, as it knows the
+// amount to fund. Coin selection that only lets the strategy arrange the coins
+// needs a selector created with NewTargetedBranchAndBound, as the amount isn't
+// passed to ArrangeCoins.
+type BranchAndBoundCoinSelector struct {
+	// target is the amount the selected coins need to fund in addition to
+	// the fees for spending them. If it's zero, the coins are only
+	// arranged largest first.
+	target btcutil.Amount
+
+	// costOfChange is the amount the selected coins may exceed the target
+	// by. It is the amount that would be left over without creating a
+	// change output.
+	costOfChange btcutil.Amount
+}
+
+// NewTargetedBranchAndBound creates a branch and bound coin selector for a
+// transaction that needs target satoshis in addition to the fees for spending
+// the selected coins. A set of coins is accepted if it exceeds the target by at
+// most costOfChange.
+func NewTargetedBranchAndBound(target,
+	costOfChange btcutil.Amount) *BranchAndBoundCoinSelector {
+
+	return &BranchAndBoundCoinSelector{
+		target:       target,
+		costOfChange: costOfChange,
+	}
+}
+
+// ArrangeCoins takes a list of coins and arranges them according to the
+// specified coin selection strategy and fee rate. If the selector has a target
+// and the search finds a set of coins for it, those coins come first. All other
+// coins follow largest first, so the coin selection can still fund the
+// transaction with a change output if the arranged coins aren't sufficient.
+//
+// NOTE: Part of the wallet.CoinSelectionStrategy interface.
+func (b *BranchAndBoundCoinSelector) ArrangeCoins(eligible []wallet.Coin,
+	feeSatPerKb btcutil.Amount) ([]wallet.Coin, error) {
+
+	sort.Slice(eligible, func(i, j int) bool {
+		return eligible[i].Value > eligible[j].Value
+	})
+
+	if b.target == 0 {
+		return eligible, nil
+	}
+
+	feeRate := chainfee.SatPerKVByte(feeSatPerKb).FeePerKWeight()
+	selected, ok := selectBranchAndBound(
+		b.target, b.costOfChange, eligible, feeRate,
+	)
+	if !ok {
+		return eligible, nil
+	}
+
+	isSelected := make(map[int]struct{}, len(selected))
+	arranged := make([]wallet.Coin, 0, len(eligible))
+	for _, idx := range selected {
+		isSelected[idx] = struct{}{}
+		arranged = append(arranged, eligible[idx])
+	}
+	for idx, coin := range eligible {
+		if _, ok := isSelected[idx]; !ok {
+			arranged = append(arranged, coin)
+		}
+	}
+
+	return arranged, nil
+}
+
+// coinSelectChangeless attempts to select a set of coins that funds amt
+// satoshis at the given fee rate without a change output. False is returned if
+// there is no such set, in which case the caller should select coins with a
+// change output instead.
+func coinSelectChangeless(feeRate chainfee.SatPerKWeight, amt,
+	dustLimit btcutil.Amount, coins []wallet.Coin,
+	existingWeight input.TxWeightEstimator,
+	changeType ChangeAddressType) ([]wallet.Coin, bool, error) {
+
+	// The coins need to pay for the parts of the transaction that already
+	// exist in addition to the amount.
+	baseWeight := existingWeight
+	target := amt + feeRate.FeeForWeight(baseWeight.Weight())
+
+	// Without a change output, anything left over after paying the fee
+	// goes to the miners. A change output is only created if the amount
+	// left over after paying for it isn't dust, so we accept sets of coins
+	// that exceed the target by less than that.
+	_, changeFee, err := calculateFees(nil, feeRate, baseWeight, changeType)
+	if err != nil {
+		return nil, false, err
+	}
+	costOfChange := changeFee - feeRate.FeeForWeight(baseWeight.Weight()) +
+		dustLimit - 1
+
+	selectedIdx, ok := selectBranchAndBound(
+		target, costOfChange, coins, feeRate,
+	)
+	if !ok {
+		return nil, false, nil
+	}
+
+	selected := make([]wallet.Coin, 0, len(selectedIdx))
+	var totalSat btcutil.Amount
+	for _, idx := range selectedIdx {
+		selected = append(selected, coins[idx])
+		totalSat += btcutil.Amount(coins[idx].Value)
+	}
+
+	// The search works with the fees of the individual inputs, so we make
+	// sure the whole transaction is funded without change before we use
+	// the result.
+	requiredFeeNoChange, requiredFeeWithChange, err := calculateFees(
+		selected, feeRate, existingWeight, changeType,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	changeAmt, newAmtNeeded, err := CalculateChangeAmount(
+		totalSat, amt, requiredFeeNoChange, requiredFeeWithChange,
+		dustLimit, changeType,
+	)
+	if err != nil || changeAmt != 0 || newAmtNeeded != 0 {
+		return nil, false, nil
+	}
+
+	return selected, true, nil
+}
+
+// selectBranchAndBound searches for the set of coins whose effective values,
+// their values minus the fees for spending them, add up to at least target and
+// at most target plus costOfChange. Of all such sets found, the one closest to
+// the target is returned as indexes into the given coins. Coins that can't be
+// spent economically at the fee rate are never selected.
+func selectBranchAndBound(target, costOfChange btcutil.Amount,
+	coins []wallet.Coin, feeRate chainfee.SatPerKWeight) ([]int, bool) {
+
+	type candidate struct {
+		idx       int
+		effective btcutil.Amount
+	}
+
+	candidates := make([]candidate, 0, len(coins))
+	for idx, coin := range coins {
+		var weightEstimate input.TxWeightEstimator
+		baseWeight := weightEstimate.Weight()
+		err := addInputWeight(&weightEstimate, coin.PkScript)
+		if err != nil {
+			continue
+		}
+		inputFee := feeRate.FeeForWeight(
+			weightEstimate.Weight() - baseWeight,
+		)
+
+		effective := btcutil.Amount(coin.Value) - inputFee
+		if effective <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			idx:       idx,
+			effective: effective,
+		})
+	}
+
+	// Trying the largest coins first finds solutions with few inputs
+	// early and lets us prune branches that overshoot the target sooner.
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].effective > candidates[j].effective
+	})
+
+	// remaining[i] is the total effective value of the candidates from
+	// index i onwards. A branch that can't reach the target even if it
+	// included all of them is pruned.
+	remaining := make([]btcutil.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].effective
+	}
+
+	var (
+		tries      int
+		selection  []int
+		best       []int
+		bestExcess btcutil.Amount = -1
+	)
+
+	// search decides for the candidate at index i whether to include it,
+	// given that the candidates before it add up to value.
+	var search func(i int, value btcutil.Amount)
+	search = func(i int, value btcutil.Amount) {
+		tries++
+		if tries > bnbMaxTries || bestExcess == 0 {
+			return
+		}
+
+		if value > target+costOfChange {
+			return
+		}
+
+		if value >= target {
+			excess := value - target
+			if bestExcess < 0 || excess < bestExcess {
+				best = append(best[:0], selection...)
+				bestExcess = excess
+			}
+
+			return
+		}
+
+		if i == len(candidates) || value+remaining[i] < target {
+			return
+		}
+
+		// Explore the branch including the candidate first, then the
+		// one omitting it.
+		selection = append(selection, candidates[i].idx)
+		search(i+1, value+candidates[i].effective)
+		selection = selection[:len(selection)-1]
+
+		search(i+1, value)
+	}
+	search(0, 0)
+
+	if bestExcess < 0 {
+		return nil, false
+	}
+
+	return best, true
+}
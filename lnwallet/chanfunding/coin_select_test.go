@@ -342,6 +342,87 @@ func TestCoinSelect(t *testing.T) {
 	}
 }
 
+// TestCoinSelectBranchAndBound tests that the branch and bound strategy selects
+// a set of coins that doesn't require a change output if there is one, and
+// falls back to selecting the largest coins first otherwise.
+func TestCoinSelectBranchAndBound(t *testing.T) {
+	t.Parallel()
+
+	const (
+		feeRate   = chainfee.SatPerKWeight(100)
+		dustLimit = btcutil.Amount(1000)
+	)
+
+	coin := func(value btcutil.Amount) wallet.Coin {
+		return wallet.Coin{
+			TxOut: wire.TxOut{
+				PkScript: p2wkhScript,
+				Value:    int64(value),
+			},
+		}
+	}
+
+	fundingOutputEstimate := input.TxWeightEstimator{}
+	fundingOutputEstimate.AddP2WSHOutput()
+
+	// The two medium coins fund the output without change, leaving a
+	// little bit to the miners.
+	outputValue := btcutil.Amount(500_000) -
+		fundingFee(feeRate, 2, false) - 100
+	coins := []wallet.Coin{
+		coin(1_000_000), coin(300_000), coin(200_000), coin(50_000),
+	}
+
+	selected, changeAmt, err := CoinSelect(
+		feeRate, outputValue, dustLimit, coins,
+		CoinSelectionBranchAndBound, fundingOutputEstimate,
+		defaultChanFundingChangeType,
+	)
+	require.NoError(t, err)
+	require.Zero(t, changeAmt)
+	require.Len(t, selected, 2)
+	require.EqualValues(t, 300_000, selected[0].Value)
+	require.EqualValues(t, 200_000, selected[1].Value)
+
+	// The largest coins strategy selects the largest coin and creates a
+	// change output instead.
+	selected, changeAmt, err = CoinSelect(
+		feeRate, outputValue, dustLimit, coins,
+		wallet.CoinSelectionLargest, fundingOutputEstimate,
+		defaultChanFundingChangeType,
+	)
+	require.NoError(t, err)
+	require.NotZero(t, changeAmt)
+	require.Len(t, selected, 1)
+	require.EqualValues(t, 1_000_000, selected[0].Value)
+
+	// If no set of coins avoids change, the largest coins are selected.
+	selected, changeAmt, err = CoinSelect(
+		feeRate, 700_000, dustLimit, coins,
+		CoinSelectionBranchAndBound, fundingOutputEstimate,
+		defaultChanFundingChangeType,
+	)
+	require.NoError(t, err)
+	require.NotZero(t, changeAmt)
+	require.Len(t, selected, 1)
+	require.EqualValues(t, 1_000_000, selected[0].Value)
+
+	// A targeted selector arranges the coins it found first, followed by
+	// the other coins largest first.
+	target := outputValue + fundingFee(feeRate, 0, false)
+	arranged, err := NewTargetedBranchAndBound(target, dustLimit).
+		ArrangeCoins(coins, btcutil.Amount(feeRate.FeePerKVByte()))
+	require.NoError(t, err)
+
+	arrangedValues := make([]int64, 0, len(arranged))
+	for _, coin := range arranged {
+		arrangedValues = append(arrangedValues, coin.Value)
+	}
+	require.Equal(
+		t, []int64{300_000, 200_000, 1_000_000, 50_000}, arrangedValues,
+	)
+}
+
 // TestCalculateChangeAmount tests that the change amount calculation performs
 // correctly, taking into account the type of change output and whether we want
 // to create a change output in the first place.
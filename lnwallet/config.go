@@ -62,4 +62,9 @@ type Config struct {
 	// CoinSelectionStrategy is the strategy that is used for selecting
 	// coins when funding a transaction.
 	CoinSelectionStrategy wallet.CoinSelectionStrategy
+
+	// SpendUnconfirmed is the policy that determines which unconfirmed
+	// outputs coin selection may use if a transaction allows spending
+	// unconfirmed outputs.
+	SpendUnconfirmed SpendUnconfirmedPolicy
 }
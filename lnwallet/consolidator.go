@@ -0,0 +1,355 @@
+package lnwallet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+const (
+	// DefaultConsolidateInterval is the default interval at which the
+	// UtxoConsolidator checks whether fees are low enough to consolidate.
+	DefaultConsolidateInterval = time.Hour
+
+	// consolidationConfTarget is the confirmation target used to estimate
+	// the fee rate of consolidation transactions. Consolidating isn't time
+	// sensitive, so we can afford to wait for a low fee window.
+	consolidationConfTarget = 144
+
+	// minConsolidationInputs is the minimum number of small UTXOs that
+	// need to be available before we bother consolidating them.
+	minConsolidationInputs = 3
+)
+
+// UtxoConsolidatorConfig houses the parameters of the UtxoConsolidator.
+type UtxoConsolidatorConfig struct {
+	// Wallet is the wallet whose UTXOs are consolidated.
+	Wallet *LightningWallet
+
+	// MaxFeeRate is the fee rate at or below which small UTXOs are
+	// consolidated.
+	MaxFeeRate chainfee.SatPerKWeight
+
+	// MaxUtxoValue is the largest value of a UTXO that is still considered
+	// small enough to be consolidated.
+	MaxUtxoValue btcutil.Amount
+
+	// Interval is the interval at which the fee rate is checked.
+	Interval time.Duration
+
+	// IsSweeperOutpoint returns true if the given outpoint is used by the
+	// sweeper and therefore must not be consolidated.
+	IsSweeperOutpoint func(wire.OutPoint) bool
+}
+
+// UtxoConsolidator is a background task that consolidates the small UTXOs of
+// the wallet's default account into a single output whenever the estimated fee
+// rate drops below a configured threshold. UTXOs that are leased or locked,
+// used by the sweeper or held back as reserve for fee bumping anchor channels
+// are never consolidated.
+type UtxoConsolidator struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *UtxoConsolidatorConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewUtxoConsolidator creates a new UtxoConsolidator with the given config.
+func NewUtxoConsolidator(cfg *UtxoConsolidatorConfig) *UtxoConsolidator {
+	return &UtxoConsolidator{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start launches the background task that periodically checks whether small
+// UTXOs should be consolidated.
+func (u *UtxoConsolidator) Start() error {
+	u.started.Do(func() {
+		walletLog.Infof("Starting UTXO consolidator, consolidating "+
+			"UTXOs of at most %v at fee rates of at most %v",
+			u.cfg.MaxUtxoValue, u.cfg.MaxFeeRate.FeePerVByte())
+
+		u.wg.Add(1)
+		go u.consolidationLoop()
+	})
+
+	return nil
+}
+
+// Stop signals the background task to exit and waits for it to do so.
+func (u *UtxoConsolidator) Stop() error {
+	u.stopped.Do(func() {
+		close(u.quit)
+		u.wg.Wait()
+	})
+
+	return nil
+}
+
+// consolidationLoop periodically attempts to consolidate small UTXOs.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *UtxoConsolidator) consolidationLoop() {
+	defer u.wg.Done()
+
+	ticker := time.NewTicker(u.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := u.maybeConsolidate(); err != nil {
+				walletLog.Errorf("Unable to consolidate "+
+					"UTXOs: %v", err)
+			}
+
+		case <-u.quit:
+			return
+		}
+	}
+}
+
+// maybeConsolidate consolidates the small UTXOs of the wallet if the current
+// fee rate estimate is low enough.
+func (u *UtxoConsolidator) maybeConsolidate() error {
+	feeRate, err := u.cfg.Wallet.Cfg.FeeEstimator.EstimateFeePerKW(
+		consolidationConfTarget,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to estimate fee rate: %w", err)
+	}
+
+	if feeRate > u.cfg.MaxFeeRate {
+		walletLog.Debugf("Fee rate of %v is above consolidation "+
+			"threshold of %v, not consolidating UTXOs",
+			feeRate.FeePerVByte(), u.cfg.MaxFeeRate.FeePerVByte())
+
+		return nil
+	}
+
+	return u.cfg.Wallet.WithCoinSelectLock(func() error {
+		return u.consolidate(feeRate)
+	})
+}
+
+// consolidate spends the small UTXOs of the wallet's default account into a
+// single output at the given fee rate.
+//
+// NOTE: This method must be called with the coin selection lock held.
+func (u *UtxoConsolidator) consolidate(feeRate chainfee.SatPerKWeight) error {
+	w := u.cfg.Wallet
+
+	// Only confirmed UTXOs are considered. Leased outputs aren't returned
+	// by the wallet, but outputs locked for a pending funding flow or used
+	// by the sweeper need to be filtered out explicitly.
+	utxos, err := w.ListUnspentWitnessFromDefaultAccount(1, math.MaxInt32)
+	if err != nil {
+		return err
+	}
+
+	locked := make(map[wire.OutPoint]struct{})
+	for _, op := range w.LockedOutpoints() {
+		locked[*op] = struct{}{}
+	}
+
+	eligible := make([]*Utxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if _, ok := locked[utxo.OutPoint]; ok {
+			continue
+		}
+		if u.cfg.IsSweeperOutpoint(utxo.OutPoint) {
+			continue
+		}
+
+		eligible = append(eligible, utxo)
+	}
+
+	numAnchorChans, err := w.CurrentNumAnchorChans()
+	if err != nil {
+		return err
+	}
+	reserve := w.RequiredReserve(uint32(numAnchorChans))
+
+	inputs := selectConsolidationInputs(
+		eligible, u.cfg.MaxUtxoValue, reserve,
+	)
+	if len(inputs) == 0 {
+		walletLog.Debugf("Not enough small UTXOs to consolidate")
+		return nil
+	}
+
+	var (
+		weightEstimate input.TxWeightEstimator
+		totalValue     btcutil.Amount
+	)
+	tx := wire.NewMsgTx(2)
+	for _, utxo := range inputs {
+		switch utxo.AddressType {
+		case WitnessPubKey:
+			weightEstimate.AddP2WKHInput()
+
+		case NestedWitnessPubKey:
+			weightEstimate.AddNestedP2WKHInput()
+
+		case TaprootPubkey:
+			weightEstimate.AddTaprootKeySpendInput(
+				txscript.SigHashDefault,
+			)
+
+		default:
+			return fmt.Errorf("unsupported address type %v of "+
+				"UTXO %v", utxo.AddressType, utxo.OutPoint)
+		}
+
+		totalValue += utxo.Value
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: utxo.OutPoint,
+		})
+	}
+	weightEstimate.AddP2TROutput()
+
+	// Make sure consolidating is worth it at the current fee rate.
+	fee := feeRate.FeeForWeight(weightEstimate.Weight())
+	outputValue := totalValue - fee
+	if outputValue < DustLimitForSize(input.P2TRSize) {
+		walletLog.Debugf("Consolidating %d UTXOs worth %v would "+
+			"cost %v in fees, skipping", len(inputs), totalValue,
+			fee)
+
+		return nil
+	}
+
+	// Only derive the output address once we know we'll consolidate, so
+	// we don't burn an address on a rejected attempt.
+	changeAddr, err := w.NewAddress(
+		TaprootPubkey, true, DefaultAccountName,
+	)
+	if err != nil {
+		return err
+	}
+	pkScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return err
+	}
+	tx.AddTxOut(wire.NewTxOut(int64(outputValue), pkScript))
+
+	// Lease the inputs while we sign the transaction and release them
+	// again if anything goes wrong.
+	for i, utxo := range inputs {
+		_, _, _, err := w.LeaseOutput(
+			chanfunding.LndInternalLockID, utxo.OutPoint,
+			chanfunding.DefaultLockDuration,
+		)
+		if err != nil {
+			u.releaseInputs(inputs[:i])
+			return err
+		}
+	}
+
+	finalTx, err := u.signTx(tx)
+	if err != nil {
+		u.releaseInputs(inputs)
+		return err
+	}
+
+	walletLog.Infof("Consolidating %d UTXOs worth %v into %v at fee "+
+		"rate %v in tx %v", len(inputs), totalValue, outputValue,
+		feeRate.FeePerVByte(), finalTx.TxHash())
+
+	label := labels.MakeLabel(labels.LabelTypeConsolidation, nil)
+	if err := w.PublishTransaction(finalTx, label); err != nil {
+		u.releaseInputs(inputs)
+		return err
+	}
+
+	return nil
+}
+
+// signTx signs all inputs of the given consolidation transaction with the
+// wallet's keys.
+func (u *UtxoConsolidator) signTx(tx *wire.MsgTx) (*wire.MsgTx, error) {
+	w := u.cfg.Wallet
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.DecorateInputs(packet, true); err != nil {
+		return nil, err
+	}
+
+	if err := w.FinalizePsbt(packet, DefaultAccountName); err != nil {
+		return nil, err
+	}
+
+	return psbt.Extract(packet)
+}
+
+// releaseInputs releases the leases of the given inputs.
+func (u *UtxoConsolidator) releaseInputs(inputs []*Utxo) {
+	for _, utxo := range inputs {
+		err := u.cfg.Wallet.ReleaseOutput(
+			chanfunding.LndInternalLockID, utxo.OutPoint,
+		)
+		if err != nil {
+			walletLog.Errorf("Unable to release UTXO %v: %v",
+				utxo.OutPoint, err)
+		}
+	}
+}
+
+// selectConsolidationInputs returns the UTXOs that should be consolidated.
+// These are all UTXOs with a value of at most maxValue, except for those that
+// need to be held back so that the value of the remaining UTXOs covers the
+// given anchor reserve. Nil is returned if there aren't enough small UTXOs to
+// make consolidation worthwhile.
+func selectConsolidationInputs(utxos []*Utxo, maxValue,
+	reserve btcutil.Amount) []*Utxo {
+
+	var (
+		candidates []*Utxo
+		keptValue  btcutil.Amount
+	)
+	for _, utxo := range utxos {
+		if utxo.Value > maxValue {
+			keptValue += utxo.Value
+			continue
+		}
+
+		candidates = append(candidates, utxo)
+	}
+
+	// While the consolidation transaction is unconfirmed, its inputs
+	// can't be used to fee bump anchor channels. So if the UTXOs we don't
+	// touch aren't enough to cover the reserve, we hold back the largest
+	// small ones as well.
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Value > candidates[j].Value
+	})
+	for len(candidates) > 0 && keptValue < reserve {
+		keptValue += candidates[0].Value
+		candidates = candidates[1:]
+	}
+
+	if len(candidates) < minConsolidationInputs {
+		return nil
+	}
+
+	return candidates
+}
@@ -0,0 +1,83 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSelectConsolidationInputs checks that only small UTXOs are selected for
+// consolidation and that enough value is held back to cover the anchor
+// reserve.
+func TestSelectConsolidationInputs(t *testing.T) {
+	t.Parallel()
+
+	makeUtxos := func(values ...btcutil.Amount) []*Utxo {
+		utxos := make([]*Utxo, 0, len(values))
+		for i, value := range values {
+			utxos = append(utxos, &Utxo{
+				Value: value,
+				OutPoint: wire.OutPoint{
+					Index: uint32(i),
+				},
+			})
+		}
+
+		return utxos
+	}
+
+	testCases := []struct {
+		name      string
+		values    []btcutil.Amount
+		maxValue  btcutil.Amount
+		reserve   btcutil.Amount
+		expValues []btcutil.Amount
+	}{{
+		name:      "all small utxos selected",
+		values:    []btcutil.Amount{1_000, 2_000, 3_000},
+		maxValue:  10_000,
+		expValues: []btcutil.Amount{3_000, 2_000, 1_000},
+	}, {
+		name:     "too few small utxos",
+		values:   []btcutil.Amount{1_000, 2_000, 50_000},
+		maxValue: 10_000,
+	}, {
+		name:      "large utxos cover reserve",
+		values:    []btcutil.Amount{1_000, 2_000, 3_000, 50_000},
+		maxValue:  10_000,
+		reserve:   10_000,
+		expValues: []btcutil.Amount{3_000, 2_000, 1_000},
+	}, {
+		name:      "largest small utxo held back for reserve",
+		values:    []btcutil.Amount{1_000, 2_000, 3_000, 4_000},
+		maxValue:  10_000,
+		reserve:   4_000,
+		expValues: []btcutil.Amount{3_000, 2_000, 1_000},
+	}, {
+		name:     "reserve leaves too few utxos",
+		values:   []btcutil.Amount{1_000, 2_000, 3_000, 4_000},
+		maxValue: 10_000,
+		reserve:  5_000,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			inputs := selectConsolidationInputs(
+				makeUtxos(tc.values...), tc.maxValue,
+				tc.reserve,
+			)
+
+			var values []btcutil.Amount
+			for _, input := range inputs {
+				values = append(values, input.Value)
+			}
+			require.Equal(t, tc.expValues, values)
+		})
+	}
+}
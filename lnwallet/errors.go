@@ -85,6 +85,24 @@ func ErrNonZeroPushAmount() ReservationError {
 	return ReservationError{errors.New("non-zero push amounts are disabled")}
 }
 
+// ErrTorOnlyPeer returns an error indicating that the remote party is only
+// reachable over Tor and channels from such peers are rejected.
+func ErrTorOnlyPeer() ReservationError {
+	return ReservationError{
+		errors.New("channels from tor-only peers are not accepted"),
+	}
+}
+
+// ErrTooManyChannels returns an error indicating that a new channel would
+// exceed the maximum number of channels we permit with a single peer, which
+// includes the channels that are still pending.
+func ErrTooManyChannels(numChans, maxChans int) ReservationError {
+	return ReservationError{
+		fmt.Errorf("already %d open or pending channels, max channels "+
+			"per peer is %d", numChans, maxChans),
+	}
+}
+
 // ErrMinHtlcTooLarge returns an error indicating that the MinHTLC value the
 // remote required is too large to be accepted.
 func ErrMinHtlcTooLarge(minHtlc,
@@ -2969,7 +2969,7 @@ func testSingleFunderExternalFundingTx(miner *rpctest.Harness,
 	aliceChanFunder := chanfunding.NewWalletAssembler(
 		chanfunding.WalletConfig{
 			CoinSource: lnwallet.NewCoinSource(
-				alice, allowUtxo,
+				alice, lnwallet.DefaultAccountName, allowUtxo,
 			),
 			CoinSelectLocker:      alice,
 			CoinLeaser:            alice,
@@ -3109,6 +3109,8 @@ func TestLightningWallet(t *testing.T, targetBackEnd string) {
 	blockCache := blockcache.NewBlockCache(10000)
 	chainNotifier, err := btcdnotify.New(
 		&rpcConfig, netParams, hintCache, hintCache, blockCache,
+		chainntnfs.DefaultRescanConcurrency,
+		chainntnfs.DefaultBlockDownloadConcurrency,
 	)
 	require.NoError(t, err, "unable to create notifier")
 	if err := chainNotifier.Start(); err != nil {
@@ -3293,6 +3295,8 @@ func runTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
 					tempTestDirAlice, false, time.Minute,
 				),
 			},
+			AnchorChanReservedValue:    lnwallet.AnchorChanReservedValue,
+			MaxAnchorChanReservedValue: lnwallet.MaxAnchorChanReservedValue,
 		}
 		aliceWalletController, err = walletDriver.New(
 			aliceWalletConfig, blockCache,
@@ -3324,6 +3328,8 @@ func runTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
 					tempTestDirBob, false, time.Minute,
 				),
 			},
+			AnchorChanReservedValue:    lnwallet.AnchorChanReservedValue,
+			MaxAnchorChanReservedValue: lnwallet.MaxAnchorChanReservedValue,
 		}
 		bobWalletController, err = walletDriver.New(
 			bobWalletConfig, blockCache,
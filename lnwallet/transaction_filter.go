@@ -0,0 +1,190 @@
+package lnwallet
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TransactionFilter describes a set of conditions wallet transactions need to
+// satisfy. Conditions with a zero value are not applied.
+type TransactionFilter struct {
+	// LabelContains only matches transactions whose label contains the
+	// given string, ignoring case.
+	LabelContains string
+
+	// MinAmount only matches transactions whose absolute net value from
+	// the PoV of the wallet is at least the given amount.
+	MinAmount btcutil.Amount
+
+	// MaxAmount only matches transactions whose absolute net value from
+	// the PoV of the wallet is at most the given amount.
+	MaxAmount btcutil.Amount
+
+	// MinConfs only matches transactions with at least the given number of
+	// confirmations.
+	MinConfs int32
+
+	// MaxConfs only matches transactions with at most the given number of
+	// confirmations.
+	MaxConfs int32
+
+	// StartTime only matches transactions with a timestamp at or after
+	// the given unix timestamp.
+	StartTime int64
+
+	// EndTime only matches transactions with a timestamp at or before the
+	// given unix timestamp.
+	EndTime int64
+
+	// Address only matches transactions involving the given address,
+	// either through an output paying to it or an input spending from it.
+	Address btcutil.Address
+
+	// FetchInputInfo looks up the wallet output spent by an input, which
+	// is needed to match the inputs of a transaction against Address. Only
+	// the outputs of the wallet are known, so inputs spending from other
+	// parties never match. If nil, only the outputs are matched.
+	FetchInputInfo func(prevOut *wire.OutPoint) (*Utxo, error)
+}
+
+// HeightRange returns the range of block heights, as expected by
+// ListTransactionDetails, that contains all transactions matching the
+// confirmation range of the filter at the given best height. This allows
+// the wallet to skip all blocks outside of the range instead of returning
+// every transaction. If no transaction can match, false is returned.
+func (f *TransactionFilter) HeightRange(bestHeight int32) (int32, int32,
+	bool) {
+
+	// Unconfirmed transactions are only included if no minimum number of
+	// confirmations is required, which is expressed by an end height of
+	// -1.
+	startHeight, endHeight := int32(0), int32(-1)
+	if f.MinConfs > 0 {
+		endHeight = bestHeight - f.MinConfs + 1
+		if endHeight < 0 {
+			return 0, 0, false
+		}
+	}
+
+	if f.MaxConfs > 0 {
+		startHeight = bestHeight - f.MaxConfs + 1
+		if startHeight < 0 {
+			startHeight = 0
+		}
+	}
+
+	// A start height above the end height would make the wallet return
+	// the transactions in reverse, so we catch that case here.
+	if endHeight >= 0 && startHeight > endHeight {
+		return 0, 0, false
+	}
+
+	return startHeight, endHeight, true
+}
+
+// Matches returns true if the given transaction satisfies all conditions of
+// the filter.
+func (f *TransactionFilter) Matches(tx *TransactionDetail) bool {
+	label := strings.ToLower(tx.Label)
+	if !strings.Contains(label, strings.ToLower(f.LabelContains)) {
+		return false
+	}
+
+	amt := tx.Value
+	if amt < 0 {
+		amt = -amt
+	}
+	if amt < f.MinAmount || (f.MaxAmount != 0 && amt > f.MaxAmount) {
+		return false
+	}
+
+	if tx.NumConfirmations < f.MinConfs ||
+		(f.MaxConfs != 0 && tx.NumConfirmations > f.MaxConfs) {
+
+		return false
+	}
+
+	if tx.Timestamp < f.StartTime ||
+		(f.EndTime != 0 && tx.Timestamp > f.EndTime) {
+
+		return false
+	}
+
+	if f.Address != nil && !paysToAddress(tx, f.Address) &&
+		!f.spendsFromAddress(tx) {
+
+		return false
+	}
+
+	return true
+}
+
+// spendsFromAddress returns true if one of the inputs of the given transaction
+// spends a wallet output paying to the address of the filter.
+func (f *TransactionFilter) spendsFromAddress(tx *TransactionDetail) bool {
+	if f.FetchInputInfo == nil {
+		return false
+	}
+
+	pkScript, err := txscript.PayToAddrScript(f.Address)
+	if err != nil {
+		return false
+	}
+
+	for _, prevOut := range tx.PreviousOutpoints {
+		if !prevOut.IsOurOutput {
+			continue
+		}
+
+		op, err := wire.NewOutPointFromString(prevOut.OutPoint)
+		if err != nil {
+			continue
+		}
+
+		// An output that can't be looked up can't be matched either.
+		utxo, err := f.FetchInputInfo(op)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(utxo.PkScript, pkScript) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paysToAddress returns true if one of the outputs of the given transaction
+// pays to the given address.
+func paysToAddress(tx *TransactionDetail, addr btcutil.Address) bool {
+	encodedAddr := addr.EncodeAddress()
+	for _, output := range tx.OutputDetails {
+		for _, outputAddr := range output.Addresses {
+			if outputAddr.EncodeAddress() == encodedAddr {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FilterTransactions returns all transactions of the given list that match
+// the filter, in their original order.
+func FilterTransactions(txs []*TransactionDetail,
+	filter *TransactionFilter) []*TransactionDetail {
+
+	var matches []*TransactionDetail
+	for _, tx := range txs {
+		if filter.Matches(tx) {
+			matches = append(matches, tx)
+		}
+	}
+
+	return matches
+}
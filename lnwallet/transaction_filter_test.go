@@ -0,0 +1,218 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionFilterHeightRange tests that the confirmation range of a
+// transaction filter is translated into the correct block heights.
+func TestTransactionFilterHeightRange(t *testing.T) {
+	t.Parallel()
+
+	const bestHeight = 100
+
+	testCases := []struct {
+		name          string
+		filter        TransactionFilter
+		expectedStart int32
+		expectedEnd   int32
+		expectedOk    bool
+	}{{
+		name:          "no confirmation range",
+		expectedStart: 0,
+		expectedEnd:   -1,
+		expectedOk:    true,
+	}, {
+		name:          "only confirmed",
+		filter:        TransactionFilter{MinConfs: 1},
+		expectedStart: 0,
+		expectedEnd:   100,
+		expectedOk:    true,
+	}, {
+		name:          "at least six confirmations",
+		filter:        TransactionFilter{MinConfs: 6},
+		expectedStart: 0,
+		expectedEnd:   95,
+		expectedOk:    true,
+	}, {
+		name:          "recent including unconfirmed",
+		filter:        TransactionFilter{MaxConfs: 10},
+		expectedStart: 91,
+		expectedEnd:   -1,
+		expectedOk:    true,
+	}, {
+		name: "confirmation window",
+		filter: TransactionFilter{
+			MinConfs: 3,
+			MaxConfs: 10,
+		},
+		expectedStart: 91,
+		expectedEnd:   98,
+		expectedOk:    true,
+	}, {
+		name:       "more confirmations than blocks",
+		filter:     TransactionFilter{MinConfs: 200},
+		expectedOk: false,
+	}, {
+		name: "empty window",
+		filter: TransactionFilter{
+			MinConfs: 10,
+			MaxConfs: 3,
+		},
+		expectedOk: false,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			start, end, ok := tc.filter.HeightRange(bestHeight)
+			require.Equal(t, tc.expectedOk, ok)
+			if !ok {
+				return
+			}
+
+			require.Equal(t, tc.expectedStart, start)
+			require.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
+
+// TestFilterTransactions tests that transactions are matched against all
+// conditions of a transaction filter.
+func TestFilterTransactions(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	addr1, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), params,
+	)
+	require.NoError(t, err)
+	addr2, err := btcutil.NewAddressTaproot(make([]byte, 32), params)
+	require.NoError(t, err)
+	addr2Script, err := txscript.PayToAddrScript(addr2)
+	require.NoError(t, err)
+
+	// The third transaction spends a wallet output paying to addr2 and
+	// an output of another party.
+	ourPrevOut := wire.OutPoint{Index: 1}
+	theirPrevOut := wire.OutPoint{Index: 2}
+	fetchInputInfo := func(prevOut *wire.OutPoint) (*Utxo, error) {
+		if *prevOut != ourPrevOut {
+			return nil, ErrNotMine
+		}
+
+		return &Utxo{PkScript: addr2Script}, nil
+	}
+
+	txs := []*TransactionDetail{{
+		Value:            50_000,
+		NumConfirmations: 10,
+		Timestamp:        1000,
+		Label:            "Channel Open",
+		OutputDetails: []OutputDetail{{
+			Addresses: []btcutil.Address{addr1},
+		}},
+	}, {
+		Value:            -20_000,
+		NumConfirmations: 2,
+		Timestamp:        2000,
+		Label:            "sweep",
+		OutputDetails: []OutputDetail{{
+			Addresses: []btcutil.Address{addr2},
+		}},
+	}, {
+		Value:     1_000,
+		Timestamp: 3000,
+		PreviousOutpoints: []PreviousOutPoint{{
+			OutPoint:    theirPrevOut.String(),
+			IsOurOutput: false,
+		}, {
+			OutPoint:    ourPrevOut.String(),
+			IsOurOutput: true,
+		}},
+	}}
+
+	testCases := []struct {
+		name            string
+		filter          TransactionFilter
+		expectedIndices []int
+	}{{
+		name:            "no filter",
+		expectedIndices: []int{0, 1, 2},
+	}, {
+		name:            "label case insensitive",
+		filter:          TransactionFilter{LabelContains: "OPEN"},
+		expectedIndices: []int{0},
+	}, {
+		name: "absolute amount range",
+		filter: TransactionFilter{
+			MinAmount: 10_000,
+			MaxAmount: 30_000,
+		},
+		expectedIndices: []int{1},
+	}, {
+		name:            "confirmed only",
+		filter:          TransactionFilter{MinConfs: 1},
+		expectedIndices: []int{0, 1},
+	}, {
+		name:            "max confirmations",
+		filter:          TransactionFilter{MaxConfs: 5},
+		expectedIndices: []int{1, 2},
+	}, {
+		name: "time range",
+		filter: TransactionFilter{
+			StartTime: 1500,
+			EndTime:   2500,
+		},
+		expectedIndices: []int{1},
+	}, {
+		name:            "address outputs only",
+		filter:          TransactionFilter{Address: addr2},
+		expectedIndices: []int{1},
+	}, {
+		name: "address outputs and inputs",
+		filter: TransactionFilter{
+			Address:        addr2,
+			FetchInputInfo: fetchInputInfo,
+		},
+		expectedIndices: []int{1, 2},
+	}, {
+		name: "address without wallet inputs",
+		filter: TransactionFilter{
+			Address:        addr1,
+			FetchInputInfo: fetchInputInfo,
+		},
+		expectedIndices: []int{0},
+	}, {
+		name: "combined",
+		filter: TransactionFilter{
+			Address:   addr1,
+			MinAmount: 60_000,
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var expected []*TransactionDetail
+			for _, idx := range tc.expectedIndices {
+				expected = append(expected, txs[idx])
+			}
+
+			matches := FilterTransactions(txs, &tc.filter)
+			require.Equal(t, expected, matches)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SpendUnconfirmedPolicy determines which unconfirmed wallet outputs may be
+// used as inputs by coin selection if a transaction allows spending
+// unconfirmed outputs at all.
+type SpendUnconfirmedPolicy uint8
+
+const (
+	// SpendUnconfirmedAny allows spending all unconfirmed outputs.
+	SpendUnconfirmedAny SpendUnconfirmedPolicy = iota
+
+	// SpendUnconfirmedOwnChangeOnly only allows spending unconfirmed
+	// outputs of transactions that spend at least one of our own wallet
+	// outputs, such as the change of our own transactions. Unconfirmed
+	// funds received from others are never spent.
+	SpendUnconfirmedOwnChangeOnly
+
+	// SpendUnconfirmedNever never allows spending unconfirmed outputs.
+	SpendUnconfirmedNever
+)
+
+const (
+	// spendUnconfirmedAnyStr is the config value of SpendUnconfirmedAny.
+	spendUnconfirmedAnyStr = "any"
+
+	// spendUnconfirmedOwnChangeOnlyStr is the config value of
+	// SpendUnconfirmedOwnChangeOnly.
+	spendUnconfirmedOwnChangeOnlyStr = "own-change-only"
+
+	// spendUnconfirmedNeverStr is the config value of
+	// SpendUnconfirmedNever.
+	spendUnconfirmedNeverStr = "never"
+)
+
+// String returns the config value of the policy.
+func (p SpendUnconfirmedPolicy) String() string {
+	switch p {
+	case SpendUnconfirmedAny:
+		return spendUnconfirmedAnyStr
+
+	case SpendUnconfirmedOwnChangeOnly:
+		return spendUnconfirmedOwnChangeOnlyStr
+
+	case SpendUnconfirmedNever:
+		return spendUnconfirmedNeverStr
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(p))
+	}
+}
+
+// ParseSpendUnconfirmedPolicy parses the config value of a spend unconfirmed
+// policy.
+func ParseSpendUnconfirmedPolicy(s string) (SpendUnconfirmedPolicy, error) {
+	switch s {
+	case spendUnconfirmedAnyStr:
+		return SpendUnconfirmedAny, nil
+
+	case spendUnconfirmedOwnChangeOnlyStr:
+		return SpendUnconfirmedOwnChangeOnly, nil
+
+	case spendUnconfirmedNeverStr:
+		return SpendUnconfirmedNever, nil
+
+	default:
+		return 0, fmt.Errorf("unknown spend unconfirmed policy %q", s)
+	}
+}
+
+// SpendableUnconfirmed returns the unconfirmed outputs among the given ones
+// that may be spent according to the policy. Confirmed outputs are ignored.
+// For the own change only policy, the transaction of each unconfirmed output
+// is looked up to check whether it spends any of our own outputs.
+func (p SpendUnconfirmedPolicy) SpendableUnconfirmed(utxos []*Utxo,
+	fetchTx func(*chainhash.Hash) (*TransactionDetail, error)) (
+	map[wire.OutPoint]struct{}, error) {
+
+	spendable := make(map[wire.OutPoint]struct{})
+	if p == SpendUnconfirmedNever {
+		return spendable, nil
+	}
+
+	// Multiple outputs can belong to the same transaction, so we only
+	// look up each transaction once.
+	ownTxs := make(map[chainhash.Hash]bool)
+	for _, utxo := range utxos {
+		if utxo.Confirmations > 0 {
+			continue
+		}
+
+		if p == SpendUnconfirmedAny {
+			spendable[utxo.OutPoint] = struct{}{}
+			continue
+		}
+
+		ownTx, ok := ownTxs[utxo.Hash]
+		if !ok {
+			tx, err := fetchTx(&utxo.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch "+
+					"transaction %v: %w", utxo.Hash, err)
+			}
+
+			ownTx = spendsOwnOutput(tx)
+			ownTxs[utxo.Hash] = ownTx
+		}
+
+		if ownTx {
+			spendable[utxo.OutPoint] = struct{}{}
+		}
+	}
+
+	return spendable, nil
+}
+
+// spendsOwnOutput returns true if the given transaction spends at least one
+// output controlled by our wallet, which means we created it. Outputs of
+// transactions that only spend foreign outputs were received from others.
+func spendsOwnOutput(tx *TransactionDetail) bool {
+	for _, prevOut := range tx.PreviousOutpoints {
+		if prevOut.IsOurOutput {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSpendUnconfirmedPolicy checks that all policies can be parsed from
+// their string representation.
+func TestParseSpendUnconfirmedPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []SpendUnconfirmedPolicy{
+		SpendUnconfirmedAny, SpendUnconfirmedOwnChangeOnly,
+		SpendUnconfirmedNever,
+	} {
+		parsed, err := ParseSpendUnconfirmedPolicy(policy.String())
+		require.NoError(t, err)
+		require.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseSpendUnconfirmedPolicy("sometimes")
+	require.Error(t, err)
+}
+
+// TestSpendableUnconfirmed checks that the own change only policy only allows
+// spending unconfirmed outputs of transactions that spend our own outputs.
+func TestSpendableUnconfirmed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		confirmedTx = chainhash.Hash{1}
+		changeTx    = chainhash.Hash{2}
+		receivedTx  = chainhash.Hash{3}
+	)
+
+	utxos := []*Utxo{{
+		OutPoint:      wire.OutPoint{Hash: confirmedTx},
+		Confirmations: 3,
+	}, {
+		OutPoint: wire.OutPoint{Hash: changeTx, Index: 1},
+	}, {
+		OutPoint: wire.OutPoint{Hash: changeTx, Index: 2},
+	}, {
+		OutPoint: wire.OutPoint{Hash: receivedTx},
+	}}
+
+	// The change transaction spends one of our outputs and one foreign
+	// output, the received transaction only spends foreign outputs.
+	txs := map[chainhash.Hash]*TransactionDetail{
+		changeTx: {
+			PreviousOutpoints: []PreviousOutPoint{
+				{IsOurOutput: false},
+				{IsOurOutput: true},
+			},
+		},
+		receivedTx: {
+			PreviousOutpoints: []PreviousOutPoint{
+				{IsOurOutput: false},
+			},
+		},
+	}
+	numFetches := 0
+	fetchTx := func(hash *chainhash.Hash) (*TransactionDetail, error) {
+		numFetches++
+		return txs[*hash], nil
+	}
+
+	spendable, err := SpendUnconfirmedAny.SpendableUnconfirmed(
+		utxos, fetchTx,
+	)
+	require.NoError(t, err)
+	require.Len(t, spendable, 3)
+	require.Zero(t, numFetches)
+
+	spendable, err = SpendUnconfirmedNever.SpendableUnconfirmed(
+		utxos, fetchTx,
+	)
+	require.NoError(t, err)
+	require.Empty(t, spendable)
+
+	spendable, err = SpendUnconfirmedOwnChangeOnly.SpendableUnconfirmed(
+		utxos, fetchTx,
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]struct{}{
+		utxos[1].OutPoint: {},
+		utxos[2].OutPoint: {},
+	}, spendable)
+
+	// Each transaction is only looked up once.
+	require.Equal(t, 2, numFetches)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -43,12 +44,17 @@ const (
 	// set fee rate.
 	AnchorChanReservedValue = btcutil.Amount(10_000)
 
+	// MaxAnchorChanReservedFactor is the number of times the per-channel
+	// reserve that we'll reserve at most for anchor channel fee bumping,
+	// such that nodes with a high number of channels don't have to keep
+	// around a very large amount for the unlikely scenario that they all
+	// close at the same time.
+	MaxAnchorChanReservedFactor = 10
+
 	// MaxAnchorChanReservedValue is the maximum value we'll reserve for
-	// anchor channel fee bumping. We cap it at 10 times the per-channel
-	// amount such that nodes with a high number of channels don't have to
-	// keep around a very large amount for the unlikely scenario that they
-	// all close at the same time.
-	MaxAnchorChanReservedValue = 10 * AnchorChanReservedValue
+	// anchor channel fee bumping by default.
+	MaxAnchorChanReservedValue = MaxAnchorChanReservedFactor *
+		AnchorChanReservedValue
 )
 
 var (
@@ -209,6 +215,18 @@ type InitFundingReserveMsg struct {
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// Account is the name of the wallet account whose UTXOs are used to
+	// fund the channel and that receives the change output. If empty, the
+	// default account is used. This does ONLY apply for the internal
+	// wallet backed channel opening case.
+	Account string
+
+	// CoinSelectionStrategy is the strategy used to select the coins that
+	// fund the channel. If nil, the configured strategy of the wallet is
+	// used. This does ONLY apply for the internal wallet backed channel
+	// opening case.
+	CoinSelectionStrategy wallet.CoinSelectionStrategy
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -577,6 +595,58 @@ func (l *LightningWallet) ListUnspentWitnessFromDefaultAccount(
 	)
 }
 
+// ValidateFundingAccount returns an error if channels can't be funded from the
+// wallet account with the given name.
+func (l *LightningWallet) ValidateFundingAccount(account string) error {
+	_, err := l.accountChangeAddrType(account)
+	return err
+}
+
+// accountChangeAddrType returns the address type of change outputs sent back
+// to the given account when funding a channel from it. An error is returned if
+// the account doesn't exist or channels can't be funded from it.
+func (l *LightningWallet) accountChangeAddrType(
+	account string) (AddressType, error) {
+
+	// The default account spans all of our key scopes, so we can use our
+	// preferred change address type.
+	if account == DefaultAccountName {
+		return TaprootPubkey, nil
+	}
+
+	// We can't derive change addresses for the catch-all imported account.
+	if account == waddrmgr.ImportedAddrAccountName {
+		return 0, fmt.Errorf("channels can't be funded from the %q "+
+			"account", account)
+	}
+
+	accounts, err := l.ListAccounts(account, nil)
+	if err != nil {
+		return 0, fmt.Errorf("unable to look up account %q: %w",
+			account, err)
+	}
+	if len(accounts) == 0 {
+		return 0, fmt.Errorf("account %q not found", account)
+	}
+
+	// Custom accounts are bound to a single key scope, which determines
+	// the type of addresses we can derive from them.
+	switch accounts[0].KeyScope {
+	case waddrmgr.KeyScopeBIP0084:
+		return WitnessPubKey, nil
+
+	case waddrmgr.KeyScopeBIP0049Plus:
+		return NestedWitnessPubKey, nil
+
+	case waddrmgr.KeyScopeBIP0086:
+		return TaprootPubkey, nil
+
+	default:
+		return 0, fmt.Errorf("account %q has unsupported key scope %v",
+			account, accounts[0].KeyScope)
+	}
+}
+
 // LockedOutpoints returns a list of all currently locked outpoint.
 func (l *LightningWallet) LockedOutpoints() []*wire.OutPoint {
 	outPoints := make([]*wire.OutPoint, 0, len(l.lockedOutPoints))
@@ -851,15 +921,36 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	// The enforcement still happens at the last step - in PsbtFundingVerify
 	enforceNewReservedValue := true
 
+	// Unless a specific account was requested, the channel is funded from
+	// the default account.
+	account := DefaultAccountName
+	if req.Account != "" {
+		account = req.Account
+	}
+
+	// The change output of the funding transaction is sent back to the
+	// funding account, so we need to know which address type it supports.
+	changeAddrType, err := l.accountChangeAddrType(account)
+	if err != nil {
+		req.err <- err
+		req.resp <- nil
+		return
+	}
+
 	// If no chanFunder was provided, then we'll assume the default
 	// assembler, which is backed by the wallet's internal coin selection.
 	if req.ChanFunder == nil {
+		strategy := l.Cfg.CoinSelectionStrategy
+		if req.CoinSelectionStrategy != nil {
+			strategy = req.CoinSelectionStrategy
+		}
+
 		// We use the P2WSH dust limit since it is larger than the
 		// P2WPKH dust limit and to avoid threading through two
 		// different dust limits.
 		cfg := chanfunding.WalletConfig{
 			CoinSource: NewCoinSource(
-				l, req.AllowUtxoForFunding,
+				l, account, req.AllowUtxoForFunding,
 			),
 			CoinSelectLocker: l,
 			CoinLeaser:       l,
@@ -867,7 +958,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 			DustLimit: DustLimitForSize(
 				input.P2WSHSize,
 			),
-			CoinSelectionStrategy: l.Cfg.CoinSelectionStrategy,
+			CoinSelectionStrategy: strategy,
 		}
 		req.ChanFunder = chanfunding.NewWalletAssembler(cfg)
 	} else {
@@ -879,10 +970,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	remoteFundingAmt := req.RemoteFundingAmt
 	hasAnchors := req.CommitType.HasAnchors()
 
-	var (
-		fundingIntent chanfunding.Intent
-		err           error
-	)
+	var fundingIntent chanfunding.Intent
 
 	// If we've just received an inbound funding request that we have a
 	// registered shim intent to, then we'll obtain the backing intent now.
@@ -942,7 +1030,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 			FeeRate:      req.FundingFeePerKw,
 			ChangeAddr: func() (btcutil.Address, error) {
 				return l.NewAddress(
-					TaprootPubkey, true, DefaultAccountName,
+					changeAddrType, true, account,
 				)
 			},
 			Musig2: req.CommitType == CommitmentTypeSimpleTaproot,
@@ -1157,6 +1245,23 @@ func (l *LightningWallet) CurrentNumAnchorChans() (int, error) {
 	return numAnchors, nil
 }
 
+// AnchorFeeBumpCost returns the fee that is needed to bump a single anchor
+// commitment transaction without any HTLCs to the given fee rate through
+// CPFP. The child transaction is assumed to spend our anchor output together
+// with a single wallet input and to pay to a change output.
+func AnchorFeeBumpCost(feeRate chainfee.SatPerKWeight) btcutil.Amount {
+	var estimator input.TxWeightEstimator
+	estimator.AddWitnessInput(input.AnchorWitnessSize)
+	estimator.AddP2WKHInput()
+	estimator.AddP2TROutput()
+
+	// The child needs to pay for the whole package, so we add the weight
+	// of the parent commitment transaction.
+	packageWeight := estimator.Weight() + input.AnchorCommitWeight
+
+	return feeRate.FeeForWeight(packageWeight)
+}
+
 // CheckReservedValue checks whether publishing a transaction with the given
 // inputs and outputs would violate the value we reserve in the wallet for
 // bumping the fee of anchor channels. The numAnchorChans argument should be
@@ -2534,16 +2639,22 @@ func (l *LightningWallet) CancelRebroadcast(txid chainhash.Hash) {
 }
 
 // CoinSource is a wrapper around the wallet that implements the
-// chanfunding.CoinSource interface.
+// chanfunding.CoinSource interface. Only coins of a single wallet account are
+// offered.
 type CoinSource struct {
 	wallet    *LightningWallet
+	account   string
 	allowUtxo func(Utxo) bool
 }
 
-// NewCoinSource creates a new instance of the CoinSource wrapper struct.
-func NewCoinSource(w *LightningWallet, allowUtxo func(Utxo) bool) *CoinSource {
+// NewCoinSource creates a new instance of the CoinSource wrapper struct that
+// lists the coins of the given wallet account.
+func NewCoinSource(w *LightningWallet, account string,
+	allowUtxo func(Utxo) bool) *CoinSource {
+
 	return &CoinSource{
 		wallet:    w,
+		account:   account,
 		allowUtxo: allowUtxo,
 	}
 }
@@ -2553,8 +2664,18 @@ func NewCoinSource(w *LightningWallet, allowUtxo func(Utxo) bool) *CoinSource {
 func (c *CoinSource) ListCoins(minConfs int32,
 	maxConfs int32) ([]wallet.Coin, error) {
 
-	utxos, err := c.wallet.ListUnspentWitnessFromDefaultAccount(
-		minConfs, maxConfs,
+	utxos, err := c.wallet.ListUnspentWitness(
+		minConfs, maxConfs, c.account,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unconfirmed coins are only offered if the spend unconfirmed policy
+	// allows it.
+	policy := c.wallet.Cfg.SpendUnconfirmed
+	spendableUnconfirmed, err := policy.SpendableUnconfirmed(
+		utxos, c.wallet.GetTransactionDetails,
 	)
 	if err != nil {
 		return nil, err
@@ -2563,6 +2684,15 @@ func (c *CoinSource) ListCoins(minConfs int32,
 	var coins []wallet.Coin
 
 	for _, utxo := range utxos {
+		_, spendable := spendableUnconfirmed[utxo.OutPoint]
+		if utxo.Confirmations == 0 && !spendable {
+			walletLog.Debugf("Cannot use unconfirmed utxo=%v due "+
+				"to spend unconfirmed policy %v",
+				utxo.OutPoint, policy)
+
+			continue
+		}
+
 		// If there is a filter function supplied all utxos not adhering
 		// to these conditions will be discarded.
 		if c.allowUtxo != nil && !c.allowUtxo(*utxo) {
@@ -1,8 +1,12 @@
 package lnwallet
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,3 +36,86 @@ func TestRegisterFundingIntent(t *testing.T) {
 	err = lw.RegisterFundingIntent(testID, nil)
 	require.ErrorIs(err, ErrDuplicatePendingChanID)
 }
+
+// TestAnchorFeeBumpCost checks that the fee needed to bump an anchor
+// commitment scales with the fee rate and covers the parent's weight.
+func TestAnchorFeeBumpCost(t *testing.T) {
+	t.Parallel()
+
+	feeRate := chainfee.SatPerKWeight(1000)
+	cost := AnchorFeeBumpCost(feeRate)
+
+	// The child alone has to at least pay for the commitment.
+	minCost := feeRate.FeeForWeight(input.AnchorCommitWeight)
+	require.Greater(t, cost, minCost)
+
+	// Doubling the fee rate should double the cost.
+	require.Equal(t, 2*cost, AnchorFeeBumpCost(2*feeRate))
+}
+
+// accountsWalletController is a WalletController that only knows about a fixed
+// set of accounts.
+type accountsWalletController struct {
+	WalletController
+
+	accounts map[string]*waddrmgr.AccountProperties
+}
+
+// ListAccounts returns the account with the given name, if known.
+func (a *accountsWalletController) ListAccounts(name string,
+	_ *waddrmgr.KeyScope) ([]*waddrmgr.AccountProperties, error) {
+
+	account, ok := a.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("account %v not found", name)
+	}
+
+	return []*waddrmgr.AccountProperties{account}, nil
+}
+
+// TestAccountChangeAddrType checks that channels can only be funded from known
+// accounts and that change is sent to an address type the account supports.
+func TestAccountChangeAddrType(t *testing.T) {
+	t.Parallel()
+
+	accounts := map[string]*waddrmgr.AccountProperties{
+		"segwit":  {KeyScope: waddrmgr.KeyScopeBIP0084},
+		"nested":  {KeyScope: waddrmgr.KeyScopeBIP0049Plus},
+		"taproot": {KeyScope: waddrmgr.KeyScopeBIP0086},
+		"legacy":  {KeyScope: waddrmgr.KeyScopeBIP0044},
+	}
+	lw, err := NewLightningWallet(Config{
+		WalletController: &accountsWalletController{
+			accounts: accounts,
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		account     string
+		expAddrType AddressType
+		expErr      bool
+	}{
+		{account: DefaultAccountName, expAddrType: TaprootPubkey},
+		{account: "segwit", expAddrType: WitnessPubKey},
+		{account: "nested", expAddrType: NestedWitnessPubKey},
+		{account: "taproot", expAddrType: TaprootPubkey},
+		{account: "legacy", expErr: true},
+		{account: "unknown", expErr: true},
+		{account: waddrmgr.ImportedAddrAccountName, expErr: true},
+	}
+
+	for _, tc := range testCases {
+		addrType, err := lw.accountChangeAddrType(tc.account)
+		if tc.expErr {
+			require.Error(t, err, tc.account)
+			require.Error(t, lw.ValidateFundingAccount(tc.account))
+
+			continue
+		}
+
+		require.NoError(t, err, tc.account)
+		require.Equal(t, tc.expAddrType, addrType, tc.account)
+		require.NoError(t, lw.ValidateFundingAccount(tc.account))
+	}
+}
@@ -235,6 +235,17 @@ func FuzzGossipTimestampRange(f *testing.F) {
 	})
 }
 
+func FuzzOnionMessage(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgOnionMessage.
+		data = prefixWithMsgType(data, MsgOnionMessage)
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data)
+	})
+}
+
 func FuzzInit(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Prefix with MsgInit.
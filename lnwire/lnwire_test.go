@@ -1337,6 +1337,18 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			pathKey, err := randPubKey()
+			require.NoError(t, err)
+
+			onionBlob := make([]byte, r.Intn(MaxMsgBody-35))
+			_, err = r.Read(onionBlob)
+			require.NoError(t, err)
+
+			v[0] = reflect.ValueOf(*NewOnionMessage(
+				pathKey, onionBlob,
+			))
+		},
 		MsgUpdateAddHTLC: func(v []reflect.Value, r *rand.Rand) {
 			req := &UpdateAddHTLC{
 				ID:     r.Uint64(),
@@ -1583,6 +1595,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgClosingComplete,
 			scenario: func(m ClosingComplete) bool {
@@ -56,6 +56,7 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgOnionMessage                        = 513
 	MsgKickoffSig                          = 777
 )
 
@@ -148,6 +149,8 @@ func (t MessageType) String() string {
 		return "ReplyChannelRange"
 	case MsgGossipTimestampRange:
 		return "GossipTimestampRange"
+	case MsgOnionMessage:
+		return "OnionMessage"
 	case MsgClosingComplete:
 		return "ClosingComplete"
 	case MsgClosingSig:
@@ -275,6 +278,8 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &ReplyChannelRange{}
 	case MsgGossipTimestampRange:
 		msg = &GossipTimestampRange{}
+	case MsgOnionMessage:
+		msg = &OnionMessage{}
 	case MsgClosingComplete:
 		msg = &ClosingComplete{}
 	case MsgClosingSig:
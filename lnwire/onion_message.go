@@ -0,0 +1,77 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessage is sent to relay an onion message through the network. Unlike
+// an HTLC, an onion message isn't bound to a channel and carries no value. Each
+// hop peels off one layer of the onion to learn the next hop of the message.
+type OnionMessage struct {
+	// PathKey is the ephemeral key of the blinded path the receiving node
+	// uses to decrypt its layer of the onion and the data the creator of
+	// the blinded path left for it.
+	PathKey *btcec.PublicKey
+
+	// OnionBlob is the serialized onion message packet.
+	OnionBlob []byte
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// NewOnionMessage creates a new OnionMessage.
+func NewOnionMessage(pathKey *btcec.PublicKey,
+	onionBlob []byte) *OnionMessage {
+
+	return &OnionMessage{
+		PathKey:   pathKey,
+		OnionBlob: onionBlob,
+	}
+}
+
+// Decode deserializes a serialized OnionMessage stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, _ uint32) error {
+	if err := ReadElement(r, &o.PathKey); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+	blobLen := binary.BigEndian.Uint16(l[:])
+
+	o.OnionBlob = make([]byte, blobLen)
+	_, err := io.ReadFull(r, o.OnionBlob)
+
+	return err
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, _ uint32) error {
+	if err := WritePublicKey(w, o.PathKey); err != nil {
+		return err
+	}
+
+	return writeDataWithLength(w, o.OnionBlob)
+}
+
+// MsgType returns the integer uniquely identifying an OnionMessage on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
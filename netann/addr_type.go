@@ -0,0 +1,104 @@
+package netann
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+// AddrType is the type of an address that can be advertised in a node
+// announcement.
+type AddrType string
+
+const (
+	// AddrTypeIPv4 is the type of clearnet IPv4 addresses.
+	AddrTypeIPv4 AddrType = "ipv4"
+
+	// AddrTypeIPv6 is the type of clearnet IPv6 addresses.
+	AddrTypeIPv6 AddrType = "ipv6"
+
+	// AddrTypeTorV2 is the type of Tor v2 onion addresses.
+	AddrTypeTorV2 AddrType = "torv2"
+
+	// AddrTypeTorV3 is the type of Tor v3 onion addresses.
+	AddrTypeTorV3 AddrType = "torv3"
+
+	// AddrTypeOpaque is the type of addresses lnd doesn't know how to
+	// interpret.
+	AddrTypeOpaque AddrType = "opaque"
+)
+
+// IsClearnet returns true if addresses of this type reveal the network
+// location of the node.
+func (t AddrType) IsClearnet() bool {
+	return t == AddrTypeIPv4 || t == AddrTypeIPv6
+}
+
+// ParseAddrType parses the name of an address type.
+func ParseAddrType(name string) (AddrType, error) {
+	addrType := AddrType(name)
+	switch addrType {
+	case AddrTypeIPv4, AddrTypeIPv6, AddrTypeTorV2, AddrTypeTorV3,
+		AddrTypeOpaque:
+
+		return addrType, nil
+
+	default:
+		return "", fmt.Errorf("unknown address type %q, must be one "+
+			"of %v, %v, %v, %v or %v", name, AddrTypeIPv4,
+			AddrTypeIPv6, AddrTypeTorV2, AddrTypeTorV3,
+			AddrTypeOpaque)
+	}
+}
+
+// AddrTypeOf returns the type of the given address.
+func AddrTypeOf(addr net.Addr) AddrType {
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		if addr.IP.To4() != nil {
+			return AddrTypeIPv4
+		}
+
+		return AddrTypeIPv6
+
+	case *tor.OnionAddr:
+		if len(addr.OnionService) == tor.V2Len {
+			return AddrTypeTorV2
+		}
+
+		return AddrTypeTorV3
+
+	default:
+		return AddrTypeOpaque
+	}
+}
+
+// FilterAddrs splits the given addresses into those with one of the allowed
+// types and those that must not be advertised. If allowed is empty, all
+// addresses are allowed.
+func FilterAddrs(addrs []net.Addr, allowed []AddrType) ([]net.Addr,
+	[]net.Addr) {
+
+	if len(allowed) == 0 {
+		return addrs, nil
+	}
+
+	allowedTypes := make(map[AddrType]struct{}, len(allowed))
+	for _, addrType := range allowed {
+		allowedTypes[addrType] = struct{}{}
+	}
+
+	kept := make([]net.Addr, 0, len(addrs))
+	var dropped []net.Addr
+	for _, addr := range addrs {
+		if _, ok := allowedTypes[AddrTypeOf(addr)]; !ok {
+			dropped = append(dropped, addr)
+			continue
+		}
+
+		kept = append(kept, addr)
+	}
+
+	return kept, dropped
+}
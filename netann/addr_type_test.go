@@ -0,0 +1,72 @@
+package netann
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testIPv4Addr = &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 9735}
+	testIPv6Addr = &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9735}
+
+	testTorV2Addr = &tor.OnionAddr{
+		OnionService: strings.Repeat("a", 16) + tor.OnionSuffix,
+		Port:         9735,
+	}
+	testTorV3Addr = &tor.OnionAddr{
+		OnionService: strings.Repeat("b", 56) + tor.OnionSuffix,
+		Port:         9735,
+	}
+
+	testOpaqueAddr = &lnwire.OpaqueAddrs{Payload: []byte{0xff}}
+)
+
+// TestAddrTypeOf tests that addresses are classified by their type.
+func TestAddrTypeOf(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, AddrTypeIPv4, AddrTypeOf(testIPv4Addr))
+	require.Equal(t, AddrTypeIPv6, AddrTypeOf(testIPv6Addr))
+	require.Equal(t, AddrTypeTorV2, AddrTypeOf(testTorV2Addr))
+	require.Equal(t, AddrTypeTorV3, AddrTypeOf(testTorV3Addr))
+	require.Equal(t, AddrTypeOpaque, AddrTypeOf(testOpaqueAddr))
+
+	_, err := ParseAddrType("ipv5")
+	require.Error(t, err)
+
+	addrType, err := ParseAddrType("torv3")
+	require.NoError(t, err)
+	require.Equal(t, AddrTypeTorV3, addrType)
+	require.False(t, addrType.IsClearnet())
+	require.True(t, AddrTypeIPv6.IsClearnet())
+}
+
+// TestFilterAddrs tests that only addresses of the allowed types are kept.
+func TestFilterAddrs(t *testing.T) {
+	t.Parallel()
+
+	addrs := []net.Addr{
+		testIPv4Addr, testTorV3Addr, testIPv6Addr, testTorV2Addr,
+		testOpaqueAddr,
+	}
+
+	// Without any allowed types, all addresses are kept.
+	kept, dropped := FilterAddrs(addrs, nil)
+	require.Equal(t, addrs, kept)
+	require.Empty(t, dropped)
+
+	// Only allowing onion addresses drops all clearnet addresses.
+	kept, dropped = FilterAddrs(
+		addrs, []AddrType{AddrTypeTorV2, AddrTypeTorV3},
+	)
+	require.Equal(t, []net.Addr{testTorV3Addr, testTorV2Addr}, kept)
+	require.Equal(
+		t, []net.Addr{testIPv4Addr, testIPv6Addr, testOpaqueAddr},
+		dropped,
+	)
+}
@@ -1,15 +1,25 @@
 package netann
 
 import (
+	"bytes"
+	"fmt"
 	"image/color"
 	"net"
 	"time"
 
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
+// MaxNodeAnnExtraDataSize is the maximum size of the extra TLV records we
+// append to our own node announcement. It matches the maximum amount of extra
+// data the graph stores for a node.
+const MaxNodeAnnExtraDataSize = channeldb.MaxAllowedExtraOpaqueBytes
+
 // NodeAnnModifier is a closure that makes in-place modifications to an
 // lnwire.NodeAnnouncement.
 type NodeAnnModifier func(*lnwire.NodeAnnouncement)
@@ -46,6 +56,64 @@ func NodeAnnSetFeatures(features *lnwire.RawFeatureVector) func(*lnwire.NodeAnno
 	}
 }
 
+// NodeAnnSetExtraData is a functional option that sets the extra opaque data
+// appended to the given node announcement.
+func NodeAnnSetExtraData(
+	data lnwire.ExtraOpaqueData) func(*lnwire.NodeAnnouncement) {
+
+	return func(nodeAnn *lnwire.NodeAnnouncement) {
+		nodeAnn.ExtraOpaqueData = data
+	}
+}
+
+// EncodeNodeAnnExtraRecords encodes the given custom records as a TLV stream
+// that can be appended to a node announcement. All records must use types in
+// the custom range, and the encoded stream must not exceed
+// MaxNodeAnnExtraDataSize.
+func EncodeNodeAnnExtraRecords(
+	records record.CustomSet) (lnwire.ExtraOpaqueData, error) {
+
+	if err := records.Validate(); err != nil {
+		return nil, err
+	}
+
+	tlvStream, err := tlv.NewStream(tlv.MapToRecords(records)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	if b.Len() > MaxNodeAnnExtraDataSize {
+		return nil, fmt.Errorf("node announcement TLV records of %v "+
+			"bytes exceed maximum of %v bytes", b.Len(),
+			MaxNodeAnnExtraDataSize)
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeNodeAnnExtraRecords decodes the TLV records appended to a node
+// announcement.
+func DecodeNodeAnnExtraRecords(
+	data lnwire.ExtraOpaqueData) (record.CustomSet, error) {
+
+	typeMap, err := data.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(record.CustomSet, len(typeMap))
+	for recordType, value := range typeMap {
+		records[uint64(recordType)] = value
+	}
+
+	return records, nil
+}
+
 // NodeAnnSetTimestamp is a functional option that sets the timestamp of the
 // announcement to the current time, or increments it if the timestamp is
 // already in the future.
@@ -0,0 +1,72 @@
+package netann
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodeAnnExtraRecords tests that custom TLV records can be encoded into
+// the extra data of a node announcement and decoded again, and that records
+// outside the custom range or exceeding the size limit are rejected.
+func TestNodeAnnExtraRecords(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		records record.CustomSet
+		err     bool
+	}{
+		{
+			name:    "no records",
+			records: record.CustomSet{},
+		},
+		{
+			name: "custom records",
+			records: record.CustomSet{
+				record.CustomTypeStart:     []byte("endpoint"),
+				record.CustomTypeStart + 1: []byte{},
+				1 << 40:                    []byte{0x01, 0x02},
+			},
+		},
+		{
+			name: "type below custom range",
+			records: record.CustomSet{
+				record.CustomTypeStart - 1: []byte{0x01},
+			},
+			err: true,
+		},
+		{
+			name: "records too large",
+			records: record.CustomSet{
+				record.CustomTypeStart: make(
+					[]byte, MaxNodeAnnExtraDataSize,
+				),
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			extraData, err := EncodeNodeAnnExtraRecords(tc.records)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.LessOrEqual(
+				t, len(extraData), MaxNodeAnnExtraDataSize,
+			)
+
+			records, err := DecodeNodeAnnExtraRecords(extraData)
+			require.NoError(t, err)
+			require.Equal(t, tc.records, records)
+		})
+	}
+}
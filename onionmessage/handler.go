@@ -0,0 +1,339 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+	"golang.org/x/time/rate"
+)
+
+// RelayMode determines which onion messages are relayed.
+type RelayMode string
+
+const (
+	// RelayOff disables relaying onion messages.
+	RelayOff RelayMode = "off"
+
+	// RelayPeers only relays onion messages that are received from and
+	// forwarded to peers we have a channel with.
+	RelayPeers RelayMode = "peers"
+
+	// RelayAll relays onion messages between any connected peers.
+	RelayAll RelayMode = "all"
+)
+
+const (
+	// encryptedDataType is the type of the onion message payload record
+	// that carries the data the creator of the blinded path encrypted for
+	// us.
+	encryptedDataType tlv.Type = 4
+
+	// nextSCIDType is the type of the encrypted data record that
+	// identifies the next hop by the short channel ID of a channel with
+	// it.
+	nextSCIDType tlv.Type = 2
+
+	// nextNodeIDType is the type of the encrypted data record that
+	// identifies the next hop by its node ID.
+	nextNodeIDType tlv.Type = 4
+
+	// nextPathKeyOverrideType is the type of the encrypted data record
+	// that replaces the path key passed on to the next hop.
+	nextPathKeyOverrideType tlv.Type = 8
+)
+
+var (
+	// ErrRelayDisabled is returned when an onion message is received while
+	// relaying onion messages is disabled.
+	ErrRelayDisabled = errors.New("onion message relay disabled")
+
+	// ErrRateLimited is returned when a peer exceeded its onion message
+	// rate limit.
+	ErrRateLimited = errors.New("onion message rate limit exceeded")
+
+	// ErrNoChannel is returned in the RelayPeers mode when an onion
+	// message is received from or destined to a peer we don't have a
+	// channel with.
+	ErrNoChannel = errors.New("no channel with peer")
+
+	// ErrUnsupportedPacketSize is returned when the onion message packet
+	// isn't of the size of a regular onion packet. Larger packets are
+	// dropped to bound the resources spent on a single message.
+	ErrUnsupportedPacketSize = errors.New("unsupported onion message " +
+		"packet size")
+
+	// ErrFinalHop is returned when we're the recipient of the onion
+	// message. Onion messages addressed to us aren't handled yet.
+	ErrFinalHop = errors.New("onion message addressed to us")
+)
+
+// OnionRouter peels off our layer of onion message packets. It is
+// implemented by *sphinx.Router.
+type OnionRouter interface {
+	// ReconstructOnionPacket processes our layer of the onion packet
+	// without persisting its shared secret for replay protection. Onion
+	// messages carry no value, so they don't need to be protected against
+	// replays.
+	ReconstructOnionPacket(onionPkt *sphinx.OnionPacket, assocData []byte,
+		opts ...sphinx.ProcessOnionOpt) (*sphinx.ProcessedPacket,
+		error)
+
+	// DecryptBlindedHopData decrypts the data the creator of the blinded
+	// path encrypted for us.
+	DecryptBlindedHopData(ephemPub *btcec.PublicKey,
+		encryptedData []byte) ([]byte, error)
+
+	// NextEphemeral derives the path key of the next hop.
+	NextEphemeral(ephemPub *btcec.PublicKey) (*btcec.PublicKey, error)
+}
+
+// Config houses the parameters of the onion message Handler.
+type Config struct {
+	// Relay determines which onion messages are relayed.
+	Relay RelayMode
+
+	// PeerRateLimit is the number of onion messages per second that are
+	// relayed for a single peer.
+	PeerRateLimit rate.Limit
+
+	// PeerBurst is the number of onion messages a single peer may send at
+	// once before its messages are rate limited.
+	PeerBurst int
+
+	// Router peels off our layer of onion message packets.
+	Router OnionRouter
+
+	// HasChannel returns true if we have a channel with the given peer.
+	HasChannel func(peer route.Vertex) bool
+
+	// ChannelPeer returns the peer we have the channel with the given
+	// short channel ID with.
+	ChannelPeer func(scid lnwire.ShortChannelID) (route.Vertex, error)
+
+	// SendMessage queues the onion message for delivery to the given peer.
+	// It fails if we're not connected to the peer.
+	SendMessage func(peer route.Vertex, msg *lnwire.OnionMessage) error
+}
+
+// Handler relays onion messages received from our peers according to the
+// configured relay policy. Each peer is rate limited individually, messages
+// exceeding its limit are dropped.
+type Handler struct {
+	cfg *Config
+
+	// limiters holds the rate limiter of each peer that sent us an onion
+	// message.
+	limiters map[route.Vertex]*rate.Limiter
+
+	mu sync.Mutex
+}
+
+// New creates a new onion message Handler.
+func New(cfg *Config) *Handler {
+	return &Handler{
+		cfg:      cfg,
+		limiters: make(map[route.Vertex]*rate.Limiter),
+	}
+}
+
+// HandleMessage processes an onion message received from the given peer and
+// relays it to the next hop. An error is returned if the message was dropped.
+func (h *Handler) HandleMessage(from route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	if h.cfg.Relay == RelayOff {
+		return ErrRelayDisabled
+	}
+
+	// Check the rate limit first, so a flood of messages doesn't cost us
+	// any database lookups or onion processing.
+	if !h.limiter(from).Allow() {
+		return ErrRateLimited
+	}
+
+	if h.cfg.Relay == RelayPeers && !h.cfg.HasChannel(from) {
+		return ErrNoChannel
+	}
+
+	if len(msg.OnionBlob) != lnwire.OnionPacketSize {
+		return fmt.Errorf("%w: %v bytes", ErrUnsupportedPacketSize,
+			len(msg.OnionBlob))
+	}
+
+	next, nextMsg, err := h.peel(msg)
+	if err != nil {
+		return err
+	}
+
+	if h.cfg.Relay == RelayPeers && !h.cfg.HasChannel(next) {
+		return ErrNoChannel
+	}
+
+	if err := h.cfg.SendMessage(next, nextMsg); err != nil {
+		return fmt.Errorf("unable to relay onion message to %v: %w",
+			next, err)
+	}
+
+	log.Tracef("Relayed onion message from %v to %v", from, next)
+
+	return nil
+}
+
+// RemovePeer removes the rate limiter of the given peer. It should be called
+// once the peer disconnected.
+func (h *Handler) RemovePeer(peer route.Vertex) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.limiters, peer)
+}
+
+// limiter returns the rate limiter of the given peer, creating it if it
+// doesn't exist yet.
+func (h *Handler) limiter(peer route.Vertex) *rate.Limiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	limiter, ok := h.limiters[peer]
+	if !ok {
+		limiter = rate.NewLimiter(h.cfg.PeerRateLimit, h.cfg.PeerBurst)
+		h.limiters[peer] = limiter
+	}
+
+	return limiter
+}
+
+// peel removes our layer of the onion message and returns the next hop along
+// with the message to send to it.
+func (h *Handler) peel(msg *lnwire.OnionMessage) (route.Vertex,
+	*lnwire.OnionMessage, error) {
+
+	var pkt sphinx.OnionPacket
+	err := pkt.Decode(bytes.NewReader(msg.OnionBlob))
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("invalid onion "+
+			"packet: %w", err)
+	}
+
+	processed, err := h.cfg.Router.ReconstructOnionPacket(
+		&pkt, nil, sphinx.WithBlindingPoint(msg.PathKey),
+	)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("unable to process "+
+			"onion packet: %w", err)
+	}
+
+	if processed.Action == sphinx.ExitNode {
+		return route.Vertex{}, nil, ErrFinalHop
+	}
+
+	// The payload of each hop must carry the data the creator of the
+	// blinded path encrypted for it, which tells us where to relay the
+	// message to.
+	var encData []byte
+	payloadStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(encryptedDataType, &encData),
+	)
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+	parsed, err := payloadStream.DecodeWithParsedTypes(
+		bytes.NewReader(processed.Payload.Payload),
+	)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("invalid onion "+
+			"message payload: %w", err)
+	}
+	if _, ok := parsed[encryptedDataType]; !ok {
+		return route.Vertex{}, nil, errors.New("onion message " +
+			"payload without encrypted data")
+	}
+
+	data, err := h.cfg.Router.DecryptBlindedHopData(msg.PathKey, encData)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("unable to decrypt "+
+			"blinded data: %w", err)
+	}
+
+	next, nextPathKey, err := h.parseBlindedData(data)
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	// If the creator of the blinded path didn't override the path key of
+	// the next hop, we'll derive it from ours.
+	if nextPathKey == nil {
+		nextPathKey, err = h.cfg.Router.NextEphemeral(msg.PathKey)
+		if err != nil {
+			return route.Vertex{}, nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	if err := processed.NextPacket.Encode(&b); err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	return next, lnwire.NewOnionMessage(nextPathKey, b.Bytes()), nil
+}
+
+// parseBlindedData parses the decrypted data the creator of the blinded path
+// left for us and returns the next hop along with the path key override, if
+// any.
+func (h *Handler) parseBlindedData(data []byte) (route.Vertex,
+	*btcec.PublicKey, error) {
+
+	var (
+		scid            uint64
+		nextNodeID      *btcec.PublicKey
+		pathKeyOverride *btcec.PublicKey
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(nextSCIDType, &scid),
+		tlv.MakePrimitiveRecord(nextNodeIDType, &nextNodeID),
+		tlv.MakePrimitiveRecord(
+			nextPathKeyOverrideType, &pathKeyOverride,
+		),
+	)
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+	parsed, err := stream.DecodeWithParsedTypes(bytes.NewReader(data))
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("invalid blinded "+
+			"data: %w", err)
+	}
+
+	var next route.Vertex
+	_, hasNodeID := parsed[nextNodeIDType]
+	_, hasSCID := parsed[nextSCIDType]
+	switch {
+	case hasNodeID:
+		next = route.NewVertex(nextNodeID)
+
+	case hasSCID:
+		chanID := lnwire.NewShortChanIDFromInt(scid)
+		next, err = h.cfg.ChannelPeer(chanID)
+		if err != nil {
+			return route.Vertex{}, nil, fmt.Errorf("unknown next "+
+				"channel %v: %w", chanID, err)
+		}
+
+	default:
+		return route.Vertex{}, nil, errors.New("blinded data without " +
+			"next hop")
+	}
+
+	if _, ok := parsed[nextPathKeyOverrideType]; !ok {
+		pathKeyOverride = nil
+	}
+
+	return next, pathKeyOverride, nil
+}
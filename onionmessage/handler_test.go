@@ -0,0 +1,376 @@
+package onionmessage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// testNode is a node taking part in the relay of an onion message.
+type testNode struct {
+	priv   *btcec.PrivateKey
+	router *sphinx.Router
+}
+
+func newTestNode(t *testing.T) *testNode {
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &testNode{
+		priv: priv,
+		router: sphinx.NewRouter(
+			&keychain.PrivKeyECDH{PrivKey: priv},
+			&chaincfg.RegressionNetParams,
+			sphinx.NewMemoryReplayLog(),
+		),
+	}
+}
+
+func (n *testNode) vertex() route.Vertex {
+	return route.NewVertex(n.priv.PubKey())
+}
+
+// encodeRecords encodes the given records as a TLV stream.
+func encodeRecords(t *testing.T, records ...tlv.Record) []byte {
+	t.Helper()
+
+	stream, err := tlv.NewStream(records...)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, stream.Encode(&b))
+
+	return b.Bytes()
+}
+
+// buildOnionMessage creates an onion message that is relayed by the relay
+// node according to the given blinded data and then delivered to the
+// recipient. The data left for the recipient is returned as well.
+func buildOnionMessage(t *testing.T, relay, recipient *testNode,
+	relayData []byte) (*lnwire.OnionMessage, []byte) {
+
+	t.Helper()
+
+	pathID := []byte("path id of the recipient")
+	recipientData := encodeRecords(
+		t, tlv.MakePrimitiveRecord(tlv.Type(6), &pathID),
+	)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// The plaintexts are encrypted in place, so we pass copies.
+	blindedPath, err := sphinx.BuildBlindedPath(
+		sessionKey, []*sphinx.HopInfo{
+			{
+				NodePub:   relay.priv.PubKey(),
+				PlainText: append([]byte(nil), relayData...),
+			},
+			{
+				NodePub: recipient.priv.PubKey(),
+				PlainText: append(
+					[]byte(nil), recipientData...,
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	var path sphinx.PaymentPath
+	for i, hop := range blindedPath.BlindedHops {
+		cipherText := hop.CipherText
+		payload, err := sphinx.NewTLVHopPayload(encodeRecords(
+			t, tlv.MakePrimitiveRecord(
+				encryptedDataType, &cipherText,
+			),
+		))
+		require.NoError(t, err)
+
+		path[i] = sphinx.OnionHop{
+			NodePub:    *hop.BlindedNodePub,
+			HopPayload: payload,
+		}
+	}
+
+	onionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pkt, err := sphinx.NewOnionPacket(
+		&path, onionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, pkt.Encode(&b))
+
+	msg := lnwire.NewOnionMessage(blindedPath.BlindingPoint, b.Bytes())
+
+	return msg, recipientData
+}
+
+// sentMessage is an onion message passed to the SendMessage callback.
+type sentMessage struct {
+	peer route.Vertex
+	msg  *lnwire.OnionMessage
+}
+
+// newTestHandler creates a handler for the given node that relays messages to
+// any connected peer, records the sent messages and allows a burst of ten
+// messages per peer.
+func newTestHandler(node *testNode, connected map[route.Vertex]bool,
+	sent *[]sentMessage) (*Handler, *Config) {
+
+	cfg := &Config{
+		Relay:         RelayAll,
+		PeerRateLimit: rate.Limit(1),
+		PeerBurst:     10,
+		Router:        node.router,
+		HasChannel: func(peer route.Vertex) bool {
+			return false
+		},
+		ChannelPeer: func(lnwire.ShortChannelID) (route.Vertex,
+			error) {
+
+			return route.Vertex{}, errors.New("unknown channel")
+		},
+		SendMessage: func(peer route.Vertex,
+			msg *lnwire.OnionMessage) error {
+
+			if !connected[peer] {
+				return errors.New("peer not connected")
+			}
+
+			*sent = append(*sent, sentMessage{peer, msg})
+
+			return nil
+		},
+	}
+
+	return New(cfg), cfg
+}
+
+// TestHandlerRelay tests that an onion message is relayed to the next hop
+// identified by its node ID or the short channel ID of a channel with it,
+// and that the recipient is able to process the relayed message.
+func TestHandlerRelay(t *testing.T) {
+	t.Parallel()
+
+	sender := newTestNode(t)
+	relay := newTestNode(t)
+	recipient := newTestNode(t)
+
+	nextNodeID := recipient.priv.PubKey()
+	scid := lnwire.NewShortChanIDFromInt(123456)
+	scidInt := scid.ToUint64()
+
+	testCases := []struct {
+		name      string
+		relayData []byte
+	}{
+		{
+			name: "next node id",
+			relayData: encodeRecords(t, tlv.MakePrimitiveRecord(
+				nextNodeIDType, &nextNodeID,
+			)),
+		},
+		{
+			name: "next short channel id",
+			relayData: encodeRecords(t, tlv.MakePrimitiveRecord(
+				nextSCIDType, &scidInt,
+			)),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sent []sentMessage
+			connected := map[route.Vertex]bool{
+				recipient.vertex(): true,
+			}
+			h, cfg := newTestHandler(relay, connected, &sent)
+			cfg.ChannelPeer = func(id lnwire.ShortChannelID) (
+				route.Vertex, error) {
+
+				require.Equal(t, scid, id)
+				return recipient.vertex(), nil
+			}
+
+			msg, recipientData := buildOnionMessage(
+				t, relay, recipient, tc.relayData,
+			)
+			err := h.HandleMessage(sender.vertex(), msg)
+			require.NoError(t, err)
+
+			require.Len(t, sent, 1)
+			require.Equal(t, recipient.vertex(), sent[0].peer)
+
+			// The recipient must be able to process the relayed
+			// message and decrypt the data left for it.
+			relayed := sent[0].msg
+			var pkt sphinx.OnionPacket
+			err = pkt.Decode(bytes.NewReader(relayed.OnionBlob))
+			require.NoError(t, err)
+
+			router := recipient.router
+			processed, err := router.ReconstructOnionPacket(
+				&pkt, nil,
+				sphinx.WithBlindingPoint(relayed.PathKey),
+			)
+			require.NoError(t, err)
+			require.Equal(
+				t, sphinx.ProcessCode(sphinx.ExitNode),
+				processed.Action,
+			)
+
+			var encData []byte
+			stream, err := tlv.NewStream(tlv.MakePrimitiveRecord(
+				encryptedDataType, &encData,
+			))
+			require.NoError(t, err)
+			err = stream.Decode(
+				bytes.NewReader(processed.Payload.Payload),
+			)
+			require.NoError(t, err)
+
+			data, err := recipient.router.DecryptBlindedHopData(
+				relayed.PathKey, encData,
+			)
+			require.NoError(t, err)
+			require.Equal(t, recipientData, data)
+
+			// As the recipient, a handler drops the message.
+			var recipientSent []sentMessage
+			recipientHandler, _ := newTestHandler(
+				recipient, nil, &recipientSent,
+			)
+			err = recipientHandler.HandleMessage(
+				relay.vertex(), relayed,
+			)
+			require.ErrorIs(t, err, ErrFinalHop)
+			require.Empty(t, recipientSent)
+		})
+	}
+}
+
+// TestHandlerPolicy tests that onion messages violating the relay policy are
+// dropped.
+func TestHandlerPolicy(t *testing.T) {
+	t.Parallel()
+
+	sender := newTestNode(t)
+	relay := newTestNode(t)
+	recipient := newTestNode(t)
+
+	nextNodeID := recipient.priv.PubKey()
+	relayData := encodeRecords(t, tlv.MakePrimitiveRecord(
+		nextNodeIDType, &nextNodeID,
+	))
+
+	newHandler := func() (*Handler, *Config, *[]sentMessage) {
+		var sent []sentMessage
+		connected := map[route.Vertex]bool{
+			recipient.vertex(): true,
+		}
+		h, cfg := newTestHandler(relay, connected, &sent)
+
+		return h, cfg, &sent
+	}
+
+	t.Run("relay disabled", func(t *testing.T) {
+		h, cfg, sent := newHandler()
+		cfg.Relay = RelayOff
+
+		msg, _ := buildOnionMessage(t, relay, recipient, relayData)
+		err := h.HandleMessage(sender.vertex(), msg)
+		require.ErrorIs(t, err, ErrRelayDisabled)
+		require.Empty(t, *sent)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		h, cfg, sent := newHandler()
+		cfg.PeerRateLimit = rate.Every(time.Hour)
+		cfg.PeerBurst = 2
+
+		msg, _ := buildOnionMessage(t, relay, recipient, relayData)
+		for i := 0; i < cfg.PeerBurst; i++ {
+			err := h.HandleMessage(sender.vertex(), msg)
+			require.NoError(t, err)
+		}
+
+		err := h.HandleMessage(sender.vertex(), msg)
+		require.ErrorIs(t, err, ErrRateLimited)
+		require.Len(t, *sent, cfg.PeerBurst)
+
+		// Other peers have their own limit.
+		require.NoError(t, h.HandleMessage(recipient.vertex(), msg))
+
+		// Once the peer disconnected, its limit is reset.
+		h.RemovePeer(sender.vertex())
+		require.NoError(t, h.HandleMessage(sender.vertex(), msg))
+	})
+
+	t.Run("oversized packet", func(t *testing.T) {
+		h, _, sent := newHandler()
+
+		msg, _ := buildOnionMessage(t, relay, recipient, relayData)
+		msg.OnionBlob = append(msg.OnionBlob, make([]byte, 1000)...)
+
+		err := h.HandleMessage(sender.vertex(), msg)
+		require.ErrorIs(t, err, ErrUnsupportedPacketSize)
+		require.Empty(t, *sent)
+	})
+
+	t.Run("peers only", func(t *testing.T) {
+		h, cfg, sent := newHandler()
+		cfg.Relay = RelayPeers
+
+		channelPeers := map[route.Vertex]bool{
+			sender.vertex(): true,
+		}
+		cfg.HasChannel = func(peer route.Vertex) bool {
+			return channelPeers[peer]
+		}
+
+		// The next hop isn't a channel peer.
+		msg, _ := buildOnionMessage(t, relay, recipient, relayData)
+		err := h.HandleMessage(sender.vertex(), msg)
+		require.ErrorIs(t, err, ErrNoChannel)
+
+		// Neither is the sender of this message.
+		err = h.HandleMessage(recipient.vertex(), msg)
+		require.ErrorIs(t, err, ErrNoChannel)
+		require.Empty(t, *sent)
+
+		// Once we have a channel with the next hop, the message is
+		// relayed.
+		channelPeers[recipient.vertex()] = true
+		require.NoError(t, h.HandleMessage(sender.vertex(), msg))
+		require.Len(t, *sent, 1)
+	})
+
+	t.Run("next hop not connected", func(t *testing.T) {
+		var sent []sentMessage
+		h, _ := newTestHandler(relay, nil, &sent)
+
+		msg, _ := buildOnionMessage(t, relay, recipient, relayData)
+		require.Error(t, h.HandleMessage(sender.vertex(), msg))
+		require.Empty(t, sent)
+	})
+}
@@ -318,6 +318,11 @@ type Config struct {
 	// payments.
 	MaxOutgoingCltvExpiry uint32
 
+	// MaxOutgoingCltvExpiryPolicy is used when creating ChannelLinks and
+	// determines how forwarded HTLCs that exceed MaxOutgoingCltvExpiry are
+	// handled.
+	MaxOutgoingCltvExpiryPolicy htlcswitch.MaxCltvExpiryPolicy
+
 	// MaxChannelFeeAllocation is used when creating ChannelLinks and is the
 	// maximum percentage of total funds that can be allocated to a channel's
 	// commitment fee. This only applies for the initiator of the channel.
@@ -332,6 +337,11 @@ type Config struct {
 	// closure initiated by the remote peer.
 	CoopCloseTargetConfs uint32
 
+	// CoopCloseScript, if set, is the script that cooperative closes pay
+	// our balance out to, unless the channel has an upfront shutdown
+	// script or a delivery script was requested for the close.
+	CoopCloseScript lnwire.DeliveryAddress
+
 	// ServerPubKey is the serialized, compressed public key of our lnd node.
 	// It is used to determine which policy (channel edge) to pass to the
 	// ChannelLink.
@@ -358,6 +368,10 @@ type Config struct {
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
 
+	// HandleOnionMessage is called whenever an onion message is received
+	// from the peer. If nil, onion messages are silently dropped.
+	HandleOnionMessage func(peer [33]byte, msg *lnwire.OnionMessage) error
+
 	// GetAliases is passed to created links so the Switch and link can be
 	// aware of the channel's aliases.
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
@@ -381,6 +395,11 @@ type Config struct {
 	// invalid.
 	DisallowRouteBlinding bool
 
+	// ManualDataLossRecovery prevents channels from being force closed if
+	// the peer's channel reestablish message shows that it lost state or
+	// sent invalid commitment secrets.
+	ManualDataLossRecovery bool
+
 	// Quit is the server's quit channel. If this is closed, we halt operation.
 	Quit chan struct{}
 }
@@ -1153,6 +1172,25 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		return p.cfg.ChainArb.NotifyContractUpdate(*chanPoint, update)
 	}
 
+	// If the peer proves that our state of the channel is outdated, we'll
+	// let the chain arbitrator know, as we might be running on an old
+	// backup. A channel restored from a static channel backup is always
+	// outdated, and we rely on broadcasts to sweep its funds once the peer
+	// force closed it, so it never counts.
+	restored := lnChan.State().HasChanStatus(channeldb.ChanStatusRestored)
+	notifyReestablishFailed := func(op wire.OutPoint,
+		reason channelnotifier.ReestablishFailureReason, err error,
+		forceClose bool) {
+
+		if reason.MightBeStaleLocalState() && !restored {
+			p.cfg.ChainArb.NotifyStaleLocalState(op)
+		}
+
+		p.cfg.ChannelNotifier.NotifyReestablishFailedEvent(
+			op, reason, err, forceClose,
+		)
+	}
+
 	//nolint:lll
 	linkCfg := htlcswitch.ChannelLinkConfig{
 		Peer:                   p,
@@ -1190,10 +1228,14 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		NotifyActiveChannel:     p.cfg.ChannelNotifier.NotifyActiveChannelEvent,
 		NotifyInactiveChannel:   p.cfg.ChannelNotifier.NotifyInactiveChannelEvent,
 		NotifyInactiveLinkEvent: p.cfg.ChannelNotifier.NotifyInactiveLinkEvent,
+		NotifyReestablishFailed: notifyReestablishFailed,
 		HtlcNotifier:            p.cfg.HtlcNotifier,
 		GetAliases:              p.cfg.GetAliases,
 		PreviouslySentShutdown:  shutdownMsg,
 		DisallowRouteBlinding:   p.cfg.DisallowRouteBlinding,
+		ManualDataLossRecovery:  p.cfg.ManualDataLossRecovery,
+
+		MaxOutgoingCltvExpiryPolicy: p.cfg.MaxOutgoingCltvExpiryPolicy,
 	}
 
 	// Before adding our new link, purge the switch of any pending or live
@@ -1838,6 +1880,19 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.OnionMessage:
+			// Onion messages are best effort, so a message that
+			// we refuse to relay is dropped without penalizing
+			// the peer.
+			if p.cfg.HandleOnionMessage == nil {
+				break
+			}
+
+			err := p.cfg.HandleOnionMessage(p.PubKey(), msg)
+			if err != nil {
+				p.log.Debugf("Dropping onion message: %v", err)
+			}
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -2140,6 +2195,10 @@ func messageSummary(msg lnwire.Message) string {
 
 	case *lnwire.Custom:
 		return fmt.Sprintf("type=%d", msg.Type)
+
+	case *lnwire.OnionMessage:
+		return fmt.Sprintf("path_key=%x, onion_size=%v",
+			msg.PathKey.SerializeCompressed(), len(msg.OnionBlob))
 	}
 
 	return fmt.Sprintf("unknown msg type=%T", msg)
@@ -2485,6 +2544,19 @@ func (p *Brontide) ChannelSnapshots() []*channeldb.ChannelSnapshot {
 // genDeliveryScript returns a new script to be used to send our funds to in
 // the case of a cooperative channel close negotiation.
 func (p *Brontide) genDeliveryScript() ([]byte, error) {
+	// Use the configured close script if there is one. A taproot script
+	// is only accepted by peers that negotiated the shutdown-any-segwit
+	// feature, so we fall back to a fresh address for all others.
+	if len(p.cfg.CoopCloseScript) > 0 {
+		isTaproot := txscript.IsPayToTaproot(p.cfg.CoopCloseScript)
+		if !isTaproot || p.taprootShutdownAllowed() {
+			return p.cfg.CoopCloseScript, nil
+		}
+
+		p.log.Warnf("Peer doesn't support taproot shutdown scripts, " +
+			"using fresh delivery addr instead of configured one")
+	}
+
 	// We'll send a normal p2wkh address unless we've negotiated the
 	// shutdown-any-segwit feature.
 	addrType := lnwallet.WitnessPubKey
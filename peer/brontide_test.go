@@ -28,6 +28,9 @@ var (
 
 	// p2wshAddress is a valid pay to witness script hash address.
 	p2wshAddress = "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3"
+
+	// p2trAddress is a valid pay to taproot address.
+	p2trAddress = "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
 )
 
 // TestPeerChannelClosureShutdownResponseLinkRemoved tests the shutdown
@@ -748,9 +751,12 @@ func TestChooseDeliveryScript(t *testing.T) {
 
 // TestCustomShutdownScript tests that the delivery script of a shutdown
 // message can be set to a specified address. It checks that setting a close
-// script fails for channels which have an upfront shutdown script already set.
+// script fails for channels which have an upfront shutdown script already set
+// and that the configured close script is only used if neither is set.
 func TestCustomShutdownScript(t *testing.T) {
 	script := genScript(t, p2SHAddress)
+	configScript := genScript(t, p2wshAddress)
+	taprootScript := genScript(t, p2trAddress)
 
 	// setShutdown is a function which sets the upfront shutdown address for
 	// the local channel.
@@ -769,6 +775,14 @@ func TestCustomShutdownScript(t *testing.T) {
 		// userCloseScript is the address specified by the user.
 		userCloseScript lnwire.DeliveryAddress
 
+		// configCloseScript is the close script configured for all
+		// cooperative closes.
+		configCloseScript lnwire.DeliveryAddress
+
+		// expectFreshScript is true if we expect the configured close
+		// script to be replaced by a fresh one.
+		expectFreshScript bool
+
 		// expectedScript is the address we expect to be set on the shutdown
 		// message.
 		expectedScript lnwire.DeliveryAddress
@@ -803,6 +817,31 @@ func TestCustomShutdownScript(t *testing.T) {
 			userCloseScript: []byte("different addr"),
 			expectedError:   chancloser.ErrUpfrontShutdownScriptMismatch,
 		},
+		{
+			name:              "Config script, no user script",
+			update:            noUpdate,
+			configCloseScript: configScript,
+			expectedScript:    configScript,
+		},
+		{
+			name:              "Config script, user script",
+			update:            noUpdate,
+			userCloseScript:   script,
+			configCloseScript: configScript,
+			expectedScript:    script,
+		},
+		{
+			name:              "Shutdown set, config script",
+			update:            setShutdown,
+			configCloseScript: configScript,
+			expectedScript:    script,
+		},
+		{
+			name:              "Taproot config script, no support",
+			update:            noUpdate,
+			configCloseScript: taprootScript,
+			expectFreshScript: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -823,6 +862,8 @@ func TestCustomShutdownScript(t *testing.T) {
 				mockSwitch = harness.mockSwitch
 			)
 
+			alicePeer.cfg.CoopCloseScript = test.configCloseScript
+
 			chanPoint := bobChan.ChannelPoint()
 			chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
 			mockLink := newMockUpdateHandler(chanID)
@@ -868,6 +909,19 @@ func TestCustomShutdownScript(t *testing.T) {
 				t.Fatalf("expected shutdown message, got %T", msg)
 			}
 
+			// The peer doesn't support taproot shutdown scripts, so
+			// a fresh script must be used instead of the configured
+			// one.
+			if test.expectFreshScript {
+				require.NotEmpty(t, shutdownMsg.Address)
+				require.NotEqual(
+					t, test.configCloseScript,
+					shutdownMsg.Address,
+				)
+
+				return
+			}
+
 			// If the test has not specified an expected address, do not check
 			// whether the shutdown address matches. This covers the case where
 			// we expect shutdown to a random address and cannot match it.
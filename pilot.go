@@ -7,12 +7,17 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tor"
 )
@@ -80,6 +85,10 @@ type chanController struct {
 	confTarget    uint32
 	chanMinHtlcIn lnwire.MilliSatoshi
 	netParams     chainreg.BitcoinNetParams
+
+	// feeBudget, if set, is charged with the on-chain fees of the funding
+	// transactions of the channels opened.
+	feeBudget *autopilot.FeeBudget
 }
 
 // OpenChannel opens a channel to a target peer, with a capacity of the
@@ -97,6 +106,13 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 		return err
 	}
 
+	// Concurrent channel opens share the fee budget, so we'll reserve the
+	// estimated fee before starting the funding workflow.
+	feeReservation, err := c.reserveFeeBudget(feePerKw)
+	if err != nil {
+		return err
+	}
+
 	// Construct the open channel request and send it to the server to begin
 	// the funding workflow.
 	req := &funding.InitFundingMsg{
@@ -116,14 +132,84 @@ func (c *chanController) OpenChannel(target *btcec.PublicKey,
 	updateStream, errChan := c.server.OpenChannel(req)
 	select {
 	case err := <-errChan:
+		// No funding transaction was published, so the reserved fee is
+		// returned to the budget.
+		if feeReservation != nil {
+			relErr := c.feeBudget.Release(feeReservation)
+			if relErr != nil {
+				atplLog.Errorf("Unable to release reserved "+
+					"fee: %v", relErr)
+			}
+		}
+
 		return err
-	case <-updateStream:
+	case update := <-updateStream:
+		c.settleFeeBudget(feeReservation, update)
 		return nil
 	case <-c.server.quit:
 		return nil
 	}
 }
 
+// reserveFeeBudget reserves the estimated fee of a funding transaction at the
+// given fee rate from the fee budget, if set. The estimate assumes a single
+// wallet input and a change output.
+func (c *chanController) reserveFeeBudget(
+	feePerKw chainfee.SatPerKWeight) (*autopilot.FeeReservation, error) {
+
+	if c.feeBudget == nil {
+		return nil, nil
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2WSHOutput()
+	weightEstimate.AddP2TROutput()
+
+	fee := feePerKw.FeeForWeight(weightEstimate.Weight())
+
+	return c.feeBudget.Reserve(fee)
+}
+
+// settleFeeBudget replaces the given fee reservation with the on-chain fee of
+// the funding transaction announced by the given pending channel update.
+func (c *chanController) settleFeeBudget(res *autopilot.FeeReservation,
+	update *lnrpc.OpenStatusUpdate) {
+
+	// If we can't find the actual fee, the reserved estimate stays
+	// charged.
+	if res == nil {
+		return
+	}
+
+	pending := update.GetChanPending()
+	if pending == nil {
+		return
+	}
+
+	txid, err := chainhash.NewHash(pending.Txid)
+	if err != nil {
+		atplLog.Errorf("Invalid funding txid: %v", err)
+		return
+	}
+
+	// The funding transaction was crafted by our wallet, so it knows the
+	// fee that was paid.
+	details, err := c.server.cc.Wallet.GetTransactionDetails(txid)
+	if err != nil {
+		atplLog.Errorf("Unable to fetch funding transaction %v: %v",
+			txid, err)
+
+		return
+	}
+
+	fee := btcutil.Amount(details.TotalFees)
+	if err := c.feeBudget.Settle(res, fee); err != nil {
+		atplLog.Errorf("Unable to record fee of funding transaction "+
+			"%v: %v", txid, err)
+	}
+}
+
 func (c *chanController) CloseChannel(chanPoint *wire.OutPoint) error {
 	return nil
 }
@@ -142,9 +228,15 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 
 	atplLog.Infof("Instantiating autopilot with active=%v, "+
 		"max_channels=%d, allocation=%f, min_chan_size=%d, "+
-		"max_chan_size=%d, private=%t, min_confs=%d, conf_target=%d",
+		"max_chan_size=%d, private=%t, min_confs=%d, conf_target=%d, "+
+		"recommend_only=%t, fee_budget=%d, fee_budget_period=%v, "+
+		"activation_delay=%v, activation_delay_blocks=%d, "+
+		"require_manual_first_channel=%t",
 		cfg.Active, cfg.MaxChannels, cfg.Allocation, cfg.MinChannelSize,
-		cfg.MaxChannelSize, cfg.Private, cfg.MinConfs, cfg.ConfTarget)
+		cfg.MaxChannelSize, cfg.Private, cfg.MinConfs, cfg.ConfTarget,
+		cfg.RecommendOnly, cfg.FeeBudget, cfg.FeeBudgetPeriod,
+		cfg.ActivationDelay, cfg.ActivationDelayBlocks,
+		cfg.RequireManualFirstChannel)
 
 	// Set up the constraints the autopilot heuristics must adhere to.
 	atplConstraints := autopilot.NewConstraints(
@@ -166,6 +258,61 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 		return nil, err
 	}
 
+	// If a fee budget is configured, we'll restore the fees spent within
+	// the current period so a restart doesn't reset them.
+	var feeBudget *autopilot.FeeBudget
+	if cfg.FeeBudget > 0 {
+		store, err := autopilot.NewFeeBudgetStore(svr.miscDB)
+		if err != nil {
+			return nil, err
+		}
+
+		budgetCfg := autopilot.FeeBudgetConfig{
+			Budget: btcutil.Amount(cfg.FeeBudget),
+			Period: cfg.FeeBudgetPeriod,
+			Store:  store,
+			Clock:  clock.NewDefaultClock(),
+		}
+		feeBudget, err = autopilot.NewFeeBudget(budgetCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If activation requirements are configured, we'll restore their
+	// state so a restart doesn't reset the activation delay.
+	var activation *autopilot.Activation
+	if cfg.ActivationDelay > 0 || cfg.ActivationDelayBlocks > 0 ||
+		cfg.RequireManualFirstChannel {
+
+		store, err := autopilot.NewActivationStore(svr.miscDB)
+		if err != nil {
+			return nil, err
+		}
+
+		requireManual := cfg.RequireManualFirstChannel
+		bestHeight := func() (uint32, error) {
+			_, height, err := svr.cc.ChainIO.GetBestBlock()
+			if err != nil {
+				return 0, err
+			}
+
+			return uint32(height), nil
+		}
+		activationCfg := autopilot.ActivationConfig{
+			Delay:                     cfg.ActivationDelay,
+			DelayBlocks:               cfg.ActivationDelayBlocks,
+			BestHeight:                bestHeight,
+			RequireManualFirstChannel: requireManual,
+			Store:                     store,
+			Clock:                     clock.NewDefaultClock(),
+		}
+		activation, err = autopilot.NewActivation(activationCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// With the heuristic itself created, we can now populate the remainder
 	// of the items that the autopilot agent needs to perform its duties.
 	self := svr.identityECDH.PubKey()
@@ -179,14 +326,18 @@ func initAutoPilot(svr *server, cfg *lncfg.AutoPilot,
 			confTarget:    cfg.ConfTarget,
 			chanMinHtlcIn: minHTLCIn,
 			netParams:     netParams,
+			feeBudget:     feeBudget,
 		},
 		WalletBalance: func() (btcutil.Amount, error) {
 			return svr.cc.Wallet.ConfirmedBalance(
 				cfg.MinConfs, lnwallet.DefaultAccountName,
 			)
 		},
-		Graph:       autopilot.ChannelGraphFromDatabase(svr.graphDB),
-		Constraints: atplConstraints,
+		Graph:         autopilot.ChannelGraphFromDatabase(svr.graphDB),
+		Constraints:   atplConstraints,
+		RecommendOnly: cfg.RecommendOnly,
+		FeeBudget:     feeBudget,
+		Activation:    activation,
 		ConnectToPeer: func(target *btcec.PublicKey, addrs []net.Addr) (bool, error) {
 			// First, we'll check if we're already connected to the
 			// target peer. If we are, we can exit early. Otherwise,
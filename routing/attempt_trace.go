@@ -0,0 +1,397 @@
+package routing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// AttemptTraceVersion is the version of the attempt trace format. It is only
+// increased on incompatible changes of the format, new fields may be added
+// without changing it.
+const AttemptTraceVersion = 1
+
+const (
+	// AttemptStatusSettled is the status of an attempt that was settled.
+	AttemptStatusSettled = "settled"
+
+	// AttemptStatusFailed is the status of an attempt that failed.
+	AttemptStatusFailed = "failed"
+
+	// HopResultSuccess is the result of a hop that forwarded the HTLC
+	// successfully.
+	HopResultSuccess = "success"
+
+	// HopResultFailure is the result of the hop the HTLC failed at.
+	HopResultFailure = "failure"
+
+	// HopResultUnknown is the result of a hop that the HTLC didn't reach
+	// or whose result can't be derived from the failure.
+	HopResultUnknown = "unknown"
+)
+
+// AttemptTracer records traces of HTLC attempts for offline analysis.
+type AttemptTracer interface {
+	// AttemptRegistered is called once an attempt of the payment was
+	// registered with the control tower, right before it is sent.
+	AttemptRegistered(paymentID lntypes.Hash,
+		attempt *channeldb.HTLCAttempt)
+
+	// AttemptResolved is called once an attempt of the payment was settled
+	// or failed.
+	AttemptResolved(paymentID lntypes.Hash, attempt *channeldb.HTLCAttempt)
+}
+
+// AttemptTrace is the trace of a resolved HTLC attempt. It is encoded as a
+// single line of JSON by the AttemptTraceWriter. All times are unix
+// timestamps in nanoseconds.
+type AttemptTrace struct {
+	// Version is the version of the trace format.
+	Version int `json:"version"`
+
+	// PaymentHash is the hex encoded identifier of the payment the
+	// attempt belongs to.
+	PaymentHash string `json:"payment_hash"`
+
+	// AttemptID is the unique id of the attempt.
+	AttemptID uint64 `json:"attempt_id"`
+
+	// AttemptTime is the time the attempt was sent.
+	AttemptTime int64 `json:"attempt_time_ns"`
+
+	// ResolveTime is the time the attempt was settled or failed.
+	ResolveTime int64 `json:"resolve_time_ns"`
+
+	// Status is either AttemptStatusSettled or AttemptStatusFailed.
+	Status string `json:"status"`
+
+	// FailureReason describes why a failed attempt failed. It is one of
+	// "message", "unknown", "unreadable" or "internal".
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	// FailureCode is the failure code of the failure message, if any.
+	FailureCode string `json:"failure_code,omitempty"`
+
+	// FailureSourceIndex is the position in the route of the node that
+	// failed the attempt, where zero is ourselves. It is only set if the
+	// failure source is known.
+	FailureSourceIndex *uint32 `json:"failure_source_index,omitempty"`
+
+	// SourcePubKey is the hex encoded public key of the sender.
+	SourcePubKey string `json:"source_pub_key"`
+
+	// TotalAmtMsat is the amount sent including fees.
+	TotalAmtMsat uint64 `json:"total_amt_msat"`
+
+	// TotalFeesMsat is the fee paid to the hops of the route.
+	TotalFeesMsat uint64 `json:"total_fees_msat"`
+
+	// TotalTimeLock is the absolute time lock of the first hop.
+	TotalTimeLock uint32 `json:"total_time_lock"`
+
+	// Hops are the traces of the hops of the route.
+	Hops []*HopTrace `json:"hops"`
+}
+
+// HopTrace is the trace of a single hop of an attempt, which covers the pair
+// from the previous node of the route to the hop's node.
+type HopTrace struct {
+	// ChanID is the channel the hop is reached through.
+	ChanID uint64 `json:"chan_id"`
+
+	// PubKey is the hex encoded public key of the hop's node.
+	PubKey string `json:"pub_key"`
+
+	// AmtToForwardMsat is the amount the hop forwards to the next hop.
+	AmtToForwardMsat uint64 `json:"amt_to_forward_msat"`
+
+	// FeeMsat is the fee charged by the hop.
+	FeeMsat uint64 `json:"fee_msat"`
+
+	// Expiry is the absolute time lock of the HTLC the hop offers to the
+	// next hop.
+	Expiry uint32 `json:"expiry"`
+
+	// Result is the result of the hop as derived from the outcome of the
+	// attempt. It is one of HopResultSuccess, HopResultFailure or
+	// HopResultUnknown.
+	Result string `json:"result"`
+
+	// MissionControl is the state mission control held for the pair when
+	// the attempt was sent. It isn't set if the attempt was sent before a
+	// restart.
+	MissionControl *PairTrace `json:"mission_control,omitempty"`
+}
+
+// PairTrace is the state mission control held for a node pair.
+type PairTrace struct {
+	// FailTime is the time of the last failure, zero if there was none.
+	FailTime int64 `json:"fail_time_ns"`
+
+	// FailAmtMsat is the amount of the last failure.
+	FailAmtMsat uint64 `json:"fail_amt_msat"`
+
+	// SuccessTime is the time of the last success, zero if there was none.
+	SuccessTime int64 `json:"success_time_ns"`
+
+	// SuccessAmtMsat is the highest amount that succeeded.
+	SuccessAmtMsat uint64 `json:"success_amt_msat"`
+}
+
+// PairHistorySource returns the state mission control holds for a node pair.
+type PairHistorySource interface {
+	// GetPairHistorySnapshot returns the stored history for a given node
+	// pair.
+	GetPairHistorySnapshot(fromNode, toNode route.Vertex) TimedPairResult
+}
+
+// AttemptTraceWriter is an AttemptTracer that writes the trace of each
+// resolved attempt as a line of JSON. The traces are kept separate from the
+// payments in the database.
+type AttemptTraceWriter struct {
+	w       io.Writer
+	history PairHistorySource
+
+	// pairStates holds the mission control state of the pairs of the
+	// route of each attempt in flight, captured when it was sent.
+	pairStates map[uint64][]*PairTrace
+
+	mu sync.Mutex
+}
+
+// A compile-time check to ensure AttemptTraceWriter implements the
+// AttemptTracer interface.
+var _ AttemptTracer = (*AttemptTraceWriter)(nil)
+
+// NewAttemptTraceWriter returns a new AttemptTraceWriter that writes the
+// traces to the given writer, including the state of the given mission
+// control history.
+func NewAttemptTraceWriter(w io.Writer,
+	history PairHistorySource) *AttemptTraceWriter {
+
+	return &AttemptTraceWriter{
+		w:          w,
+		history:    history,
+		pairStates: make(map[uint64][]*PairTrace),
+	}
+}
+
+// AttemptRegistered captures the mission control state of the pairs of the
+// attempt's route.
+//
+// NOTE: Part of the AttemptTracer interface.
+func (a *AttemptTraceWriter) AttemptRegistered(_ lntypes.Hash,
+	attempt *channeldb.HTLCAttempt) {
+
+	rt := &attempt.Route
+	states := make([]*PairTrace, len(rt.Hops))
+
+	from := rt.SourcePubKey
+	for i, hop := range rt.Hops {
+		result := a.history.GetPairHistorySnapshot(
+			from, hop.PubKeyBytes,
+		)
+		states[i] = &PairTrace{
+			FailTime:       unixNano(result.FailTime),
+			FailAmtMsat:    uint64(result.FailAmt),
+			SuccessTime:    unixNano(result.SuccessTime),
+			SuccessAmtMsat: uint64(result.SuccessAmt),
+		}
+
+		from = hop.PubKeyBytes
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pairStates[attempt.AttemptID] = states
+}
+
+// AttemptResolved writes the trace of the resolved attempt.
+//
+// NOTE: Part of the AttemptTracer interface.
+func (a *AttemptTraceWriter) AttemptResolved(paymentID lntypes.Hash,
+	attempt *channeldb.HTLCAttempt) {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	states := a.pairStates[attempt.AttemptID]
+	delete(a.pairStates, attempt.AttemptID)
+
+	trace := newAttemptTrace(paymentID, attempt, states)
+
+	line, err := json.Marshal(trace)
+	if err != nil {
+		log.Errorf("Unable to encode trace of attempt %v: %v",
+			attempt.AttemptID, err)
+
+		return
+	}
+
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		log.Errorf("Unable to write trace of attempt %v: %v",
+			attempt.AttemptID, err)
+	}
+}
+
+// AttemptTraceFile is an AttemptTraceWriter that appends the traces to a
+// file.
+type AttemptTraceFile struct {
+	*AttemptTraceWriter
+
+	file *os.File
+}
+
+// NewAttemptTraceFile opens the file at the given path for appending attempt
+// traces to it, creating it if it doesn't exist.
+func NewAttemptTraceFile(path string,
+	history PairHistorySource) (*AttemptTraceFile, error) {
+
+	file, err := os.OpenFile(
+		path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open attempt trace file: %w",
+			err)
+	}
+
+	return &AttemptTraceFile{
+		AttemptTraceWriter: NewAttemptTraceWriter(file, history),
+		file:               file,
+	}, nil
+}
+
+// Close closes the underlying file.
+func (a *AttemptTraceFile) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.file.Close()
+}
+
+// newAttemptTrace assembles the trace of the resolved attempt, using the
+// mission control states of its pairs if known.
+func newAttemptTrace(paymentID lntypes.Hash, attempt *channeldb.HTLCAttempt,
+	states []*PairTrace) *AttemptTrace {
+
+	rt := &attempt.Route
+	trace := &AttemptTrace{
+		Version:       AttemptTraceVersion,
+		PaymentHash:   paymentID.String(),
+		AttemptID:     attempt.AttemptID,
+		AttemptTime:   unixNano(attempt.AttemptTime),
+		SourcePubKey:  hex.EncodeToString(rt.SourcePubKey[:]),
+		TotalAmtMsat:  uint64(rt.TotalAmount),
+		TotalFeesMsat: uint64(rt.TotalFees()),
+		TotalTimeLock: rt.TotalTimeLock,
+		Hops:          make([]*HopTrace, len(rt.Hops)),
+	}
+
+	// The hops before the failure source forwarded the HTLC, while the
+	// failure source failed to forward it over the next hop. If the
+	// failure source is the final node, all hops succeeded. Without a
+	// known failure source, nothing can be said about the hops.
+	hopResult := func(int) string {
+		return HopResultUnknown
+	}
+
+	switch {
+	case attempt.Settle != nil:
+		trace.Status = AttemptStatusSettled
+		trace.ResolveTime = unixNano(attempt.Settle.SettleTime)
+		hopResult = func(int) string {
+			return HopResultSuccess
+		}
+
+	case attempt.Failure != nil:
+		failure := attempt.Failure
+
+		trace.Status = AttemptStatusFailed
+		trace.ResolveTime = unixNano(failure.FailTime)
+		trace.FailureReason = htlcFailReasonString(failure.Reason)
+		if failure.Message != nil {
+			trace.FailureCode = failure.Message.Code().String()
+		}
+
+		if failure.Reason != channeldb.HTLCFailMessage &&
+			failure.Reason != channeldb.HTLCFailUnknown {
+
+			break
+		}
+
+		sourceIdx := failure.FailureSourceIndex
+		trace.FailureSourceIndex = &sourceIdx
+		hopResult = func(i int) string {
+			switch {
+			case i < int(sourceIdx):
+				return HopResultSuccess
+
+			case i == int(sourceIdx):
+				return HopResultFailure
+
+			default:
+				return HopResultUnknown
+			}
+		}
+	}
+
+	for i, hop := range rt.Hops {
+		pubKey := hop.PubKeyBytes
+		hopTrace := &HopTrace{
+			ChanID:           hop.ChannelID,
+			PubKey:           hex.EncodeToString(pubKey[:]),
+			AmtToForwardMsat: uint64(hop.AmtToForward),
+			FeeMsat:          uint64(rt.HopFee(i)),
+			Expiry:           hop.OutgoingTimeLock,
+			Result:           hopResult(i),
+		}
+
+		if i < len(states) {
+			hopTrace.MissionControl = states[i]
+		}
+
+		trace.Hops[i] = hopTrace
+	}
+
+	return trace
+}
+
+// htlcFailReasonString returns the string representation of the HTLC failure
+// reason used in attempt traces.
+func htlcFailReasonString(reason channeldb.HTLCFailReason) string {
+	switch reason {
+	case channeldb.HTLCFailUnknown:
+		return "unknown"
+
+	case channeldb.HTLCFailUnreadable:
+		return "unreadable"
+
+	case channeldb.HTLCFailInternal:
+		return "internal"
+
+	case channeldb.HTLCFailMessage:
+		return "message"
+
+	default:
+		return fmt.Sprintf("reason(%d)", reason)
+	}
+}
+
+// unixNano returns the unix timestamp of the given time in nanoseconds, or
+// zero if the time is unset.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
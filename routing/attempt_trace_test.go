@@ -0,0 +1,167 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPairHistorySource is a PairHistorySource that returns a fixed result for
+// a single pair.
+type mockPairHistorySource struct {
+	pair   DirectedNodePair
+	result TimedPairResult
+}
+
+func (m *mockPairHistorySource) GetPairHistorySnapshot(fromNode,
+	toNode route.Vertex) TimedPairResult {
+
+	if m.pair != NewDirectedNodePair(fromNode, toNode) {
+		return TimedPairResult{}
+	}
+
+	return m.result
+}
+
+// TestAttemptTraceWriter tests that the traces of resolved attempts are
+// written as lines of JSON.
+func TestAttemptTraceWriter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		source = route.Vertex{1}
+		node1  = route.Vertex{2}
+		node2  = route.Vertex{3}
+		node3  = route.Vertex{4}
+
+		paymentID   = lntypes.Hash{5}
+		attemptTime = time.Unix(1_000, 0)
+		resolveTime = time.Unix(1_010, 0)
+		failTime    = time.Unix(900, 0)
+	)
+
+	rt := route.Route{
+		TotalTimeLock: 200,
+		TotalAmount:   1_030,
+		SourcePubKey:  source,
+		Hops: []*route.Hop{
+			{
+				PubKeyBytes:      node1,
+				ChannelID:        1,
+				AmtToForward:     1_020,
+				OutgoingTimeLock: 180,
+			},
+			{
+				PubKeyBytes:      node2,
+				ChannelID:        2,
+				AmtToForward:     1_000,
+				OutgoingTimeLock: 160,
+			},
+			{
+				PubKeyBytes:      node3,
+				ChannelID:        3,
+				AmtToForward:     1_000,
+				OutgoingTimeLock: 160,
+			},
+		},
+	}
+
+	history := &mockPairHistorySource{
+		pair: NewDirectedNodePair(node1, node2),
+		result: TimedPairResult{
+			FailTime: failTime,
+			FailAmt:  2_000,
+		},
+	}
+
+	var buf bytes.Buffer
+	tracer := NewAttemptTraceWriter(&buf, history)
+
+	// A failed attempt whose failure source is known is traced with the
+	// mission control state captured when it was sent.
+	failedAttempt := &channeldb.HTLCAttempt{
+		HTLCAttemptInfo: channeldb.HTLCAttemptInfo{
+			AttemptID:   1,
+			Route:       rt,
+			AttemptTime: attemptTime,
+		},
+	}
+	tracer.AttemptRegistered(paymentID, failedAttempt)
+
+	failedAttempt.Failure = &channeldb.HTLCFailInfo{
+		FailTime:           resolveTime,
+		Message:            lnwire.NewTemporaryChannelFailure(nil),
+		Reason:             channeldb.HTLCFailMessage,
+		FailureSourceIndex: 2,
+	}
+	tracer.AttemptResolved(paymentID, failedAttempt)
+
+	// A settled attempt that was sent before a restart is traced without
+	// any mission control state.
+	settledAttempt := &channeldb.HTLCAttempt{
+		HTLCAttemptInfo: channeldb.HTLCAttemptInfo{
+			AttemptID:   2,
+			Route:       rt,
+			AttemptTime: attemptTime,
+		},
+		Settle: &channeldb.HTLCSettleInfo{
+			SettleTime: resolveTime,
+		},
+	}
+	tracer.AttemptResolved(paymentID, settledAttempt)
+
+	// All captured states are released once the attempts resolved.
+	require.Empty(t, tracer.pairStates)
+
+	decoder := json.NewDecoder(&buf)
+
+	var failedTrace AttemptTrace
+	require.NoError(t, decoder.Decode(&failedTrace))
+
+	require.Equal(t, AttemptTraceVersion, failedTrace.Version)
+	require.Equal(t, paymentID.String(), failedTrace.PaymentHash)
+	require.EqualValues(t, 1, failedTrace.AttemptID)
+	require.Equal(t, attemptTime.UnixNano(), failedTrace.AttemptTime)
+	require.Equal(t, resolveTime.UnixNano(), failedTrace.ResolveTime)
+	require.Equal(t, AttemptStatusFailed, failedTrace.Status)
+	require.Equal(t, "message", failedTrace.FailureReason)
+	require.Equal(t, "TemporaryChannelFailure", failedTrace.FailureCode)
+	require.NotNil(t, failedTrace.FailureSourceIndex)
+	require.EqualValues(t, 2, *failedTrace.FailureSourceIndex)
+	require.EqualValues(t, 30, failedTrace.TotalFeesMsat)
+
+	require.Len(t, failedTrace.Hops, 3)
+	require.Equal(t, HopResultSuccess, failedTrace.Hops[0].Result)
+	require.Equal(t, HopResultSuccess, failedTrace.Hops[1].Result)
+	require.Equal(t, HopResultFailure, failedTrace.Hops[2].Result)
+
+	require.EqualValues(t, 10, failedTrace.Hops[0].FeeMsat)
+	require.EqualValues(t, 20, failedTrace.Hops[1].FeeMsat)
+	require.EqualValues(t, 0, failedTrace.Hops[2].FeeMsat)
+
+	require.Equal(t, &PairTrace{}, failedTrace.Hops[0].MissionControl)
+	require.Equal(t, &PairTrace{
+		FailTime:    failTime.UnixNano(),
+		FailAmtMsat: 2_000,
+	}, failedTrace.Hops[1].MissionControl)
+
+	var settledTrace AttemptTrace
+	require.NoError(t, decoder.Decode(&settledTrace))
+
+	require.Equal(t, AttemptStatusSettled, settledTrace.Status)
+	require.Empty(t, settledTrace.FailureReason)
+	require.Nil(t, settledTrace.FailureSourceIndex)
+	for _, hop := range settledTrace.Hops {
+		require.Equal(t, HopResultSuccess, hop.Result)
+		require.Nil(t, hop.MissionControl)
+	}
+
+	require.False(t, decoder.More())
+}
@@ -0,0 +1,78 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// cltvDeltaLowerPercentile is the percentile of the advertised CLTV
+	// deltas below which a delta is considered an outlier.
+	cltvDeltaLowerPercentile = 10
+
+	// cltvDeltaUpperPercentile is the percentile of the advertised CLTV
+	// deltas above which a delta is considered an outlier.
+	cltvDeltaUpperPercentile = 90
+
+	// minCltvDeltaSamples is the minimum number of policies we need to
+	// have seen before we consider the summary meaningful.
+	minCltvDeltaSamples = 10
+)
+
+// CltvDeltaSummary summarizes the distribution of CLTV deltas that are
+// advertised in a set of channel policies, such as the ones of our peers. It
+// can be used to detect whether a given delta deviates significantly from what
+// those nodes use.
+type CltvDeltaSummary struct {
+	// NumPolicies is the number of channel policies the summary is based
+	// on.
+	NumPolicies int
+
+	// Lower is the CLTV delta at the lower percentile boundary.
+	Lower uint16
+
+	// Median is the median CLTV delta.
+	Median uint16
+
+	// Upper is the CLTV delta at the upper percentile boundary.
+	Upper uint16
+}
+
+// NewCltvDeltaSummary creates a summary from the passed set of advertised
+// CLTV deltas. Nil is returned if there aren't enough samples for the summary
+// to be meaningful.
+func NewCltvDeltaSummary(deltas []uint16) *CltvDeltaSummary {
+	if len(deltas) < minCltvDeltaSamples {
+		return nil
+	}
+
+	sorted := make([]uint16, len(deltas))
+	copy(sorted, deltas)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	percentile := func(p int) uint16 {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+
+	return &CltvDeltaSummary{
+		NumPolicies: len(sorted),
+		Lower:       percentile(cltvDeltaLowerPercentile),
+		Median:      percentile(50),
+		Upper:       percentile(cltvDeltaUpperPercentile),
+	}
+}
+
+// IsOutlier returns true if the given CLTV delta lies outside of the range
+// that is commonly used by the summarized policies.
+func (c *CltvDeltaSummary) IsOutlier(delta uint16) bool {
+	return delta < c.Lower || delta > c.Upper
+}
+
+// String returns a human readable representation of the summary.
+func (c *CltvDeltaSummary) String() string {
+	return fmt.Sprintf("policies=%v, p%d=%v, median=%v, p%d=%v",
+		c.NumPolicies, cltvDeltaLowerPercentile, c.Lower, c.Median,
+		cltvDeltaUpperPercentile, c.Upper)
+}
@@ -0,0 +1,39 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCltvDeltaSummary tests that the CLTV delta summary correctly determines
+// the percentile boundaries and detects outliers.
+func TestCltvDeltaSummary(t *testing.T) {
+	t.Parallel()
+
+	// Too few samples shouldn't result in a summary.
+	require.Nil(t, NewCltvDeltaSummary(
+		make([]uint16, minCltvDeltaSamples-1),
+	))
+
+	// Create 100 samples ranging from 1 to 100.
+	deltas := make([]uint16, 100)
+	for i := range deltas {
+		deltas[len(deltas)-1-i] = uint16(i + 1)
+	}
+
+	summary := NewCltvDeltaSummary(deltas)
+	require.NotNil(t, summary)
+	require.Equal(t, 100, summary.NumPolicies)
+	require.EqualValues(t, 10, summary.Lower)
+	require.EqualValues(t, 50, summary.Median)
+	require.EqualValues(t, 90, summary.Upper)
+
+	require.True(t, summary.IsOutlier(9))
+	require.False(t, summary.IsOutlier(10))
+	require.False(t, summary.IsOutlier(90))
+	require.True(t, summary.IsOutlier(91))
+
+	// The passed samples must not be modified.
+	require.EqualValues(t, 100, deltas[0])
+}
@@ -0,0 +1,423 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// DefaultFeeSimulationSamples is the default number of sender and
+	// receiver pairs that are sampled by a fee simulation.
+	DefaultFeeSimulationSamples = 100
+
+	// MaxFeeSimulationSamples is the maximum number of sender and receiver
+	// pairs that can be sampled by a fee simulation. Every sample runs
+	// path finding twice, so this bounds the duration of a simulation.
+	MaxFeeSimulationSamples = 1000
+
+	// DefaultFeeSimulationAmount is the default amount of the payments
+	// that are simulated.
+	DefaultFeeSimulationAmount = lnwire.MilliSatoshi(100_000_000)
+)
+
+var (
+	// ErrNoFeeSimulationPairs is returned if the graph doesn't contain
+	// enough nodes to sample sender and receiver pairs from.
+	ErrNoFeeSimulationPairs = errors.New("not enough nodes in the graph " +
+		"to simulate payments")
+)
+
+// FeeChange is a proposed change of the forwarding fees of one of our
+// channels.
+type FeeChange struct {
+	// ChannelID is the short channel id of the channel.
+	ChannelID uint64
+
+	// BaseFee is the proposed base fee of the channel.
+	BaseFee lnwire.MilliSatoshi
+
+	// FeeRate is the proposed proportional fee of the channel in parts per
+	// million.
+	FeeRate lnwire.MilliSatoshi
+}
+
+// FeeSimulationRequest describes the fee changes to simulate.
+type FeeSimulationRequest struct {
+	// Changes are the proposed fee changes. Channels without a change
+	// keep their current fees.
+	Changes []FeeChange
+
+	// Amount is the amount of the simulated payments.
+	Amount lnwire.MilliSatoshi
+
+	// Samples is the number of sender and receiver pairs that are sampled
+	// from the graph.
+	Samples int
+
+	// CltvLimit is the maximum total time lock of the simulated routes,
+	// excluding the final cltv delta.
+	CltvLimit uint32
+}
+
+// ChannelRoutingEstimate is the estimated routing volume of one of our
+// channels before and after the proposed fee changes.
+type ChannelRoutingEstimate struct {
+	// ChannelID is the short channel id of the channel.
+	ChannelID uint64
+
+	// RoutesBefore is the number of simulated routes that forward over the
+	// channel with the current fees.
+	RoutesBefore int
+
+	// RoutesAfter is the number of simulated routes that forward over the
+	// channel with the proposed fees.
+	RoutesAfter int
+
+	// FeesBefore is the sum of the fees we'd earn on the simulated routes
+	// with the current fees.
+	FeesBefore lnwire.MilliSatoshi
+
+	// FeesAfter is the sum of the fees we'd earn on the simulated routes
+	// with the proposed fees.
+	FeesAfter lnwire.MilliSatoshi
+}
+
+// FeeSimulationResult is the outcome of a fee simulation.
+type FeeSimulationResult struct {
+	// Samples is the number of sender and receiver pairs that were
+	// simulated.
+	Samples int
+
+	// RoutesFound is the number of sampled pairs that a route was found
+	// for with the current fees.
+	RoutesFound int
+
+	// Channels holds the estimates for each of our channels that has a
+	// known policy.
+	Channels []*ChannelRoutingEstimate
+}
+
+// feeSimulationGraph is a routingGraph that views the graph from the
+// perspective of a simulated sender and replaces the policies of some of our
+// channels.
+type feeSimulationGraph struct {
+	routingGraph
+
+	// sender is the node the simulated payments are sent from.
+	sender route.Vertex
+
+	// self is our own node.
+	self route.Vertex
+
+	// policies holds the policies that replace the ones of our channels in
+	// the graph, keyed by channel id.
+	policies map[uint64]*models.CachedEdgePolicy
+}
+
+// sourceNode returns the simulated sender.
+//
+// NOTE: Part of the routingGraph interface.
+func (g *feeSimulationGraph) sourceNode() route.Vertex {
+	return g.sender
+}
+
+// forEachNodeChannel calls the callback for every channel of the given node,
+// replacing the policies of our own channels if requested.
+//
+// NOTE: Part of the routingGraph interface.
+func (g *feeSimulationGraph) forEachNodeChannel(nodePub route.Vertex,
+	cb func(channel *channeldb.DirectedChannel) error) error {
+
+	return g.routingGraph.forEachNodeChannel(nodePub,
+		func(channel *channeldb.DirectedChannel) error {
+			policy, ok := g.policies[channel.ChannelID]
+			if !ok || channel.OtherNode != g.self {
+				return cb(channel)
+			}
+
+			// Path finding may modify the policy, so every channel
+			// gets its own copy.
+			simulated := *channel
+			policyCopy := *policy
+			simulated.InPolicy = &policyCopy
+
+			return cb(&simulated)
+		},
+	)
+}
+
+// noBandwidthHints is a bandwidthHints implementation without any hints, as
+// we don't know the balances of the channels of simulated senders.
+type noBandwidthHints struct{}
+
+// availableChanBandwidth never returns a hint.
+//
+// NOTE: Part of the bandwidthHints interface.
+func (noBandwidthHints) availableChanBandwidth(uint64,
+	lnwire.MilliSatoshi) (lnwire.MilliSatoshi, bool) {
+
+	return 0, false
+}
+
+// SimulateFeeChanges estimates how the proposed fee changes would affect the
+// routing volume of our channels. It samples random sender and receiver pairs
+// from the graph and compares the routes between them with the current and the
+// proposed fees. The simulated senders don't know anything about the
+// liquidity of the channels, so they choose the cheapest route. This is an
+// estimate only and nothing is changed.
+func (r *ChannelRouter) SimulateFeeChanges(req *FeeSimulationRequest) (
+	*FeeSimulationResult, error) {
+
+	self := r.selfNode.PubKeyBytes
+
+	pairs, err := sampleFeeSimulationPairs(
+		r.cfg.Graph, self, req.Samples,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulateFeeChanges(
+		r.cachedGraph, self, pairs, req, r.SyncedHeight(),
+	)
+}
+
+// sampleFeeSimulationPairs returns the given number of random sender and
+// receiver pairs of nodes with channels, excluding our own node.
+func sampleFeeSimulationPairs(graph *channeldb.ChannelGraph,
+	self route.Vertex, samples int) ([]DirectedNodePair, error) {
+
+	var nodes []route.Vertex
+	err := graph.ForEachNodeCached(func(node route.Vertex,
+		chans map[uint64]*channeldb.DirectedChannel) error {
+
+		if node != self && len(chans) > 0 {
+			nodes = append(nodes, node)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) < 2 {
+		return nil, ErrNoFeeSimulationPairs
+	}
+
+	randIndex := func(n int) (int, error) {
+		i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+		if err != nil {
+			return 0, err
+		}
+
+		return int(i.Int64()), nil
+	}
+
+	pairs := make([]DirectedNodePair, 0, samples)
+	for len(pairs) < samples {
+		from, err := randIndex(len(nodes))
+		if err != nil {
+			return nil, err
+		}
+
+		// Draw the receiver from the remaining nodes, so it always
+		// differs from the sender.
+		to, err := randIndex(len(nodes) - 1)
+		if err != nil {
+			return nil, err
+		}
+		if to >= from {
+			to++
+		}
+
+		pairs = append(pairs, NewDirectedNodePair(
+			nodes[from], nodes[to],
+		))
+	}
+
+	return pairs, nil
+}
+
+// simulateFeeChanges runs the fee simulation for the given sender and receiver
+// pairs.
+func simulateFeeChanges(graph routingGraph, self route.Vertex,
+	pairs []DirectedNodePair, req *FeeSimulationRequest,
+	height uint32) (*FeeSimulationResult, error) {
+
+	// Collect the current policies of our channels, which are the incoming
+	// policies of our peers' channels from us.
+	var peers []route.Vertex
+	err := graph.forEachNodeChannel(self,
+		func(channel *channeldb.DirectedChannel) error {
+			peers = append(peers, channel.OtherNode)
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint64]*models.CachedEdgePolicy)
+	for _, peer := range peers {
+		err := graph.forEachNodeChannel(peer,
+			func(channel *channeldb.DirectedChannel) error {
+				if channel.OtherNode == self &&
+					channel.InPolicy != nil {
+
+					current[channel.ChannelID] =
+						channel.InPolicy
+				}
+
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	proposed := make(map[uint64]*models.CachedEdgePolicy)
+	for _, change := range req.Changes {
+		policy, ok := current[change.ChannelID]
+		if !ok {
+			return nil, fmt.Errorf("no policy known for channel %v",
+				change.ChannelID)
+		}
+
+		policyCopy := *policy
+		policyCopy.FeeBaseMSat = change.BaseFee
+		policyCopy.FeeProportionalMillionths = change.FeeRate
+		proposed[change.ChannelID] = &policyCopy
+	}
+
+	estimates := make(map[uint64]*ChannelRoutingEstimate, len(current))
+	result := &FeeSimulationResult{
+		Samples: len(pairs),
+	}
+	for chanID := range current {
+		estimate := &ChannelRoutingEstimate{
+			ChannelID: chanID,
+		}
+		estimates[chanID] = estimate
+		result.Channels = append(result.Channels, estimate)
+	}
+
+	for _, pair := range pairs {
+		before, err := simulateRoute(
+			graph, self, pair, nil, req, height,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if before != nil {
+			result.RoutesFound++
+		}
+
+		after, err := simulateRoute(
+			graph, self, pair, proposed, req, height,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		forEachForward(before, self, func(chanID uint64,
+			fee lnwire.MilliSatoshi) {
+
+			if estimate, ok := estimates[chanID]; ok {
+				estimate.RoutesBefore++
+				estimate.FeesBefore += fee
+			}
+		})
+		forEachForward(after, self, func(chanID uint64,
+			fee lnwire.MilliSatoshi) {
+
+			if estimate, ok := estimates[chanID]; ok {
+				estimate.RoutesAfter++
+				estimate.FeesAfter += fee
+			}
+		})
+	}
+
+	return result, nil
+}
+
+// simulateRoute returns the cheapest route between the pair with the given
+// policies of our channels, or nil if there is none.
+func simulateRoute(graph routingGraph, self route.Vertex,
+	pair DirectedNodePair, policies map[uint64]*models.CachedEdgePolicy,
+	req *FeeSimulationRequest, height uint32) (*route.Route, error) {
+
+	simGraph := &feeSimulationGraph{
+		routingGraph: graph,
+		sender:       pair.From,
+		self:         self,
+		policies:     policies,
+	}
+
+	restrictions := &RestrictParams{
+		ProbabilitySource: func(route.Vertex, route.Vertex,
+			lnwire.MilliSatoshi, btcutil.Amount) float64 {
+
+			return 1
+		},
+		FeeLimit:  lnwire.MaxMilliSatoshi,
+		CltvLimit: req.CltvLimit,
+	}
+
+	finalCltvDelta := uint16(zpay32.DefaultAssumedFinalCLTVDelta)
+	path, _, err := findPath(
+		&graphParams{
+			graph:          simGraph,
+			bandwidthHints: noBandwidthHints{},
+		},
+		restrictions, &PathFindingConfig{}, pair.From, pair.To,
+		req.Amount, 0, int32(height)+int32(finalCltvDelta),
+	)
+
+	// Not finding a route is an expected outcome of a sample.
+	var noRouteErr noRouteError
+	if errors.As(err, &noRouteErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newRoute(
+		pair.From, path, height, finalHopParams{
+			amt:       req.Amount,
+			totalAmt:  req.Amount,
+			cltvDelta: finalCltvDelta,
+		}, nil,
+	)
+}
+
+// forEachForward calls the callback with the outgoing channel and the fee
+// earned for every forward of the route by the given node.
+func forEachForward(rt *route.Route, node route.Vertex,
+	cb func(chanID uint64, fee lnwire.MilliSatoshi)) {
+
+	if rt == nil {
+		return
+	}
+
+	// The final hop doesn't forward, so it's skipped.
+	for i := 0; i < len(rt.Hops)-1; i++ {
+		if rt.Hops[i].PubKeyBytes != node {
+			continue
+		}
+
+		cb(rt.Hops[i+1].ChannelID, rt.HopFee(i))
+	}
+}
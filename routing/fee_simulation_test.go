@@ -0,0 +1,132 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulateFeeChanges tests that fee changes are simulated against
+// competing routes and parallel channels.
+func TestSimulateFeeChanges(t *testing.T) {
+	t.Parallel()
+
+	const (
+		ourFee        = lnwire.MilliSatoshi(2_000)
+		competitorFee = lnwire.MilliSatoshi(1_000)
+		lowerFee      = lnwire.MilliSatoshi(500)
+	)
+
+	policy := func(fee lnwire.MilliSatoshi) *testChannelPolicy {
+		return &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: fee,
+			MinHTLC:     1,
+			MaxHTLC:     100_000_000,
+		}
+	}
+
+	// The sender a can reach d either through us or through b. We have
+	// two parallel channels to d.
+	testChannels := []*testChannel{
+		symmetricTestChannel("a", "self", 100_000, policy(0), 1),
+		symmetricTestChannel("self", "d", 100_000, policy(ourFee), 2),
+		symmetricTestChannel("self", "d", 100_000, policy(ourFee), 3),
+		symmetricTestChannel("a", "b", 100_000, policy(0), 4),
+		symmetricTestChannel(
+			"b", "d", 100_000, policy(competitorFee), 5,
+		),
+	}
+
+	ctx := newPathFindingTestContext(t, true, testChannels, "self")
+	self := ctx.keyFromAlias("self")
+
+	sourceNode, err := ctx.graph.SourceNode()
+	require.NoError(t, err)
+
+	routingGraph, err := NewCachedGraph(sourceNode, ctx.graph)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, routingGraph.Close())
+	})
+
+	pairs := []DirectedNodePair{
+		NewDirectedNodePair(
+			ctx.keyFromAlias("a"), ctx.keyFromAlias("d"),
+		),
+	}
+
+	simulate := func(
+		changes ...FeeChange) map[uint64]*ChannelRoutingEstimate {
+
+		result, err := simulateFeeChanges(
+			routingGraph, self, pairs, &FeeSimulationRequest{
+				Changes:   changes,
+				Amount:    10_000_000,
+				CltvLimit: 1_000,
+			}, 100,
+		)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Samples)
+		require.Equal(t, 1, result.RoutesFound)
+
+		estimates := make(map[uint64]*ChannelRoutingEstimate)
+		for _, estimate := range result.Channels {
+			estimates[estimate.ChannelID] = estimate
+		}
+
+		// Estimates are returned for all channels we have a policy
+		// for, including the ones without any traffic.
+		require.Len(t, estimates, 3)
+
+		return estimates
+	}
+
+	// Without any changes, the route through the competitor is cheaper.
+	estimates := simulate()
+	for _, estimate := range estimates {
+		require.Zero(t, estimate.RoutesBefore)
+		require.Zero(t, estimate.RoutesAfter)
+	}
+
+	// Lowering the fee of only one of the parallel channels has no effect,
+	// as senders need to assume that the more expensive channel is used.
+	estimates = simulate(FeeChange{
+		ChannelID: 2,
+		BaseFee:   lowerFee,
+	})
+	for _, estimate := range estimates {
+		require.Zero(t, estimate.RoutesAfter)
+	}
+
+	// Lowering the fees of both parallel channels attracts the route.
+	estimates = simulate(
+		FeeChange{ChannelID: 2, BaseFee: lowerFee},
+		FeeChange{ChannelID: 3, BaseFee: lowerFee},
+	)
+
+	var (
+		routesAfter int
+		feesAfter   lnwire.MilliSatoshi
+	)
+	for _, estimate := range estimates {
+		require.Zero(t, estimate.RoutesBefore)
+		require.Zero(t, estimate.FeesBefore)
+
+		routesAfter += estimate.RoutesAfter
+		feesAfter += estimate.FeesAfter
+	}
+	require.Equal(t, 1, routesAfter)
+	require.Equal(t, lowerFee, feesAfter)
+	require.Zero(t, estimates[1].RoutesAfter)
+
+	// Changes of channels we don't know a policy for are rejected.
+	_, err = simulateFeeChanges(
+		routingGraph, self, pairs, &FeeSimulationRequest{
+			Changes: []FeeChange{{ChannelID: 5}},
+			Amount:  10_000_000,
+		}, 100,
+	)
+	require.ErrorContains(t, err, "no policy known")
+}
@@ -0,0 +1,347 @@
+package localchans
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// maxRecentFailures is the maximum number of failed updates the queue
+	// keeps around to report them.
+	maxRecentFailures = 100
+
+	// DefaultBatchSize is the default number of channels whose policy is
+	// updated in a single batch.
+	DefaultBatchSize = 10
+
+	// DefaultInterval is the default interval between two batches of
+	// policy updates.
+	DefaultInterval = time.Minute
+)
+
+// QueueConfig holds the configuration of the policy update queue.
+type QueueConfig struct {
+	// BatchSize is the maximum number of channels that are updated in a
+	// single batch.
+	BatchSize int
+
+	// Interval is the interval between two batches. It must match the
+	// interval of the ticker.
+	Interval time.Duration
+
+	// Ticker is the ticker that triggers the next batch.
+	Ticker ticker.Ticker
+
+	// Clock is used to record when updates are queued and batches are
+	// applied.
+	Clock clock.Clock
+
+	// UpdatePolicy updates, persists and gossips the policy of the given
+	// channels.
+	UpdatePolicy func(routing.ChannelPolicy, ...wire.OutPoint) (
+		[]*lnrpc.FailedUpdate, error)
+}
+
+// QueuedUpdate is a policy update of a single channel.
+type QueuedUpdate struct {
+	// ChanPoint is the channel point of the channel to update.
+	ChanPoint wire.OutPoint
+
+	// Policy is the new policy of the channel.
+	Policy routing.ChannelPolicy
+
+	// QueuedAt is the time at which the update was queued.
+	QueuedAt time.Time
+}
+
+// PendingUpdate is a queued update along with the time at which it is
+// expected to be applied.
+type PendingUpdate struct {
+	QueuedUpdate
+
+	// EstimatedApplyTime is the time at which the update is expected to be
+	// applied.
+	EstimatedApplyTime time.Time
+}
+
+// QueueStatus is a snapshot of the progress of the policy update queue.
+type QueueStatus struct {
+	// Pending are the queued updates in the order in which they will be
+	// applied.
+	Pending []PendingUpdate
+
+	// NumApplied is the number of channel updates that were applied.
+	NumApplied uint64
+
+	// NumFailed is the number of channel updates that failed.
+	NumFailed uint64
+
+	// RecentFailures are the most recent failed updates, oldest first.
+	RecentFailures []*lnrpc.FailedUpdate
+
+	// NextBatch is the time of the next batch.
+	NextBatch time.Time
+}
+
+// PolicyUpdateQueue applies policy updates of many channels gradually. Every
+// update is gossiped to the whole network, and other nodes only accept a
+// limited number of updates of a channel within an interval. Updating all our
+// channels at once would therefore be rate limited, so the queue applies at
+// most one batch of updates per interval instead.
+type PolicyUpdateQueue struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *QueueConfig
+
+	// applyMtx serializes the application of updates, so that an urgent
+	// update can't be overwritten by a batch that is being applied
+	// concurrently.
+	applyMtx sync.Mutex
+
+	// mtx guards the fields below.
+	mtx            sync.Mutex
+	queue          []*QueuedUpdate
+	lastBatch      time.Time
+	numApplied     uint64
+	numFailed      uint64
+	recentFailures []*lnrpc.FailedUpdate
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPolicyUpdateQueue returns a new policy update queue with the given
+// config.
+func NewPolicyUpdateQueue(cfg *QueueConfig) (*PolicyUpdateQueue, error) {
+	if cfg.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %v",
+			cfg.BatchSize)
+	}
+
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %v",
+			cfg.Interval)
+	}
+
+	return &PolicyUpdateQueue{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start starts applying queued updates.
+func (q *PolicyUpdateQueue) Start() error {
+	q.started.Do(func() {
+		log.Infof("Policy update queue starting, applying up to %v "+
+			"updates every %v", q.cfg.BatchSize, q.cfg.Interval)
+
+		q.mtx.Lock()
+		q.lastBatch = q.cfg.Clock.Now()
+		q.mtx.Unlock()
+
+		q.cfg.Ticker.Resume()
+
+		q.wg.Add(1)
+		go q.batchLoop()
+	})
+
+	return nil
+}
+
+// Stop stops applying queued updates. Updates that are still queued are
+// dropped.
+func (q *PolicyUpdateQueue) Stop() error {
+	q.stopped.Do(func() {
+		log.Info("Policy update queue shutting down...")
+		defer log.Debug("Policy update queue shutdown complete")
+
+		close(q.quit)
+		q.wg.Wait()
+
+		q.cfg.Ticker.Stop()
+	})
+
+	return nil
+}
+
+// Enqueue adds the given updates to the queue. If a channel already has a
+// queued update, it is replaced and keeps its position in the queue.
+func (q *PolicyUpdateQueue) Enqueue(updates ...QueuedUpdate) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	now := q.cfg.Clock.Now()
+
+	queued := make(map[wire.OutPoint]*QueuedUpdate, len(q.queue))
+	for _, update := range q.queue {
+		queued[update.ChanPoint] = update
+	}
+
+	for _, update := range updates {
+		if existing, ok := queued[update.ChanPoint]; ok {
+			existing.Policy = update.Policy
+			existing.QueuedAt = now
+
+			continue
+		}
+
+		update := update
+		update.QueuedAt = now
+		q.queue = append(q.queue, &update)
+		queued[update.ChanPoint] = &update
+	}
+
+	log.Debugf("Queued %v policy updates, %v pending", len(updates),
+		len(q.queue))
+}
+
+// ApplyNow applies the given policy to a single channel right away, without
+// waiting for its turn. A queued update of the channel is dropped, as it would
+// otherwise overwrite the new policy later on.
+func (q *PolicyUpdateQueue) ApplyNow(policy routing.ChannelPolicy,
+	chanPoint wire.OutPoint) ([]*lnrpc.FailedUpdate, error) {
+
+	q.applyMtx.Lock()
+	defer q.applyMtx.Unlock()
+
+	q.mtx.Lock()
+	for i, update := range q.queue {
+		if update.ChanPoint != chanPoint {
+			continue
+		}
+
+		log.Debugf("Dropping queued policy update of channel %v in "+
+			"favor of urgent update", chanPoint)
+
+		q.queue = append(q.queue[:i], q.queue[i+1:]...)
+
+		break
+	}
+	q.mtx.Unlock()
+
+	return q.apply(&QueuedUpdate{ChanPoint: chanPoint, Policy: policy})
+}
+
+// Status returns a snapshot of the progress of the queue.
+func (q *PolicyUpdateQueue) Status() *QueueStatus {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	nextBatch := q.lastBatch.Add(q.cfg.Interval)
+
+	status := &QueueStatus{
+		Pending:    make([]PendingUpdate, 0, len(q.queue)),
+		NumApplied: q.numApplied,
+		NumFailed:  q.numFailed,
+		RecentFailures: append([]*lnrpc.FailedUpdate(nil),
+			q.recentFailures...),
+		NextBatch: nextBatch,
+	}
+	for i, update := range q.queue {
+		batch := time.Duration(i / q.cfg.BatchSize)
+		applyTime := nextBatch.Add(batch * q.cfg.Interval)
+
+		status.Pending = append(status.Pending, PendingUpdate{
+			QueuedUpdate:       *update,
+			EstimatedApplyTime: applyTime,
+		})
+	}
+
+	return status
+}
+
+// batchLoop applies a batch of queued updates on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (q *PolicyUpdateQueue) batchLoop() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.cfg.Ticker.Ticks():
+			q.applyBatch()
+
+		case <-q.quit:
+			return
+		}
+	}
+}
+
+// applyBatch applies the next batch of queued updates.
+func (q *PolicyUpdateQueue) applyBatch() {
+	q.applyMtx.Lock()
+	defer q.applyMtx.Unlock()
+
+	q.mtx.Lock()
+	q.lastBatch = q.cfg.Clock.Now()
+
+	batchSize := q.cfg.BatchSize
+	if batchSize > len(q.queue) {
+		batchSize = len(q.queue)
+	}
+	batch := q.queue[:batchSize]
+	q.queue = q.queue[batchSize:]
+	remaining := len(q.queue)
+	q.mtx.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	// The policies of the channels differ in general, so every update is
+	// applied on its own. A failed update is recorded and doesn't hold
+	// back the rest of the batch.
+	for _, update := range batch {
+		_, _ = q.apply(update)
+	}
+
+	log.Infof("Applied a batch of %v policy updates, %v remaining",
+		len(batch), remaining)
+}
+
+// apply applies a single update and records the outcome.
+//
+// NOTE: The caller MUST hold the applyMtx.
+func (q *PolicyUpdateQueue) apply(update *QueuedUpdate) (
+	[]*lnrpc.FailedUpdate, error) {
+
+	failed, err := q.cfg.UpdatePolicy(update.Policy, update.ChanPoint)
+	if err != nil {
+		failed = []*lnrpc.FailedUpdate{makeFailureItem(
+			update.ChanPoint,
+			lnrpc.UpdateFailure_UPDATE_FAILURE_INTERNAL_ERR,
+			err.Error(),
+		)}
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if len(failed) == 0 {
+		q.numApplied++
+
+		return nil, err
+	}
+
+	for _, failure := range failed {
+		log.Warnf("Unable to update policy of channel %v: %v",
+			update.ChanPoint, failure.UpdateError)
+	}
+
+	q.numFailed++
+	q.recentFailures = append(q.recentFailures, failed...)
+	if len(q.recentFailures) > maxRecentFailures {
+		q.recentFailures = q.recentFailures[len(q.recentFailures)-
+			maxRecentFailures:]
+	}
+
+	return failed, err
+}
@@ -0,0 +1,151 @@
+package localchans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPolicyUpdateQueue tests that queued policy updates are applied in
+// batches, that queued updates of a channel are replaced, and that urgent
+// updates skip the queue.
+func TestPolicyUpdateQueue(t *testing.T) {
+	t.Parallel()
+
+	const interval = time.Minute
+
+	var (
+		chanA      = wire.OutPoint{Hash: chainhash.Hash{1}}
+		chanB      = wire.OutPoint{Hash: chainhash.Hash{2}}
+		chanC      = wire.OutPoint{Hash: chainhash.Hash{3}}
+		chanFailed = wire.OutPoint{Hash: chainhash.Hash{4}}
+	)
+
+	policy := func(baseFee lnwire.MilliSatoshi) routing.ChannelPolicy {
+		return routing.ChannelPolicy{
+			FeeSchema: routing.FeeSchema{
+				BaseFee: baseFee,
+			},
+			TimeLockDelta: 80,
+		}
+	}
+
+	type appliedUpdate struct {
+		chanPoint wire.OutPoint
+		baseFee   lnwire.MilliSatoshi
+	}
+	applied := make(chan appliedUpdate, 10)
+
+	updatePolicy := func(newPolicy routing.ChannelPolicy,
+		chanPoints ...wire.OutPoint) ([]*lnrpc.FailedUpdate, error) {
+
+		require.Len(t, chanPoints, 1)
+		if chanPoints[0] == chanFailed {
+			return []*lnrpc.FailedUpdate{makeFailureItem(
+				chanFailed,
+				lnrpc.UpdateFailure_UPDATE_FAILURE_NOT_FOUND,
+				"not found",
+			)}, nil
+		}
+
+		applied <- appliedUpdate{
+			chanPoint: chanPoints[0],
+			baseFee:   newPolicy.BaseFee,
+		}
+
+		return nil, nil
+	}
+
+	startTime := time.Unix(1_700_000_000, 0)
+	testClock := clock.NewTestClock(startTime)
+	testTicker := ticker.NewForce(interval)
+
+	queue, err := NewPolicyUpdateQueue(&QueueConfig{
+		BatchSize:    2,
+		Interval:     interval,
+		Ticker:       testTicker,
+		Clock:        testClock,
+		UpdatePolicy: updatePolicy,
+	})
+	require.NoError(t, err)
+	require.NoError(t, queue.Start())
+	t.Cleanup(func() {
+		require.NoError(t, queue.Stop())
+	})
+
+	// Queue an update of three channels, then replace the update of the
+	// second one. It must keep its position in the queue.
+	queue.Enqueue(
+		QueuedUpdate{ChanPoint: chanA, Policy: policy(1)},
+		QueuedUpdate{ChanPoint: chanB, Policy: policy(1)},
+		QueuedUpdate{ChanPoint: chanC, Policy: policy(1)},
+	)
+	queue.Enqueue(QueuedUpdate{ChanPoint: chanB, Policy: policy(2)})
+
+	status := queue.Status()
+	require.Len(t, status.Pending, 3)
+	require.Equal(t, startTime.Add(interval), status.NextBatch)
+
+	expectedPending := []struct {
+		chanPoint wire.OutPoint
+		applyTime time.Time
+	}{
+		{chanA, startTime.Add(interval)},
+		{chanB, startTime.Add(interval)},
+		{chanC, startTime.Add(2 * interval)},
+	}
+	for i, expected := range expectedPending {
+		pending := status.Pending[i]
+		require.Equal(t, expected.chanPoint, pending.ChanPoint)
+		require.Equal(t, expected.applyTime, pending.EstimatedApplyTime)
+	}
+
+	// An urgent update of the last channel is applied right away and
+	// drops its queued update.
+	failed, err := queue.ApplyNow(policy(3), chanC)
+	require.NoError(t, err)
+	require.Empty(t, failed)
+	require.Equal(t, appliedUpdate{chanC, 3}, <-applied)
+
+	status = queue.Status()
+	require.Len(t, status.Pending, 2)
+	require.EqualValues(t, 1, status.NumApplied)
+
+	// The next tick applies the remaining updates in a single batch, with
+	// the latest policy of the second channel.
+	testClock.SetTime(startTime.Add(interval))
+	testTicker.Force <- testClock.Now()
+
+	require.Equal(t, appliedUpdate{chanA, 1}, <-applied)
+	require.Equal(t, appliedUpdate{chanB, 2}, <-applied)
+
+	require.Eventually(t, func() bool {
+		status := queue.Status()
+		return len(status.Pending) == 0 && status.NumApplied == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, startTime.Add(2*interval), queue.Status().NextBatch)
+
+	// Failed updates are recorded so they can be reported.
+	queue.Enqueue(QueuedUpdate{ChanPoint: chanFailed, Policy: policy(1)})
+	testTicker.Force <- testClock.Now()
+
+	require.Eventually(t, func() bool {
+		return queue.Status().NumFailed == 1
+	}, time.Second, 10*time.Millisecond)
+
+	status = queue.Status()
+	require.Empty(t, status.Pending)
+	require.Len(t, status.RecentFailures, 1)
+	require.Equal(
+		t, lnrpc.UpdateFailure_UPDATE_FAILURE_NOT_FOUND,
+		status.RecentFailures[0].Reason,
+	)
+}
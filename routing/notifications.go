@@ -13,7 +13,24 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/queue"
+)
+
+const (
+	// topologyHistorySize is the number of most recent topology changes
+	// that are kept in memory, so clients can resume their subscription
+	// after a disconnect without missing any changes.
+	topologyHistorySize = 1000
+)
+
+var (
+	// ErrTopologySeqNumUnavailable is returned when a client attempts to
+	// resume a topology subscription from a sequence number whose
+	// subsequent changes are no longer (or were never) known.
+	ErrTopologySeqNumUnavailable = errors.New("topology changes after " +
+		"the given sequence number are not available")
 )
 
 // TopologyClient represents an intent to receive notifications from the
@@ -27,28 +44,24 @@ type TopologyClient struct {
 	// TODO(roasbeef): chan for each update type instead?
 	TopologyChanges <-chan *TopologyChange
 
+	// SeqNum is the sequence number of the latest topology change that
+	// was applied when the client subscribed. All changes sent over
+	// TopologyChanges have a higher sequence number, unless the client
+	// resumed from an earlier sequence number.
+	SeqNum uint64
+
 	// Cancel is a function closure that should be executed when the client
 	// wishes to cancel their notification intent. Doing so allows the
 	// ChannelRouter to free up resources.
 	Cancel func()
 }
 
-// topologyClientUpdate is a message sent to the channel router to either
-// register a new topology client or re-register an existing client.
+// topologyClientUpdate is a message sent to the channel router to cancel an
+// existing client's notifications.
 type topologyClientUpdate struct {
-	// cancel indicates if the update to the client is cancelling an
-	// existing client's notifications. If not then this update will be to
-	// register a new set of notifications.
-	cancel bool
-
-	// clientID is the unique identifier for this client. Any further
-	// updates (deleting or adding) to this notification client will be
-	// dispatched according to the target clientID.
+	// clientID is the unique identifier for the client whose notifications
+	// should be canceled.
 	clientID uint64
-
-	// ntfnChan is a *send-only* channel in which notifications should be
-	// sent over from router -> client.
-	ntfnChan chan<- *TopologyChange
 }
 
 // SubscribeTopology returns a new topology client which can be used by the
@@ -57,6 +70,26 @@ type topologyClientUpdate struct {
 // nodes appearing, node updating their attributes, new channels, channels
 // closing, and updates in the routing policies of a channel's directed edges.
 func (r *ChannelRouter) SubscribeTopology() (*TopologyClient, error) {
+	return r.subscribeTopology(false, 0)
+}
+
+// SubscribeTopologyFrom returns a new topology client just like
+// SubscribeTopology, but first replays all known topology changes with a
+// sequence number greater than the given one. This allows a client to resume
+// its subscription after a disconnect. ErrTopologySeqNumUnavailable is
+// returned if the changes after the given sequence number are no longer
+// available.
+func (r *ChannelRouter) SubscribeTopologyFrom(
+	seqNum uint64) (*TopologyClient, error) {
+
+	return r.subscribeTopology(true, seqNum)
+}
+
+// subscribeTopology registers a new topology client, optionally replaying the
+// changes after the given sequence number.
+func (r *ChannelRouter) subscribeTopology(resume bool,
+	seqNum uint64) (*TopologyClient, error) {
+
 	// If the router is not yet started, return an error to avoid a
 	// deadlock waiting for it to handle the subscription request.
 	if atomic.LoadUint32(&r.started) == 0 {
@@ -71,23 +104,47 @@ func (r *ChannelRouter) SubscribeTopology() (*TopologyClient, error) {
 		clientID)
 
 	ntfnChan := make(chan *TopologyChange, 10)
-
-	select {
-	case r.ntfnClientUpdates <- &topologyClientUpdate{
-		cancel:   false,
-		clientID: clientID,
+	client := &topologyClient{
 		ntfnChan: ntfnChan,
-	}:
-	case <-r.quit:
-		return nil, errors.New("ChannelRouter shutting down")
+		queue:    queue.NewConcurrentQueue(20),
+		exit:     make(chan struct{}),
 	}
+	client.queue.Start()
+
+	// We hold the topology mutex while registering the client, so no new
+	// change can be dispatched in between looking up the backlog and
+	// adding the client.
+	r.topologyMtx.Lock()
+
+	var backlog []*TopologyChange
+	if resume {
+		var err error
+		backlog, err = r.topologyHistory.since(seqNum)
+		if err != nil {
+			r.topologyMtx.Unlock()
+			client.queue.Stop()
+
+			return nil, err
+		}
+	}
+
+	lastSeqNum := r.topologyHistory.lastSeqNum
+	r.topologyClients.Store(clientID, client)
+	for _, change := range backlog {
+		client.queue.ChanIn() <- change
+	}
+
+	r.topologyMtx.Unlock()
+
+	client.wg.Add(1)
+	go r.forwardTopologyChanges(client)
 
 	return &TopologyClient{
 		TopologyChanges: ntfnChan,
+		SeqNum:          lastSeqNum,
 		Cancel: func() {
 			select {
 			case r.ntfnClientUpdates <- &topologyClientUpdate{
-				cancel:   true,
 				clientID: clientID,
 			}:
 			case <-r.quit:
@@ -107,6 +164,10 @@ type topologyClient struct {
 	// topologyClient client.
 	ntfnChan chan<- *TopologyChange
 
+	// queue buffers the notifications for the client, so they are
+	// delivered in order without blocking the channel router.
+	queue *queue.ConcurrentQueue
+
 	// exit is a channel that is used internally by the channel router to
 	// cancel any active un-consumed goroutine notifications.
 	exit chan struct{}
@@ -114,41 +175,88 @@ type topologyClient struct {
 	wg sync.WaitGroup
 }
 
-// notifyTopologyChange notifies all registered clients of a new change in
-// graph topology in a non-blocking.
+// forwardTopologyChanges delivers the queued notifications of a client in
+// order until the client is canceled or the router exits.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *ChannelRouter) forwardTopologyChanges(c *topologyClient) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case item, ok := <-c.queue.ChanOut():
+			if !ok {
+				return
+			}
+
+			select {
+			case c.ntfnChan <- item.(*TopologyChange):
+
+			case <-c.exit:
+				return
+
+			case <-r.quit:
+				return
+			}
+
+		// If the client cancels the notifications, then we'll exit
+		// early.
+		case <-c.exit:
+			return
+
+		// Similarly, if the ChannelRouter itself exists early, then
+		// we'll also exit ourselves.
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// stopTopologyClients removes all topology clients and stops their queues once
+// their forwarding goroutines have exited. It must only be called after the
+// router's quit channel was closed.
+func (r *ChannelRouter) stopTopologyClients() {
+	r.topologyClients.Range(func(clientID uint64,
+		client *topologyClient) bool {
+
+		r.topologyClients.Delete(clientID)
+
+		client.wg.Wait()
+		client.queue.Stop()
+
+		return true
+	})
+}
+
+// notifyTopologyChange assigns the next sequence number to the change and
+// notifies all registered clients of it in a non-blocking manner.
 func (r *ChannelRouter) notifyTopologyChange(topologyDiff *TopologyChange) {
+	r.topologyMtx.Lock()
+	defer r.topologyMtx.Unlock()
 
-	// notifyClient is a helper closure that will send topology updates to
-	// the given client.
-	notifyClient := func(clientID uint64, client *topologyClient) bool {
-		client.wg.Add(1)
+	r.topologyHistory.add(topologyDiff)
 
+	// notifyClient is a helper closure that will queue topology updates
+	// for the given client.
+	notifyClient := func(clientID uint64, client *topologyClient) bool {
 		log.Tracef("Sending topology notification to client=%v, "+
-			"NodeUpdates=%v, ChannelEdgeUpdates=%v, "+
-			"ClosedChannels=%v", clientID,
+			"SeqNum=%v, NodeUpdates=%v, ChannelEdgeUpdates=%v, "+
+			"ClosedChannels=%v", clientID, topologyDiff.SeqNum,
 			len(topologyDiff.NodeUpdates),
 			len(topologyDiff.ChannelEdgeUpdates),
 			len(topologyDiff.ClosedChannels))
 
-		go func(c *topologyClient) {
-			defer c.wg.Done()
+		select {
+		case client.queue.ChanIn() <- topologyDiff:
 
-			select {
-
-			// In this case we'll try to send the notification
-			// directly to the upstream client consumer.
-			case c.ntfnChan <- topologyDiff:
-
-			// If the client cancels the notifications, then we'll
-			// exit early.
-			case <-c.exit:
+		// If the client cancels the notifications, then we'll skip
+		// it.
+		case <-client.exit:
 
-			// Similarly, if the ChannelRouter itself exists early,
-			// then we'll also exit ourselves.
-			case <-r.quit:
-
-			}
-		}(client)
+		// Similarly, if the ChannelRouter itself exists early, then
+		// there's no point in queueing the notification.
+		case <-r.quit:
+		}
 
 		// Always return true here so the following Range will iterate
 		// all clients.
@@ -160,10 +268,77 @@ func (r *ChannelRouter) notifyTopologyChange(topologyDiff *TopologyChange) {
 	r.topologyClients.Range(notifyClient)
 }
 
+// topologyChangeHistory keeps track of the sequence numbers assigned to
+// topology changes and retains the most recent changes, so clients can resume
+// their subscriptions.
+type topologyChangeHistory struct {
+	// lastSeqNum is the sequence number of the latest topology change.
+	lastSeqNum uint64
+
+	// changes holds the most recent topology changes, ordered by their
+	// sequence number.
+	changes []*TopologyChange
+
+	// maxSize is the maximum number of changes that are retained.
+	maxSize int
+}
+
+// newTopologyChangeHistory creates a new topology change history that retains
+// up to maxSize changes. Sequence numbers start right after startSeqNum.
+func newTopologyChangeHistory(maxSize int,
+	startSeqNum uint64) *topologyChangeHistory {
+
+	return &topologyChangeHistory{
+		lastSeqNum: startSeqNum,
+		maxSize:    maxSize,
+	}
+}
+
+// add assigns the next sequence number to the given change and adds it to the
+// history, dropping the oldest change if the history is full.
+func (h *topologyChangeHistory) add(change *TopologyChange) {
+	h.lastSeqNum++
+	change.SeqNum = h.lastSeqNum
+
+	h.changes = append(h.changes, change)
+	if len(h.changes) > h.maxSize {
+		h.changes = h.changes[1:]
+	}
+}
+
+// since returns all retained changes with a sequence number greater than the
+// given one. ErrTopologySeqNumUnavailable is returned if some of those changes
+// are no longer retained or if the sequence number is unknown.
+func (h *topologyChangeHistory) since(
+	seqNum uint64) ([]*TopologyChange, error) {
+
+	if seqNum > h.lastSeqNum {
+		return nil, ErrTopologySeqNumUnavailable
+	}
+
+	// The first retained change is the one right after the oldest
+	// sequence number a client can resume from.
+	oldestSeqNum := h.lastSeqNum - uint64(len(h.changes))
+	if seqNum < oldestSeqNum {
+		return nil, ErrTopologySeqNumUnavailable
+	}
+
+	backlog := h.changes[seqNum-oldestSeqNum:]
+	result := make([]*TopologyChange, len(backlog))
+	copy(result, backlog)
+
+	return result, nil
+}
+
 // TopologyChange represents a new set of modifications to the channel graph.
 // Topology changes will be dispatched in real-time as the ChannelGraph
 // validates and process modifications to the authenticated channel graph.
 type TopologyChange struct {
+	// SeqNum is the sequence number of the change. Sequence numbers are
+	// strictly increasing, also across restarts, but they are only
+	// contiguous within the lifetime of a single ChannelRouter.
+	SeqNum uint64
+
 	// NodeUpdates is a slice of nodes which are either new to the channel
 	// graph, or have had their attributes updated in an authenticated
 	// manner.
@@ -321,19 +496,11 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 	// Any node announcement maps directly to a NetworkNodeUpdate struct.
 	// No further data munging or db queries are required.
 	case *channeldb.LightningNode:
-		pubKey, err := m.PubKey()
+		nodeUpdate, err := newNetworkNodeUpdate(m)
 		if err != nil {
 			return err
 		}
 
-		nodeUpdate := &NetworkNodeUpdate{
-			Addresses:   m.Addresses,
-			IdentityKey: pubKey,
-			Alias:       m.Alias,
-			Color:       EncodeHexColor(m.Color),
-			Features:    m.Features.Clone(),
-		}
-
 		update.NodeUpdates = append(update.NodeUpdates, nodeUpdate)
 		return nil
 
@@ -354,38 +521,10 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 				err)
 		}
 
-		// If the flag is one, then the advertising node is actually
-		// the second node.
-		sourceNode := edgeInfo.NodeKey1
-		connectingNode := edgeInfo.NodeKey2
-		if m.ChannelFlags&lnwire.ChanUpdateDirection == 1 {
-			sourceNode = edgeInfo.NodeKey2
-			connectingNode = edgeInfo.NodeKey1
-		}
-
-		aNode, err := sourceNode()
+		edgeUpdate, err := newChannelEdgeUpdate(edgeInfo, m)
 		if err != nil {
 			return err
 		}
-		cNode, err := connectingNode()
-		if err != nil {
-			return err
-		}
-
-		edgeUpdate := &ChannelEdgeUpdate{
-			ChanID:          m.ChannelID,
-			ChanPoint:       edgeInfo.ChannelPoint,
-			TimeLockDelta:   m.TimeLockDelta,
-			Capacity:        edgeInfo.Capacity,
-			MinHTLC:         m.MinHTLC,
-			MaxHTLC:         m.MaxHTLC,
-			BaseFee:         m.FeeBaseMSat,
-			FeeRate:         m.FeeProportionalMillionths,
-			AdvertisingNode: aNode,
-			ConnectingNode:  cNode,
-			Disabled:        m.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
-			ExtraOpaqueData: m.ExtraOpaqueData,
-		}
 
 		// TODO(roasbeef): add bit to toggle
 		update.ChannelEdgeUpdates = append(update.ChannelEdgeUpdates,
@@ -398,6 +537,121 @@ func addToTopologyChange(graph *channeldb.ChannelGraph, update *TopologyChange,
 	}
 }
 
+// newNetworkNodeUpdate creates a node update from the given node
+// announcement.
+func newNetworkNodeUpdate(
+	node *channeldb.LightningNode) (*NetworkNodeUpdate, error) {
+
+	pubKey, err := node.PubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NetworkNodeUpdate{
+		Addresses:   node.Addresses,
+		IdentityKey: pubKey,
+		Alias:       node.Alias,
+		Color:       EncodeHexColor(node.Color),
+		Features:    node.Features.Clone(),
+	}, nil
+}
+
+// newChannelEdgeUpdate creates an edge update from the given channel and one
+// of its policies.
+func newChannelEdgeUpdate(edgeInfo *models.ChannelEdgeInfo,
+	policy *models.ChannelEdgePolicy) (*ChannelEdgeUpdate, error) {
+
+	// If the flag is one, then the advertising node is actually the
+	// second node.
+	sourceNode := edgeInfo.NodeKey1
+	connectingNode := edgeInfo.NodeKey2
+	if policy.ChannelFlags&lnwire.ChanUpdateDirection == 1 {
+		sourceNode = edgeInfo.NodeKey2
+		connectingNode = edgeInfo.NodeKey1
+	}
+
+	aNode, err := sourceNode()
+	if err != nil {
+		return nil, err
+	}
+	cNode, err := connectingNode()
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := policy.ChannelFlags&lnwire.ChanUpdateDisabled != 0
+
+	return &ChannelEdgeUpdate{
+		ChanID:          policy.ChannelID,
+		ChanPoint:       edgeInfo.ChannelPoint,
+		TimeLockDelta:   policy.TimeLockDelta,
+		Capacity:        edgeInfo.Capacity,
+		MinHTLC:         policy.MinHTLC,
+		MaxHTLC:         policy.MaxHTLC,
+		BaseFee:         policy.FeeBaseMSat,
+		FeeRate:         policy.FeeProportionalMillionths,
+		AdvertisingNode: aNode,
+		ConnectingNode:  cNode,
+		Disabled:        disabled,
+		ExtraOpaqueData: policy.ExtraOpaqueData,
+	}, nil
+}
+
+// TopologySnapshot returns the current state of the channel graph as a single
+// topology change that contains an update for every announced node and every
+// channel policy. To not miss any changes, a client should subscribe to
+// topology changes before taking the snapshot. Changes that were applied
+// after subscribing may then also be contained in the snapshot already.
+func (r *ChannelRouter) TopologySnapshot() (*TopologyChange, error) {
+	snapshot := &TopologyChange{}
+	err := r.cfg.Graph.ForEachNode(func(_ kvdb.RTx,
+		node *channeldb.LightningNode) error {
+
+		// Nodes without an announcement don't have any attributes
+		// worth sending.
+		if !node.HaveNodeAnnouncement {
+			return nil
+		}
+
+		nodeUpdate, err := newNetworkNodeUpdate(node)
+		if err != nil {
+			return err
+		}
+		snapshot.NodeUpdates = append(snapshot.NodeUpdates, nodeUpdate)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.cfg.Graph.ForEachChannel(func(info *models.ChannelEdgeInfo,
+		policy1, policy2 *models.ChannelEdgePolicy) error {
+
+		policies := []*models.ChannelEdgePolicy{policy1, policy2}
+		for _, policy := range policies {
+			if policy == nil {
+				continue
+			}
+
+			edgeUpdate, err := newChannelEdgeUpdate(info, policy)
+			if err != nil {
+				return err
+			}
+			snapshot.ChannelEdgeUpdates = append(
+				snapshot.ChannelEdgeUpdates, edgeUpdate,
+			)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
 // EncodeHexColor takes a color and returns it in hex code format.
 func EncodeHexColor(color color.RGBA) string {
 	return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
@@ -999,3 +999,136 @@ func TestEncodeHexColor(t *testing.T) {
 		}
 	}
 }
+
+// TestTopologyChangeHistory tests that the topology change history assigns
+// increasing sequence numbers and only returns backlogs it fully retains.
+func TestTopologyChangeHistory(t *testing.T) {
+	t.Parallel()
+
+	const (
+		maxSize     = 3
+		startSeqNum = 100
+	)
+	history := newTopologyChangeHistory(maxSize, startSeqNum)
+
+	// Without any changes, only the start sequence number is known.
+	backlog, err := history.since(startSeqNum)
+	require.NoError(t, err)
+	require.Empty(t, backlog)
+
+	_, err = history.since(startSeqNum + 1)
+	require.ErrorIs(t, err, ErrTopologySeqNumUnavailable)
+
+	// Add more changes than the history retains.
+	for i := 0; i < maxSize+2; i++ {
+		change := &TopologyChange{}
+		history.add(change)
+		require.EqualValues(t, startSeqNum+i+1, change.SeqNum)
+	}
+
+	// The first two changes were dropped, so we can only resume from the
+	// second one onward.
+	_, err = history.since(startSeqNum + 1)
+	require.ErrorIs(t, err, ErrTopologySeqNumUnavailable)
+
+	backlog, err = history.since(startSeqNum + 2)
+	require.NoError(t, err)
+	require.Len(t, backlog, maxSize)
+	for i, change := range backlog {
+		require.EqualValues(t, startSeqNum+i+3, change.SeqNum)
+	}
+
+	backlog, err = history.since(startSeqNum + maxSize + 2)
+	require.NoError(t, err)
+	require.Empty(t, backlog)
+}
+
+// TestSubscribeTopologyFrom tests that a topology client can resume its
+// subscription and receives all missed changes in order.
+func TestSubscribeTopologyFrom(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxSingleNode(t, startingBlockHeight)
+
+	ntfnClient, err := ctx.router.SubscribeTopology()
+	require.NoError(t, err)
+
+	const numChanges = 5
+	for i := 0; i < numChanges; i++ {
+		ctx.router.notifyTopologyChange(&TopologyChange{
+			ClosedChannels: []*ClosedChanSummary{{
+				ChanID: uint64(i),
+			}},
+		})
+	}
+
+	// The first client receives all changes in order.
+	var seqNums []uint64
+	for i := 0; i < numChanges; i++ {
+		select {
+		case ntfn := <-ntfnClient.TopologyChanges:
+			require.EqualValues(t, i, ntfn.ClosedChannels[0].ChanID)
+			require.Greater(t, ntfn.SeqNum, ntfnClient.SeqNum)
+			seqNums = append(seqNums, ntfn.SeqNum)
+
+		case <-time.After(timeout):
+			t.Fatal("notification not received")
+		}
+	}
+	ntfnClient.Cancel()
+
+	// A client resuming after the second change receives the remaining
+	// ones with the same sequence numbers.
+	resumedClient, err := ctx.router.SubscribeTopologyFrom(seqNums[1])
+	require.NoError(t, err)
+	defer resumedClient.Cancel()
+
+	for i := 2; i < numChanges; i++ {
+		select {
+		case ntfn := <-resumedClient.TopologyChanges:
+			require.Equal(t, seqNums[i], ntfn.SeqNum)
+
+		case <-time.After(timeout):
+			t.Fatal("notification not received")
+		}
+	}
+
+	// Resuming from an unknown sequence number fails.
+	_, err = ctx.router.SubscribeTopologyFrom(seqNums[numChanges-1] + 1)
+	require.ErrorIs(t, err, ErrTopologySeqNumUnavailable)
+}
+
+// TestTopologyClientsStoppedOnQuit tests that all topology clients are removed
+// and their goroutines stopped once the router shuts down.
+func TestTopologyClientsStoppedOnQuit(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxSingleNode(t, startingBlockHeight)
+
+	_, err := ctx.router.SubscribeTopology()
+	require.NoError(t, err)
+	require.Equal(t, 1, ctx.router.topologyClients.Len())
+
+	var client *topologyClient
+	ctx.router.topologyClients.Range(func(_ uint64,
+		c *topologyClient) bool {
+
+		client = c
+		return true
+	})
+
+	require.NoError(t, ctx.router.Stop())
+	require.Zero(t, ctx.router.topologyClients.Len())
+
+	// The forwarding goroutine has exited, and a new item isn't picked
+	// up by the stopped queue.
+	client.wg.Wait()
+	select {
+	case client.queue.ChanIn() <- &TopologyChange{}:
+		t.Fatal("queue still running")
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
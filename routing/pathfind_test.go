@@ -169,7 +169,7 @@ func makeTestGraph(t *testing.T, useCache bool) (*channeldb.ChannelGraph,
 	graph, err := channeldb.NewChannelGraph(
 		backend, opts.RejectCacheSize, opts.ChannelCacheSize,
 		opts.BatchCommitInterval, opts.PreAllocCacheNumNodes,
-		useCache, false,
+		opts.MaxCacheChannels, useCache, false,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -553,6 +553,16 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		log.Errorf("Error reporting payment success to mc: %v", err)
 	}
 
+	// Mark the channels of the successful route as recently used, so they
+	// are kept in the in-memory graph if its size is limited.
+	if p.router.cfg.Graph != nil {
+		chanIDs := make([]uint64, 0, len(attempt.Route.Hops))
+		for _, hop := range attempt.Route.Hops {
+			chanIDs = append(chanIDs, hop.ChannelID)
+		}
+		p.router.cfg.Graph.MarkChannelsUsed(chanIDs...)
+	}
+
 	// In case of success we atomically store settle result to the DB move
 	// the shard to the settled state.
 	htlcAttempt, err := p.router.cfg.Control.SettleAttempt(
@@ -572,6 +582,12 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		return nil, err
 	}
 
+	if p.router.cfg.AttemptTracer != nil {
+		p.router.cfg.AttemptTracer.AttemptResolved(
+			p.identifier, htlcAttempt,
+		)
+	}
+
 	return &attemptResult{
 		attempt: htlcAttempt,
 	}, nil
@@ -602,8 +618,17 @@ func (p *paymentLifecycle) registerAttempt(rt *route.Route,
 	err = p.router.cfg.Control.RegisterAttempt(
 		p.identifier, &attempt.HTLCAttemptInfo,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.router.cfg.AttemptTracer != nil {
+		p.router.cfg.AttemptTracer.AttemptRegistered(
+			p.identifier, attempt,
+		)
+	}
 
-	return attempt, err
+	return attempt, nil
 }
 
 // createNewPaymentAttempt creates a new payment attempt from the given route.
@@ -945,6 +970,12 @@ func (p *paymentLifecycle) failAttempt(attemptID uint64,
 		return nil, err
 	}
 
+	if p.router.cfg.AttemptTracer != nil {
+		p.router.cfg.AttemptTracer.AttemptResolved(
+			p.identifier, attempt,
+		)
+	}
+
 	return &attemptResult{
 		attempt: attempt,
 		err:     sendError,
@@ -107,6 +107,14 @@ func DefaultBimodalConfig() BimodalConfig {
 	}
 }
 
+// BimodalDecayTimeFromHalfLife returns the BimodalDecayTime after which the
+// influence of a payment result is halved after the given half-life, so that
+// the bimodal estimator forgets its results at the same pace as the apriori
+// estimator with a PenaltyHalfLife of the same duration.
+func BimodalDecayTimeFromHalfLife(halfLife time.Duration) time.Duration {
+	return time.Duration(float64(halfLife) / math.Ln2)
+}
+
 // BimodalEstimator returns node and pair probabilities based on historical
 // payment results based on a liquidity distribution model of the LN. The main
 // function is to estimate the direct channel probability based on a depleted
@@ -706,3 +706,15 @@ func FuzzProbability(f *testing.F) {
 			successAmt, failAmt, amt)
 	})
 }
+
+// TestBimodalDecayTimeFromHalfLife tests that the influence of a result is
+// halved after the half-life the decay time was derived from.
+func TestBimodalDecayTimeFromHalfLife(t *testing.T) {
+	t.Parallel()
+
+	halfLife := time.Hour
+	decayTime := BimodalDecayTimeFromHalfLife(halfLife)
+
+	weight := math.Exp(-float64(halfLife) / float64(decayTime))
+	require.InDelta(t, 0.5, weight, 1e-9)
+}
@@ -0,0 +1,397 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// ProbeTargetRandom is the probe target policy that probes random
+	// nodes of the graph.
+	ProbeTargetRandom = "random"
+
+	// ProbeTargetStale is the probe target policy that probes the node
+	// pairs that mission control has the oldest results for, falling back
+	// to random nodes if it has none.
+	ProbeTargetStale = "stale"
+
+	// MinProbeInterval is the minimum interval between two probes.
+	MinProbeInterval = time.Minute
+
+	// DefaultProbeAmount is the default amount that is probed with.
+	DefaultProbeAmount = lnwire.MilliSatoshi(10_000_000)
+
+	// DefaultProbeFeeLimit is the default maximum fee of the route of a
+	// probe.
+	DefaultProbeFeeLimit = lnwire.MilliSatoshi(100_000)
+)
+
+var (
+	// ErrNoProbeTarget is returned if no node to probe could be found.
+	ErrNoProbeTarget = errors.New("no probe target found")
+
+	// errProbeSettled is returned if a probe was unexpectedly settled.
+	errProbeSettled = errors.New("probe was settled")
+)
+
+// ProberConfig holds the configuration of the Prober.
+type ProberConfig struct {
+	// Interval is the interval at which probes are sent.
+	Interval time.Duration
+
+	// Amount is the amount that is probed with.
+	Amount lnwire.MilliSatoshi
+
+	// FeeLimit is the maximum fee of the route of a probe. As probes are
+	// sent to a random payment hash, they can't be settled and never pay
+	// any fees. The limit bounds the fees in case a probe is settled
+	// regardless.
+	FeeLimit lnwire.MilliSatoshi
+
+	// CltvLimit is the maximum total time lock of the route of a probe,
+	// excluding the final cltv delta. It bounds the time the liquidity of
+	// the channels along the route is locked up if a hop holds the probe.
+	CltvLimit uint32
+
+	// FinalCltvDelta is the cltv delta used for the final hop of a probe.
+	FinalCltvDelta uint16
+
+	// TargetPolicy is the policy the probed nodes are selected with. It is
+	// either ProbeTargetRandom or ProbeTargetStale.
+	TargetPolicy string
+
+	// SelfNode is our own node, which is never probed.
+	SelfNode route.Vertex
+
+	// MissionControl is the mission control instance that learns from the
+	// probes and guides their path finding.
+	MissionControl *MissionControl
+
+	// ForEachNode calls the callback for each node of the graph that has
+	// channels.
+	ForEachNode func(cb func(node route.Vertex) error) error
+
+	// FindRoute finds a route for the probe.
+	FindRoute func(req *RouteRequest) (*route.Route, float64, error)
+
+	// SendToRoute sends the probe along the route and blocks until it is
+	// resolved.
+	SendToRoute func(hash lntypes.Hash, rt *route.Route) (
+		*channeldb.HTLCAttempt, error)
+
+	// DeletePayment deletes the payment of a resolved probe, so probes
+	// don't accumulate in the payment database.
+	DeletePayment func(hash lntypes.Hash) error
+}
+
+// validate checks that the config is sane.
+func (c *ProberConfig) validate() error {
+	if c.Interval < MinProbeInterval {
+		return fmt.Errorf("probe interval must be at least %v",
+			MinProbeInterval)
+	}
+
+	if c.Amount == 0 {
+		return errors.New("probe amount must be positive")
+	}
+
+	switch c.TargetPolicy {
+	case ProbeTargetRandom, ProbeTargetStale:
+
+	default:
+		return fmt.Errorf("unknown probe target policy %q",
+			c.TargetPolicy)
+	}
+
+	return nil
+}
+
+// Prober periodically sends probes through the network to keep mission
+// control informed about the liquidity of channels that our payments didn't
+// use recently. Probes are sent to a random payment hash, so they always fail
+// once they reach their target and never pay any fees. Only a single probe is
+// in flight at a time to limit the load put on other nodes.
+type Prober struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ProberConfig
+
+	// lastProbed holds the time each pair was last probed by the stale
+	// target policy, so that pairs whose probes fail before reaching them
+	// aren't probed over and over.
+	lastProbed map[DirectedNodePair]time.Time
+
+	// unresolved holds the payment hashes of probes that failed while
+	// their HTLC was still in flight, so their payments couldn't be
+	// deleted yet. The deletion is retried before every probe. Hashes
+	// that are still unresolved on shutdown are forgotten, their payments
+	// are failed by the router once it resumes them.
+	unresolved map[lntypes.Hash]struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProber returns a new Prober with the given config.
+func NewProber(cfg *ProberConfig) (*Prober, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Prober{
+		cfg:        cfg,
+		lastProbed: make(map[DirectedNodePair]time.Time),
+		unresolved: make(map[lntypes.Hash]struct{}),
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// Start starts sending probes.
+func (p *Prober) Start() error {
+	p.started.Do(func() {
+		log.Infof("Prober starting, probing %v every %v with target "+
+			"policy %v", p.cfg.Amount, p.cfg.Interval,
+			p.cfg.TargetPolicy)
+
+		p.wg.Add(1)
+		go p.probeLoop()
+	})
+
+	return nil
+}
+
+// Stop stops sending probes and waits for the probe in flight to be resolved.
+func (p *Prober) Stop() error {
+	p.stopped.Do(func() {
+		log.Info("Prober shutting down...")
+		defer log.Debug("Prober shutdown complete")
+
+		close(p.quit)
+		p.wg.Wait()
+	})
+
+	return nil
+}
+
+// probeLoop sends a probe at every interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *Prober) probeLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := p.probe()
+			switch {
+			// A lack of targets or routes is expected in small
+			// graphs and isn't worth more than a debug message.
+			case errors.Is(err, ErrNoProbeTarget):
+				log.Debugf("Skipping probe: %v", err)
+
+			case err != nil:
+				log.Warnf("Unable to probe: %v", err)
+			}
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// probe selects a target and sends a single probe to it.
+func (p *Prober) probe() error {
+	p.deleteUnresolved()
+
+	target, lastHop, err := p.selectTarget()
+	if err != nil {
+		return err
+	}
+
+	restrictions := &RestrictParams{
+		FeeLimit:          p.cfg.FeeLimit,
+		CltvLimit:         p.cfg.CltvLimit,
+		ProbabilitySource: p.cfg.MissionControl.GetProbability,
+		LastHop:           lastHop,
+	}
+	routeReq, err := NewRouteRequest(
+		p.cfg.SelfNode, &target, p.cfg.Amount, 0, restrictions, nil,
+		nil, nil, p.cfg.FinalCltvDelta,
+	)
+	if err != nil {
+		return err
+	}
+
+	rt, _, err := p.cfg.FindRoute(routeReq)
+	if err != nil {
+		log.Debugf("No route to probe target %v: %v", target, err)
+
+		return nil
+	}
+
+	// A random payment hash makes sure that the target can't settle the
+	// probe.
+	var hash lntypes.Hash
+	if _, err := rand.Read(hash[:]); err != nil {
+		return err
+	}
+
+	log.Debugf("Probing %v with %v over %v hops", target, p.cfg.Amount,
+		len(rt.Hops))
+
+	// The attempt failing is the expected outcome of a probe, its result
+	// is reported to mission control by the router.
+	attempt, err := p.cfg.SendToRoute(hash, rt)
+	if attempt == nil {
+		// The probe failed before its HTLC was resolved, for example
+		// because its attempt couldn't be registered or sent. This may
+		// leave a payment behind, which we clean up as well.
+		p.deletePayment(hash)
+
+		return err
+	}
+
+	if attempt.Settle != nil {
+		return fmt.Errorf("%w: payment hash %v", errProbeSettled, hash)
+	}
+
+	log.Debugf("Probe %v to %v resolved: %v", hash, target, err)
+
+	return p.cfg.DeletePayment(hash)
+}
+
+// deletePayment deletes the payment of a probe that failed early. If its HTLC
+// is still in flight, the deletion is retried later.
+func (p *Prober) deletePayment(hash lntypes.Hash) {
+	err := p.cfg.DeletePayment(hash)
+	switch {
+	case err == nil:
+
+	case errors.Is(err, channeldb.ErrPaymentInFlight):
+		log.Debugf("Probe %v still in flight, deleting its payment "+
+			"later", hash)
+
+		p.unresolved[hash] = struct{}{}
+
+	// If the probe failed before its payment was created, there's nothing
+	// to delete.
+	default:
+		log.Debugf("Unable to delete payment of probe %v: %v", hash,
+			err)
+	}
+}
+
+// deleteUnresolved deletes the payments of the probes whose HTLCs have been
+// resolved since they failed.
+func (p *Prober) deleteUnresolved() {
+	for hash := range p.unresolved {
+		err := p.cfg.DeletePayment(hash)
+		if errors.Is(err, channeldb.ErrPaymentInFlight) {
+			continue
+		}
+		if err != nil {
+			log.Debugf("Unable to delete payment of probe %v: %v",
+				hash, err)
+		}
+
+		delete(p.unresolved, hash)
+	}
+}
+
+// selectTarget returns the node to probe according to the target policy,
+// along with the last hop the probe must take if it targets a specific pair.
+func (p *Prober) selectTarget() (route.Vertex, *route.Vertex, error) {
+	if p.cfg.TargetPolicy == ProbeTargetStale {
+		pair, ok := p.stalePair()
+		if ok {
+			p.lastProbed[pair] = time.Now()
+
+			return pair.To, &pair.From, nil
+		}
+	}
+
+	target, err := p.randomTarget()
+
+	return target, nil, err
+}
+
+// stalePair returns the pair that mission control holds the oldest result
+// for, taking the last time we probed the pair into account. Pairs starting at
+// our own node are skipped, as the liquidity of our own channels is known.
+func (p *Prober) stalePair() (DirectedNodePair, bool) {
+	snapshot := p.cfg.MissionControl.GetHistorySnapshot()
+
+	var (
+		stale  DirectedNodePair
+		oldest time.Time
+		found  bool
+	)
+	for _, pair := range snapshot.Pairs {
+		if pair.Pair.From == p.cfg.SelfNode {
+			continue
+		}
+
+		latest := pair.FailTime
+		if pair.SuccessTime.After(latest) {
+			latest = pair.SuccessTime
+		}
+		if probed := p.lastProbed[pair.Pair]; probed.After(latest) {
+			latest = probed
+		}
+
+		if !found || latest.Before(oldest) {
+			stale, oldest, found = pair.Pair, latest, true
+		}
+	}
+
+	return stale, found
+}
+
+// randomTarget returns a random node of the graph other than our own.
+func (p *Prober) randomTarget() (route.Vertex, error) {
+	var (
+		target route.Vertex
+		count  int64
+	)
+
+	// Reservoir sampling selects a node uniformly without having to hold
+	// all nodes in memory.
+	err := p.cfg.ForEachNode(func(node route.Vertex) error {
+		if node == p.cfg.SelfNode {
+			return nil
+		}
+
+		count++
+		n, err := rand.Int(rand.Reader, big.NewInt(count))
+		if err != nil {
+			return err
+		}
+		if n.Int64() == 0 {
+			target = node
+		}
+
+		return nil
+	})
+	if err != nil {
+		return target, err
+	}
+
+	if count == 0 {
+		return target, ErrNoProbeTarget
+	}
+
+	return target, nil
+}
@@ -0,0 +1,269 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// proberTestContext holds a prober with mocked dependencies that record the
+// probes sent.
+type proberTestContext struct {
+	prober *Prober
+
+	nodes    []route.Vertex
+	requests []*RouteRequest
+	sent     []lntypes.Hash
+	deleted  []lntypes.Hash
+	settle   bool
+
+	// sendErr, if set, makes the probes fail before their HTLC is
+	// resolved.
+	sendErr error
+
+	// deleteErrs are returned by the next payment deletions, in order.
+	deleteErrs []error
+}
+
+// newProberTestContext returns a prober test context with the given target
+// policy, using the given mission control.
+func newProberTestContext(t *testing.T, mc *MissionControl,
+	policy string) *proberTestContext {
+
+	ctx := &proberTestContext{}
+
+	testRoute := &route.Route{
+		SourcePubKey: mcTestSelf,
+		Hops: []*route.Hop{
+			{
+				PubKeyBytes: mcTestNode1,
+				ChannelID:   1,
+			},
+		},
+	}
+
+	prober, err := NewProber(&ProberConfig{
+		Interval:       MinProbeInterval,
+		Amount:         DefaultProbeAmount,
+		FeeLimit:       DefaultProbeFeeLimit,
+		CltvLimit:      1_000,
+		FinalCltvDelta: 40,
+		TargetPolicy:   policy,
+		SelfNode:       mcTestSelf,
+		MissionControl: mc,
+		ForEachNode: func(cb func(route.Vertex) error) error {
+			for _, node := range ctx.nodes {
+				if err := cb(node); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		FindRoute: func(req *RouteRequest) (*route.Route, float64,
+			error) {
+
+			ctx.requests = append(ctx.requests, req)
+
+			return testRoute, 1, nil
+		},
+		SendToRoute: func(hash lntypes.Hash, rt *route.Route) (
+			*channeldb.HTLCAttempt, error) {
+
+			ctx.sent = append(ctx.sent, hash)
+
+			if ctx.sendErr != nil {
+				return nil, ctx.sendErr
+			}
+
+			attempt := &channeldb.HTLCAttempt{}
+			if ctx.settle {
+				attempt.Settle = &channeldb.HTLCSettleInfo{}
+
+				return attempt, nil
+			}
+
+			attempt.Failure = &channeldb.HTLCFailInfo{}
+
+			return attempt, errors.New("probe failed")
+		},
+		DeletePayment: func(hash lntypes.Hash) error {
+			if len(ctx.deleteErrs) > 0 {
+				err := ctx.deleteErrs[0]
+				ctx.deleteErrs = ctx.deleteErrs[1:]
+				if err != nil {
+					return err
+				}
+			}
+
+			ctx.deleted = append(ctx.deleted, hash)
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx.prober = prober
+
+	return ctx
+}
+
+// TestProberConfigValidate tests that invalid prober configs are rejected.
+func TestProberConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	validConfig := func() *ProberConfig {
+		return &ProberConfig{
+			Interval:     MinProbeInterval,
+			Amount:       DefaultProbeAmount,
+			TargetPolicy: ProbeTargetRandom,
+		}
+	}
+
+	require.NoError(t, validConfig().validate())
+
+	cfg := validConfig()
+	cfg.Interval = time.Second
+	require.Error(t, cfg.validate())
+
+	cfg = validConfig()
+	cfg.Amount = 0
+	require.Error(t, cfg.validate())
+
+	cfg = validConfig()
+	cfg.TargetPolicy = "unknown"
+	require.Error(t, cfg.validate())
+}
+
+// TestProberRandomTarget tests that probes are sent to random nodes other than
+// our own, and that the payments of failed probes are deleted.
+func TestProberRandomTarget(t *testing.T) {
+	ctx := createMcTestContext(t)
+	proberCtx := newProberTestContext(t, ctx.mc, ProbeTargetRandom)
+
+	// Without any other nodes there's nothing to probe.
+	proberCtx.nodes = []route.Vertex{mcTestSelf}
+	require.ErrorIs(t, proberCtx.prober.probe(), ErrNoProbeTarget)
+	require.Empty(t, proberCtx.sent)
+
+	proberCtx.nodes = []route.Vertex{mcTestSelf, mcTestNode1}
+	require.NoError(t, proberCtx.prober.probe())
+
+	require.Len(t, proberCtx.requests, 1)
+	req := proberCtx.requests[0]
+	require.Equal(t, mcTestNode1, req.Target)
+	require.Equal(t, DefaultProbeAmount, req.Amount)
+	require.Equal(t, DefaultProbeFeeLimit, req.Restrictions.FeeLimit)
+	require.Nil(t, req.Restrictions.LastHop)
+
+	require.Len(t, proberCtx.sent, 1)
+	require.Equal(t, proberCtx.sent, proberCtx.deleted)
+
+	// A settled probe is reported and its payment kept.
+	proberCtx.settle = true
+	require.ErrorIs(t, proberCtx.prober.probe(), errProbeSettled)
+	require.Len(t, proberCtx.sent, 2)
+	require.Len(t, proberCtx.deleted, 1)
+}
+
+// TestProberEarlyFailure tests that the payment of a probe that fails before
+// its HTLC is resolved is deleted, once its HTLC is no longer in flight.
+func TestProberEarlyFailure(t *testing.T) {
+	ctx := createMcTestContext(t)
+	proberCtx := newProberTestContext(t, ctx.mc, ProbeTargetRandom)
+	proberCtx.nodes = []route.Vertex{mcTestSelf, mcTestNode1}
+
+	// A probe that fails early has its payment deleted right away.
+	errSend := errors.New("unable to send")
+	proberCtx.sendErr = errSend
+	require.ErrorIs(t, proberCtx.prober.probe(), errSend)
+	require.Len(t, proberCtx.sent, 1)
+	require.Equal(t, proberCtx.sent, proberCtx.deleted)
+
+	// If its HTLC is still in flight, the payment is deleted before a
+	// later probe, once the HTLC was resolved.
+	proberCtx.deleteErrs = []error{
+		channeldb.ErrPaymentInFlight, channeldb.ErrPaymentInFlight,
+	}
+	require.ErrorIs(t, proberCtx.prober.probe(), errSend)
+	require.Len(t, proberCtx.sent, 2)
+	require.Len(t, proberCtx.deleted, 1)
+
+	proberCtx.sendErr = nil
+	require.NoError(t, proberCtx.prober.probe())
+	require.Len(t, proberCtx.sent, 3)
+	require.Len(t, proberCtx.deleted, 2)
+	require.Equal(t, proberCtx.sent[2], proberCtx.deleted[1])
+
+	require.NoError(t, proberCtx.prober.probe())
+	require.Len(t, proberCtx.sent, 4)
+	require.Equal(t, []lntypes.Hash{
+		proberCtx.sent[0], proberCtx.sent[2], proberCtx.sent[1],
+		proberCtx.sent[3],
+	}, proberCtx.deleted)
+	require.Empty(t, proberCtx.prober.unresolved)
+}
+
+// TestProberStaleTarget tests that probes are sent over the pairs that mission
+// control holds the oldest results for.
+func TestProberStaleTarget(t *testing.T) {
+	ctx := createMcTestContext(t)
+	proberCtx := newProberTestContext(t, ctx.mc, ProbeTargetStale)
+	proberCtx.nodes = []route.Vertex{mcTestSelf, mcTestNode1}
+
+	// Without any results, random nodes are probed.
+	require.NoError(t, proberCtx.prober.probe())
+	require.Nil(t, proberCtx.requests[0].Restrictions.LastHop)
+
+	node3 := route.Vertex{13}
+	oldPair := NewDirectedNodePair(mcTestNode1, mcTestNode2)
+	newPair := NewDirectedNodePair(mcTestNode2, node3)
+	ownPair := NewDirectedNodePair(mcTestSelf, mcTestNode1)
+
+	err := ctx.mc.ImportHistory(&MissionControlSnapshot{
+		Pairs: []MissionControlPairSnapshot{
+			{
+				Pair: ownPair,
+				TimedPairResult: TimedPairResult{
+					FailTime: mcTestTime.Add(-time.Hour),
+					FailAmt:  lnwire.MilliSatoshi(1_000),
+				},
+			},
+			{
+				Pair: oldPair,
+				TimedPairResult: TimedPairResult{
+					FailTime: mcTestTime.Add(-time.Minute),
+					FailAmt:  lnwire.MilliSatoshi(1_000),
+				},
+			},
+			{
+				Pair: newPair,
+				TimedPairResult: TimedPairResult{
+					SuccessTime: mcTestTime,
+					SuccessAmt:  lnwire.MilliSatoshi(1_000),
+				},
+			},
+		},
+	}, true)
+	require.NoError(t, err)
+
+	// Our own pair is skipped, so the oldest pair is probed by routing
+	// over it as the last hop.
+	require.NoError(t, proberCtx.prober.probe())
+	req := proberCtx.requests[1]
+	require.Equal(t, oldPair.To, req.Target)
+	require.Equal(t, &oldPair.From, req.Restrictions.LastHop)
+
+	// Having been probed just now, the next probe goes over the other
+	// pair, even though mission control didn't learn anything new.
+	require.NoError(t, proberCtx.prober.probe())
+	req = proberCtx.requests[2]
+	require.Equal(t, newPair.To, req.Target)
+	require.Equal(t, &newPair.From, req.Restrictions.LastHop)
+}
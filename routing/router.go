@@ -411,6 +411,10 @@ type Config struct {
 	// IsAlias returns whether a passed ShortChannelID is an alias. This is
 	// only used for our local channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// AttemptTracer is an optional tracer that records the HTLC attempts
+	// of payments for offline analysis. If nil, no traces are recorded.
+	AttemptTracer AttemptTracer
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -476,12 +480,19 @@ type ChannelRouter struct {
 	// channel.
 	topologyClients *lnutils.SyncMap[uint64, *topologyClient]
 
-	// ntfnClientUpdates is a channel that's used to send new updates to
-	// topology notification clients to the ChannelRouter. Updates either
-	// add a new notification client, or cancel notifications for an
-	// existing client.
+	// ntfnClientUpdates is a channel that's used to cancel notifications
+	// for an existing topology notification client.
 	ntfnClientUpdates chan *topologyClientUpdate
 
+	// topologyMtx serializes the assignment of sequence numbers to
+	// topology changes with their dispatch to the topology clients and
+	// the registration of new clients.
+	topologyMtx sync.Mutex
+
+	// topologyHistory retains the most recent topology changes, so
+	// clients can resume their subscriptions.
+	topologyHistory *topologyChangeHistory
+
 	// channelEdgeMtx is a mutex we use to make sure we process only one
 	// ChannelEdgePolicy at a time for a given channelID, to ensure
 	// consistency between the various database accesses.
@@ -528,6 +539,13 @@ func New(cfg Config) (*ChannelRouter, error) {
 		statTicker:        ticker.New(defaultStatInterval),
 		stats:             new(routerStats),
 		quit:              make(chan struct{}),
+
+		// We start the sequence numbers at the current time, so they
+		// keep increasing across restarts and clients can't resume
+		// from a sequence number of a previous run.
+		topologyHistory: newTopologyChangeHistory(
+			topologyHistorySize, uint64(time.Now().UnixNano()),
+		),
 	}
 
 	return r, nil
@@ -1300,31 +1318,19 @@ func (r *ChannelRouter) networkHandler() {
 					" processed.", chainUpdate.Height)
 			}
 
-		// A new notification client update has arrived. We're either
-		// gaining a new client, or cancelling notifications for an
-		// existing client.
+		// A client wants to cancel its topology notifications.
 		case ntfnUpdate := <-r.ntfnClientUpdates:
-			clientID := ntfnUpdate.clientID
-
-			if ntfnUpdate.cancel {
-				client, ok := r.topologyClients.LoadAndDelete(
-					clientID,
-				)
-				if ok {
-					close(client.exit)
-					client.wg.Wait()
-
-					close(client.ntfnChan)
-				}
+			client, ok := r.topologyClients.LoadAndDelete(
+				ntfnUpdate.clientID,
+			)
+			if ok {
+				close(client.exit)
+				client.wg.Wait()
+				client.queue.Stop()
 
-				continue
+				close(client.ntfnChan)
 			}
 
-			r.topologyClients.Store(clientID, &topologyClient{
-				ntfnChan: ntfnUpdate.ntfnChan,
-				exit:     make(chan struct{}),
-			})
-
 		// The graph prune ticker has ticked, so we'll examine the
 		// state of the known graph to filter out any zombie channels
 		// for pruning.
@@ -1348,6 +1354,7 @@ func (r *ChannelRouter) networkHandler() {
 		// The router has been signalled to exit, to we exit our main
 		// loop so the wait group can be decremented.
 		case <-r.quit:
+			r.stopTopologyClients()
 			return
 		}
 	}
@@ -36,6 +36,7 @@ import (
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/chanbackup"
@@ -72,6 +73,7 @@ import (
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/rpcperms"
 	"github.com/lightningnetwork/lnd/signal"
@@ -301,10 +303,26 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/TestConnection": {{
+			Entity: "peers",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/DisconnectPeer": {{
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/BanPeer": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/UnbanPeer": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListBannedPeers": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/OpenChannel": {{
 			Entity: "onchain",
 			Action: "write",
@@ -333,6 +351,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/EstimateCloseCosts": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/AbandonChannel": {{
 			Entity: "offchain",
 			Action: "write",
@@ -354,6 +379,19 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetRuntimeConfig": {{
+			Entity: "info",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "peers",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetRecoveryInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -366,6 +404,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/BalanceBreakdown": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/EstimateFee": {{
 			Entity: "onchain",
 			Action: "read",
@@ -378,6 +423,20 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ListContractResolutions": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		// The raw commitment state is sensitive, so it's only exposed
+		// to macaroons that may also modify channels, even though the
+		// call itself doesn't.
+		"/lnrpc.Lightning/GetChannelCommitState": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/ListChannels": {{
 			Entity: "offchain",
 			Action: "read",
@@ -422,6 +481,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "invoices",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetInvoiceIndices": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/SubscribeTransactions": {{
 			Entity: "onchain",
 			Action: "read",
@@ -486,10 +549,26 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ForwardingPolicies": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/UpdateChannelPolicy": {{
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/QueuePolicyUpdates": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/PolicyUpdateQueueStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SimulateFeeChange": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ForwardingHistory": {{
 			Entity: "offchain",
 			Action: "read",
@@ -498,6 +577,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/ChannelRecoveryStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ExportChannelBackup": {{
 			Entity: "offchain",
 			Action: "read",
@@ -510,6 +593,28 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ExportAllChannelBackupsToFile": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/CheckChanBackupFile": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SnapshotChannelDB": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/CompactChannelDB": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/SubscribeChannelBackups": {{
 			Entity: "offchain",
 			Action: "read",
@@ -552,6 +657,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/AbortChannelOpen": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
 		lnrpc.RegisterRPCMiddlewareURI: {{
 			Entity: "macaroon",
 			Action: "write",
@@ -771,7 +883,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		s.sweeper, tower, s.towerClientMgr, r.cfg.net.ResolveTCPAddr,
 		genInvoiceFeatures, genAmpInvoiceFeatures,
 		s.getNodeAnnouncement, s.updateAndBrodcastSelfNode, parseAddr,
-		rpcsLog, s.aliasMgr.GetPeerAlias,
+		rpcsLog, s.aliasMgr.GetPeerAlias, s.invoiceFallbackAddr,
 	)
 	if err != nil {
 		return err
@@ -1072,6 +1184,53 @@ func allowCORS(handler http.Handler, origins []string) http.Handler {
 	})
 }
 
+// checkMaxSendAmount returns an error if the given amount of an on-chain send
+// exceeds the maximum send amount of the wallet config, unless the caller
+// overrides the limit. Overriding the limit is rejected if it's disabled by
+// the config.
+func checkMaxSendAmount(cfg *lncfg.Wallet, amt btcutil.Amount,
+	override bool) error {
+
+	if override && cfg.NoMaxSendAmountOverride {
+		return fmt.Errorf("overriding the maximum send amount is " +
+			"disabled")
+	}
+
+	maxSendAmt := btcutil.Amount(cfg.MaxSendAmount)
+	if maxSendAmt == 0 || override || amt <= maxSendAmt {
+		return nil
+	}
+
+	if cfg.NoMaxSendAmountOverride {
+		return fmt.Errorf("amount %v exceeds the maximum send amount "+
+			"of %v", amt, maxSendAmt)
+	}
+
+	return fmt.Errorf("amount %v exceeds the maximum send amount of %v, "+
+		"set override_max_send_amount to send it anyway", amt,
+		maxSendAmt)
+}
+
+// sweepAmount returns the total value of the outputs of the given sweep
+// transaction that pay to the target address.
+func sweepAmount(sweepTx *wire.MsgTx,
+	targetAddr btcutil.Address) (btcutil.Amount, error) {
+
+	pkScript, err := txscript.PayToAddrScript(targetAddr)
+	if err != nil {
+		return 0, err
+	}
+
+	var amt btcutil.Amount
+	for _, txOut := range sweepTx.TxOut {
+		if bytes.Equal(txOut.PkScript, pkScript) {
+			amt += btcutil.Amount(txOut.Value)
+		}
+	}
+
+	return amt, nil
+}
+
 // sendCoinsOnChain makes an on-chain transaction in or to send coins to one or
 // more addresses specified in the passed payment map. The payment map maps an
 // address to a specified output value to be sent to that address.
@@ -1287,6 +1446,7 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 	feePerKw, err := lnrpc.CalculateFeeRate(
 		uint64(in.SatPerByte), in.SatPerVbyte, // nolint:staticcheck
 		targetConf, r.server.cc.FeeEstimator,
+		r.cfg.minRelayFeeRate(), r.cfg.maxFeeRate(),
 	)
 	if err != nil {
 		return nil, err
@@ -1304,6 +1464,16 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		in.Addr, btcutil.Amount(in.Amount), int64(feePerKw), minConfs,
 		in.SendAll)
 
+	// The amount of a send_all request is only known once the sweep
+	// transaction was crafted, so only the override is validated here.
+	err = checkMaxSendAmount(
+		r.cfg.Wallet, btcutil.Amount(in.Amount),
+		in.OverrideMaxSendAmount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Decode the address receiving the coins, we need to check whether the
 	// address is valid for this network.
 	targetAddr, err := btcutil.DecodeAddress(
@@ -1453,6 +1623,29 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 			return nil, err
 		}
 
+		// Unless sweeps are exempt, the amount sent to the target
+		// address must not exceed the maximum send amount.
+		if !r.cfg.Wallet.MaxSendAmountExemptSendAll {
+			sweepAmt, err := sweepAmount(
+				sweepTxPkg.SweepTx, targetAddr,
+			)
+			if err != nil {
+				sweepTxPkg.CancelSweepAttempt()
+
+				return nil, err
+			}
+
+			err = checkMaxSendAmount(
+				r.cfg.Wallet, sweepAmt,
+				in.OverrideMaxSendAmount,
+			)
+			if err != nil {
+				sweepTxPkg.CancelSweepAttempt()
+
+				return nil, err
+			}
+		}
+
 		rpcsLog.Debugf("Sweeping all coins from wallet to addr=%v, "+
 			"with tx=%v", in.Addr, spew.Sdump(sweepTxPkg.SweepTx))
 
@@ -1514,6 +1707,7 @@ func (r *rpcServer) SendMany(ctx context.Context,
 	feePerKw, err := lnrpc.CalculateFeeRate(
 		uint64(in.SatPerByte), in.SatPerVbyte, // nolint:staticcheck
 		targetConf, r.server.cc.FeeEstimator,
+		r.cfg.minRelayFeeRate(), r.cfg.maxFeeRate(),
 	)
 	if err != nil {
 		return nil, err
@@ -1542,6 +1736,18 @@ func (r *rpcServer) SendMany(ctx context.Context,
 	rpcsLog.Infof("[sendmany] outputs=%v, sat/kw=%v",
 		spew.Sdump(in.AddrToAmount), int64(feePerKw))
 
+	var totalAmt btcutil.Amount
+	for _, amt := range in.AddrToAmount {
+		totalAmt += btcutil.Amount(amt)
+	}
+
+	err = checkMaxSendAmount(
+		r.cfg.Wallet, totalAmt, in.OverrideMaxSendAmount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	var txid *chainhash.Hash
 
 	// We'll attempt to send to the target set of outputs, ensuring that we
@@ -1790,6 +1996,73 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 	return &lnrpc.ConnectPeerResponse{}, nil
 }
 
+// TestConnection checks whether a remote peer can be reached by performing a
+// transient handshake with it, without establishing a lasting connection.
+func (r *rpcServer) TestConnection(ctx context.Context,
+	in *lnrpc.TestConnectionRequest) (*lnrpc.TestConnectionResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.Addr == nil {
+		return nil, fmt.Errorf("need: lnc pubkeyhash@hostname")
+	}
+
+	pubkeyHex, err := hex.DecodeString(in.Addr.Pubkey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubkeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubKey.IsEqual(r.server.identityECDH.PubKey()) {
+		return nil, fmt.Errorf("cannot make connection to self")
+	}
+
+	addr, err := parseAddr(in.Addr.Host, r.cfg.net)
+	if err != nil {
+		return nil, err
+	}
+
+	peerAddr := &lnwire.NetAddress{
+		IdentityKey: pubKey,
+		Address:     addr,
+		ChainNet:    r.cfg.ActiveNetParams.Net,
+	}
+
+	timeout := r.cfg.ConnectionTimeout
+	if in.Timeout != 0 {
+		timeout = time.Duration(in.Timeout) * time.Second
+	}
+
+	// The test mustn't outlive the deadline of the caller.
+	deadline, ok := ctx.Deadline()
+	if ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	rpcsLog.Debugf("[testconnection] testing connection to %x@%s with "+
+		"timeout %v", peerAddr.IdentityKey.SerializeCompressed(),
+		peerAddr.Address, timeout)
+
+	result, err := r.server.TestConnection(peerAddr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	features := invoicesrpc.CreateRPCFeatures(result.Features)
+
+	return &lnrpc.TestConnectionResponse{
+		HandshakeLatencyMs: result.HandshakeLatency.Milliseconds(),
+		InitLatencyMs:      result.InitLatency.Milliseconds(),
+		Features:           features,
+	}, nil
+}
+
 // DisconnectPeer attempts to disconnect one peer from another identified by a
 // given pubKey. In the case that we currently have a pending or active channel
 // with the target peer, this action will be disallowed.
@@ -1852,6 +2125,108 @@ func (r *rpcServer) DisconnectPeer(ctx context.Context,
 	return &lnrpc.DisconnectPeerResponse{}, nil
 }
 
+// BanPeer disconnects from the peer identified by the given pubKey and refuses
+// any connection with it until the ban expires. The channels with the peer
+// are not affected.
+func (r *rpcServer) BanPeer(ctx context.Context,
+	in *lnrpc.BanPeerRequest) (*lnrpc.BanPeerResponse, error) {
+
+	rpcsLog.Debugf("[banpeer] peer(%s) for %vs", in.PubKey,
+		in.DurationSecs)
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	peerPubKey, err := parsePeerPubKey(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.DurationSecs == 0 {
+		return nil, fmt.Errorf("ban duration must be set")
+	}
+	if in.DurationSecs > math.MaxInt64/uint64(time.Second) {
+		return nil, fmt.Errorf("ban duration of %vs is too large",
+			in.DurationSecs)
+	}
+	duration := time.Duration(in.DurationSecs) * time.Second
+
+	expiry, err := r.server.BanPeer(peerPubKey, duration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to ban peer: %w", err)
+	}
+
+	return &lnrpc.BanPeerResponse{
+		Expiry: expiry.Unix(),
+	}, nil
+}
+
+// UnbanPeer lifts the ban of the peer identified by the given pubKey.
+func (r *rpcServer) UnbanPeer(ctx context.Context,
+	in *lnrpc.UnbanPeerRequest) (*lnrpc.UnbanPeerResponse, error) {
+
+	rpcsLog.Debugf("[unbanpeer] peer(%s)", in.PubKey)
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	peerPubKey, err := parsePeerPubKey(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.UnbanPeer(peerPubKey); err != nil {
+		return nil, fmt.Errorf("unable to unban peer: %w", err)
+	}
+
+	return &lnrpc.UnbanPeerResponse{}, nil
+}
+
+// ListBannedPeers returns all peers that are currently banned.
+func (r *rpcServer) ListBannedPeers(ctx context.Context,
+	_ *lnrpc.ListBannedPeersRequest) (*lnrpc.ListBannedPeersResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	bans := r.server.BannedPeers()
+
+	resp := &lnrpc.ListBannedPeersResponse{
+		BannedPeers: make([]*lnrpc.BannedPeer, 0, len(bans)),
+	}
+	for peer, expiry := range bans {
+		resp.BannedPeers = append(resp.BannedPeers, &lnrpc.BannedPeer{
+			PubKey: peer.String(),
+			Expiry: expiry.Unix(),
+		})
+	}
+
+	// Sort the bans by expiry to return a stable result.
+	sort.Slice(resp.BannedPeers, func(i, j int) bool {
+		return resp.BannedPeers[i].Expiry < resp.BannedPeers[j].Expiry
+	})
+
+	return resp, nil
+}
+
+// parsePeerPubKey parses the hex encoded compressed public key of a peer.
+func parsePeerPubKey(pubKeyStr string) (*btcec.PublicKey, error) {
+	pubKeyBytes, err := hex.DecodeString(pubKeyStr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode pubkey bytes: %w", err)
+	}
+	peerPubKey, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse pubkey: %w", err)
+	}
+
+	return peerPubKey, nil
+}
+
 // newFundingShimAssembler returns a new fully populated
 // chanfunding.CannedAssembler using a FundingShim obtained from an RPC caller.
 func newFundingShimAssembler(chanPointShim *lnrpc.ChanPointShim, initiator bool,
@@ -2122,6 +2497,18 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			"greater than %v", maxHtlcs, input.MaxHTLCNumber/2)
 	}
 
+	// The number of confirmations of the funding transaction must be
+	// within the range the chain notifier is able to dispatch. Zero-conf
+	// channels don't wait for any confirmations.
+	if in.FundingConfs > chainntnfs.MaxNumConfs {
+		return nil, fmt.Errorf("funding-confs (%v) cannot be greater "+
+			"than %v", in.FundingConfs, chainntnfs.MaxNumConfs)
+	}
+	if in.FundingConfs != 0 && in.ZeroConf {
+		return nil, fmt.Errorf("funding-confs cannot be set for " +
+			"zero-conf channels")
+	}
+
 	// Then, we'll extract the minimum number of confirmations that each
 	// output we use to fund the channel's funding transaction should
 	// satisfy.
@@ -2185,6 +2572,7 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		feeRate, err = lnrpc.CalculateFeeRate(
 			uint64(in.SatPerByte), in.SatPerVbyte,
 			targetConf, r.server.cc.FeeEstimator,
+			r.cfg.minRelayFeeRate(), r.cfg.maxFeeRate(),
 		)
 		if err != nil {
 			return nil, err
@@ -2303,17 +2691,40 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		}
 	}
 
-	// Instruct the server to trigger the necessary events to attempt to
-	// open a new channel. A stream is returned in place, this stream will
-	// be used to consume updates of the state of the pending channel.
-	return &funding.InitFundingMsg{
-		TargetPubkey:    nodePubKey,
-		ChainHash:       *r.cfg.ActiveNetParams.GenesisHash,
-		LocalFundingAmt: localFundingAmt,
-		BaseFee:         channelBaseFee,
-		FeeRate:         channelFeeRate,
-		PushAmt: lnwire.NewMSatFromSatoshis(
-			remoteInitialBalance,
+	// If a funding account was specified, make sure channels can be funded
+	// from it. The account only applies to the internal wallet funding
+	// flow, so it can't be combined with a funding shim.
+	if in.Account != "" {
+		if in.FundingShim != nil {
+			return nil, fmt.Errorf("cannot provide a funding " +
+				"shim while funding from an account")
+		}
+
+		err := r.server.cc.Wallet.ValidateFundingAccount(in.Account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	coinSelectionStrategy, err := lnrpc.UnmarshallCoinSelectionStrategy(
+		in.CoinSelectionStrategy,
+		r.server.cc.Wallet.Cfg.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Instruct the server to trigger the necessary events to attempt to
+	// open a new channel. A stream is returned in place, this stream will
+	// be used to consume updates of the state of the pending channel.
+	return &funding.InitFundingMsg{
+		TargetPubkey:    nodePubKey,
+		ChainHash:       *r.cfg.ActiveNetParams.GenesisHash,
+		LocalFundingAmt: localFundingAmt,
+		BaseFee:         channelBaseFee,
+		FeeRate:         channelFeeRate,
+		PushAmt: lnwire.NewMSatFromSatoshis(
+			remoteInitialBalance,
 		),
 		MinHtlcIn:         minHtlcIn,
 		FundingFeePerKw:   feeRate,
@@ -2330,6 +2741,10 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MinFundAmt:        minFundAmt,
 		Memo:              []byte(in.Memo),
 		Outpoints:         outpoints,
+		Account:           in.Account,
+		FundingConfs:      uint16(in.FundingConfs),
+
+		CoinSelectionStrategy: coinSelectionStrategy,
 	}, nil
 }
 
@@ -2543,6 +2958,15 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		return nil, fmt.Errorf("specify at least one channel")
 	}
 
+	// If a funding account was specified, make sure the batch transaction
+	// can be funded from it.
+	if in.Account != "" {
+		err := r.server.cc.Wallet.ValidateFundingAccount(in.Account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// In case we remove a pending channel from the database, we need to set
 	// a close height, so we'll just use the current best known height.
 	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
@@ -2568,6 +2992,7 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		Wallet:           r.server.cc.Wallet,
 		NetParams:        &r.server.cc.Wallet.Cfg.NetParams,
 		Quit:             r.quit,
+		MinInputConfs:    r.cfg.Funding.MinInputConfs,
 	})
 	rpcPoints, err := batcher.BatchFund(ctx, in)
 	if err != nil {
@@ -2745,6 +3170,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		feeRate, err := lnrpc.CalculateFeeRate(
 			uint64(in.SatPerByte), in.SatPerVbyte, // nolint:staticcheck
 			targetConf, r.server.cc.FeeEstimator,
+			r.cfg.minRelayFeeRate(), r.cfg.maxFeeRate(),
 		)
 		if err != nil {
 			return err
@@ -2949,6 +3375,73 @@ func (r *rpcServer) abandonChan(chanPoint *wire.OutPoint,
 	return nil
 }
 
+// EstimateCloseCosts estimates the on-chain fees of closing all open channels
+// at the current fee rates, both cooperatively and by force. The channels
+// themselves are left untouched.
+func (r *rpcServer) EstimateCloseCosts(_ context.Context,
+	in *lnrpc.EstimateCloseCostsRequest) (*lnrpc.EstimateCloseCostsResponse,
+	error) {
+
+	// Use the default conf target of a cooperative close if no fee
+	// preference was given.
+	targetConf := uint32(in.TargetConf)
+	if targetConf == 0 && in.SatPerVbyte == 0 {
+		targetConf = 6
+	}
+
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, targetConf, r.server.cc.FeeEstimator,
+		r.cfg.minRelayFeeRate(), r.cfg.maxFeeRate(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	openChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	anchorReserve, err := r.requiredReserve()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.EstimateCloseCostsResponse{
+		Channels:         make([]*lnrpc.ChannelCloseCost, 0),
+		SatPerVbyte:      uint64(feeRate.FeePerVByte()),
+		AnchorReserveSat: int64(anchorReserve),
+	}
+	for _, channel := range openChannels {
+		cost := chancloser.EstimateCloseCost(channel, feeRate)
+
+		localCoopFee := cost.LocalCoopCloseFee()
+		localForceFee := cost.LocalForceCloseFee()
+
+		resp.Channels = append(resp.Channels, &lnrpc.ChannelCloseCost{
+			ChannelPoint:          channel.FundingOutpoint.String(),
+			ChanId:                channel.ShortChanID().ToUint64(),
+			Initiator:             cost.Initiator,
+			CoopCloseFeeSat:       int64(cost.CoopCloseFee),
+			CommitFeeSat:          int64(cost.CommitFee),
+			AnchorCpfpFeeSat:      int64(cost.AnchorCpfpFee),
+			SweepFeeSat:           int64(cost.SweepFee),
+			LocalCoopCloseFeeSat:  int64(localCoopFee),
+			LocalForceCloseFeeSat: int64(localForceFee),
+		})
+
+		resp.TotalCoopCloseFeeSat += int64(localCoopFee)
+		resp.TotalForceCloseFeeSat += int64(localForceFee)
+	}
+
+	rpcsLog.Debugf("[estimateclosecosts] fee_rate=%v, num_channels=%v, "+
+		"total_coop_close_fee=%v, total_force_close_fee=%v", feeRate,
+		len(openChannels), resp.TotalCoopCloseFeeSat,
+		resp.TotalForceCloseFeeSat)
+
+	return resp, nil
+}
+
 // AbandonChannel removes all channel state from the database except for a
 // close summary. This method can be used to get rid of permanently unusable
 // channels due to bugs fixed in newer versions of lnd.
@@ -3183,6 +3676,30 @@ func (r *rpcServer) GetDebugInfo(_ context.Context,
 	}, nil
 }
 
+// GetRuntimeConfig returns the resolved configuration of the daemon with
+// sensitive values redacted, together with the deprecated options that are set.
+func (r *rpcServer) GetRuntimeConfig(_ context.Context,
+	_ *lnrpc.GetRuntimeConfigRequest) (*lnrpc.GetRuntimeConfigResponse,
+	error) {
+
+	flatConfig, deprecated, err := configToFlatMap(*r.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error converting config to flat map: "+
+			"%w", err)
+	}
+
+	deprecatedOptions := make([]string, 0, len(deprecated))
+	for option := range deprecated {
+		deprecatedOptions = append(deprecatedOptions, option)
+	}
+	sort.Strings(deprecatedOptions)
+
+	return &lnrpc.GetRuntimeConfigResponse{
+		Config:            flatConfig,
+		DeprecatedOptions: deprecatedOptions,
+	}, nil
+}
+
 // GetRecoveryInfo returns a boolean indicating whether the wallet is started
 // in recovery mode, whether the recovery is finished, and the progress made
 // so far.
@@ -3481,44 +3998,118 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 	// don't display this, then anytime we attempt a funding reservation,
 	// the outputs will chose as being "gone" until they're confirmed on
 	// chain.
-	var lockedBalance btcutil.Amount
-	leases, err := r.server.cc.Wallet.ListLeasedOutputs()
+	lockedBalance, err := r.lockedBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	requiredReserve, err := r.requiredReserve()
 	if err != nil {
 		return nil, err
 	}
+
+	rpcsLog.Debugf("[walletbalance] Total balance=%v (confirmed=%v, "+
+		"unconfirmed=%v)", totalBalance, confirmedBalance,
+		unconfirmedBalance)
+
+	return &lnrpc.WalletBalanceResponse{
+		TotalBalance:              int64(totalBalance),
+		ConfirmedBalance:          int64(confirmedBalance),
+		UnconfirmedBalance:        int64(unconfirmedBalance),
+		LockedBalance:             int64(lockedBalance),
+		ReservedBalanceAnchorChan: int64(requiredReserve),
+		AccountBalance:            rpcAccountBalances,
+	}, nil
+}
+
+// lockedBalance returns the total value of all wallet outputs that are
+// currently leased.
+func (r *rpcServer) lockedBalance() (btcutil.Amount, error) {
+	leases, err := r.server.cc.Wallet.ListLeasedOutputs()
+	if err != nil {
+		return 0, err
+	}
+
+	var lockedBalance btcutil.Amount
 	for _, leasedOutput := range leases {
 		utxoInfo, err := r.server.cc.Wallet.FetchInputInfo(
 			&leasedOutput.Outpoint,
 		)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
 		lockedBalance += utxoInfo.Value
 	}
 
-	// Get the current number of non-private anchor channels.
+	return lockedBalance, nil
+}
+
+// requiredReserve returns the reserve the wallet needs to hold back for fee
+// bumping the commitments of the current non-private anchor channels.
+func (r *rpcServer) requiredReserve() (btcutil.Amount, error) {
 	currentNumAnchorChans, err := r.server.cc.Wallet.CurrentNumAnchorChans()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	// Get the required reserve for the wallet.
-	requiredReserve := r.server.cc.Wallet.RequiredReserve(
+	return r.server.cc.Wallet.RequiredReserve(
 		uint32(currentNumAnchorChans),
+	), nil
+}
+
+// BalanceBreakdown returns a detailed breakdown of all on-chain funds,
+// including funds that are leased, reserved for anchor channels or still
+// time-locked in force closed channels.
+func (r *rpcServer) BalanceBreakdown(_ context.Context,
+	_ *lnrpc.BalanceBreakdownRequest) (*lnrpc.BalanceBreakdownResponse,
+	error) {
+
+	// We fetch the confirmed and unconfirmed outputs in a single call, so
+	// they are consistent with each other.
+	utxos, err := r.server.cc.Wallet.ListUnspentWitness(
+		0, math.MaxInt32, "",
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	rpcsLog.Debugf("[walletbalance] Total balance=%v (confirmed=%v, "+
-		"unconfirmed=%v)", totalBalance, confirmedBalance,
-		unconfirmedBalance)
+	lockedBalance, err := r.lockedBalance()
+	if err != nil {
+		return nil, err
+	}
 
-	return &lnrpc.WalletBalanceResponse{
-		TotalBalance:              int64(totalBalance),
-		ConfirmedBalance:          int64(confirmedBalance),
-		UnconfirmedBalance:        int64(unconfirmedBalance),
-		LockedBalance:             int64(lockedBalance),
-		ReservedBalanceAnchorChan: int64(requiredReserve),
-		AccountBalance:            rpcAccountBalances,
+	requiredReserve, err := r.requiredReserve()
+	if err != nil {
+		return nil, err
+	}
+
+	// The limbo balance of pending force closed channels are the funds
+	// that are waiting to mature before they can be swept into the
+	// wallet.
+	_, immatureBalance, err := r.fetchPendingForceCloseChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := lnwallet.NewBalanceBreakdown(
+		utxos, lockedBalance, requiredReserve,
+		btcutil.Amount(immatureBalance),
+	)
+
+	rpcsLog.Debugf("[balancebreakdown] Total balance=%v (available=%v, "+
+		"reserved=%v, unconfirmed=%v, locked=%v, immature=%v)",
+		breakdown.Total(), breakdown.Available, breakdown.Reserved,
+		breakdown.Unconfirmed, breakdown.Locked, breakdown.Immature)
+
+	return &lnrpc.BalanceBreakdownResponse{
+		TotalBalance:              int64(breakdown.Total()),
+		AvailableBalance:          int64(breakdown.Available),
+		ReservedBalanceAnchorChan: int64(breakdown.Reserved),
+		UnconfirmedBalance:        int64(breakdown.Unconfirmed),
+		LockedBalance:             int64(breakdown.Locked),
+		ImmatureBalance:           int64(breakdown.Immature),
+		ConfirmedBalance:          int64(breakdown.Confirmed()),
 	}, nil
 }
 
@@ -4272,6 +4863,211 @@ func (r *rpcServer) LookupHtlcResolution(
 	}, nil
 }
 
+// ListContractResolutions lists every closed channel whose outputs are still
+// being resolved on chain, together with the resolution state of each output.
+func (r *rpcServer) ListContractResolutions(_ context.Context,
+	_ *lnrpc.ListContractResolutionsRequest) (
+	*lnrpc.ListContractResolutionsResponse, error) {
+
+	_, currentHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListContractResolutionsResponse{}
+	for _, report := range r.server.chainArb.ResolutionReports() {
+		channel := &lnrpc.ChannelResolution{
+			ChannelPoint: marshalChanPoint(report.ChanPoint),
+		}
+
+		for _, contract := range report.Contracts {
+			rpcContract, err := marshallContractReport(
+				contract, currentHeight,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// The channel is only fully recovered once the last of
+			// its unresolved outputs matured.
+			resolved := rpcContract.State ==
+				lnrpc.ContractResolutionState_RESOLVED
+			if !resolved && rpcContract.MaturityHeight >
+				channel.ExpectedResolutionHeight {
+
+				channel.ExpectedResolutionHeight =
+					rpcContract.MaturityHeight
+			}
+
+			channel.LimboBalance += rpcContract.LimboBalance
+			channel.RecoveredBalance += rpcContract.RecoveredBalance
+			channel.Contracts = append(
+				channel.Contracts, rpcContract,
+			)
+		}
+
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
+// marshallContractReport converts a contract report of a resolver into its
+// RPC representation, deriving the resolution state of the output from its
+// balances and maturity height.
+func marshallContractReport(report *contractcourt.ContractReport,
+	currentHeight int32) (*lnrpc.ContractResolution, error) {
+
+	var resolutionType lnrpc.ResolutionType
+	switch report.Type {
+	case contractcourt.ReportOutputIncomingHtlc:
+		resolutionType = lnrpc.ResolutionType_INCOMING_HTLC
+
+	case contractcourt.ReportOutputOutgoingHtlc:
+		resolutionType = lnrpc.ResolutionType_OUTGOING_HTLC
+
+	case contractcourt.ReportOutputUnencumbered:
+		resolutionType = lnrpc.ResolutionType_COMMIT
+
+	case contractcourt.ReportOutputAnchor:
+		resolutionType = lnrpc.ResolutionType_ANCHOR
+
+	default:
+		return nil, fmt.Errorf("unknown report output type: %v",
+			report.Type)
+	}
+
+	contract := &lnrpc.ContractResolution{
+		Type:             resolutionType,
+		Outpoint:         report.Outpoint.String(),
+		AmountSat:        int64(report.Amount),
+		MaturityHeight:   report.MaturityHeight,
+		Stage:            report.Stage,
+		LimboBalance:     int64(report.LimboBalance),
+		RecoveredBalance: int64(report.RecoveredBalance),
+	}
+
+	if contract.MaturityHeight != 0 {
+		contract.BlocksTilMaturity =
+			int32(contract.MaturityHeight) - currentHeight
+	}
+
+	switch {
+	case report.LimboBalance == 0:
+		contract.State = lnrpc.ContractResolutionState_RESOLVED
+
+	case contract.BlocksTilMaturity > 0:
+		contract.State = lnrpc.ContractResolutionState_AWAITING_TIMELOCK
+
+	default:
+		contract.State = lnrpc.ContractResolutionState_SWEEPING
+	}
+
+	return contract, nil
+}
+
+// GetChannelCommitState returns the raw commitment state of an open channel as
+// stored in the channel database. Only read transactions are used, so the
+// channel state is never modified.
+func (r *rpcServer) GetChannelCommitState(_ context.Context,
+	in *lnrpc.ChannelCommitStateRequest) (*lnrpc.ChannelCommitStateResponse,
+	error) {
+
+	if in.ChanPoint == nil {
+		return nil, fmt.Errorf("chan_point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.OutPoint{
+		Hash:  *txid,
+		Index: in.ChanPoint.OutputIndex,
+	}
+
+	dbChannel, err := r.server.chanStateDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel %v: %w",
+			chanPoint, err)
+	}
+
+	resp := &lnrpc.ChannelCommitStateResponse{
+		ChanPoint: marshalChanPoint(chanPoint),
+		LocalCommitment: marshallCommitmentState(
+			&dbChannel.LocalCommitment,
+		),
+		RemoteCommitment: marshallCommitmentState(
+			&dbChannel.RemoteCommitment,
+		),
+	}
+
+	// A pending remote commitment only exists while we're waiting for the
+	// remote node to revoke its previous commitment.
+	commitDiff, err := dbChannel.RemoteCommitChainTip()
+	switch {
+	case err == nil:
+		resp.PendingRemoteCommitment = marshallCommitmentState(
+			&commitDiff.Commitment,
+		)
+
+	case !errors.Is(err, channeldb.ErrNoPendingCommit):
+		return nil, fmt.Errorf("unable to fetch pending remote "+
+			"commitment: %w", err)
+	}
+
+	ackedUpdates, err := dbChannel.UnsignedAckedUpdates()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch unsigned acked "+
+			"updates: %w", err)
+	}
+	resp.NumUnsignedAckedUpdates = uint32(len(ackedUpdates))
+
+	localUpdates, err := dbChannel.RemoteUnsignedLocalUpdates()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch unsigned local "+
+			"updates: %w", err)
+	}
+	resp.NumUnsignedLocalUpdates = uint32(len(localUpdates))
+
+	return resp, nil
+}
+
+// marshallCommitmentState converts a commitment of the channel database into
+// its RPC representation.
+func marshallCommitmentState(
+	commit *channeldb.ChannelCommitment) *lnrpc.CommitmentState {
+
+	state := &lnrpc.CommitmentState{
+		CommitHeight:      commit.CommitHeight,
+		LocalBalanceMsat:  uint64(commit.LocalBalance),
+		RemoteBalanceMsat: uint64(commit.RemoteBalance),
+		CommitFeeSat:      int64(commit.CommitFee),
+		FeePerKw:          int64(commit.FeePerKw),
+		LocalLogIndex:     commit.LocalLogIndex,
+		LocalHtlcIndex:    commit.LocalHtlcIndex,
+		RemoteLogIndex:    commit.RemoteLogIndex,
+		RemoteHtlcIndex:   commit.RemoteHtlcIndex,
+	}
+
+	if commit.CommitTx != nil {
+		state.CommitTxid = commit.CommitTx.TxHash().String()
+	}
+
+	for _, htlc := range commit.Htlcs {
+		rHash := htlc.RHash
+		state.Htlcs = append(state.Htlcs, &lnrpc.HTLC{
+			Incoming:         htlc.Incoming,
+			Amount:           int64(htlc.Amt.ToSatoshis()),
+			HashLock:         rHash[:],
+			ExpirationHeight: htlc.RefundTimeout,
+			HtlcIndex:        htlc.HtlcIndex,
+		})
+	}
+
+	return state
+}
+
 // ListChannels returns a description of all the open channels that this node
 // is a participant in.
 func (r *rpcServer) ListChannels(ctx context.Context,
@@ -4988,6 +5784,15 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 					},
 				}
 
+			case channelnotifier.ReestablishFailedEvent:
+				failure := marshallReestablishFailure(event)
+				update = &lnrpc.ChannelEventUpdate{
+					Type: lnrpc.ChannelEventUpdate_REESTABLISH_FAILED_CHANNEL,
+					Channel: &lnrpc.ChannelEventUpdate_ReestablishFailedChannel{
+						ReestablishFailedChannel: failure,
+					},
+				}
+
 			default:
 				return fmt.Errorf("unexpected channel event update: %v", event)
 			}
@@ -5011,6 +5816,45 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 	}
 }
 
+// marshallReestablishFailure converts a channel reestablish failure event into
+// its RPC representation.
+func marshallReestablishFailure(
+	event channelnotifier.ReestablishFailedEvent) *lnrpc.ChannelReestablishFailure { //nolint:lll
+
+	var reason lnrpc.ReestablishFailureReason
+	switch event.Reason {
+	case channelnotifier.ReestablishFailureRemoteDataLoss:
+		reason = lnrpc.ReestablishFailureReason_REMOTE_DATA_LOSS
+
+	case channelnotifier.ReestablishFailureLocalDataLoss:
+		reason = lnrpc.ReestablishFailureReason_LOCAL_DATA_LOSS
+
+	case channelnotifier.ReestablishFailureInvalidCommitSecret:
+		reason = lnrpc.ReestablishFailureReason_INVALID_COMMIT_SECRET
+
+	case channelnotifier.ReestablishFailureInvalidCommitPoint:
+		reason = lnrpc.ReestablishFailureReason_INVALID_COMMIT_POINT
+
+	case channelnotifier.ReestablishFailureCannotSyncCommitChains:
+		reason = lnrpc.ReestablishFailureReason_CANNOT_SYNC_COMMIT_CHAINS //nolint:lll
+
+	default:
+		reason = lnrpc.ReestablishFailureReason_REESTABLISH_FAILURE_UNKNOWN //nolint:lll
+	}
+
+	var errStr string
+	if event.Err != nil {
+		errStr = event.Err.Error()
+	}
+
+	return &lnrpc.ChannelReestablishFailure{
+		ChanPoint:  marshalChanPoint(*event.ChannelPoint),
+		Reason:     reason,
+		Error:      errStr,
+		ForceClose: event.ForceClose,
+	}
+}
+
 // paymentStream enables different types of payment streams, such as:
 // lnrpc.Lightning_SendPaymentServer and lnrpc.Lightning_SendToRouteServer to
 // execute sendPayment. We use this struct as a sort of bridge to enable code
@@ -5768,7 +6612,10 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
 			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
 		},
-		GetAlias: r.server.aliasMgr.GetPeerAlias,
+		GetAlias:         r.server.aliasMgr.GetPeerAlias,
+		FallbackAddr:     r.server.invoiceFallbackAddr,
+		MaxAmount:        r.cfg.Invoices.MaxAmount,
+		RejectZeroAmount: !r.cfg.Invoices.AllowZeroAmount,
 	}
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
@@ -5791,6 +6638,12 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Private:         invoice.Private,
 		RouteHints:      routeHints,
 		Amp:             invoice.IsAmp,
+		SetFeatures: invoicesrpc.CreateFeatureBits(
+			invoice.SetFeatures,
+		),
+		UnsetFeatures: invoicesrpc.CreateFeatureBits(
+			invoice.UnsetFeatures,
+		),
 	}
 
 	if invoice.RPreimage != nil {
@@ -5801,6 +6654,18 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		addInvoiceData.Preimage = &preimage
 	}
 
+	// If a payment hash is specified together with the preimage, we verify
+	// that both match. Without a preimage, the payment hash is ignored as
+	// it always was, so callers that set it don't break. Hold invoices are
+	// created through the AddHoldInvoice RPC instead.
+	if invoice.RHash != nil && invoice.RPreimage != nil {
+		hash, err := lntypes.MakeHash(invoice.RHash)
+		if err != nil {
+			return nil, err
+		}
+		addInvoiceData.Hash = &hash
+	}
+
 	hash, dbInvoice, err := invoicesrpc.AddInvoice(
 		ctx, addInvoiceCfg, addInvoiceData,
 	)
@@ -5928,6 +6793,23 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 	return resp, nil
 }
 
+// GetInvoiceIndices returns the highest add and settle index that have been
+// assigned to invoices so far.
+func (r *rpcServer) GetInvoiceIndices(ctx context.Context,
+	_ *lnrpc.GetInvoiceIndicesRequest) (*lnrpc.GetInvoiceIndicesResponse,
+	error) {
+
+	addIndex, settleIndex, err := r.server.invoices.InvoiceIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.GetInvoiceIndicesResponse{
+		AddIndex:    addIndex,
+		SettleIndex: settleIndex,
+	}, nil
+}
+
 // SubscribeInvoices returns a uni-directional stream (server -> client) for
 // notifying the client of newly added/settled invoices.
 func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
@@ -6620,13 +7502,32 @@ func (r *rpcServer) StopDaemon(_ context.Context,
 // review of the responding node. Events notified include: new nodes coming
 // online, nodes updating their authenticated attributes, new channels being
 // advertised, updates in the routing policy for a directional channel edge,
-// and finally when prior channels are closed on-chain.
+// and finally when prior channels are closed on-chain. Clients can resume a
+// subscription from a sequence number or request a snapshot of the full graph
+// first.
 func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
 	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
 
+	if req.IncludeSnapshot && req.StartSeqNum != 0 {
+		return fmt.Errorf("include_snapshot and start_seq_num are " +
+			"mutually exclusive")
+	}
+
 	// First, we start by subscribing to a new intent to receive
-	// notifications from the channel router.
-	client, err := r.server.chanRouter.SubscribeTopology()
+	// notifications from the channel router. If the client wants to
+	// resume a previous subscription, the router replays the changes it
+	// missed.
+	var (
+		client *routing.TopologyClient
+		err    error
+	)
+	if req.StartSeqNum != 0 {
+		client, err = r.server.chanRouter.SubscribeTopologyFrom(
+			req.StartSeqNum,
+		)
+	} else {
+		client, err = r.server.chanRouter.SubscribeTopology()
+	}
 	if err != nil {
 		return err
 	}
@@ -6635,6 +7536,24 @@ func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
 	// up once either the server, or client exists.
 	defer client.Cancel()
 
+	// We take the snapshot only after subscribing, so no change can get
+	// lost in between. Changes that are already part of the snapshot may
+	// be sent again afterwards, which is fine since applying them again
+	// doesn't alter the graph.
+	if req.IncludeSnapshot {
+		snapshot, err := r.server.chanRouter.TopologySnapshot()
+		if err != nil {
+			return err
+		}
+		snapshot.SeqNum = client.SeqNum
+
+		graphUpdate := marshallTopologyChange(snapshot)
+		graphUpdate.Snapshot = true
+		if err := updateStream.Send(graphUpdate); err != nil {
+			return err
+		}
+	}
+
 	for {
 		select {
 
@@ -6779,6 +7698,7 @@ func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopol
 		NodeUpdates:    nodeUpdates,
 		ChannelUpdates: channelUpdates,
 		ClosedChans:    closedChans,
+		SeqNum:         topChange.SeqNum,
 	}
 }
 
@@ -7165,11 +8085,204 @@ func (r *rpcServer) FeeReport(ctx context.Context,
 // 0.000001, or 0.0001%.
 const minFeeRate = 1e-6
 
+// ForwardingPolicies returns the effective forwarding policy of our channels.
+// For channels with an active link, this is the policy the link currently
+// enforces, which includes overrides that may not have been gossiped yet.
+// Otherwise it is our latest policy from the graph, or the configured default
+// policy if we haven't announced one yet.
+func (r *rpcServer) ForwardingPolicies(_ context.Context,
+	req *lnrpc.ForwardingPoliciesRequest) (*lnrpc.ForwardingPoliciesResponse,
+	error) {
+
+	openChannels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	livePolicies := r.server.htlcSwitch.ForwardingPolicies()
+	selfPubKey := r.server.identityECDH.PubKey().SerializeCompressed()
+
+	resp := &lnrpc.ForwardingPoliciesResponse{
+		Policies: make([]*lnrpc.EffectiveForwardingPolicy, 0),
+	}
+	for _, channel := range openChannels {
+		chanID := channel.ShortChanID().ToUint64()
+		if req.ChanId != 0 && req.ChanId != chanID {
+			continue
+		}
+
+		chanPoint := channel.FundingOutpoint
+		rpcPolicy := &lnrpc.EffectiveForwardingPolicy{
+			ChanId:       chanID,
+			ChannelPoint: chanPoint.String(),
+			MaxHtlcInFlightMsat: uint64(
+				channel.LocalChanCfg.MaxPendingAmount,
+			),
+		}
+
+		// The policy of an active link takes precedence, as that is
+		// the one that is enforced when forwarding.
+		linkChanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+		policy, ok := livePolicies[linkChanID]
+		if ok {
+			rpcPolicy.Source = lnrpc.EffectiveForwardingPolicy_ACTIVE_LINK
+		} else {
+			policy, ok, err = r.selfForwardingPolicy(
+				&chanPoint, selfPubKey,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// Without an announced policy, the link will be
+			// created with the default policy.
+			if ok {
+				rpcPolicy.Source = lnrpc.EffectiveForwardingPolicy_GRAPH
+			} else {
+				policy = r.server.cc.RoutingPolicy
+				rpcPolicy.Source = lnrpc.EffectiveForwardingPolicy_DEFAULT
+			}
+		}
+
+		rpcPolicy.BaseFeeMsat = int64(policy.BaseFee)
+		rpcPolicy.FeeRatePpm = int64(policy.FeeRate)
+		rpcPolicy.InboundBaseFeeMsat = policy.InboundFee.Base
+		rpcPolicy.InboundFeeRatePpm = policy.InboundFee.Rate
+		rpcPolicy.TimeLockDelta = policy.TimeLockDelta
+		rpcPolicy.MinHtlcMsat = uint64(policy.MinHTLCOut)
+		rpcPolicy.MaxHtlcMsat = uint64(policy.MaxHTLC)
+
+		resp.Policies = append(resp.Policies, rpcPolicy)
+	}
+
+	if req.ChanId != 0 && len(resp.Policies) == 0 {
+		return nil, fmt.Errorf("no open channel with chan id %v",
+			req.ChanId)
+	}
+
+	return resp, nil
+}
+
+// selfForwardingPolicy returns the forwarding policy that we've announced for
+// the given channel in the graph. The returned boolean is false if we haven't
+// announced a policy for the channel yet.
+func (r *rpcServer) selfForwardingPolicy(chanPoint *wire.OutPoint,
+	selfPubKey []byte) (models.ForwardingPolicy, bool, error) {
+
+	info, p1, p2, err := r.server.graphDB.FetchChannelEdgesByOutpoint(
+		chanPoint,
+	)
+	switch {
+	case errors.Is(err, channeldb.ErrEdgeNotFound):
+		return models.ForwardingPolicy{}, false, nil
+
+	case err != nil:
+		return models.ForwardingPolicy{}, false, err
+	}
+
+	selfPolicy := p2
+	if bytes.Equal(info.NodeKey1Bytes[:], selfPubKey) {
+		selfPolicy = p1
+	}
+	if selfPolicy == nil {
+		return models.ForwardingPolicy{}, false, nil
+	}
+
+	var inboundFee lnwire.Fee
+	_, err = selfPolicy.ExtraOpaqueData.ExtractRecords(&inboundFee)
+	if err != nil {
+		return models.ForwardingPolicy{}, false, err
+	}
+
+	return models.ForwardingPolicy{
+		MinHTLCOut:    selfPolicy.MinHTLC,
+		MaxHTLC:       selfPolicy.MaxHTLC,
+		BaseFee:       selfPolicy.FeeBaseMSat,
+		FeeRate:       selfPolicy.FeeProportionalMillionths,
+		TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
+		InboundFee:    models.NewInboundFeeFromWire(inboundFee),
+	}, true, nil
+}
+
 // UpdateChannelPolicy allows the caller to update the channel forwarding policy
-// for all channels globally, or a particular channel.
+// for all channels globally, or a particular channel. The update of a single
+// channel is applied right away, a global update is queued for all channels
+// and applied gradually by the policy update queue.
 func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	req *lnrpc.PolicyUpdateRequest) (*lnrpc.PolicyUpdateResponse, error) {
 
+	targetChans, chanPolicy, err := r.parsePolicyUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[updatechanpolicy] updating channel policy "+
+		"base_fee=%v, rate_fixed=%v, time_lock_delta: %v, "+
+		"min_htlc=%v, max_htlc=%v, targets=%v",
+		chanPolicy.BaseFee, chanPolicy.FeeRate,
+		chanPolicy.TimeLockDelta, chanPolicy.MinHTLC,
+		chanPolicy.MaxHTLC, spew.Sdump(targetChans))
+
+	// With the scope resolved, we'll now send this to the policy update
+	// queue so it can propagate the new policy for our target channel(s).
+	// A single channel is updated right away, which also drops a queued
+	// update of it that would otherwise overwrite the new policy.
+	if len(targetChans) == 1 {
+		failedUpdates, err := r.server.policyUpdateQueue.ApplyNow(
+			*chanPolicy, targetChans[0],
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.PolicyUpdateResponse{
+			FailedUpdates: failedUpdates,
+		}, nil
+	}
+
+	// Updating all channels at once would flood the network with channel
+	// updates, so a global update is queued instead.
+	targetChans, err = r.fetchOutgoingChanPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]localchans.QueuedUpdate, 0, len(targetChans))
+	for _, chanPoint := range targetChans {
+		updates = append(updates, localchans.QueuedUpdate{
+			ChanPoint: chanPoint,
+			Policy:    *chanPolicy,
+		})
+	}
+	r.server.policyUpdateQueue.Enqueue(updates...)
+
+	return &lnrpc.PolicyUpdateResponse{}, nil
+}
+
+// fetchOutgoingChanPoints returns the channel points of all our channels.
+func (r *rpcServer) fetchOutgoingChanPoints() ([]wire.OutPoint, error) {
+	var chanPoints []wire.OutPoint
+	err := r.server.chanRouter.ForAllOutgoingChannels(func(_ kvdb.RTx,
+		info *models.ChannelEdgeInfo,
+		_ *models.ChannelEdgePolicy) error {
+
+		chanPoints = append(chanPoints, info.ChannelPoint)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanPoints, nil
+}
+
+// parsePolicyUpdate validates a policy update request and returns the
+// channels it targets along with the new policy. No channels are returned if
+// the update targets all channels.
+func (r *rpcServer) parsePolicyUpdate(req *lnrpc.PolicyUpdateRequest) (
+	[]wire.OutPoint, *routing.ChannelPolicy, error) {
+
 	var targetChans []wire.OutPoint
 	switch scope := req.Scope.(type) {
 	// If the request is targeting all active channels, then we don't need
@@ -7181,14 +8294,14 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	case *lnrpc.PolicyUpdateRequest_ChanPoint:
 		txid, err := lnrpc.GetChanPointFundingTxid(scope.ChanPoint)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		targetChans = append(targetChans, wire.OutPoint{
 			Hash:  *txid,
 			Index: scope.ChanPoint.OutputIndex,
 		})
 	default:
-		return nil, fmt.Errorf("unknown scope: %v", scope)
+		return nil, nil, fmt.Errorf("unknown scope: %v", scope)
 	}
 
 	var feeRateFixed uint32
@@ -7200,7 +8313,7 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		errMsg := "cannot set both FeeRate and FeeRatePpm at the " +
 			"same time"
 
-		return nil, status.Errorf(codes.InvalidArgument, errMsg)
+		return nil, nil, status.Errorf(codes.InvalidArgument, errMsg)
 
 	// If the request is using fee_rate.
 	case req.FeeRate != 0:
@@ -7208,7 +8321,7 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		// the passed fee rate is below 1e-6, or the lowest allowed
 		// non-zero fee rate expressible within the protocol.
 		if req.FeeRate != 0 && req.FeeRate < minFeeRate {
-			return nil, fmt.Errorf("fee rate of %v is too "+
+			return nil, nil, fmt.Errorf("fee rate of %v is too "+
 				"small, min fee rate is %v", req.FeeRate,
 				minFeeRate)
 		}
@@ -7232,24 +8345,24 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 	// We'll also ensure that the user isn't setting a CLTV delta that
 	// won't give outgoing HTLCs enough time to fully resolve if needed.
 	if req.TimeLockDelta < minTimeLockDelta {
-		return nil, fmt.Errorf("time lock delta of %v is too small, "+
-			"minimum supported is %v", req.TimeLockDelta,
+		return nil, nil, fmt.Errorf("time lock delta of %v is too "+
+			"small, minimum supported is %v", req.TimeLockDelta,
 			minTimeLockDelta)
 	} else if req.TimeLockDelta > uint32(MaxTimeLockDelta) {
-		return nil, fmt.Errorf("time lock delta of %v is too big, "+
-			"maximum supported is %v", req.TimeLockDelta,
+		return nil, nil, fmt.Errorf("time lock delta of %v is too "+
+			"big, maximum supported is %v", req.TimeLockDelta,
 			MaxTimeLockDelta)
 	}
 
 	// By default, positive inbound fees are rejected.
 	if !r.cfg.AcceptPositiveInboundFees && req.InboundFee != nil {
 		if req.InboundFee.BaseFeeMsat > 0 {
-			return nil, fmt.Errorf("positive values for inbound "+
+			return nil, nil, fmt.Errorf("positive values for inbound "+
 				"base fee msat are not supported: %v",
 				req.InboundFee.BaseFeeMsat)
 		}
 		if req.InboundFee.FeeRatePpm > 0 {
-			return nil, fmt.Errorf("positive values for inbound "+
+			return nil, nil, fmt.Errorf("positive values for inbound "+
 				"fee rate ppm are not supported: %v",
 				req.InboundFee.FeeRatePpm)
 		}
@@ -7280,31 +8393,210 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		minHtlc = &min
 	}
 
-	chanPolicy := routing.ChannelPolicy{
+	chanPolicy := &routing.ChannelPolicy{
 		FeeSchema:     feeSchema,
 		TimeLockDelta: req.TimeLockDelta,
 		MaxHTLC:       maxHtlc,
 		MinHTLC:       minHtlc,
 	}
 
-	rpcsLog.Debugf("[updatechanpolicy] updating channel policy "+
-		"base_fee=%v, rate_fixed=%v, time_lock_delta: %v, "+
-		"min_htlc=%v, max_htlc=%v, targets=%v",
-		req.BaseFeeMsat, feeRateFixed, req.TimeLockDelta,
-		minHtlc, maxHtlc,
-		spew.Sdump(targetChans))
+	return targetChans, chanPolicy, nil
+}
+
+// QueuePolicyUpdates queues channel policy updates that are applied gradually,
+// so that updating many channels at once doesn't cause a burst of channel
+// updates. Urgent updates of a single channel are applied right away.
+func (r *rpcServer) QueuePolicyUpdates(_ context.Context,
+	req *lnrpc.QueuePolicyUpdatesRequest) (*lnrpc.QueuePolicyUpdatesResponse,
+	error) {
+
+	if len(req.Updates) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no policy "+
+			"updates specified")
+	}
+
+	// An urgent update skips the queue, which is only acceptable for a
+	// single channel, as otherwise we'd end up with the burst of updates
+	// the queue is meant to avoid.
+	if req.Urgent {
+		if len(req.Updates) != 1 {
+			return nil, status.Error(codes.InvalidArgument,
+				"only a single update can be urgent")
+		}
+
+		targetChans, chanPolicy, err := r.parsePolicyUpdate(
+			req.Updates[0],
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(targetChans) != 1 {
+			return nil, status.Error(codes.InvalidArgument,
+				"an urgent update must target a single channel")
+		}
+
+		rpcsLog.Debugf("[queuepolicyupdates] applying urgent policy "+
+			"update of channel %v", targetChans[0])
+
+		failedUpdates, err := r.server.policyUpdateQueue.ApplyNow(
+			*chanPolicy, targetChans[0],
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.QueuePolicyUpdatesResponse{
+			FailedUpdates: failedUpdates,
+		}, nil
+	}
+
+	// All updates are validated before queueing any of them, so that an
+	// invalid request doesn't leave the queue half updated.
+	var updates []localchans.QueuedUpdate
+	for _, rpcUpdate := range req.Updates {
+		targetChans, chanPolicy, err := r.parsePolicyUpdate(rpcUpdate)
+		if err != nil {
+			return nil, err
+		}
+
+		// A global update is queued for each of our channels.
+		if len(targetChans) == 0 {
+			targetChans, err = r.fetchOutgoingChanPoints()
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	// With the scope resolved, we'll now send this to the local channel
-	// manager so it can propagate the new policy for our target channel(s).
-	failedUpdates, err := r.server.localChanMgr.UpdatePolicy(chanPolicy,
-		targetChans...)
+		for _, chanPoint := range targetChans {
+			updates = append(updates, localchans.QueuedUpdate{
+				ChanPoint: chanPoint,
+				Policy:    *chanPolicy,
+			})
+		}
+	}
+
+	rpcsLog.Debugf("[queuepolicyupdates] queueing %v policy updates",
+		len(updates))
+
+	r.server.policyUpdateQueue.Enqueue(updates...)
+
+	return &lnrpc.QueuePolicyUpdatesResponse{
+		NumQueued: uint32(len(updates)),
+	}, nil
+}
+
+// PolicyUpdateQueueStatus reports the progress of the queued channel policy
+// updates.
+func (r *rpcServer) PolicyUpdateQueueStatus(_ context.Context,
+	_ *lnrpc.PolicyUpdateQueueStatusRequest) (
+	*lnrpc.PolicyUpdateQueueStatusResponse, error) {
+
+	queueStatus := r.server.policyUpdateQueue.Status()
+
+	resp := &lnrpc.PolicyUpdateQueueStatusResponse{
+		PendingUpdates: make(
+			[]*lnrpc.PendingPolicyUpdate, 0,
+			len(queueStatus.Pending),
+		),
+		NumApplied:     queueStatus.NumApplied,
+		NumFailed:      queueStatus.NumFailed,
+		RecentFailures: queueStatus.RecentFailures,
+		BatchSize:      uint32(r.cfg.Gossip.MaxChannelUpdateBurst),
+		BatchIntervalSec: uint64(
+			r.cfg.Gossip.ChannelUpdateInterval.Seconds(),
+		),
+		NextBatchTime: queueStatus.NextBatch.Unix(),
+	}
+	for _, update := range queueStatus.Pending {
+		rpcUpdate := &lnrpc.PendingPolicyUpdate{
+			ChannelPoint:       update.ChanPoint.String(),
+			QueuedAt:           update.QueuedAt.Unix(),
+			EstimatedApplyTime: update.EstimatedApplyTime.Unix(),
+		}
+		resp.PendingUpdates = append(resp.PendingUpdates, rpcUpdate)
+	}
+
+	return resp, nil
+}
+
+// SimulateFeeChange estimates how proposed fee changes of our channels would
+// affect their routing volume. It samples random payments between other nodes
+// of the graph and compares the cheapest routes for them with the current and
+// the proposed fees. Nothing is changed by this call.
+func (r *rpcServer) SimulateFeeChange(_ context.Context,
+	req *lnrpc.SimulateFeeChangeRequest) (*lnrpc.SimulateFeeChangeResponse,
+	error) {
+
+	simReq := &routing.FeeSimulationRequest{
+		Amount:    routing.DefaultFeeSimulationAmount,
+		Samples:   routing.DefaultFeeSimulationSamples,
+		CltvLimit: r.cfg.MaxOutgoingCltvExpiry,
+	}
+
+	switch {
+	case req.AmtSat < 0:
+		return nil, errors.New("amount must not be negative")
+
+	case req.AmtSat > 0:
+		simReq.Amount = lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(req.AmtSat),
+		)
+	}
+
+	switch {
+	case req.NumSamples > routing.MaxFeeSimulationSamples:
+		return nil, fmt.Errorf("at most %v samples can be simulated",
+			routing.MaxFeeSimulationSamples)
+
+	case req.NumSamples > 0:
+		simReq.Samples = int(req.NumSamples)
+	}
+
+	for _, change := range req.Changes {
+		if change.BaseFeeMsat < 0 {
+			return nil, fmt.Errorf("base fee of channel %v must "+
+				"not be negative", change.ChanId)
+		}
+
+		simReq.Changes = append(simReq.Changes, routing.FeeChange{
+			ChannelID: change.ChanId,
+			BaseFee:   lnwire.MilliSatoshi(change.BaseFeeMsat),
+			FeeRate:   lnwire.MilliSatoshi(change.FeeRatePpm),
+		})
+	}
+
+	rpcsLog.Debugf("[simulatefeechange] simulating %v payments of %v "+
+		"with %v fee changes", simReq.Samples, simReq.Amount,
+		len(simReq.Changes))
+
+	result, err := r.server.chanRouter.SimulateFeeChanges(simReq)
 	if err != nil {
 		return nil, err
 	}
 
-	return &lnrpc.PolicyUpdateResponse{
-		FailedUpdates: failedUpdates,
-	}, nil
+	resp := &lnrpc.SimulateFeeChangeResponse{
+		NumSamples:  uint32(result.Samples),
+		RoutesFound: uint32(result.RoutesFound),
+		Channels:    make([]*lnrpc.ChannelRoutingEstimate, 0),
+	}
+	for _, estimate := range result.Channels {
+		resp.Channels = append(resp.Channels,
+			&lnrpc.ChannelRoutingEstimate{
+				ChanId:         estimate.ChannelID,
+				RoutesBefore:   uint32(estimate.RoutesBefore),
+				RoutesAfter:    uint32(estimate.RoutesAfter),
+				FeesBeforeMsat: int64(estimate.FeesBefore),
+				FeesAfterMsat:  int64(estimate.FeesAfter),
+			},
+		)
+	}
+
+	// Sort the channels to return them in a stable order.
+	sort.Slice(resp.Channels, func(i, j int) bool {
+		return resp.Channels[i].ChanId < resp.Channels[j].ChanId
+	})
+
+	return resp, nil
 }
 
 // ForwardingHistory allows the caller to query the htlcswitch for a record of
@@ -7665,6 +8957,206 @@ func (r *rpcServer) ExportAllChannelBackups(ctx context.Context,
 	return r.createBackupSnapshot(allUnpackedBackups)
 }
 
+// ExportAllChannelBackupsToFile atomically writes a multi-channel backup of all
+// channels, including pending ones, to the requested path.
+func (r *rpcServer) ExportAllChannelBackupsToFile(_ context.Context,
+	in *lnrpc.ExportAllChannelBackupsToFileRequest) (
+	*lnrpc.ExportAllChannelBackupsToFileResponse, error) {
+
+	// A relative path would be resolved against the working directory of
+	// lnd, which is rarely what the caller expects.
+	if !filepath.IsAbs(in.Path) {
+		return nil, fmt.Errorf("backup path must be absolute")
+	}
+
+	// The channel source returns all channels we know of, so pending
+	// channels and channels waiting to be closed are included as well.
+	backups, err := chanbackup.FetchStaticChanBackups(
+		r.server.chanStateDB, r.server.addrSource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch all static chan "+
+			"backups: %v", err)
+	}
+
+	var b bytes.Buffer
+	unpackedMultiBackup := chanbackup.Multi{
+		StaticBackups: backups,
+	}
+	err = unpackedMultiBackup.PackToWriter(&b, r.server.cc.KeyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to multi-pack backups: %w", err)
+	}
+
+	err = chanbackup.WritePackedMulti(
+		in.Path, chanbackup.PackedMulti(b.Bytes()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write channel backup file: "+
+			"%w", err)
+	}
+
+	chanPoints := make([]wire.OutPoint, 0, len(backups))
+	for _, backup := range backups {
+		chanPoints = append(chanPoints, backup.FundingOutpoint)
+	}
+
+	rpcsLog.Infof("Wrote backup of %d channels to %v", len(chanPoints),
+		in.Path)
+
+	return &lnrpc.ExportAllChannelBackupsToFileResponse{
+		Path:       in.Path,
+		ChanPoints: marshalChanPoints(chanPoints),
+	}, nil
+}
+
+// CheckChanBackupFile re-derives the static channel backups of all current
+// channels and compares them against the channel backup file on disk.
+func (r *rpcServer) CheckChanBackupFile(ctx context.Context,
+	_ *lnrpc.CheckChanBackupFileRequest) (
+	*lnrpc.CheckChanBackupFileResponse, error) {
+
+	liveBackups, err := chanbackup.FetchStaticChanBackups(
+		r.server.chanStateDB, r.server.addrSource,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch all static chan "+
+			"backups: %v", err)
+	}
+
+	backupFile := chanbackup.NewMultiFile(r.cfg.BackupFilePath)
+	fileBackup, err := backupFile.ExtractMulti(r.server.cc.KeyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read channel backup file "+
+			"%v: %v", r.cfg.BackupFilePath, err)
+	}
+
+	diff := chanbackup.CompareBackups(
+		liveBackups, fileBackup.StaticBackups,
+	)
+	if !diff.InSync() {
+		rpcsLog.Warnf("Channel backup file %v is out of sync: %d "+
+			"channels missing, %d stale channels",
+			r.cfg.BackupFilePath, len(diff.MissingChans),
+			len(diff.StaleChans))
+	}
+
+	return &lnrpc.CheckChanBackupFileResponse{
+		InSync:            diff.InSync(),
+		MissingChanPoints: marshalChanPoints(diff.MissingChans),
+		StaleChanPoints:   marshalChanPoints(diff.StaleChans),
+		NumLiveChans:      uint32(len(liveBackups)),
+		NumFileChans:      uint32(len(fileBackup.StaticBackups)),
+	}, nil
+}
+
+// SnapshotChannelDB writes a consistent point-in-time copy of the full channel
+// database to a new file at the requested path.
+func (r *rpcServer) SnapshotChannelDB(_ context.Context,
+	in *lnrpc.SnapshotChannelDBRequest) (*lnrpc.SnapshotChannelDBResponse,
+	error) {
+
+	// A relative path would be resolved against the working directory of
+	// lnd, which is rarely what the caller expects.
+	if !filepath.IsAbs(in.Path) {
+		return nil, fmt.Errorf("snapshot path must be absolute")
+	}
+
+	rpcsLog.Infof("Writing channel DB snapshot to %v", in.Path)
+
+	size, err := r.server.chanStateDB.GetParentDB().WriteSnapshot(in.Path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write channel DB snapshot: "+
+			"%w", err)
+	}
+
+	rpcsLog.Infof("Wrote channel DB snapshot of %d bytes to %v", size,
+		in.Path)
+
+	return &lnrpc.SnapshotChannelDBResponse{
+		Path:      in.Path,
+		SizeBytes: size,
+	}, nil
+}
+
+// CompactChannelDB compacts the channel database while lnd is running. Writes
+// are only paused while the compacted copy is swapped in, and the whole
+// compaction is bounded by the timeout of the request.
+func (r *rpcServer) CompactChannelDB(ctx context.Context,
+	in *lnrpc.CompactChannelDBRequest) (*lnrpc.CompactChannelDBResponse,
+	error) {
+
+	if r.cfg.DB.Backend != lncfg.BoltBackend {
+		return nil, fmt.Errorf("%w, the %v backend is used",
+			channeldb.ErrCompactionUnsupported, r.cfg.DB.Backend)
+	}
+
+	// Tracking the transactions to pause writes for the swap has
+	// a cost, so the channel DB is only opened that way if requested.
+	if !r.cfg.DB.Bolt.CompactOnDemand {
+		return nil, errors.New("compacting the channel DB on demand " +
+			"is disabled, enable it with db.bolt.compact-on-demand")
+	}
+
+	if in.TimeoutSeconds != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(
+			ctx, time.Duration(in.TimeoutSeconds)*time.Second,
+		)
+		defer cancel()
+	}
+
+	rpcsLog.Infof("Compacting channel DB on request")
+
+	start := time.Now()
+	chanDB := r.server.chanStateDB.GetParentDB()
+	sizeBefore, sizeAfter, err := chanDB.Compact(ctx)
+	switch {
+	// The compaction was aborted before the compacted file was swapped in,
+	// so the database is unchanged.
+	case err != nil && ctx.Err() != nil:
+		return nil, fmt.Errorf("channel DB compaction aborted after "+
+			"%v, the database is unchanged: %w",
+			time.Since(start).Truncate(time.Millisecond), ctx.Err())
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to compact channel DB: %w", err)
+	}
+
+	rpcsLog.Infof("Compacted channel DB from %d to %d bytes in %v",
+		sizeBefore, sizeAfter,
+		time.Since(start).Truncate(time.Millisecond))
+
+	return &lnrpc.CompactChannelDBResponse{
+		SizeBeforeBytes: sizeBefore,
+		SizeAfterBytes:  sizeAfter,
+	}, nil
+}
+
+// marshalChanPoints converts the given channel outpoints into their RPC
+// representation.
+func marshalChanPoints(chanPoints []wire.OutPoint) []*lnrpc.ChannelPoint {
+	rpcChanPoints := make([]*lnrpc.ChannelPoint, 0, len(chanPoints))
+	for _, chanPoint := range chanPoints {
+		rpcChanPoints = append(
+			rpcChanPoints, marshalChanPoint(chanPoint),
+		)
+	}
+
+	return rpcChanPoints
+}
+
+// marshalChanPoint converts the given channel outpoint into its RPC
+// representation.
+func marshalChanPoint(chanPoint wire.OutPoint) *lnrpc.ChannelPoint {
+	return &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: chanPoint.Hash[:],
+		},
+		OutputIndex: chanPoint.Index,
+	}
+}
+
 // RestoreChannelBackups accepts a set of singular channel backups, or a single
 // encrypted multi-chan backup and attempts to recover any funds remaining
 // within the channel. If we're able to unpack the backup, then the new channel
@@ -7688,8 +9180,16 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 		chainArb:   r.server.chainArb,
 	}
 
+	// If only selected channels should be restored, we'll assemble the
+	// filter that determines which channels of the backup are restored.
+	filter, err := unmarshallRestoreFilter(in.ChanPoints, in.Peers)
+	if err != nil {
+		return nil, err
+	}
+
 	// We'll accept either a list of Single backups, or a single Multi
 	// backup which contains several single backups.
+	var backups []chanbackup.Single
 	switch {
 	case in.GetChanBackups() != nil:
 		chanBackupsProtos := in.GetChanBackups()
@@ -7703,13 +9203,8 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 			)
 		}
 
-		// With our backups obtained, we'll now restore them which will
-		// write the new backups to disk, and then attempt to connect
-		// out to any peers that we know of which were our prior
-		// channel peers.
-		err := chanbackup.UnpackAndRecoverSingles(
-			chanbackup.PackedSingles(packedBackups),
-			r.server.cc.KeyRing, chanRestorer, r.server,
+		backups, err = chanbackup.PackedSingles(packedBackups).Unpack(
+			r.server.cc.KeyRing,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to unpack single "+
@@ -7719,22 +9214,395 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 	case in.GetMultiChanBackup() != nil:
 		packedMultiBackup := in.GetMultiChanBackup()
 
-		// With our backups obtained, we'll now restore them which will
-		// write the new backups to disk, and then attempt to connect
-		// out to any peers that we know of which were our prior
-		// channel peers.
 		packedMulti := chanbackup.PackedMulti(packedMultiBackup)
-		err := chanbackup.UnpackAndRecoverMulti(
-			packedMulti, r.server.cc.KeyRing, chanRestorer,
-			r.server,
-		)
+		multi, err := packedMulti.Unpack(r.server.cc.KeyRing)
 		if err != nil {
 			return nil, fmt.Errorf("unable to unpack chan "+
 				"backup: %v", err)
 		}
+		backups = multi.StaticBackups
+	}
+
+	// With our backups obtained, we'll now restore the selected ones which
+	// will write the new backups to disk, and then attempt to connect out
+	// to any peers that we know of which were our prior channel peers in
+	// the background. Channels that couldn't be restored are reported as
+	// failed in their result, so we still recover the other ones and
+	// return the outcome of all of them.
+	results, err := chanbackup.RestoreFiltered(
+		backups, filter, chanRestorer,
+	)
+	if err != nil {
+		rpcsLog.Errorf("Unable to restore all chan backups: %v", err)
+	}
+	r.server.recoverRestoredChannels(
+		chanbackup.RestoredBackups(backups, results),
+	)
+
+	// While the peers are connected to, we'll check the funding outputs
+	// of the selected channels if requested. The lookups are aborted once
+	// the caller goes away, in which case the remaining funding outputs
+	// are reported as unverified.
+	fundingOutputs := make([]chanbackup.FundingOutputStatus, len(results))
+	if r.cfg.VerifyRestoredChannels {
+		var (
+			selected []chanbackup.Single
+			indexes  []int
+		)
+		for i, result := range results {
+			switch result.Status {
+			case chanbackup.RestoreStatusSkipped,
+				chanbackup.RestoreStatusFailed:
+
+				continue
+			}
+
+			selected = append(selected, backups[i])
+			indexes = append(indexes, i)
+		}
+
+		statuses := r.server.verifyRestoredFundingOutputs(
+			selected, ctx.Done(),
+		)
+		for i, status := range statuses {
+			fundingOutputs[indexes[i]] = status
+		}
 	}
 
-	return &lnrpc.RestoreBackupResponse{}, nil
+	resp := &lnrpc.RestoreBackupResponse{
+		Results: make([]*lnrpc.ChannelRestoreResult, 0, len(results)),
+	}
+	for i, result := range results {
+		rpcResult := &lnrpc.ChannelRestoreResult{
+			ChanPoint: marshalChanPoint(result.ChanPoint),
+			Status:    marshallRestoreStatus(result.Status),
+			FundingOutput: marshallFundingOutputStatus(
+				fundingOutputs[i],
+			),
+		}
+		if result.RemoteNodePub != nil {
+			rpcResult.RemotePubkey = hex.EncodeToString(
+				result.RemoteNodePub.SerializeCompressed(),
+			)
+		}
+		if result.Err != nil {
+			rpcResult.Error = result.Err.Error()
+		}
+
+		resp.Results = append(resp.Results, rpcResult)
+	}
+
+	return resp, nil
+}
+
+// ChannelRecoveryStatus lists the channels that were restored from a static
+// channel backup together with the state of their recovery.
+func (r *rpcServer) ChannelRecoveryStatus(_ context.Context,
+	_ *lnrpc.ChannelRecoveryStatusRequest) (
+	*lnrpc.ChannelRecoveryStatusResponse, error) {
+
+	resp := &lnrpc.ChannelRecoveryStatusResponse{}
+
+	// Restored channels that are still open are waiting for their peer
+	// to send the data needed to recover our funds and to force close the
+	// channel.
+	openChannels, err := r.server.chanStateDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range openChannels {
+		if !channel.HasChanStatus(channeldb.ChanStatusRestored) {
+			continue
+		}
+
+		// If we aren't connected to the peer, we need an address to
+		// reach it at.
+		peerOnline := r.isPeerOnline(channel.IdentityPub)
+		reachable := peerOnline
+		if !reachable {
+			reachable, err = r.isPeerReachable(channel.IdentityPub)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The peer sent us its commitment point, which we stored when
+		// marking the data loss, once it reestablished the channel.
+		dlpReceived := channel.HasChanStatus(
+			channeldb.ChanStatusLocalDataLoss,
+		)
+		timedOut := restoredChanTimedOut(
+			channel, r.cfg.Recovery.PeerTimeout, time.Now(),
+		)
+
+		recovery := &lnrpc.ChannelRecovery{
+			ChanPoint: marshalChanPoint(channel.FundingOutpoint),
+			RemotePubkey: hex.EncodeToString(
+				channel.IdentityPub.SerializeCompressed(),
+			),
+			State: openRecoveryState(
+				dlpReceived, reachable, timedOut,
+			),
+			PeerOnline: peerOnline,
+		}
+		if !channel.RestoredAt.IsZero() {
+			recovery.RestoredAt = uint64(channel.RestoredAt.Unix())
+		}
+		recovery.FundingOutput, err = r.fetchRestoredFundingStatus(
+			channel.FundingOutpoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Channels = append(resp.Channels, recovery)
+	}
+
+	// Once the peer force closed a restored channel, it is moved to the
+	// closed channels. We only know that it was restored from the state
+	// stored in the historical channel bucket.
+	closedChannels, err := r.server.chanStateDB.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range closedChannels {
+		histChan, err := r.server.chanStateDB.FetchHistoricalChannel(
+			&summary.ChanPoint,
+		)
+		switch {
+		// The channel was closed in a version that didn't record
+		// historical channels, so it can't have been restored.
+		case errors.Is(err, channeldb.ErrNoHistoricalBucket),
+			errors.Is(err, channeldb.ErrChannelNotFound):
+
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		if !histChan.HasChanStatus(channeldb.ChanStatusRestored) {
+			continue
+		}
+
+		// The channel stays pending until all of its outputs are
+		// resolved.
+		state := lnrpc.ChannelRecoveryState_RECOVERY_COMPLETE
+		if summary.IsPending {
+			state = lnrpc.
+				ChannelRecoveryState_RECOVERY_CLOSE_CONFIRMED
+		}
+
+		recovery := &lnrpc.ChannelRecovery{
+			ChanPoint: marshalChanPoint(summary.ChanPoint),
+			RemotePubkey: hex.EncodeToString(
+				summary.RemotePub.SerializeCompressed(),
+			),
+			State:       state,
+			PeerOnline:  r.isPeerOnline(summary.RemotePub),
+			ClosingTxid: summary.ClosingTXID.String(),
+		}
+		recovery.FundingOutput, err = r.fetchRestoredFundingStatus(
+			summary.ChanPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		resp.Channels = append(resp.Channels, recovery)
+
+		if summary.IsPending {
+			continue
+		}
+
+		reports, err := r.server.miscDB.FetchChannelReports(
+			*r.cfg.ActiveNetParams.GenesisHash, &summary.ChanPoint,
+		)
+		switch {
+		// If the channel does not have its resolver outcomes stored,
+		// there was nothing to sweep.
+		case errors.Is(err, channeldb.ErrNoChainHashBucket),
+			errors.Is(err, channeldb.ErrNoChannelSummaries):
+
+		case err != nil:
+			return nil, err
+		}
+
+		for _, report := range reports {
+			isCommit := report.ResolverType ==
+				channeldb.ResolverTypeCommit
+			if !isCommit || report.SpendTxID == nil {
+				continue
+			}
+
+			recovery.SweepTxids = append(
+				recovery.SweepTxids, report.SpendTxID.String(),
+			)
+		}
+	}
+
+	return resp, nil
+}
+
+// fetchRestoredFundingStatus returns the status of the funding output of the
+// given restored channel that was stored when it was last looked up on chain.
+func (r *rpcServer) fetchRestoredFundingStatus(
+	chanPoint wire.OutPoint) (lnrpc.FundingOutputStatus, error) {
+
+	status, err := r.server.chanStateDB.FetchRestoredFundingStatus(
+		chanPoint,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return marshallFundingOutputStatus(
+		chanbackup.FundingOutputStatus(status),
+	), nil
+}
+
+// openRecoveryState returns the recovery state of a restored channel that is
+// still open, depending on whether the peer already sent us the data needed to
+// recover our funds, whether we're able to reach the peer and whether the peer
+// failed to send the data within the configured timeout.
+func openRecoveryState(dlpReceived, reachable,
+	timedOut bool) lnrpc.ChannelRecoveryState {
+
+	switch {
+	case dlpReceived:
+		return lnrpc.ChannelRecoveryState_RECOVERY_DLP_RECEIVED
+
+	case !reachable:
+		return lnrpc.ChannelRecoveryState_RECOVERY_NO_PEER_ADDRESS
+
+	case timedOut:
+		return lnrpc.ChannelRecoveryState_RECOVERY_NEEDS_INTERVENTION
+
+	default:
+		return lnrpc.ChannelRecoveryState_RECOVERY_WAITING_FOR_PEER
+	}
+}
+
+// isPeerOnline returns true if we're currently connected to the given peer.
+func (r *rpcServer) isPeerOnline(pubKey *btcec.PublicKey) bool {
+	_, err := r.server.FindPeer(pubKey)
+
+	return err == nil
+}
+
+// isPeerReachable returns true if we know of any address to connect to the
+// given peer at, either from a channel backup or from the peer's node
+// announcement.
+func (r *rpcServer) isPeerReachable(pubKey *btcec.PublicKey) (bool, error) {
+	linkNode, err := r.server.chanStateDB.LinkNodeDB().FetchLinkNode(pubKey)
+	switch {
+	case err == nil:
+		if len(linkNode.Addresses) != 0 {
+			return true, nil
+		}
+
+	case !errors.Is(err, channeldb.ErrNodeNotFound):
+		return false, err
+	}
+
+	_, _, err = r.server.fetchNodeAdvertisedAddrs(pubKey)
+	switch {
+	case err == nil:
+		return true, nil
+
+	case errors.Is(err, errNoAdvertisedAddr),
+		errors.Is(err, channeldb.ErrGraphNodeNotFound):
+
+		return false, nil
+
+	default:
+		return false, err
+	}
+}
+
+// unmarshallRestoreFilter creates the filter selecting the channels of a
+// backup to restore from the given channel points and peer public keys. A nil
+// filter, which selects all channels, is returned if neither is set.
+func unmarshallRestoreFilter(chanPoints []*lnrpc.ChannelPoint,
+	peers [][]byte) (*chanbackup.RestoreFilter, error) {
+
+	if len(chanPoints) == 0 && len(peers) == 0 {
+		return nil, nil
+	}
+
+	filter := &chanbackup.RestoreFilter{
+		ChanPoints: make(map[wire.OutPoint]struct{}, len(chanPoints)),
+		Peers:      make(map[[33]byte]struct{}, len(peers)),
+	}
+	for _, chanPoint := range chanPoints {
+		txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		outPoint := wire.NewOutPoint(txid, chanPoint.OutputIndex)
+		filter.ChanPoints[*outPoint] = struct{}{}
+	}
+	for _, peer := range peers {
+		pubKey, err := btcec.ParsePubKey(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer public key: %w",
+				err)
+		}
+
+		var peerKey [33]byte
+		copy(peerKey[:], pubKey.SerializeCompressed())
+		filter.Peers[peerKey] = struct{}{}
+	}
+
+	return filter, nil
+}
+
+// marshallRestoreStatus converts the outcome of restoring a channel into its
+// RPC representation.
+func marshallRestoreStatus(
+	status chanbackup.RestoreStatus) lnrpc.ChannelRestoreStatus {
+
+	switch status {
+	case chanbackup.RestoreStatusRestored:
+		return lnrpc.ChannelRestoreStatus_RESTORE_STATUS_RESTORED
+
+	case chanbackup.RestoreStatusAlreadyExists:
+		return lnrpc.ChannelRestoreStatus_RESTORE_STATUS_ALREADY_EXISTS
+
+	case chanbackup.RestoreStatusSkipped:
+		return lnrpc.ChannelRestoreStatus_RESTORE_STATUS_SKIPPED
+
+	case chanbackup.RestoreStatusFailed:
+		return lnrpc.ChannelRestoreStatus_RESTORE_STATUS_FAILED
+
+	default:
+		return lnrpc.ChannelRestoreStatus_RESTORE_STATUS_UNKNOWN
+	}
+}
+
+// marshallFundingOutputStatus converts the on-chain state of the funding output
+// of a restored channel into its RPC representation.
+func marshallFundingOutputStatus(
+	status chanbackup.FundingOutputStatus) lnrpc.FundingOutputStatus {
+
+	switch status {
+	case chanbackup.FundingOutputUnspent:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_UNSPENT
+
+	case chanbackup.FundingOutputSpent:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_SPENT
+
+	case chanbackup.FundingOutputNotFound:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_NOT_FOUND
+
+	case chanbackup.FundingOutputMismatch:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_MISMATCH
+
+	case chanbackup.FundingOutputUnconfirmed:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_UNCONFIRMED
+
+	default:
+		return lnrpc.FundingOutputStatus_FUNDING_OUTPUT_UNVERIFIED
+	}
 }
 
 // SubscribeChannelBackups allows a client to sub-subscribe to the most up to
@@ -7777,6 +9645,8 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 				continue
 			case channelnotifier.InactiveLinkEvent:
 				continue
+			case channelnotifier.ReestablishFailedEvent:
+				continue
 			}
 
 			// Now that we know the channel state has changed,
@@ -8199,6 +10069,83 @@ func (r *rpcServer) FundingStateStep(ctx context.Context,
 	return &lnrpc.FundingStateStepResp{}, nil
 }
 
+// AbortChannelOpen aborts pending channel opens that are still being
+// negotiated, either a single one identified by its pending channel ID or all
+// of the ones with a peer.
+func (r *rpcServer) AbortChannelOpen(_ context.Context,
+	in *lnrpc.AbortChannelOpenRequest) (*lnrpc.AbortChannelOpenResponse,
+	error) {
+
+	var (
+		aborted [][32]byte
+		err     error
+	)
+	switch {
+	case len(in.PendingChanId) != 0 && len(in.NodePubkey) != 0:
+		return nil, status.Error(codes.InvalidArgument, "only one of "+
+			"pending_chan_id and node_pubkey can be set")
+
+	case len(in.PendingChanId) != 0:
+		if len(in.PendingChanId) != 32 {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"pending_chan_id must be 32 bytes, got %d",
+				len(in.PendingChanId))
+		}
+
+		var pendingChanID [32]byte
+		copy(pendingChanID[:], in.PendingChanId)
+
+		rpcsLog.Debugf("[abortchannelopen] pending_id=%x",
+			pendingChanID[:])
+
+		err = r.server.fundingMgr.AbortChannelOpen(pendingChanID)
+		if err == nil {
+			aborted = append(aborted, pendingChanID)
+		}
+
+	case len(in.NodePubkey) != 0:
+		nodePubKey, parseErr := btcec.ParsePubKey(in.NodePubkey)
+		if parseErr != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid node_pubkey: %v", parseErr)
+		}
+
+		rpcsLog.Debugf("[abortchannelopen] node_pubkey=%x",
+			in.NodePubkey)
+
+		aborted, err = r.server.fundingMgr.AbortPeerChannelOpens(
+			nodePubKey,
+		)
+
+	default:
+		return nil, status.Error(codes.InvalidArgument, "either "+
+			"pending_chan_id or node_pubkey must be set")
+	}
+
+	switch {
+	case errors.Is(err, funding.ErrAbortTooLate):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+
+	case errors.Is(err, funding.ErrUnknownChannelOpen):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, err
+	}
+
+	resp := &lnrpc.AbortChannelOpenResponse{
+		AbortedPendingChanIds: make([][]byte, 0, len(aborted)),
+	}
+	for _, pendingChanID := range aborted {
+		pendingChanID := pendingChanID
+		resp.AbortedPendingChanIds = append(
+			resp.AbortedPendingChanIds, pendingChanID[:],
+		)
+	}
+
+	return resp, nil
+}
+
 // RegisterRPCMiddleware adds a new gRPC middleware to the interceptor chain. A
 // gRPC middleware is software component external to lnd that aims to add
 // additional business logic to lnd by observing/intercepting/validating
@@ -1,9 +1,29 @@
 package lnd
 
 import (
+	"context"
+	"encoding/hex"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/peer"
+	"github.com/lightningnetwork/lnd/shachain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetAllPermissions(t *testing.T) {
@@ -12,3 +32,474 @@ func TestGetAllPermissions(t *testing.T) {
 	// Currently there are there are 16 entity:action pairs in use.
 	assert.Equal(t, len(perms), 16)
 }
+
+// TestMarshallContractReport asserts that the resolution state of an output
+// is derived correctly from its contract report.
+func TestMarshallContractReport(t *testing.T) {
+	t.Parallel()
+
+	const currentHeight = 100
+
+	awaitingTimelock := lnrpc.ContractResolutionState_AWAITING_TIMELOCK
+
+	testCases := []struct {
+		name          string
+		outputType    contractcourt.ReportOutputType
+		maturity      uint32
+		limbo         btcutil.Amount
+		expectedType  lnrpc.ResolutionType
+		expectedState lnrpc.ContractResolutionState
+	}{
+		{
+			name:          "htlc awaiting timelock",
+			outputType:    contractcourt.ReportOutputOutgoingHtlc,
+			maturity:      currentHeight + 10,
+			limbo:         1000,
+			expectedType:  lnrpc.ResolutionType_OUTGOING_HTLC,
+			expectedState: awaitingTimelock,
+		},
+		{
+			name:          "mature commitment output",
+			outputType:    contractcourt.ReportOutputUnencumbered,
+			maturity:      currentHeight,
+			limbo:         1000,
+			expectedType:  lnrpc.ResolutionType_COMMIT,
+			expectedState: lnrpc.ContractResolutionState_SWEEPING,
+		},
+		{
+			name:          "swept htlc",
+			outputType:    contractcourt.ReportOutputIncomingHtlc,
+			maturity:      currentHeight - 10,
+			expectedType:  lnrpc.ResolutionType_INCOMING_HTLC,
+			expectedState: lnrpc.ContractResolutionState_RESOLVED,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := &contractcourt.ContractReport{
+				Type:           tc.outputType,
+				MaturityHeight: tc.maturity,
+				LimboBalance:   tc.limbo,
+			}
+			contract, err := marshallContractReport(
+				report, currentHeight,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedType, contract.Type)
+			require.Equal(t, tc.expectedState, contract.State)
+			require.Equal(
+				t, int32(tc.maturity)-currentHeight,
+				contract.BlocksTilMaturity,
+			)
+		})
+	}
+
+	// Unknown output types are rejected.
+	_, err := marshallContractReport(
+		&contractcourt.ContractReport{Type: 99}, currentHeight,
+	)
+	require.Error(t, err)
+}
+
+// TestMarshallCommitmentState asserts that a commitment of the channel
+// database is converted into its RPC representation.
+func TestMarshallCommitmentState(t *testing.T) {
+	t.Parallel()
+
+	commitTx := wire.NewMsgTx(2)
+	rHash := [32]byte{1}
+	commit := &channeldb.ChannelCommitment{
+		CommitHeight:    7,
+		LocalLogIndex:   3,
+		LocalHtlcIndex:  2,
+		RemoteLogIndex:  5,
+		RemoteHtlcIndex: 4,
+		LocalBalance:    lnwire.MilliSatoshi(500_000_000),
+		RemoteBalance:   lnwire.MilliSatoshi(400_000_000),
+		CommitFee:       btcutil.Amount(1_000),
+		FeePerKw:        btcutil.Amount(253),
+		CommitTx:        commitTx,
+		Htlcs: []channeldb.HTLC{{
+			RHash:         rHash,
+			Amt:           lnwire.MilliSatoshi(50_000_000),
+			RefundTimeout: 800_000,
+			HtlcIndex:     1,
+			Incoming:      true,
+		}},
+	}
+
+	state := marshallCommitmentState(commit)
+	require.Equal(t, &lnrpc.CommitmentState{
+		CommitHeight:      7,
+		LocalBalanceMsat:  500_000_000,
+		RemoteBalanceMsat: 400_000_000,
+		CommitFeeSat:      1_000,
+		FeePerKw:          253,
+		CommitTxid:        commitTx.TxHash().String(),
+		LocalLogIndex:     3,
+		LocalHtlcIndex:    2,
+		RemoteLogIndex:    5,
+		RemoteHtlcIndex:   4,
+		Htlcs: []*lnrpc.HTLC{{
+			Incoming:         true,
+			Amount:           50_000,
+			HashLock:         rHash[:],
+			ExpirationHeight: 800_000,
+			HtlcIndex:        1,
+		}},
+	}, state)
+}
+
+// TestCheckMaxSendAmount tests that on-chain sends are capped by the maximum
+// send amount unless the call overrides it and overrides are allowed.
+func TestCheckMaxSendAmount(t *testing.T) {
+	t.Parallel()
+
+	cfg := &lncfg.Wallet{}
+
+	// Without a maximum, any amount can be sent.
+	require.NoError(
+		t, checkMaxSendAmount(cfg, btcutil.SatoshiPerBitcoin, false),
+	)
+
+	cfg.MaxSendAmount = 100_000
+	require.NoError(t, checkMaxSendAmount(cfg, 100_000, false))
+	require.ErrorContains(
+		t, checkMaxSendAmount(cfg, 100_001, false),
+		"exceeds the maximum send amount",
+	)
+	require.NoError(t, checkMaxSendAmount(cfg, 100_001, true))
+
+	// Once overrides are disabled, using the override is rejected even if
+	// the amount is below the maximum.
+	cfg.NoMaxSendAmountOverride = true
+	require.ErrorContains(
+		t, checkMaxSendAmount(cfg, 100_001, true), "disabled",
+	)
+	require.ErrorContains(
+		t, checkMaxSendAmount(cfg, 1, true), "disabled",
+	)
+	require.Error(t, checkMaxSendAmount(cfg, 100_001, false))
+	require.NoError(t, checkMaxSendAmount(cfg, 1, false))
+}
+
+// TestSweepAmount tests that only the outputs paying to the target address
+// count towards the amount of a sweep.
+func TestSweepAmount(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.RegressionNetParams
+	targetAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		make([]byte, 20), params,
+	)
+	require.NoError(t, err)
+	targetScript, err := txscript.PayToAddrScript(targetAddr)
+	require.NoError(t, err)
+
+	changeAddr, err := btcutil.NewAddressTaproot(make([]byte, 32), params)
+	require.NoError(t, err)
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	require.NoError(t, err)
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxOut(wire.NewTxOut(40_000, targetScript))
+	sweepTx.AddTxOut(wire.NewTxOut(10_000, changeScript))
+
+	amt, err := sweepAmount(sweepTx, targetAddr)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(40_000), amt)
+}
+
+// TestOpenRecoveryState asserts that restored channels whose peer can't be
+// reached are only reported as unrecoverable until the peer sent its data, and
+// that reachable peers that didn't send it in time need manual intervention.
+func TestOpenRecoveryState(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_WAITING_FOR_PEER,
+		openRecoveryState(false, true, false),
+	)
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_NEEDS_INTERVENTION,
+		openRecoveryState(false, true, true),
+	)
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_NO_PEER_ADDRESS,
+		openRecoveryState(false, false, false),
+	)
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_NO_PEER_ADDRESS,
+		openRecoveryState(false, false, true),
+	)
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_DLP_RECEIVED,
+		openRecoveryState(true, true, true),
+	)
+	require.Equal(
+		t, lnrpc.ChannelRecoveryState_RECOVERY_DLP_RECEIVED,
+		openRecoveryState(true, false, false),
+	)
+}
+
+// newRestoredChannelShell creates the shell of a channel with the given peer
+// as it's stored when restoring a channel from a static channel backup.
+func newRestoredChannelShell(t *testing.T, index uint32,
+	peerKey *btcec.PublicKey, addrs []net.Addr) *channeldb.ChannelShell {
+
+	t.Helper()
+
+	newKey := func() keychain.KeyDescriptor {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return keychain.KeyDescriptor{PubKey: privKey.PubKey()}
+	}
+	newChanCfg := func() channeldb.ChannelConfig {
+		return channeldb.ChannelConfig{
+			MultiSigKey:         newKey(),
+			RevocationBasePoint: newKey(),
+			PaymentBasePoint:    newKey(),
+			DelayBasePoint:      newKey(),
+			HtlcBasePoint:       newKey(),
+		}
+	}
+
+	return &channeldb.ChannelShell{
+		NodeAddrs: addrs,
+		Chan: &channeldb.OpenChannel{
+			ChanType:    channeldb.SingleFunderTweaklessBit,
+			ChainHash:   *chainreg.BitcoinTestNetParams.GenesisHash,
+			IsInitiator: true,
+			Capacity:    1_000_000,
+			FundingOutpoint: wire.OutPoint{
+				Hash:  chainhash.Hash{byte(index)},
+				Index: index,
+			},
+			ShortChannelID: lnwire.ShortChannelID{
+				BlockHeight: 100,
+				TxIndex:     index,
+			},
+			IdentityPub:             peerKey,
+			LocalChanCfg:            newChanCfg(),
+			RemoteChanCfg:           newChanCfg(),
+			RemoteCurrentRevocation: peerKey,
+			RevocationStore:         shachain.NewRevocationStore(),
+			RevocationProducer: shachain.NewRevocationProducer(
+				chainhash.Hash{byte(index)},
+			),
+		},
+	}
+}
+
+// TestChannelRecoveryStatus asserts that the recovery state of restored
+// channels is derived from the channel DB, both while the channels are open
+// and after their peer force closed them.
+func TestChannelRecoveryStatus(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	peerKeys := make([]*btcec.PublicKey, 7)
+	for i := range peerKeys {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		peerKeys[i] = privKey.PubKey()
+	}
+	addrs := []net.Addr{&net.TCPAddr{IP: net.IP{10, 0, 0, 1}, Port: 9735}}
+
+	const peerTimeout = time.Hour
+
+	var (
+		// The first peer is connected to us, so it doesn't need an
+		// address.
+		online = newRestoredChannelShell(t, 1, peerKeys[0], nil)
+
+		// We know where to reach the second peer.
+		reachable = newRestoredChannelShell(t, 2, peerKeys[1], addrs)
+
+		// There's no address for the third peer at all.
+		noAddress = newRestoredChannelShell(t, 3, peerKeys[2], nil)
+
+		// The fourth peer is reachable, but didn't send its data in
+		// time.
+		timedOut = newRestoredChannelShell(t, 4, peerKeys[3], addrs)
+
+		// The fifth peer can't be reached anymore, but already sent
+		// its data.
+		dlpReceived = newRestoredChannelShell(t, 5, peerKeys[4], nil)
+
+		// The channels with the last two peers were force closed, one
+		// of them is fully resolved already.
+		confirmed = newRestoredChannelShell(t, 6, peerKeys[5], addrs)
+		complete  = newRestoredChannelShell(t, 7, peerKeys[6], addrs)
+	)
+	timedOut.Chan.RestoredAt = time.Unix(time.Now().Unix(), 0).Add(
+		-2 * peerTimeout,
+	)
+
+	chanStateDB := db.ChannelStateDB()
+	require.NoError(t, chanStateDB.RestoreChannelShells(
+		online, reachable, noAddress, timedOut, dlpReceived,
+		confirmed, complete,
+	))
+
+	dlpPoint, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, dlpReceived.Chan.MarkDataLoss(dlpPoint.PubKey()))
+
+	closeChannel := func(shell *channeldb.ChannelShell,
+		closingTxid chainhash.Hash) {
+
+		channel := shell.Chan
+		require.NoError(t, channel.CloseChannel(
+			&channeldb.ChannelCloseSummary{
+				ChanPoint:       channel.FundingOutpoint,
+				ShortChanID:     channel.ShortChannelID,
+				ChainHash:       channel.ChainHash,
+				ClosingTXID:     closingTxid,
+				RemotePub:       channel.IdentityPub,
+				Capacity:        channel.Capacity,
+				CloseType:       channeldb.RemoteForceClose,
+				IsPending:       true,
+				LocalChanConfig: channel.LocalChanCfg,
+			},
+		))
+	}
+	closeChannel(confirmed, chainhash.Hash{0x06})
+	closeChannel(complete, chainhash.Hash{0x07})
+	require.NoError(t, chanStateDB.MarkChanFullyClosed(
+		&complete.Chan.FundingOutpoint,
+	))
+
+	// Only the sweep of our commitment output is reported.
+	sweepTxid := chainhash.Hash{0x08}
+	htlcTxid := chainhash.Hash{0x09}
+	chainHash := *chainreg.BitcoinTestNetParams.GenesisHash
+	for _, report := range []*channeldb.ResolverReport{{
+		OutPoint:        wire.OutPoint{Hash: chainhash.Hash{0x07}},
+		ResolverType:    channeldb.ResolverTypeCommit,
+		ResolverOutcome: channeldb.ResolverOutcomeClaimed,
+		SpendTxID:       &sweepTxid,
+	}, {
+		OutPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{0x07},
+			Index: 1,
+		},
+		ResolverType:    channeldb.ResolverTypeIncomingHtlc,
+		ResolverOutcome: channeldb.ResolverOutcomeClaimed,
+		SpendTxID:       &htlcTxid,
+	}} {
+		require.NoError(t, db.PutResolverReport(
+			nil, chainHash, &complete.Chan.FundingOutpoint, report,
+		))
+	}
+
+	r := &rpcServer{
+		server: &server{
+			chanStateDB: chanStateDB,
+			miscDB:      db,
+			graphDB:     db.ChannelGraph(),
+			peersByPub: map[string]*peer.Brontide{
+				string(peerKeys[0].SerializeCompressed()): {},
+			},
+		},
+		cfg: &Config{
+			ActiveNetParams: chainreg.BitcoinTestNetParams,
+			Recovery: &lncfg.Recovery{
+				PeerTimeout: peerTimeout,
+			},
+		},
+	}
+
+	resp, err := r.ChannelRecoveryStatus(
+		context.Background(), &lnrpc.ChannelRecoveryStatusRequest{},
+	)
+	require.NoError(t, err)
+
+	recoveries := make(map[string]*lnrpc.ChannelRecovery)
+	for _, recovery := range resp.Channels {
+		txid, err := lnrpc.GetChanPointFundingTxid(recovery.ChanPoint)
+		require.NoError(t, err)
+
+		recoveries[txid.String()] = recovery
+	}
+	require.Len(t, recoveries, 7)
+
+	assertRecovery := func(shell *channeldb.ChannelShell,
+		state lnrpc.ChannelRecoveryState, peerOnline bool) {
+
+		t.Helper()
+
+		txid := shell.Chan.FundingOutpoint.Hash.String()
+		recovery, ok := recoveries[txid]
+		require.True(t, ok, "missing channel %v", txid)
+		require.Equal(t, state, recovery.State, txid)
+		require.Equal(t, peerOnline, recovery.PeerOnline, txid)
+		require.Equal(
+			t, hex.EncodeToString(
+				shell.Chan.IdentityPub.SerializeCompressed(),
+			), recovery.RemotePubkey,
+		)
+	}
+
+	assertRecovery(
+		online, lnrpc.ChannelRecoveryState_RECOVERY_WAITING_FOR_PEER,
+		true,
+	)
+	assertRecovery(
+		reachable, lnrpc.ChannelRecoveryState_RECOVERY_WAITING_FOR_PEER,
+		false,
+	)
+	assertRecovery(
+		noAddress, lnrpc.ChannelRecoveryState_RECOVERY_NO_PEER_ADDRESS,
+		false,
+	)
+	assertRecovery(
+		timedOut,
+		lnrpc.ChannelRecoveryState_RECOVERY_NEEDS_INTERVENTION, false,
+	)
+	assertRecovery(
+		dlpReceived, lnrpc.ChannelRecoveryState_RECOVERY_DLP_RECEIVED,
+		false,
+	)
+	assertRecovery(
+		confirmed,
+		lnrpc.ChannelRecoveryState_RECOVERY_CLOSE_CONFIRMED, false,
+	)
+	assertRecovery(
+		complete, lnrpc.ChannelRecoveryState_RECOVERY_COMPLETE, false,
+	)
+
+	// The open channels report when they were restored, the closed ones
+	// their closing and sweep transactions.
+	recovery := func(shell *channeldb.ChannelShell) *lnrpc.ChannelRecovery {
+		return recoveries[shell.Chan.FundingOutpoint.Hash.String()]
+	}
+
+	timedOutRecovery := recovery(timedOut)
+	require.EqualValues(
+		t, timedOut.Chan.RestoredAt.Unix(), timedOutRecovery.RestoredAt,
+	)
+
+	closedRecovery := recovery(confirmed)
+	require.Equal(
+		t, chainhash.Hash{0x06}.String(), closedRecovery.ClosingTxid,
+	)
+	require.Empty(t, closedRecovery.SweepTxids)
+
+	completeRecovery := recovery(complete)
+	require.Equal(
+		t, chainhash.Hash{0x07}.String(), completeRecovery.ClosingTxid,
+	)
+	require.Equal(
+		t, []string{sweepTxid.String()}, completeRecovery.SweepTxids,
+	)
+}
@@ -48,7 +48,7 @@ rm $FILE_TMP
 
 # OPTIONS_NO_CONF is a list of all options without any expected entries in 
 # sample-lnd.conf. There's no validation needed for these options. 
-OPTIONS_NO_CONF="help lnddir configfile version end"
+OPTIONS_NO_CONF="help lnddir configfile version checkconfig end"
 
 
 # OPTIONS_NO_LND_DEFAULT_VALUE_CHECK is a list of options with default values
@@ -56,7 +56,8 @@ OPTIONS_NO_CONF="help lnddir configfile version end"
 # included in sample-lnd.conf but no further checks are performed.
 OPTIONS_NO_LND_DEFAULT_VALUE_CHECK="adminmacaroonpath readonlymacaroonpath \
     invoicemacaroonpath rpclisten restlisten listen backupfilepath maxchansize \
-    bitcoin.chaindir bitcoin.defaultchanconfs bitcoin.defaultremotedelay \
+    preimage-store-path bitcoin.chaindir bitcoin.defaultchanconfs \
+    bitcoin.defaultremotedelay \
     bitcoin.dnsseed signrpc.signermacaroonpath walletrpc.walletkitmacaroonpath \
     chainrpc.notifiermacaroonpath routerrpc.routermacaroonpath" 
 
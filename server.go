@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 	prand "math/rand"
 	"net"
@@ -38,6 +39,7 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/feature"
+	"github.com/lightningnetwork/lnd/feeautopilot"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/healthcheck"
@@ -51,14 +53,18 @@ import (
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/onionmessage"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
@@ -75,6 +81,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -228,6 +235,20 @@ type server struct {
 	// intended to replace it.
 	scheduledPeerConnection map[string]func()
 
+	// peerBans maps the pubkey string of peers that were temporarily
+	// banned to the time their ban expires. While a peer is banned, we
+	// neither accept connections from it nor attempt to reconnect to it.
+	// Bans are kept in memory only and don't survive a restart.
+	peerBans map[string]time.Time
+
+	// peerAddrCache, if set, persists the addresses we successfully
+	// connected to peers over, so they can be tried on reconnect.
+	peerAddrCache *channeldb.PeerAddrCache
+
+	// coopCloseScript, if set, is the script cooperative closes pay our
+	// balance out to instead of a fresh wallet address.
+	coopCloseScript lnwire.DeliveryAddress
+
 	// pongBuf is a shared pong reply buffer we'll use across all active
 	// peer goroutines. We know the max size of a pong message
 	// (lnwire.MaxPongBytes), so we can allocate this ahead of time, and
@@ -254,10 +275,21 @@ type server struct {
 
 	htlcSwitch *htlcswitch.Switch
 
+	tipLagMonitor *chainreg.TipLagMonitor
+
 	interceptableSwitch *htlcswitch.InterceptableSwitch
 
 	invoices *invoices.InvoiceRegistry
 
+	// invoiceFallbackAddr returns the fallback address that is added to
+	// new invoices. It is nil if no fallback address should be added.
+	invoiceFallbackAddr func(hash lntypes.Hash) (btcutil.Address, error)
+
+	// fallbackAddrMgr hands out fresh invoice fallback addresses and
+	// reconciles on-chain payments to them. It is nil unless the fresh
+	// fallback address mode is used.
+	fallbackAddrMgr *invoicesrpc.FallbackAddrManager
+
 	channelNotifier *channelnotifier.ChannelNotifier
 
 	peerNotifier *peernotifier.PeerNotifier
@@ -270,18 +302,36 @@ type server struct {
 
 	missionControl *routing.MissionControl
 
+	// attemptTraceFile is the file the traces of payment attempts are
+	// written to. It is nil if tracing is disabled.
+	attemptTraceFile *routing.AttemptTraceFile
+
 	chanRouter *routing.ChannelRouter
 
+	// prober sends probes to keep mission control up to date. It is nil
+	// if probing is disabled.
+	prober *routing.Prober
+
 	controlTower routing.ControlTower
 
 	authGossiper *discovery.AuthenticatedGossiper
 
 	localChanMgr *localchans.Manager
 
+	// policyUpdateQueue applies queued policy updates of our channels
+	// gradually.
+	policyUpdateQueue *localchans.PolicyUpdateQueue
+
+	// feeAutopilot adjusts the fee rates of our channels. It is nil if the
+	// fee autopilot is disabled.
+	feeAutopilot *feeautopilot.Manager
+
 	utxoNursery *contractcourt.UtxoNursery
 
 	sweeper *sweep.UtxoSweeper
 
+	utxoConsolidator *lnwallet.UtxoConsolidator
+
 	chainArb *contractcourt.ChainArbitrator
 
 	sphinx *hop.OnionProcessor
@@ -327,6 +377,10 @@ type server struct {
 
 	customMessageServer *subscribe.Server
 
+	// onionMsgHandler relays onion messages on behalf of our peers. It is
+	// nil if onion message relay is disabled.
+	onionMsgHandler *onionmessage.Handler
+
 	// txPublisher is a publisher with fee-bumping capability.
 	txPublisher *sweep.TxPublisher
 
@@ -335,6 +389,150 @@ type server struct {
 	wg sync.WaitGroup
 }
 
+// cltvDeltaChecker compares the CLTV deltas we advertise against the ones
+// required by our connected peers on startup and then at every configured
+// interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) cltvDeltaChecker() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Routing.CltvDeltaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.checkCltvDelta(); err != nil {
+			srvrLog.Errorf("Unable to check CLTV delta: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// checkCltvDelta logs a warning if our default CLTV delta or the delta of any
+// of our advertised channel policies is an outlier compared to the deltas our
+// connected peers advertise for their channels. Those are the nodes payments
+// are routed to and from through us, so an outlier either makes our channels
+// unattractive for path finding or exposes us to the risk of not being able to
+// claim incoming HTLCs on chain in time. The check is advisory only.
+func (s *server) checkCltvDelta() error {
+	// nodeDeltas returns the CLTV deltas of the enabled policies the given
+	// node advertises for its channels, keyed by channel ID.
+	nodeDeltas := func(node route.Vertex) (map[uint64]uint16, error) {
+		deltas := make(map[uint64]uint16)
+		err := s.graphDB.ForEachNodeChannel(nil, node, func(
+			_ kvdb.RTx, _ *models.ChannelEdgeInfo,
+			policy, _ *models.ChannelEdgePolicy) error {
+
+			if policy == nil || policy.IsDisabled() {
+				return nil
+			}
+			deltas[policy.ChannelID] = policy.TimeLockDelta
+
+			return nil
+		})
+		if err != nil &&
+			!errors.Is(err, channeldb.ErrGraphNoEdgesFound) {
+
+			return nil, err
+		}
+
+		return deltas, nil
+	}
+
+	selfNode := route.NewVertex(s.identityECDH.PubKey())
+	ourDeltas, err := nodeDeltas(selfNode)
+	if err != nil {
+		return err
+	}
+
+	var peerDeltas []uint16
+	for _, p := range s.Peers() {
+		deltas, err := nodeDeltas(p.PubKey())
+		if err != nil {
+			return err
+		}
+
+		for _, delta := range deltas {
+			peerDeltas = append(peerDeltas, delta)
+		}
+	}
+
+	summary := routing.NewCltvDeltaSummary(peerDeltas)
+	if summary == nil {
+		srvrLog.Debugf("Not enough channel policies (%d) of connected "+
+			"peers known to check CLTV delta", len(peerDeltas))
+
+		return nil
+	}
+
+	srvrLog.Debugf("CLTV deltas of connected peers: %v", summary)
+
+	defaultDelta := uint16(s.cfg.Bitcoin.TimeLockDelta)
+	if summary.IsOutlier(defaultDelta) {
+		srvrLog.Warnf("Configured time lock delta %d is an outlier "+
+			"compared to our peers (%v), this may cause routing "+
+			"failures through our node", defaultDelta, summary)
+	}
+
+	for chanID, delta := range ourDeltas {
+		if !summary.IsOutlier(delta) {
+			continue
+		}
+
+		srvrLog.Warnf("Time lock delta %d of channel %v is an "+
+			"outlier compared to our peers (%v), this may cause "+
+			"routing failures through the channel", delta,
+			lnwire.NewShortChanIDFromInt(chanID), summary)
+	}
+
+	return nil
+}
+
+// paymentPruneInterval is the interval at which old failed payments are pruned
+// from the database if payments-auto-prune is set.
+const paymentPruneInterval = time.Hour
+
+// paymentPruner periodically deletes the failed payments that are older than
+// the configured age, starting right away.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *server) paymentPruner() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(paymentPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		before := time.Now().Add(-s.cfg.PaymentsAutoPrune)
+		numPayments, numAttempts, err := s.miscDB.PruneFailedPayments(
+			before,
+		)
+		switch {
+		case err != nil:
+			srvrLog.Errorf("Unable to prune failed payments, "+
+				"pruned %d with %d HTLC attempts before the "+
+				"error: %v", numPayments, numAttempts, err)
+
+		case numPayments > 0:
+			srvrLog.Infof("Pruned %d failed payments with %d HTLC "+
+				"attempts created more than %v ago",
+				numPayments, numAttempts,
+				s.cfg.PaymentsAutoPrune)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
 // updatePersistentPeerAddrs subscribes to topology changes and stores
 // advertised addresses for any NodeAnnouncements from our persisted peers.
 func (s *server) updatePersistentPeerAddrs() error {
@@ -377,10 +575,20 @@ func (s *server) updatePersistentPeerAddrs() error {
 						continue
 					}
 
+					// The node announcement was just
+					// received, so its addresses take
+					// precedence over the cached ones.
+					updateAddrs := orderPeerAddrs(
+						update.Addresses, time.Now(),
+						s.cachedPeerAddrs(
+							update.IdentityKey,
+						),
+					)
+
 					addrs := make([]*lnwire.NetAddress, 0,
-						len(update.Addresses))
+						len(updateAddrs))
 
-					for _, addr := range update.Addresses {
+					for _, addr := range updateAddrs {
 						addrs = append(addrs,
 							&lnwire.NetAddress{
 								IdentityKey: update.IdentityKey,
@@ -494,13 +702,20 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		)
 	)
 
+	var listenerOpts []brontide.ListenerOption
+	if len(cfg.InboundNets) > 0 {
+		listenerOpts = append(listenerOpts, brontide.WithConnFilter(
+			inboundConnFilter(cfg.InboundNets),
+		))
+	}
+
 	listeners := make([]net.Listener, len(listenAddrs))
 	for i, listenAddr := range listenAddrs {
 		// Note: though brontide.NewListener uses ResolveTCPAddr, it
 		// doesn't need to call the general lndResolveTCP function
 		// since we are resolving a local address.
 		listeners[i], err = brontide.NewListener(
-			nodeKeyECDH, listenAddr.String(),
+			nodeKeyECDH, listenAddr.String(), listenerOpts...,
 		)
 		if err != nil {
 			return nil, err
@@ -550,6 +765,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		CustomFeatures:           cfg.ProtocolOptions.CustomFeatures(),
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
 		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
+		NoOnionMessages:          cfg.OnionMessages.Relay == onionmessage.RelayOff,
 	})
 	if err != nil {
 		return nil, err
@@ -603,6 +819,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		peerErrors:              make(map[string]*queue.CircularBuffer),
 		ignorePeerTermination:   make(map[*peer.Brontide]struct{}),
 		scheduledPeerConnection: make(map[string]func()),
+		peerBans:                make(map[string]time.Time),
 		pongBuf:                 make([]byte, lnwire.MaxPongBytes),
 
 		peersByPub:                make(map[string]*peer.Brontide),
@@ -632,8 +849,76 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		dbs.InvoiceDB, expiryWatcher, &registryConfig,
 	)
 
+	switch cfg.Invoices.FallbackAddrMode {
+	case lncfg.FallbackAddrModeFixed:
+		addr, err := btcutil.DecodeAddress(
+			cfg.Invoices.FallbackAddr, cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.invoiceFallbackAddr = func(lntypes.Hash) (btcutil.Address,
+			error) {
+
+			return addr, nil
+		}
+
+	case lncfg.FallbackAddrModeFresh:
+		newAddress := func() (btcutil.Address, error) {
+			return cc.Wallet.NewAddress(
+				lnwallet.WitnessPubKey, false,
+				lnwallet.DefaultAccountName,
+			)
+		}
+		pendingInvoices := func() ([]invoices.Invoice, error) {
+			return fetchPendingInvoices(dbs.InvoiceDB)
+		}
+		lookupInvoice := func(hash lntypes.Hash) (invoices.Invoice,
+			error) {
+
+			return s.invoices.LookupInvoice(
+				context.Background(), hash,
+			)
+		}
+		cancelInvoice := func(hash lntypes.Hash) error {
+			return s.invoices.CancelInvoice(
+				context.Background(), hash,
+			)
+		}
+
+		//nolint:lll
+		s.fallbackAddrMgr = invoicesrpc.NewFallbackAddrManager(
+			&invoicesrpc.FallbackAddrConfig{
+				ChainParams:           cfg.ActiveNetParams.Params,
+				NewAddress:            newAddress,
+				IsOurAddress:          cc.Wallet.IsOurAddress,
+				PendingInvoices:       pendingInvoices,
+				LookupInvoice:         lookupInvoice,
+				CancelInvoice:         cancelInvoice,
+				SubscribeTransactions: cc.Wallet.SubscribeTransactions,
+				MaxUnused:             cfg.Invoices.FallbackAddrMaxUnused,
+			},
+		)
+		s.invoiceFallbackAddr = s.fallbackAddrMgr.FallbackAddr
+	}
+
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
+	if cfg.OnionMessages.Relay != onionmessage.RelayOff {
+		s.onionMsgHandler = onionmessage.New(&onionmessage.Config{
+			Relay: cfg.OnionMessages.Relay,
+			PeerRateLimit: rate.Limit(
+				cfg.OnionMessages.PeerRateLimit,
+			),
+			PeerBurst:   cfg.OnionMessages.PeerBurst,
+			Router:      sphinxRouter,
+			HasChannel:  s.hasOpenChannel,
+			ChannelPeer: s.channelPeer,
+			SendMessage: s.sendOnionMessage,
+		})
+	}
+
 	thresholdSats := btcutil.Amount(cfg.DustThreshold)
 	thresholdMSats := lnwire.NewMSatFromSatoshis(thresholdSats)
 
@@ -681,6 +966,23 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
+
+	// If enabled, stop forwarding HTLCs while the chain backend lags
+	// behind the network, as we can't safely enforce their timelocks.
+	if chainOpts := cfg.ChainOptions; chainOpts.TipLagMonitorEnabled() {
+		s.tipLagMonitor = chainreg.NewTipLagMonitor(
+			&chainreg.TipLagMonitorConfig{
+				BackendInfo: cc.BackendInfo,
+				MaxBlockLag: chainOpts.MaxTipLag,
+				MaxTimeLag:  chainOpts.MaxTipLagTime,
+				Interval:    chainOpts.TipLagCheckInterval,
+				Clock:       clock.NewDefaultClock(),
+				OnLagging:   s.htlcSwitch.PauseForwards,
+				OnRecovered: s.htlcSwitch.ResumeForwards,
+			},
+		)
+	}
+
 	s.interceptableSwitch, err = htlcswitch.NewInterceptableSwitch(
 		&htlcswitch.InterceptableSwitchConfig{
 			Switch:             s.htlcSwitch,
@@ -694,9 +996,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	wCache, err := newWitnessCache(dbs, cc.KeyRing)
+	if err != nil {
+		return nil, err
+	}
+
 	s.witnessBeacon = newPreimageBeacon(
-		dbs.ChanStateDB.NewWitnessCache(),
-		s.interceptableSwitch.ForwardPacket,
+		wCache, s.interceptableSwitch.ForwardPacket,
 	)
 
 	chanStatusMgrCfg := &netann.ChanStatusConfig{
@@ -798,6 +1104,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	selfAddrs := make([]net.Addr, 0, len(externalIPs))
 	selfAddrs = append(selfAddrs, externalIPs...)
+	selfAddrs = s.filterAnnounceAddrs(selfAddrs)
 
 	// As the graph can be obtained at anytime from the network, we won't
 	// replicate it, and instead it'll only be stored locally.
@@ -824,6 +1131,19 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
+
+	// Any custom TLV records are appended to the node announcement.
+	nodeAnnRecords, err := lncfg.ParseTLVRecords(cfg.NodeAnnTLVRecords)
+	if err != nil {
+		return nil, err
+	}
+	nodeAnnExtraData, err := netann.EncodeNodeAnnExtraRecords(
+		nodeAnnRecords,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	selfNode := &channeldb.LightningNode{
 		HaveNodeAnnouncement: true,
 		LastUpdate:           time.Now(),
@@ -831,6 +1151,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Alias:                nodeAlias.String(),
 		Features:             s.featureMgr.Get(feature.SetNodeAnn),
 		Color:                color,
+		ExtraOpaqueData:      nodeAnnExtraData,
 	}
 	copy(selfNode.PubKeyBytes[:], nodeKeyDesc.PubKey.SerializeCompressed())
 
@@ -894,6 +1215,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 				CapacityFraction:      aCfg.CapacityFraction,
 			}
 
+			// The mission control decay half-life overrides the
+			// decay of the estimator if set.
+			if cfg.Routing.McDecayHalfLife > 0 {
+				aprioriConfig.PenaltyHalfLife =
+					cfg.Routing.McDecayHalfLife
+			}
+
 			estimator, err = routing.NewAprioriEstimator(
 				aprioriConfig,
 			)
@@ -911,6 +1239,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 				BimodalDecayTime: bCfg.DecayTime,
 			}
 
+			if cfg.Routing.McDecayHalfLife > 0 {
+				bimodalConfig.BimodalDecayTime =
+					routing.BimodalDecayTimeFromHalfLife(
+						cfg.Routing.McDecayHalfLife,
+					)
+			}
+
 			estimator, err = routing.NewBimodalEstimator(
 				bimodalConfig,
 			)
@@ -969,6 +1304,19 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	strictPruning := (cfg.Bitcoin.Node == "neutrino" ||
 		cfg.Routing.StrictZombiePruning)
+	// Only trace payment attempts if a trace file is configured, as the
+	// interface must be nil to disable tracing.
+	var attemptTracer routing.AttemptTracer
+	if cfg.Routing.AttemptTraceFile != "" {
+		s.attemptTraceFile, err = routing.NewAttemptTraceFile(
+			cfg.Routing.AttemptTraceFile, s.missionControl,
+		)
+		if err != nil {
+			return nil, err
+		}
+		attemptTracer = s.attemptTraceFile
+	}
+
 	s.chanRouter, err = routing.New(routing.Config{
 		Graph:               chanGraph,
 		Chain:               cc.ChainIO,
@@ -988,11 +1336,58 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Clock:               clock.NewDefaultClock(),
 		StrictZombiePruning: strictPruning,
 		IsAlias:             aliasmgr.IsAlias,
+		AttemptTracer:       attemptTracer,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %w", err)
 	}
 
+	if cfg.Routing.ProbeInterval != 0 {
+		// Only nodes with channels can be probed.
+		type directedChans = map[uint64]*channeldb.DirectedChannel
+		forEachNode := func(cb func(route.Vertex) error) error {
+			return chanGraph.ForEachNodeCached(
+				func(node route.Vertex,
+					chans directedChans) error {
+
+					if len(chans) == 0 {
+						return nil
+					}
+
+					return cb(node)
+				},
+			)
+		}
+
+		finalCltvDelta := uint16(cfg.Bitcoin.TimeLockDelta)
+		s.prober, err = routing.NewProber(&routing.ProberConfig{
+			Interval: cfg.Routing.ProbeInterval,
+			Amount: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.Routing.ProbeAmount),
+			),
+			FeeLimit: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.Routing.ProbeFeeLimit),
+			),
+			CltvLimit: cfg.MaxOutgoingCltvExpiry -
+				uint32(finalCltvDelta),
+			FinalCltvDelta: finalCltvDelta,
+			TargetPolicy:   cfg.Routing.ProbeTargetPolicy,
+			SelfNode:       selfNode.PubKeyBytes,
+			MissionControl: s.missionControl,
+			ForEachNode:    forEachNode,
+			FindRoute:      s.chanRouter.FindRoute,
+			SendToRoute:    s.chanRouter.SendToRoute,
+			DeletePayment: func(hash lntypes.Hash) error {
+				return dbs.ChanStateDB.DeletePayment(
+					hash, false,
+				)
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't create prober: %w", err)
+		}
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(dbs.ChanStateDB)
 	if err != nil {
@@ -1003,6 +1398,53 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	// If enabled, we'll remember the addresses we successfully connected
+	// to peers over, so a changed address doesn't prevent reconnecting
+	// until gossip catches up.
+	if cfg.PeerAddrCacheSize > 0 {
+		s.peerAddrCache, err = channeldb.NewPeerAddrCache(
+			dbs.ChanStateDB, channeldb.PeerAddrCacheConfig{
+				MaxAddrs: cfg.PeerAddrCacheSize,
+				MaxAge:   channeldb.DefaultPeerAddrMaxAge,
+				Clock:    clock.NewDefaultClock(),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Pay all cooperative closes out to a single address if requested,
+	// so closing many channels doesn't fragment the wallet's UTXOs.
+	switch {
+	case cfg.CoopCloseAddr != "":
+		s.coopCloseScript, err = chancloser.ParseUpfrontShutdownAddress(
+			cfg.CoopCloseAddr, cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	// A P2WKH address is used for consolidation, as every peer accepts
+	// it as a shutdown script.
+	case cfg.CoopCloseConsolidate:
+		addr, err := cc.Wallet.NewAddress(
+			lnwallet.WitnessPubKey, false,
+			lnwallet.DefaultAccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.coopCloseScript, err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		srvrLog.Infof("Paying cooperative closes out to "+
+			"consolidation address %v", addr)
+	}
+
 	s.authGossiper = discovery.New(discovery.Config{
 		Router:                s.chanRouter,
 		Notifier:              s.cc.ChainNotifier,
@@ -1031,6 +1473,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MinimumBatchSize:        10,
 		SubBatchDelay:           cfg.Gossip.SubBatchDelay,
 		IgnoreHistoricalFilters: cfg.IgnoreHistoricalGossipFilters,
+		MaxFilterWindow:         cfg.Gossip.MaxFilterWindow,
 		PinnedSyncers:           cfg.Gossip.PinnedSyncers,
 		MaxChannelUpdateBurst:   cfg.Gossip.MaxChannelUpdateBurst,
 		ChannelUpdateInterval:   cfg.Gossip.ChannelUpdateInterval,
@@ -1049,6 +1492,48 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FetchChannel:              s.chanStateDB.FetchChannel,
 	}
 
+	// Queued policy updates are paced by a global rate across all our
+	// channels, so that updating many channels at once doesn't flood the
+	// network with channel updates.
+	policyUpdateInterval := cfg.Gossip.PolicyUpdateInterval
+	s.policyUpdateQueue, err = localchans.NewPolicyUpdateQueue(
+		&localchans.QueueConfig{
+			BatchSize:    cfg.Gossip.PolicyUpdateBatchSize,
+			Interval:     policyUpdateInterval,
+			Ticker:       ticker.New(policyUpdateInterval),
+			Clock:        clock.NewDefaultClock(),
+			UpdatePolicy: s.localChanMgr.UpdatePolicy,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't create policy update queue: %w",
+			err)
+	}
+
+	if cfg.FeeAutopilot.Active {
+		apCfg := cfg.FeeAutopilot
+		router, chanDB := s.chanRouter, s.chanStateDB
+		fwdLog := dbs.ChanStateDB.ForwardingLog()
+
+		autopilotCfg := &feeautopilot.Config{
+			MinUpdateInterval:      apCfg.MinUpdateInterval,
+			MinFeeRate:             apCfg.MinFeeRate,
+			MaxFeeRate:             apCfg.MaxFeeRate,
+			Step:                   apCfg.Step,
+			Ticker:                 ticker.New(apCfg.Interval),
+			Clock:                  clock.NewDefaultClock(),
+			ForAllOutgoingChannels: router.ForAllOutgoingChannels,
+			FetchAllOpenChannels:   chanDB.FetchAllOpenChannels,
+			QueryForwardingLog:     fwdLog.Query,
+			QueuePolicyUpdates:     s.policyUpdateQueue.Enqueue,
+		}
+		s.feeAutopilot, err = feeautopilot.NewManager(autopilotCfg)
+		if err != nil {
+			return nil, fmt.Errorf("can't create fee autopilot: %w",
+				err)
+		}
+	}
+
 	utxnStore, err := contractcourt.NewNurseryStore(
 		s.cfg.ActiveNetParams.GenesisHash, dbs.ChanStateDB,
 	)
@@ -1067,11 +1552,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 	aggregator := sweep.NewBudgetAggregator(
 		cc.FeeEstimator, sweep.DefaultMaxInputsPerTx,
+		cfg.Sweeper.CrossChannelBatching,
+	)
+
+	// The safe reconnect guard halts all broadcasts of the contract
+	// resolution and the sweeper once we learn that our channel state is
+	// outdated. Justice transactions of the breach arbitrator are never
+	// halted, as they only spend from revoked states of the peer.
+	safeReconnect, err := contractcourt.NewSafeReconnectGuard(
+		cfg.DLP.SafeReconnectMode, dbs.ChanStateDB,
 	)
+	if err != nil {
+		return nil, err
+	}
+	publishTx := safeReconnect.PublishTx(cc.Wallet.PublishTransaction)
 
 	s.txPublisher = sweep.NewTxPublisher(sweep.TxPublisherConfig{
 		Signer:    cc.Wallet.Cfg.Signer,
-		Wallet:    cc.Wallet,
+		Wallet:    newSweeperWallet(cc.Wallet, publishTx),
 		Estimator: cc.FeeEstimator,
 		Notifier:  cc.ChainNotifier,
 	})
@@ -1080,7 +1578,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FeeEstimator:         cc.FeeEstimator,
 		GenSweepScript:       newSweepPkScriptGen(cc.Wallet),
 		Signer:               cc.Wallet.Cfg.Signer,
-		Wallet:               newSweeperWallet(cc.Wallet),
+		Wallet:               newSweeperWallet(cc.Wallet, publishTx),
 		Mempool:              cc.MempoolNotifier,
 		Notifier:             cc.ChainNotifier,
 		Store:                sweeperStore,
@@ -1091,13 +1589,34 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoDeadlineConfTarget: cfg.Sweeper.NoDeadlineConfTarget,
 	})
 
+	// If enabled, consolidate small wallet UTXOs during low fee windows.
+	if cfg.Wallet.AutoConsolidateBelowFeeRate != 0 {
+		maxFeeRate := chainfee.SatPerKVByte(
+			cfg.Wallet.AutoConsolidateBelowFeeRate * 1000,
+		).FeePerKWeight()
+		maxUtxoValue := btcutil.Amount(
+			cfg.Wallet.AutoConsolidateMaxUtxoValue,
+		)
+
+		consolidatorCfg := &lnwallet.UtxoConsolidatorConfig{
+			Wallet:            cc.Wallet,
+			MaxFeeRate:        maxFeeRate,
+			MaxUtxoValue:      maxUtxoValue,
+			Interval:          lnwallet.DefaultConsolidateInterval,
+			IsSweeperOutpoint: s.sweeper.IsSweeperOutpoint,
+		}
+		s.utxoConsolidator = lnwallet.NewUtxoConsolidator(
+			consolidatorCfg,
+		)
+	}
+
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
 		ChainIO:             cc.ChainIO,
 		ConfDepth:           1,
 		FetchClosedChannels: s.chanStateDB.FetchClosedChannels,
 		FetchClosedChannel:  s.chanStateDB.FetchClosedChannel,
 		Notifier:            cc.ChainNotifier,
-		PublishTransaction:  cc.Wallet.PublishTransaction,
+		PublishTransaction:  publishTx,
 		Store:               utxnStore,
 		SweepInput:          s.sweeper.SweepInput,
 		Budget:              s.cfg.Sweeper.Budget,
@@ -1141,7 +1660,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		IncomingBroadcastDelta: lncfg.DefaultIncomingBroadcastDelta,
 		OutgoingBroadcastDelta: lncfg.DefaultOutgoingBroadcastDelta,
 		NewSweepAddr:           newSweepPkScriptGen(cc.Wallet),
-		PublishTx:              cc.Wallet.PublishTransaction,
+		PublishTx:              publishTx,
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
 			for _, msg := range msgs {
 				err := s.htlcSwitch.ProcessContractResolution(msg)
@@ -1246,6 +1765,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return &pc.Incoming
 		},
+		CoopCloseFinalConfs:      s.cfg.CoopCloseFinalConfs,
+		SafeReconnect:            safeReconnect,
+		MaxConcurrentForceCloses: s.cfg.MaxConcurrentForceCloses,
 	}, dbs.ChanStateDB)
 
 	// Select the configuration and funding parameters for Bitcoin.
@@ -1320,6 +1842,27 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			devCfg, reservationTimeout, zombieSweeperInterval)
 	}
 
+	// With Tor active we can reach peers that are only reachable over Tor
+	// ourselves, so there's no reason to reject their channels.
+	if cfg.Funding.RejectTorOnlyPeers && cfg.Tor.Active {
+		srvrLog.Infof("Ignoring funding.reject-tor-only-peers, as " +
+			"tor.active is set")
+	}
+
+	maxPendingOverrides := make(
+		map[[33]byte]int, len(cfg.MaxPendingChannelsOverrides),
+	)
+	for peer, maxPending := range cfg.MaxPendingChannelsOverrides {
+		maxPendingOverrides[peer] = maxPending
+	}
+
+	minChanSizeOverrides := make(
+		map[[33]byte]btcutil.Amount, len(cfg.MinChanSizeOverrides),
+	)
+	for peer, minChanSize := range cfg.MinChanSizeOverrides {
+		minChanSizeOverrides[peer] = minChanSize
+	}
+
 	//nolint:lll
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		Dev:                devCfg,
@@ -1433,7 +1976,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			// channels with this peer is zero.
 			s.mu.Lock()
 			pubStr := string(peerKey.SerializeCompressed())
-			if _, ok := s.persistentPeers[pubStr]; !ok {
+			_, ok := s.persistentPeers[pubStr]
+			if !ok && !s.reconnectDenied(pubStr) {
 				s.persistentPeers[pubStr] = false
 			}
 			s.mu.Unlock()
@@ -1480,8 +2024,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ZombieSweeperInterval:         zombieSweeperInterval,
 		ReservationTimeout:            reservationTimeout,
 		MinChanSize:                   btcutil.Amount(cfg.MinChanSize),
+		MinChanSizeOverrides:          minChanSizeOverrides,
+		MinChanSizeNetOverrides:       cfg.MinChanSizeNetOverrides,
 		MaxChanSize:                   btcutil.Amount(cfg.MaxChanSize),
 		MaxPendingChannels:            cfg.MaxPendingChannels,
+		MaxPendingChannelsOverrides:   maxPendingOverrides,
+		MaxPendingChannelsGlobal:      cfg.MaxPendingChannelsGlobal,
+		MaxChannelsPerPeer:            cfg.MaxChannelsPerPeer,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
@@ -1490,9 +2039,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		EnableUpfrontShutdown:         cfg.EnableUpfrontShutdown,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
-		DeleteAliasEdge:   deleteAliasEdge,
-		AliasManager:      s.aliasMgr,
-		IsSweeperOutpoint: s.sweeper.IsSweeperOutpoint,
+		DeleteAliasEdge:    deleteAliasEdge,
+		AliasManager:       s.aliasMgr,
+		IsSweeperOutpoint:  s.sweeper.IsSweeperOutpoint,
+		MinInputConfs:      cfg.Funding.MinInputConfs,
+		MinFundingConfs:    cfg.Funding.MinFundingConfs,
+		RejectTorOnlyPeers: cfg.Funding.RejectTorOnlyPeers,
+		TorActive:          cfg.Tor.Active,
+		IsTorOnlyPeer:      s.isTorOnlyPeer,
 	})
 	if err != nil {
 		return nil, err
@@ -1513,6 +2067,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	}
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
 		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		cfg.Backup.Debounce, cfg.Backup.DebounceMaxWait,
 	)
 	if err != nil {
 		return nil, err
@@ -1701,6 +2256,7 @@ func (s *server) signAliasUpdate(u *lnwire.ChannelUpdate) (*ecdsa.Signature,
 //   - diskCheck
 //   - tlsHealthCheck
 //   - torController, only created when tor is enabled.
+//   - feeEstimatorCheck, only created when an external fee URL is used.
 //
 // If a health check has been disabled by setting attempts to 0, our monitor
 // will not run it.
@@ -1820,6 +2376,47 @@ func (s *server) createLivenessMonitor(cfg *Config, cc *chainreg.ChainControl) {
 		checks = append(checks, remoteSignerConnectionCheck)
 	}
 
+	// If we fetch our fee estimates from an external fee URL, add the
+	// healthcheck that makes sure they don't go stale. The web estimator
+	// may be wrapped to enforce a max fee rate and a minimum relay fee
+	// rate.
+	feeEstimator := cc.FeeEstimator
+	if maxEstimator, ok := feeEstimator.(*chainfee.CeilingEstimator); ok {
+		feeEstimator = maxEstimator.Estimator
+	}
+	if floorEstimator, ok := feeEstimator.(*chainfee.FloorEstimator); ok {
+		feeEstimator = floorEstimator.Estimator
+	}
+	webEstimator, ok := feeEstimator.(*chainfee.WebAPIEstimator)
+	if ok {
+		maxStaleness := cfg.HealthChecks.FeeEstimator.MaxStaleness
+		feeEstimatorCheck := healthcheck.NewObservation(
+			"fee estimator",
+			func() error {
+				return webEstimator.CheckStaleness(maxStaleness)
+			},
+			cfg.HealthChecks.FeeEstimator.Interval,
+			cfg.HealthChecks.FeeEstimator.Timeout,
+			cfg.HealthChecks.FeeEstimator.Backoff,
+			cfg.HealthChecks.FeeEstimator.Attempts,
+		)
+		checks = append(checks, feeEstimatorCheck)
+	}
+
+	// If we monitor our chain backend for lagging behind the network, add
+	// a healthcheck that alarms while it does.
+	if s.tipLagMonitor != nil {
+		tipLagCheck := healthcheck.NewObservation(
+			"chain tip lag",
+			s.tipLagMonitor.CheckLag,
+			cfg.HealthChecks.ChainTipLag.Interval,
+			cfg.HealthChecks.ChainTipLag.Timeout,
+			cfg.HealthChecks.ChainTipLag.Backoff,
+			cfg.HealthChecks.ChainTipLag.Attempts,
+		)
+		checks = append(checks, tipLagCheck)
+	}
+
 	// If we have not disabled all of our health checks, we create a
 	// liveness monitor with our configured checks.
 	s.livenessMonitor = healthcheck.NewMonitor(
@@ -1968,6 +2565,14 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.sweeper.Stop)
 
+		if s.utxoConsolidator != nil {
+			if err := s.utxoConsolidator.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.utxoConsolidator.Stop)
+		}
+
 		if err := s.utxoNursery.Start(); err != nil {
 			startErr = err
 			return
@@ -1995,6 +2600,14 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcSwitch.Stop)
 
+		if s.tipLagMonitor != nil {
+			if err := s.tipLagMonitor.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.tipLagMonitor.Stop)
+		}
+
 		if err := s.interceptableSwitch.Start(); err != nil {
 			startErr = err
 			return
@@ -2019,12 +2632,42 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.chanRouter.Stop)
 
+		if s.prober != nil {
+			if err := s.prober.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.prober.Stop)
+		}
+
+		if err := s.policyUpdateQueue.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.policyUpdateQueue.Stop)
+
+		if s.feeAutopilot != nil {
+			if err := s.feeAutopilot.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.feeAutopilot.Stop)
+		}
+
 		if err := s.invoices.Start(); err != nil {
 			startErr = err
 			return
 		}
 		cleanup = cleanup.add(s.invoices.Stop)
 
+		if s.fallbackAddrMgr != nil {
+			if err := s.fallbackAddrMgr.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.fallbackAddrMgr.Stop)
+		}
+
 		if err := s.sphinx.Start(); err != nil {
 			startErr = err
 			return
@@ -2056,32 +2699,17 @@ func (s *server) Start() error {
 		// any backups to recover. We do this now as we want to ensure
 		// that have all the information we need to handle channel
 		// recovery _before_ we even accept connections from any peers.
+		// The peers of the restored channels are only connected to
+		// once the connMgr is started.
 		chanRestorer := &chanDBRestorer{
 			db:         s.chanStateDB,
 			secretKeys: s.cc.KeyRing,
 			chainArb:   s.chainArb,
 		}
-		if len(s.chansToRestore.PackedSingleChanBackups) != 0 {
-			err := chanbackup.UnpackAndRecoverSingles(
-				s.chansToRestore.PackedSingleChanBackups,
-				s.cc.KeyRing, chanRestorer, s,
-			)
-			if err != nil {
-				startErr = fmt.Errorf("unable to unpack single "+
-					"backups: %v", err)
-				return
-			}
-		}
-		if len(s.chansToRestore.PackedMultiChanBackup) != 0 {
-			err := chanbackup.UnpackAndRecoverMulti(
-				s.chansToRestore.PackedMultiChanBackup,
-				s.cc.KeyRing, chanRestorer, s,
-			)
-			if err != nil {
-				startErr = fmt.Errorf("unable to unpack chan "+
-					"backup: %v", err)
-				return
-			}
+		restoredChans, err := s.restoreChansFromBackups(chanRestorer)
+		if err != nil {
+			startErr = err
+			return
 		}
 
 		if err := s.chanSubSwapper.Start(); err != nil {
@@ -2103,6 +2731,21 @@ func (s *server) Start() error {
 			go s.watchExternalIP()
 		}
 
+		if s.cfg.Routing.CltvDeltaCheckInterval > 0 {
+			s.wg.Add(1)
+			go s.cltvDeltaChecker()
+		}
+
+		if s.cfg.Recovery.PeerTimeout != 0 {
+			s.wg.Add(1)
+			go s.restoredChanChecker()
+		}
+
+		if s.cfg.PaymentsAutoPrune != 0 {
+			s.wg.Add(1)
+			go s.paymentPruner()
+		}
+
 		// Start connmgr last to prevent connections before init.
 		s.connMgr.Start()
 		cleanup = cleanup.add(func() error {
@@ -2110,6 +2753,23 @@ func (s *server) Start() error {
 			return nil
 		})
 
+		// Now that we can connect to the peers of the restored
+		// channels, we'll start the data loss recovery protocol with
+		// them. Their funding outputs are checked at the same time,
+		// as this may take a while with light clients.
+		s.recoverRestoredChannels(restoredChans)
+
+		if s.cfg.VerifyRestoredChannels && len(restoredChans) != 0 {
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+
+				s.verifyRestoredFundingOutputs(
+					restoredChans, s.quit,
+				)
+			}()
+		}
+
 		// If peers are specified as a config option, we'll add those
 		// peers first.
 		for _, peerAddrCfg := range s.cfg.AddPeers {
@@ -2164,6 +2824,12 @@ func (s *server) Start() error {
 			startErr = err
 			return
 		}
+		if s.peerAddrCache != nil {
+			if err := s.peerAddrCache.Prune(); err != nil {
+				startErr = err
+				return
+			}
+		}
 		if err := s.establishPersistentConnections(); err != nil {
 			startErr = err
 			return
@@ -2264,18 +2930,54 @@ func (s *server) Stop() error {
 		if err := s.chanStatusMgr.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanStatusMgr: %v", err)
 		}
+		if s.tipLagMonitor != nil {
+			if err := s.tipLagMonitor.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop chain tip lag "+
+					"monitor: %v", err)
+			}
+		}
 		if err := s.htlcSwitch.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcSwitch: %v", err)
 		}
 		if err := s.sphinx.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sphinx: %v", err)
 		}
+		if s.fallbackAddrMgr != nil {
+			if err := s.fallbackAddrMgr.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"fallbackAddrMgr: %v", err)
+			}
+		}
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
 		}
+		if err := s.policyUpdateQueue.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop policy update queue: "+
+				"%v", err)
+		}
+		if s.feeAutopilot != nil {
+			if err := s.feeAutopilot.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop fee autopilot: "+
+					"%v", err)
+			}
+		}
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+
+		// The prober is stopped after the router, which aborts the
+		// probe in flight the prober waits for.
+		if s.prober != nil {
+			if err := s.prober.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop prober: %v", err)
+			}
+		}
+		if s.attemptTraceFile != nil {
+			if err := s.attemptTraceFile.Close(); err != nil {
+				srvrLog.Warnf("failed to close attempt trace "+
+					"file: %v", err)
+			}
+		}
 		if err := s.chainArb.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chainArb: %v", err)
 		}
@@ -2292,6 +2994,12 @@ func (s *server) Stop() error {
 		if err := s.authGossiper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop authGossiper: %v", err)
 		}
+		if s.utxoConsolidator != nil {
+			if err := s.utxoConsolidator.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop UTXO "+
+					"consolidator: %v", err)
+			}
+		}
 		if err := s.sweeper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sweeper: %v", err)
 		}
@@ -2938,8 +3646,9 @@ func (s *server) createNewHiddenService() error {
 		Features: lnwire.NewFeatureVector(
 			newNodeAnn.Features, lnwire.Features,
 		),
-		Color:        newNodeAnn.RGBColor,
-		AuthSigBytes: newNodeAnn.Signature.ToSignatureBytes(),
+		Color:           newNodeAnn.RGBColor,
+		AuthSigBytes:    newNodeAnn.Signature.ToSignatureBytes(),
+		ExtraOpaqueData: newNodeAnn.ExtraOpaqueData,
 	}
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
 	if err := s.graphDB.SetSourceNode(selfNode); err != nil {
@@ -3014,6 +3723,12 @@ func (s *server) genNodeAnnouncement(features *lnwire.RawFeatureVector,
 		modifier(s.currentNodeAnn)
 	}
 
+	// Make sure none of the changes sneaked in an address we must not
+	// advertise.
+	s.currentNodeAnn.Addresses = s.filterAnnounceAddrs(
+		s.currentNodeAnn.Addresses,
+	)
+
 	// Sign a new update after applying all of the passed modifiers.
 	err := netann.SignNodeAnnouncement(
 		s.nodeSigner, s.identityKeyLoc, s.currentNodeAnn,
@@ -3025,6 +3740,18 @@ func (s *server) genNodeAnnouncement(features *lnwire.RawFeatureVector,
 	return *s.currentNodeAnn, nil
 }
 
+// filterAnnounceAddrs removes all addresses whose type isn't among the address
+// types we're configured to advertise in our node announcement.
+func (s *server) filterAnnounceAddrs(addrs []net.Addr) []net.Addr {
+	kept, dropped := netann.FilterAddrs(addrs, s.cfg.AnnounceTypes)
+	for _, addr := range dropped {
+		srvrLog.Warnf("Not advertising address %v of type %v in node "+
+			"announcement", addr, netann.AddrTypeOf(addr))
+	}
+
+	return kept
+}
+
 // updateAndBrodcastSelfNode generates a new node announcement
 // applying the giving modifiers and updating the time stamp
 // to ensure it propagates through the network. Then it brodcasts
@@ -3052,6 +3779,7 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	selfNode.Alias = newNodeAnn.Alias.String()
 	selfNode.Features = s.featureMgr.Get(feature.SetNodeAnn)
 	selfNode.Color = newNodeAnn.RGBColor
+	selfNode.ExtraOpaqueData = newNodeAnn.ExtraOpaqueData
 	selfNode.AuthSigBytes = newNodeAnn.Signature.ToSignatureBytes()
 
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
@@ -3074,6 +3802,10 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 type nodeAddresses struct {
 	pubKey    *btcec.PublicKey
 	addresses []net.Addr
+
+	// lastUpdate is the time of the node announcement the addresses were
+	// learned from, if any.
+	lastUpdate time.Time
 }
 
 // establishPersistentConnections attempts to establish persistent connections
@@ -3183,7 +3915,8 @@ func (s *server) establishPersistentConnections() error {
 		}
 
 		n := &nodeAddresses{
-			addresses: addrs,
+			addresses:  addrs,
+			lastUpdate: channelPeer.LastUpdate,
 		}
 		n.pubKey, err = channelPeer.PubKey()
 		if err != nil {
@@ -3197,6 +3930,15 @@ func (s *server) establishPersistentConnections() error {
 		return err
 	}
 
+	// Add the addresses we previously connected to each peer over, so
+	// we can reconnect even if their gossip is outdated.
+	for _, nodeAddr := range nodeAddrsMap {
+		nodeAddr.addresses = orderPeerAddrs(
+			nodeAddr.addresses, nodeAddr.lastUpdate,
+			s.cachedPeerAddrs(nodeAddr.pubKey),
+		)
+	}
+
 	srvrLog.Debugf("Establishing %v persistent connections on start",
 		len(nodeAddrsMap))
 
@@ -3205,10 +3947,29 @@ func (s *server) establishPersistentConnections() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Peers on the allowlist are reconnected to first, while peers on the
+	// denylist are never reconnected to automatically.
+	var prioritizedPeers, otherPeers []string
+	for pubStr := range nodeAddrsMap {
+		switch {
+		case s.reconnectDenied(pubStr):
+			srvrLog.Infof("Not reconnecting to peer %x on the "+
+				"peer denylist", pubStr)
+
+		case s.reconnectPrioritized(pubStr):
+			prioritizedPeers = append(prioritizedPeers, pubStr)
+
+		default:
+			otherPeers = append(otherPeers, pubStr)
+		}
+	}
+
 	// Iterate through the combined list of addresses from prior links and
 	// node announcements and attempt to reconnect to each node.
 	var numOutboundConns int
-	for pubStr, nodeAddr := range nodeAddrsMap {
+	for _, pubStr := range append(prioritizedPeers, otherPeers...) {
+		nodeAddr := nodeAddrsMap[pubStr]
+
 		// Add this peer to the set of peers we should maintain a
 		// persistent connection with. We set the value to false to
 		// indicate that we should not continue to reconnect if the
@@ -3238,9 +3999,11 @@ func (s *server) establishPersistentConnections() error {
 		// that mobile nodes or nodes with a small number of
 		// channels obtain connectivity quickly, but larger
 		// nodes are able to disperse the costs of connecting to
-		// all peers at once.
+		// all peers at once. Prioritized peers are never
+		// staggered.
 		if numOutboundConns < numInstantInitReconnect ||
-			!s.cfg.StaggerInitialReconnect {
+			!s.cfg.StaggerInitialReconnect ||
+			s.reconnectPrioritized(pubStr) {
 
 			go s.connectToPersistentPeer(pubStr)
 		} else {
@@ -3253,6 +4016,94 @@ func (s *server) establishPersistentConnections() error {
 	return nil
 }
 
+// reconnectDenied returns true if the peer with the given serialized public
+// key is on the peer denylist, meaning we must never automatically reconnect
+// to it.
+func (s *server) reconnectDenied(pubStr string) bool {
+	var peer route.Vertex
+	copy(peer[:], pubStr)
+
+	_, ok := s.cfg.PeerDenylist[peer]
+
+	return ok
+}
+
+// reconnectPrioritized returns true if the peer with the given serialized
+// public key is on the peer allowlist, meaning reconnecting to it is always
+// prioritized.
+func (s *server) reconnectPrioritized(pubStr string) bool {
+	var peer route.Vertex
+	copy(peer[:], pubStr)
+
+	_, ok := s.cfg.PeerAllowlist[peer]
+
+	return ok
+}
+
+// inboundConnFilter returns a connection filter for the brontide listener that
+// only accepts inbound connections from the given networks.
+func inboundConnFilter(allowedNets []*net.IPNet) func(net.Addr) bool {
+	return func(remoteAddr net.Addr) bool {
+		if tcpAddr, ok := remoteAddr.(*net.TCPAddr); ok {
+			for _, allowedNet := range allowedNets {
+				if allowedNet.Contains(tcpAddr.IP) {
+					return true
+				}
+			}
+		}
+
+		srvrLog.Debugf("Rejecting inbound connection from %v, not "+
+			"part of the allowed networks", remoteAddr)
+
+		return false
+	}
+}
+
+// banRemaining returns the time left until the ban of the peer with the given
+// serialized public key expires, or zero if the peer isn't banned. Expired
+// bans are removed.
+//
+// NOTE: The server's mutex MUST be held when calling this method.
+func (s *server) banRemaining(pubStr string) time.Duration {
+	expiry, ok := s.peerBans[pubStr]
+	if !ok {
+		return 0
+	}
+
+	remaining := time.Until(expiry)
+	if remaining <= 0 {
+		delete(s.peerBans, pubStr)
+		return 0
+	}
+
+	return remaining
+}
+
+// delayPersistentReconnect attempts to reconnect to the persistent peer with
+// the given serialized public key once the delay passed, unless the retry is
+// canceled in the meantime.
+//
+// NOTE: The server's mutex MUST be held when calling this method.
+func (s *server) delayPersistentReconnect(pubStr string, delay time.Duration) {
+	cancelChan, ok := s.persistentRetryCancels[pubStr]
+	if !ok {
+		cancelChan = make(chan struct{})
+		s.persistentRetryCancels[pubStr] = cancelChan
+	}
+
+	go func() {
+		select {
+		case <-time.After(delay):
+		case <-cancelChan:
+			return
+		case <-s.quit:
+			return
+		}
+
+		s.connectToPersistentPeer(pubStr)
+	}()
+}
+
 // delayInitialReconnect will attempt a reconnection to the given peer after
 // sampling a value for the delay between 0s and the maxInitReconnectDelay.
 //
@@ -3464,6 +4315,11 @@ func (s *server) findPeerByPubStr(pubStr string) (*peer.Brontide, error) {
 func (s *server) nextPeerBackoff(pubStr string,
 	startTime time.Time) time.Duration {
 
+	// Prioritized peers are always retried as soon as possible.
+	if s.reconnectPrioritized(pubStr) {
+		return s.cfg.MinBackoff
+	}
+
 	// Now, determine the appropriate backoff to use for the retry.
 	backoff, ok := s.persistentPeersBackoff[pubStr]
 	if !ok {
@@ -3536,6 +4392,16 @@ func (s *server) InboundPeerConnected(conn net.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// If the peer is temporarily banned, we'll refuse the connection.
+	if s.banRemaining(pubStr) > 0 {
+		srvrLog.Debugf("Rejecting inbound connection from banned "+
+			"peer %x, remote=%v", nodePub.SerializeCompressed(),
+			conn.RemoteAddr())
+
+		conn.Close()
+		return
+	}
+
 	// If we already have an outbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.outboundPeers[pubStr]; ok {
@@ -3623,6 +4489,19 @@ func (s *server) OutboundPeerConnected(connReq *connmgr.ConnReq, conn net.Conn)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// If the peer is temporarily banned, we'll drop the connection.
+	if s.banRemaining(pubStr) > 0 {
+		srvrLog.Debugf("Dropping outbound connection to banned peer "+
+			"%x, remote=%v", nodePub.SerializeCompressed(),
+			conn.RemoteAddr())
+
+		if connReq != nil {
+			s.connMgr.Remove(connReq.ID())
+		}
+		conn.Close()
+		return
+	}
+
 	// If we already have an inbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.inboundPeers[pubStr]; ok {
@@ -3783,6 +4662,92 @@ func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
 	})
 }
 
+// fetchPendingInvoices returns all invoices of the given database that are not
+// yet settled or canceled.
+func fetchPendingInvoices(
+	invoiceDB invoices.InvoiceDB) ([]invoices.Invoice, error) {
+
+	resp, err := invoiceDB.QueryInvoices(
+		context.Background(), invoices.InvoiceQuery{
+			PendingOnly:    true,
+			NumMaxInvoices: math.MaxUint64,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Invoices, nil
+}
+
+// handleOnionMessage hands an incoming onion message to the onion message
+// handler, dropping it if relay is disabled.
+func (s *server) handleOnionMessage(peer [33]byte,
+	msg *lnwire.OnionMessage) error {
+
+	if s.onionMsgHandler == nil {
+		return onionmessage.ErrRelayDisabled
+	}
+
+	return s.onionMsgHandler.HandleMessage(route.Vertex(peer), msg)
+}
+
+// hasOpenChannel returns true if we have at least one open channel with the
+// given node.
+func (s *server) hasOpenChannel(node route.Vertex) bool {
+	pubKey, err := btcec.ParsePubKey(node[:])
+	if err != nil {
+		return false
+	}
+
+	channels, err := s.chanStateDB.FetchOpenChannels(pubKey)
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch channels with %v: %v", node,
+			err)
+
+		return false
+	}
+
+	return len(channels) > 0
+}
+
+// channelPeer returns the node on the other end of the given channel from our
+// point of view.
+func (s *server) channelPeer(scid lnwire.ShortChannelID) (route.Vertex,
+	error) {
+
+	info, _, _, err := s.graphDB.FetchChannelEdgesByID(scid.ToUint64())
+	if err != nil {
+		return route.Vertex{}, err
+	}
+
+	ourKey := route.NewVertex(s.identityECDH.PubKey())
+	switch {
+	case info.NodeKey1Bytes == ourKey:
+		return info.NodeKey2Bytes, nil
+
+	case info.NodeKey2Bytes == ourKey:
+		return info.NodeKey1Bytes, nil
+	}
+
+	return route.Vertex{}, fmt.Errorf("channel %v is not ours", scid)
+}
+
+// sendOnionMessage sends an onion message to the given peer if we are
+// connected to it.
+func (s *server) sendOnionMessage(node route.Vertex,
+	msg *lnwire.OnionMessage) error {
+
+	s.mu.RLock()
+	targetPeer, err := s.findPeerByPubStr(string(node[:]))
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return targetPeer.SendMessageLazy(false, msg)
+}
+
 // SubscribeCustomMessages subscribes to a stream of incoming custom peer
 // messages.
 func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
@@ -3809,6 +4774,18 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		ChainNet:    s.cfg.ActiveNetParams.Net,
 	}
 
+	// For outbound connections, we'll remember the address we reached the
+	// peer at, so we can try it again on reconnect. The remote address of
+	// an inbound connection can't be dialed, so it's not recorded.
+	if !inbound && s.peerAddrCache != nil {
+		err := s.peerAddrCache.AddPeerAddr(pubKey, addr)
+		if err != nil {
+			srvrLog.Errorf("Unable to cache address %v of peer "+
+				"%x: %v", addr, pubKey.SerializeCompressed(),
+				err)
+		}
+	}
+
 	// With the brontide connection established, we'll now craft the feature
 	// vectors to advertise to the remote node.
 	initFeatures := s.featureMgr.Get(feature.SetInit)
@@ -3838,6 +4815,10 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		towerClient = s.towerClientMgr
 	}
 
+	// With the manual DLP policy, channels of peers that lost state aren't
+	// force closed automatically.
+	manualDataLossRecovery := s.cfg.DLP.Policy == lncfg.DLPPolicyManual
+
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
 	// and outgoing broadcast deltas to prevent htlcs from being accepted or
@@ -3851,7 +4832,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		Inbound:                 inbound,
 		Features:                initFeatures,
 		LegacyFeatures:          legacyFeatures,
-		OutgoingCltvRejectDelta: lncfg.DefaultOutgoingCltvRejectDelta,
+		OutgoingCltvRejectDelta: s.cfg.OutgoingCltvRejectDelta,
 		ChanActiveTimeout:       s.cfg.ChanEnableTimeout,
 		ErrorBuffer:             errBuffer,
 		WritePool:               s.writePool,
@@ -3892,21 +4873,26 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 
 		FundingManager: s.fundingMgr,
 
+		MaxOutgoingCltvExpiryPolicy: s.cfg.MaxOutgoingCltvExpiryPolicy,
+
 		Hodl:                    s.cfg.Hodl,
 		UnsafeReplay:            s.cfg.UnsafeReplay,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation: s.cfg.MaxChannelFeeAllocation,
 		CoopCloseTargetConfs:    s.cfg.CoopCloseTargetConfs,
+		CoopCloseScript:         s.coopCloseScript,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		ChannelCommitInterval:  s.cfg.ChannelCommitInterval,
 		PendingCommitInterval:  s.cfg.PendingCommitInterval,
 		ChannelCommitBatchSize: s.cfg.ChannelCommitBatchSize,
 		HandleCustomMessage:    s.handleCustomMessage,
+		HandleOnionMessage:     s.handleOnionMessage,
 		GetAliases:             s.aliasMgr.GetAliases,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
 		DisallowRouteBlinding:  s.cfg.ProtocolOptions.NoRouteBlinding(),
+		ManualDataLossRecovery: manualDataLossRecovery,
 		Quit:                   s.quit,
 	}
 
@@ -4130,12 +5116,16 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 	}
 
 	// We'll ensure that we locate all the peers advertised addresses for
-	// reconnection purposes.
-	advertisedAddrs, err := s.fetchNodeAdvertisedAddrs(pubKey)
+	// reconnection purposes, as well as the addresses we previously
+	// connected to them over.
+	var gossipUpdate time.Time
+	cachedAddrs := s.cachedPeerAddrs(pubKey)
+	advertisedAddrs, lastUpdate, err := s.fetchNodeAdvertisedAddrs(pubKey)
 	switch {
 	// We found advertised addresses, so use them.
 	case err == nil:
 		addrs = advertisedAddrs
+		gossipUpdate = lastUpdate
 
 	// The peer doesn't have an advertised address.
 	case err == errNoAdvertisedAddr:
@@ -4155,7 +5145,13 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 		// to us (we'll see a private address, which is
 		// the address used by our onion service to dial
 		// to lnd), so we don't have enough information
-		// to attempt a reconnect.
+		// to attempt a reconnect, unless we connected to
+		// the peer successfully before.
+		if len(cachedAddrs) > 0 {
+			addrs = nil
+			break
+		}
+
 		srvrLog.Debugf("Ignoring reconnection attempt "+
 			"to inbound peer %v without "+
 			"advertised address", p)
@@ -4169,6 +5165,7 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 			"address for node %x: %v", p.PubKey(),
 			err)
 	}
+	addrs = orderPeerAddrs(addrs, gossipUpdate, cachedAddrs)
 
 	// Make an easy lookup map so that we can check if an address
 	// is already in the address list that we have stored for this peer.
@@ -4197,6 +5194,12 @@ func (s *server) peerTerminationWatcher(p *peer.Brontide, ready chan struct{}) {
 	backoff := s.nextPeerBackoff(pubStr, p.StartTime())
 	s.persistentPeersBackoff[pubStr] = backoff
 
+	// If the peer is temporarily banned, we'll wait for the ban to expire
+	// before reconnecting.
+	if remaining := s.banRemaining(pubStr); remaining > backoff {
+		backoff = remaining
+	}
+
 	// Initialize a retry canceller for this peer if one does not
 	// exist.
 	cancelChan, ok := s.persistentRetryCancels[pubStr]
@@ -4238,6 +5241,13 @@ func (s *server) connectToPersistentPeer(pubKeyStr string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// If the peer is temporarily banned, we'll only try again once the ban
+	// expired.
+	if remaining := s.banRemaining(pubKeyStr); remaining > 0 {
+		s.delayPersistentReconnect(pubKeyStr, remaining)
+		return
+	}
+
 	// Create an easy lookup map of the addresses we have stored for the
 	// peer. We will remove entries from this map if we have existing
 	// connection requests for the associated address and then any leftover
@@ -4288,14 +5298,25 @@ func (s *server) connectToPersistentPeer(pubKeyStr string) {
 	}
 
 	// Any addresses left in addrMap are new ones that we have not made
-	// connection requests for. So create new connection requests for those.
-	// If there is more than one address in the address map, stagger the
-	// creation of the connection requests for those.
+	// connection requests for. So create new connection requests for those,
+	// in the order of preference of the stored addresses. If there is more
+	// than one address in the address map, stagger the creation of the
+	// connection requests for those.
+	newAddrs := make([]*lnwire.NetAddress, 0, len(addrMap))
+	for _, addr := range s.persistentPeerAddrs[pubKeyStr] {
+		if _, ok := addrMap[addr.String()]; !ok {
+			continue
+		}
+
+		newAddrs = append(newAddrs, addr)
+		delete(addrMap, addr.String())
+	}
+
 	go func() {
 		ticker := time.NewTicker(multiAddrConnectionStagger)
 		defer ticker.Stop()
 
-		for _, addr := range addrMap {
+		for _, addr := range newAddrs {
 			// Send the persistent connection request to the
 			// connection manager, saving the request itself so we
 			// can cancel/restart the process as needed.
@@ -4373,6 +5394,11 @@ func (s *server) removePeer(p *peer.Brontide) {
 	copy(pubKey[:], pubSer)
 
 	s.peerNotifier.NotifyPeerOffline(pubKey)
+
+	// Drop any onion message rate limiting state we kept for the peer.
+	if s.onionMsgHandler != nil {
+		s.onionMsgHandler.RemovePeer(route.Vertex(pKey))
+	}
 }
 
 // ConnectToPeer requests that the server connect to a Lightning Network peer
@@ -4412,6 +5438,17 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 	// then instruct the connection manager to attempt to establish a
 	// persistent connection to the peer.
 	srvrLog.Debugf("Connecting to %v", addr)
+	if s.banRemaining(targetPub) > 0 {
+		banExpiry := s.peerBans[targetPub]
+		s.mu.Unlock()
+		return fmt.Errorf("peer %x is banned until %v", targetPub,
+			banExpiry)
+	}
+	if perm && s.reconnectDenied(targetPub) {
+		s.mu.Unlock()
+		return fmt.Errorf("peer %x is on the peer denylist, unable to "+
+			"connect permanently", targetPub)
+	}
 	if perm {
 		connReq := &connmgr.ConnReq{
 			Addr:      addr,
@@ -4478,6 +5515,124 @@ func (s *server) connectToPeer(addr *lnwire.NetAddress,
 	s.OutboundPeerConnected(nil, conn)
 }
 
+// ConnectionTestResult is the outcome of a successful connection test to a
+// peer.
+type ConnectionTestResult struct {
+	// HandshakeLatency is the time it took to dial the peer and complete
+	// the transport handshake.
+	HandshakeLatency time.Duration
+
+	// InitLatency is the time it took to dial the peer until its init
+	// message was received.
+	InitLatency time.Duration
+
+	// Features is the set of features the peer advertised in its init
+	// message.
+	Features *lnwire.FeatureVector
+}
+
+// TestConnection dials the peer at the given address, performs the transport
+// handshake and exchanges init messages with it, then disconnects right away.
+// The connection isn't handed to the peer manager, so no lasting connection
+// is established. The given timeout applies to the whole test.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) TestConnection(addr *lnwire.NetAddress,
+	timeout time.Duration) (*ConnectionTestResult, error) {
+
+	// The remote node would replace an existing connection with the test
+	// connection, so we refuse to test peers we're already connected to.
+	if _, err := s.FindPeer(addr.IdentityKey); err == nil {
+		return nil, fmt.Errorf("already connected to peer %x",
+			addr.IdentityKey.SerializeCompressed())
+	}
+
+	initFeatures := s.featureMgr.Get(feature.SetInit)
+	legacyFeatures := s.featureMgr.Get(feature.SetLegacyGlobal)
+	initMsg := lnwire.NewInitMessage(
+		legacyFeatures.RawFeatureVector, initFeatures.RawFeatureVector,
+	)
+
+	return testPeerConnection(
+		s.identityECDH, addr, timeout, s.cfg.net.Dial, initMsg,
+	)
+}
+
+// testPeerConnection dials the peer at the given address with the given
+// dialer, performs the transport handshake, sends our init message and waits
+// for the init message of the peer. The connection is closed before
+// returning. The given timeout applies to the whole test.
+func testPeerConnection(local keychain.SingleKeyECDH, addr *lnwire.NetAddress,
+	timeout time.Duration, dial tor.DialFunc,
+	initMsg *lnwire.Init) (*ConnectionTestResult, error) {
+
+	// The handshake is bound by the timeout of the test rather than the
+	// default handshake timeout, so slow peers can be tested too.
+	start := time.Now()
+	conn, err := brontide.DialWithDeadline(
+		local, addr, start.Add(timeout), dial,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %v: %w", addr,
+			err)
+	}
+	defer conn.Close()
+
+	handshakeLatency := time.Since(start)
+
+	// The exchange of init messages must complete within the remainder of
+	// the timeout.
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, initMsg, 0); err != nil {
+		return nil, err
+	}
+	if err := conn.WriteMessage(b.Bytes()); err != nil {
+		return nil, fmt.Errorf("unable to send init message: %w", err)
+	}
+	if _, err := conn.Flush(); err != nil {
+		return nil, fmt.Errorf("unable to send init message: %w", err)
+	}
+
+	// The first message the peer sends after the handshake must be its
+	// init message.
+	rawMsg, err := conn.ReadNextMessage()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read init message: %w", err)
+	}
+	initLatency := time.Since(start)
+
+	msg, err := lnwire.ReadMessage(bytes.NewReader(rawMsg), 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse init message: %w", err)
+	}
+	remoteInit, ok := msg.(*lnwire.Init)
+	if !ok {
+		return nil, fmt.Errorf("expected init message, got %v",
+			msg.MsgType())
+	}
+
+	err = remoteInit.Features.Merge(remoteInit.GlobalFeatures)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge legacy global "+
+			"features: %w", err)
+	}
+
+	srvrLog.Debugf("Tested connection to %v, handshake_latency=%v, "+
+		"init_latency=%v", addr, handshakeLatency, initLatency)
+
+	return &ConnectionTestResult{
+		HandshakeLatency: handshakeLatency,
+		InitLatency:      initLatency,
+		Features: lnwire.NewFeatureVector(
+			remoteInit.Features, lnwire.Features,
+		),
+	}, nil
+}
+
 // DisconnectPeer sends the request to server to close the connection with peer
 // identified by public key.
 //
@@ -4514,6 +5669,107 @@ func (s *server) DisconnectPeer(pubKey *btcec.PublicKey) error {
 	return nil
 }
 
+// BanPeer disconnects from the peer with the given public key and refuses any
+// connection with it for the given duration. Unlike DisconnectPeer, the peer
+// stays a persistent peer, so the connection is re-established once the ban
+// expires. The time at which the ban expires is returned.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) BanPeer(pubKey *btcec.PublicKey,
+	duration time.Duration) (time.Time, error) {
+
+	if duration <= 0 {
+		return time.Time{}, fmt.Errorf("invalid ban duration %v",
+			duration)
+	}
+
+	pubBytes := pubKey.SerializeCompressed()
+	pubStr := string(pubBytes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiry := time.Now().Add(duration)
+	s.peerBans[pubStr] = expiry
+
+	srvrLog.Infof("Banning peer %x until %v", pubBytes, expiry)
+
+	// Cancel any pending connection attempts, we'll only reconnect once
+	// the ban expired.
+	s.cancelConnReqs(pubStr, nil)
+
+	// If we're connected to the peer, we'll disconnect it now. The peer
+	// termination watcher schedules the reconnection of persistent peers
+	// for when the ban expires.
+	peer, err := s.findPeerByPubStr(pubStr)
+	if err == nil {
+		peer.Disconnect(fmt.Errorf("server: peer banned until %v",
+			expiry))
+
+		return expiry, nil
+	}
+
+	// The map value only tells whether the peer is permanent, so we
+	// check for presence to also cover non-permanent persistent peers.
+	if _, ok := s.persistentPeers[pubStr]; ok {
+		s.delayPersistentReconnect(pubStr, duration)
+	}
+
+	return expiry, nil
+}
+
+// UnbanPeer lifts the ban of the peer with the given public key. If the peer
+// is a persistent peer, we'll reconnect to it right away.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) UnbanPeer(pubKey *btcec.PublicKey) error {
+	pubBytes := pubKey.SerializeCompressed()
+	pubStr := string(pubBytes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.banRemaining(pubStr) == 0 {
+		return fmt.Errorf("peer %x is not banned", pubBytes)
+	}
+	delete(s.peerBans, pubStr)
+
+	srvrLog.Infof("Lifted ban of peer %x", pubBytes)
+
+	if _, ok := s.persistentPeers[pubStr]; !ok {
+		return nil
+	}
+
+	// Replace the retry that waits for the ban to expire with an
+	// immediate reconnection attempt.
+	s.cancelConnReqs(pubStr, nil)
+	go s.connectToPersistentPeer(pubStr)
+
+	return nil
+}
+
+// BannedPeers returns the peers that are currently banned along with the time
+// their ban expires.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) BannedPeers() map[route.Vertex]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bans := make(map[route.Vertex]time.Time, len(s.peerBans))
+	for pubStr, expiry := range s.peerBans {
+		if s.banRemaining(pubStr) == 0 {
+			continue
+		}
+
+		var peer route.Vertex
+		copy(peer[:], pubStr)
+		bans[peer] = expiry
+	}
+
+	return bans
+}
+
 // OpenChannel sends a request to the server to open a channel to the specified
 // peer identified by nodeKey with the passed channel funding parameters.
 //
@@ -4621,23 +5877,126 @@ func computeNextBackoff(currBackoff, maxBackoff time.Duration) time.Duration {
 // advertised address of a node, but they don't have one.
 var errNoAdvertisedAddr = errors.New("no advertised address found")
 
-// fetchNodeAdvertisedAddrs attempts to fetch the advertised addresses of a node.
-func (s *server) fetchNodeAdvertisedAddrs(pub *btcec.PublicKey) ([]net.Addr, error) {
+// fetchNodeAdvertisedAddrs attempts to fetch the advertised addresses of a
+// node, along with the time of the node announcement they were taken from.
+func (s *server) fetchNodeAdvertisedAddrs(pub *btcec.PublicKey) ([]net.Addr,
+	time.Time, error) {
+
 	vertex, err := route.NewVertexFromBytes(pub.SerializeCompressed())
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	node, err := s.graphDB.FetchLightningNode(nil, vertex)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	if len(node.Addresses) == 0 {
-		return nil, errNoAdvertisedAddr
+		return nil, time.Time{}, errNoAdvertisedAddr
+	}
+
+	return node.Addresses, node.LastUpdate, nil
+}
+
+// isTorOnlyPeer returns true if the given peer is only reachable over Tor. A
+// peer that advertises addresses is Tor-only if all of them are onion
+// addresses. For other peers, we go by the address of our connection to the
+// peer, which is only an onion address if we dialed the peer's onion service.
+func (s *server) isTorOnlyPeer(peer lnpeer.Peer) (bool, error) {
+	addrs, _, err := s.fetchNodeAdvertisedAddrs(peer.IdentityKey())
+	switch {
+	case err == nil:
+		return allOnionAddrs(addrs), nil
+
+	// Without advertised addresses, we fall back to the connection.
+	case errors.Is(err, errNoAdvertisedAddr),
+		errors.Is(err, channeldb.ErrGraphNodeNotFound):
+
+		return allOnionAddrs([]net.Addr{peer.Address()}), nil
+
+	default:
+		return false, err
+	}
+}
+
+// allOnionAddrs returns true if the given addresses are all onion addresses.
+func allOnionAddrs(addrs []net.Addr) bool {
+	if len(addrs) == 0 {
+		return false
+	}
+
+	for _, addr := range addrs {
+		if _, ok := addr.(*tor.OnionAddr); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cachedPeerAddrs returns the addresses we previously connected to the given
+// peer over successfully, if the peer address cache is enabled.
+func (s *server) cachedPeerAddrs(
+	pub *btcec.PublicKey) []channeldb.CachedPeerAddr {
+
+	if s.peerAddrCache == nil {
+		return nil
+	}
+
+	cached, err := s.peerAddrCache.FetchPeerAddrs(pub)
+	if err != nil {
+		srvrLog.Errorf("Unable to fetch cached addresses of peer "+
+			"%x: %v", pub.SerializeCompressed(), err)
+
+		return nil
+	}
+
+	return cached
+}
+
+// orderPeerAddrs combines the addresses of a peer learned from gossip, whose
+// node announcement is from the given time, with the addresses we previously
+// connected to the peer over successfully. Cached addresses that were
+// successful after the node announcement come first, as the peer's gossip
+// likely didn't catch up with an address change yet. They are followed by the
+// gossip addresses, which are preferred over older cached addresses.
+// Duplicates are removed.
+func orderPeerAddrs(gossipAddrs []net.Addr, gossipUpdate time.Time,
+	cached []channeldb.CachedPeerAddr) []net.Addr {
+
+	addrs := make([]net.Addr, 0, len(gossipAddrs)+len(cached))
+	seen := make(map[string]struct{}, cap(addrs))
+	add := func(addr net.Addr) {
+		if _, ok := seen[addr.String()]; ok {
+			return
+		}
+
+		seen[addr.String()] = struct{}{}
+		addrs = append(addrs, addr)
+	}
+
+	// The cached addresses are sorted with the most recently successful
+	// one first.
+	var numFresh int
+	for _, c := range cached {
+		if !c.LastSuccess.After(gossipUpdate) {
+			break
+		}
+
+		add(c.Address)
+		numFresh++
+	}
+
+	for _, addr := range gossipAddrs {
+		add(addr)
+	}
+
+	for _, c := range cached[numFresh:] {
+		add(c.Address)
 	}
 
-	return node.Addresses, nil
+	return addrs
 }
 
 // fetchLastChanUpdate returns a function which is able to retrieve our latest
@@ -1,9 +1,24 @@
 package lnd
 
 import (
+	"bytes"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/peer"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/stretchr/testify/require"
 )
 
 // TestShouldPeerBootstrap tests that we properly skip network bootstrap for
@@ -80,3 +95,436 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		}
 	}
 }
+
+// TestPeerReconnectLists tests that peers on the denylist are never
+// reconnected to, and that peers on the allowlist are always retried with the
+// minimum backoff.
+func TestPeerReconnectLists(t *testing.T) {
+	t.Parallel()
+
+	newPeer := func() (string, route.Vertex) {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		pubKey := privKey.PubKey()
+
+		return string(pubKey.SerializeCompressed()),
+			route.NewVertex(pubKey)
+	}
+	deniedPeer, deniedVertex := newPeer()
+	allowedPeer, allowedVertex := newPeer()
+	otherPeer, _ := newPeer()
+
+	const (
+		minBackoff = time.Second
+		maxBackoff = time.Hour
+	)
+	s := &server{
+		cfg: &Config{
+			MinBackoff: minBackoff,
+			MaxBackoff: maxBackoff,
+			PeerDenylist: map[route.Vertex]struct{}{
+				deniedVertex: {},
+			},
+			PeerAllowlist: map[route.Vertex]struct{}{
+				allowedVertex: {},
+			},
+		},
+		persistentPeersBackoff: map[string]time.Duration{
+			allowedPeer: time.Minute,
+			otherPeer:   time.Minute,
+		},
+	}
+
+	require.True(t, s.reconnectDenied(deniedPeer))
+	require.False(t, s.reconnectDenied(allowedPeer))
+	require.False(t, s.reconnectDenied(otherPeer))
+
+	require.True(t, s.reconnectPrioritized(allowedPeer))
+	require.False(t, s.reconnectPrioritized(deniedPeer))
+	require.False(t, s.reconnectPrioritized(otherPeer))
+
+	// A failed connection attempt increases the backoff of regular peers,
+	// but prioritized peers are retried with the minimum backoff.
+	require.Equal(
+		t, minBackoff, s.nextPeerBackoff(allowedPeer, time.Time{}),
+	)
+	require.Greater(
+		t, s.nextPeerBackoff(otherPeer, time.Time{}), minBackoff,
+	)
+}
+
+// TestPeerBans tests that peers can be banned temporarily, that bans can be
+// lifted and that expired bans are removed.
+func TestPeerBans(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+	pubStr := string(pubKey.SerializeCompressed())
+
+	s := &server{
+		peersByPub: make(map[string]*peer.Brontide),
+		peerBans:   make(map[string]time.Time),
+	}
+
+	// A ban needs a positive duration.
+	_, err = s.BanPeer(pubKey, 0)
+	require.Error(t, err)
+
+	// Banning a peer we're not connected to just records the ban.
+	expiry, err := s.BanPeer(pubKey, time.Hour)
+	require.NoError(t, err)
+	require.Greater(t, s.banRemaining(pubStr), time.Duration(0))
+	require.Equal(t, map[route.Vertex]time.Time{
+		route.NewVertex(pubKey): expiry,
+	}, s.BannedPeers())
+
+	// Once lifted, the peer is no longer banned and can't be unbanned
+	// again.
+	require.NoError(t, s.UnbanPeer(pubKey))
+	require.Zero(t, s.banRemaining(pubStr))
+	require.Empty(t, s.BannedPeers())
+	require.Error(t, s.UnbanPeer(pubKey))
+
+	// Expired bans are removed.
+	s.peerBans[pubStr] = time.Now().Add(-time.Second)
+	require.Empty(t, s.BannedPeers())
+	require.NotContains(t, s.peerBans, pubStr)
+}
+
+// TestOrderPeerAddrs tests that cached peer addresses are only preferred over
+// the addresses learned from gossip if they were successful after the node
+// announcement, and that duplicates are removed.
+func TestOrderPeerAddrs(t *testing.T) {
+	t.Parallel()
+
+	addr := func(ip string) net.Addr {
+		return &net.TCPAddr{IP: net.ParseIP(ip), Port: 9735}
+	}
+
+	gossipUpdate := time.Unix(1_700_000_000, 0)
+	gossipAddrs := []net.Addr{addr("10.0.0.1"), addr("10.0.0.2")}
+	cached := []channeldb.CachedPeerAddr{{
+		Address:     addr("10.0.0.3"),
+		LastSuccess: gossipUpdate.Add(time.Hour),
+	}, {
+		Address:     addr("10.0.0.2"),
+		LastSuccess: gossipUpdate.Add(time.Minute),
+	}, {
+		Address:     addr("10.0.0.4"),
+		LastSuccess: gossipUpdate.Add(-time.Hour),
+	}}
+
+	require.Equal(t, []net.Addr{
+		addr("10.0.0.3"), addr("10.0.0.2"), addr("10.0.0.1"),
+		addr("10.0.0.4"),
+	}, orderPeerAddrs(gossipAddrs, gossipUpdate, cached))
+
+	// Without any cached addresses, the gossip addresses are used as is.
+	require.Equal(
+		t, gossipAddrs, orderPeerAddrs(gossipAddrs, gossipUpdate, nil),
+	)
+
+	// Without gossip, only the cached addresses are used.
+	require.Equal(t, []net.Addr{
+		addr("10.0.0.3"), addr("10.0.0.2"), addr("10.0.0.4"),
+	}, orderPeerAddrs(nil, time.Time{}, cached))
+}
+
+// TestAllOnionAddrs tests that a peer is only considered Tor-only if all of
+// its addresses are onion addresses.
+func TestAllOnionAddrs(t *testing.T) {
+	t.Parallel()
+
+	onion := &tor.OnionAddr{
+		OnionService: "3g2upl4pq6kufc4m.onion",
+		Port:         9735,
+	}
+	clearnet := &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 9735}
+
+	// Peers connecting through our onion service appear to come from the
+	// loopback interface.
+	loopback := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9735}
+
+	require.False(t, allOnionAddrs(nil))
+	require.True(t, allOnionAddrs([]net.Addr{onion}))
+	require.False(t, allOnionAddrs([]net.Addr{onion, clearnet}))
+	require.False(t, allOnionAddrs([]net.Addr{clearnet}))
+	require.False(t, allOnionAddrs([]net.Addr{loopback}))
+}
+
+// TestRestoredChanTimedOut asserts that only restored channels whose peer
+// didn't send its data within the timeout are reported as timed out.
+func TestRestoredChanTimedOut(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	restoredAt := time.Unix(1_700_000_000, 0)
+	shell := &channeldb.ChannelShell{
+		NodeAddrs: []net.Addr{&net.TCPAddr{
+			IP:   net.ParseIP("127.0.0.1"),
+			Port: 9735,
+		}},
+		Chan: &channeldb.OpenChannel{
+			FundingOutpoint:         wire.OutPoint{Index: 1},
+			IdentityPub:             priv.PubKey(),
+			RemoteCurrentRevocation: priv.PubKey(),
+			RevocationStore:         shachain.NewRevocationStore(),
+			RevocationProducer: shachain.NewRevocationProducer(
+				chainhash.Hash{},
+			),
+			RestoredAt: restoredAt,
+		},
+	}
+	cdb := fullDB.ChannelStateDB()
+	require.NoError(t, cdb.RestoreChannelShells(shell))
+
+	channels, err := cdb.FetchOpenChannels(priv.PubKey())
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	channel := channels[0]
+
+	const timeout = 24 * time.Hour
+	require.False(t, restoredChanTimedOut(
+		channel, timeout, restoredAt.Add(timeout-time.Second),
+	))
+	require.True(t, restoredChanTimedOut(
+		channel, timeout, restoredAt.Add(timeout),
+	))
+
+	// A zero timeout disables the check.
+	require.False(t, restoredChanTimedOut(
+		channel, 0, restoredAt.Add(timeout),
+	))
+
+	// Channels restored by older versions don't have a restore time.
+	channel.RestoredAt = time.Time{}
+	require.False(t, restoredChanTimedOut(
+		channel, timeout, restoredAt.Add(timeout),
+	))
+	channel.RestoredAt = restoredAt
+
+	// Once the peer sent its data, we only wait for the force close.
+	require.NoError(t, channel.MarkDataLoss(priv.PubKey()))
+	require.False(t, restoredChanTimedOut(
+		channel, timeout, restoredAt.Add(timeout),
+	))
+}
+
+// TestPeerConnectionTest asserts that a connection test exchanges init
+// messages with the peer, reports the features it advertised and fails if the
+// peer doesn't respond properly within the timeout.
+func TestPeerConnectionTest(t *testing.T) {
+	t.Parallel()
+
+	localPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	localKey := &keychain.PrivKeyECDH{PrivKey: localPriv}
+
+	// newPeer starts a listener that handles a single connection with the
+	// given function, and returns the address of the listener.
+	newPeer := func(t *testing.T,
+		handle func(conn *brontide.Conn)) *lnwire.NetAddress {
+
+		remotePriv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		listener, err := brontide.NewListener(
+			&keychain.PrivKeyECDH{PrivKey: remotePriv},
+			"localhost:0",
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, listener.Close())
+		})
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			handle(conn.(*brontide.Conn))
+		}()
+
+		return &lnwire.NetAddress{
+			IdentityKey: remotePriv.PubKey(),
+			Address:     listener.Addr().(*net.TCPAddr),
+		}
+	}
+
+	// sendMsg writes the given message to the connection. Errors are
+	// ignored, as they show up as a failed connection test.
+	sendMsg := func(conn *brontide.Conn, msg lnwire.Message) {
+		var b bytes.Buffer
+		if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+			return
+		}
+		if err := conn.WriteMessage(b.Bytes()); err != nil {
+			return
+		}
+		_, _ = conn.Flush()
+	}
+
+	localInit := lnwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(),
+		lnwire.NewRawFeatureVector(lnwire.DataLossProtectRequired),
+	)
+	dial := func(network, address string,
+		timeout time.Duration) (net.Conn, error) {
+
+		return net.DialTimeout(network, address, timeout)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		// The peer expects our init message first and then replies
+		// with its own, advertising a feature in the legacy global
+		// feature vector to make sure both vectors are merged.
+		receivedInit := make(chan *lnwire.Init, 1)
+		addr := newPeer(t, func(conn *brontide.Conn) {
+			rawMsg, err := conn.ReadNextMessage()
+			if err != nil {
+				return
+			}
+			msg, err := lnwire.ReadMessage(
+				bytes.NewReader(rawMsg), 0,
+			)
+			if err != nil {
+				return
+			}
+			init, _ := msg.(*lnwire.Init)
+			receivedInit <- init
+
+			sendMsg(conn, lnwire.NewInitMessage(
+				lnwire.NewRawFeatureVector(
+					lnwire.StaticRemoteKeyOptional,
+				),
+				lnwire.NewRawFeatureVector(
+					lnwire.GossipQueriesOptional,
+				),
+			))
+		})
+
+		result, err := testPeerConnection(
+			localKey, addr, time.Minute, dial, localInit,
+		)
+		require.NoError(t, err)
+
+		init := <-receivedInit
+		require.NotNil(t, init)
+		require.True(t, init.Features.IsSet(
+			lnwire.DataLossProtectRequired,
+		))
+
+		require.True(t, result.Features.HasFeature(
+			lnwire.StaticRemoteKeyOptional,
+		))
+		require.True(t, result.Features.HasFeature(
+			lnwire.GossipQueriesOptional,
+		))
+		require.GreaterOrEqual(
+			t, result.InitLatency, result.HandshakeLatency,
+		)
+	})
+
+	t.Run("unexpected message", func(t *testing.T) {
+		t.Parallel()
+
+		addr := newPeer(t, func(conn *brontide.Conn) {
+			sendMsg(conn, lnwire.NewPing(0))
+		})
+
+		_, err := testPeerConnection(
+			localKey, addr, time.Minute, dial, localInit,
+		)
+		require.ErrorContains(t, err, "expected init message")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// The peer completes the handshake but never sends its init
+		// message, so the test must fail once the timeout expires.
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		addr := newPeer(t, func(conn *brontide.Conn) {
+			<-done
+		})
+
+		timeout := 500 * time.Millisecond
+		start := time.Now()
+		_, err := testPeerConnection(
+			localKey, addr, timeout, dial, localInit,
+		)
+		require.ErrorContains(t, err, "unable to read init message")
+		require.Less(t, time.Since(start), 10*timeout)
+	})
+
+	t.Run("handshake timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// The peer accepts the connection but never answers our first
+		// act, so the handshake must fail once the timeout of the
+		// test expires rather than the default handshake timeout.
+		listener, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+			require.NoError(t, listener.Close())
+		})
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			<-done
+		}()
+		addr := &lnwire.NetAddress{
+			IdentityKey: localPriv.PubKey(),
+			Address:     listener.Addr().(*net.TCPAddr),
+		}
+
+		timeout := 500 * time.Millisecond
+		start := time.Now()
+		_, err = testPeerConnection(
+			localKey, addr, timeout, dial, localInit,
+		)
+		require.ErrorContains(t, err, "unable to connect")
+		require.Less(t, time.Since(start), 5*timeout)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		// Nothing is listening at the address once the listener is
+		// closed.
+		listener, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		addr := &lnwire.NetAddress{
+			IdentityKey: localPriv.PubKey(),
+			Address:     listener.Addr().(*net.TCPAddr),
+		}
+		require.NoError(t, listener.Close())
+
+		_, err = testPeerConnection(
+			localKey, addr, time.Minute, dial, localInit,
+		)
+		require.ErrorContains(t, err, "unable to connect")
+	})
+}
@@ -106,6 +106,11 @@ type Interceptor struct {
 	// gracefully, similar to when receiving SIGINT.
 	shutdownRequestChannel chan struct{}
 
+	// reloadChannel receives a notification for each SIGHUP, asking the
+	// daemon to reload its log levels. Notifications are coalesced while
+	// a reload is pending.
+	reloadChannel chan struct{}
+
 	// quit is closed when instructing the main interrupt handler to exit.
 	// Note that to avoid losing notifications, only shutdown func may
 	// close this channel.
@@ -126,6 +131,7 @@ func Intercept() (Interceptor, error) {
 		interruptChannel:       make(chan os.Signal, 1),
 		shutdownChannel:        make(chan struct{}),
 		shutdownRequestChannel: make(chan struct{}),
+		reloadChannel:          make(chan struct{}, 1),
 		quit:                   make(chan struct{}),
 	}
 
@@ -134,6 +140,7 @@ func Intercept() (Interceptor, error) {
 		os.Kill,
 		syscall.SIGTERM,
 		syscall.SIGQUIT,
+		syscall.SIGHUP,
 	}
 	signal.Notify(channels.interruptChannel, signalsToCatch...)
 	go channels.mainInterruptHandler()
@@ -175,6 +182,13 @@ func (c *Interceptor) mainInterruptHandler() {
 		select {
 		case signal := <-c.interruptChannel:
 			log.Infof("Received %v", signal)
+
+			// A SIGHUP only asks us to reload the log levels.
+			if signal == syscall.SIGHUP {
+				c.requestReload()
+				continue
+			}
+
 			shutdown()
 
 		case <-c.shutdownRequestChannel:
@@ -222,6 +236,21 @@ func (c *Interceptor) RequestShutdown() {
 	}
 }
 
+// requestReload notifies the reload channel, unless a reload is already
+// pending.
+func (c *Interceptor) requestReload() {
+	select {
+	case c.reloadChannel <- struct{}{}:
+	default:
+	}
+}
+
+// ReloadChannel returns the channel that receives a notification each time
+// the daemon is asked to reload its log levels by a SIGHUP.
+func (c *Interceptor) ReloadChannel() <-chan struct{} {
+	return c.reloadChannel
+}
+
 // ShutdownChannel returns the channel that will be closed once the main
 // interrupt handler has exited.
 func (c *Interceptor) ShutdownChannel() <-chan struct{} {
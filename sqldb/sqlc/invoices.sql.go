@@ -355,13 +355,35 @@ func (q *Queries) GetInvoiceHTLCs(ctx context.Context, invoiceID int64) ([]Invoi
 	return items, nil
 }
 
+const getInvoiceIndices = `-- name: GetInvoiceIndices :one
+SELECT
+    CAST(COALESCE((
+        SELECT current_value FROM invoice_sequences WHERE name = 'add_index'
+    ), 0) AS BIGINT) AS add_index,
+    CAST(COALESCE((
+        SELECT current_value FROM invoice_sequences WHERE name = 'settle_index'
+    ), 0) AS BIGINT) AS settle_index
+`
+
+type GetInvoiceIndicesRow struct {
+	AddIndex    int64
+	SettleIndex int64
+}
+
+func (q *Queries) GetInvoiceIndices(ctx context.Context) (GetInvoiceIndicesRow, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceIndices)
+	var i GetInvoiceIndicesRow
+	err := row.Scan(&i.AddIndex, &i.SettleIndex)
+	return i, err
+}
+
 const insertInvoice = `-- name: InsertInvoice :one
 INSERT INTO invoices (
     hash, preimage, memo, amount_msat, cltv_delta, expiry, payment_addr, 
     payment_request, payment_request_hash, state, amount_paid_msat, is_amp,
-    is_hodl, is_keysend, created_at
+    is_hodl, is_keysend, created_at, id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
 ) RETURNING id
 `
 
@@ -381,6 +403,7 @@ type InsertInvoiceParams struct {
 	IsHodl             bool
 	IsKeysend          bool
 	CreatedAt          time.Time
+	ID                 int64
 }
 
 func (q *Queries) InsertInvoice(ctx context.Context, arg InsertInvoiceParams) (int64, error) {
@@ -400,6 +423,7 @@ func (q *Queries) InsertInvoice(ctx context.Context, arg InsertInvoiceParams) (i
 		arg.IsHodl,
 		arg.IsKeysend,
 		arg.CreatedAt,
+		arg.ID,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -483,6 +507,19 @@ func (q *Queries) InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertI
 	return err
 }
 
+const nextInvoiceAddIndex = `-- name: NextInvoiceAddIndex :one
+UPDATE invoice_sequences SET current_value = current_value + 1
+WHERE name = 'add_index'
+RETURNING current_value
+`
+
+func (q *Queries) NextInvoiceAddIndex(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextInvoiceAddIndex)
+	var current_value int64
+	err := row.Scan(&current_value)
+	return current_value, err
+}
+
 const nextInvoiceSettleIndex = `-- name: NextInvoiceSettleIndex :one
 UPDATE invoice_sequences SET current_value = current_value + 1
 WHERE name = 'settle_index'
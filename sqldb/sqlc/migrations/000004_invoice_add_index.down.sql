@@ -0,0 +1 @@
+DELETE FROM invoice_sequences WHERE name = 'add_index';
@@ -0,0 +1,6 @@
+-- Initialize a sequence for the add index that assigns the ids of new invoices.
+-- Unlike the ids chosen by the database, the values of the sequence are never
+-- reused after an invoice is deleted, so the add index never decreases. It
+-- starts after the highest id that was assigned so far.
+INSERT INTO invoice_sequences(name, current_value)
+SELECT 'add_index', COALESCE(MAX(id), 0) FROM invoices;
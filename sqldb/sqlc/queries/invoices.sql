@@ -2,9 +2,9 @@
 INSERT INTO invoices (
     hash, preimage, memo, amount_msat, cltv_delta, expiry, payment_addr, 
     payment_request, payment_request_hash, state, amount_paid_msat, is_amp,
-    is_hodl, is_keysend, created_at
+    is_hodl, is_keysend, created_at, id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
 ) RETURNING id;
 
 -- name: InsertInvoiceFeature :exec
@@ -101,11 +101,25 @@ UPDATE invoices
 SET amount_paid_msat = $2
 WHERE id = $1;
 
+-- name: NextInvoiceAddIndex :one
+UPDATE invoice_sequences SET current_value = current_value + 1
+WHERE name = 'add_index'
+RETURNING current_value;
+
 -- name: NextInvoiceSettleIndex :one
 UPDATE invoice_sequences SET current_value = current_value + 1
 WHERE name = 'settle_index'
 RETURNING current_value;
 
+-- name: GetInvoiceIndices :one
+SELECT
+    CAST(COALESCE((
+        SELECT current_value FROM invoice_sequences WHERE name = 'add_index'
+    ), 0) AS BIGINT) AS add_index,
+    CAST(COALESCE((
+        SELECT current_value FROM invoice_sequences WHERE name = 'settle_index'
+    ), 0) AS BIGINT) AS settle_index;
+
 -- name: DeleteInvoice :execresult
 DELETE 
 FROM invoices 
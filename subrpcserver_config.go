@@ -5,6 +5,7 @@ import (
 	"net"
 	"reflect"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btclog"
 	"github.com/lightningnetwork/lnd/autopilot"
@@ -24,6 +25,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
@@ -122,7 +124,8 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 		modifiers ...netann.NodeAnnModifier) error,
 	parseAddr func(addr string) (net.Addr, error),
 	rpcLogger btclog.Logger,
-	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)) error {
+	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error),
+	fallbackAddr func(lntypes.Hash) (btcutil.Address, error)) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -176,6 +179,12 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("FeeEstimator").Set(
 				reflect.ValueOf(cc.FeeEstimator),
 			)
+			subCfgValue.FieldByName("MinFeeRate").Set(
+				reflect.ValueOf(cfg.minRelayFeeRate()),
+			)
+			subCfgValue.FieldByName("MaxFeeRate").Set(
+				reflect.ValueOf(cfg.maxFeeRate()),
+			)
 			subCfgValue.FieldByName("Wallet").Set(
 				reflect.ValueOf(cc.Wallet),
 			)
@@ -225,6 +234,15 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("Chain").Set(
 				reflect.ValueOf(cc.ChainIO),
 			)
+			subCfgValue.FieldByName("BackendInfo").Set(
+				reflect.ValueOf(cc.BackendInfo),
+			)
+			subCfgValue.FieldByName("BackendFailover").Set(
+				reflect.ValueOf(cc.BackendFailover),
+			)
+			subCfgValue.FieldByName("HeightHintCache").Set(
+				reflect.ValueOf(cc.HeightHintCache),
+			)
 
 		case *invoicesrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -266,6 +284,15 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("GetAlias").Set(
 				reflect.ValueOf(getAlias),
 			)
+			subCfgValue.FieldByName("FallbackAddr").Set(
+				reflect.ValueOf(fallbackAddr),
+			)
+			subCfgValue.FieldByName("MaxHoldInvoiceAmt").Set(
+				reflect.ValueOf(cfg.Invoices.MaxHoldAmount),
+			)
+			subCfgValue.FieldByName("RejectZeroAmount").Set(
+				reflect.ValueOf(!cfg.Invoices.AllowZeroAmount),
+			)
 
 		case *neutrinorpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -1,6 +1,7 @@
 package sweep
 
 import (
+	"math"
 	"sort"
 
 	"github.com/btcsuite/btcd/btcutil"
@@ -31,6 +32,13 @@ type BudgetAggregator struct {
 	// maxInputs specifies the maximum number of inputs allowed in a single
 	// sweep tx.
 	maxInputs uint32
+
+	// crossChannelBatching, if set, groups all inputs without a requested
+	// deadline into one cluster, even if their default deadline heights
+	// differ. This consolidates the sweeps of outputs that were resolved
+	// in different channels into fewer transactions, at the cost of
+	// linking those channels on chain.
+	crossChannelBatching bool
 }
 
 // Compile-time constraint to ensure BudgetAggregator implements UtxoAggregator.
@@ -38,11 +46,12 @@ var _ UtxoAggregator = (*BudgetAggregator)(nil)
 
 // NewBudgetAggregator creates a new instance of a BudgetAggregator.
 func NewBudgetAggregator(estimator chainfee.Estimator,
-	maxInputs uint32) *BudgetAggregator {
+	maxInputs uint32, crossChannelBatching bool) *BudgetAggregator {
 
 	return &BudgetAggregator{
-		estimator: estimator,
-		maxInputs: maxInputs,
+		estimator:            estimator,
+		maxInputs:            maxInputs,
+		crossChannelBatching: crossChannelBatching,
 	}
 }
 
@@ -70,6 +79,15 @@ func (b *BudgetAggregator) ClusterInputs(inputs InputsMap) []InputSet {
 	// grouping exclusive inputs may jeopardize non-exclusive inputs.
 	exclusiveInputs := make(map[wire.OutPoint]clusterGroup)
 
+	// noDeadlineInputs is the set of inputs without a requested deadline,
+	// which are all swept together if cross channel batching is enabled.
+	// They use the earliest of their default deadline heights so none of
+	// them is swept later than it would have been on its own.
+	var (
+		noDeadlineInputs []SweeperInput
+		noDeadlineHeight int32 = math.MaxInt32
+	)
+
 	// Iterate all the inputs and group them based on their specified
 	// deadline heights.
 	for _, input := range filteredInputs {
@@ -87,6 +105,17 @@ func (b *BudgetAggregator) ClusterInputs(inputs InputsMap) []InputSet {
 			continue
 		}
 
+		if b.crossChannelBatching &&
+			input.params.DeadlineHeight.IsNone() {
+
+			noDeadlineInputs = append(noDeadlineInputs, *input)
+			if height < noDeadlineHeight {
+				noDeadlineHeight = height
+			}
+
+			continue
+		}
+
 		cluster, ok := clusters[height]
 		if !ok {
 			cluster = make([]SweeperInput, 0)
@@ -96,6 +125,16 @@ func (b *BudgetAggregator) ClusterInputs(inputs InputsMap) []InputSet {
 		clusters[height] = cluster
 	}
 
+	if len(noDeadlineInputs) > 0 {
+		log.Tracef("Batching %v inputs without deadline using "+
+			"deadline height %v", len(noDeadlineInputs),
+			noDeadlineHeight)
+
+		clusters[noDeadlineHeight] = append(
+			clusters[noDeadlineHeight], noDeadlineInputs...,
+		)
+	}
+
 	// Now that we have the clusters, we can create the input sets.
 	//
 	// NOTE: cannot pre-allocate the slice since we don't know the number
@@ -150,7 +150,7 @@ func TestBudgetAggregatorFilterInputs(t *testing.T) {
 
 	// Init the budget aggregator with the mocked estimator and zero max
 	// num of inputs.
-	b := NewBudgetAggregator(estimator, 0)
+	b := NewBudgetAggregator(estimator, 0, false)
 
 	// Call the method under test.
 	result := b.filterInputs(inputs)
@@ -214,7 +214,7 @@ func TestBudgetAggregatorSortInputs(t *testing.T) {
 	}
 
 	// Init the budget aggregator with zero max num of inputs.
-	b := NewBudgetAggregator(nil, 0)
+	b := NewBudgetAggregator(nil, 0, false)
 
 	// Call the method under test.
 	result := b.sortInputs(inputs)
@@ -279,7 +279,7 @@ func TestBudgetAggregatorCreateInputSets(t *testing.T) {
 	}
 
 	// Create a budget aggregator with max number of inputs set to 2.
-	b := NewBudgetAggregator(nil, 2)
+	b := NewBudgetAggregator(nil, 2, false)
 
 	// Create test cases.
 	testCases := []struct {
@@ -540,7 +540,7 @@ func TestBudgetInputSetClusterInputs(t *testing.T) {
 	}
 
 	// Create a budget aggregator with a max number of inputs set to 100.
-	b := NewBudgetAggregator(estimator, DefaultMaxInputsPerTx)
+	b := NewBudgetAggregator(estimator, DefaultMaxInputsPerTx, false)
 
 	// Call the method under test.
 	result := b.ClusterInputs(inputs)
@@ -572,6 +572,77 @@ func TestBudgetInputSetClusterInputs(t *testing.T) {
 	require.Contains(t, deadlines, deadline2)
 }
 
+// TestBudgetAggregatorCrossChannelBatching checks that inputs without a
+// requested deadline are only batched across different default deadline
+// heights if cross channel batching is enabled.
+func TestBudgetAggregatorCrossChannelBatching(t *testing.T) {
+	t.Parallel()
+
+	const minFeeRate = chainfee.SatPerKWeight(1000)
+
+	estimator := &chainfee.MockEstimator{}
+	estimator.On("RelayFeePerKW").Return(minFeeRate)
+
+	wt := &input.MockWitnessType{}
+	wt.On("SizeUpperBound").Return(lntypes.WeightUnit(100), true, nil)
+	wt.On("String").Return("mock witness type").Maybe()
+
+	var (
+		// The outputs resolved in two different channels were offered
+		// at different heights, so their default deadlines differ.
+		noDeadline1 = testHeight + DefaultDeadlineDelta
+		noDeadline2 = noDeadline1 + 10
+
+		// A time-sensitive input always keeps its own deadline.
+		deadline = int32(10)
+	)
+
+	// createInput adds a sweeper input with a sufficient budget and the
+	// given deadlines to the inputs map.
+	inputs := make(InputsMap)
+	createInput := func(index uint32, deadlineHeight int32,
+		requested fn.Option[int32]) {
+
+		op := wire.OutPoint{Index: index}
+
+		inp := &input.MockInput{}
+		inp.On("OutPoint").Return(op).Maybe()
+		inp.On("WitnessType").Return(wt).Maybe()
+		inp.On("RequiredTxOut").Return(nil).Maybe()
+		inp.On("RequiredLockTime").Return(uint32(0), false).Maybe()
+
+		inputs[op] = &SweeperInput{
+			Input: inp,
+			params: Params{
+				Budget:         btcutil.SatoshiPerBitcoin,
+				DeadlineHeight: requested,
+			},
+			DeadlineHeight: deadlineHeight,
+		}
+	}
+
+	createInput(1, noDeadline1, fn.None[int32]())
+	createInput(2, noDeadline2, fn.None[int32]())
+	createInput(3, deadline, fn.Some(deadline))
+
+	// Without cross channel batching, each deadline gets its own set.
+	b := NewBudgetAggregator(estimator, DefaultMaxInputsPerTx, false)
+	require.Len(t, b.ClusterInputs(inputs), 3)
+
+	// With cross channel batching, both inputs without a requested
+	// deadline are swept together using the earlier deadline.
+	b = NewBudgetAggregator(estimator, DefaultMaxInputsPerTx, true)
+	sets := b.ClusterInputs(inputs)
+	require.Len(t, sets, 2)
+
+	setsByDeadline := make(map[int32]InputSet)
+	for _, set := range sets {
+		setsByDeadline[set.DeadlineHeight()] = set
+	}
+	require.Len(t, setsByDeadline[noDeadline1].Inputs(), 2)
+	require.Len(t, setsByDeadline[deadline].Inputs(), 1)
+}
+
 // TestSplitOnLocktime asserts `splitOnLocktime` works as expected.
 func TestSplitOnLocktime(t *testing.T) {
 	t.Parallel()
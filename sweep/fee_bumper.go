@@ -39,6 +39,11 @@ var (
 	// preparation, usually due to the output being dust.
 	ErrTxNoOutput = errors.New("tx has no output")
 
+	// ErrMaxFeeRateExceeded is returned when the starting fee rate of a
+	// sweep exceeds the max fee rate allowed by its budget and the config.
+	ErrMaxFeeRateExceeded = errors.New("starting fee rate exceeds max " +
+		"fee rate")
+
 	// ErrThirdPartySpent is returned when a third party has spent the
 	// input in the sweeping tx.
 	ErrThirdPartySpent = errors.New("third party spent the output")
@@ -382,6 +387,36 @@ func (t *TxPublisher) initializeFeeFunction(
 		"maxFeeRateAllowed=%v", confTarget, req.Budget,
 		maxFeeRateAllowed)
 
+	// If the deadline is about to be reached, the sweep needs to be
+	// published with the max fee rate allowed by its budget right away. If
+	// the configured max fee rate is lower than that, the sweep may not
+	// confirm in time, which the user needs to be aware of.
+	if confTarget <= 1 && maxFeeRateAllowed == req.MaxFeeRate {
+		log.Errorf("Time-critical sweep of %v inputs with deadline "+
+			"height %v needs a fee rate above the max fee rate of "+
+			"%v allowed by the config, it may not confirm before "+
+			"its deadline: inputs=%v", len(req.Inputs),
+			req.DeadlineHeight, req.MaxFeeRate,
+			inputTypeSummary(req.Inputs))
+	}
+
+	// A starting fee rate above the max fee rate would make the fee
+	// function start above its ending fee rate, so we refuse it.
+	err = fn.MapOptionZ(req.StartingFeeRate,
+		func(feeRate chainfee.SatPerKWeight) error {
+			if feeRate <= maxFeeRateAllowed {
+				return nil
+			}
+
+			return fmt.Errorf("%w: starting fee rate %v, max fee "+
+				"rate %v", ErrMaxFeeRateExceeded, feeRate,
+				maxFeeRateAllowed)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the fee function and return it.
 	//
 	// TODO(yy): return based on differet req.Strategy?
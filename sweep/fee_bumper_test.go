@@ -11,6 +11,7 @@ import (
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -279,6 +280,13 @@ func TestInitializeFeeFunction(t *testing.T) {
 	f, err = tp.initializeFeeFunction(req)
 	require.NoError(t, err)
 	require.Equal(t, feerate, f.FeeRate())
+
+	// A starting fee rate above the max fee rate is rejected instead of
+	// starting the fee function above its ending fee rate.
+	req.StartingFeeRate = fn.Some(req.MaxFeeRate + 1)
+	f, err = tp.initializeFeeFunction(req)
+	require.ErrorIs(t, err, ErrMaxFeeRateExceeded)
+	require.Nil(t, f)
 }
 
 // TestStoreRecord correctly increases the request counter and saves the
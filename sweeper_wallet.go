@@ -2,6 +2,7 @@ package lnd
 
 import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lnwallet"
 )
 
@@ -9,16 +10,29 @@ import (
 // sweeper's Wallet interface.
 type sweeperWallet struct {
 	*lnwallet.LightningWallet
+
+	// publishTx is used to publish the sweep transactions.
+	publishTx func(*wire.MsgTx, string) error
 }
 
 // newSweeperWallet creates a new sweeper wallet from the given
-// LightningWallet.
-func newSweeperWallet(w *lnwallet.LightningWallet) *sweeperWallet {
+// LightningWallet that publishes transactions with the given function.
+func newSweeperWallet(w *lnwallet.LightningWallet,
+	publishTx func(*wire.MsgTx, string) error) *sweeperWallet {
+
 	return &sweeperWallet{
 		LightningWallet: w,
+		publishTx:       publishTx,
 	}
 }
 
+// PublishTransaction publishes the given sweep transaction.
+func (s *sweeperWallet) PublishTransaction(tx *wire.MsgTx,
+	label string) error {
+
+	return s.publishTx(tx, label)
+}
+
 // CancelRebroadcast cancels the rebroadcast of the given transaction.
 func (s *sweeperWallet) CancelRebroadcast(txid chainhash.Hash) {
 	// For neutrino, we don't config the rebroadcaster for the wallet as it
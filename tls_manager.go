@@ -52,12 +52,25 @@ type TLSManagerCfg struct {
 	TLSDisableAutofill bool
 	TLSCertDuration    time.Duration
 
+	// TLSCertBlob and TLSKeyBlob hold the PEM encoded certificate and key
+	// if they were supplied directly instead of as files. If they are
+	// set, the TLS paths are ignored and the pair is never generated,
+	// renewed or encrypted, as we can't rewrite it.
+	TLSCertBlob []byte
+	TLSKeyBlob  []byte
+
 	LetsEncryptDir    string
 	LetsEncryptDomain string
 	LetsEncryptListen string
 
 	DisableRestTLS bool
 
+	// RPCListenerTLS and RESTListenerTLS hold the TLS configuration of
+	// the RPC and REST listeners that don't use the global certificate,
+	// keyed by the address of the listener.
+	RPCListenerTLS  map[string]*lncfg.ListenerTLS
+	RESTListenerTLS map[string]*lncfg.ListenerTLS
+
 	HTTPHeaderTimeout time.Duration
 }
 
@@ -90,8 +103,11 @@ func NewTLSManager(cfg *TLSManagerCfg) *TLSManager {
 }
 
 // getConfig returns a TLS configuration for the gRPC server and credentials
-// and a proxy destination for the REST reverse proxy.
+// and a proxy destination for the REST reverse proxy. It also returns the
+// functions to listen on the RPC and REST addresses with the TLS config of
+// each listener.
 func (t *TLSManager) getConfig() ([]grpc.ServerOption, []grpc.DialOption,
+	func(net.Addr) (net.Listener, error),
 	func(net.Addr) (net.Listener, error), func(), error) {
 
 	var (
@@ -102,23 +118,21 @@ func (t *TLSManager) getConfig() ([]grpc.ServerOption, []grpc.DialOption,
 		keyBytes = t.ephemeralKey
 		certBytes = t.ephemeralCert
 	} else {
-		certBytes, keyBytes, err = cert.GetCertBytesFromPath(
-			t.cfg.TLSCertPath, t.cfg.TLSKeyPath,
-		)
+		certBytes, keyBytes, err = t.certBytes()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 	}
 
 	certData, _, err := cert.LoadCertFromBytes(certBytes, keyBytes)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	if t.tlsReloader == nil {
 		tlsr, err := cert.NewTLSReloader(certBytes, keyBytes)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
 		}
 		t.tlsReloader = tlsr
 	}
@@ -126,13 +140,27 @@ func (t *TLSManager) getConfig() ([]grpc.ServerOption, []grpc.DialOption,
 	tlsCfg := cert.TLSConfFromCert(certData)
 	tlsCfg.GetCertificate = t.tlsReloader.GetCertificateFunc()
 
+	// Listeners can use their own certificate instead of the global one,
+	// so we load their TLS configs as well.
+	rpcListenerCfgs, err := loadListenerTLSConfigs(t.cfg.RPCListenerTLS)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	restListenerCfgs, err := loadListenerTLSConfigs(t.cfg.RESTListenerTLS)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
 	// If Let's Encrypt is enabled, we need to set up the autocert manager
 	// and override the TLS config's GetCertificate function.
 	cleanUp := t.setUpLetsEncrypt(&certData, tlsCfg)
 
 	// Now that we know that we have a certificate, let's generate the
-	// required config options.
-	serverCreds := credentials.NewTLS(tlsCfg)
+	// required config options. The gRPC server uses the global TLS config
+	// unless a connection was accepted by a listener with its own config.
+	serverCreds := &listenerCredentials{
+		TransportCredentials: credentials.NewTLS(tlsCfg),
+	}
 	serverOpts := []grpc.ServerOption{grpc.Creds(serverCreds)}
 
 	// For our REST dial options, we skip TLS verification, and we also
@@ -156,9 +184,41 @@ func (t *TLSManager) getConfig() ([]grpc.ServerOption, []grpc.DialOption,
 		),
 	}
 
-	// Return a function closure that can be used to listen on a given
-	// address with the current TLS config.
+	// Return function closures that can be used to listen on a given
+	// address with the current TLS config. The gRPC server terminates TLS
+	// itself, so we only need to tell it which config to use for the
+	// connections of a listener.
+	rpcListen := func(addr net.Addr) (net.Listener, error) {
+		lis, err := lncfg.ListenOnAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		listenerCfg, ok := rpcListenerCfgs[addr.String()]
+		if !ok {
+			return lis, nil
+		}
+
+		return &credentialsListener{
+			Listener: lis,
+			creds:    credentials.NewTLS(listenerCfg),
+		}, nil
+	}
+
 	restListen := func(addr net.Addr) (net.Listener, error) {
+		// A listener with its own TLS options overrides the global
+		// ones.
+		listenerTLS := t.cfg.RESTListenerTLS[addr.String()]
+		switch {
+		case listenerTLS != nil && listenerTLS.DisableTLS:
+			return lncfg.ListenOnAddress(addr)
+
+		case listenerTLS != nil:
+			return lncfg.TLSListenOnAddress(
+				addr, restListenerCfgs[addr.String()],
+			)
+		}
+
 		// For restListen we will call ListenOnAddress if TLS is
 		// disabled.
 		if t.cfg.DisableRestTLS {
@@ -168,12 +228,53 @@ func (t *TLSManager) getConfig() ([]grpc.ServerOption, []grpc.DialOption,
 		return lncfg.TLSListenOnAddress(addr, tlsCfg)
 	}
 
-	return serverOpts, restDialOpts, restListen, cleanUp, nil
+	return serverOpts, restDialOpts, rpcListen, restListen, cleanUp, nil
+}
+
+// usesBlobs returns true if the certificate and key were supplied directly
+// instead of as files.
+func (t *TLSManager) usesBlobs() bool {
+	return len(t.cfg.TLSCertBlob) != 0
+}
+
+// certBytes returns the PEM encoded certificate and key, either as supplied
+// directly or as read from their files.
+func (t *TLSManager) certBytes() ([]byte, []byte, error) {
+	if t.usesBlobs() {
+		return t.cfg.TLSCertBlob, t.cfg.TLSKeyBlob, nil
+	}
+
+	return cert.GetCertBytesFromPath(t.cfg.TLSCertPath, t.cfg.TLSKeyPath)
+}
+
+// loadCertBlobs loads the certificate and key that were supplied directly.
+// We can't renew a certificate we didn't generate, so we only warn if it
+// expired.
+func (t *TLSManager) loadCertBlobs() (*tls.Config, error) {
+	certData, parsedCert, err := cert.LoadCertFromBytes(
+		t.cfg.TLSCertBlob, t.cfg.TLSKeyBlob,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS certificate from "+
+			"tlscertblob and tlskeyblob: %w", err)
+	}
+
+	if time.Now().After(parsedCert.NotAfter) {
+		ltndLog.Warnf("The TLS certificate supplied by tlscertblob "+
+			"expired at %v, it needs to be replaced manually",
+			parsedCert.NotAfter)
+	}
+
+	return cert.TLSConfFromCert(certData), nil
 }
 
 // generateOrRenewCert generates a new TLS certificate if we're not using one
 // yet or renews it if it's outdated.
 func (t *TLSManager) generateOrRenewCert() (*tls.Config, error) {
+	if t.usesBlobs() {
+		return t.loadCertBlobs()
+	}
+
 	// Generete a TLS pair if we don't have one yet.
 	var emptyKeyRing keychain.SecretKeyRing
 	err := t.generateCertPair(emptyKeyRing)
@@ -467,30 +568,31 @@ func (t *TLSManager) setUpLetsEncrypt(certData *tls.Certificate,
 // unlocked and a new TLS pair can be encrypted to disk. Otherwise we can
 // process the certificate normally.
 func (t *TLSManager) SetCertificateBeforeUnlock() ([]grpc.ServerOption,
-	[]grpc.DialOption, func(net.Addr) (net.Listener, error), func(),
-	error) {
+	[]grpc.DialOption, func(net.Addr) (net.Listener, error),
+	func(net.Addr) (net.Listener, error), func(), error) {
 
 	if t.cfg.TLSEncryptKey {
 		_, err := t.loadEphemeralCertificate()
 		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("unable to load "+
-				"ephemeral certificate: %v", err)
+			return nil, nil, nil, nil, nil, fmt.Errorf("unable "+
+				"to load ephemeral certificate: %v", err)
 		}
 	} else {
 		_, err := t.generateOrRenewCert()
 		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("unable to "+
-				"generate or renew TLS certificate: %v", err)
+			return nil, nil, nil, nil, nil, fmt.Errorf("unable "+
+				"to generate or renew TLS certificate: %v", err)
 		}
 	}
 
-	serverOpts, restDialOpts, restListen, cleanUp, err := t.getConfig()
+	serverOpts, restDialOpts, rpcListen, restListen, cleanUp,
+		err := t.getConfig()
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("unable to load TLS "+
-			"credentials: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("unable to load "+
+			"TLS credentials: %v", err)
 	}
 
-	return serverOpts, restDialOpts, restListen, cleanUp, nil
+	return serverOpts, restDialOpts, rpcListen, restListen, cleanUp, nil
 }
 
 // loadEphemeralCertificate creates and loads the ephemeral certificate which
@@ -596,9 +698,7 @@ func (t *TLSManager) deleteEphemeralSettings() {
 func (t *TLSManager) IsCertExpired(keyRing keychain.SecretKeyRing) (bool,
 	time.Time, error) {
 
-	certBytes, keyBytes, err := cert.GetCertBytesFromPath(
-		t.cfg.TLSCertPath, t.cfg.TLSKeyPath,
-	)
+	certBytes, keyBytes, err := t.certBytes()
 	if err != nil {
 		return false, time.Time{}, err
 	}
@@ -630,3 +730,86 @@ func (t *TLSManager) IsCertExpired(keyRing keychain.SecretKeyRing) (bool,
 
 	return false, parsedCert.NotAfter, nil
 }
+
+// loadListenerTLSConfigs loads the TLS configs of the listeners that use their
+// own certificate, keyed by the address of the listener.
+func loadListenerTLSConfigs(
+	listenerTLS map[string]*lncfg.ListenerTLS) (map[string]*tls.Config,
+	error) {
+
+	tlsCfgs := make(map[string]*tls.Config, len(listenerTLS))
+	for addr, listener := range listenerTLS {
+		if listener.DisableTLS {
+			continue
+		}
+
+		certData, _, err := cert.LoadCert(
+			listener.CertPath, listener.KeyPath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load TLS certificate "+
+				"of listener %v: %w", addr, err)
+		}
+
+		tlsCfgs[addr] = cert.TLSConfFromCert(certData)
+	}
+
+	return tlsCfgs, nil
+}
+
+// credentialsListener is a listener whose connections use their own transport
+// credentials instead of the ones of the gRPC server.
+type credentialsListener struct {
+	net.Listener
+
+	creds credentials.TransportCredentials
+}
+
+// Accept waits for the next connection and attaches the transport credentials
+// of the listener to it.
+//
+// NOTE: This is part of the net.Listener interface.
+func (l *credentialsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentialsConn{Conn: conn, creds: l.creds}, nil
+}
+
+// credentialsConn is a connection accepted by a credentialsListener.
+type credentialsConn struct {
+	net.Conn
+
+	creds credentials.TransportCredentials
+}
+
+// listenerCredentials are the transport credentials of the gRPC server. The
+// handshake of connections accepted by a credentialsListener is done with the
+// credentials of the listener, all others use the embedded credentials.
+type listenerCredentials struct {
+	credentials.TransportCredentials
+}
+
+// ServerHandshake does the server side handshake of the given connection.
+//
+// NOTE: This is part of the credentials.TransportCredentials interface.
+func (c *listenerCredentials) ServerHandshake(
+	rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+
+	if conn, ok := rawConn.(*credentialsConn); ok {
+		return conn.creds.ServerHandshake(conn.Conn)
+	}
+
+	return c.TransportCredentials.ServerHandshake(rawConn)
+}
+
+// Clone returns a copy of the transport credentials.
+//
+// NOTE: This is part of the credentials.TransportCredentials interface.
+func (c *listenerCredentials) Clone() credentials.TransportCredentials {
+	return &listenerCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+	}
+}
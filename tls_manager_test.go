@@ -18,10 +18,12 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/cert"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lntest/channels"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -55,7 +57,7 @@ func TestGenerateOrRenewCert(t *testing.T) {
 	tlsManager := NewTLSManager(cfg)
 	_, err := tlsManager.generateOrRenewCert()
 	require.NoError(t, err)
-	_, _, _, cleanUp, err := tlsManager.getConfig()
+	_, _, _, _, cleanUp, err := tlsManager.getConfig()
 	require.NoError(t, err, "couldn't retrieve TLS config")
 	t.Cleanup(cleanUp)
 
@@ -239,6 +241,159 @@ func TestGenerateEphemeralCert(t *testing.T) {
 	require.NoError(t, err, "error loading permanent certificate")
 }
 
+// TestListenerTLS tests that listeners with their own TLS options use their
+// own certificate, while all other listeners use the global one.
+func TestListenerTLS(t *testing.T) {
+	t.Parallel()
+
+	tempDir, certPath, keyPath := newTestDirectory(t)
+	listenerCertPath, listenerKeyPath, listenerCert := writeTestCertFiles(
+		t, false, false, nil,
+	)
+	listenerTLS := &lncfg.ListenerTLS{
+		CertPath: listenerCertPath,
+		KeyPath:  listenerKeyPath,
+	}
+
+	tcpAddr := &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}
+	rpcUnixAddr := &net.UnixAddr{Name: tempDir + "/rpc.sock", Net: "unix"}
+	restUnixAddr := &net.UnixAddr{Name: tempDir + "/rest.sock", Net: "unix"}
+
+	cfg := &TLSManagerCfg{
+		TLSCertPath:     certPath,
+		TLSKeyPath:      keyPath,
+		TLSCertDuration: testTLSCertDuration,
+		RPCListenerTLS: map[string]*lncfg.ListenerTLS{
+			tcpAddr.String(): listenerTLS,
+		},
+		RESTListenerTLS: map[string]*lncfg.ListenerTLS{
+			tcpAddr.String(): listenerTLS,
+			restUnixAddr.String(): {
+				DisableTLS: true,
+			},
+		},
+	}
+	tlsManager := NewTLSManager(cfg)
+	serverOpts, _, rpcListen, restListen, cleanUp, err :=
+		tlsManager.SetCertificateBeforeUnlock()
+	require.NoError(t, err)
+	t.Cleanup(cleanUp)
+
+	_, globalCert, err := cert.LoadCert(certPath, keyPath)
+	require.NoError(t, err)
+
+	// peerCert does a TLS handshake with the given listener and returns
+	// the certificate it presented.
+	peerCert := func(lis net.Listener) *x509.Certificate {
+		conn, err := tls.Dial(
+			lis.Addr().Network(), lis.Addr().String(), &tls.Config{
+				InsecureSkipVerify: true,
+				NextProtos:         []string{"h2"},
+			},
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0]
+	}
+
+	// The gRPC server uses the certificate of the listener a connection
+	// was accepted on.
+	grpcServer := grpc.NewServer(serverOpts...)
+	t.Cleanup(grpcServer.Stop)
+
+	listenerRPC, err := rpcListen(tcpAddr)
+	require.NoError(t, err)
+	go func() { _ = grpcServer.Serve(listenerRPC) }()
+
+	globalRPC, err := rpcListen(rpcUnixAddr)
+	require.NoError(t, err)
+	go func() { _ = grpcServer.Serve(globalRPC) }()
+
+	require.Equal(t, listenerCert.Raw, peerCert(listenerRPC).Raw)
+	require.Equal(t, globalCert.Raw, peerCert(globalRPC).Raw)
+
+	// A REST listener with its own certificate presents it, while TLS can
+	// be disabled for a single REST listener.
+	listenerREST, err := restListen(tcpAddr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listenerREST.Close() })
+
+	go func() {
+		conn, err := listenerREST.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.(*tls.Conn).Handshake()
+	}()
+	require.Equal(t, listenerCert.Raw, peerCert(listenerREST).Raw)
+
+	noTLSREST, err := restListen(restUnixAddr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = noTLSREST.Close() })
+	require.IsType(t, &net.UnixListener{}, noTLSREST)
+}
+
+// TestTLSManagerBlobs tests that a certificate and key supplied directly are
+// used as they are, without writing anything to disk, even if the certificate
+// expired.
+func TestTLSManagerBlobs(t *testing.T) {
+	t.Parallel()
+
+	encodePair := func(expired bool) ([]byte, []byte) {
+		certDerBytes, keyBytes := genCertPair(t, expired)
+		certPEM := pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: certDerBytes,
+		})
+		keyPEM := pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: keyBytes,
+		})
+
+		return certPEM, keyPEM
+	}
+
+	tempDir, certPath, keyPath := newTestDirectory(t)
+	certPEM, keyPEM := encodePair(false)
+	cfg := &TLSManagerCfg{
+		TLSCertPath:     certPath,
+		TLSKeyPath:      keyPath,
+		TLSCertBlob:     certPEM,
+		TLSKeyBlob:      keyPEM,
+		TLSCertDuration: testTLSCertDuration,
+	}
+	tlsManager := NewTLSManager(cfg)
+	_, _, _, _, cleanUp, err := tlsManager.SetCertificateBeforeUnlock()
+	require.NoError(t, err)
+	t.Cleanup(cleanUp)
+
+	expired, _, err := tlsManager.IsCertExpired(nil)
+	require.NoError(t, err)
+	require.False(t, expired)
+
+	// An expired certificate is still used, as we can't renew it.
+	cfg.TLSCertBlob, cfg.TLSKeyBlob = encodePair(true)
+	_, err = tlsManager.generateOrRenewCert()
+	require.NoError(t, err)
+
+	expired, _, err = tlsManager.IsCertExpired(nil)
+	require.NoError(t, err)
+	require.True(t, expired)
+
+	// Nothing was written to the TLS paths.
+	files, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	// A key that doesn't match the certificate is rejected.
+	cfg.TLSKeyBlob = keyPEM
+	_, err = tlsManager.generateOrRenewCert()
+	require.Error(t, err)
+}
+
 // genCertPair generates a key/cert pair, with the option of generating expired
 // certificates to make sure they are being regenerated correctly.
 func genCertPair(t *testing.T, expired bool) ([]byte, []byte) {
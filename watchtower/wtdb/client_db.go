@@ -259,7 +259,7 @@ type ClientDB struct {
 // migrations will be applied before returning. Any attempt to open a database
 // with a version number higher that the latest version will fail to prevent
 // accidental reversion.
-func OpenClientDB(db kvdb.Backend) (*ClientDB, error) {
+func OpenClientDB(db kvdb.Backend, opts ...DBOption) (*ClientDB, error) {
 	firstInit, err := isFirstInit(db)
 	if err != nil {
 		return nil, err
@@ -272,7 +272,9 @@ func OpenClientDB(db kvdb.Backend) (*ClientDB, error) {
 		),
 	}
 
-	err = initOrSyncVersions(clientDB, firstInit, clientDBVersions)
+	err = initOrSyncVersions(
+		clientDB, firstInit, clientDBVersions, newDBCfg(opts...),
+	)
 	if err != nil {
 		db.Close()
 		return nil, err
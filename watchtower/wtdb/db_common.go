@@ -3,6 +3,7 @@ package wtdb
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 )
@@ -43,3 +44,47 @@ func isFirstInit(db kvdb.Backend) (bool, error) {
 
 	return !metadataExists, nil
 }
+
+// dbCfg holds the optional settings used when opening a tower or client
+// database.
+type dbCfg struct {
+	// preMigrationCheck is called before any migration is applied. If it
+	// returns an error, the migration isn't started.
+	preMigrationCheck func() error
+}
+
+// DBOption is a functional option to modify the way a tower or client
+// database is opened.
+type DBOption func(*dbCfg)
+
+// WithPreMigrationCheck sets a check that must pass before any migration of
+// the database is applied, for example to make sure there's enough disk space
+// for it.
+func WithPreMigrationCheck(check func() error) DBOption {
+	return func(cfg *dbCfg) {
+		cfg.preMigrationCheck = check
+	}
+}
+
+// newDBCfg applies the given options to an empty database config.
+func newDBCfg(opts ...DBOption) *dbCfg {
+	cfg := &dbCfg{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// runPreMigrationCheck runs the configured pre-migration check, if any.
+func (c *dbCfg) runPreMigrationCheck() error {
+	if c.preMigrationCheck == nil {
+		return nil
+	}
+
+	if err := c.preMigrationCheck(); err != nil {
+		return fmt.Errorf("refusing to migrate database: %w", err)
+	}
+
+	return nil
+}
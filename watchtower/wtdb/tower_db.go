@@ -60,7 +60,7 @@ type TowerDB struct {
 // migrations will be applied before returning. Any attempt to open a database
 // with a version number higher that the latest version will fail to prevent
 // accidental reversion.
-func OpenTowerDB(db kvdb.Backend) (*TowerDB, error) {
+func OpenTowerDB(db kvdb.Backend, opts ...DBOption) (*TowerDB, error) {
 	firstInit, err := isFirstInit(db)
 	if err != nil {
 		return nil, err
@@ -70,7 +70,9 @@ func OpenTowerDB(db kvdb.Backend) (*TowerDB, error) {
 		db: db,
 	}
 
-	err = initOrSyncVersions(towerDB, firstInit, towerDBVersions)
+	err = initOrSyncVersions(
+		towerDB, firstInit, towerDBVersions, newDBCfg(opts...),
+	)
 	if err != nil {
 		db.Close()
 		return nil, err
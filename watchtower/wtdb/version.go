@@ -148,7 +148,9 @@ type versionedDB interface {
 // will simply write the latest version to the database. Otherwise, passing init
 // as false will cause the database to apply any needed migrations to ensure its
 // version matches the latest version in the provided versions list.
-func initOrSyncVersions(db versionedDB, init bool, versions []version) error {
+func initOrSyncVersions(db versionedDB, init bool, versions []version,
+	cfg *dbCfg) error {
+
 	// If the database has not yet been created, we'll initialize the
 	// database version with the latest known version.
 	if init {
@@ -159,14 +161,14 @@ func initOrSyncVersions(db versionedDB, init bool, versions []version) error {
 
 	// Otherwise, ensure that any migrations are applied to ensure the data
 	// is in the format expected by the latest version.
-	return syncVersions(db, versions)
+	return syncVersions(db, versions, cfg)
 }
 
 // syncVersions ensures the database version is consistent with the highest
 // known database version, applying any migrations that have not been made. If
 // the highest known version number is lower than the database's version, this
 // method will fail to prevent accidental reversions.
-func syncVersions(db versionedDB, versions []version) error {
+func syncVersions(db versionedDB, versions []version, cfg *dbCfg) error {
 	curVersion, err := db.Version()
 	if err != nil {
 		return err
@@ -185,6 +187,10 @@ func syncVersions(db versionedDB, versions []version) error {
 		return nil
 	}
 
+	if err := cfg.runPreMigrationCheck(); err != nil {
+		return err
+	}
+
 	// Otherwise, apply any migrations in order to bring the database
 	// version up to the highest known version.
 	updates := getMigrations(versions, curVersion)
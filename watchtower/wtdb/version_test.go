@@ -0,0 +1,66 @@
+package wtdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreMigrationCheck ensures that a failing pre-migration check prevents
+// any migration from being applied, and that it isn't run if the database is
+// already up to date.
+func TestPreMigrationCheck(t *testing.T) {
+	t.Parallel()
+
+	bdb, err := NewBoltBackendCreator(true, t.TempDir(), "wtclient.db")(
+		&kvdb.BoltConfig{DBTimeout: kvdb.DefaultDBTimeout},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, bdb.Close())
+	})
+
+	db := &ClientDB{db: bdb}
+	err = kvdb.Update(bdb, func(tx kvdb.RwTx) error {
+		return initDBVersion(tx, 0)
+	}, func() {})
+	require.NoError(t, err)
+
+	var migrated bool
+	versions := []version{
+		{
+			txMigration: func(kvdb.RwTx) error {
+				migrated = true
+				return nil
+			},
+		},
+	}
+
+	errNoSpace := errors.New("not enough disk space")
+	var checks int
+	cfg := newDBCfg(WithPreMigrationCheck(func() error {
+		checks++
+		return errNoSpace
+	}))
+	require.ErrorIs(t, syncVersions(db, versions, cfg), errNoSpace)
+	require.False(t, migrated)
+
+	version, err := db.Version()
+	require.NoError(t, err)
+	require.Zero(t, version)
+
+	// Once the check passes, the migration is applied.
+	cfg = newDBCfg(WithPreMigrationCheck(func() error {
+		checks++
+		return nil
+	}))
+	require.NoError(t, syncVersions(db, versions, cfg))
+	require.True(t, migrated)
+	require.Equal(t, 2, checks)
+
+	// An up to date database doesn't need to be checked.
+	require.NoError(t, syncVersions(db, versions, cfg))
+	require.Equal(t, 2, checks)
+}
@@ -2,6 +2,7 @@ package lnd
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -9,6 +10,8 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -32,6 +35,73 @@ type witnessCache interface {
 	AddSha256Witnesses(preimages ...lntypes.Preimage) error
 }
 
+// fallbackWitnessCache is a witnessCache that adds new preimages to its
+// primary cache only, but also looks up preimages in its fallback cache. This
+// keeps the preimages that were stored in the channel database available after
+// switching to a separate preimage store.
+type fallbackWitnessCache struct {
+	primary  witnessCache
+	fallback witnessCache
+}
+
+// A compile-time check to ensure fallbackWitnessCache implements the
+// witnessCache interface.
+var _ witnessCache = (*fallbackWitnessCache)(nil)
+
+// LookupSha256Witness attempts to lookup the preimage for a sha256 hash in the
+// primary cache first, and then in the fallback cache.
+func (f *fallbackWitnessCache) LookupSha256Witness(
+	hash lntypes.Hash) (lntypes.Preimage, error) {
+
+	preimage, err := f.primary.LookupSha256Witness(hash)
+	if !errors.Is(err, channeldb.ErrNoWitnesses) {
+		return preimage, err
+	}
+
+	return f.fallback.LookupSha256Witness(hash)
+}
+
+// AddSha256Witnesses adds a batch of new sha256 preimages into the primary
+// cache.
+func (f *fallbackWitnessCache) AddSha256Witnesses(
+	preimages ...lntypes.Preimage) error {
+
+	return f.primary.AddSha256Witnesses(preimages...)
+}
+
+// newWitnessCache returns the witness cache that the preimage beacon stores
+// preimages in. If a separate preimage database is used, the preimages are
+// encrypted with a key derived from the given key ring. If the separate
+// database is only kept as a fallback, new preimages are stored in the channel
+// database instead.
+func newWitnessCache(dbs *DatabaseInstances,
+	keyRing keychain.KeyRing) (witnessCache, error) {
+
+	chanDBCache := dbs.ChanStateDB.NewWitnessCache()
+	if dbs.PreimageDB == nil {
+		return chanDBCache, nil
+	}
+
+	encrypter, err := lnencrypt.KeyRingEncrypter(keyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive preimage encryption "+
+			"key: %w", err)
+	}
+
+	separateCache := channeldb.NewWitnessCache(dbs.PreimageDB, encrypter)
+	if dbs.PreimageDBFallback {
+		return &fallbackWitnessCache{
+			primary:  chanDBCache,
+			fallback: separateCache,
+		}, nil
+	}
+
+	return &fallbackWitnessCache{
+		primary:  separateCache,
+		fallback: chanDBCache,
+	}, nil
+}
+
 // preimageBeacon is an implementation of the contractcourt.WitnessBeacon
 // interface, and the lnwallet.PreimageCache interface. This implementation is
 // concerned with a single witness type: sha256 hahsh preimages.
@@ -6,6 +6,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/require"
@@ -54,3 +55,92 @@ func (w *mockWitnessCache) AddSha256Witnesses(
 
 	return nil
 }
+
+// TestFallbackWitnessCache asserts that new preimages are only added to the
+// primary cache, while lookups also consult the fallback cache.
+func TestFallbackWitnessCache(t *testing.T) {
+	t.Parallel()
+
+	primaryDB, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	fallbackDB, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	cache := &fallbackWitnessCache{
+		primary:  primaryDB.NewWitnessCache(),
+		fallback: fallbackDB.NewWitnessCache(),
+	}
+
+	oldPreimage := lntypes.Preimage{1}
+	newPreimage := lntypes.Preimage{2}
+	unknownPreimage := lntypes.Preimage{3}
+
+	// A preimage that was stored in the fallback cache before can still be
+	// looked up.
+	require.NoError(t, fallbackDB.NewWitnessCache().AddSha256Witnesses(
+		oldPreimage,
+	))
+	preimage, err := cache.LookupSha256Witness(oldPreimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, oldPreimage, preimage)
+
+	// New preimages are only written to the primary cache.
+	require.NoError(t, cache.AddSha256Witnesses(newPreimage))
+	preimage, err = cache.LookupSha256Witness(newPreimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, newPreimage, preimage)
+
+	_, err = fallbackDB.NewWitnessCache().LookupSha256Witness(
+		newPreimage.Hash(),
+	)
+	require.ErrorIs(t, err, channeldb.ErrNoWitnesses)
+
+	_, err = cache.LookupSha256Witness(unknownPreimage.Hash())
+	require.ErrorIs(t, err, channeldb.ErrNoWitnesses)
+}
+
+// TestNewWitnessCacheSwitchBack asserts that the preimages stored in the
+// separate preimage database remain available after switching back to storing
+// preimages in the channel database.
+func TestNewWitnessCacheSwitchBack(t *testing.T) {
+	t.Parallel()
+
+	chanDB, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	preimageDB, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	dbs := &DatabaseInstances{
+		ChanStateDB: chanDB,
+		PreimageDB:  preimageDB.Backend,
+	}
+	keyRing := &lnencrypt.MockKeyRing{}
+
+	// Store a preimage while the separate preimage store is in use.
+	separate, err := newWitnessCache(dbs, keyRing)
+	require.NoError(t, err)
+
+	oldPreimage := lntypes.Preimage{1}
+	require.NoError(t, separate.AddSha256Witnesses(oldPreimage))
+
+	// After switching back, new preimages go to the channel database while
+	// the old preimage can still be looked up.
+	dbs.PreimageDBFallback = true
+	switchedBack, err := newWitnessCache(dbs, keyRing)
+	require.NoError(t, err)
+
+	preimage, err := switchedBack.LookupSha256Witness(oldPreimage.Hash())
+	require.NoError(t, err)
+	require.Equal(t, oldPreimage, preimage)
+
+	newPreimage := lntypes.Preimage{2}
+	require.NoError(t, switchedBack.AddSha256Witnesses(newPreimage))
+
+	preimage, err = chanDB.NewWitnessCache().LookupSha256Witness(
+		newPreimage.Hash(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, newPreimage, preimage)
+}